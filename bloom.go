@@ -0,0 +1,265 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/parsyl/parquet/internal/xxhash"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+const (
+	// bloomBlockSize is the size (in bytes) of each block of a split
+	// block bloom filter.  Each block is 8 32 bit words.
+	bloomBlockSize = 32
+
+	// bloomMaxSize is the largest bloom filter that is written
+	// or read.
+	bloomMaxSize = 128 * 1024 * 1024
+
+	// bloomFPP is the false positive probability that bloom
+	// filters are sized for.
+	bloomFPP = 0.01
+)
+
+// bloomSalt is used to pick the bit that is set in each word of a block.
+var bloomSalt = [8]uint32{
+	0x47b6137b, 0x44974d91, 0x8824ad5b, 0xa2b7289d,
+	0x705495c7, 0x2df1424b, 0x9efc4947, 0x5c6bfb31,
+}
+
+// BloomFilter is a split block bloom filter (the only algorithm the
+// parquet spec defines).  It can tell that a column chunk doesn't hold
+// a value, but a value it may contain might not be in the column chunk.
+type BloomFilter struct {
+	blocks [][8]uint32
+}
+
+// BloomHash returns the hash of a plain encoded value (without the
+// length of byte array values) that is inserted into a BloomFilter.
+func BloomHash(v []byte) uint64 {
+	return xxhash.Sum64(v)
+}
+
+// NewBloomFilter returns a bloom filter that holds the hashes
+// (see BloomHash).  It is sized so that about 1% of the values it
+// doesn't hold are false positives.
+func NewBloomFilter(hashes []uint64) *BloomFilter {
+	distinct := make(map[uint64]struct{}, len(hashes))
+	for _, h := range hashes {
+		distinct[h] = struct{}{}
+	}
+
+	b := &BloomFilter{blocks: make([][8]uint32, bloomSize(len(distinct))/bloomBlockSize)}
+	for h := range distinct {
+		b.insert(h)
+	}
+	return b
+}
+
+// bloomSize returns the number of bytes (a power of 2) a bloom
+// filter needs to hold n distinct values.
+func bloomSize(n int) int {
+	bits := -8 * float64(n) / math.Log(1-math.Pow(bloomFPP, 1.0/8))
+	size := bloomBlockSize
+	for float64(size*8) < bits && size < bloomMaxSize {
+		size *= 2
+	}
+	return size
+}
+
+// MayContain returns false if v (a plain encoded value without
+// its length) definitely isn't in the bloom filter.
+func (b *BloomFilter) MayContain(v []byte) bool {
+	h := BloomHash(v)
+	blk := &b.blocks[b.block(h)]
+	for i, m := range bloomMask(uint32(h)) {
+		if blk[i]&m == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BloomFilter) insert(h uint64) {
+	blk := &b.blocks[b.block(h)]
+	for i, m := range bloomMask(uint32(h)) {
+		blk[i] |= m
+	}
+}
+
+// block uses the upper 32 bits of the hash to pick a block.
+func (b *BloomFilter) block(h uint64) int {
+	return int(((h >> 32) * uint64(len(b.blocks))) >> 32)
+}
+
+// bloomMask sets one bit in each word of a block.
+func bloomMask(x uint32) [8]uint32 {
+	var out [8]uint32
+	for i, s := range bloomSalt {
+		out[i] = 1 << ((x * s) >> 27)
+	}
+	return out
+}
+
+func (b *BloomFilter) bytes() []byte {
+	out := make([]byte, len(b.blocks)*bloomBlockSize)
+	for i, blk := range b.blocks {
+		for j, w := range blk {
+			binary.LittleEndian.PutUint32(out[i*bloomBlockSize+j*4:], w)
+		}
+	}
+	return out
+}
+
+// WriteBloomFilter writes the bloom filter of the column chunk at pth in
+// the current row group.  Bloom filters are written after all of the row
+// group's column chunks and the footer has the offset of each of them.
+func (m *Metadata) WriteBloomFilter(w io.Writer, pth []string, b *BloomFilter) error {
+	rg, err := m.currentRowGroup()
+	if err != nil {
+		return err
+	}
+
+	data := b.bytes()
+	hdr, err := bloomFilterHeader(int32(len(data)))
+	if err != nil {
+		return err
+	}
+
+	o := position(w)
+	n, err := w.Write(append(hdr, data...))
+	if err != nil {
+		return err
+	}
+
+	rg.bloomFilters = append(rg.bloomFilters, bloomFilterLen{col: strings.Join(pth, "."), n: int64(n), offset: o})
+	return nil
+}
+
+// bloomFilterHeader returns a BloomFilterHeader.  schema.go predates the
+// final version of the bloom filter spec, so the header is written field
+// by field: its hash (BloomFilterHash's field 1) is XXHASH, not MURMUR3,
+// and it doesn't have the compression field (which must be UNCOMPRESSED).
+func bloomFilterHeader(numBytes int32) ([]byte, error) {
+	buf := thrift.NewTMemoryBuffer()
+	p := thrift.NewTCompactProtocol(buf)
+
+	steps := []func() error{
+		func() error { return p.WriteStructBegin("BloomFilterHeader") },
+		func() error { return p.WriteFieldBegin("numBytes", thrift.I32, 1) },
+		func() error { return p.WriteI32(numBytes) },
+		p.WriteFieldEnd,
+		func() error { return writeEmptyUnion(p, "algorithm", 2, "BLOCK") },
+		func() error { return writeEmptyUnion(p, "hash", 3, "XXHASH") },
+		func() error { return writeEmptyUnion(p, "compression", 4, "UNCOMPRESSED") },
+		p.WriteFieldStop,
+		p.WriteStructEnd,
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeEmptyUnion writes a field that is a union whose first
+// member (an empty struct) is set.
+func writeEmptyUnion(p thrift.TProtocol, name string, id int16, member string) error {
+	steps := []func() error{
+		func() error { return p.WriteFieldBegin(name, thrift.STRUCT, id) },
+		func() error { return p.WriteStructBegin(name) },
+		func() error { return p.WriteFieldBegin(member, thrift.STRUCT, 1) },
+		func() error { return p.WriteStructBegin(member) },
+		p.WriteFieldStop,
+		p.WriteStructEnd,
+		p.WriteFieldEnd,
+		p.WriteFieldStop,
+		p.WriteStructEnd,
+		p.WriteFieldEnd,
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBloomFilter reads the bloom filter at offset.
+func ReadBloomFilter(r io.ReadSeeker, offset int64) (*BloomFilter, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	hdr := sch.NewBloomFilterPageHeader()
+	if err := hdr.Read(thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: r})); err != nil {
+		return nil, fmt.Errorf("unable to read bloom filter header: %s", err)
+	}
+
+	if hdr.Algorithm == nil || hdr.Algorithm.BLOCK == nil || hdr.Hash == nil || hdr.Hash.MURMUR3 == nil {
+		return nil, fmt.Errorf("unsupported bloom filter algorithm or hash")
+	}
+
+	n := int(hdr.NumBytes)
+	if n <= 0 || n > bloomMaxSize || n%bloomBlockSize != 0 {
+		return nil, fmt.Errorf("invalid bloom filter size %d", n)
+	}
+
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	b := &BloomFilter{blocks: make([][8]uint32, n/bloomBlockSize)}
+	for i := range b.blocks {
+		for j := range b.blocks[i] {
+			b.blocks[i][j] = binary.LittleEndian.Uint32(data[i*bloomBlockSize+j*4:])
+		}
+	}
+	return b, nil
+}
+
+// MayContain returns false if none of the row groups can have v (a
+// plain encoded value without its length) in the column col.  A row
+// group whose column chunk doesn't have a bloom filter may have v.
+func (m *Metadata) MayContain(r io.ReadSeeker, col string, v []byte) (bool, error) {
+	for _, rg := range m.metadata.RowGroups {
+		for _, ch := range rg.Columns {
+			if strings.Join(ch.MetaData.PathInSchema, ".") != col {
+				continue
+			}
+
+			if ch.MetaData.BloomFilterOffset == nil {
+				return true, nil
+			}
+
+			b, err := ReadBloomFilter(r, *ch.MetaData.BloomFilterOffset)
+			if err != nil {
+				return false, err
+			}
+
+			if b.MayContain(v) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// bloomFilterLen is the size of a bloom filter (including its header)
+// that was written after the column chunks of a row group.
+type bloomFilterLen struct {
+	col string
+	n   int64
+	// offset is where the bloom filter starts (-1 if that
+	// isn't known, see CountingWriter)
+	offset int64
+}
@@ -0,0 +1,46 @@
+package parquet
+
+import (
+	"fmt"
+	"hash/crc32"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// SetPageChecksums makes the page headers that are written have the CRC32
+// of their page's (compressed) data and makes Pages return pages whose
+// checksums are verified when they are read (pages without a checksum
+// aren't checked).  It must be called before any pages are written or
+// before Pages is called.
+func (m *Metadata) SetPageChecksums() {
+	m.pageChecksums = true
+}
+
+// setChecksum sets the checksum of ph to the CRC32 of the page's data
+// (which is written in parts, like the levels and values of a v2 page).
+func (m *Metadata) setChecksum(ph *sch.PageHeader, page [][]byte) {
+	if !m.pageChecksums {
+		return
+	}
+
+	var crc uint32
+	for _, b := range page {
+		crc = crc32.Update(crc, crc32.IEEETable, b)
+	}
+
+	c := int32(crc)
+	ph.Crc = &c
+}
+
+// verifyChecksum makes sure the CRC32 of a page's data
+// matches the checksum in its header (if it has one).
+func verifyChecksum(ph *sch.PageHeader, data []byte) error {
+	if ph.Crc == nil {
+		return nil
+	}
+
+	if crc := crc32.ChecksumIEEE(data); crc != uint32(*ph.Crc) {
+		return fmt.Errorf("page checksum mismatch: the header has %08x and the data has %08x", uint32(*ph.Crc), crc)
+	}
+	return nil
+}
@@ -7,13 +7,15 @@ import (
 	"go/format"
 	"log"
 	"os"
+	"strings"
 	"text/template"
 )
 
 var (
-	pkg    = flag.String("package", "main", "package of the generated code")
-	max    = flag.Int("maxwidth", 3, "the bit width at which to stop")
-	outPth = flag.String("output", "bitpack.go", "name of the file that is produced, defaults to parquet.go")
+	pkg        = flag.String("package", "main", "package of the generated code")
+	max        = flag.Int("maxwidth", 32, "the bit width at which to stop")
+	outPth     = flag.String("output", "bitpack.go", "name of the file that is produced, defaults to parquet.go")
+	benchmarks = flag.Bool("benchmarks", false, "also produce a _bench_test.go file with a benchmark for each width")
 )
 
 func main() {
@@ -36,28 +38,47 @@ func main() {
 		}
 	}
 
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, pb)
-	if err != nil {
+	if err := write(tmpl, pb, *outPth); err != nil {
 		log.Fatal(err)
 	}
 
-	gocode, err := format.Source(buf.Bytes())
+	if !*benchmarks {
+		return
+	}
+
+	tmpl, err = template.New("benchmarks").Funcs(funcs).Parse(benchTpl)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	f, err := os.Create(*outPth)
-	if err != nil {
+	pth := fmt.Sprintf("%s_bench_test.go", strings.TrimSuffix(*outPth, ".go"))
+	if err := write(tmpl, pb, pth); err != nil {
 		log.Fatal(err)
 	}
+}
+
+func write(tmpl *template.Template, pb bitback, pth string) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pb); err != nil {
+		return err
+	}
 
-	_, err = f.Write(gocode)
+	gocode, err := format.Source(buf.Bytes())
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	f, err := os.Create(pth)
+	if err != nil {
+		return err
 	}
 
-	f.Close()
+	if _, err := f.Write(gocode); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
 }
 
 type bitback struct {
@@ -65,78 +86,33 @@ type bitback struct {
 	Max     int
 }
 
-type byt struct {
-	I     int
-	Or    string
-	And   int
+// part is the section of a value that is stored in
+// a byte (or the section of a byte that is stored in
+// a value).
+type part struct {
+	// I is the index of the value (when packing) or
+	// the byte (when unpacking).
+	I int
+	// Shift is how far the part is shifted right before masking.
 	Shift int
-	Dir   string
+	And   int
+	// Offset is how far the masked part is shifted left.
+	Offset int
 }
 
 var (
 	funcs = template.FuncMap{
-		"pack": func(width int) [][]byt {
-			bs := [][]byt{{}}
-			var x int
-			and := 1<<uint(width) - 1
-			for i := 0; i < 8; i++ {
-				shift := (i * width) % 8
-				if shift+width > 8 {
-					a1 := 7 >> uint(width-(8-shift))
-					a2 := 7 - a1
-					s2 := 8 - shift
-					bs[x] = append(bs[x],
-						byt{
-							I:     i,
-							And:   a1,
-							Shift: shift,
-							Dir:   "<<",
-						})
-					x++
-					bs = append(bs, []byt{})
-					bs[x] = append(bs[x],
-						byt{
-							I:     i,
-							And:   a2,
-							Or:    " |\n",
-							Shift: s2,
-							Dir:   ">>",
-						},
-					)
-				} else {
-					o := " |\n"
-					if shift+width == 8 {
-						o = ""
-					}
-					bs[x] = append(bs[x], byt{
-						I:     i,
-						And:   and,
-						Or:    o,
-						Shift: shift,
-						Dir:   "<<",
-					})
-					if shift+width == 8 && i < 7 {
-						bs = append(bs, []byt{})
-						x++
-					}
-				}
-			}
-			return bs
+		// pack returns the parts of each of the values that make up
+		// byte j of a group of 8 values packed with the given width.
+		"pack": func(width, j int) []part {
+			return parts(width, j, true)
 		},
-		"uint8": func(width, i int) string {
-			shift := (i * width) % 8
-			index := (i * width) / 8
-			mask := ((1 << uint(width)) - 1) << uint(shift)
-			if mask < (1 << 8) {
-				return fmt.Sprintf("(uint8(vals[%d] & %d) >> %d),", index, mask, shift)
-			}
-
-			return fmt.Sprintf(
-				"%s | %s,",
-				fmt.Sprintf("(uint8(vals[%d] & %d) >> %d)", index, mask&((1<<8)-1), shift),
-				fmt.Sprintf("(uint8(vals[%d] & %d) << %d)", index+1, mask>>8, 8-shift),
-			)
+		// unpack returns the parts of each of the bytes that make up
+		// value i of a group of 8 values packed with the given width.
+		"unpack": func(width, i int) []part {
+			return parts(width, i, false)
 		},
+		"last": func(i int, p []part) bool { return i == len(p)-1 },
 		"N": func(start, end int) (stream chan int) {
 			stream = make(chan int)
 			go func() {
@@ -161,7 +137,7 @@ var (
 
 const MaxSize = {{.Max}}
 
-func Pack(b []byte, width int, vals []uint8) []byte {
+func Pack(b []byte, width int, vals []uint32) []byte {
 	switch width {
 		{{range $i := N 1 .Max }}case {{$i}}:
 			return pack{{$i}}(b, vals)
@@ -171,32 +147,195 @@ func Pack(b []byte, width int, vals []uint8) []byte {
 }
 
 {{range $i := N 1 .Max}}
-func pack{{$i}}(b []byte, vals []uint8) []byte {
+func pack{{$i}}(b []byte, vals []uint32) []byte {
 return append(b, {{template "bytes" $i}} )
 }
 {{end}}
 
-func Unpack(width int, vals []byte) []uint8 {
+func Unpack(width int, vals []byte) []uint32 {
 	switch width {
 		{{range $i := N 1 .Max }}case {{$i}}:
 			return unpack{{$i}}(vals)
 		{{end}}default:
-			return []uint8{}
+			return []uint32{}
 	}
 }
 
 {{range $i := N 1 .Max }}
-	   func unpack{{$i}}(vals []byte) []uint8 { {{template "ints" .}}
+	   func unpack{{$i}}(vals []byte) []uint32 { {{template "ints" .}}
 	   }
 {{end}}
+
+const MaxSize64 = 64
+
+// Pack64 packs a group of 8 values with widths up to 64 bits.
+// Unlike Pack it isn't unrolled, the bits are accumulated in
+// a uint64 and written a byte at a time.
+func Pack64(b []byte, width int, vals []uint64) []byte {
+	if width < 1 || width > MaxSize64 {
+		return b
+	}
+
+	var acc uint64
+	var n uint
+	push := func(v uint64, w uint) {
+		acc |= (v & (1<<w - 1)) << n
+		n += w
+		for n >= 8 {
+			b = append(b, byte(acc))
+			acc >>= 8
+			n -= 8
+		}
+	}
+
+	for _, v := range vals[:8] {
+		// acc can hold up to 7 bits that haven't been written,
+		// so wide values are pushed in two halves.
+		if width > 32 {
+			push(v, 32)
+			push(v>>32, uint(width-32))
+		} else {
+			push(v, uint(width))
+		}
+	}
+	return b
+}
+
+// Unpack64 unpacks a group of 8 values with widths up to 64 bits.
+func Unpack64(width int, vals []byte) []uint64 {
+	if width < 1 || width > MaxSize64 {
+		return []uint64{}
+	}
+
+	var acc uint64
+	var n uint
+	var i int
+	pull := func(w uint) uint64 {
+		for n < w {
+			acc |= uint64(vals[i]) << n
+			i++
+			n += 8
+		}
+		v := acc & (1<<w - 1)
+		acc >>= w
+		n -= w
+		return v
+	}
+
+	out := make([]uint64, 8)
+	for j := range out {
+		if width > 32 {
+			out[j] = pull(32) | pull(uint(width-32))<<32
+		} else {
+			out[j] = pull(uint(width))
+		}
+	}
+	return out
+}
 `
 
-	bytesTpl = `{{define "bytes"}}
-{{ $bytes := pack .}} {{range $byte := $bytes}} ( {{ range $b := $byte}} byte((vals[{{$b.I}}]&{{$b.And}}){{$b.Dir}}{{$b.Shift}}){{$b.Or}}{{end}} ),
-{{end}}
-{{end}}`
+	bytesTpl = `{{define "bytes"}}{{$width := .}}
+{{range $j := N 1 $width}}{{$parts := pack $width $j}}({{range $i, $p := $parts}}byte((vals[{{$p.I}}]>>{{$p.Shift}})&{{$p.And}})<<{{$p.Offset}}{{if not (last $i $parts)}} |
+{{end}}{{end}}),
+{{end}}{{end}}`
+
 	intsTpl = `{{define "ints"}}{{$width := .}}
-return []uint8{
-{{range $i := N 0 7}} {{uint8 $width $i}}
+return []uint32{
+{{range $i := N 0 7}}{{$parts := unpack $width $i}}{{range $j, $p := $parts}}uint32((vals[{{$p.I}}]>>{{$p.Shift}})&{{$p.And}})<<{{$p.Offset}}{{if not (last $j $parts)}} |
+{{end}}{{end}},
 {{end}} }{{end}}`
 )
+
+// benchTpl benchmarks each width of Pack and Unpack
+// with the same 1024 values.
+var benchTpl = `package {{.Package}}
+
+// Code generated by github.com/parsyl/parquet.  DO NOT EDIT.
+
+import "testing"
+
+var benchVals = func() []uint32 {
+	out := make([]uint32, 1024)
+	for i := range out {
+		out[i] = uint32(i) * 2654435761
+	}
+	return out
+}()
+
+func benchmarkPack(b *testing.B, width int) {
+	mask := uint32(1<<uint(width) - 1)
+	vals := make([]uint32, len(benchVals))
+	for i, v := range benchVals {
+		vals[i] = v & mask
+	}
+
+	buf := make([]byte, 0, len(vals)/8*width)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = buf[:0]
+		for j := 0; j < len(vals); j += 8 {
+			buf = Pack(buf, width, vals[j:j+8])
+		}
+	}
+}
+
+func benchmarkUnpack(b *testing.B, width int) {
+	var buf []byte
+	for j := 0; j < len(benchVals); j += 8 {
+		buf = Pack(buf, width, benchVals[j:j+8])
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(buf); j += width {
+			Unpack(width, buf[j:j+width])
+		}
+	}
+}
+{{range $i := N 1 .Max}}
+func BenchmarkPack{{$i}}(b *testing.B) { benchmarkPack(b, {{$i}}) }
+
+func BenchmarkUnpack{{$i}}(b *testing.B) { benchmarkUnpack(b, {{$i}}) }
+{{end}}
+`
+
+// parts finds the overlap between the bits of 8 values of the
+// given width and the bits of the bytes they are packed into.
+// When packing, n is the index of a byte (starting at 1) and the
+// parts come from the values.  When unpacking, n is the index of
+// a value and the parts come from the bytes.
+func parts(width, n int, packing bool) []part {
+	var out []part
+	for i := 0; i < 8; i++ {
+		for j := 0; j < width; j++ {
+			valStart, byteStart := i*width, j*8
+			start := maxInt(valStart, byteStart)
+			end := minInt(valStart+width, byteStart+8)
+			if start >= end {
+				continue
+			}
+
+			and := 1<<uint(end-start) - 1
+			if packing && j == n-1 {
+				out = append(out, part{I: i, Shift: start - valStart, And: and, Offset: start - byteStart})
+			} else if !packing && i == n {
+				out = append(out, part{I: j, Shift: start - byteStart, And: and, Offset: start - valStart})
+			}
+		}
+	}
+	return out
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/parsyl/parquet/cmd/parquetgen/dremel/testcases/doc"
+	"github.com/parsyl/parquet/cmd/parquetgen/dremel/testcases/order"
 	"github.com/parsyl/parquet/cmd/parquetgen/dremel/testcases/person"
 	"github.com/parsyl/parquet/cmd/parquetgen/dremel/testcases/repetition"
 	"github.com/stretchr/testify/assert"
@@ -277,3 +278,73 @@ func TestRepetition(t *testing.T) {
 
 	assert.Equal(t, repetitionDocs, out)
 }
+
+var (
+	orders = []order.Order{
+		{
+			ID: 1,
+			LineItems: []order.LineItem{
+				{SKU: "a", Quantity: 2, Discount: pfloat64(0.5), Note: []byte("gift")},
+				{SKU: "b", Quantity: 1, Note: []byte("fragile")},
+			},
+		},
+		{
+			ID: 2,
+		},
+		{
+			ID: 3,
+			LineItems: []order.LineItem{
+				{SKU: "c", Quantity: 5, Note: []byte("rush")},
+			},
+		},
+	}
+)
+
+// TestOrderLevels verifies the levels of a top level slice of structs
+// (a repeated group) whose fields are required, optional and []byte.
+func TestOrderLevels(t *testing.T) {
+	var buf bytes.Buffer
+	pw, err := order.NewParquetWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, o := range orders {
+		pw.Add(o)
+	}
+
+	if err := pw.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	pw.Close()
+
+	pr, err := order.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []order.Levels{
+		{Name: "id"},
+		{Name: "line_items.sku", Defs: []uint8{1, 1, 0, 1}, Reps: []uint8{0, 1, 0, 0}},
+		{Name: "line_items.quantity", Defs: []uint8{1, 1, 0, 1}, Reps: []uint8{0, 1, 0, 0}},
+		{Name: "line_items.discount", Defs: []uint8{2, 1, 0, 1}, Reps: []uint8{0, 1, 0, 0}},
+		{Name: "line_items.note", Defs: []uint8{1, 1, 0, 1}, Reps: []uint8{0, 1, 0, 0}},
+	}
+
+	assert.Equal(t, expected, pr.Levels())
+
+	var out []order.Order
+	for pr.Next() {
+		var o order.Order
+		pr.Scan(&o)
+		out = append(out, o)
+	}
+
+	assert.NoError(t, pr.Error())
+	assert.Equal(t, orders, out)
+}
+
+func pfloat64(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,103 @@
+package dremel
+
+import (
+	"fmt"
+
+	"github.com/parsyl/parquet/cmd/parquetgen/fields"
+)
+
+// readMap generates the code that writes the key or value column
+// of a map.  The keys are sorted so both columns have the entries in
+// the same order (and so a file is the same each time it's written).
+func readMap(f, m fields.Field) string {
+	key, _ := m.MapTypes()
+	val := fmt.Sprintf("x.%s[k]", m.Name)
+	if f.Name == "Key" {
+		val = "k"
+	}
+
+	return fmt.Sprintf(`func read%s%s(x %s, vals []%s, defs, reps []uint8) ([]%s, []uint8, []uint8) {
+	switch {
+	case x.%s == nil:
+		defs = append(defs, 0)
+		reps = append(reps, 0)
+	case len(x.%s) == 0:
+		defs = append(defs, 1)
+		reps = append(reps, 0)
+	default:
+		keys := make([]%s, 0, len(x.%s))
+		for k := range x.%s {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+		for i, k := range keys {
+			var rep uint8
+			if i > 0 {
+				rep = 1
+			}
+			defs = append(defs, 2)
+			reps = append(reps, rep)
+			vals = append(vals, %s)
+		}
+	}
+
+	return vals, defs, reps
+}`, m.Name, f.Name, f.StructType(), f.Type, f.Type, m.Name, m.Name, key, m.Name, m.Name, val)
+}
+
+// writeMap generates the code that initializes a map from its key or
+// value column.  The key column is read first and it keeps track of
+// the keys (in the order they were written) so the value column can
+// put each value with its key.
+func writeMap(f, m fields.Field) string {
+	key, _ := m.MapTypes()
+	if f.Name == "Key" {
+		return fmt.Sprintf(`func write%s%s(keys *[]%s) func(x *%s, vals []%s, defs, reps []uint8) (int, int) {
+	return func(x *%s, vals []%s, defs, reps []uint8) (int, int) {
+		var nVals, nLevels int
+		*keys = (*keys)[:0]
+		for i, def := range defs {
+			if i > 0 && reps[i] == 0 {
+				break
+			}
+
+			nLevels++
+			if def == 0 {
+				continue
+			}
+
+			if x.%s == nil {
+				x.%s = %s{}
+			}
+
+			if def == 2 {
+				*keys = append(*keys, vals[nVals])
+				nVals++
+			}
+		}
+
+		return nVals, nLevels
+	}
+}`, m.Name, f.Name, key, f.StructType(), f.Type, f.StructType(), f.Type, m.Name, m.Name, m.Type)
+	}
+
+	return fmt.Sprintf(`func write%s%s(keys *[]%s) func(x *%s, vals []%s, defs, reps []uint8) (int, int) {
+	return func(x *%s, vals []%s, defs, reps []uint8) (int, int) {
+		var nVals, nLevels int
+		for i, def := range defs {
+			if i > 0 && reps[i] == 0 {
+				break
+			}
+
+			nLevels++
+			if def == 2 {
+				x.%s[(*keys)[nVals]] = vals[nVals]
+				nVals++
+			}
+		}
+
+		return nVals, nLevels
+	}
+}`, m.Name, f.Name, key, f.StructType(), f.Type, f.StructType(), f.Type, m.Name)
+}
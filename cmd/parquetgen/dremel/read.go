@@ -9,8 +9,8 @@ import (
 
 func readRequired(f fields.Field) string {
 	return fmt.Sprintf(`func read%s(x %s) %s {
-	return x.%s
-}`, strings.Join(f.FieldNames(), ""), f.StructType(), f.TypeName(), strings.Join(f.FieldNames(), "."))
+	return %s
+}`, strings.Join(f.FieldNames(), ""), f.StructType(), f.TypeName(), f.FromNamed("x."+strings.Join(f.FieldNames(), ".")))
 }
 
 func readOptional(f fields.Field) string {
@@ -25,24 +25,24 @@ func readOptional(f fields.Field) string {
 
 	var ptr string
 	rts := f.RepetitionTypes()
-	if rts[len(rts)-1] == fields.Optional {
+	if rts[len(rts)-1] == fields.Optional && !f.Nillable() {
 		ptr = "*"
 	}
 
 	out += fmt.Sprintf(`	default:
-			vals = append(vals, %sx.%s)
+			vals = append(vals, %s)
 			defs = append(defs, %d)
-			return vals, defs, reps`, ptr, nilField(n, f), n)
+			return vals, defs, reps`, f.FromNamed(ptr+"x."+nilField(n, f)), n)
 
 	return fmt.Sprintf(`func read%s(x %s, vals []%s, defs, reps []uint8) ([]%s, []uint8, []uint8) {
 		switch {
 		%s
 		}
-	}`, strings.Join(f.FieldNames(), ""), f.StructType(), cleanTypeName(f.Type), cleanTypeName(f.Type), out)
+	}`, strings.Join(f.FieldNames(), ""), f.StructType(), removeStar(f.Type), removeStar(f.Type), out)
 }
 
-func cleanTypeName(s string) string {
-	return strings.Replace(strings.Replace(s, "*", "", 1), "[]", "", 1)
+func removeStar(s string) string {
+	return strings.Replace(s, "*", "", 1)
 }
 
 func nilField(i int, f fields.Field) string {
@@ -69,8 +69,8 @@ func readRepeated(f fields.Field) string {
 }`,
 		strings.Join(f.FieldNames(), ""),
 		f.StructType(),
-		cleanTypeName(f.Type),
-		cleanTypeName(f.Type),
+		removeStar(f.Type),
+		removeStar(f.Type),
 		doReadRepeated(f, 0, "x"),
 	)
 }
@@ -78,7 +78,7 @@ func readRepeated(f fields.Field) string {
 func doReadRepeated(f fields.Field, i int, varName string) string {
 	if i == f.MaxDef() {
 		rts := f.RepetitionTypes()
-		if rts[len(rts)-1] == fields.Optional {
+		if rts[len(rts)-1] == fields.Optional && !f.Nillable() {
 			varName = fmt.Sprintf("*%s", varName)
 		}
 		if rts[len(rts)-1] != fields.Repeated {
@@ -87,7 +87,7 @@ func doReadRepeated(f fields.Field, i int, varName string) string {
 		}
 		return fmt.Sprintf(`defs = append(defs, %d)
 reps = append(reps, lastRep)
-vals = append(vals, %s)`, i, varName)
+vals = append(vals, %s)`, i, f.FromNamed(varName))
 	}
 
 	fieldName, rt, n, reps := f.NilField(i)
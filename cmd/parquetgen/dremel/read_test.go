@@ -187,6 +187,27 @@ func TestRead(t *testing.T) {
 		defs = append(defs, 2)
 		return vals, defs, reps
 	}
+}`,
+		},
+		{
+			name: "mix of optional and require and nested 3 deep v4",
+			f: fields.Field{
+				Name: "Friend", RepetitionType: fields.Optional, Children: []fields.Field{
+					{Name: "Hobby", RepetitionType: fields.Required, Children: []fields.Field{
+						{Type: "string", Name: "Name", RepetitionType: fields.Required},
+					}},
+				},
+			},
+			result: `func readFriendHobbyName(x Person, vals []string, defs, reps []uint8) ([]string, []uint8, []uint8) {
+	switch {
+	case x.Friend == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	default:
+		vals = append(vals, x.Friend.Hobby.Name)
+		defs = append(defs, 1)
+		return vals, defs, reps
+	}
 }`,
 		},
 		{
@@ -577,6 +598,93 @@ func TestRead(t *testing.T) {
 	}
 
 	return vals, defs, reps
+}`,
+		},
+		{
+			name: "named type",
+			f: fields.Field{
+				Type: "float32", Named: "Celsius", Name: "Temp", RepetitionType: fields.Required,
+			},
+			result: `func readTemp(x Person) float32 {
+	return float32(x.Temp)
+}`,
+		},
+		{
+			name: "optional named type and nested",
+			f: fields.Field{
+				Name: "Visit", Type: "Visit", RepetitionType: fields.Optional, Children: []fields.Field{
+					{Type: "float32", Named: "Celsius", Name: "Temp", RepetitionType: fields.Optional},
+				},
+			},
+			result: `func readVisitTemp(x Person, vals []float32, defs, reps []uint8) ([]float32, []uint8, []uint8) {
+	switch {
+	case x.Visit == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	case x.Visit.Temp == nil:
+		defs = append(defs, 1)
+		return vals, defs, reps
+	default:
+		vals = append(vals, float32(*x.Visit.Temp))
+		defs = append(defs, 2)
+		return vals, defs, reps
+	}
+}`,
+		},
+		{
+			name: "repeated named type",
+			f: fields.Field{
+				Type: "string", Named: "Mood", Name: "Moods", RepetitionType: fields.Repeated,
+			},
+			result: `func readMoods(x Person, vals []string, defs, reps []uint8) ([]string, []uint8, []uint8) {
+	var lastRep uint8
+
+	if len(x.Moods) == 0 {
+		defs = append(defs, 0)
+		reps = append(reps, lastRep)
+	} else {
+		for i0, x0 := range x.Moods {
+			if i0 >= 1 {
+				lastRep = 1
+			}
+			defs = append(defs, 1)
+			reps = append(reps, lastRep)
+			vals = append(vals, string(x0))
+		}
+	}
+
+	return vals, defs, reps
+}`,
+		},
+		{
+			name: "marshaler",
+			f: fields.Field{
+				Type: "int64", Named: "Money", Marshaler: true, Name: "Price", RepetitionType: fields.Required,
+			},
+			result: `func readPrice(x Person) int64 {
+	return x.Price.MarshalParquet()
+}`,
+		},
+		{
+			name: "optional marshaler and nested",
+			f: fields.Field{
+				Name: "Item", Type: "Item", RepetitionType: fields.Optional, Children: []fields.Field{
+					{Type: "int64", Named: "Money", Marshaler: true, Name: "Price", RepetitionType: fields.Optional},
+				},
+			},
+			result: `func readItemPrice(x Person, vals []int64, defs, reps []uint8) ([]int64, []uint8, []uint8) {
+	switch {
+	case x.Item == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	case x.Item.Price == nil:
+		defs = append(defs, 1)
+		return vals, defs, reps
+	default:
+		vals = append(vals, x.Item.Price.MarshalParquet())
+		defs = append(defs, 2)
+		return vals, defs, reps
+	}
 }`,
 		},
 	}
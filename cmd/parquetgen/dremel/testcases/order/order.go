@@ -0,0 +1,15 @@
+package order
+
+//go:generate parquetgen -input order.go -type Order -package order -output generated.go
+
+type LineItem struct {
+	SKU      string   `parquet:"sku"`
+	Quantity int32    `parquet:"quantity"`
+	Discount *float64 `parquet:"discount"`
+	Note     []byte   `parquet:"note"`
+}
+
+type Order struct {
+	ID        int64      `parquet:"id"`
+	LineItems []LineItem `parquet:"line_items"`
+}
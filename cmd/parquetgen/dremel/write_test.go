@@ -192,6 +192,26 @@ func TestWrite(t *testing.T) {
 		return 1, 1
 	}
 
+	return 0, 1
+}`,
+		},
+		{
+			name: "mix of optional and required and nested 3 deep v4",
+			field: fields.Field{
+				Name: "Friend", Type: "Entity", RepetitionType: fields.Optional, Children: []fields.Field{
+					{Name: "Hobby", Type: "Item", RepetitionType: fields.Required, Children: []fields.Field{
+						{Type: "string", Name: "Name", RepetitionType: fields.Required},
+					}},
+				},
+			},
+			result: `func writeFriendHobbyName(x *Person, vals []string, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Friend = &Entity{Hobby: Item{Name: vals[0]}}
+		return 1, 1
+	}
+
 	return 0, 1
 }`,
 		},
@@ -765,6 +785,93 @@ func TestWrite(t *testing.T) {
 	}
 
 	return nVals, nLevels
+}`,
+		},
+		{
+			name: "named type",
+			field: fields.Field{
+				Type: "float32", Named: "Celsius", Name: "Temp", RepetitionType: fields.Required,
+			},
+			result: `func writeTemp(x *Person, vals []float32) {
+	x.Temp = Celsius(vals[0])
+}`,
+		},
+		{
+			name: "optional named type and nested",
+			field: fields.Field{
+				Name: "Visit", Type: "Visit", RepetitionType: fields.Optional, Children: []fields.Field{
+					{Type: "float32", Named: "Celsius", Name: "Temp", RepetitionType: fields.Optional},
+				},
+			},
+			result: `func writeVisitTemp(x *Person, vals []float32, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Visit = &Visit{}
+	case 2:
+		x.Visit = &Visit{Temp: (*Celsius)(pfloat32(vals[0]))}
+		return 1, 1
+	}
+
+	return 0, 1
+}`,
+		},
+		{
+			name: "repeated named type",
+			field: fields.Field{
+				Type: "string", Named: "Mood", Name: "Moods", RepetitionType: fields.Repeated,
+			},
+			result: `func writeMoods(x *Person, vals []string, defs, reps []uint8) (int, int) {
+	var nVals, nLevels int
+	ind := make(indices, 1)
+
+	for i := range defs {
+		def := defs[i]
+		rep := reps[i]
+		if i > 0 && rep == 0 {
+			break
+		}
+
+		nLevels++
+		ind.rep(rep)
+
+		switch def {
+		case 1:
+			x.Moods = append(x.Moods, Mood(vals[nVals]))
+			nVals++
+		}
+	}
+
+	return nVals, nLevels
+}`,
+		},
+		{
+			name: "marshaler",
+			field: fields.Field{
+				Type: "int64", Named: "Money", Marshaler: true, Name: "Price", RepetitionType: fields.Required,
+			},
+			result: `func writePrice(x *Person, vals []int64) {
+	x.Price = *unmarshalMoney(vals[0])
+}`,
+		},
+		{
+			name: "optional marshaler and nested",
+			field: fields.Field{
+				Name: "Item", Type: "Item", RepetitionType: fields.Optional, Children: []fields.Field{
+					{Type: "int64", Named: "Money", Marshaler: true, Name: "Price", RepetitionType: fields.Optional},
+				},
+			},
+			result: `func writeItemPrice(x *Person, vals []int64, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Item = &Item{}
+	case 2:
+		x.Item = &Item{Price: unmarshalMoney(vals[0])}
+		return 1, 1
+	}
+
+	return 0, 1
 }`,
 		},
 	}
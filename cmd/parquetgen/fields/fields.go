@@ -2,7 +2,9 @@ package fields
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"unicode"
 )
 
 // Field holds metadata that is required by parquetgen in order
@@ -17,6 +19,35 @@ type Field struct {
 	Embedded       bool
 	NthChild       int
 	Defined        bool
+	// Logical is the parquet logical type that was requested
+	// with the struct tag (for example: `parquet:"name=dob,logical=date"`).
+	Logical string
+	// Precision and Scale are set by the struct tag of a decimal field
+	// (for example: `parquet:"name=amount,logical=decimal,precision=18,scale=2"`).
+	Precision int
+	Scale     int
+	// Unit is set by the struct tag of a timestamp or time field (millis,
+	// micros or nanos, for example: `parquet:"name=ts,logical=timestamp,unit=micros"`).
+	Unit string
+	// CRS is set by the struct tag of a geometry or geography field (for
+	// example: `parquet:"name=shape,logical=geometry,crs=OGC:CRS84"`).
+	CRS string
+	// Encoding is the encoding of the field's column that was requested
+	// with the struct tag (for example: `parquet:"name=id,encoding=delta"`).
+	// The writer's encoding options are used for the column if it's empty.
+	Encoding string
+	// Anonymous is the go source of the struct type of an anonymous
+	// struct field (like Meta struct{A int32}), whose Type is a name
+	// that parse makes up for it.
+	Anonymous string
+	// Named is the declared go type of a field whose type is a named
+	// scalar type (like Celsius for type Celsius float32), in which case
+	// Type is the underlying type that the field is written as.
+	Named string
+	// Marshaler is true if Named is converted to and from Type by its
+	// MarshalParquet and UnmarshalParquet methods (like a Money struct
+	// whose MarshalParquet returns its cents as an int64).
+	Marshaler bool
 }
 
 type input struct {
@@ -98,7 +129,7 @@ func (f Field) FieldTypes() []string {
 	var out []string
 	for _, fld := range Reverse(f.Chain()) {
 		if fld.Type != "" {
-			out = append(out, fld.Type)
+			out = append(out, fld.GoType())
 		}
 	}
 	return out
@@ -138,7 +169,21 @@ func (f Field) DefIndex(def int) int {
 	return def
 }
 
-// MaxDef cacluates the largest possible definition
+// MaxDefinitionLevel returns the largest definition level of the
+// field's column, which is the number of optional and repeated
+// fields in its RepetitionTypes.
+func (f Field) MaxDefinitionLevel() int {
+	return int(f.RepetitionTypes().MaxDef())
+}
+
+// MaxRepetitionLevel returns the largest repetition level of the
+// field's column, which is the number of repeated fields in its
+// RepetitionTypes.
+func (f Field) MaxRepetitionLevel() int {
+	return int(f.RepetitionTypes().MaxRep())
+}
+
+// MaxDef calculates the largest possible definition
 // level for the nested field.
 func (f Field) MaxDef() int {
 	var out int
@@ -150,7 +195,7 @@ func (f Field) MaxDef() int {
 	return out
 }
 
-// MaxRep cacluates the largest possible repetition
+// MaxRep calculates the largest possible repetition
 // level for the nested field.
 func (f Field) MaxRep() int {
 	var out int
@@ -386,13 +431,13 @@ func (f Field) Init(def, rep int) string {
 		case Required:
 			if fld.Primitive() {
 				if (fld.Parent.IsRoot() || fld.Parent.Defined) && fld.Parent.RepetitionType == Repeated && (rep == 0 || rep == reps) { //Should this be a check for repeated anywhere in the full chain?
-					right = fmt.Sprintf(right, "vals[nVals]%s")
+					right = fmt.Sprintf(right, fld.ToNamed("vals[nVals]")+"%s")
 				} else if (fld.Parent.Parent == nil || fld.Parent.Defined) && rep == 0 {
-					right = fmt.Sprintf(right, "vals[0]%s")
+					right = fmt.Sprintf(right, fld.ToNamed("vals[0]")+"%s")
 				} else if fld.Parent.RepetitionType == Repeated {
-					right = fmt.Sprintf(right, fmt.Sprintf("%s: vals[nVals]%%s", fld.Name))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s: %s%%s", fld.Name, fld.ToNamed("vals[nVals]")))
 				} else {
-					right = fmt.Sprintf(right, fmt.Sprintf("%s: vals[0]%%s", fld.Name))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s: %s%%s", fld.Name, fld.ToNamed("vals[0]")))
 				}
 			} else {
 				right = fmt.Sprintf(right, fmt.Sprintf("%s: %s{%%s}", fld.Name, fld.Type))
@@ -400,15 +445,15 @@ func (f Field) Init(def, rep int) string {
 		case Optional:
 			if fld.Primitive() {
 				if f.NthChild == 0 && fld.Parent.Optional() && !fld.Parent.Repeated() {
-					right = fmt.Sprintf(right, fmt.Sprintf("%s: p%s(vals[0])%%s", fld.Name, fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s: %s%%s", fld.Name, fld.pointer("vals[0]")))
 				} else if fld.Parent.RepetitionType == Repeated {
-					right = fmt.Sprintf(right, fmt.Sprintf("p%s(vals[nVals])%%s", fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s%%s", fld.pointer("vals[nVals]")))
 				} else if fld.Parent.Repeated() && f.NthChild == 0 {
-					right = fmt.Sprintf(right, fmt.Sprintf("%s: p%s(vals[nVals])%%s", fld.Name, fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s: %s%%s", fld.Name, fld.pointer("vals[nVals]")))
 				} else if fld.Parent.Repeated() && f.NthChild > 0 {
-					right = fmt.Sprintf(right, fmt.Sprintf("p%s(vals[nVals])%%s", fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s%%s", fld.pointer("vals[nVals]")))
 				} else {
-					right = fmt.Sprintf(right, fmt.Sprintf("p%s(vals[0])%%s", fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s%%s", fld.pointer("vals[0]")))
 				}
 			} else {
 				if j == 0 {
@@ -419,12 +464,13 @@ func (f Field) Init(def, rep int) string {
 			}
 		case Repeated:
 			if fld.Primitive() {
+				v := fld.ToNamed("vals[nVals]")
 				if j == 0 {
-					right = fmt.Sprintf(right, fmt.Sprintf("append(x%s, vals[nVals])%%s", left))
+					right = fmt.Sprintf(right, fmt.Sprintf("append(x%s, %s)%%s", left, v))
 				} else if !fld.IsRoot() {
-					right = fmt.Sprintf(right, fmt.Sprintf("%s: []%s{vals[nVals]}%%s", fld.Name, fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s: []%s{%s}%%s", fld.Name, fld.GoType(), v))
 				} else {
-					right = fmt.Sprintf(right, fmt.Sprintf("[]%s{vals[nVals]}%%s", fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("[]%s{%s}%%s", fld.GoType(), v))
 				}
 			} else {
 				if rep > 0 && reps == rep || (fld.MaxRepForDef(def) == rep && !strings.Contains(right, "append(")) {
@@ -475,8 +521,7 @@ func (f Field) Path() string {
 // Primitive is called in order to determine if the field is primitive or not.
 
 func (f Field) Primitive() bool {
-	_, ok := primitiveTypes[f.Type]
-	return ok
+	return f.fieldType().name != ""
 }
 
 func (f Field) FieldType() string {
@@ -485,12 +530,31 @@ func (f Field) FieldType() string {
 		op = "Optional"
 	}
 
-	ft := primitiveTypes[f.Type]
+	ft := f.fieldType()
 	return fmt.Sprintf(ft.name, op, "Field")
 }
 
 func (f Field) ParquetType() string {
-	ft := primitiveTypes[f.Type]
+	if f.Logical == "decimal" {
+		typ := fmt.Sprintf(primitiveTypes[f.Type].name, "", "Type")
+		if n, ok := f.FixedLen(); ok {
+			typ = fmt.Sprintf("FixedLenByteArrayType(%d)", n)
+		}
+		if f.Type == "big.Int" {
+			typ = fmt.Sprintf("FixedLenByteArrayType(%d)", f.BigIntLen())
+		}
+		return fmt.Sprintf("DecimalType(%d, %d, %s)", f.Precision, f.Scale, typ)
+	}
+	if f.Logical == "geometry" || f.Logical == "geography" {
+		return fmt.Sprintf("%sType(%q)", strings.Title(f.Logical), f.CRS)
+	}
+	if n, ok := f.FixedLen(); ok && f.Logical == "" {
+		return fmt.Sprintf("FixedLenByteArrayType(%d)", n)
+	}
+	if f.Type == "[]byte" && f.Logical == "" {
+		return "ByteArrayType"
+	}
+	ft := f.fieldType()
 	return fmt.Sprintf(ft.name, "", "Type")
 }
 
@@ -500,24 +564,227 @@ func (f Field) Category() string {
 		op = "Optional"
 	}
 
-	ft := primitiveTypes[f.Type]
+	ft := f.fieldType()
 	return fmt.Sprintf(ft.category, op)
 }
 
+// PointerFunc is the name of the function in the generated code
+// that returns a pointer to a value of the field's type.
+//
+// example: pint32, ptime, pfixedlenbytearray16
+func (f Field) PointerFunc() string {
+	if f.Logical == "null" {
+		return "pnull"
+	}
+	if _, ok := f.FixedLen(); ok || f.Type == "big.Int" {
+		return fmt.Sprintf("p%s", strings.ToLower(fmt.Sprintf(f.fieldType().name, "", "")))
+	}
+	parts := strings.Split(f.Type, ".")
+	return fmt.Sprintf("p%s", strings.ToLower(parts[len(parts)-1]))
+}
+
+// FixedLen returns the length of a fixed size byte
+// array field (for example: [16]byte).
+func (f Field) FixedLen() (int, bool) {
+	var n int
+	if _, err := fmt.Sscanf(f.Type, "[%d]byte", &n); err != nil || f.Type != fmt.Sprintf("[%d]byte", n) || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// pointer returns the code that turns the value v into
+// the type of an optional field.
+func (f Field) pointer(v string) string {
+	if f.Nillable() {
+		return v
+	}
+	if f.Marshaler {
+		return fmt.Sprintf("%s(%s)", f.UnmarshalFunc(), v)
+	}
+	if f.Named != "" {
+		return fmt.Sprintf("(*%s)(%s(%s))", f.Named, f.PointerFunc(), v)
+	}
+	return fmt.Sprintf("%s(%s)", f.PointerFunc(), v)
+}
+
+// GoType is the type of the field in its go struct, which
+// is Named for a named scalar type (otherwise it is Type).
+func (f Field) GoType() string {
+	if f.Named != "" {
+		return f.Named
+	}
+	return f.Type
+}
+
+// ToNamed returns the code that converts v, a value of the
+// field's Type, to its named type (if it has one).
+func (f Field) ToNamed(v string) string {
+	if f.Marshaler {
+		return fmt.Sprintf("*%s(%s)", f.UnmarshalFunc(), v)
+	}
+	if f.Named == "" {
+		return v
+	}
+	return fmt.Sprintf("%s(%s)", f.Named, v)
+}
+
+// FromNamed returns the code that converts v, a value of the
+// field's named type (if it has one), to its Type.
+func (f Field) FromNamed(v string) string {
+	if f.Marshaler {
+		// the method can be called on a pointer, so
+		// a dereferenced optional value doesn't need to be
+		return fmt.Sprintf("%s.MarshalParquet()", strings.TrimPrefix(v, "*"))
+	}
+	if f.Named == "" {
+		return v
+	}
+	return fmt.Sprintf("%s(%s)", f.Type, v)
+}
+
+// UnmarshalFunc is the name of the generated function that
+// returns a pointer to a new Named that is unmarshaled from
+// a value of the field's Type (see Marshaler).
+func (f Field) UnmarshalFunc() string {
+	return "unmarshal" + f.Named
+}
+
+// MarshalerInterface is the name, without its Marshaler or Unmarshaler
+// suffix, of the interfaces in package parquet that the type of a
+// Marshaler field implements.
+//
+// example: Int64, Time, Bytes
+func (f Field) MarshalerInterface() string {
+	switch f.Type {
+	case "time.Time":
+		return "Time"
+	case "[]byte":
+		return "Bytes"
+	}
+	return strings.ToUpper(f.Type[:1]) + f.Type[1:]
+}
+
+// Nillable is true if the go type can be nil without
+// being a pointer, so an optional field uses the type as is.
+func (f Field) Nillable() bool {
+	return f.Type == "[]byte"
+}
+
+// IsMap is true if the field is a go map (for example:
+// map[string]int32), which is written as a parquet MAP.
+func (f Field) IsMap() bool {
+	return strings.HasPrefix(f.Type, "map[")
+}
+
+// MapTypes returns the key and value types of a map field.
+func (f Field) MapTypes() (string, string) {
+	i := strings.Index(f.Type, "]")
+	return f.Type[len("map["):i], f.Type[i+1:]
+}
+
+// Map returns the map field that f is the key or value of.
+func (f Field) Map() (Field, bool) {
+	if f.Parent == nil || f.Parent.Parent == nil || !f.Parent.Parent.IsMap() {
+		return Field{}, false
+	}
+	return *f.Parent.Parent, true
+}
+
 func (f Field) TypeName() string {
 	var star string
-	if f.RepetitionType == Optional {
+	if f.RepetitionType == Optional && !f.Nillable() {
 		star = "*"
 	}
 	return fmt.Sprintf("%s%s", star, f.Type)
 }
 
+// SupportsLogical is true if the logical type requested by the
+// struct tag can be used with the field's go type.
+func (f Field) SupportsLogical() bool {
+	if _, ok := f.FixedLen(); ok && f.Logical == "decimal" {
+		return true
+	}
+	// a column that is always null has to be optional
+	if f.Logical == "null" && f.RepetitionType != Optional {
+		return false
+	}
+	// a big.Int is only used through a pointer (or in a slice)
+	if f.Type == "big.Int" && f.RepetitionType == Required {
+		return false
+	}
+	_, ok := logicalTypes[f.Logical][f.Type]
+	return ok
+}
+
+// MaxPrecision is the largest precision a decimal field's go type can hold.
+func (f Field) MaxPrecision() int {
+	switch f.Type {
+	case "int32":
+		return 9
+	case "int64":
+		return 18
+	case "big.Int":
+		return 76
+	}
+
+	if n, ok := f.FixedLen(); ok {
+		return fixedPrecision(n)
+	}
+	return 0
+}
+
+// BigIntLen is the length of the FIXED_LEN_BYTE_ARRAY that a big.Int
+// decimal is stored in, which is the fewest bytes its precision fits in.
+func (f Field) BigIntLen() int {
+	n := 1
+	for fixedPrecision(n) < f.Precision {
+		n++
+	}
+	return n
+}
+
+// fixedPrecision is the largest precision an n byte decimal can
+// hold (the largest n byte two's complement is 2^(8n-1) - 1).
+func fixedPrecision(n int) int {
+	return int(float64(8*n-1) * math.Log10(2))
+}
+
+func (f Field) fieldType() fieldType {
+	if ft, ok := logicalTypes[f.Logical][f.Type]; ok {
+		if f.Logical == "decimal" {
+			// each precision and scale needs its own field type
+			// because the schema is part of the field type.
+			ft.name = fmt.Sprintf(ft.name, f.Precision, f.Scale)
+		}
+		if f.Logical == "timestamp" || f.Logical == "time" {
+			ft.name = fmt.Sprintf(ft.name, timestampUnits[f.Unit])
+		}
+		if f.Logical == "geometry" || f.Logical == "geography" {
+			// like a decimal, each crs needs its own field type
+			ft.name = fmt.Sprintf(ft.name, crsName(f.CRS))
+		}
+		return ft
+	}
+	if n, ok := f.FixedLen(); ok {
+		if f.Logical == "decimal" {
+			return fieldType{fmt.Sprintf("FixedLenByteArray%dDecimal%d_%d%%s%%s", n, f.Precision, f.Scale), "fixed%s"}
+		}
+		return fieldType{fmt.Sprintf("FixedLenByteArray%d%%s%%s", n), "fixed%s"}
+	}
+	return primitiveTypes[f.Type]
+}
+
 type fieldType struct {
 	name     string
 	category string
 }
 
 var primitiveTypes = map[string]fieldType{
+	"int8":    {"Int8%s%s", "numeric%s"},
+	"int16":   {"Int16%s%s", "numeric%s"},
+	"uint8":   {"Uint8%s%s", "numeric%s"},
+	"uint16":  {"Uint16%s%s", "numeric%s"},
 	"int32":   {"Int32%s%s", "numeric%s"},
 	"uint32":  {"Uint32%s%s", "numeric%s"},
 	"int64":   {"Int64%s%s", "numeric%s"},
@@ -526,6 +793,140 @@ var primitiveTypes = map[string]fieldType{
 	"float64": {"Float64%s%s", "numeric%s"},
 	"bool":    {"Bool%s%s", "bool%s"},
 	"string":  {"String%s%s", "string%s"},
+
+	"time.Time":     {"Timestamp%s%s", "time%s"},
+	"time.Duration": {"Interval%s%s", "interval%s"},
+	"[]byte":        {"Bytes%s%s", "bytes%s"},
+}
+
+// logicalTypes maps a logical type (set by a struct tag) and a go
+// type to the fieldType that is used in place of the go type's default.
+var logicalTypes = map[string]map[string]fieldType{
+	"date": {
+		"time.Time": {"Date%s%s", "time%s"},
+	},
+	"timestamp": {
+		"time.Time": {"Timestamp%s%%s%%s", "time%s"},
+	},
+	"time": {
+		"time.Duration": {"TimeOfDay%s%%s%%s", "time%s"},
+	},
+	"uuid": {
+		"[16]byte": {"UUID%s%s", "fixed%s"},
+	},
+	"float16": {
+		"uint16": {"Float16%s%s", "time%s"},
+	},
+	"enum": {
+		"string": {"Enum%s%s", "string%s"},
+	},
+	"json": {
+		"string": {"JSON%s%s", "string%s"},
+	},
+	"bson": {
+		"[]byte": {"BSON%s%s", "bytes%s"},
+	},
+	"decimal": {
+		"int32":   {"Int32Decimal%d_%d%%s%%s", "numeric%s"},
+		"int64":   {"Int64Decimal%d_%d%%s%%s", "numeric%s"},
+		"big.Int": {"BigIntDecimal%d_%d%%s%%s", "bigint%s"},
+	},
+	"null": {
+		"struct{}": {"Null%s%s", "null%s"},
+	},
+	"geometry": {
+		"[]byte": {"Geometry%s%%s%%s", "bytes%s"},
+	},
+	"geography": {
+		"[]byte": {"Geography%s%%s%%s", "bytes%s"},
+	},
+}
+
+// crsName is the part of a geometry or geography field type's name that
+// comes from its crs, which is the crs without anything that can't be in
+// a go identifier (so OGC:CRS84 is OGCCRS84).
+func crsName(crs string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return -1
+		}
+		return r
+	}, crs)
+}
+
+// timestampUnits are the units of a timestamp (or time) field and what
+// they add to the name of its field type (millis is the default, so it
+// doesn't add anything).
+var timestampUnits = map[string]string{
+	"millis": "",
+	"micros": "Micros",
+	"nanos":  "Nanos",
+}
+
+// SupportsCRS is true if the field's crs (set by the struct tag)
+// is on a field whose logical type can have one.
+func (f Field) SupportsCRS() bool {
+	return f.CRS == "" || f.Logical == "geometry" || f.Logical == "geography"
+}
+
+// SupportsUnit is true if the field's unit (set by the
+// struct tag) is one that its logical type can use.
+func (f Field) SupportsUnit() bool {
+	if f.Logical != "timestamp" && f.Logical != "time" {
+		return f.Unit == ""
+	}
+	_, ok := timestampUnits[f.Unit]
+	return ok
+}
+
+// encodings are the encodings that can be set by the struct tag, the
+// name of each one in the parquet format and whether a field's column
+// can be written with it.
+var encodings = map[string]struct {
+	name     string
+	supports func(Field) bool
+}{
+	"plain":                   {"PLAIN", func(Field) bool { return true }},
+	"dictionary":              {"RLE_DICTIONARY", Field.byteArray},
+	"delta":                   {"DELTA_BINARY_PACKED", Field.integer},
+	"delta_length_byte_array": {"DELTA_LENGTH_BYTE_ARRAY", Field.byteArray},
+	"delta_byte_array":        {"DELTA_BYTE_ARRAY", Field.byteArray},
+}
+
+// SupportsEncoding is true if the field's encoding (set by the
+// struct tag) can be used with the field's column type.
+func (f Field) SupportsEncoding() bool {
+	if f.Encoding == "" {
+		return true
+	}
+	enc, ok := encodings[f.Encoding]
+	return ok && enc.supports(f)
+}
+
+// EncodingName is the parquet name of the field's encoding
+// (for example: DELTA_BINARY_PACKED for delta).
+func (f Field) EncodingName() string {
+	return encodings[f.Encoding].name
+}
+
+// integer is true if the field's column is an INT32 or INT64.
+func (f Field) integer() bool {
+	switch f.Type {
+	case "int8", "int16", "uint8", "uint16", "int32", "uint32", "int64", "uint64":
+		// a float16 is a FIXED_LEN_BYTE_ARRAY
+		return f.Logical != "float16"
+	case "time.Time":
+		return true
+	case "time.Duration":
+		// a time.Duration without a logical type is an INTERVAL
+		return f.Logical == "time"
+	}
+	return false
+}
+
+// byteArray is true if the field's column is a BYTE_ARRAY.
+func (f Field) byteArray() bool {
+	return f.Type == "string" || f.Type == "[]byte"
 }
 
 func max(i []int) int {
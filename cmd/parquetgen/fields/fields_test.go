@@ -48,6 +48,134 @@ func TestNilFields(t *testing.T) {
 	}
 }
 
+func TestLogicalTypes(t *testing.T) {
+	testCases := []struct {
+		f           fields.Field
+		fieldType   string
+		parquetType string
+		category    string
+	}{
+		{
+			f:           fields.Field{Type: "string", Logical: "enum", RepetitionType: fields.Required},
+			fieldType:   "EnumField",
+			parquetType: "EnumType",
+			category:    "string",
+		},
+		{
+			f:           fields.Field{Type: "string", Logical: "enum", RepetitionType: fields.Optional},
+			fieldType:   "EnumOptionalField",
+			parquetType: "EnumType",
+			category:    "stringOptional",
+		},
+		{
+			f:           fields.Field{Type: "string", Logical: "json", RepetitionType: fields.Required},
+			fieldType:   "JSONField",
+			parquetType: "JSONType",
+			category:    "string",
+		},
+		{
+			f:           fields.Field{Type: "string", Logical: "json", RepetitionType: fields.Optional},
+			fieldType:   "JSONOptionalField",
+			parquetType: "JSONType",
+			category:    "stringOptional",
+		},
+		{
+			f:           fields.Field{Type: "[]byte", Logical: "bson", RepetitionType: fields.Required},
+			fieldType:   "BSONField",
+			parquetType: "BSONType",
+			category:    "bytes",
+		},
+		{
+			f:           fields.Field{Type: "[]byte", Logical: "bson", RepetitionType: fields.Optional},
+			fieldType:   "BSONOptionalField",
+			parquetType: "BSONType",
+			category:    "bytesOptional",
+		},
+		{
+			f:           fields.Field{Type: "time.Time", Logical: "date", RepetitionType: fields.Required},
+			fieldType:   "DateField",
+			parquetType: "DateType",
+			category:    "time",
+		},
+		{
+			f:           fields.Field{Type: "time.Duration", Logical: "time", Unit: "millis", RepetitionType: fields.Required},
+			fieldType:   "TimeOfDayField",
+			parquetType: "TimeOfDayType",
+			category:    "time",
+		},
+		{
+			f:           fields.Field{Type: "time.Duration", Logical: "time", Unit: "micros", RepetitionType: fields.Optional},
+			fieldType:   "TimeOfDayMicrosOptionalField",
+			parquetType: "TimeOfDayMicrosType",
+			category:    "timeOptional",
+		},
+		{
+			f:           fields.Field{Type: "time.Duration", Logical: "time", Unit: "nanos", RepetitionType: fields.Required},
+			fieldType:   "TimeOfDayNanosField",
+			parquetType: "TimeOfDayNanosType",
+			category:    "time",
+		},
+		{
+			f:           fields.Field{Type: "[16]byte", Logical: "uuid", RepetitionType: fields.Required},
+			fieldType:   "UUIDField",
+			parquetType: "UUIDType",
+			category:    "fixed",
+		},
+		{
+			f:           fields.Field{Type: "uint16", Logical: "float16", RepetitionType: fields.Required},
+			fieldType:   "Float16Field",
+			parquetType: "Float16Type",
+			category:    "time",
+		},
+		{
+			f:           fields.Field{Type: "uint16", Logical: "float16", RepetitionType: fields.Optional},
+			fieldType:   "Float16OptionalField",
+			parquetType: "Float16Type",
+			category:    "timeOptional",
+		},
+		{
+			f:           fields.Field{Type: "int64", Logical: "decimal", Precision: 18, Scale: 2, RepetitionType: fields.Required},
+			fieldType:   "Int64Decimal18_2Field",
+			parquetType: "DecimalType(18, 2, Int64Type)",
+			category:    "numeric",
+		},
+		{
+			f:           fields.Field{Type: "[16]byte", Logical: "decimal", Precision: 38, Scale: 4, RepetitionType: fields.Optional},
+			fieldType:   "FixedLenByteArray16Decimal38_4OptionalField",
+			parquetType: "DecimalType(38, 4, FixedLenByteArrayType(16))",
+			category:    "fixedOptional",
+		},
+		{
+			f:           fields.Field{Type: "big.Int", Logical: "decimal", Precision: 38, Scale: 2, RepetitionType: fields.Optional},
+			fieldType:   "BigIntDecimal38_2OptionalField",
+			parquetType: "DecimalType(38, 2, FixedLenByteArrayType(16))",
+			category:    "bigintOptional",
+		},
+		{
+			f:           fields.Field{Type: "[]byte", Logical: "geometry", CRS: "OGC:CRS84", RepetitionType: fields.Required},
+			fieldType:   "GeometryOGCCRS84Field",
+			parquetType: `GeometryType("OGC:CRS84")`,
+			category:    "bytes",
+		},
+		{
+			f:           fields.Field{Type: "[]byte", Logical: "geography", RepetitionType: fields.Optional},
+			fieldType:   "GeographyOptionalField",
+			parquetType: `GeographyType("")`,
+			category:    "bytesOptional",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.fieldType, func(t *testing.T) {
+			tc.f.Parent = &fields.Field{Type: "Person"}
+			assert.True(t, tc.f.SupportsLogical())
+			assert.Equal(t, tc.fieldType, tc.f.FieldType())
+			assert.Equal(t, tc.parquetType, tc.f.ParquetType())
+			assert.Equal(t, tc.category, tc.f.Category())
+		})
+	}
+}
+
 func TestInit(t *testing.T) {
 	testCases := []struct {
 		fields   []fields.Field
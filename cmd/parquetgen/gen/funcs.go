@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"github.com/parsyl/parquet/cmd/parquetgen/cases"
 	"github.com/parsyl/parquet/cmd/parquetgen/dremel"
@@ -13,15 +14,14 @@ import (
 var (
 	funcs = template.FuncMap{
 		"removeStar": func(s string) string {
-			return strings.Replace(strings.Replace(s, "*", "", 1), "[]", "", 1)
+			return strings.Replace(s, "*", "", 1)
 		},
 		"camelCase": func(s string) string {
 			return cases.Camel(s)
 		},
-		"camelCaseRemoveStar": func(s string) string {
-			return cases.Camel(strings.Replace(strings.Replace(s, "*", "", 1), "[]", "", 1))
-		},
-		"dedupe": dedupe,
+		"dedupe":      dedupe,
+		"dedupeStats": dedupeStats,
+		"marshalers":  marshalers,
 		"compressionFunc": func(f fields.Field) string {
 			if strings.Contains(f.Category(), "Optional") {
 				return "optionalFieldCompression"
@@ -31,6 +31,9 @@ var (
 		"funcName": func(f fields.Field) string {
 			return strings.Join(f.FieldNames(), "")
 		},
+		"funcPath": func(f fields.Field) string {
+			return strings.Join(f.FieldNames(), ".")
+		},
 		"join": func(names []string) string {
 			return strings.Join(names, ".")
 		},
@@ -44,6 +47,14 @@ var (
 		"maxType": func(f fields.Field) string {
 			var out string
 			switch f.Type {
+			case "int8", "*int8":
+				out = "math.MaxInt8"
+			case "int16", "*int16":
+				out = "math.MaxInt16"
+			case "uint8", "*uint8":
+				out = "math.MaxUint8"
+			case "uint16", "*uint16":
+				out = "math.MaxUint16"
 			case "int32", "*int32":
 				out = "math.MaxInt32"
 			case "int64", "*int64":
@@ -59,7 +70,140 @@ var (
 			}
 			return out
 		},
+		"minType": func(f fields.Field) string {
+			var out string
+			switch f.Type {
+			case "int8", "*int8":
+				out = "math.MinInt8"
+			case "int16", "*int16":
+				out = "math.MinInt16"
+			}
+			return out
+		},
+		// readType is the type that binary.Read decodes a column's
+		// values into.  Types that parquet doesn't have a physical type
+		// for are widened to INT32 and need to be narrowed after reading.
+		"readType": func(f fields.Field) string {
+			switch f.Type {
+			case "int8", "*int8", "int16", "*int16":
+				return "int32"
+			case "uint8", "*uint8", "uint16", "*uint16":
+				return "uint32"
+			}
+			return strings.Replace(f.TypeName(), "*", "", 1)
+		},
+		"narrow": func(f fields.Field) bool {
+			switch f.Type {
+			case "int8", "*int8", "int16", "*int16", "uint8", "*uint8", "uint16", "*uint16":
+				return true
+			}
+			return false
+		},
+		// statsType is the name of the generated stats struct
+		// for fields whose stats are computed from their stored
+		// value rather than their go value (timeStats, etc).
+		"statsType": func(f fields.Field) string {
+			s := strings.Replace(f.FieldType(), "Field", "Stats", 1)
+			// lower the whole acronym for names like UUIDStats
+			n := strings.IndexFunc(s, unicode.IsLower)
+			if n < 2 {
+				n = 2
+			}
+			return strings.ToLower(s[:n-1]) + s[n-1:]
+		},
+		"fixedLess": fixedLess,
+		"fixedLen": func(f fields.Field) int {
+			n, _ := f.FixedLen()
+			return n
+		},
+		"storedType": func(f fields.Field) string {
+			return storageOf(f).typ
+		},
+		"uintType": func(f fields.Field) string {
+			return storageOf(f).uint
+		},
+		// toStored converts the go value v to the value that is
+		// written to the parquet file.
+		"toStored": func(f fields.Field, v string) string {
+			return strings.Replace(storageOf(f).to, "$v", v, -1)
+		},
+		// checkStored is the code that returns a value and an error
+		// if the go value v can't be stored (it's empty if every
+		// value can be).
+		"checkStored": func(f fields.Field, v string) string {
+			return strings.Replace(storageOf(f).check, "$v", v, -1)
+		},
+		// fromStored converts the value x from the parquet file to
+		// the go value.
+		"fromStored": func(f fields.Field, x string) string {
+			return strings.Replace(storageOf(f).from, "$x", x, -1)
+		},
+		// storedLess is the code that compares the stored values a and b.
+		"storedLess": func(f fields.Field, a, b string) string {
+			less := storageOf(f).less
+			if less == "" {
+				less = "$a < $b"
+			}
+			return strings.NewReplacer("$a", a, "$b", b).Replace(less)
+		},
+		// isNaN is the code that checks whether the value v of a
+		// floating point field is a NaN, which is left out of the
+		// min and max statistics (it can't be ordered).  It's empty
+		// for fields that can't be NaN.
+		"isNaN": func(f fields.Field, v string) string {
+			if nan := storageOf(f).nan; nan != "" {
+				return strings.Replace(nan, "$x", v, -1)
+			}
+			switch f.Type {
+			case "float32", "*float32":
+				return fmt.Sprintf("math.IsNaN(float64(%s))", v)
+			case "float64", "*float64":
+				return fmt.Sprintf("math.IsNaN(%s)", v)
+			}
+			return ""
+		},
+		// anonymous are the anonymous struct fields, which need
+		// a type declared for them.
+		"anonymous": anonymous,
+		// maps are the map fields of the struct.
+		"maps": func(f fields.Field) []fields.Field {
+			var out []fields.Field
+			for _, ch := range f.Children {
+				if ch.IsMap() {
+					out = append(out, ch)
+				}
+			}
+			return out
+		},
+		// bigInts is true if any of the fields are big.Int
+		// decimals, which need math/big imported.
+		"bigInts": func(fs []fields.Field) bool {
+			for _, f := range fs {
+				if f.Type == "big.Int" {
+					return true
+				}
+			}
+			return false
+		},
+		"isMapField": func(f fields.Field) bool {
+			_, ok := f.Map()
+			return ok
+		},
+		// mapKeys is the name of the variable that keeps track of
+		// a map's keys (see dremel.Write) for the map field f or
+		// its key and value fields.
+		"mapKeys": func(f fields.Field) string {
+			if m, ok := f.Map(); ok {
+				f = m
+			}
+			return strings.ToLower(f.Name[:1]) + f.Name[1:] + "Keys"
+		},
+		"mapKeyType": func(f fields.Field) string {
+			k, _ := f.MapTypes()
+			return k
+		},
 		"columnName":    func(f fields.Field) string { return strings.Join(f.ColumnNames(), ".") },
+		"lessFunc":      lessFunc,
 		"writeFunc":     dremel.Write,
 		"readFunc":      dremel.Read,
 		"writeFuncName": func(f fields.Field) string { return fmt.Sprintf("write%s", strings.Join(f.FieldNames(), "")) },
@@ -71,9 +215,13 @@ var (
 			return "parquet.RequiredField"
 		},
 		"byteSize": func(f fields.Field) string {
+			if s := storageOf(f); s.size != "" {
+				return s.size
+			}
+
 			var out string
 			switch f.Type {
-			case "int32", "*int32", "uint32", "*uint32", "float32", "*float32":
+			case "int8", "*int8", "int16", "*int16", "uint8", "*uint8", "uint16", "*uint16", "int32", "*int32", "uint32", "*uint32", "float32", "*float32":
 				out = "4"
 			case "int64", "*int64", "uint64", "*uint64", "float64", "*float64":
 				out = "8"
@@ -82,9 +230,13 @@ var (
 		},
 		// based on binary.Write
 		"putFunc": func(f fields.Field) string {
+			if s := storageOf(f); s.put != "" {
+				return s.put
+			}
+
 			var out string
 			switch f.Type {
-			case "int32", "*int32", "uint32", "*uint32", "float32", "*float32":
+			case "int8", "*int8", "int16", "*int16", "uint8", "*uint8", "uint16", "*uint16", "int32", "*int32", "uint32", "*uint32", "float32", "*float32":
 				out = "PutUint32"
 			case "int64", "*int64", "uint64", "*uint64", "float64", "*float64":
 				out = "PutUint64"
@@ -95,9 +247,9 @@ var (
 		"uintFunc": func(f fields.Field) string {
 			var out string
 			switch f.Type {
-			case "int32":
+			case "int8", "int16", "int32", "uint8", "uint16":
 				out = "uint32(v)"
-			case "*int32":
+			case "*int8", "*int16", "*int32", "*uint8", "*uint16":
 				out = "uint32(*v)"
 			case "uint32":
 				out = "v"
@@ -124,3 +276,209 @@ var (
 		},
 	}
 )
+
+// storage describes how a go type that has no direct parquet
+// equivalent (time.Time, a time.Duration that's a time of day or
+// a uint16 that's a half precision float) is stored in a parquet file.
+type storage struct {
+	typ  string
+	uint string
+	size string
+	put  string
+	// to and from are expressions that convert between the go value
+	// ($v) and the stored value ($x).
+	to   string
+	from string
+	// less compares two stored values ($a and $b), which is done
+	// with < unless the stored values aren't in the right order.
+	less string
+	// nan checks whether the stored value $x is a NaN (for
+	// floating point types).
+	nan string
+	// check returns an error (as its second result) if the go value
+	// $v can't be stored, which is only needed when to can't store
+	// every value.
+	check string
+}
+
+var storages = map[string]storage{
+	"Timestamp": {
+		typ:  "int64",
+		uint: "uint64",
+		size: "8",
+		put:  "PutUint64",
+		to:   "$v.Unix()*1000 + int64($v.Nanosecond())/int64(time.Millisecond)",
+		from: "time.Unix($x/1000, ($x%1000)*int64(time.Millisecond)).UTC()",
+	},
+	"TimestampMicros": {
+		typ:  "int64",
+		uint: "uint64",
+		size: "8",
+		put:  "PutUint64",
+		to:   "$v.Unix()*1000000 + int64($v.Nanosecond())/int64(time.Microsecond)",
+		from: "time.Unix($x/1000000, ($x%1000000)*int64(time.Microsecond)).UTC()",
+	},
+	"TimestampNanos": {
+		typ:  "int64",
+		uint: "uint64",
+		size: "8",
+		put:  "PutUint64",
+		to:   "$v.UnixNano()",
+		from: "time.Unix(0, $x).UTC()",
+		// UnixNano overflows outside of 1677 to 2262
+		check: "parquet.TimestampNanos($v)",
+	},
+	"Date": {
+		typ:  "int32",
+		uint: "uint32",
+		size: "4",
+		put:  "PutUint32",
+		to:   "int32($v.Truncate(24*time.Hour).Unix() / 86400)",
+		from: "time.Unix(int64($x)*86400, 0).UTC()",
+	},
+	"TimeOfDay": {
+		typ:  "int32",
+		uint: "uint32",
+		size: "4",
+		put:  "PutUint32",
+		to:   "int32($v / time.Millisecond)",
+		from: "time.Duration($x) * time.Millisecond",
+	},
+	"TimeOfDayMicros": {
+		typ:  "int64",
+		uint: "uint64",
+		size: "8",
+		put:  "PutUint64",
+		to:   "int64($v / time.Microsecond)",
+		from: "time.Duration($x) * time.Microsecond",
+	},
+	"TimeOfDayNanos": {
+		typ:  "int64",
+		uint: "uint64",
+		size: "8",
+		put:  "PutUint64",
+		to:   "int64($v)",
+		from: "time.Duration($x)",
+	},
+	"Float16": {
+		typ:  "uint16",
+		uint: "uint16",
+		size: "2",
+		put:  "PutUint16",
+		to:   "$v",
+		from: "$x",
+		less: "parquet.Float16Less($a, $b)",
+		nan:  "parquet.Float16IsNaN($x)",
+	},
+}
+
+func storageOf(f fields.Field) storage {
+	n := strings.TrimSuffix(f.FieldType(), "Field")
+	return storages[strings.TrimSuffix(n, "Optional")]
+}
+
+// fixedLess is the code that compares two fixed length byte
+// arrays, which are signed numbers if the field is a decimal.
+func fixedLess(f fields.Field, a, b string) string {
+	if f.Logical == "decimal" {
+		return fmt.Sprintf("parquet.DecimalLess(%s[:], %s[:])", a, b)
+	}
+	return fmt.Sprintf("string(%s[:]) < string(%s[:])", a, b)
+}
+
+// lessFunc is the code of a function that reports whether row a comes
+// before row b when they are sorted by the (non-repeated) field f.  A nil
+// value (or a nil struct on the way to it) comes before any other value.
+func lessFunc(f fields.Field) string {
+	var pth []string
+	var nils []string
+	for _, fld := range fields.Reverse(f.Chain()) {
+		if fld.Name == "" {
+			continue
+		}
+		pth = append(pth, fld.Name)
+		if fld.RepetitionType == fields.Optional {
+			nils = append(nils, fmt.Sprintf("$r.%s == nil", strings.Join(pth, ".")))
+		}
+	}
+
+	v := "$r." + strings.Join(pth, ".")
+	// paren is used when the value is the receiver of a method or sliced
+	paren := func(v string) string { return v }
+	if f.RepetitionType == fields.Optional && !f.Nillable() {
+		v = "*" + v
+		paren = func(v string) string { return "(" + v + ")" }
+	}
+
+	x, y := strings.Replace(v, "$r", "a", 1), strings.Replace(v, "$r", "b", 1)
+	if f.Marshaler {
+		// the values are compared as the type that they marshal to
+		x, y = f.FromNamed(x), f.FromNamed(y)
+		paren = func(v string) string { return v }
+	}
+	var less string
+	switch {
+	case f.Logical == "null":
+		// the values are always null
+		less = "false"
+	case f.Logical == "float16":
+		less = fmt.Sprintf("parquet.Float16Less(%s, %s)", x, y)
+	case f.Type == "bool" && f.Marshaler:
+		less = fmt.Sprintf("!%s && %s", x, y)
+	case f.Type == "bool":
+		// a named bool type (like type Flag bool) would
+		// make the result a Flag instead of a bool
+		less = fmt.Sprintf("!%s && %s", f.FromNamed(x), f.FromNamed(y))
+	case f.Type == "time.Time" && f.Named != "" && !f.Marshaler:
+		// a named time type (like type Date time.Time)
+		// doesn't have the methods of time.Time
+		less = fmt.Sprintf("%s.Before(%s)", f.FromNamed(x), f.FromNamed(y))
+	case f.Type == "time.Time":
+		less = fmt.Sprintf("%s.Before(%s)", paren(x), y)
+	case f.Type == "big.Int":
+		less = fmt.Sprintf("%s.Cmp(%s) < 0", strings.TrimPrefix(x, "*"), strings.TrimPrefix(y, "*"))
+	case f.Type == "[]byte":
+		less = fmt.Sprintf("string(%s) < string(%s)", x, y)
+	case strings.HasPrefix(f.Type, "["):
+		less = fixedLess(f, paren(x), paren(y))
+	default:
+		less = fmt.Sprintf("%s < %s", x, y)
+	}
+
+	if len(nils) == 0 {
+		return fmt.Sprintf("func(a, b %s) bool { return %s }", f.StructType(), less)
+	}
+
+	isNil := strings.Join(nils, " || ")
+	aNil, bNil := strings.Replace(isNil, "$r", "a", -1), strings.Replace(isNil, "$r", "b", -1)
+	return fmt.Sprintf(`func(a, b %s) bool {
+		if %s {
+			return !(%s)
+		}
+		if %s {
+			return false
+		}
+		return %s
+	}`, f.StructType(), aNil, bNil, bNil, less)
+}
+
+// anonymous returns the anonymous struct fields of f and its children.
+// A struct that is used by more than one field (like []Being and Being)
+// has the same anonymous struct fields each time, so those are only
+// returned once.
+func anonymous(f fields.Field) []fields.Field {
+	var out []fields.Field
+	seen := map[string]bool{}
+	var add func(f fields.Field)
+	add = func(f fields.Field) {
+		for _, ch := range f.Children {
+			if ch.Anonymous != "" && !seen[ch.Type] {
+				seen[ch.Type] = true
+				out = append(out, ch)
+			}
+			add(ch)
+		}
+	}
+	add(f)
+	return out
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go/format"
 	"os"
+	"strings"
 	"text/template"
 
 	"github.com/parsyl/parquet"
@@ -26,15 +27,17 @@ var (
 )
 
 // FromStruct generates a parquet reader and writer based on the struct
-// of type 'typ' that is defined in the go file at 'pth'.
-func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
-	result, err := parse.Fields(typ, pth)
+// of type 'typ' that is defined in the go file at 'pth'.  If 'prefix'
+// isn't empty it is added to the names of everything that is generated.
+// opts are passed on to parse.Fields.
+func FromStruct(pth, outPth, typ, pkg, imp, prefix string, ignore bool, opts ...func(*parse.Options)) error {
+	result, err := parse.Fields(typ, pth, opts...)
 	if err != nil {
 		return err
 	}
 
-	if len(result.Errors) > 0 && !ignore {
-		return fmt.Errorf("not generating parquet.go (-ignore set to false), err: %v", result.Errors)
+	if err := result.Err(); err != nil && !ignore {
+		return fmt.Errorf("not generating %s, %s has unsupported fields:\n%s", outPth, typ, err)
 	}
 
 	i := input{
@@ -57,6 +60,16 @@ func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
 		stringOptionalTpl,
 		boolTpl,
 		boolOptionalTpl,
+		timeTpl,
+		timeOptionalTpl,
+		bytesTpl,
+		bytesOptionalTpl,
+		fixedTpl,
+		fixedOptionalTpl,
+		intervalTpl,
+		intervalOptionalTpl,
+		nullOptionalTpl,
+		bigintOptionalTpl,
 		newFieldTpl,
 		requiredStatsTpl,
 		optionalStatsTpl,
@@ -64,6 +77,16 @@ func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
 		boolOptionalStatsTpl,
 		stringStatsTpl,
 		stringOptionalStatsTpl,
+		timeStatsTpl,
+		timeOptionalStatsTpl,
+		bytesStatsTpl,
+		bytesOptionalStatsTpl,
+		fixedStatsTpl,
+		fixedOptionalStatsTpl,
+		intervalStatsTpl,
+		intervalOptionalStatsTpl,
+		nullOptionalStatsTpl,
+		bigintOptionalStatsTpl,
 	} {
 		var err error
 		tmpl, err = tmpl.Parse(t)
@@ -83,6 +106,13 @@ func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
 		return fmt.Errorf("err: %s, gocode: %s", err, string(buf.Bytes()))
 	}
 
+	if prefix != "" {
+		gocode, err = addPrefix(gocode, prefix)
+		if err != nil {
+			return err
+		}
+	}
+
 	f, err := os.Create(outPth)
 	if err != nil {
 		return err
@@ -98,7 +128,7 @@ func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
 
 // FromParquet generates a go struct, a reader, and a writer based
 // on the parquet file at 'parq'
-func FromParquet(parq, pth, outPth, typ, pkg, imp string, ignore bool) error {
+func FromParquet(parq, pth, outPth, typ, pkg, imp, prefix string, ignore bool) error {
 	pf, err := os.Open(parq)
 	if err != nil {
 		return err
@@ -144,7 +174,7 @@ func FromParquet(parq, pth, outPth, typ, pkg, imp string, ignore bool) error {
 	}
 
 	f.Close()
-	return FromStruct(pth, outPth, typ, pkg, imp, ignore)
+	return FromStruct(pth, outPth, typ, pkg, imp, prefix, ignore)
 }
 
 type input struct {
@@ -189,6 +219,48 @@ func dedupe(flds []fields.Field) []fields.Field {
 	return out
 }
 
+// marshalers returns a field for each of the types that are converted
+// by their MarshalParquet and UnmarshalParquet methods (see
+// fields.Field.Marshaler) so each of them gets one UnmarshalFunc
+// and one check that it has the methods of its parquet interfaces.
+func marshalers(flds []fields.Field) []fields.Field {
+	seen := map[string]bool{}
+	var out []fields.Field
+	for _, f := range flds {
+		if f.Marshaler && !seen[f.Named] {
+			out = append(out, f)
+			seen[f.Named] = true
+		}
+	}
+	return out
+}
+
+// dedupeStats is like dedupe but for the stats types.  The numeric
+// fields share a stats type when they have the same go type (like an
+// Int64Field and an Int64Decimal18_2Field), the string fields all
+// share one (like a StringField and an EnumField) and so do the []byte
+// fields (like a BytesField and a BSONField).
+func dedupeStats(flds []fields.Field) []fields.Field {
+	seen := map[string]bool{}
+	out := make([]fields.Field, 0, len(flds))
+	for _, f := range flds {
+		k := f.FieldType()
+		switch c := f.Category(); {
+		case strings.HasPrefix(c, "numeric"):
+			k = c + strings.TrimPrefix(f.TypeName(), "*")
+		case strings.HasPrefix(c, "string"), strings.HasPrefix(c, "bytes"):
+			k = c
+		}
+
+		if !seen[k] {
+			out = append(out, f)
+			seen[k] = true
+		}
+	}
+
+	return out
+}
+
 func getImport(i string) string {
 	if i == "" {
 		return ""
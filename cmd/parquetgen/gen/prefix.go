@@ -0,0 +1,84 @@
+package gen
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// addPrefix renames everything that is declared at the top level of the
+// generated code so that the code for more than one struct can live in
+// the same package.  Exported names are prefixed as is (ParquetReader
+// becomes PersonParquetReader) and unexported names stay unexported
+// (buffpool becomes personBuffpool).  Constructors keep New at the
+// front (NewParquetWriter becomes NewPersonParquetWriter).  Methods and
+// struct fields aren't renamed.
+func addPrefix(src []byte, prefix string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	// The keys of a struct literal are resolved like any other
+	// identifier, so a field that has the same name as a top level
+	// declaration (like the compression field of ParquetWriter)
+	// must be skipped.
+	keys := map[*ast.Ident]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		cl, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		if _, ok := cl.Type.(*ast.MapType); ok {
+			return true
+		}
+
+		for _, e := range cl.Elts {
+			if kv, ok := e.(*ast.KeyValueExpr); ok {
+				if id, ok := kv.Key.(*ast.Ident); ok {
+					keys[id] = true
+				}
+			}
+		}
+		return true
+	})
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || id.Obj == nil || keys[id] || file.Scope.Objects[id.Name] != id.Obj {
+			return true
+		}
+
+		id.Name = prefixed(prefix, id.Name)
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func prefixed(prefix, name string) string {
+	for _, n := range []string{"New", "new"} {
+		if strings.HasPrefix(name, n) && len(name) > len(n) && ast.IsExported(name[len(n):]) {
+			return n + prefix + name[len(n):]
+		}
+	}
+
+	if ast.IsExported(name) {
+		return prefix + name
+	}
+
+	r, n := utf8.DecodeRuneInString(prefix)
+	s, m := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + prefix[n:] + string(unicode.ToUpper(s)) + name[m:]
+}
@@ -1,17 +1,23 @@
 package gen
 
-var newFieldTpl = `{{define "newField"}}New{{.FieldType}}({{readFuncName .}}, {{writeFuncName .}}, []string{ {{.Path}} }{{if not .Required}}, []int{ {{joinTypes .RepetitionTypes}} }{{end}}, {{compressionFunc .}}(compression)),{{end}}`
+var newFieldTpl = `{{define "newField"}}{{$map := isMapField .}}New{{.FieldType}}({{readFuncName .}}, {{writeFuncName .}}{{if $map}}({{mapKeys .}}){{end}}, []string{ {{.Path}} }{{if not .Required}}, []int{ {{joinTypes .RepetitionTypes}} }{{end}}, {{compressionFunc .}}(compression, level){{if $map}}, parquet.OptionalFieldGroup(0, parquet.MapGroup){{end}}),{{end}}`
 
 var tpl = `package {{.Package}}
 
 // Code generated by github.com/parsyl/parquet.  DO NOT EDIT.
 
 import (
+	"context"
+	"encoding/csv"
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"encoding/binary"
-	"math"
+	"math"{{if bigInts .Parent.Fields}}
+	"math/big"{{end}}
+	"time"{{if maps .Parent}}
+	"sort"{{end}}
 
 	"github.com/valyala/bytebufferpool"
 	"github.com/parsyl/parquet"
@@ -27,11 +33,16 @@ const (
 	compressionUncompressed compression = 0
 	compressionSnappy       compression = 1
 	compressionGzip         compression = 2
+	compressionZstd         compression = 3
+	compressionLz4Raw       compression = 4
 	compressionUnknown      compression = -1
 )
 
 var buffpool = bytebufferpool.Pool{}
-
+{{range anonymous .Parent}}
+// {{.Type}} is the type of the anonymous struct field {{.Name}}.
+type {{.Type}} = {{.Anonymous}}
+{{end}}
 // ParquetWriter reprents a row group
 type ParquetWriter struct {
 	fields []Field
@@ -48,10 +59,78 @@ type ParquetWriter struct {
 	meta *parquet.Metadata
 	w    io.Writer
 	compression compression
+	// level is the compression level (only used by zstd)
+	level int
+
+	// maxDictionarySize is the largest dictionary a column chunk
+	// can have before it is plain encoded (0 turns dictionaries off)
+	maxDictionarySize int
+
+	// deltaBinaryPacked is true if integer columns are
+	// written with DELTA_BINARY_PACKED encoding
+	deltaBinaryPacked bool
+
+	// byteArrayEncoding is the encoding of string and []byte columns
+	// (DELTA_LENGTH_BYTE_ARRAY, DELTA_BYTE_ARRAY or PLAIN, which means
+	// they are dictionary encoded when they can be)
+	byteArrayEncoding sch.Encoding
+
+	// dataPageV2 is true if pages are written as
+	// DATA_PAGE_V2 instead of DATA_PAGE
+	dataPageV2 bool
+
+	// bloomFilters holds the columns that get a bloom
+	// filter in each row group
+	bloomFilters map[string]bool
+
+	// columnOrder holds the columns (see ColumnOrder) whose column
+	// chunks are written first in each row group
+	columnOrder []string
+
+	// failFast is true if WriteAll stops at the
+	// first row that can't be written (see FailFast)
+	failFast bool
+
+	// pageIndex is true if the footer has a
+	// column index and offset index for each
+	// column chunk
+	pageIndex bool
+
+	// pageChecksums is true if each page header
+	// has the CRC32 of its page's data
+	pageChecksums bool
+
+	// statsTruncateLength is the longest that the min and
+	// max of a string or []byte column can be (0 is no limit)
+	statsTruncateLength int
+
+	// sortingColumns are the columns (see SortedBy)
+	// that the rows of each row group are sorted by
+	sortingColumns []sortingColumn
+
+	// keyValueMetadata is written in the
+	// footer (see SetMetadata)
+	keyValueMetadata map[string]string
+
+	// createdBy is written in the footer (see SetCreatedBy)
+	createdBy string
+
+	// maxRowGroupRows is the number of rows Add adds to a row
+	// group before writing it (0 means only Write writes it)
+	maxRowGroupRows int
+	rowGroupRows    int
+
+	// ctx (see WriteContext) is checked before
+	// each row group and the footer are written
+	ctx context.Context
+
+	// err is an error from a row group written by Add
+	err error
 }
 
-func Fields(compression compression) []Field {
-	return []Field{ {{range .Parent.Fields}}
+func Fields(compression compression, level int) []Field {
+	{{range maps .Parent}}{{mapKeys .}} := new([]{{mapKeyType .}})
+	{{end}}return []Field{ {{range .Parent.Fields}}
 		{{template "newField" .}}{{end}}
 	}
 }
@@ -62,7 +141,7 @@ func Fields(compression compression) []Field {
 
 {{end}}
 
-func fieldCompression(c compression) func(*parquet.RequiredField) {
+func fieldCompression(c compression, level int) func(*parquet.RequiredField) {
 	switch c {
 	case compressionUncompressed:
 		return parquet.RequiredFieldUncompressed
@@ -70,12 +149,16 @@ func fieldCompression(c compression) func(*parquet.RequiredField) {
 		return parquet.RequiredFieldSnappy
 	case compressionGzip:
 		return parquet.RequiredFieldGzip
+	case compressionZstd:
+		return parquet.RequiredFieldZstdLevel(level)
+	case compressionLz4Raw:
+		return parquet.RequiredFieldLz4Raw
 	default:
 		return parquet.RequiredFieldUncompressed
 	}
 }
 
-func optionalFieldCompression(c compression) func(*parquet.OptionalField) {
+func optionalFieldCompression(c compression, level int) func(*parquet.OptionalField) {
 	switch c {
 	case compressionUncompressed:
 		return parquet.OptionalFieldUncompressed
@@ -83,20 +166,28 @@ func optionalFieldCompression(c compression) func(*parquet.OptionalField) {
 		return parquet.OptionalFieldSnappy
 	case compressionGzip:
 		return parquet.OptionalFieldGzip
+	case compressionZstd:
+		return parquet.OptionalFieldZstdLevel(level)
+	case compressionLz4Raw:
+		return parquet.OptionalFieldLz4Raw
 	default:
 		return parquet.OptionalFieldUncompressed
 	}
 }
 
+// NewParquetWriter returns a writer of a parquet file to w, which can be
+// any io.Writer (it's never asked to Seek because the writer counts the
+// bytes written to it to know where each column chunk starts).
 func NewParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
-	return newParquetWriter(w, append(opts, begin)...)
+	return newParquetWriter(parquet.NewCountingWriter(w, 0), append(opts, begin)...)
 }
 
 func newParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
 	p := &ParquetWriter{
-		max:         1000,
-		w:           w,
-		compression: compressionSnappy,
+		max:               1000,
+		w:                 w,
+		compression:       compressionSnappy,
+		maxDictionarySize: parquet.DefaultMaxDictionarySize,
 	}
 
 	for _, opt := range opts {
@@ -105,19 +196,60 @@ func newParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*Parquet
 		}
 	}
 
-	p.fields = Fields(p.compression)
+	p.fields = Fields(p.compression, p.level)
 	if p.meta == nil {
-		ff := Fields(p.compression)
-		schema := make([]parquet.Field, len(ff))
-		for i, f := range ff {
-			schema[i] = f.Schema()
+		if err := p.newMeta(); err != nil {
+			return nil, err
 		}
-		p.meta = parquet.New(schema...)
 	}
 
 	return p, nil
 }
 
+// newMeta starts the metadata of a new file.
+func (p *ParquetWriter) newMeta() error {
+	ff := Fields(p.compression, p.level)
+	schema := make([]parquet.Field, len(ff))
+	for i, f := range ff {
+		schema[i] = f.Schema()
+	}
+	p.meta = parquet.New(schema...)
+
+	if p.pageIndex {
+		p.meta.SetPageIndex()
+	}
+
+	if p.pageChecksums {
+		p.meta.SetPageChecksums()
+	}
+
+	if p.statsTruncateLength > 0 {
+		p.meta.SetStatsTruncateLength(p.statsTruncateLength)
+	}
+
+	for _, sc := range p.sortingColumns {
+		if err := p.meta.SortedBy(sc.column, sc.descending); err != nil {
+			return err
+		}
+	}
+
+	p.meta.SetKeyValueMetadata(p.keyValueMetadata)
+	p.meta.SetCreatedBy(p.createdBy)
+	return nil
+}
+
+// Schema returns the schema that ParquetWriter writes (which can
+// be printed to compare it with the schema another tool expects).
+// The first element is the root of the schema.
+func Schema() []*sch.SchemaElement {
+	ff := Fields(compressionSnappy, 0)
+	schema := make([]parquet.Field, len(ff))
+	for i, f := range ff {
+		schema[i] = f.Schema()
+	}
+	return parquet.New(schema...).Schema()
+}
+
 // MaxPageSize is the maximum number of rows in each row groups' page.
 func MaxPageSize(m int) func(*ParquetWriter) error {
 	return func(p *ParquetWriter) error {
@@ -126,6 +258,212 @@ func MaxPageSize(m int) func(*ParquetWriter) error {
 	}
 }
 
+// MaxDictionarySize is the largest (in bytes) the dictionary of a string
+// or []byte column chunk can be before the column chunk is written with
+// plain encoding instead.  0 turns off dictionary encoding.
+func MaxDictionarySize(n int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if n < 0 {
+			return fmt.Errorf("invalid max dictionary size %d, it must not be negative", n)
+		}
+		p.maxDictionarySize = n
+		return nil
+	}
+}
+
+// DeltaBinaryPacked writes the integer columns (including timestamps and
+// dates) with DELTA_BINARY_PACKED encoding instead of plain encoding, which
+// is much smaller for sorted or slowly changing values.
+func DeltaBinaryPacked(p *ParquetWriter) error {
+	p.deltaBinaryPacked = true
+	return nil
+}
+
+// DeltaLengthByteArray writes the string and []byte columns with
+// DELTA_LENGTH_BYTE_ARRAY encoding (all of the lengths followed by all
+// of the values) instead of dictionary encoding.
+func DeltaLengthByteArray(p *ParquetWriter) error {
+	p.byteArrayEncoding = sch.Encoding_DELTA_LENGTH_BYTE_ARRAY
+	return nil
+}
+
+// DeltaByteArray writes the string and []byte columns with DELTA_BYTE_ARRAY
+// encoding instead of dictionary encoding.  Each value only stores what
+// comes after the prefix it shares with the value before it, which is much
+// smaller for sorted values or values with common prefixes (like URLs).
+func DeltaByteArray(p *ParquetWriter) error {
+	p.byteArrayEncoding = sch.Encoding_DELTA_BYTE_ARRAY
+	return nil
+}
+
+// DataPageV2 writes DATA_PAGE_V2 pages instead of DATA_PAGE pages.  The
+// definition and repetition levels of a v2 page aren't compressed, only
+// its values are.
+func DataPageV2(p *ParquetWriter) error {
+	p.dataPageV2 = true
+	return nil
+}
+
+// BloomFilter writes a split block bloom filter for each of the columns
+// (given by their names or the names of their fields) in each row group,
+// which ParquetReader.MayContain uses.  Only string and []byte columns
+// can have bloom filters.
+func BloomFilter(cols ...string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		ff := getFields(Fields(compressionUnknown, 0))
+		for _, col := range cols {
+			if c, ok := columnNames[col]; ok {
+				col = c
+			}
+
+			f, ok := ff[col]
+			if !ok {
+				return fmt.Errorf("unknown column: %s", col)
+			}
+
+			if _, ok := f.(bloomField); !ok {
+				return fmt.Errorf("column %s can't have a bloom filter, only string and []byte columns can", col)
+			}
+
+			if p.bloomFilters == nil {
+				p.bloomFilters = map[string]bool{}
+			}
+			p.bloomFilters[col] = true
+		}
+		return nil
+	}
+}
+
+// ColumnOrder writes the column chunks of the columns (given by their
+// names or the names of their fields) first in each row group, in the
+// order they are given, so a reader that only reads those columns has
+// less to seek past.  The other column chunks are written after them in
+// the order of the schema.  The footer lists the column chunks in the
+// order of the schema no matter where they are in the file.
+func ColumnOrder(cols ...string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		ff := getFields(Fields(compressionUnknown, 0))
+		for _, col := range cols {
+			if c, ok := columnNames[col]; ok {
+				col = c
+			}
+
+			if _, ok := ff[col]; !ok {
+				return fmt.Errorf("unknown column: %s", col)
+			}
+			p.columnOrder = append(p.columnOrder, col)
+		}
+		return nil
+	}
+}
+
+// FailFast makes WriteAll return the error of the first row that can't be
+// written without adding any of the rows, instead of writing the rest.
+func FailFast(p *ParquetWriter) error {
+	p.failFast = true
+	return nil
+}
+
+// PageIndex writes a column index (the min, max and null count of each
+// page) and an offset index (where each page starts) for each column chunk
+// before the footer.  ParquetReader uses them to skip the pages that can't
+// match its filters (see Filter).
+func PageIndex(p *ParquetWriter) error {
+	p.pageIndex = true
+	return nil
+}
+
+// StatsTruncateLength truncates the min and max statistics of string and
+// []byte columns that are longer than n bytes so that very long values
+// don't bloat the footer (see parquet.Metadata.SetStatsTruncateLength).
+// The statistics say that a truncated min or max isn't exact.
+func StatsTruncateLength(n int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if n < 0 {
+			return fmt.Errorf("invalid stats truncate length %d, it must not be negative", n)
+		}
+		p.statsTruncateLength = n
+		return nil
+	}
+}
+
+// PageChecksums writes the CRC32 of each page's (compressed) data in its
+// header so that readers can tell when a page is corrupt (see
+// VerifyChecksums).
+func PageChecksums(p *ParquetWriter) error {
+	p.pageChecksums = true
+	return nil
+}
+
+type sortingColumn struct {
+	column     string
+	descending bool
+}
+
+// SortedBy records in the metadata of each row group that its rows are
+// sorted by a column (given by its name or by its field's name), which can
+// be used more than once for rows that are sorted by more than one column.
+// The writer doesn't sort the rows, they must be added in order (see Less).
+func SortedBy(column string, descending bool) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if col, ok := columnNames[column]; ok {
+			column = col
+		}
+
+		if _, err := Less(column, descending); err != nil {
+			return err
+		}
+
+		p.sortingColumns = append(p.sortingColumns, sortingColumn{column: column, descending: descending})
+		return nil
+	}
+}
+
+// SetMetadata writes kv as the key/value metadata in the file's footer,
+// for things about the file (like what wrote it) that other programs can
+// read (see ParquetReader.Metadata).  It isn't part of the schema.
+func SetMetadata(kv map[string]string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.keyValueMetadata = make(map[string]string, len(kv))
+		for k, v := range kv {
+			p.keyValueMetadata[k] = v
+		}
+		return nil
+	}
+}
+
+// SetCreatedBy writes s (like "myapp version 1.2.3") as the application
+// that wrote the file in its footer instead of parquet.DefaultCreatedBy
+// (see ParquetReader.CreatedBy).
+func SetCreatedBy(s string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.createdBy = s
+		return nil
+	}
+}
+
+// MaxRowGroupRows is the maximum number of rows in each row group.  Add
+// writes the row group (just like calling Write) once it has m rows.  0
+// means there is no maximum, so row groups are only written by Write.
+func MaxRowGroupRows(m int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if m < 0 {
+			return fmt.Errorf("invalid max row group rows %d, it must not be negative", m)
+		}
+		p.maxRowGroupRows = m
+		return nil
+	}
+}
+
+// WriteContext makes Write (and Add, when it writes a row group) and Close
+// return ctx's error instead of writing anything once ctx is done.
+func WriteContext(ctx context.Context) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.ctx = ctx
+		return nil
+	}
+}
+
 var par1 = []byte("PAR1")
 
 func begin(p *ParquetWriter) error {
@@ -155,29 +493,98 @@ func Gzip(p *ParquetWriter) error {
 	return nil
 }
 
-func withCompression(c compression) func(*ParquetWriter) error {
+func Zstd(p *ParquetWriter) error {
+	p.compression = compressionZstd
+	return nil
+}
+
+// Lz4Raw compresses with LZ4_RAW (lz4 blocks without a frame),
+// which is what hadoop 3 and newer versions of spark use.
+func Lz4Raw(p *ParquetWriter) error {
+	p.compression = compressionLz4Raw
+	return nil
+}
+
+// ZstdLevel compresses with zstd at the given level
+// (1 is the fastest and 22 the smallest).
+func ZstdLevel(level int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if level < 1 || level > 22 {
+			return fmt.Errorf("invalid zstd level %d, it must be between 1 and 22", level)
+		}
+		p.compression = compressionZstd
+		p.level = level
+		return nil
+	}
+}
+
+func withCompression(c compression, level int) func(*ParquetWriter) error {
 	return func(p *ParquetWriter) error {
 		p.compression = c
+		p.level = level
 		return nil
 	}
 }
 
+// Write writes the rows that have been added since the last call
+// to Write as a row group.
 func (p *ParquetWriter) Write() error {
-	for i, f := range p.fields {
-		if err := f.Write(p.w, p.meta); err != nil {
+	if p.err != nil {
+		return p.err
+	}
+
+	if err := p.ctxErr(); err != nil {
+		return err
+	}
+
+	// an empty row group isn't written
+	if p.len == 0 {
+		return nil
+	}
+
+	var blooms [][]Field
+	for _, i := range p.writeOrder() {
+		f := p.fields[i]
+		fields := []Field{f}
+		for child := p.child; child != nil; child = child.child {
+			fields = append(fields, child.fields[i])
+		}
+
+		if p.bloomFilters[f.Name()] {
+			blooms = append(blooms, fields)
+		}
+
+		if err := p.writeDictionary(fields); err != nil {
 			return err
 		}
 
-		for child := p.child; child != nil; child = child.child {
-			if err := child.fields[i].Write(p.w, p.meta); err != nil {
+		delta, byteArray := p.encodings(f.Name())
+		for _, f := range fields {
+			if ef, ok := f.(encodingField); ok {
+				if delta {
+					ef.SetDeltaBinaryPacked()
+				}
+				ef.SetByteArrayEncoding(byteArray)
+			}
+
+			if pf, ok := f.(dataPageV2Field); ok && p.dataPageV2 {
+				pf.SetDataPageV2()
+			}
+
+			if err := f.Write(p.w, p.meta); err != nil {
 				return err
 			}
 		}
 	}
 
-	p.fields = Fields(p.compression)
+	if err := p.writeBloomFilters(blooms); err != nil {
+		return err
+	}
+
+	p.fields = Fields(p.compression, p.level)
 	p.child = nil
 	p.len = 0
+	p.rowGroupRows = 0
 
 	schema := make([]parquet.Field, len(p.fields))
 	for i, f := range p.fields {
@@ -187,195 +594,1212 @@ func (p *ParquetWriter) Write() error {
 	return nil
 }
 
-func (p *ParquetWriter) Close() error {
-	if err := p.meta.Footer(p.w); err != nil {
-		return err
+// writeOrder returns the indices of p.fields in the order
+// that their column chunks are written (see ColumnOrder).
+func (p *ParquetWriter) writeOrder() []int {
+	out := make([]int, 0, len(p.fields))
+	first := map[string]bool{}
+	for _, col := range p.columnOrder {
+		for i, f := range p.fields {
+			if f.Name() == col && !first[col] {
+				out = append(out, i)
+				first[col] = true
+			}
+		}
 	}
 
-	_, err := p.w.Write(par1)
-	return err
+	for i, f := range p.fields {
+		if !first[f.Name()] {
+			out = append(out, i)
+		}
+	}
+	return out
 }
 
-func (p *ParquetWriter) Add(rec {{.Parent.StructType}}) {
-	if p.len == p.max {
-		if p.child == nil {
-			// an error can't happen here
-			p.child, _ = newParquetWriter(p.w, MaxPageSize(p.max), withMeta(p.meta), withCompression(p.compression))
+func (p *ParquetWriter) ctxErr() error {
+	if p.ctx == nil {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
+// bloomField is a field that can have a bloom filter.
+type bloomField interface {
+	Path() []string
+	bloomHashes(hashes []uint64) []uint64
+}
+
+// writeBloomFilters writes the bloom filter of each column chunk (the
+// fields of each chunk are its pages) after the row group's column chunks.
+func (p *ParquetWriter) writeBloomFilters(chunks [][]Field) error {
+	for _, fields := range chunks {
+		var hashes []uint64
+		for _, f := range fields {
+			hashes = f.(bloomField).bloomHashes(hashes)
 		}
 
-		p.child.Add(rec)
-		return
+		pth := fields[0].(bloomField).Path()
+		if err := p.meta.WriteBloomFilter(p.w, pth, parquet.NewBloomFilter(hashes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flusher is an io.Writer (like a bufio.Writer) that buffers
+// what is written to it until it is flushed.
+type flusher interface {
+	Flush() error
+}
+
+// Flush writes the rows that have been added since the last call to Write
+// (or Flush) as a row group and then flushes the underlying io.Writer if it
+// has a Flush method.  Everything up to the end of the row group has been
+// written to the io.Writer when Flush returns, but the file can't be read
+// until Close writes the footer.
+func (p *ParquetWriter) Flush() error {
+	if err := p.Write(); err != nil {
+		return err
 	}
 
-	p.meta.NextDoc()
-	for _, f := range p.fields {
-		f.Add(rec)
+	if f, ok := p.w.(flusher); ok {
+		return f.Flush()
 	}
+	return nil
+}
 
-	p.len++
+type dictionaryField interface {
+	addToDictionary(d *parquet.Dictionary) bool
+	SetDictionary(d *parquet.Dictionary)
+	WriteDictionary(w io.Writer, meta *parquet.Metadata) error
 }
 
-type Field interface {
-	Add(r {{.Parent.StructType}})
-	Write(w io.Writer, meta *parquet.Metadata) error
-	Schema() parquet.Field
-	Scan(r *{{.Parent.StructType}})
-	Read(r io.ReadSeeker, pg parquet.Page) error
-	Name() string
-	Levels() ([]uint8, []uint8)
+// encodingField is a field that can be written with something other than
+// plain (or dictionary) encoding.  Each encoding is only used if it works
+// with the field's column type.
+type encodingField interface {
+	SetDeltaBinaryPacked()
+	SetByteArrayEncoding(enc sch.Encoding)
 }
 
-func getFields(ff []Field) map[string]Field {
-	m := make(map[string]Field, len(ff))
-	for _, f := range ff {
-		m[f.Name()] = f
+type dataPageV2Field interface {
+	SetDataPageV2()
+}
+
+// encodings returns whether the column is DELTA_BINARY_PACKED and the
+// encoding of its byte arrays (see encodingField), which come from the
+// column's struct tag if it has an encoding and the options if it doesn't.
+func (p *ParquetWriter) encodings(col string) (bool, sch.Encoding) {
+	enc, ok := columnEncodings[col]
+	if !ok {
+		return p.deltaBinaryPacked, p.byteArrayEncoding
 	}
-	return m
+
+	switch enc {
+	case sch.Encoding_DELTA_LENGTH_BYTE_ARRAY, sch.Encoding_DELTA_BYTE_ARRAY:
+		return false, enc
+	}
+	return enc == sch.Encoding_DELTA_BINARY_PACKED, sch.Encoding_PLAIN
 }
 
-func NewParquetReader(r io.ReadSeeker, opts ...func(*ParquetReader)) (*ParquetReader, error) {
-	ff := Fields(compressionUnknown)
-	pr := &ParquetReader{
-		r: r,
+// writeDictionary writes the dictionary page of a column chunk (the
+// fields are the chunk's pages) when the column supports dictionary
+// encoding and the chunk's distinct values fit in the dictionary.
+// Otherwise the column chunk is plain encoded.  A column whose struct
+// tag sets its encoding only gets a dictionary if it's dictionary.
+func (p *ParquetWriter) writeDictionary(fields []Field) error {
+	enc, ok := columnEncodings[fields[0].Name()]
+	if p.maxDictionarySize == 0 || (ok && enc != sch.Encoding_RLE_DICTIONARY) || (!ok && p.byteArrayEncoding != sch.Encoding_PLAIN) {
+		return nil
 	}
 
-	for _, opt := range opts {
-		opt(pr)
+	d := parquet.NewDictionary(p.maxDictionarySize)
+	for _, f := range fields {
+		df, ok := f.(dictionaryField)
+		if !ok || !df.addToDictionary(d) {
+			return nil
+		}
 	}
 
-	schema := make([]parquet.Field, len(ff))
-	for i, f := range ff {
-		pr.fieldNames = append(pr.fieldNames, f.Name())
-		schema[i] = f.Schema()
+	if d.Len() == 0 {
+		return nil
 	}
 
-	meta := parquet.New(schema...)
-	if err := meta.ReadFooter(r); err != nil {
-		return nil, err
+	for _, f := range fields {
+		f.(dictionaryField).SetDictionary(d)
+	}
+	return fields[0].(dictionaryField).WriteDictionary(p.w, p.meta)
+}
+
+func (p *ParquetWriter) Close() error {
+	if p.err != nil {
+		return p.err
+	}
+
+	if err := p.ctxErr(); err != nil {
+		return err
+	}
+
+	if err := p.meta.Footer(p.w); err != nil {
+		return err
+	}
+
+	_, err := p.w.Write(par1)
+	return err
+}
+
+// Reset starts a new file that is written to w with the same options, so
+// one writer can write many files.  The rows that have been added since
+// the last Write, the row groups of the current file and any error from
+// Add are discarded, so Close needs to be called first to finish the
+// current file.
+func (p *ParquetWriter) Reset(w io.Writer) error {
+	p.w = parquet.NewCountingWriter(w, 0)
+	p.fields = Fields(p.compression, p.level)
+	p.child = nil
+	p.len = 0
+	p.rowGroupRows = 0
+	p.err = nil
+
+	if err := p.newMeta(); err != nil {
+		return err
+	}
+	return begin(p)
+}
+
+func (p *ParquetWriter) Add(rec {{.Parent.StructType}}) {
+	p.add(rec)
+
+	if p.maxRowGroupRows == 0 {
+		return
+	}
+
+	p.rowGroupRows++
+	if p.rowGroupRows == p.maxRowGroupRows && p.err == nil {
+		p.err = p.Write()
+	}
+}
+
+// WriteRow adds a row (like Add) for producers that have one row at a
+// time.  It writes the row group once it has MaxRowGroupRows rows and,
+// unlike Add, returns the error from writing it.  The rows that are left
+// over when there are no more still need a Write (or Flush) before Close.
+func (p *ParquetWriter) WriteRow(rec {{.Parent.StructType}}) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.Add(rec)
+	return p.err
+}
+
+// WriteAll adds rows (like Add) and writes them (like Write).  A row that
+// can't be written (like a decimal with more digits than its precision)
+// would make Write fail for all of them, so each one is left out instead
+// and its error is returned in errs at its index in rows (errs is nil if
+// all of the rows were written).  The rows are written to a scratch
+// buffer first to find the ones that can't be written, which is about as
+// much work again as writing them.  With FailFast, none of the rows are
+// added if one of them can't be written and the error of the first one is
+// returned as err.
+func (p *ParquetWriter) WriteAll(rows []{{.Parent.StructType}}) ([]error, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	var errs []error
+	if err := p.checkRows(rows); err != nil {
+		errs = make([]error, len(rows))
+		p.rowErrors(rows, 0, errs)
+	}
+
+	if p.failFast {
+		for i, err := range errs {
+			if err != nil {
+				return errs, fmt.Errorf("row %d: %s", i, err)
+			}
+		}
+	}
+
+	for i, rec := range rows {
+		if errs == nil || errs[i] == nil {
+			p.Add(rec)
+		}
+	}
+	return errs, p.Write()
+}
+
+// rowErrors sets errs[i+j] to the error of each row j that can't be
+// written.  rows are split in half until the rows that can't be written
+// are on their own.
+func (p *ParquetWriter) rowErrors(rows []{{.Parent.StructType}}, i int, errs []error) {
+	err := p.checkRows(rows)
+	if err == nil {
+		return
+	}
+
+	if len(rows) == 1 {
+		errs[i] = err
+		return
+	}
+
+	n := len(rows) / 2
+	p.rowErrors(rows[:n], i, errs)
+	if p.failFast && hasError(errs[i:i+n]) {
+		return
+	}
+	p.rowErrors(rows[n:], i+n, errs)
+}
+
+// checkRows returns the error from writing rows to a scratch buffer.
+func (p *ParquetWriter) checkRows(rows []{{.Parent.StructType}}) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	w, err := newParquetWriter(buf, MaxPageSize(p.max), withCompression(compressionUncompressed, 0))
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range rows {
+		w.add(rec)
+	}
+	return w.Write()
+}
+
+func hasError(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ParquetWriter) add(rec {{.Parent.StructType}}) {
+	if p.len == p.max {
+		if p.child == nil {
+			// an error can't happen here
+			p.child, _ = newParquetWriter(p.w, MaxPageSize(p.max), withMeta(p.meta), withCompression(p.compression, p.level))
+		}
+
+		p.child.add(rec)
+		return
+	}
+
+	p.meta.NextDoc()
+	for _, f := range p.fields {
+		f.Add(rec)
+	}
+
+	p.len++
+}
+
+// RotatingWriter writes rows to a series of parquet files that each have
+// up to a maximum number of rows.  Each file is a complete parquet file
+// (with its own footer) that is written with the writer's options.  The
+// io.Writer of each file comes from a function that is given the index
+// of the file (starting at 0), which is called when the file's first row
+// is added, so there are no empty files.  If the io.Writer is also an
+// io.Closer (like an *os.File) it is closed when its file is finished
+// (even if finishing it fails).  Once a file can't be finished every
+// call to Add and Close returns that error.
+type RotatingWriter struct {
+	pw       *ParquetWriter
+	w        io.Writer
+	next     func(index int) (io.Writer, error)
+	opts     []func(*ParquetWriter) error
+	maxRows  int
+	files    int
+	fileRows int
+	err      error
+}
+
+// NewRotatingWriter returns a RotatingWriter that starts a new file
+// after every maxRows rows.
+func NewRotatingWriter(maxRows int, next func(index int) (io.Writer, error), opts ...func(*ParquetWriter) error) (*RotatingWriter, error) {
+	if maxRows < 1 {
+		return nil, fmt.Errorf("invalid max rows %d, it must be at least 1", maxRows)
+	}
+	return &RotatingWriter{next: next, opts: opts, maxRows: maxRows}, nil
+}
+
+// Add adds a row to the current file, first finishing it and starting
+// the next one if it already has the maximum number of rows.
+func (r *RotatingWriter) Add(rec {{.Parent.StructType}}) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	if r.w != nil && r.fileRows == r.maxRows {
+		if err := r.finish(); err != nil {
+			return err
+		}
+	}
+
+	if r.w == nil {
+		if err := r.start(); err != nil {
+			return err
+		}
+	}
+
+	r.fileRows++
+	return r.pw.WriteRow(rec)
+}
+
+// Files returns the number of files that have been started.
+func (r *RotatingWriter) Files() int {
+	return r.files
+}
+
+// Close finishes the current file.
+func (r *RotatingWriter) Close() error {
+	if r.err != nil || r.w == nil {
+		return r.err
+	}
+	return r.finish()
+}
+
+// start gets the io.Writer of the next file and starts
+// the file (reusing the ParquetWriter of the last file).
+func (r *RotatingWriter) start() error {
+	w, err := r.next(r.files)
+	if err != nil {
+		return err
+	}
+
+	if r.pw == nil {
+		r.pw, err = NewParquetWriter(w, r.opts...)
+	} else {
+		err = r.pw.Reset(w)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.w = w
+	r.files++
+	r.fileRows = 0
+	return nil
+}
+
+// finish writes the rest of the current file's rows and its footer
+// and then closes it.
+func (r *RotatingWriter) finish() (err error) {
+	w := r.w
+	r.w = nil
+	defer func() {
+		if c, ok := w.(io.Closer); ok {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			} else if cerr != nil {
+				err = fmt.Errorf("%s (and then unable to close the file: %s)", err, cerr)
+			}
+		}
+		r.err = err
+	}()
+
+	if err := r.pw.Write(); err != nil {
+		return err
+	}
+	return r.pw.Close()
+}
+
+type Field interface {
+	Add(r {{.Parent.StructType}})
+	Write(w io.Writer, meta *parquet.Metadata) error
+	Schema() parquet.Field
+	Scan(r *{{.Parent.StructType}})
+	Read(r io.ReadSeeker, pg parquet.Page) error
+	Name() string
+	Levels() ([]uint8, []uint8)
+	copyTo(dest interface{}) error
+}
+
+func getFields(ff []Field) map[string]Field {
+	m := make(map[string]Field, len(ff))
+	for _, f := range ff {
+		m[f.Name()] = f
+	}
+	return m
+}
+
+func NewParquetReader(r io.ReadSeeker, opts ...func(*ParquetReader)) (*ParquetReader, error) {
+	ff := Fields(compressionUnknown, 0)
+	pr := &ParquetReader{
+		r: r,
+	}
+
+	for _, opt := range opts {
+		opt(pr)
+	}
+
+	if pr.parallel > 1 {
+		ra, ok := r.(io.ReaderAt)
+		if !ok {
+			return nil, fmt.Errorf("ReadParallel needs a reader that is also an io.ReaderAt, %T isn't", r)
+		}
+		pr.readerAt = ra
+	}
+
+	schema := make([]parquet.Field, len(ff))
+	for i, f := range ff {
+		pr.fieldNames = append(pr.fieldNames, f.Name())
+		schema[i] = f.Schema()
+	}
+
+	meta := parquet.New(schema...)
+	if err := meta.ReadFooter(r); err != nil {
+		return nil, err
+	}
+
+	// the optional fields that the file doesn't
+	// have a column for are left as nil
+	if err := meta.CheckColumns(); err != nil {
+		return nil, err
+	}
+
+	if pr.verifyChecksums {
+		meta.SetPageChecksums()
+	}
+
+	if pr.noCopy {
+		meta.SetNoCopy()
+	}
+
+	pages, err := meta.Pages()
+	if err != nil {
+		return nil, err
+	}
+
+	pr.pages = map[string][]parquet.Page{}
+	for i, rg := range meta.RowGroups() {
+		// the row groups after MaxRows aren't needed
+		if pr.maxRows > 0 && pr.rows >= pr.maxRows {
+			break
+		}
+
+		skip, err := meta.Skip(rg, pr.filters...)
+		if err != nil {
+			return nil, err
+		}
+
+		if skip {
+			continue
+		}
+
+		rgPages := make(map[string]parquet.Page, len(rg.Columns()))
+		for _, col := range rg.Columns() {
+			name := strings.Join(col.MetaData.PathInSchema, ".")
+			rgPages[name] = pages[name][i]
+		}
+
+		rgPages, rg.Rows, err = meta.FilterPages(r, rg, rgPages, pr.filters...)
+		if err != nil {
+			return nil, err
+		}
+
+		// all of the row group's pages were skipped
+		if rg.Rows == 0 {
+			continue
+		}
+
+		for _, col := range rg.Columns() {
+			name := strings.Join(col.MetaData.PathInSchema, ".")
+			pr.pages[name] = append(pr.pages[name], rgPages[name])
+		}
+		pr.rows += rg.Rows
+		pr.rowGroups = append(pr.rowGroups, rg)
+	}
+
+	if pr.maxRows > 0 && pr.rows > pr.maxRows {
+		pr.rows = pr.maxRows
+	}
+
+	_, err = r.Seek(4, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	pr.meta = meta
+
+	if err := pr.ctxErr(); err != nil {
+		return nil, err
+	}
+	return pr, pr.readRowGroup()
+}
+
+// NewParquetReaderAt returns a reader of the size bytes of r (like a file
+// in an object store that is read with range requests).  Only the footer
+// and the column chunks (and page indexes) that are needed are read, each
+// with a single ReadAt.
+func NewParquetReaderAt(r io.ReaderAt, size int64, opts ...func(*ParquetReader)) (*ParquetReader, error) {
+	return NewParquetReader(io.NewSectionReader(r, 0, size), opts...)
+}
+
+func readerIndex(i int) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.index = i
+	}
+}
+
+// Filter makes the reader skip each row group whose statistics show that
+// none of its rows can have a column value that compares to value with op.
+// The column is given by its name (like "hobby.name") or by its field's
+// name (like "Hobby.Name").  The pages of a file written with a page index
+// (see PageIndex) are skipped the same way.  The rows of the row groups (or
+// pages) that aren't skipped are all returned, even the ones that don't
+// match the filter.
+func Filter(column string, op parquet.Operator, value interface{}) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		if col, ok := columnNames[column]; ok {
+			column = col
+		}
+		p.filters = append(p.filters, parquet.Filter{Column: column, Op: op, Value: value})
+	}
+}
+
+// MaxRows makes the reader stop after n rows (which can be in the middle
+// of a row group) without reading the row groups that come after them.
+// Rows returns n if the file has more rows than that.  ReadColumn still
+// reads all of a column's values.  MaxRows(0) doesn't limit the rows.
+func MaxRows(n int64) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.maxRows = n
+	}
+}
+
+// VerifyChecksums makes the reader check the checksum of each page it
+// reads (see PageChecksums) and fail with an error if the page's data
+// doesn't match it.  Pages without a checksum aren't checked.
+func VerifyChecksums(p *ParquetReader) {
+	p.verifyChecksums = true
+}
+
+// NoCopy makes the reader read the values of string and []byte fields as
+// slices of a column chunk's data instead of copying each one, which saves
+// an allocation per value.  The values are only valid until the reader
+// reads the next row group (Next, ReadColumn or ReadRowGroup is called
+// again): they must be copied to keep them any longer, and a []byte value
+// must never be changed because the strings share its memory.
+func NoCopy(p *ParquetReader) {
+	p.noCopy = true
+}
+
+// SkipErrors makes the reader skip each row group that can't be read (like
+// one with a corrupt page) instead of failing.  The errors of the row groups
+// that were skipped are returned by Errors and Rows goes down by the number
+// of rows that each of them had.  ReadColumn and ReadRowGroup still fail.
+func SkipErrors(p *ParquetReader) {
+	p.skipErrors = true
+}
+
+// Where makes Next skip the rows that f returns false for.  Each row is
+// read (and passed to f) before it is skipped, so Filter should be used as
+// well when it can skip whole row groups or pages that can't have any
+// matching rows.  Rows still returns the number of rows before they are
+// skipped.  Where doesn't apply to ReadColumn or ReadRowGroup.
+func Where(f func({{.Parent.StructType}}) bool) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.where = f
+	}
+}
+
+// ReadContext makes the reader stop (Next returns false and Error returns
+// ctx's error) before it reads another row group once ctx is done.
+func ReadContext(ctx context.Context) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.ctx = ctx
+	}
+}
+
+// ReadParallel makes the reader read (and decode) the next n row groups at
+// the same time, each in its own goroutine, when it runs out of rows.  The
+// rows still come back in the order they're in the file, but up to n row
+// groups are kept in memory.  The reader has to also be an io.ReaderAt
+// (like an *os.File or the reader that NewParquetReaderAt uses) so the
+// row groups can each be read from their own offset.
+func ReadParallel(n int) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.parallel = n
+	}
+}
+
+// ParquetReader reads one page from a row group.
+type ParquetReader struct {
+	fields         map[string]Field
+	fieldNames     []string
+	index          int
+	cursor         int64
+	rows           int64
+	maxRows        int64
+	verifyChecksums bool
+	noCopy         bool
+	skipErrors     bool
+	errs           []error
+	rowGroupCursor int64
+	rowGroupCount  int64
+	pages          map[string][]parquet.Page
+	meta           *parquet.Metadata
+	filters        []parquet.Filter
+	ctx            context.Context
+	err            error
+
+	// parallel (see ReadParallel) is the number of row groups that
+	// are read at a time and decoded holds the ones that have been
+	// read but not scanned yet
+	parallel int
+	readerAt io.ReaderAt
+	decoded  []map[string]Field
+
+	// where (see Where) is checked by Next, which
+	// keeps the matching row in row for Scan
+	where func({{.Parent.StructType}}) bool
+	row   *{{.Parent.StructType}}
+
+	r         io.ReadSeeker
+	rowGroups []parquet.RowGroup
+}
+
+type Levels struct {
+	Name string
+	Defs []uint8
+	Reps []uint8
+}
+
+func (p *ParquetReader) Levels() []Levels {
+	var out []Levels
+	//for {
+	for _, name := range p.fieldNames {
+		f := p.fields[name]
+		d, r := f.Levels()
+		out = append(out, Levels{Name: f.Name(), Defs: d, Reps: r})
+	}
+	//	if err := p.readRowGroup(); err != nil {
+	//		break
+	//	}
+	//}
+	return out
+}
+
+func (p *ParquetReader) Error() error {
+	return p.err
+}
+
+// Errors returns the errors of the row groups that were skipped (see
+// SkipErrors).
+func (p *ParquetReader) Errors() []error {
+	return p.errs
+}
+
+// skip records err, the error of the row group rg, if SkipErrors
+// was used, in which case rg's rows are no longer counted.
+func (p *ParquetReader) skip(rg parquet.RowGroup, err error) bool {
+	if !p.skipErrors {
+		return false
+	}
+	p.errs = append(p.errs, err)
+	p.rows -= rg.Rows
+	return true
+}
+
+func (p *ParquetReader) ctxErr() error {
+	if p.ctx == nil {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
+func (p *ParquetReader) readRowGroup() error {
+	for {
+		p.rowGroupCursor = 0
+
+		if len(p.rowGroups) == 0 {
+			p.rowGroupCount = 0
+			return nil
+		}
+
+		if p.parallel > 1 {
+			return p.readRowGroups()
+		}
+
+		rg := p.rowGroups[0]
+		fields, err := p.readColumns(rg)
+		for name, pages := range p.pages {
+			if len(pages) > 0 {
+				p.pages[name] = pages[1:]
+			}
+		}
+		p.rowGroups = p.rowGroups[1:]
+
+		if err != nil {
+			if p.skip(rg, err) {
+				continue
+			}
+			return err
+		}
+
+		p.fields = fields
+		p.rowGroupCount = rg.Rows
+		return nil
+	}
+}
+
+// readColumns reads the columns of rg, which is the next row group.
+func (p *ParquetReader) readColumns(rg parquet.RowGroup) (map[string]Field, error) {
+	fields := getFields(Fields(compressionUnknown, 0))
+	for _, col := range rg.Columns() {
+		name := strings.Join(col.MetaData.PathInSchema, ".")
+		f, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+		pages := p.pages[name]
+		if len(pages) <= p.index {
+			break
+		}
+
+		pg := pages[0]
+		if _, err := p.r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		if err := f.Read(p.r, pg); err != nil {
+			return nil, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		}
+	}
+	return fields, nil
+}
+
+// readRowGroups is readRowGroup for ReadParallel.  When the row groups
+// that were read last time have all been scanned, the next ones are read
+// at the same time and kept in the order they're in the file.
+func (p *ParquetReader) readRowGroups() error {
+	for len(p.decoded) == 0 {
+		if len(p.rowGroups) == 0 {
+			p.rowGroupCount = 0
+			return nil
+		}
+
+		n := p.parallel
+		if n > len(p.rowGroups) {
+			n = len(p.rowGroups)
+		}
+
+		decoded := make([]map[string]Field, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i, rg := range p.rowGroups[:n] {
+			pages := make(map[string]parquet.Page, len(rg.Columns()))
+			for _, col := range rg.Columns() {
+				name := strings.Join(col.MetaData.PathInSchema, ".")
+				pages[name] = p.pages[name][i]
+			}
+
+			wg.Add(1)
+			go func(i int, rg parquet.RowGroup, pages map[string]parquet.Page) {
+				defer wg.Done()
+				decoded[i], errs[i] = p.readRowGroupAt(rg, pages)
+			}(i, rg, pages)
+		}
+		wg.Wait()
+
+		// the row groups that were skipped are left out of
+		// both decoded and rowGroups so that they stay in step
+		var rowGroups []parquet.RowGroup
+		for i, err := range errs {
+			if err == nil {
+				p.decoded = append(p.decoded, decoded[i])
+				rowGroups = append(rowGroups, p.rowGroups[i])
+				continue
+			}
+			if !p.skip(p.rowGroups[i], err) {
+				return err
+			}
+		}
+
+		for name := range p.pages {
+			p.pages[name] = p.pages[name][n:]
+		}
+		p.rowGroups = append(rowGroups, p.rowGroups[n:]...)
+	}
+
+	p.fields = p.decoded[0]
+	p.decoded = p.decoded[1:]
+	p.rowGroupCount = p.rowGroups[0].Rows
+	p.rowGroups = p.rowGroups[1:]
+	return nil
+}
+
+// readRowGroupAt reads the columns of a row group with a reader of its own
+// (so it doesn't share a position with the other row groups being read).
+func (p *ParquetReader) readRowGroupAt(rg parquet.RowGroup, pages map[string]parquet.Page) (map[string]Field, error) {
+	r := io.NewSectionReader(p.readerAt, 0, math.MaxInt64)
+	fields := getFields(Fields(compressionUnknown, 0))
+	for _, col := range rg.Columns() {
+		name := strings.Join(col.MetaData.PathInSchema, ".")
+		f, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+
+		pg := pages[name]
+		if _, err := r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		if err := f.Read(r, pg); err != nil {
+			return nil, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		}
+	}
+	return fields, nil
+}
+
+func (p *ParquetReader) Rows() int64 {
+	return p.rows
+}
+
+// Next advances the reader to the next row, which can then be read with
+// Scan.  It returns false when there are no more rows or there was an error,
+// which can be checked with Error.  Only one row group is kept in memory at a
+// time: the next one is read when all of the current row group's rows have
+// been scanned.  Rows that don't match Where are skipped.
+func (p *ParquetReader) Next() bool {
+	for p.next() {
+		if p.where == nil {
+			return true
+		}
+
+		// the row has to be read to check it, so
+		// Scan copies the row that was read here
+		var x {{.Parent.StructType}}
+		p.scan(&x)
+		if p.where(x) {
+			p.row = &x
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ParquetReader) next() bool {
+	if p.err == nil && p.cursor >= p.rows {
+		return false
+	}
+	if p.rowGroupCursor >= p.rowGroupCount {
+		if p.err = p.ctxErr(); p.err != nil {
+			return false
+		}
+
+		p.err = p.readRowGroup()
+		if p.err != nil {
+			return false
+		}
+
+		// the rest of the row groups could have been skipped
+		if p.cursor >= p.rows {
+			return false
+		}
+	}
+
+	p.cursor++
+	p.rowGroupCursor++
+	return true
+}
+
+// columnNames maps the name of each field (like "Hobby.Name")
+// to the name of its column (like "hobby.name").
+var columnNames = map[string]string{ {{range .Parent.Fields}}
+	"{{funcPath .}}": "{{columnName .}}",{{end}}
+}
+
+// columnEncodings are the encodings that were set for
+// columns by their fields' struct tags.
+var columnEncodings = map[string]sch.Encoding{ {{range .Parent.Fields}}{{if .Encoding}}
+	"{{columnName .}}": sch.Encoding_{{.EncodingName}},{{end}}{{end}}
+}
+
+// lessFuncs compares two rows by each column that isn't repeated.
+var lessFuncs = map[string]func(a, b {{.Parent.StructType}}) bool{ {{range .Parent.Fields}}{{if not .Repeated}}
+	"{{columnName .}}": {{lessFunc .}},{{end}}{{end}}
+}
+
+// Less returns a function that reports whether row a comes before row b
+// when the rows are sorted by a column (given by its name or by its field's
+// name), which can be used with sort.Slice to sort the rows before they are
+// added (see SortedBy).  Nulls come first, or last when descending.
+// Repeated columns can't be sorted by.
+func Less(column string, descending bool) (func(a, b {{.Parent.StructType}}) bool, error) {
+	if col, ok := columnNames[column]; ok {
+		column = col
+	}
+
+	less, ok := lessFuncs[column]
+	if !ok {
+		if _, ok := getFields(Fields(compressionUnknown, 0))[column]; ok {
+			return nil, fmt.Errorf("column %s is repeated, rows can't be sorted by it", column)
+		}
+		return nil, fmt.Errorf("unknown column: %s", column)
+	}
+
+	if descending {
+		return func(a, b {{.Parent.StructType}}) bool { return less(b, a) }, nil
+	}
+	return less, nil
+}
+
+// ToMap returns a map from the name of each of x's columns (like
+// "hobby.name") to its value, which can be marshaled to JSON.  Nil values
+// are left out, the values of repeated columns are slices and maps are
+// under the name of their group (see parquet.ToMap).
+func ToMap(x {{.Parent.StructType}}) map[string]interface{} {
+	return parquet.ToMap(x, columnNames)
+}
+
+// FromMap returns the row that ToMap returned m for.  m can also be
+// unmarshaled from JSON (use json.Decoder's UseNumber if the row has 64
+// bit integers that don't fit in a float64).
+func FromMap(m map[string]interface{}) ({{.Parent.StructType}}, error) {
+	var x {{.Parent.StructType}}
+	err := parquet.FromMap(m, &x, columnNames)
+	return x, err
+}
+
+// Equal reports whether x and other have the same value in each column
+// (see parquet.RowsEqual).  Unlike reflect.DeepEqual, a nil slice is equal
+// to an empty one and times in different locations are equal if they're
+// the same instant, but a nil pointer is only equal to another nil pointer.
+func (x {{.Parent.StructType}}) Equal(other {{.Parent.StructType}}) bool {
+	return parquet.RowsEqual(x, other, columnNames)
+}
+
+// ReadColumn reads all the values of a single column without reading any
+// of the other columns.  The column is given by its name (like "hobby.name")
+// or by its field's name (like "Hobby.Name").  dest must be a pointer to a
+// slice of the column's type (the non-pointer type for optional fields).
+// Optional and repeated columns only put their non-nil values in dest, so
+// their definition and repetition levels are returned too.
+func (p *ParquetReader) ReadColumn(name string, dest interface{}) (Levels, error) {
+	if col, ok := columnNames[name]; ok {
+		name = col
 	}
-	pr.rows = meta.Rows()
-	var err error
-	pr.pages, err = meta.Pages()
+
+	f, ok := getFields(Fields(compressionUnknown, 0))[name]
+	if !ok {
+		return Levels{}, fmt.Errorf("unknown column: %s", name)
+	}
+
+	pages, err := p.meta.Pages()
 	if err != nil {
-		return nil, err
+		return Levels{}, err
 	}
 
-	pr.rowGroups = meta.RowGroups()
-	_, err = r.Seek(4, io.SeekStart)
+	// put the reader back so reading rows isn't affected
+	pos, err := p.r.Seek(0, io.SeekCurrent)
 	if err != nil {
-		return nil, err
+		return Levels{}, err
 	}
-	pr.meta = meta
+	defer p.r.Seek(pos, io.SeekStart)
 
-	return pr, pr.readRowGroup()
-}
+	for _, pg := range pages[name] {
+		if _, err := p.r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return Levels{}, err
+		}
 
-func readerIndex(i int) func(*ParquetReader) {
-	return func(p *ParquetReader) {
-		p.index = i
+		if err := f.Read(p.r, pg); err != nil {
+			return Levels{}, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		}
 	}
+
+	defs, reps := f.Levels()
+	return Levels{Name: f.Name(), Defs: defs, Reps: reps}, f.copyTo(dest)
 }
 
-// ParquetReader reads one page from a row group.
-type ParquetReader struct {
-	fields         map[string]Field
-	fieldNames     []string
-	index          int
-	cursor         int64
-	rows           int64
-	rowGroupCursor int64
-	rowGroupCount  int64
-	pages          map[string][]parquet.Page
-	meta           *parquet.Metadata
-	err            error
+// NumRowGroups returns the number of row groups in the file (including
+// the ones that are skipped by Filter or MaxRows) from its footer.
+func (p *ParquetReader) NumRowGroups() int {
+	return len(p.meta.RowGroups())
+}
 
-	r         io.ReadSeeker
-	rowGroups []parquet.RowGroup
+// Metadata returns the key/value metadata from the file's footer (see
+// SetMetadata), which is empty if the file doesn't have any.
+func (p *ParquetReader) Metadata() map[string]string {
+	return p.meta.KeyValueMetadata()
 }
 
-type Levels struct {
-	Name string
-	Defs []uint8
-	Reps []uint8
+// CreatedBy returns the application that wrote the file (see SetCreatedBy)
+// from its footer, which is empty if the footer doesn't say.
+func (p *ParquetReader) CreatedBy() string {
+	return p.meta.CreatedBy()
 }
 
-func (p *ParquetReader) Levels() []Levels {
-	var out []Levels
-	//for {
-	for _, name := range p.fieldNames {
-		f := p.fields[name]
-		d, r := f.Levels()
-		out = append(out, Levels{Name: f.Name(), Defs: d, Reps: r})
+// NumRows returns the number of rows in the file (the sum of the rows of
+// its row groups) from its footer.  Unlike Rows, it includes the rows that
+// are skipped by Filter or MaxRows.
+func (p *ParquetReader) NumRows() int64 {
+	var n int64
+	for _, rg := range p.meta.RowGroups() {
+		n += rg.Rows
 	}
-	//	if err := p.readRowGroup(); err != nil {
-	//		break
-	//	}
-	//}
-	return out
+	return n
 }
 
-func (p *ParquetReader) Error() error {
-	return p.err
-}
+// ReadRowGroup reads all of the rows of the row group at index i of the
+// file (see NumRowGroups), seeking straight to its column chunks, and
+// doesn't change which row Next and Scan are on.  Filter and MaxRows don't
+// apply to it (ReadContext does).  A reader can't be shared between
+// goroutines, so each worker that reads row groups in parallel needs its
+// own reader.
+func (p *ParquetReader) ReadRowGroup(i int) ([]{{.Parent.StructType}}, error) {
+	rowGroups := p.meta.RowGroups()
+	if i < 0 || i >= len(rowGroups) {
+		return nil, fmt.Errorf("row group %d is out of range, the file has %d row groups", i, len(rowGroups))
+	}
 
-func (p *ParquetReader) readRowGroup() error {
-	p.rowGroupCursor = 0
+	if err := p.ctxErr(); err != nil {
+		return nil, err
+	}
 
-	if len(p.rowGroups) == 0 {
-		p.rowGroupCount = 0
-		return nil
+	pages, err := p.meta.Pages()
+	if err != nil {
+		return nil, err
+	}
+
+	// put the reader back so reading rows isn't affected
+	pos, err := p.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
 	}
+	defer p.r.Seek(pos, io.SeekStart)
 
-	rg := p.rowGroups[0]
-	p.fields = getFields(Fields(compressionUnknown))
-	p.rowGroupCount = rg.Rows
-	p.rowGroupCursor = 0
+	rg := rowGroups[i]
+	fields := getFields(Fields(compressionUnknown, 0))
 	for _, col := range rg.Columns() {
 		name := strings.Join(col.MetaData.PathInSchema, ".")
-		f, ok := p.fields[name]
+		f, ok := fields[name]
 		if !ok {
-			return fmt.Errorf("unknown field: %s", name)
+			return nil, fmt.Errorf("unknown field: %s", name)
 		}
-		pages := p.pages[name]
-		if len(pages) <= p.index {
-			break
+
+		pg := pages[name][i]
+		if _, err := p.r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return nil, err
 		}
 
-		pg := pages[0]
 		if err := f.Read(p.r, pg); err != nil {
-			return fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+			return nil, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
 		}
-		p.pages[name] = p.pages[name][1:]
 	}
-	p.rowGroups = p.rowGroups[1:]
-	return nil
+
+	out := make([]{{.Parent.StructType}}, rg.Rows)
+	for j := range out {
+		for _, name := range p.fieldNames {
+			fields[name].Scan(&out[j])
+		}
+	}
+	return out, nil
 }
 
-func (p *ParquetReader) Rows() int64 {
-	return p.rows
+// MayContain uses the bloom filters of a column (given by its name or by
+// its field's name) to check whether any of the row groups can have value,
+// which must be a string or a []byte.  A row group whose column chunk
+// doesn't have a bloom filter (see BloomFilter) can have any value, and
+// a value that may be in a row group might not be.
+func (p *ParquetReader) MayContain(name string, value interface{}) (bool, error) {
+	if col, ok := columnNames[name]; ok {
+		name = col
+	}
+
+	if _, ok := getFields(Fields(compressionUnknown, 0))[name]; !ok {
+		return false, fmt.Errorf("unknown column: %s", name)
+	}
+
+	var v []byte
+	switch x := value.(type) {
+	case string:
+		v = []byte(x)
+	case []byte:
+		v = x
+	default:
+		return false, fmt.Errorf("invalid value %v (%T), it must be a string or a []byte", value, value)
+	}
+
+	// put the reader back so reading rows isn't affected
+	pos, err := p.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+	defer p.r.Seek(pos, io.SeekStart)
+
+	return p.meta.MayContain(p.r, name, v)
 }
 
-func (p *ParquetReader) Next() bool {
-	if p.err == nil && p.cursor >= p.rows {
-		return false
+// WriteCSV writes the rest of the rows to w as CSV with a header of the
+// columns' names.  columns (given by their names or by their fields'
+// names) are the columns that are written, in the order of Fields, and
+// all of the columns are written if none are given.  Nulls are empty
+// fields and repeated columns are JSON arrays (see parquet.CSVRecord).
+func (p *ParquetReader) WriteCSV(w io.Writer, columns ...string) error {
+	include := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if c, ok := columnNames[col]; ok {
+			col = c
+		}
+
+		if _, ok := getFields(Fields(compressionUnknown, 0))[col]; !ok {
+			return fmt.Errorf("unknown column: %s", col)
+		}
+		include[col] = true
 	}
-	if p.rowGroupCursor >= p.rowGroupCount {
-		p.err = p.readRowGroup()
-		if p.err != nil {
-			return false
+
+	var names []string
+	for _, f := range Fields(compressionUnknown, 0) {
+		if len(include) == 0 || include[f.Name()] {
+			names = append(names, f.Name())
 		}
 	}
 
-	p.cursor++
-	p.rowGroupCursor++
-	return true
+	cw := csv.NewWriter(w)
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+
+	for p.Next() {
+		var x {{.Parent.StructType}}
+		p.Scan(&x)
+		rec, err := parquet.CSVRecord(ToMap(x), names)
+		if err != nil {
+			return err
+		}
+
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	if err := p.Error(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
 }
 
+// Scan copies the current row into x.
 func (p *ParquetReader) Scan(x *{{.Parent.StructType}}) {
 	if p.err != nil {
 		return
 	}
 
+	if p.row != nil {
+		*x = *p.row
+		p.row = nil
+		return
+	}
+
+	p.scan(x)
+}
+
+func (p *ParquetReader) scan(x *{{.Parent.StructType}}) {
 	for _, name := range p.fieldNames {
 		f := p.fields[name]
 		f.Scan(x)
@@ -401,9 +1825,39 @@ func (p *ParquetReader) Scan(x *{{.Parent.StructType}}) {
 {{if eq .Category "boolOptional"}}
 {{ template "boolOptionalField" .}}
 {{end}}
+{{if eq .Category "time"}}
+{{ template "timeField" .}}
+{{end}}
+{{if eq .Category "timeOptional"}}
+{{ template "timeOptionalField" .}}
+{{end}}
+{{if eq .Category "bytes"}}
+{{ template "bytesField" .}}
+{{end}}
+{{if eq .Category "bytesOptional"}}
+{{ template "bytesOptionalField" .}}
+{{end}}
+{{if eq .Category "fixed"}}
+{{ template "fixedField" .}}
+{{end}}
+{{if eq .Category "fixedOptional"}}
+{{ template "fixedOptionalField" .}}
+{{end}}
+{{if eq .Category "interval"}}
+{{ template "intervalField" .}}
+{{end}}
+{{if eq .Category "intervalOptional"}}
+{{ template "intervalOptionalField" .}}
+{{end}}
+{{if eq .Category "nullOptional"}}
+{{ template "nullOptionalField" .}}
+{{end}}
+{{if eq .Category "bigintOptional"}}
+{{ template "bigintOptionalField" .}}
+{{end}}
 {{end}}
 
-{{range dedupe .Parent.Fields}}
+{{range dedupeStats .Parent.Fields}}
 {{if eq .Category "numeric"}}
 {{ template "requiredStats" .}}
 {{end}}
@@ -422,8 +1876,42 @@ func (p *ParquetReader) Scan(x *{{.Parent.StructType}}) {
 {{if eq .Category "boolOptional"}}
 {{ template "boolOptionalStats" .}}
 {{end}}
+{{if eq .Category "time"}}
+{{ template "timeStats" .}}
+{{end}}
+{{if eq .Category "timeOptional"}}
+{{ template "timeOptionalStats" .}}
+{{end}}
+{{if eq .Category "bytes"}}
+{{ template "bytesStats" .}}
+{{end}}
+{{if eq .Category "bytesOptional"}}
+{{ template "bytesOptionalStats" .}}
+{{end}}
+{{if eq .Category "fixed"}}
+{{ template "fixedStats" .}}
+{{end}}
+{{if eq .Category "fixedOptional"}}
+{{ template "fixedOptionalStats" .}}
+{{end}}
+{{if eq .Category "interval"}}
+{{ template "intervalStats" .}}
+{{end}}
+{{if eq .Category "intervalOptional"}}
+{{ template "intervalOptionalStats" .}}
+{{end}}
+{{if eq .Category "nullOptional"}}
+{{ template "nullOptionalStats" .}}
+{{end}}
+{{if eq .Category "bigintOptional"}}
+{{ template "bigintOptionalStats" .}}
+{{end}}
 {{end}}
 
+func pint8(i int8) *int8          { return &i }
+func pint16(i int16) *int16       { return &i }
+func puint8(i uint8) *uint8       { return &i }
+func puint16(i uint16) *uint16    { return &i }
 func pint32(i int32) *int32       { return &i }
 func puint32(i uint32) *uint32    { return &i }
 func pint64(i int64) *int64       { return &i }
@@ -432,6 +1920,20 @@ func pbool(b bool) *bool          { return &b }
 func pstring(s string) *string    { return &s }
 func pfloat32(f float32) *float32 { return &f }
 func pfloat64(f float64) *float64 { return &f }
+func ptime(t time.Time) *time.Time { return &t }
+func pduration(d time.Duration) *time.Duration { return &d }
+{{range marshalers .Parent.Fields}}
+var (
+	_ parquet.{{.MarshalerInterface}}Marshaler   = (*{{.Named}})(nil)
+	_ parquet.{{.MarshalerInterface}}Unmarshaler = (*{{.Named}})(nil)
+)
+
+func {{.UnmarshalFunc}}(v {{.Type}}) *{{.Named}} {
+	x := new({{.Named}})
+	x.UnmarshalParquet(v)
+	return x
+}
+{{end}}
 
 // keeps track of the indices of repeated fields
 // that have already been handled by a previous field
@@ -457,6 +1959,34 @@ func maxDef(types []int) uint8 {
 	return out
 }
 
+func Int8Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_INT_8
+	se.ConvertedType = &ct
+}
+
+func Int16Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_INT_16
+	se.ConvertedType = &ct
+}
+
+func Uint8Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_UINT_8
+	se.ConvertedType = &ct
+}
+
+func Uint16Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_UINT_16
+	se.ConvertedType = &ct
+}
+
 func Int32Type(se *sch.SchemaElement) {
 	t := sch.Type_INT32
 	se.Type = &t
@@ -491,6 +2021,96 @@ func Float64Type(se *sch.SchemaElement) {
 	se.Type = &t
 }
 
+func TimestampType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	ct := sch.ConvertedType_TIMESTAMP_MILLIS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIMESTAMP: &sch.TimestampType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MILLIS: sch.NewMilliSeconds()},
+		},
+	}
+}
+
+func TimestampMicrosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	ct := sch.ConvertedType_TIMESTAMP_MICROS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIMESTAMP: &sch.TimestampType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MICROS: sch.NewMicroSeconds()},
+		},
+	}
+}
+
+// TimestampNanosType doesn't have a converted type (there
+// isn't one for nanoseconds), only a logical type.
+func TimestampNanosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	se.LogicalType = &sch.LogicalType{
+		TIMESTAMP: &sch.TimestampType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+		},
+	}
+}
+
+// TimeOfDayType is a time.Duration since midnight
+// that is stored as the milliseconds since midnight.
+func TimeOfDayType(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_TIME_MILLIS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIME: &sch.TimeType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MILLIS: sch.NewMilliSeconds()},
+		},
+	}
+}
+
+func TimeOfDayMicrosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	ct := sch.ConvertedType_TIME_MICROS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIME: &sch.TimeType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MICROS: sch.NewMicroSeconds()},
+		},
+	}
+}
+
+// TimeOfDayNanosType doesn't have a converted type (there
+// isn't one for nanoseconds), only a logical type.
+func TimeOfDayNanosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	se.LogicalType = &sch.LogicalType{
+		TIME: &sch.TimeType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+		},
+	}
+}
+
+func DateType(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_DATE
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		DATE: sch.NewDateType(),
+	}
+}
+
 func BoolType(se *sch.SchemaElement) {
 	t := sch.Type_BOOLEAN
 	se.Type = &t
@@ -500,4 +2120,125 @@ func StringType(se *sch.SchemaElement) {
 	t := sch.Type_BYTE_ARRAY
 	se.Type = &t
 }
+
+func EnumType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+	ct := sch.ConvertedType_ENUM
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		ENUM: sch.NewEnumType(),
+	}
+}
+
+func JSONType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+	ct := sch.ConvertedType_JSON
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		JSON: sch.NewJsonType(),
+	}
+}
+
+func ByteArrayType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+}
+
+func BSONType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+	ct := sch.ConvertedType_BSON
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		BSON: sch.NewBsonType(),
+	}
+}
+
+func GeometryType(crs string) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		ByteArrayType(se)
+		se.LogicalType = &sch.LogicalType{
+			GEOMETRY: &sch.GeometryType{Crs: crsPtr(crs)},
+		}
+	}
+}
+
+func GeographyType(crs string) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		ByteArrayType(se)
+		se.LogicalType = &sch.LogicalType{
+			GEOGRAPHY: &sch.GeographyType{Crs: crsPtr(crs)},
+		}
+	}
+}
+
+// crsPtr leaves the crs of a geometry or geography
+// column unset (OGC:CRS84) when it's empty.
+func crsPtr(crs string) *string {
+	if crs == "" {
+		return nil
+	}
+	return &crs
+}
+
+func FixedLenByteArrayType(n int32) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		t := sch.Type_FIXED_LEN_BYTE_ARRAY
+		se.Type = &t
+		se.TypeLength = &n
+	}
+}
+
+func UUIDType(se *sch.SchemaElement) {
+	FixedLenByteArrayType(16)(se)
+	se.LogicalType = &sch.LogicalType{
+		UUID: sch.NewUUIDType(),
+	}
+}
+
+// Float16Type is a uint16 that holds the bits of a half precision
+// float, which is stored as a FIXED_LEN_BYTE_ARRAY(2) (the bits in
+// little-endian order) with the FLOAT16 logical type.
+func Float16Type(se *sch.SchemaElement) {
+	FixedLenByteArrayType(2)(se)
+	se.LogicalType = &sch.LogicalType{
+		FLOAT16: &sch.Float16Type{},
+	}
+}
+
+// IntervalType is a time.Duration that is stored as an INTERVAL
+// (see parquet.IntervalBytes).
+func IntervalType(se *sch.SchemaElement) {
+	FixedLenByteArrayType(12)(se)
+	ct := sch.ConvertedType_INTERVAL
+	se.ConvertedType = &ct
+}
+
+// NullType is a column that is always null.  Parquet columns need a
+// physical type, so it's an INT32 (that never has any values) with
+// the UNKNOWN logical type.
+func NullType(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	se.LogicalType = &sch.LogicalType{
+		UNKNOWN: sch.NewNullType(),
+	}
+}
+
+// DecimalType annotates a column of type typ (an INT32, INT64 or
+// FIXED_LEN_BYTE_ARRAY) as a decimal with the given precision and scale.
+func DecimalType(precision, scale int32, typ parquet.FieldFunc) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		typ(se)
+		ct := sch.ConvertedType_DECIMAL
+		se.ConvertedType = &ct
+		se.Precision = &precision
+		se.Scale = &scale
+		se.LogicalType = &sch.LogicalType{
+			DECIMAL: &sch.DecimalType{Precision: precision, Scale: scale},
+		}
+	}
+}
 `
@@ -0,0 +1,171 @@
+package gen
+
+var bigintOptionalTpl = `{{define "bigintOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals  []big.Int
+	read  func(r {{.StructType}}, vals []big.Int, defs, reps []uint8) ([]big.Int, []uint8, []uint8)
+	write func(r *{{.StructType}}, vals []big.Int, defs, reps []uint8) (int, int)
+	stats *{{statsType .}}
+	// limit is 10^precision, which is more than
+	// the largest decimal the field can hold
+	limit *big.Int
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []big.Int, defs, reps []uint8) ([]big.Int, []uint8, []uint8), write func(r *{{.StructType}}, vals []big.Int, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         new{{camelCase (statsType .)}}(maxDef(types)),
+		limit:         new(big.Int).Exp(big.NewInt(10), big.NewInt({{.Precision}}), nil),
+	}
+}
+
+func {{.PointerFunc}}(v big.Int) *big.Int {
+	return &v
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	for i := range f.vals {
+		v := &f.vals[i]
+		if v.CmpAbs(f.limit) >= 0 {
+			return fmt.Errorf("field %s: decimal %s has more than {{.Precision}} digits", f.Name(), v)
+		}
+
+		b, err := parquet.DecimalBytes(v, {{.BigIntLen}})
+		if err != nil {
+			return fmt.Errorf("field %s: %s", f.Name(), err)
+		}
+
+		if _, err := buf.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	n := f.Values() - len(f.vals)
+	b := make([]byte, n*{{.BigIntLen}})
+	if _, err := io.ReadFull(rr, b); err != nil {
+		return fmt.Errorf("field %s: values are shorter than {{.BigIntLen}} bytes: %s", f.Name(), err)
+	}
+
+	if n, _ := rr.Read(make([]byte, 1)); n > 0 {
+		return fmt.Errorf("field %s: values are longer than {{.BigIntLen}} bytes", f.Name())
+	}
+
+	for j := 0; j < n; j++ {
+		f.vals = append(f.vals, *parquet.DecimalInt(b[j*{{.BigIntLen}} : (j+1)*{{.BigIntLen}}]))
+	}
+	return nil
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]big.Int)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]big.Int", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+{{end}}`
+
+var bigintOptionalStatsTpl = `{{define "bigintOptionalStats"}}
+type {{statsType .}} struct {
+	min     *big.Int
+	max     *big.Int
+	nils    int64
+	nonNils int64
+	maxDef  uint8
+}
+
+func new{{camelCase (statsType .)}}(d uint8) *{{statsType .}} {
+	return &{{statsType .}}{maxDef: d}
+}
+
+func (s *{{statsType .}}) add(vals []big.Int, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
+
+		val := &vals[i]
+		i++
+		if s.nonNils == 0 || val.Cmp(s.min) < 0 {
+			s.min = val
+		}
+		if s.nonNils == 0 || s.max.Cmp(val) < 0 {
+			s.max = val
+		}
+		s.nonNils++
+	}
+}
+
+func (s *{{statsType .}}) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *{{statsType .}}) DistinctCount() *int64 {
+	return nil
+}
+
+// Write returns an error for a decimal that doesn't fit before
+// Min and Max are used, so they can ignore DecimalBytes' error.
+func (s *{{statsType .}}) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	b, _ := parquet.DecimalBytes(s.min, {{.BigIntLen}})
+	return b
+}
+
+func (s *{{statsType .}}) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	b, _ := parquet.DecimalBytes(s.max, {{.BigIntLen}})
+	return b
+}
+{{end}}`
@@ -22,17 +22,7 @@ func (f *BoolField) Schema() parquet.Field {
 
 
 func (f *BoolField) Write(w io.Writer, meta *parquet.Metadata) error {
-	ln := len(f.vals)
-	n := (ln + 7) / 8
-	rawBuf := make([]byte, n)
-
-	for i := 0; i < ln; i++ {
-		if f.vals[i] {
-			rawBuf[i/8] = rawBuf[i/8] | (1 << uint32(i%8))
-		}
-	}
-
-	return f.DoWrite(w, meta, rawBuf, len(f.vals), newBoolStats())
+	return f.DoWriteBools(w, meta, f.vals, len(f.vals), newBoolStats())
 }
 
 func (f *BoolField) Read(r io.ReadSeeker, pg parquet.Page) error {
@@ -41,7 +31,8 @@ func (f *BoolField) Read(r io.ReadSeeker, pg parquet.Page) error {
 		return err
 	}
 
-	f.vals, err = parquet.GetBools(rr, int(pg.N), sizes)
+	v, err := parquet.GetBools(rr, int(pg.N), sizes)
+	f.vals = append(f.vals, v...)
 	return err
 }
 
@@ -59,6 +50,15 @@ func (f *BoolField) Add(r {{.StructType}}) {
 	f.vals = append(f.vals, v)
 }
 
+func (f *BoolField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]bool)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]bool", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
 func (f *BoolField) Levels() ([]uint8, []uint8) {
 	return nil, nil
 }
@@ -67,7 +67,7 @@ func (f *BoolField) Levels() ([]uint8, []uint8) {
 var boolStatsTpl = `{{define "boolStats"}}
 type boolStats struct {}
 func newBoolStats() *boolStats {return &boolStats{}}
-func (b *boolStats) NullCount() *int64 {return nil}
+func (b *boolStats) NullCount() *int64 {return new(int64)}
 func (b *boolStats) DistinctCount() *int64 {return nil}
 func (b *boolStats) Min() []byte {return nil}
 func (b *boolStats) Max() []byte {return nil}
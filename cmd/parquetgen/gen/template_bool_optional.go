@@ -18,7 +18,7 @@ func NewBoolOptionalField(read func(r {{.StructType}}, vals []{{removeStar .Type
 }
 
 func (f *BoolOptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: f.RepetitionType, Types: f.Types}
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
 func (f *BoolOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
@@ -54,17 +54,16 @@ func (f *BoolOptionalField) Add(r {{.StructType}}) {
 }
 
 func (f *BoolOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
-	ln := len(f.vals)
-	byteNum := (ln + 7) / 8
-	rawBuf := make([]byte, byteNum)
+	return f.DoWriteBools(w, meta, f.vals, len(f.Defs), f.stats)
+}
 
-	for i := 0; i < ln; i++ {
-		if f.vals[i] {
-			rawBuf[i/8] = rawBuf[i/8] | (1 << uint32(i%8))
-		}
+func (f *BoolOptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]bool)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]bool", f.Name(), dest)
 	}
-
-	return f.DoWrite(w, meta, rawBuf, len(f.Defs), f.stats)
+	*d = f.vals
+	return nil
 }
 
 func (f *BoolOptionalField) Levels() ([]uint8, []uint8) {
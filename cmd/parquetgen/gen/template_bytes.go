@@ -0,0 +1,363 @@
+package gen
+
+var bytesTpl = `{{define "bytesField"}}
+type {{.FieldType}} struct {
+	parquet.RequiredField
+	vals  [][]byte
+	read  func(r {{.StructType}}) {{.TypeName}}
+	write func(r *{{.StructType}}, vals []{{removeStar .TypeName}})
+	stats *bytesStats
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}) {{.TypeName}}, write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}), path []string, opts ...func(*parquet.RequiredField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newBytesStats(),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(string(v))
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.vals), f.stats)
+	}
+
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, b := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(b)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *{{.FieldType}}) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(string(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *{{.FieldType}}) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash(v))
+	}
+	return hashes
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, pg.N)
+		if err != nil {
+			return err
+		}
+		for _, b := range vals {
+			// empty values are read as nil (see below)
+			if len(b) == 0 {
+				b = nil
+			}
+			f.vals = append(f.vals, b)
+		}
+		return nil
+	}
+
+	bs := make([]byte, 4)
+	for j := 0; j < pg.N; j++ {
+		if _, err := io.ReadFull(rr, bs); err != nil {
+			return err
+		}
+		x := binary.LittleEndian.Uint32(bs)
+		// a required column can't tell a nil slice from an
+		// empty one, so empty values are read back as nil.
+		var b []byte
+		if x > 0 {
+			b = make([]byte, x)
+			if _, err := io.ReadFull(rr, b); err != nil {
+				return err
+			}
+		}
+
+		f.vals = append(f.vals, b)
+	}
+	return nil
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[][]byte)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[][]byte", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+{{end}}`
+
+var bytesOptionalTpl = `{{define "bytesOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals  [][]byte
+	read  func(r {{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8)
+	write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) (int, int)
+	stats *bytesOptionalStats
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8), write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newBytesOptionalStats(maxDef(types)),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(string(v))
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.Defs), f.stats)
+	}
+
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, b := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(b)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *{{.FieldType}}) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(string(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *{{.FieldType}}) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash(v))
+	}
+	return hashes
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	n := f.Values() - len(f.vals)
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, n)
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, vals...)
+		return nil
+	}
+
+	bs := make([]byte, 4)
+	for j := 0; j < n; j++ {
+		if _, err := io.ReadFull(rr, bs); err != nil {
+			return err
+		}
+		x := binary.LittleEndian.Uint32(bs)
+		b := make([]byte, x)
+		if _, err := io.ReadFull(rr, b); err != nil {
+			return err
+		}
+
+		f.vals = append(f.vals, b)
+	}
+	return nil
+}
+
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[][]byte)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[][]byte", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+{{end}}`
+
+var bytesStatsTpl = `{{define "bytesStats"}}
+type bytesStats struct {
+	min     []byte
+	max     []byte
+	nonNils int64
+}
+
+func newBytesStats() *bytesStats {
+	return &bytesStats{}
+}
+
+func (s *bytesStats) add(val []byte) {
+	if s.nonNils == 0 || string(val) < string(s.min) {
+		s.min = val
+	}
+	if s.nonNils == 0 || string(val) > string(s.max) {
+		s.max = val
+	}
+	s.nonNils++
+}
+
+func (s *bytesStats) NullCount() *int64 {
+	return new(int64)
+}
+
+func (s *bytesStats) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *bytesStats) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.min
+}
+
+func (s *bytesStats) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.max
+}
+{{end}}`
+
+var bytesOptionalStatsTpl = `{{define "bytesOptionalStats"}}
+type bytesOptionalStats struct {
+	min     []byte
+	max     []byte
+	nils    int64
+	nonNils int64
+	maxDef  uint8
+}
+
+func newBytesOptionalStats(d uint8) *bytesOptionalStats {
+	return &bytesOptionalStats{maxDef: d}
+}
+
+func (s *bytesOptionalStats) add(vals [][]byte, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
+
+		val := vals[i]
+		i++
+		if s.nonNils == 0 || string(val) < string(s.min) {
+			s.min = val
+		}
+		if s.nonNils == 0 || string(val) > string(s.max) {
+			s.max = val
+		}
+		s.nonNils++
+	}
+}
+
+func (s *bytesOptionalStats) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *bytesOptionalStats) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *bytesOptionalStats) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.min
+}
+
+func (s *bytesOptionalStats) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.max
+}
+{{end}}`
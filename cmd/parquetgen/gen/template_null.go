@@ -0,0 +1,125 @@
+package gen
+
+// A null column (a *struct{} field with logical=null) is always null, so
+// its pages only have levels.  The levels come from the field like they
+// do for any other optional field, but a non-nil value is written (and
+// read) as a null at the field's own level.
+var nullOptionalTpl = `{{define "nullOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals   []struct{}
+	read   func(r {{.StructType}}, vals []struct{}, defs, reps []uint8) ([]struct{}, []uint8, []uint8)
+	write  func(r *{{.StructType}}, vals []struct{}, defs, reps []uint8) (int, int)
+	maxDef uint8
+	stats  *{{statsType .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []struct{}, defs, reps []uint8) ([]struct{}, []uint8, []uint8), write func(r *{{.StructType}}, vals []struct{}, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		maxDef:        maxDef(types),
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         new{{camelCase (statsType .)}}(maxDef(types)),
+	}
+}
+
+func {{.PointerFunc}}(v struct{}) *struct{} {
+	return &v
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	return f.DoWrite(w, meta, nil, len(f.Defs), f.stats)
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	if _, _, err := f.DoRead(r, pg); err != nil {
+		return err
+	}
+
+	f.nulls(f.Defs)
+	return nil
+}
+
+// nulls turns the definition levels of values into
+// the definition levels of nulls at the same level.
+func (f *{{.FieldType}}) nulls(defs []uint8) {
+	for i, def := range defs {
+		if def == f.maxDef {
+			defs[i] = def - 1
+		}
+	}
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	_, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.nulls(defs[len(f.Defs):])
+	f.stats.add(defs[len(f.Defs):])
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	_, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]struct{})
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]struct{}", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+{{end}}`
+
+var nullOptionalStatsTpl = `{{define "nullOptionalStats"}}
+type {{statsType .}} struct {
+	nils   int64
+	maxDef uint8
+}
+
+func new{{camelCase (statsType .)}}(d uint8) *{{statsType .}} {
+	return &{{statsType .}}{maxDef: d}
+}
+
+func (s *{{statsType .}}) add(defs []uint8) {
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+		}
+	}
+}
+
+func (s *{{statsType .}}) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *{{statsType .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsType .}}) Min() []byte {
+	return nil
+}
+
+func (s *{{statsType .}}) Max() []byte {
+	return nil
+}
+{{end}}`
@@ -22,7 +22,7 @@ func New{{.FieldType}}(read func(r {{.StructType}}, vals []{{removeStar .TypeNam
 }
 
 func (f *{{.FieldType}}) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types}
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
 func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
@@ -45,10 +45,20 @@ func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
 		return err
 	}
 
-	v := make([]{{removeStar .TypeName}}, f.Values()-len(f.vals))
+	v := make([]{{readType .}}, f.Values()-len(f.vals))
 	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+	{{if narrow .}}if err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		{{if minType .}}if x < {{minType .}} || x > {{maxType .}} {
+			return fmt.Errorf("value %d is out of range for {{removeStar .TypeName}} field %s", x, f.Name())
+		}
+		{{end}}f.vals = append(f.vals, {{removeStar .TypeName}}(x))
+	}
+	return nil{{else}}f.vals = append(f.vals, v...)
+	return err{{end}}
 }
 
 func (f *{{.FieldType}}) Add(r {{.StructType}}) {
@@ -72,6 +82,15 @@ func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
 	}
 }
 
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]{{removeStar .TypeName}})
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]{{removeStar .TypeName}}", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
 func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
 	return f.Defs, f.Reps
 }
@@ -88,7 +107,6 @@ type {{removeStar .TypeName}}optionalStats struct {
 
 func new{{removeStar .TypeName}}optionalStats(d uint8) *{{removeStar .TypeName}}optionalStats {
 	return &{{removeStar .TypeName}}optionalStats{
-		min: {{removeStar .TypeName}}(math.Max{{camelCaseRemoveStar .TypeName}}),
 		maxDef: d,
 	}
 }
@@ -101,14 +119,17 @@ func (f *{{removeStar .TypeName}}optionalStats) add(vals []{{removeStar .TypeNam
 		} else {
 			val := vals[i]
 			i++
-
-			f.nonNils++
-			if val < f.min {
+			{{with isNaN . "val"}}if {{.}} {
+				continue
+			}
+			{{end}}
+			if f.nonNils == 0 || val < f.min {
 				f.min = val
 			}
-			if val > f.max {
+			if f.nonNils == 0 || val > f.max {
 				f.max = val
 			}
+			f.nonNils++
 		}
 	}
 }
@@ -28,10 +28,20 @@ func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
 		return err
 	}
 
-	v := make([]{{.TypeName}}, int(pg.N))
+	v := make([]{{readType .}}, int(pg.N))
 	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+	{{if narrow .}}if err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		{{if minType .}}if x < {{minType .}} || x > {{maxType .}} {
+			return fmt.Errorf("value %d is out of range for {{.TypeName}} field %s", x, f.Name())
+		}
+		{{end}}f.vals = append(f.vals, {{.TypeName}}(x))
+	}
+	return nil{{else}}f.vals = append(f.vals, v...)
+	return err{{end}}
 }
 
 func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
@@ -63,6 +73,15 @@ func (f *{{.FieldType}}) Add(r {{.Parent.StructType}}) {
 	f.vals = append(f.vals, v)
 }
 
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]{{.TypeName}})
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]{{.TypeName}}", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
 func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
 	return nil, nil
 }
@@ -70,23 +89,26 @@ func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
 
 var requiredStatsTpl = `{{define "requiredStats"}}
 type {{.TypeName}}stats struct {
-	min {{.TypeName}}
-	max {{.TypeName}}
+	min     {{.TypeName}}
+	max     {{.TypeName}}
+	nonNils int64
 }
 
 func new{{camelCase .TypeName}}stats() *{{.TypeName}}stats {
-	return &{{.TypeName}}stats{
-		min: {{.TypeName}}(math.Max{{camelCase .TypeName}}),
-	}
+	return &{{.TypeName}}stats{}
 }
 
 func (i *{{.TypeName}}stats) add(val {{.TypeName}}) {
-	if val < i.min {
+	{{with isNaN . "val"}}if {{.}} {
+		return
+	}
+	{{end}}if i.nonNils == 0 || val < i.min {
 		i.min = val
 	}
-	if val > i.max {
+	if i.nonNils == 0 || val > i.max {
 		i.max = val
 	}
+	i.nonNils++
 }
 
 func (f *{{.TypeName}}stats) bytes(v {{.TypeName}}) []byte {
@@ -96,7 +118,7 @@ func (f *{{.TypeName}}stats) bytes(v {{.TypeName}}) []byte {
 }
 
 func (f *{{.TypeName}}stats) NullCount() *int64 {
-	return nil
+	return new(int64)
 }
 
 func (f *{{.TypeName}}stats) DistinctCount() *int64 {
@@ -104,10 +126,16 @@ func (f *{{.TypeName}}stats) DistinctCount() *int64 {
 }
 
 func (f *{{.TypeName}}stats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
 	return f.bytes(f.min)
 }
 
 func (f *{{.TypeName}}stats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
 	return f.bytes(f.max)
 }
 {{end}}`
@@ -1,7 +1,7 @@
 package gen
 
 var stringTpl = `{{define "stringField"}}
-type StringField struct {
+type {{.FieldType}} struct {
 	parquet.RequiredField
 	vals []string
 	read  func(r {{.StructType}}) {{.TypeName}}
@@ -9,8 +9,8 @@ type StringField struct {
 	stats *stringStats
 }
 
-func NewStringField(read func(r {{.StructType}}) {{.TypeName}}, write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}), path []string, opts ...func(*parquet.RequiredField)) *StringField {
-	return &StringField{
+func New{{.FieldType}}(read func(r {{.StructType}}) {{.TypeName}}, write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}), path []string, opts ...func(*parquet.RequiredField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
 		read:           read,
 		write:          write,
 		RequiredField: parquet.NewRequiredField(path, opts...),
@@ -18,11 +18,19 @@ func NewStringField(read func(r {{.StructType}}) {{.TypeName}}, write func(r *{{
 	}
 }
 
-func (f *StringField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
 }
 
-func (f *StringField) Write(w io.Writer, meta *parquet.Metadata) error {
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(v)
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.vals), f.stats)
+	}
+
 	buf := buffpool.Get()
 	defer buffpool.Put(buf)
 
@@ -38,19 +46,52 @@ func (f *StringField) Write(w io.Writer, meta *parquet.Metadata) error {
 	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
 }
 
-func (f *StringField) Read(r io.ReadSeeker, pg parquet.Page) error {
+func (f *{{.FieldType}}) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *{{.FieldType}}) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash([]byte(v)))
+	}
+	return hashes
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
 	rr, _, err := f.DoRead(r, pg)
 	if err != nil {
 		return err
 	}
 
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, pg.N)
+		if err != nil {
+			return err
+		}
+		for _, b := range vals {
+			f.vals = append(f.vals, parquet.NoCopyString(b))
+		}
+		return nil
+	}
+
+	// s is reused for each value (string copies it)
+	var s []byte
+	bs := make([]byte, 4)
 	for j := 0; j < pg.N; j++ {
-		var x int32
-		if err := binary.Read(rr, binary.LittleEndian, &x); err != nil {
+		if _, err := io.ReadFull(rr, bs); err != nil {
 			return err
 		}
-		s := make([]byte, x)
-		if _, err := rr.Read(s); err != nil {
+		x := int(binary.LittleEndian.Uint32(bs))
+		if cap(s) < x {
+			s = make([]byte, x)
+		}
+		s = s[:x]
+		if _, err := io.ReadFull(rr, s); err != nil {
 			return err
 		}
 
@@ -59,7 +100,7 @@ func (f *StringField) Read(r io.ReadSeeker, pg parquet.Page) error {
 	return nil
 }
 
-func (f *StringField) Scan(r *{{.StructType}}) {
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
 	if len(f.vals) == 0 {
 		return
 	}
@@ -68,13 +109,22 @@ func (f *StringField) Scan(r *{{.StructType}}) {
 	f.vals = f.vals[1:]
 }
 
-func (f *StringField) Add(r {{.StructType}}) {
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
 	v := f.read(r)
 	f.stats.add(v)
 	f.vals = append(f.vals, v)
 }
 
-func (f *StringField) Levels() ([]uint8, []uint8) {
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]string)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]string", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
 	return nil, nil
 }
 {{end}}`
@@ -113,7 +163,7 @@ func (s *stringStats) add(val string) {
 }
 
 func (s *stringStats) NullCount() *int64 {
-	return nil
+	return new(int64)
 }
 
 func (s *stringStats) DistinctCount() *int64 {
@@ -1,7 +1,7 @@
 package gen
 
 var stringOptionalTpl = `{{define "stringOptionalField"}}
-type StringOptionalField struct {
+type {{.FieldType}} struct {
 	parquet.OptionalField
 	vals []string
 	read   func(r {{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8)
@@ -9,8 +9,8 @@ type StringOptionalField struct {
 	stats *stringOptionalStats
 }
 
-func NewStringOptionalField(read func(r {{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8), write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *StringOptionalField {
-	return &StringOptionalField{
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8), write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
 		read:          read,
 		write:         write,
 		OptionalField: parquet.NewOptionalField(path, types, opts...),
@@ -18,11 +18,11 @@ func NewStringOptionalField(read func(r {{.StructType}}, vals []{{removeStar .Ty
 	}
 }
 
-func (f *StringOptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: f.RepetitionType, Types: f.Types}
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
-func (f *StringOptionalField) Add(r {{.StructType}}) {
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
 	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
 	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
 	f.vals = vals
@@ -30,7 +30,7 @@ func (f *StringOptionalField) Add(r {{.StructType}}) {
 	f.Reps = reps
 }
 
-func (f *StringOptionalField) Scan(r *{{.StructType}}) {
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
 	if len(f.Defs) == 0 {
 		return
 	}
@@ -43,7 +43,15 @@ func (f *StringOptionalField) Scan(r *{{.StructType}}) {
 	}
 }
 
-func (f *StringOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(v)
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.Defs), f.stats)
+	}
+
 	buf := buffpool.Get()
 	defer buffpool.Put(buf)
 
@@ -59,19 +67,53 @@ func (f *StringOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
 	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
 }
 
-func (f *StringOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+func (f *{{.FieldType}}) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *{{.FieldType}}) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash([]byte(v)))
+	}
+	return hashes
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
 	rr, _, err := f.DoRead(r, pg)
 	if err != nil {
 		return err
 	}
 
-	for j := 0; j < f.Values(); j++ {
-		var x int32
-		if err := binary.Read(rr, binary.LittleEndian, &x); err != nil {
+	n := f.Values() - len(f.vals)
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, n)
+		if err != nil {
+			return err
+		}
+		for _, b := range vals {
+			f.vals = append(f.vals, parquet.NoCopyString(b))
+		}
+		return nil
+	}
+
+	// s is reused for each value (string copies it)
+	var s []byte
+	bs := make([]byte, 4)
+	for j := 0; j < n; j++ {
+		if _, err := io.ReadFull(rr, bs); err != nil {
 			return err
 		}
-		s := make([]byte, x)
-		if _, err := rr.Read(s); err != nil {
+		x := int(binary.LittleEndian.Uint32(bs))
+		if cap(s) < x {
+			s = make([]byte, x)
+		}
+		s = s[:x]
+		if _, err := io.ReadFull(rr, s); err != nil {
 			return err
 		}
 
@@ -80,7 +122,16 @@ func (f *StringOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
 	return nil
 }
 
-func (f *StringOptionalField) Levels() ([]uint8, []uint8) {
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]string)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]string", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
 	return f.Defs, f.Reps
 }
 {{end}}`
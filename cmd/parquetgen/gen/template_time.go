@@ -0,0 +1,316 @@
+package gen
+
+var timeTpl = `{{define "timeField"}}
+type {{.FieldType}} struct {
+	parquet.RequiredField
+	vals  []{{.TypeName}}
+	read  func(r {{.StructType}}) {{.TypeName}}
+	write func(r *{{.StructType}}, vals []{{removeStar .TypeName}})
+	stats *{{statsType .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}) {{.TypeName}}, write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}), path []string, opts ...func(*parquet.RequiredField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         new{{camelCase (statsType .)}}(),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+{{if eq .Type "time.Time"}}
+	if pg.Type == sch.Type_INT96 {
+		v, err := parquet.GetInt96Times(rr, int(pg.N))
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, v...)
+		return nil
+	}
+{{end}}
+	v := make([]{{storedType .}}, int(pg.N))
+	if err := binary.Read(rr, binary.LittleEndian, &v); err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		f.vals = append(f.vals, {{fromStored . "x"}})
+	}
+	return nil
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, {{byteSize .}})
+	for _, v := range f.vals {
+		{{with checkStored . "v"}}if _, err := {{.}}; err != nil {
+			return fmt.Errorf("field %s: %s", f.Name(), err)
+		}
+		{{end}}binary.LittleEndian.{{putFunc .}}(bs, {{uintType .}}({{toStored . "v"}}))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	v := f.read(r)
+	f.stats.add({{toStored . "v"}})
+	f.vals = append(f.vals, v)
+}
+
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]{{.TypeName}})
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]{{.TypeName}}", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+{{end}}`
+
+var timeOptionalTpl = `{{define "timeOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals  []{{removeStar .TypeName}}
+	read  func(r {{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8)
+	write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) (int, int)
+	stats *{{statsType .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8), write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         new{{camelCase (statsType .)}}(maxDef(types)),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, {{byteSize .}})
+	for _, v := range f.vals {
+		{{with checkStored . "v"}}if _, err := {{.}}; err != nil {
+			return fmt.Errorf("field %s: %s", f.Name(), err)
+		}
+		{{end}}binary.LittleEndian.{{putFunc .}}(bs, {{uintType .}}({{toStored . "v"}}))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+{{if eq .Type "time.Time"}}
+	if pg.Type == sch.Type_INT96 {
+		v, err := parquet.GetInt96Times(rr, f.Values()-len(f.vals))
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, v...)
+		return nil
+	}
+{{end}}
+	v := make([]{{storedType .}}, f.Values()-len(f.vals))
+	if err := binary.Read(rr, binary.LittleEndian, &v); err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		f.vals = append(f.vals, {{fromStored . "x"}})
+	}
+	return nil
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]{{removeStar .TypeName}})
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]{{removeStar .TypeName}}", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+{{end}}`
+
+var timeStatsTpl = `{{define "timeStats"}}
+type {{statsType .}} struct {
+	min     {{storedType .}}
+	max     {{storedType .}}
+	nonNils int64
+}
+
+func new{{camelCase (statsType .)}}() *{{statsType .}} {
+	return &{{statsType .}}{}
+}
+
+func (s *{{statsType .}}) add(val {{storedType .}}) {
+	{{with isNaN . "val"}}if {{.}} {
+		return
+	}
+	{{end}}if s.nonNils == 0 || {{storedLess . "val" "s.min"}} {
+		s.min = val
+	}
+	if s.nonNils == 0 || {{storedLess . "s.max" "val"}} {
+		s.max = val
+	}
+	s.nonNils++
+}
+
+func (s *{{statsType .}}) bytes(v {{storedType .}}) []byte {
+	bs := make([]byte, {{byteSize .}})
+	binary.LittleEndian.{{putFunc .}}(bs, {{uintType .}}(v))
+	return bs
+}
+
+func (s *{{statsType .}}) NullCount() *int64 {
+	return new(int64)
+}
+
+func (s *{{statsType .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsType .}}) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.min)
+}
+
+func (s *{{statsType .}}) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.max)
+}
+{{end}}`
+
+var timeOptionalStatsTpl = `{{define "timeOptionalStats"}}
+type {{statsType .}} struct {
+	min     {{storedType .}}
+	max     {{storedType .}}
+	nils    int64
+	nonNils int64
+	maxDef  uint8
+}
+
+func new{{camelCase (statsType .)}}(d uint8) *{{statsType .}} {
+	return &{{statsType .}}{maxDef: d}
+}
+
+func (s *{{statsType .}}) add(vals []{{removeStar .TypeName}}, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
+
+		v := vals[i]
+		i++
+		val := {{toStored . "v"}}
+		{{with isNaN . "val"}}if {{.}} {
+			continue
+		}
+		{{end}}if s.nonNils == 0 || {{storedLess . "val" "s.min"}} {
+			s.min = val
+		}
+		if s.nonNils == 0 || {{storedLess . "s.max" "val"}} {
+			s.max = val
+		}
+		s.nonNils++
+	}
+}
+
+func (s *{{statsType .}}) bytes(v {{storedType .}}) []byte {
+	bs := make([]byte, {{byteSize .}})
+	binary.LittleEndian.{{putFunc .}}(bs, {{uintType .}}(v))
+	return bs
+}
+
+func (s *{{statsType .}}) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *{{statsType .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsType .}}) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.min)
+}
+
+func (s *{{statsType .}}) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.max)
+}
+{{end}}`
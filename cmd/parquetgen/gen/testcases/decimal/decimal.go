@@ -0,0 +1,15 @@
+package decimal
+
+import "math/big"
+
+//go:generate parquetgen -input decimal.go -type Payment -package decimal -output generated.go
+
+// Payment has a decimal field for each of the
+// ways a decimal can be stored.
+type Payment struct {
+	ID       int32    `parquet:"id"`
+	Price    int64    `parquet:"name=price,logical=decimal,precision=18,scale=2"`
+	Discount *int32   `parquet:"name=discount,logical=decimal,precision=4,scale=1"`
+	Balance  [16]byte `parquet:"name=balance,logical=decimal,precision=38,scale=4"`
+	Total    *big.Int `parquet:"name=total,logical=decimal,precision=38,scale=2"`
+}
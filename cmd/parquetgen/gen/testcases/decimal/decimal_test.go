@@ -0,0 +1,312 @@
+package decimal_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/decimal"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimal(t *testing.T) {
+	payments := []decimal.Payment{
+		{ID: 1, Price: 123456, Discount: pint32(125)},
+		{ID: 2, Price: -99},
+		{ID: 3, Price: math.MaxInt64, Discount: pint32(-9999)},
+		{ID: 4, Balance: decimal16("-170141183460469231731687303715884105728")},
+		{ID: 5, Balance: decimal16("99999999999999999999999999999999999999"), Total: bigInt("-12345678901234567890123")},
+	}
+
+	buf := write(t, payments, decimal.MaxPageSize(2))
+	assert.Equal(t, payments, read(t, buf))
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		name      string
+		typ       sch.Type
+		precision int32
+		scale     int32
+	}{
+		{name: "price", typ: sch.Type_INT64, precision: 18, scale: 2},
+		{name: "discount", typ: sch.Type_INT32, precision: 4, scale: 1},
+		{name: "balance", typ: sch.Type_FIXED_LEN_BYTE_ARRAY, precision: 38, scale: 4},
+		{name: "total", typ: sch.Type_FIXED_LEN_BYTE_ARRAY, precision: 38, scale: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var se *sch.SchemaElement
+			for _, x := range footer.Schema {
+				if x.Name == tc.name {
+					se = x
+				}
+			}
+
+			if !assert.NotNil(t, se) {
+				return
+			}
+
+			assert.Equal(t, tc.typ, se.GetType())
+			assert.Equal(t, sch.ConvertedType_DECIMAL, se.GetConvertedType())
+			assert.Equal(t, tc.precision, se.GetPrecision())
+			assert.Equal(t, tc.scale, se.GetScale())
+			assert.Equal(t, &sch.DecimalType{Precision: tc.precision, Scale: tc.scale}, se.GetLogicalType().GetDECIMAL())
+		})
+	}
+}
+
+func TestDecimalStats(t *testing.T) {
+	testCases := []struct {
+		name     string
+		column   string
+		in       []decimal.Payment
+		stored   [][]byte
+		min, max []byte
+	}{
+		{
+			name:   "fixed length",
+			column: "balance",
+			in:     []decimal.Payment{{Balance: decimal16("5")}, {Balance: decimal16("-12345678901234567890123")}, {Balance: decimal16("-1")}, {Balance: decimal16("12345678901234567890123")}},
+			min:    decimalBytes16("-12345678901234567890123"),
+			max:    decimalBytes16("12345678901234567890123"),
+		},
+		{
+			name:   "bigger than an int64",
+			column: "total",
+			in:     []decimal.Payment{{Total: bigInt("12345678901234567890123")}, {}, {Total: bigInt("-9223372036854775809")}},
+			stored: [][]byte{decimalBytes16("12345678901234567890123"), decimalBytes16("-9223372036854775809")},
+			min:    decimalBytes16("-9223372036854775809"),
+			max:    decimalBytes16("12345678901234567890123"),
+		},
+		{
+			name:   "precision",
+			column: "total",
+			in:     []decimal.Payment{{Total: bigInt("99999999999999999999999999999999999999")}, {Total: bigInt("-99999999999999999999999999999999999999")}},
+			stored: [][]byte{decimalBytes16("99999999999999999999999999999999999999"), decimalBytes16("-99999999999999999999999999999999999999")},
+			min:    decimalBytes16("-99999999999999999999999999999999999999"),
+			max:    decimalBytes16("99999999999999999999999999999999999999"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := write(t, tc.in)
+			assert.Equal(t, tc.in, read(t, buf))
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, col := range footer.RowGroups[0].Columns {
+				if col.MetaData.PathInSchema[0] == tc.column {
+					assert.Equal(t, tc.min, col.MetaData.Statistics.MinValue)
+					assert.Equal(t, tc.max, col.MetaData.Statistics.MaxValue)
+				}
+			}
+
+			if tc.stored == nil {
+				return
+			}
+
+			fr, err := parquet.NewFileReader(bytes.NewReader(buf))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c, err := fr.Column(0, tc.column)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			stored, err := c.ReadBytes()
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.stored, stored)
+			}
+		})
+	}
+
+	w, err := decimal.NewParquetWriter(&bytes.Buffer{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w.Add(decimal.Payment{Total: bigInt("-100000000000000000000000000000000000000")})
+	assert.EqualError(t, w.Write(), "field total: decimal -100000000000000000000000000000000000000 has more than 38 digits")
+}
+
+func TestDecimalFilter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := decimal.NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rowGroups := [][]decimal.Payment{
+		{{ID: 1, Balance: decimal16("-5")}, {ID: 2}},
+		{{ID: 3, Balance: decimal16("5")}, {ID: 4, Balance: decimal16("-1")}},
+	}
+	for _, rg := range rowGroups {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	r, err := decimal.NewParquetReader(bytes.NewReader(buf.Bytes()), decimal.Filter("balance", parquet.Greater, big.NewInt(0)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []decimal.Payment
+	for r.Next() {
+		var p decimal.Payment
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, rowGroups[1], out)
+}
+
+func TestDecimalRows(t *testing.T) {
+	less, err := decimal.Less("Balance", false)
+	if assert.NoError(t, err) {
+		assert.True(t, less(decimal.Payment{Balance: [16]byte{0xff}}, decimal.Payment{}))
+		assert.False(t, less(decimal.Payment{}, decimal.Payment{Balance: [16]byte{0xff}}))
+	}
+
+	assert.True(t, decimal.Payment{Total: big.NewInt(0)}.Equal(decimal.Payment{Total: parquet.DecimalInt([]byte{0, 0, 0, 0})}))
+	assert.False(t, decimal.Payment{Total: big.NewInt(1)}.Equal(decimal.Payment{Total: big.NewInt(-1)}))
+
+	p := decimal.Payment{ID: 1, Price: 5, Discount: pint32(-3), Balance: decimal16("123400"), Total: bigInt("-12345678901234567890123")}
+	out, err := decimal.FromMap(decimal.ToMap(p))
+	if assert.NoError(t, err) {
+		assert.Equal(t, p, out)
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	tooBig := "field total: decimal 100000000000000000000000000000000000000 has more than 38 digits"
+	testCases := []struct {
+		name string
+		bad  []int
+		opts []func(*decimal.ParquetWriter) error
+		errs map[int]string
+		err  string
+		rows []int
+	}{
+		{name: "no bad rows", rows: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "one bad row", bad: []int{3}, errs: map[int]string{3: tooBig}, rows: []int{0, 1, 2, 4, 5, 6, 7, 8, 9}},
+		{name: "two bad rows", bad: []int{3, 9}, errs: map[int]string{3: tooBig, 9: tooBig}, rows: []int{0, 1, 2, 4, 5, 6, 7, 8}},
+		{name: "fail fast", bad: []int{3, 9}, opts: []func(*decimal.ParquetWriter) error{decimal.FailFast}, errs: map[int]string{3: tooBig}, err: "row 3: " + tooBig},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := make([]decimal.Payment, 10)
+			for i := range in {
+				in[i] = decimal.Payment{ID: int32(i), Price: int64(i * 100)}
+			}
+			for _, i := range tc.bad {
+				in[i].Total = bigInt("100000000000000000000000000000000000000")
+			}
+
+			var buf bytes.Buffer
+			w, err := decimal.NewParquetWriter(&buf, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			errs, err := w.WriteAll(in)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tc.errs == nil {
+				assert.Nil(t, errs)
+			} else if assert.Len(t, errs, len(in)) {
+				for i, err := range errs {
+					if msg, ok := tc.errs[i]; ok {
+						assert.EqualError(t, err, msg)
+					} else {
+						assert.NoError(t, err, i)
+					}
+				}
+			}
+			assert.NoError(t, w.Close())
+
+			var expected []decimal.Payment
+			for _, i := range tc.rows {
+				expected = append(expected, in[i])
+			}
+			assert.Equal(t, expected, read(t, buf.Bytes()))
+		})
+	}
+}
+
+func write(t *testing.T, payments []decimal.Payment, opts ...func(*decimal.ParquetWriter) error) []byte {
+	var buf bytes.Buffer
+	w, err := decimal.NewParquetWriter(&buf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range payments {
+		w.Add(p)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func read(t *testing.T, data []byte) []decimal.Payment {
+	r, err := decimal.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []decimal.Payment
+	for r.Next() {
+		var p decimal.Payment
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	return out
+}
+
+func bigInt(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 10)
+	return v
+}
+
+// decimal16 returns the [16]byte of a decimal
+// column (s is its unscaled value).
+func decimal16(s string) [16]byte {
+	var out [16]byte
+	copy(out[:], decimalBytes16(s))
+	return out
+}
+
+func decimalBytes16(s string) []byte {
+	b, err := parquet.DecimalBytes(bigInt(s), 16)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func pint32(i int32) *int32 {
+	return &i
+}
@@ -0,0 +1,30 @@
+package evolve
+
+//go:generate parquetgen -input evolve.go -type V1 -package evolve -prefix V1 -output v1_generated.go
+//go:generate parquetgen -input evolve.go -type V2 -package evolve -prefix V2 -output v2_generated.go
+//go:generate parquetgen -input evolve.go -type V3 -package evolve -prefix V3 -output v3_generated.go
+
+// V1 is the first version of a user.
+type V1 struct {
+	ID   int32  `parquet:"id"`
+	Name string `parquet:"name"`
+}
+
+// V2 adds an optional email (and address) to V1, so it can read
+// the files written by V1.
+type V2 struct {
+	ID      int32   `parquet:"id"`
+	Name    string  `parquet:"name"`
+	Email   *string `parquet:"email"`
+	Address *struct {
+		City string `parquet:"city"`
+	} `parquet:"address"`
+}
+
+// V3 adds a required age to V1, so it can't read the files
+// written by V1.
+type V3 struct {
+	ID   int32  `parquet:"id"`
+	Name string `parquet:"name"`
+	Age  int32  `parquet:"age"`
+}
@@ -0,0 +1,124 @@
+package evolve_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/evolve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOlderFile(t *testing.T) {
+	users := []evolve.V1{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 3, Name: "carol"},
+	}
+
+	var buf bytes.Buffer
+	w, err := evolve.NewV1ParquetWriter(&buf, evolve.V1MaxPageSize(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, u := range users {
+		w.Add(u)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := evolve.NewV2ParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(3), r.Rows())
+
+	var out []evolve.V2
+	for r.Next() {
+		var u evolve.V2
+		r.Scan(&u)
+		out = append(out, u)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, []evolve.V2{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 3, Name: "carol"},
+	}, out)
+
+	_, err = evolve.NewV3ParquetReader(bytes.NewReader(buf.Bytes()))
+	assert.EqualError(t, err, "the file doesn't have a column for required field age")
+}
+
+func TestReadNewerFile(t *testing.T) {
+	email := "bob@example.com"
+	users := []evolve.V2{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob", Email: &email},
+		{ID: 3, Name: "carol"},
+	}
+	users[2].Address = &struct {
+		City string `parquet:"city"`
+	}{City: "paris"}
+
+	var buf bytes.Buffer
+	w, err := evolve.NewV2ParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, u := range users {
+		w.Add(u)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	// the column chunks that V1 doesn't have a field for are
+	// overwritten to show that they aren't read
+	data := buf.Bytes()
+	footer, err := parquet.ReadMetaData(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var extra int
+	for _, ch := range footer.RowGroups[0].Columns {
+		switch strings.Join(ch.MetaData.PathInSchema, ".") {
+		case "email", "address.city":
+			o := ch.MetaData.DataPageOffset
+			if ch.MetaData.DictionaryPageOffset != nil {
+				o = ch.MetaData.GetDictionaryPageOffset()
+			}
+			copy(data[o:o+ch.MetaData.TotalCompressedSize], bytes.Repeat([]byte{0xff}, int(ch.MetaData.TotalCompressedSize)))
+			extra++
+		}
+	}
+	assert.Equal(t, 2, extra)
+
+	r, err := evolve.NewV1ParquetReader(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []evolve.V1
+	for r.Next() {
+		var u evolve.V1
+		r.Scan(&u)
+		out = append(out, u)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, []evolve.V1{
+		{ID: 1, Name: "alice"},
+		{ID: 2, Name: "bob"},
+		{ID: 3, Name: "carol"},
+	}, out)
+
+	rows, err := r.ReadRowGroup(0)
+	assert.NoError(t, err)
+	assert.Equal(t, out, rows)
+
+	var names []string
+	_, err = r.ReadColumn("name", &names)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "carol"}, names)
+}
@@ -0,0 +1,11 @@
+package float16
+
+//go:generate parquetgen -input float16.go -type Reading -package float16 -output generated.go
+
+// Reading has uint16 fields that hold the bits
+// of half precision floats.
+type Reading struct {
+	ID     int32   `parquet:"id"`
+	Weight uint16  `parquet:"name=weight,logical=float16"`
+	Bias   *uint16 `parquet:"name=bias,logical=float16"`
+}
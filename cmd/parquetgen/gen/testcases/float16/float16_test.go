@@ -0,0 +1,179 @@
+package float16_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/float16"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// the bits of some half precision floats
+const (
+	one       = 0x3c00
+	two       = 0x4000
+	minusOne  = 0xbc00
+	minusTwo  = 0xc000
+	largest   = 0x7bff // 65504
+	minusZero = 0x8000
+	smallest  = 0x0001 // the smallest subnormal
+	nan       = 0x7e00
+)
+
+func TestFloat16(t *testing.T) {
+	testCases := []struct {
+		name     string
+		column   string
+		in       []float16.Reading
+		stored   [][]byte
+		min, max []byte
+	}{
+		{
+			name:   "required",
+			column: "weight",
+			in:     []float16.Reading{{Weight: one}, {Weight: minusTwo}, {Weight: largest}},
+			stored: [][]byte{{0x00, 0x3c}, {0x00, 0xc0}, {0xff, 0x7b}},
+			min:    []byte{0x00, 0xc0},
+			max:    []byte{0xff, 0x7b},
+		},
+		{
+			name:   "optional",
+			column: "bias",
+			in:     []float16.Reading{{}, {Bias: puint16(smallest)}, {Bias: puint16(minusZero)}},
+			stored: [][]byte{{0x01, 0x00}, {0x00, 0x80}},
+			min:    []byte{0x00, 0x80},
+			max:    []byte{0x01, 0x00},
+		},
+		{
+			name:   "NaN",
+			column: "weight",
+			in:     []float16.Reading{{Weight: nan}, {Weight: one}, {Weight: minusTwo}},
+			stored: [][]byte{{0x00, 0x7e}, {0x00, 0x3c}, {0x00, 0xc0}},
+			min:    []byte{0x00, 0xc0},
+			max:    []byte{0x00, 0x3c},
+		},
+		{
+			name:   "optional NaN",
+			column: "bias",
+			in:     []float16.Reading{{Bias: puint16(nan)}, {Bias: puint16(two)}, {}},
+			stored: [][]byte{{0x00, 0x7e}, {0x00, 0x40}},
+			min:    []byte{0x00, 0x40},
+			max:    []byte{0x00, 0x40},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := float16.NewParquetWriter(&buf)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, x := range tc.in {
+				w.Add(x)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			r, err := float16.NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []float16.Reading
+			for r.Next() {
+				var x float16.Reading
+				r.Scan(&x)
+				out = append(out, x)
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, tc.in, out)
+
+			rd := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var found bool
+			for _, se := range footer.Schema {
+				if se.Name != tc.column {
+					continue
+				}
+
+				found = true
+				assert.Equal(t, sch.Type_FIXED_LEN_BYTE_ARRAY, se.GetType())
+				assert.Equal(t, int32(2), se.GetTypeLength())
+				assert.Nil(t, se.ConvertedType)
+				assert.Equal(t, &sch.LogicalType{FLOAT16: &sch.Float16Type{}}, se.LogicalType)
+			}
+			assert.True(t, found, "missing %s column", tc.column)
+
+			// the stats are in the order of the floats, not
+			// their bits, and they leave out the NaNs
+			for _, col := range footer.RowGroups[0].Columns {
+				if col.MetaData.PathInSchema[0] != tc.column {
+					continue
+				}
+
+				assert.Equal(t, tc.min, col.MetaData.Statistics.MinValue)
+				assert.Equal(t, tc.max, col.MetaData.Statistics.MaxValue)
+
+				pages, err := parquet.PageHeadersAtOffset(rd, col.MetaData.DataPageOffset, col.MetaData.NumValues)
+				if assert.NoError(t, err) {
+					for _, ph := range pages {
+						assert.Equal(t, tc.min, ph.DataPageHeader.Statistics.MinValue, "page min")
+						assert.Equal(t, tc.max, ph.DataPageHeader.Statistics.MaxValue, "page max")
+					}
+				}
+			}
+
+			fr, err := parquet.NewFileReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c, err := fr.Column(0, tc.column)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			stored, err := c.ReadBytes()
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.stored, stored)
+			}
+		})
+	}
+}
+
+func TestFloat16Filter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := float16.NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w.Add(float16.Reading{ID: 1})
+	w.Add(float16.Reading{ID: 2, Weight: one})
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	// the weights are 0 and 1, which are more than -1 even
+	// though the bits of -1 (0xbc00) are a bigger uint16
+	r, err := float16.NewParquetReader(bytes.NewReader(buf.Bytes()), float16.Filter("Weight", parquet.Less, uint16(minusOne)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(0), r.Rows())
+	}
+
+	r, err = float16.NewParquetReader(bytes.NewReader(buf.Bytes()), float16.Filter("Weight", parquet.Greater, uint16(minusOne)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(2), r.Rows())
+	}
+}
+
+func puint16(i uint16) *uint16 {
+	return &i
+}
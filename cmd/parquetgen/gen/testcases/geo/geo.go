@@ -0,0 +1,11 @@
+package geo
+
+//go:generate parquetgen -input geo.go -type Place -package geo -output generated.go
+
+// Place has []byte fields that hold the WKB of
+// a geometry and a geography.
+type Place struct {
+	ID    int32  `parquet:"id"`
+	Shape []byte `parquet:"name=shape,logical=geometry,crs=OGC:CRS84,optional"`
+	Route []byte `parquet:"name=route,logical=geography"`
+}
@@ -0,0 +1,95 @@
+package geo_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/geo"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeometry(t *testing.T) {
+	crs := "OGC:CRS84"
+	testCases := []struct {
+		name     string
+		column   string
+		in       []geo.Place
+		expected *sch.LogicalType
+	}{
+		{
+			name:     "geometry",
+			column:   "shape",
+			in:       []geo.Place{{Shape: wkbPoint(1, 2)}, {}, {Shape: wkbPoint(-71.1, 42.3)}},
+			expected: &sch.LogicalType{GEOMETRY: &sch.GeometryType{Crs: &crs}},
+		},
+		{
+			name:     "geography",
+			column:   "route",
+			in:       []geo.Place{{Route: wkbPoint(0, 0)}, {Route: wkbPoint(179.9, -89.9)}},
+			expected: &sch.LogicalType{GEOGRAPHY: &sch.GeographyType{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := geo.NewParquetWriter(&buf)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, p := range tc.in {
+				w.Add(p)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			r, err := geo.NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []geo.Place
+			for r.Next() {
+				var p geo.Place
+				r.Scan(&p)
+				out = append(out, p)
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, tc.in, out)
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var found bool
+			for _, se := range footer.Schema {
+				if se.Name != tc.column {
+					continue
+				}
+
+				// the WKB is still a plain BYTE_ARRAY
+				found = true
+				assert.Equal(t, sch.Type_BYTE_ARRAY, se.GetType())
+				assert.Nil(t, se.ConvertedType)
+				assert.Equal(t, tc.expected, se.LogicalType)
+			}
+			assert.True(t, found, "missing %s column", tc.column)
+		})
+	}
+}
+
+// wkbPoint is the little endian WKB of a point.
+func wkbPoint(x, y float64) []byte {
+	b := make([]byte, 21)
+	b[0] = 1
+	binary.LittleEndian.PutUint32(b[1:], 1)
+	binary.LittleEndian.PutUint64(b[5:], math.Float64bits(x))
+	binary.LittleEndian.PutUint64(b[13:], math.Float64bits(y))
+	return b
+}
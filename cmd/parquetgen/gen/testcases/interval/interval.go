@@ -0,0 +1,13 @@
+package interval
+
+import "time"
+
+//go:generate parquetgen -input interval.go -type Task -package interval -output generated.go
+
+// Task has time.Duration fields, which are written
+// as INTERVAL columns.
+type Task struct {
+	ID      int32          `parquet:"id"`
+	Elapsed time.Duration  `parquet:"elapsed"`
+	Timeout *time.Duration `parquet:"timeout"`
+}
@@ -0,0 +1,95 @@
+package interval_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/interval"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterval(t *testing.T) {
+	tasks := []interval.Task{
+		{ID: 1, Elapsed: 23*time.Hour + 59*time.Minute + 59*time.Second + 999*time.Millisecond, Timeout: pduration(24 * time.Hour)},
+		{ID: 2, Elapsed: 25 * time.Hour},
+		{ID: 3, Elapsed: 48*time.Hour + time.Millisecond, Timeout: pduration(0)},
+	}
+
+	var buf bytes.Buffer
+	w, err := interval.NewParquetWriter(&buf, interval.MaxPageSize(2), interval.PageIndex)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, x := range tasks {
+		w.Add(x)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := interval.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []interval.Task
+	for r.Next() {
+		var x interval.Task
+		r.Scan(&x)
+		out = append(out, x)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, tasks, out)
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, column := range []string{"elapsed", "timeout"} {
+		t.Run(column, func(t *testing.T) {
+			for _, se := range footer.Schema {
+				if se.Name != column {
+					continue
+				}
+
+				assert.Equal(t, sch.Type_FIXED_LEN_BYTE_ARRAY, se.GetType())
+				assert.Equal(t, int32(12), se.GetTypeLength())
+				assert.Equal(t, sch.ConvertedType_INTERVAL, se.GetConvertedType())
+			}
+
+			// the order of an INTERVAL is undefined, so it has no min or max
+			for _, ch := range footer.RowGroups[0].Columns {
+				if ch.MetaData.PathInSchema[0] != column {
+					continue
+				}
+
+				assert.Nil(t, ch.MetaData.Statistics.MinValue)
+				assert.Nil(t, ch.MetaData.Statistics.MaxValue)
+				assert.Nil(t, ch.ColumnIndexOffset)
+			}
+		})
+	}
+}
+
+func TestNegativeInterval(t *testing.T) {
+	for x, msg := range map[*interval.Task]string{
+		{Elapsed: -time.Minute}:                        "field elapsed: negative duration -1m0s can't be stored as an INTERVAL",
+		{Timeout: pduration(-1500 * time.Millisecond)}: "field timeout: negative duration -1.5s can't be stored as an INTERVAL",
+	} {
+		w, err := interval.NewParquetWriter(&bytes.Buffer{})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		w.Add(*x)
+		assert.EqualError(t, w.Write(), msg)
+	}
+}
+
+func pduration(d time.Duration) *time.Duration {
+	return &d
+}
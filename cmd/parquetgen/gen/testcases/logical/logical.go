@@ -0,0 +1,12 @@
+package logical
+
+//go:generate parquetgen -input logical.go -type Record -package logical -output generated.go
+
+// Record has the string and []byte fields whose
+// logical types say what their values hold.
+type Record struct {
+	ID       int32   `parquet:"id"`
+	Status   *string `parquet:"name=status,logical=enum"`
+	Settings *string `parquet:"name=settings,logical=json"`
+	Profile  []byte  `parquet:"name=profile,logical=bson"`
+}
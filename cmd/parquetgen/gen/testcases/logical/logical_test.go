@@ -0,0 +1,79 @@
+package logical_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/logical"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogicalTypes(t *testing.T) {
+	records := []logical.Record{
+		{ID: 1, Status: pstring("active"), Settings: pstring(`{"theme":"dark"}`), Profile: []byte{0x05, 0x00, 0x00, 0x00, 0x00}},
+		{ID: 2},
+		{ID: 3, Status: pstring("closed"), Settings: pstring(`[1,2,3]`), Profile: []byte{0x0c, 0x00, 0x00, 0x00, 0x10, 0x61, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}},
+	}
+
+	var buf bytes.Buffer
+	w, err := logical.NewParquetWriter(&buf, logical.MaxPageSize(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, rec := range records {
+		w.Add(rec)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := logical.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []logical.Record
+	for r.Next() {
+		var rec logical.Record
+		r.Scan(&rec)
+		out = append(out, rec)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, records, out)
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		column  string
+		ct      sch.ConvertedType
+		logical *sch.LogicalType
+	}{
+		{column: "status", ct: sch.ConvertedType_ENUM, logical: &sch.LogicalType{ENUM: &sch.EnumType{}}},
+		{column: "settings", ct: sch.ConvertedType_JSON, logical: &sch.LogicalType{JSON: &sch.JsonType{}}},
+		{column: "profile", ct: sch.ConvertedType_BSON, logical: &sch.LogicalType{BSON: &sch.BsonType{}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.column, func(t *testing.T) {
+			for _, se := range footer.Schema {
+				if se.Name != tc.column {
+					continue
+				}
+
+				assert.Equal(t, sch.Type_BYTE_ARRAY, se.GetType())
+				assert.Equal(t, tc.ct, se.GetConvertedType())
+				assert.Equal(t, tc.logical, se.GetLogicalType())
+				return
+			}
+			t.Errorf("missing %s column", tc.column)
+		})
+	}
+}
+
+func pstring(s string) *string {
+	return &s
+}
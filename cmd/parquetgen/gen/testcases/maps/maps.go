@@ -0,0 +1,10 @@
+package maps
+
+//go:generate parquetgen -input maps.go -type Profile -package maps -output generated.go
+
+// Profile has map fields, which are written as MAP columns.
+type Profile struct {
+	ID         int32             `parquet:"id"`
+	Attributes map[string]string `parquet:"attributes"`
+	Ratings    map[int32]float64 `parquet:"ratings"`
+}
@@ -0,0 +1,143 @@
+package maps_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/maps"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaps(t *testing.T) {
+	rowGroups := [][]maps.Profile{
+		{
+			{ID: 1, Attributes: map[string]string{"b": "2", "a": "1", "c": "3"}, Ratings: map[int32]float64{5: 0.5}},
+			{ID: 2},
+			{ID: 3, Attributes: map[string]string{}, Ratings: map[int32]float64{-1: 1, 1: -1}},
+		},
+		{
+			{ID: 4, Ratings: map[int32]float64{}},
+			{ID: 5, Attributes: map[string]string{"x": ""}},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := maps.NewParquetWriter(&buf, maps.MaxPageSize(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var expected []maps.Profile
+	for _, rg := range rowGroups {
+		for _, p := range rg {
+			w.Add(p)
+			expected = append(expected, p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	r, err := maps.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []maps.Profile
+	for r.Next() {
+		var p maps.Profile
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, expected, out)
+}
+
+func TestMapSchema(t *testing.T) {
+	schema := maps.Schema()
+
+	// the map and key_value groups of attributes and ratings
+	groups := 4
+	if !assert.Equal(t, len(maps.Fields(0, 0))+groups+1, len(schema)) {
+		return
+	}
+
+	for i, se := range schema {
+		if se.Name != "attributes" {
+			continue
+		}
+
+		if !assert.True(t, len(schema) > i+3) {
+			return
+		}
+
+		assert.Equal(t, sch.FieldRepetitionType_OPTIONAL, se.GetRepetitionType())
+		assert.Equal(t, sch.ConvertedType_MAP, se.GetConvertedType())
+		assert.NotNil(t, se.GetLogicalType().GetMAP())
+		assert.Equal(t, int32(1), se.GetNumChildren())
+
+		kv, key, val := schema[i+1], schema[i+2], schema[i+3]
+		assert.Equal(t, "key_value", kv.Name)
+		assert.Equal(t, sch.FieldRepetitionType_REPEATED, kv.GetRepetitionType())
+		assert.Equal(t, int32(2), kv.GetNumChildren())
+		assert.Equal(t, "key", key.Name)
+		assert.Equal(t, sch.FieldRepetitionType_REQUIRED, key.GetRepetitionType())
+		assert.Equal(t, "value", val.Name)
+		assert.Equal(t, sch.FieldRepetitionType_REQUIRED, val.GetRepetitionType())
+		return
+	}
+	t.Error("missing attributes column")
+}
+
+func TestMapRows(t *testing.T) {
+	p := maps.Profile{ID: 1, Attributes: map[string]string{"a": "b"}, Ratings: map[int32]float64{1: 2.5}}
+
+	m := maps.ToMap(p)
+	assert.Equal(t, map[string]string{"a": "b"}, m["attributes"])
+	assert.Equal(t, map[int32]float64{1: 2.5}, m["ratings"])
+	for _, k := range []string{"attributes.key_value.key", "attributes.key_value.value"} {
+		_, ok := m[k]
+		assert.False(t, ok, k)
+	}
+
+	out, err := maps.FromMap(m)
+	if assert.NoError(t, err) {
+		assert.Equal(t, p, out)
+	}
+
+	assert.True(t, p.Equal(maps.Profile{ID: 1, Attributes: map[string]string{"a": "b"}, Ratings: map[int32]float64{1: 2.5}}))
+	assert.False(t, p.Equal(maps.Profile{ID: 1, Attributes: map[string]string{"a": "c"}, Ratings: map[int32]float64{1: 2.5}}))
+}
+
+func TestMapCSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := maps.NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w.Add(maps.Profile{ID: 1, Ratings: map[int32]float64{2: 0.5, 1: 1.5}})
+	w.Add(maps.Profile{ID: 2})
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := maps.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out bytes.Buffer
+	if !assert.NoError(t, r.WriteCSV(&out, "id", "ratings.key_value.key", "ratings.key_value.value")) {
+		return
+	}
+
+	recs, err := csv.NewReader(&out).ReadAll()
+	if assert.NoError(t, err) {
+		assert.Equal(t, [][]string{
+			{"id", "ratings.key_value.key", "ratings.key_value.value"},
+			{"1", "[1,2]", "[1.5,0.5]"},
+			{"2", "", ""},
+		}, recs)
+	}
+}
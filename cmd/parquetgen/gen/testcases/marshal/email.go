@@ -0,0 +1,11 @@
+package marshal
+
+import "strings"
+
+func (e Email) MarshalParquet() string {
+	return strings.ToLower(string(e))
+}
+
+func (e *Email) UnmarshalParquet(v string) {
+	*e = Email(v)
+}
@@ -0,0 +1,41 @@
+package marshal
+
+import "time"
+
+//go:generate parquetgen -input marshal.go -type Invoice -package marshal -output generated.go
+
+type Invoice struct {
+	ID      int32   `parquet:"id"`
+	Price   Money   `parquet:"price"`
+	Fee     *Money  `parquet:"fee"`
+	Refunds []Money `parquet:"refunds"`
+	Contact Email   `parquet:"contact"`
+	Lines   []Line  `parquet:"lines"`
+	Due     Date    `parquet:"name=due,logical=date"`
+	Paid    *Date   `parquet:"name=paid,logical=date"`
+}
+
+type Line struct {
+	SKU    string `parquet:"sku"`
+	Amount *Money `parquet:"amount"`
+}
+
+// Money is written as its number of cents.
+type Money struct {
+	Dollars int64
+	Cents   int64
+}
+
+func (m Money) MarshalParquet() int64 {
+	return m.Dollars*100 + m.Cents
+}
+
+func (m *Money) UnmarshalParquet(v int64) {
+	m.Dollars, m.Cents = v/100, v%100
+}
+
+// Email is written in lower case (its methods are in email.go).
+type Email string
+
+// Date is written like the time.Time it's declared as.
+type Date time.Time
@@ -0,0 +1,158 @@
+package marshal_test
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/marshal"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	invoices := []marshal.Invoice{
+		{ID: 1, Price: marshal.Money{Dollars: 12, Cents: 50}, Fee: &marshal.Money{Cents: 99}, Refunds: []marshal.Money{{Dollars: 1}, {Cents: 5}}, Contact: "alice@example.com", Lines: []marshal.Line{{SKU: "a1", Amount: &marshal.Money{Dollars: 12, Cents: 50}}, {SKU: "b2"}}},
+		{ID: 2, Price: marshal.Money{Dollars: 3}, Contact: "bob@example.com"},
+		{ID: 3, Price: marshal.Money{Dollars: 7, Cents: 1}, Fee: &marshal.Money{}, Contact: "carol@example.com"},
+	}
+
+	less, err := marshal.Less("price", false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	sort.Slice(invoices, func(i, j int) bool { return less(invoices[i], invoices[j]) })
+	assert.Equal(t, []int32{2, 3, 1}, []int32{invoices[0].ID, invoices[1].ID, invoices[2].ID})
+
+	var buf bytes.Buffer
+	w, err := marshal.NewParquetWriter(&buf, marshal.SortedBy("price", false))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, x := range invoices {
+		w.Add(x)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := marshal.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []marshal.Invoice
+	for r.Next() {
+		var x marshal.Invoice
+		r.Scan(&x)
+		out = append(out, x)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, invoices, out)
+
+	// the values are written as the int64s that Money marshals to
+	var prices []int64
+	_, err = r.ReadColumn("price", &prices)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{300, 701, 1250}, prices)
+
+	types := map[string]sch.Type{}
+	for _, se := range marshal.Schema() {
+		if se.Type != nil {
+			types[se.Name] = *se.Type
+		}
+	}
+	assert.Equal(t, sch.Type_INT64, types["price"])
+	assert.Equal(t, sch.Type_INT64, types["fee"])
+	assert.Equal(t, sch.Type_INT64, types["refunds"])
+	assert.Equal(t, sch.Type_INT64, types["amount"])
+	assert.Equal(t, sch.Type_BYTE_ARRAY, types["contact"])
+
+	m := marshal.ToMap(invoices[2])
+	assert.Equal(t, int64(1250), m["price"])
+	assert.Equal(t, []interface{}{int64(100), int64(5)}, m["refunds"])
+
+	x, err := marshal.FromMap(m)
+	assert.NoError(t, err)
+	assert.Equal(t, invoices[2], x)
+}
+
+func TestMarshalLowerCase(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := marshal.NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+	w.Add(marshal.Invoice{ID: 1, Contact: "Alice@Example.com"})
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := marshal.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var x marshal.Invoice
+	assert.True(t, r.Next())
+	r.Scan(&x)
+	assert.NoError(t, r.Error())
+	assert.Equal(t, marshal.Email("alice@example.com"), x.Contact)
+}
+
+func TestNamedTime(t *testing.T) {
+	due := marshal.Date(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC))
+	paid := marshal.Date(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+	invoices := []marshal.Invoice{
+		{ID: 1, Due: due, Paid: &paid},
+		{ID: 2, Due: paid},
+	}
+
+	less, err := marshal.Less("due", false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, less(invoices[1], invoices[0]))
+
+	var buf bytes.Buffer
+	w, err := marshal.NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, x := range invoices {
+		w.Add(x)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := marshal.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []marshal.Invoice
+	for r.Next() {
+		var x marshal.Invoice
+		r.Scan(&x)
+		out = append(out, x)
+	}
+	assert.NoError(t, r.Error())
+	if !assert.Len(t, out, 2) {
+		return
+	}
+
+	for i, x := range out {
+		assert.True(t, time.Time(invoices[i].Due).Equal(time.Time(x.Due)))
+		assert.True(t, invoices[i].Equal(x))
+	}
+	assert.True(t, time.Time(paid).Equal(time.Time(*out[0].Paid)))
+	assert.Nil(t, out[1].Paid)
+
+	types := map[string]sch.Type{}
+	for _, se := range marshal.Schema() {
+		if se.Type != nil {
+			types[se.Name] = *se.Type
+		}
+	}
+	assert.Equal(t, sch.Type_INT32, types["due"])
+	assert.Equal(t, sch.Type_INT32, types["paid"])
+}
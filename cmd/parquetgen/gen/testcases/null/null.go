@@ -0,0 +1,9 @@
+package null
+
+//go:generate parquetgen -input null.go -type Legacy -package null -output generated.go
+
+// Legacy has a column that is always null.
+type Legacy struct {
+	ID         int32     `parquet:"id"`
+	Deprecated *struct{} `parquet:"name=deprecated,logical=null"`
+}
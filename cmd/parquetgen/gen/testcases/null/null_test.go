@@ -0,0 +1,81 @@
+package null_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/null"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullColumn(t *testing.T) {
+	testCases := []struct {
+		name string
+		rows []*struct{}
+	}{
+		{name: "nil", rows: []*struct{}{nil, nil}},
+		{name: "not nil", rows: []*struct{}{{}, {}}},
+		{name: "mixed", rows: []*struct{}{nil, {}, nil}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := null.NewParquetWriter(&buf)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for i, x := range tc.rows {
+				w.Add(null.Legacy{ID: int32(i), Deprecated: x})
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, se := range footer.Schema {
+				if se.Name == "deprecated" {
+					assert.Equal(t, sch.Type_INT32, se.GetType())
+					assert.NotNil(t, se.GetLogicalType().GetUNKNOWN())
+				}
+			}
+
+			for _, col := range footer.RowGroups[0].Columns {
+				if col.MetaData.PathInSchema[0] == "deprecated" {
+					assert.Equal(t, int64(len(tc.rows)), col.MetaData.Statistics.GetNullCount())
+				}
+			}
+
+			r, err := null.NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var vals []struct{}
+			lvls, err := r.ReadColumn("deprecated", &vals)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, make([]uint8, len(tc.rows)), lvls.Defs)
+			assert.Empty(t, vals)
+
+			var i int
+			for r.Next() {
+				var l null.Legacy
+				r.Scan(&l)
+				assert.Equal(t, null.Legacy{ID: int32(i)}, l)
+				i++
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, len(tc.rows), i)
+		})
+	}
+}
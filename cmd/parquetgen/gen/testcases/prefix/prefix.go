@@ -0,0 +1,21 @@
+package prefix
+
+//go:generate parquetgen -input prefix.go -type Person -package prefix -prefix Person -output person_generated.go
+//go:generate parquetgen -input prefix.go -type Pet -package prefix -prefix Pet -output pet_generated.go
+
+type Person struct {
+	ID   int32  `parquet:"id"`
+	Name string `parquet:"name"`
+	Age  *int32 `parquet:"age"`
+	Pets []Pet  `parquet:"pets"`
+	Home *struct {
+		City    string   `parquet:"city"`
+		Streets []string `parquet:"streets"`
+	} `parquet:"home"`
+}
+
+type Pet struct {
+	Name    string  `parquet:"name"`
+	Species *string `parquet:"species"`
+	Weight  float64 `parquet:"weight"`
+}
@@ -0,0 +1,78 @@
+package prefix_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/prefix"
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(s string) *string { return &s }
+func int32Ptr(i int32) *int32 { return &i }
+
+func TestPrefix(t *testing.T) {
+	people := []prefix.Person{
+		{ID: 1, Name: "alice", Age: int32Ptr(30), Pets: []prefix.Pet{{Name: "rex", Species: strPtr("dog"), Weight: 20.5}}},
+		{ID: 2, Name: "bob"},
+	}
+	people[0].Home = &struct {
+		City    string   `parquet:"city"`
+		Streets []string `parquet:"streets"`
+	}{City: "paris", Streets: []string{"rue de rivoli"}}
+
+	pets := []prefix.Pet{
+		{Name: "tom", Species: strPtr("cat"), Weight: 4.2},
+		{Name: "nemo", Weight: 0.1},
+	}
+
+	var pbuf bytes.Buffer
+	pw, err := prefix.NewPersonParquetWriter(&pbuf, prefix.PersonSnappy)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, p := range people {
+		pw.Add(p)
+	}
+	assert.NoError(t, pw.Write())
+	assert.NoError(t, pw.Close())
+
+	var abuf bytes.Buffer
+	aw, err := prefix.NewPetParquetWriter(&abuf, prefix.PetSnappy)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, p := range pets {
+		aw.Add(p)
+	}
+	assert.NoError(t, aw.Write())
+	assert.NoError(t, aw.Close())
+
+	pr, err := prefix.NewPersonParquetReader(bytes.NewReader(pbuf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var outPeople []prefix.Person
+	for pr.Next() {
+		var p prefix.Person
+		pr.Scan(&p)
+		outPeople = append(outPeople, p)
+	}
+	assert.NoError(t, pr.Error())
+	assert.Equal(t, people, outPeople)
+
+	ar, err := prefix.NewPetParquetReader(bytes.NewReader(abuf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var outPets []prefix.Pet
+	for ar.Next() {
+		var p prefix.Pet
+		ar.Scan(&p)
+		outPets = append(outPets, p)
+	}
+	assert.NoError(t, ar.Error())
+	assert.Equal(t, pets, outPets)
+}
@@ -0,0 +1,16 @@
+package repeated
+
+//go:generate parquetgen -input repeated.go -type Message -package repeated -output generated.go
+
+// Message has repeated BYTE_ARRAY columns, each
+// element of which is a byte array value.
+type Message struct {
+	ID          int32     `parquet:"id"`
+	Attachments [][]byte  `parquet:"attachments"`
+	Hashes      [][4]byte `parquet:"hashes"`
+	Threads     []Thread  `parquet:"threads"`
+}
+
+type Thread struct {
+	Blobs [][]byte `parquet:"blobs"`
+}
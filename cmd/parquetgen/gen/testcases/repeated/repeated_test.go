@@ -0,0 +1,85 @@
+package repeated_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/repeated"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeatedByteArrays(t *testing.T) {
+	messages := []repeated.Message{
+		{ID: 1},
+		{ID: 2, Attachments: [][]byte{[]byte("a")}, Hashes: [][4]byte{{1, 2, 3, 4}}},
+		{ID: 3, Attachments: [][]byte{[]byte("bb"), {}, []byte("ccc")}, Threads: []repeated.Thread{{}, {Blobs: [][]byte{[]byte("x"), []byte("yz")}}}},
+	}
+
+	var buf bytes.Buffer
+	w, err := repeated.NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, m := range messages {
+		w.Add(m)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	for _, se := range repeated.Schema() {
+		if se.Name == "attachments" || se.Name == "blobs" {
+			assert.Equal(t, sch.Type_BYTE_ARRAY, se.GetType(), se.Name)
+			assert.Equal(t, sch.FieldRepetitionType_REPEATED, se.GetRepetitionType(), se.Name)
+		}
+	}
+
+	r, err := repeated.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []repeated.Message
+	for r.Next() {
+		var m repeated.Message
+		r.Scan(&m)
+		out = append(out, m)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, messages, out)
+
+	testCases := []struct {
+		column string
+		vals   [][]byte
+		defs   []uint8
+		reps   []uint8
+	}{
+		{
+			// zero, one and three attachments (the
+			// second of which is empty, but not null)
+			column: "attachments",
+			vals:   [][]byte{[]byte("a"), []byte("bb"), {}, []byte("ccc")},
+			defs:   []uint8{0, 1, 1, 1, 1},
+			reps:   []uint8{0, 0, 0, 1, 1},
+		},
+		{
+			column: "threads.blobs",
+			vals:   [][]byte{[]byte("x"), []byte("yz")},
+			defs:   []uint8{0, 0, 1, 2, 2},
+			reps:   []uint8{0, 0, 0, 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.column, func(t *testing.T) {
+			var vals [][]byte
+			levels, err := r.ReadColumn(tc.column, &vals)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.vals, vals)
+			assert.Equal(t, tc.defs, levels.Defs)
+			assert.Equal(t, tc.reps, levels.Reps)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package timeofday
+
+import "time"
+
+//go:generate parquetgen -input timeofday.go -type Shop -package timeofday -output generated.go
+
+// Shop has a time of day field for each unit.
+type Shop struct {
+	ID     int32          `parquet:"id"`
+	Opens  time.Duration  `parquet:"name=opens,logical=time,unit=millis"`
+	Closes *time.Duration `parquet:"name=closes,logical=time,unit=micros"`
+	Alarm  *time.Duration `parquet:"name=alarm,logical=time,unit=nanos"`
+}
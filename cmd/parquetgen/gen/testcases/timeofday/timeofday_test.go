@@ -0,0 +1,172 @@
+package timeofday_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/timeofday"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeOfDay(t *testing.T) {
+	testCases := []struct {
+		name     string
+		column   string
+		in       timeofday.Shop
+		expected timeofday.Shop
+		unit     *sch.TimeUnit
+		typ      sch.Type
+		ct       *sch.ConvertedType
+		stored   int64
+	}{
+		{
+			name:     "millis",
+			column:   "opens",
+			in:       timeofday.Shop{Opens: 9*time.Hour + 30*time.Minute + 1500*time.Microsecond},
+			expected: timeofday.Shop{Opens: 9*time.Hour + 30*time.Minute + time.Millisecond},
+			unit:     &sch.TimeUnit{MILLIS: sch.NewMilliSeconds()},
+			typ:      sch.Type_INT32,
+			ct:       sch.ConvertedTypePtr(sch.ConvertedType_TIME_MILLIS),
+			stored:   34200001,
+		},
+		{
+			name:     "micros",
+			column:   "closes",
+			in:       timeofday.Shop{Closes: pduration(17*time.Hour + 1500*time.Nanosecond)},
+			expected: timeofday.Shop{Closes: pduration(17*time.Hour + time.Microsecond)},
+			unit:     &sch.TimeUnit{MICROS: sch.NewMicroSeconds()},
+			typ:      sch.Type_INT64,
+			ct:       sch.ConvertedTypePtr(sch.ConvertedType_TIME_MICROS),
+			stored:   61200000001,
+		},
+		{
+			name:     "nanos",
+			column:   "alarm",
+			in:       timeofday.Shop{Alarm: pduration(23*time.Hour + 59*time.Minute + 59*time.Second + 999999999)},
+			expected: timeofday.Shop{Alarm: pduration(23*time.Hour + 59*time.Minute + 59*time.Second + 999999999)},
+			unit:     &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+			typ:      sch.Type_INT64,
+			stored:   86399999999999,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := write(t, []timeofday.Shop{tc.in})
+			assert.Equal(t, []timeofday.Shop{tc.expected}, read(t, buf))
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var found bool
+			for _, se := range footer.Schema {
+				if se.Name != tc.column {
+					continue
+				}
+
+				found = true
+				assert.Equal(t, tc.typ, se.GetType())
+				assert.Equal(t, tc.ct, se.ConvertedType)
+				assert.Equal(t, &sch.TimeType{IsAdjustedToUTC: true, Unit: tc.unit}, se.GetLogicalType().GetTIME())
+			}
+			assert.True(t, found, "missing %s column", tc.column)
+
+			fr, err := parquet.NewFileReader(bytes.NewReader(buf))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			c, err := fr.Column(0, tc.column)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var stored int64
+			if tc.typ == sch.Type_INT32 {
+				vals, err := c.ReadInt32()
+				if assert.NoError(t, err) && assert.Equal(t, 1, len(vals)) {
+					stored = int64(vals[0])
+				}
+			} else {
+				vals, err := c.ReadInt64()
+				if assert.NoError(t, err) && assert.Equal(t, 1, len(vals)) {
+					stored = vals[0]
+				}
+			}
+			assert.Equal(t, tc.stored, stored)
+		})
+	}
+}
+
+func TestTimeOfDayPages(t *testing.T) {
+	shops := []timeofday.Shop{
+		{ID: 1, Opens: 9*time.Hour + 30*time.Minute, Closes: pduration(17 * time.Hour), Alarm: pduration(6*time.Hour + time.Nanosecond)},
+		{ID: 2},
+		{ID: 3, Opens: 23*time.Hour + 59*time.Minute + 59*time.Second + 999*time.Millisecond, Closes: pduration(time.Microsecond)},
+	}
+
+	assert.Equal(t, shops, read(t, write(t, shops, timeofday.MaxPageSize(2))))
+}
+
+func TestTimeOfDayFilter(t *testing.T) {
+	buf := write(t, []timeofday.Shop{{ID: 1}, {ID: 2}})
+
+	testCases := []struct {
+		name  string
+		value time.Duration
+		rows  int
+	}{
+		// the opens column is in millis, so this is 0
+		{name: "truncated", value: 500 * time.Microsecond, rows: 2},
+		{name: "after", value: time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := timeofday.NewParquetReader(bytes.NewReader(buf), timeofday.Filter("Opens", parquet.Equal, tc.value))
+			if assert.NoError(t, err) {
+				assert.Equal(t, int64(tc.rows), r.Rows())
+			}
+		})
+	}
+}
+
+func write(t *testing.T, shops []timeofday.Shop, opts ...func(*timeofday.ParquetWriter) error) []byte {
+	var buf bytes.Buffer
+	w, err := timeofday.NewParquetWriter(&buf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range shops {
+		w.Add(s)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func read(t *testing.T, data []byte) []timeofday.Shop {
+	r, err := timeofday.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []timeofday.Shop
+	for r.Next() {
+		var s timeofday.Shop
+		r.Scan(&s)
+		out = append(out, s)
+	}
+	assert.NoError(t, r.Error())
+	return out
+}
+
+func pduration(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,13 @@
+package timestamp
+
+import "time"
+
+//go:generate parquetgen -input timestamp.go -type Log -package timestamp -output generated.go
+
+// Log has a timestamp field for each unit.
+type Log struct {
+	ID       int32      `parquet:"id"`
+	Logged   time.Time  `parquet:"name=logged,logical=timestamp,unit=millis"`
+	Started  time.Time  `parquet:"name=started,logical=timestamp,unit=micros"`
+	Finished *time.Time `parquet:"name=finished,logical=timestamp,unit=nanos"`
+}
@@ -0,0 +1,153 @@
+package timestamp_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/timestamp"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestamps(t *testing.T) {
+	logs := []timestamp.Log{
+		{ID: 1, Logged: time.Date(2021, 3, 4, 5, 6, 7, 8000000, time.UTC), Started: time.Date(2021, 3, 4, 5, 6, 7, 8009000, time.UTC), Finished: ptime(time.Date(2021, 3, 4, 5, 6, 7, 8009010, time.UTC))},
+		{ID: 2, Started: time.Date(1969, 12, 31, 23, 59, 59, 999999000, time.UTC)},
+		{ID: 3, Finished: ptime(time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC))},
+	}
+
+	assert.Equal(t, logs, read(t, write(t, logs, timestamp.MaxPageSize(2))))
+}
+
+func TestTimestampUnits(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 123456789, time.UTC)
+	buf := write(t, []timestamp.Log{{Logged: ts, Started: ts, Finished: &ts}})
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		name     string
+		ct       *sch.ConvertedType
+		unit     *sch.TimeUnit
+		expected int64
+	}{
+		{
+			name:     "logged",
+			ct:       sch.ConvertedTypePtr(sch.ConvertedType_TIMESTAMP_MILLIS),
+			unit:     &sch.TimeUnit{MILLIS: sch.NewMilliSeconds()},
+			expected: ts.UnixNano() / int64(time.Millisecond),
+		},
+		{
+			name:     "started",
+			ct:       sch.ConvertedTypePtr(sch.ConvertedType_TIMESTAMP_MICROS),
+			unit:     &sch.TimeUnit{MICROS: sch.NewMicroSeconds()},
+			expected: ts.UnixNano() / int64(time.Microsecond),
+		},
+		{
+			name:     "finished",
+			unit:     &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+			expected: ts.UnixNano(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var se *sch.SchemaElement
+			for _, x := range footer.Schema {
+				if x.Name == tc.name {
+					se = x
+				}
+			}
+
+			if !assert.NotNil(t, se) {
+				return
+			}
+
+			assert.Equal(t, sch.Type_INT64, se.GetType())
+			assert.Equal(t, tc.ct, se.ConvertedType)
+			assert.Equal(t, &sch.TimestampType{IsAdjustedToUTC: true, Unit: tc.unit}, se.GetLogicalType().GetTIMESTAMP())
+
+			expected := make([]byte, 8)
+			binary.LittleEndian.PutUint64(expected, uint64(tc.expected))
+			for _, col := range footer.RowGroups[0].Columns {
+				if col.MetaData.PathInSchema[0] == tc.name {
+					assert.Equal(t, expected, col.MetaData.Statistics.MinValue)
+				}
+			}
+		})
+	}
+}
+
+func TestTimestampRange(t *testing.T) {
+	// millis and micros can store times that UnixNano can't
+	logs := []timestamp.Log{
+		{Logged: time.Date(1000, 1, 2, 3, 4, 5, 6000000, time.UTC), Started: time.Date(1000, 1, 2, 3, 4, 5, 6000, time.UTC)},
+		{Logged: time.Date(3000, 1, 2, 3, 4, 5, 6000000, time.UTC), Started: time.Date(3000, 1, 2, 3, 4, 5, 6000, time.UTC)},
+	}
+
+	assert.Equal(t, logs, read(t, write(t, logs)))
+
+	// but nanos can't
+	for _, ts := range []time.Time{
+		time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		w, err := timestamp.NewParquetWriter(&bytes.Buffer{})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		w.Add(timestamp.Log{Finished: &ts})
+		assert.EqualError(t, w.Write(), fmt.Sprintf("field finished: time %s is out of range for a TIMESTAMP(NANOS)", ts.Format(time.RFC3339Nano)))
+	}
+
+	n, err := parquet.TimestampNanos(time.Unix(0, math.MaxInt64))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64), n)
+
+	_, err = timestamp.NewParquetReader(bytes.NewReader(write(t, logs)), timestamp.Filter("Finished", parquet.Less, time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.EqualError(t, err, "filter on column finished: time 2300-01-01T00:00:00Z is out of range for a TIMESTAMP(NANOS)")
+}
+
+func write(t *testing.T, logs []timestamp.Log, opts ...func(*timestamp.ParquetWriter) error) []byte {
+	var buf bytes.Buffer
+	w, err := timestamp.NewParquetWriter(&buf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range logs {
+		w.Add(l)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func read(t *testing.T, data []byte) []timestamp.Log {
+	r, err := timestamp.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []timestamp.Log
+	for r.Next() {
+		var l timestamp.Log
+		r.Scan(&l)
+		out = append(out, l)
+	}
+	assert.NoError(t, r.Error())
+	return out
+}
+
+func ptime(t time.Time) *time.Time {
+	return &t
+}
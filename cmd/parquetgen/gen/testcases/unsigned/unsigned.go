@@ -0,0 +1,11 @@
+package unsigned
+
+//go:generate parquetgen -input unsigned.go -type Device -package unsigned -output generated.go
+
+// Device has the small unsigned ints that are
+// stored as annotated INT32 columns.
+type Device struct {
+	ID    int32   `parquet:"id"`
+	Flags uint8   `parquet:"flags"`
+	Port  *uint16 `parquet:"port"`
+}
@@ -0,0 +1,78 @@
+package unsigned_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/gen/testcases/unsigned"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsigned(t *testing.T) {
+	devices := []unsigned.Device{
+		{ID: 1, Flags: math.MaxUint8, Port: puint16(math.MaxUint16)},
+		{ID: 2, Flags: 1},
+		{ID: 3, Port: puint16(0)},
+	}
+
+	var buf bytes.Buffer
+	w, err := unsigned.NewParquetWriter(&buf, unsigned.MaxPageSize(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, d := range devices {
+		w.Add(d)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := unsigned.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []unsigned.Device
+	for r.Next() {
+		var d unsigned.Device
+		r.Scan(&d)
+		out = append(out, d)
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, devices, out)
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		column string
+		ct     sch.ConvertedType
+	}{
+		{column: "flags", ct: sch.ConvertedType_UINT_8},
+		{column: "port", ct: sch.ConvertedType_UINT_16},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.column, func(t *testing.T) {
+			for _, se := range footer.Schema {
+				if se.Name != tc.column {
+					continue
+				}
+
+				assert.Equal(t, sch.Type_INT32, se.GetType())
+				assert.Equal(t, tc.ct, se.GetConvertedType())
+				return
+			}
+			t.Errorf("missing %s column", tc.column)
+		})
+	}
+}
+
+func puint16(i uint16) *uint16 {
+	return &i
+}
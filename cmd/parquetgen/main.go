@@ -3,12 +3,14 @@ package main
 import (
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io"
 	"log"
 	"os"
 
 	"github.com/parsyl/parquet"
 	"github.com/parsyl/parquet/cmd/parquetgen/gen"
+	"github.com/parsyl/parquet/cmd/parquetgen/parse"
 	sch "github.com/parsyl/parquet/schema"
 )
 
@@ -20,9 +22,14 @@ var (
 	imp          = flag.String("import", "", "import statement of -type if it doesn't live in -package")
 	pth          = flag.String("input", "", "path to the go file that defines -type")
 	outPth       = flag.String("output", "parquet.go", "name of the file that is produced, defaults to parquet.go")
+	prefix       = flag.String("prefix", "", "prefix for the names of the generated types and functions (like -prefix Person for PersonParquetWriter), so the code for more than one -type can live in -package")
 	ignore       = flag.Bool("ignore", true, "ignore unsupported fields in -type, otherwise log.Fatal is called when an unsupported type is encountered")
+	strict       = flag.Bool("strict", false, "exit non-zero, listing every unsupported field in -type, instead of ignoring them (the same as -ignore=false)")
 	parq         = flag.String("parquet", "", "path to a parquet file (if you are generating code based on an existing parquet file or printing the file metadata or page headers)")
 	structOutPth = flag.String("struct-output", "generated_struct.go", "name of the file that is produced, defaults to parquet.go")
+	schemaHash   = flag.Bool("schemahash", false, "print a hash of the columns of -type (which doesn't change when its fields are reordered) and exit")
+	showSchema   = flag.Bool("schema", false, "print the parquet schema of -type (in the same format as parquet-tools schema) and exit without generating any code")
+	nameStrategy = flag.String("name-strategy", "", "how the column names of the fields of -type that aren't named by their tags are made from the field names (snake for snake_case), by default the field name is used")
 )
 
 func main() {
@@ -32,15 +39,32 @@ func main() {
 		log.Fatal("choose -parquet or -input, but not both")
 	}
 
+	if *strict {
+		*ignore = false
+	}
+
+	var opts []func(*parse.Options)
+	if *nameStrategy != "" {
+		opt, ok := parse.NameStrategies[*nameStrategy]
+		if !ok {
+			log.Fatalf("unknown -name-strategy %s", *nameStrategy)
+		}
+		opts = append(opts, opt)
+	}
+
 	var err error
 	if *metadata {
 		readFooter()
 	} else if *pageheaders {
 		readPageHeaders()
+	} else if *schemaHash {
+		err = printSchemaHash(opts)
+	} else if *showSchema {
+		err = printSchema(opts)
 	} else if *parq == "" {
-		err = gen.FromStruct(*pth, *outPth, *typ, *pkg, *imp, *ignore)
+		err = gen.FromStruct(*pth, *outPth, *typ, *pkg, *imp, *prefix, *ignore, opts...)
 	} else {
-		err = gen.FromParquet(*parq, *structOutPth, *outPth, *typ, *pkg, *imp, *ignore)
+		err = gen.FromParquet(*parq, *structOutPth, *outPth, *typ, *pkg, *imp, *prefix, *ignore)
 	}
 
 	if err != nil {
@@ -48,6 +72,33 @@ func main() {
 	}
 }
 
+func printSchemaHash(opts []func(*parse.Options)) error {
+	result, err := parse.Fields(*typ, *pth, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := result.Err(); err != nil && !*ignore {
+		return fmt.Errorf("not printing the schema hash, %s has unsupported fields:\n%s", *typ, err)
+	}
+
+	fmt.Println(parse.SchemaHash(result.Parent.Fields()))
+	return nil
+}
+
+func printSchema(opts []func(*parse.Options)) error {
+	result, err := parse.Fields(*typ, *pth, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := result.Err(); err != nil && !*ignore {
+		return fmt.Errorf("not printing the schema, %s has unsupported fields:\n%s", *typ, err)
+	}
+
+	return parse.PrintSchema(os.Stdout, parse.Schema(result.Parent))
+}
+
 func readPageHeaders() {
 	f := openParquet()
 	footer := getFooter(f)
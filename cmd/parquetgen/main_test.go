@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMain runs parquetgen itself, instead of the tests, when the
+// test binary is re-run by runParquetgen.
+func TestMain(m *testing.M) {
+	if os.Getenv("PARQUETGEN_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runParquetgen runs parquetgen with args and returns its exit code and stderr.
+func runParquetgen(t *testing.T, args ...string) (int, string) {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "PARQUETGEN_MAIN=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if ee, ok := err.(*exec.ExitError); ok {
+		return ee.ExitCode(), stderr.String()
+	}
+	if !assert.NoError(t, err) {
+		return -1, stderr.String()
+	}
+	return 0, stderr.String()
+}
+
+func TestStrict(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parquetgen")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.go")
+	src := `package input
+
+import "time"
+
+type Zone time.Location
+
+type Row struct {
+	ID      int64
+	Name    string
+	Seen    time.Time
+	Ratio   complex128
+	Weights map[string]chan int
+	Zone    Zone
+}
+`
+	if !assert.NoError(t, ioutil.WriteFile(input, []byte(src), 0644)) {
+		return
+	}
+
+	testCases := []struct {
+		name   string
+		args   []string
+		code   int
+		errors []string
+	}{
+		{
+			name: "ignored by default",
+		},
+		{
+			name: "strict",
+			args: []string{"-strict"},
+			code: 1,
+			errors: []string{
+				"not generating",
+				"unsupported type complex128 for field Ratio",
+				"unsupported type map[string]chan int for field Weights",
+				"unsupported type Zone for field Zone",
+			},
+		},
+		{
+			name: "ignore set to false",
+			args: []string{"-ignore=false"},
+			code: 1,
+			errors: []string{
+				"unsupported type complex128 for field Ratio",
+			},
+		},
+		{
+			name: "schema hash",
+			args: []string{"-strict", "-schemahash"},
+			code: 1,
+			errors: []string{
+				"not printing the schema hash",
+				"unsupported type complex128 for field Ratio",
+			},
+		},
+		{
+			name: "schema",
+			args: []string{"-strict", "-schema"},
+			code: 1,
+			errors: []string{
+				"not printing the schema,",
+				"unsupported type complex128 for field Ratio",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filepath.Join(dir, "generated.go")
+			os.Remove(output)
+
+			args := append([]string{"-input", input, "-type", "Row", "-package", "input", "-output", output}, tc.args...)
+			code, stderr := runParquetgen(t, args...)
+			if !assert.Equal(t, tc.code, code, stderr) {
+				return
+			}
+
+			for _, e := range tc.errors {
+				assert.Contains(t, stderr, e)
+			}
+
+			_, err := os.Stat(output)
+			assert.Equal(t, tc.code == 0, err == nil)
+		})
+	}
+}
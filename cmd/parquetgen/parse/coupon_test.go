@@ -0,0 +1,8 @@
+package parse_test
+
+// The methods of Coupon are in a different file than the type
+// so that parse.Fields has to find them in the rest of the package.
+
+func (c Coupon) MarshalParquet() string { return c.code }
+
+func (c *Coupon) UnmarshalParquet(v string) { c.code = v }
@@ -0,0 +1,121 @@
+package parse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	flds "github.com/parsyl/parquet/cmd/parquetgen/fields"
+)
+
+// SchemaDiff is what changed between two versions of a
+// struct's columns (see Diff).  Columns are given by their
+// full names (like "hobby.name").
+type SchemaDiff struct {
+	Added             []string
+	Removed           []string
+	TypeChanges       []TypeChange
+	RepetitionChanges []RepetitionChange
+}
+
+// TypeChange is a column whose go (or logical) type changed.
+type TypeChange struct {
+	Column string
+	Old    string
+	New    string
+}
+
+// RepetitionChange is a column whose repetition types (the
+// repetition type of each field in its path) changed.
+type RepetitionChange struct {
+	Column string
+	Old    flds.RepetitionTypes
+	New    flds.RepetitionTypes
+}
+
+// Breaking returns true if the new fields can't read files that were
+// written with the old fields.  The generated reader returns an error
+// for a column it doesn't know about and reads a column's values and
+// levels based on its type and repetition types, so removed columns,
+// type changes and repetition changes are breaking.  Added columns
+// aren't (they're left as zero values when an old file is read).
+func (d SchemaDiff) Breaking() bool {
+	return len(d.Removed) > 0 || len(d.TypeChanges) > 0 || len(d.RepetitionChanges) > 0
+}
+
+// Diff compares the columns of the old and new versions of a struct
+// (the fields returned by Field.Fields).  The order of the columns
+// doesn't matter, since columns are read by name.
+func Diff(oldFields, newFields []flds.Field) SchemaDiff {
+	var d SchemaDiff
+	old := make(map[string]flds.Field, len(oldFields))
+	for _, f := range oldFields {
+		old[strings.Join(f.ColumnNames(), ".")] = f
+	}
+
+	seen := make(map[string]bool, len(newFields))
+	for _, f := range newFields {
+		col := strings.Join(f.ColumnNames(), ".")
+		seen[col] = true
+		o, ok := old[col]
+		if !ok {
+			d.Added = append(d.Added, col)
+			continue
+		}
+
+		if a, b := typeName(o), typeName(f); a != b {
+			d.TypeChanges = append(d.TypeChanges, TypeChange{Column: col, Old: a, New: b})
+		}
+
+		if a, b := o.RepetitionTypes(), f.RepetitionTypes(); !reflect.DeepEqual(a, b) {
+			d.RepetitionChanges = append(d.RepetitionChanges, RepetitionChange{Column: col, Old: a, New: b})
+		}
+	}
+
+	for _, f := range oldFields {
+		if col := strings.Join(f.ColumnNames(), "."); !seen[col] {
+			d.Removed = append(d.Removed, col)
+		}
+	}
+	return d
+}
+
+// typeName is the go type of a field along with its logical
+// type (if it has one), like "int64 (decimal(18,2))".
+func typeName(f flds.Field) string {
+	switch f.Logical {
+	case "":
+		return f.Type
+	case "decimal":
+		return fmt.Sprintf("%s (decimal(%d,%d))", f.Type, f.Precision, f.Scale)
+	case "timestamp":
+		// millis is how a time.Time is stored without a logical type
+		if f.Unit == "millis" {
+			return f.Type
+		}
+		return fmt.Sprintf("%s (timestamp(%s))", f.Type, f.Unit)
+	default:
+		return fmt.Sprintf("%s (%s)", f.Type, f.Logical)
+	}
+}
+
+// SchemaHash returns a hash of the columns of fields (the fields returned
+// by Field.Fields) that only changes when a column is added, removed or
+// renamed or its type or repetition types change.  The columns are sorted
+// by name first, so reordering the fields of a struct doesn't change it.
+func SchemaHash(fields []flds.Field) string {
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		cols[i] = fmt.Sprintf("%s %s %v", strings.Join(f.ColumnNames(), "."), typeName(f), f.RepetitionTypes())
+	}
+	sort.Strings(cols)
+
+	h := sha256.New()
+	for _, col := range cols {
+		fmt.Fprintln(h, col)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
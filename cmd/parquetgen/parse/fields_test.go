@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/parsyl/parquet/cmd/parquetgen/fields"
@@ -21,6 +22,7 @@ func TestFields(t *testing.T) {
 	type testInput struct {
 		name     string
 		typ      string
+		opts     []func(*parse.Options)
 		expected fields.Field
 		errors   []error
 	}
@@ -46,6 +48,16 @@ func TestFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "unexported fields",
+			typ:  "Secretive",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "string", Name: "Name", ColumnName: "Name", RepetitionType: fields.Required},
+				},
+			},
+		},
 		{
 			name: "nested struct",
 			typ:  "Nested",
@@ -135,10 +147,26 @@ func TestFields(t *testing.T) {
 			},
 			errors: []error{},
 		},
+		{
+			name: "pointer to struct chain",
+			typ:  "Resident",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "Address", Name: "Home", ColumnName: "Home", RepetitionType: fields.Optional, Children: []fields.Field{
+						{Type: "string", Name: "Street", ColumnName: "Street", RepetitionType: fields.Required},
+						{Type: "Geo", Name: "Geo", ColumnName: "Geo", RepetitionType: fields.Optional, Children: []fields.Field{
+							{Type: "float64", Name: "Lat", ColumnName: "Lat", RepetitionType: fields.Required},
+							{Type: "float64", Name: "Lon", ColumnName: "Lon", RepetitionType: fields.Optional},
+						}},
+					}},
+				},
+			},
+			errors: []error{},
+		},
 		{
 			name:   "unsupported fields",
 			typ:    "Unsupported",
-			errors: []error{fmt.Errorf("unsupported type &{time Time}")},
+			errors: []error{fmt.Errorf("unsupported type complex64 for field Signal at parse_test.go:204")},
 			expected: fields.Field{
 				Children: []fields.Field{
 					{Type: "int32", Name: "ID", ColumnName: "ID", RepetitionType: fields.Required},
@@ -158,8 +186,8 @@ func TestFields(t *testing.T) {
 				},
 			},
 			errors: []error{
-				fmt.Errorf("unsupported type &{time Time}"),
-				fmt.Errorf("unsupported type &{time Time}"),
+				fmt.Errorf("unsupported type complex64 for field S1 at parse_test.go:210"),
+				fmt.Errorf("unsupported type complex64 for field S2 at parse_test.go:213"),
 			},
 		},
 		{
@@ -198,6 +226,41 @@ func TestFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "float32 and float64",
+			typ:  "Prices",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "float32", Name: "Discount", ColumnName: "Discount", RepetitionType: fields.Required},
+					{Type: "float64", Name: "Price", ColumnName: "Price", RepetitionType: fields.Required},
+					{Type: "float32", Name: "Rebate", ColumnName: "Rebate", RepetitionType: fields.Optional},
+					{Type: "float64", Name: "Ratio", ColumnName: "Ratio", RepetitionType: fields.Optional},
+				},
+			},
+		},
+		{
+			name: "signed integer sizes",
+			typ:  "Sizes",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int8", Name: "Level", ColumnName: "Level", RepetitionType: fields.Required},
+					{Type: "int16", Name: "Count", ColumnName: "Count", RepetitionType: fields.Optional},
+					{Type: "int32", Name: "ID", ColumnName: "ID", RepetitionType: fields.Required},
+					{Type: "int64", Name: "Total", ColumnName: "Total", RepetitionType: fields.Optional},
+				},
+			},
+		},
+		{
+			name: "time",
+			typ:  "Event",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "ID", RepetitionType: fields.Required},
+					{Type: "time.Time", Name: "CreatedAt", ColumnName: "created_at", RepetitionType: fields.Required},
+					{Type: "time.Time", Name: "DeletedAt", ColumnName: "deleted_at", RepetitionType: fields.Optional},
+				},
+			},
+		},
 		{
 			name: "tags",
 			typ:  "Tagged",
@@ -209,157 +272,1228 @@ func TestFields(t *testing.T) {
 			},
 		},
 		{
-			name: "omit tag",
-			typ:  "IgnoreMe",
+			name: "logical type tags",
+			typ:  "Dated",
 			expected: fields.Field{
 				Children: []fields.Field{
 					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "time.Time", Name: "DOB", ColumnName: "dob", RepetitionType: fields.Required, Logical: "date"},
+					{Type: "time.Time", Name: "Graduated", ColumnName: "graduated", RepetitionType: fields.Optional, Logical: "date"},
 				},
 			},
 		},
 		{
-			name: "repeated",
-			typ:  "Slice",
+			name: "named time type",
+			typ:  "Birthday",
+			errors: []error{
+				fmt.Errorf("unsupported type Zone for field Zone at parse_test.go:605"),
+			},
 			expected: fields.Field{
 				Children: []fields.Field{
-					{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated},
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "time.Time", Named: "Date", Name: "DOB", ColumnName: "dob", RepetitionType: fields.Required, Logical: "date"},
+					{Type: "time.Time", Named: "Date", Name: "Left", ColumnName: "left", RepetitionType: fields.Optional, Logical: "date"},
 				},
 			},
 		},
 		{
-			name: "repeated v2",
-			typ:  "Slice2",
+			name: "strings and bytes",
+			typ:  "Blob",
 			expected: fields.Field{
 				Children: []fields.Field{
-					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
-					{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated},
+					{Type: "string", Name: "Name", ColumnName: "name", RepetitionType: fields.Required},
+					{Type: "[]byte", Name: "Payload", ColumnName: "payload", RepetitionType: fields.Required},
+					{Type: "[]byte", Name: "Thumbnail", ColumnName: "thumbnail", RepetitionType: fields.Optional},
+					{Type: "string", Name: "Caption", ColumnName: "Caption", RepetitionType: fields.Optional},
 				},
 			},
 		},
 		{
-			name: "repeated v2",
-			typ:  "Slice3",
+			name: "fixed length byte arrays",
+			typ:  "Fixed",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "[16]byte", Name: "ID", ColumnName: "id", RepetitionType: fields.Required, Logical: "uuid"},
+					{Type: "[8]byte", Name: "Checksum", ColumnName: "checksum", RepetitionType: fields.Required},
+					{Type: "[16]byte", Name: "Parent", ColumnName: "parent", RepetitionType: fields.Optional},
+				},
+			},
+		},
+		{
+			name: "repeated scalars",
+			typ:  "Scored",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "string", Name: "Tags", ColumnName: "tags", RepetitionType: fields.Repeated},
+					{Type: "int32", Name: "Scores", ColumnName: "scores", RepetitionType: fields.Repeated},
+				},
+			},
+		},
+		{
+			name: "repeated scalars in a nested struct",
+			typ:  "Contest",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "string", Name: "Name", ColumnName: "name", RepetitionType: fields.Required},
+					{Type: "Scored", Name: "Scored", ColumnName: "scored", RepetitionType: fields.Required, Children: []fields.Field{
+						{Type: "string", Name: "Tags", ColumnName: "tags", RepetitionType: fields.Repeated},
+						{Type: "int32", Name: "Scores", ColumnName: "scores", RepetitionType: fields.Repeated},
+					}},
+				},
+			},
+		},
+		{
+			name:   "duplicate column names",
+			typ:    "Collision",
+			errors: []error{fmt.Errorf("duplicate column name: id (fields ID and Identifier)")},
 			expected: fields.Field{
 				Children: []fields.Field{
 					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
-					{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated},
+					{Type: "int32", Name: "Identifier", ColumnName: "id", RepetitionType: fields.Optional},
+					{Type: "string", Name: "Name", ColumnName: "name", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name:   "duplicate column names with an embedded struct",
+			typ:    "EmbeddedCollision",
+			errors: []error{fmt.Errorf("duplicate column name: ID (fields ID and Other)")},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "ID", RepetitionType: fields.Required},
 					{Type: "int32", Name: "Age", ColumnName: "Age", RepetitionType: fields.Optional},
+					{Type: "int32", Name: "Other", ColumnName: "ID", RepetitionType: fields.Required},
 				},
 			},
 		},
 		{
-			name: "nested and repeated",
-			typ:  "Slice4",
+			name:   "unsupported logical type",
+			typ:    "BadLogical",
+			errors: []error{fmt.Errorf("unsupported logical type date for field Code (int32) at parse_test.go:114")},
 			expected: fields.Field{
 				Children: []fields.Field{
 					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
-					{Type: "Hobby", Name: "Hobbies", ColumnName: "hobbies", RepetitionType: fields.Repeated, Children: []fields.Field{
-						{Type: "string", Name: "Name", ColumnName: "Name", RepetitionType: fields.Required},
-						{Type: "int32", Name: "Difficulty", ColumnName: "Difficulty", RepetitionType: fields.Required},
-					}},
 				},
 			},
 		},
 		{
-			name: "nested and repeated v2",
-			typ:  "Slice5",
+			name: "decimals",
+			typ:  "Priced",
 			expected: fields.Field{
 				Children: []fields.Field{
 					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
-					{Type: "Hobby2", Name: "Hobby", ColumnName: "hobby", RepetitionType: fields.Required, Children: []fields.Field{
-						{Type: "string", Name: "Names", ColumnName: "names", RepetitionType: fields.Repeated},
-					}},
+					{Type: "int64", Name: "Amount", ColumnName: "amount", RepetitionType: fields.Required, Logical: "decimal", Precision: 18, Scale: 2},
+					{Type: "int32", Name: "Discount", ColumnName: "discount", RepetitionType: fields.Optional, Logical: "decimal", Precision: 4},
+					{Type: "[16]byte", Name: "Total", ColumnName: "total", RepetitionType: fields.Required, Logical: "decimal", Precision: 38, Scale: 4},
 				},
 			},
 		},
 		{
-			name: "repeated and repeated",
-			typ:  "Slice6",
+			name: "enums",
+			typ:  "Ticket",
 			expected: fields.Field{
 				Children: []fields.Field{
 					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
-					{Type: "Hobby2", Name: "Hobbies", ColumnName: "hobbies", RepetitionType: fields.Repeated, Children: []fields.Field{
-						{Type: "string", Name: "Names", ColumnName: "names", RepetitionType: fields.Repeated},
-					}},
+					{Type: "string", Name: "Status", ColumnName: "status", RepetitionType: fields.Required, Logical: "enum"},
+					{Type: "string", Name: "Priority", ColumnName: "priority", RepetitionType: fields.Optional, Logical: "enum"},
 				},
 			},
 		},
 		{
-			name: "nested repeated and repeated",
-			typ:  "Slice7",
+			name: "invalid decimals",
+			typ:  "BadDecimal",
+			errors: []error{
+				fmt.Errorf("invalid decimal precision 19 for field Amount (int64), it must be between 1 and 18"),
+				fmt.Errorf("invalid decimal scale 6 for field Rate, it must be between 0 and the precision (5)"),
+				fmt.Errorf("invalid decimal precision 0 for field Total (int64), it must be between 1 and 18"),
+				fmt.Errorf("invalid decimal precision 19 for field Big ([8]byte), it must be between 1 and 18"),
+			},
 			expected: fields.Field{
 				Children: []fields.Field{
-					{Type: "Slice6", Name: "Thing", ColumnName: "thing", RepetitionType: fields.Optional, Children: []fields.Field{
-						{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
-						{Type: "Hobby2", Name: "Hobbies", ColumnName: "hobbies", RepetitionType: fields.Repeated, Children: []fields.Field{
-							{Type: "string", Name: "Names", ColumnName: "names", RepetitionType: fields.Repeated},
-						}},
-					}},
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
 				},
 			},
 		},
 		{
-			name: "dremel paper example",
-			typ:  "Document",
+			name: "timestamp units",
+			typ:  "Stamped",
 			expected: fields.Field{
 				Children: []fields.Field{
-					{Type: "int64", Name: "DocID", ColumnName: "DocID", RepetitionType: fields.Required},
-					{Type: "Link", Name: "Links", ColumnName: "Links", RepetitionType: fields.Repeated, Children: []fields.Field{
-						{Type: "int64", Name: "Backward", ColumnName: "Backward", RepetitionType: fields.Repeated},
-						{Type: "int64", Name: "Forward", ColumnName: "Forward", RepetitionType: fields.Repeated},
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "time.Time", Name: "Logged", ColumnName: "logged", RepetitionType: fields.Required, Logical: "timestamp", Unit: "millis"},
+					{Type: "time.Time", Name: "Started", ColumnName: "started", RepetitionType: fields.Required, Logical: "timestamp", Unit: "micros"},
+					{Type: "time.Time", Name: "Finished", ColumnName: "finished", RepetitionType: fields.Optional, Logical: "timestamp", Unit: "nanos"},
+				},
+			},
+		},
+		{
+			name: "invalid timestamp units",
+			typ:  "BadEvent",
+			errors: []error{
+				fmt.Errorf("unsupported unit seconds for field Started (time.Time) at parse_test.go:397, it must be millis, micros or nanos and logical must be timestamp or time"),
+				fmt.Errorf("unsupported unit micros for field Created (time.Time) at parse_test.go:398, it must be millis, micros or nanos and logical must be timestamp or time"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "null column",
+			typ:  "Upstream",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "struct{}", Name: "Deprecated", ColumnName: "deprecated", RepetitionType: fields.Optional, Logical: "null"},
+				},
+			},
+		},
+		{
+			name: "json",
+			typ:  "Webhook",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "string", Name: "Payload", ColumnName: "payload", RepetitionType: fields.Required, Logical: "json"},
+					{Type: "string", Name: "Extra", ColumnName: "extra", RepetitionType: fields.Optional, Logical: "json"},
+				},
+			},
+		},
+		{
+			name: "bson",
+			typ:  "Attachment",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "[]byte", Name: "Doc", ColumnName: "doc", RepetitionType: fields.Required, Logical: "bson"},
+					{Type: "[]byte", Name: "Meta", ColumnName: "meta", RepetitionType: fields.Optional, Logical: "bson"},
+				},
+			},
+		},
+		{
+			name: "invalid bson",
+			typ:  "BadAttachment",
+			errors: []error{
+				fmt.Errorf("unsupported logical type bson for field Doc (string) at parse_test.go:443"),
+				fmt.Errorf("unsupported logical type bson for field Raw (int64) at parse_test.go:444"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "time of day",
+			typ:  "Shop",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "time.Duration", Name: "Opens", ColumnName: "opens", RepetitionType: fields.Required, Logical: "time", Unit: "millis"},
+					{Type: "time.Duration", Name: "Closes", ColumnName: "closes", RepetitionType: fields.Optional, Logical: "time", Unit: "micros"},
+					{Type: "time.Duration", Name: "Alarm", ColumnName: "alarm", RepetitionType: fields.Optional, Logical: "time", Unit: "nanos"},
+				},
+			},
+		},
+		{
+			name: "invalid time of day",
+			typ:  "BadShop",
+			errors: []error{
+				fmt.Errorf("unsupported logical type time for field Opens (time.Time) at parse_test.go:456"),
+				fmt.Errorf("unsupported unit seconds for field Closes (time.Duration) at parse_test.go:457, it must be millis, micros or nanos and logical must be timestamp or time"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "anonymous structs",
+			typ:  "Sensor",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "sensorMeta", Name: "Meta", ColumnName: "meta", RepetitionType: fields.Required, Anonymous: "struct {\n\tModel string `parquet:\"name=model\"`\n\tRev   *int32 `parquet:\"name=rev\"`\n}", Children: []fields.Field{
+						{Type: "string", Name: "Model", ColumnName: "model", RepetitionType: fields.Required},
+						{Type: "int32", Name: "Rev", ColumnName: "rev", RepetitionType: fields.Optional},
 					}},
-					{Type: "Name", Name: "Names", ColumnName: "Names", RepetitionType: fields.Repeated, Children: []fields.Field{
-						{Type: "Language", Name: "Languages", ColumnName: "Languages", RepetitionType: fields.Repeated, Children: []fields.Field{
-							{Type: "string", Name: "Code", ColumnName: "Code", RepetitionType: fields.Required},
-							{Type: "string", Name: "Country", ColumnName: "Country", RepetitionType: fields.Optional},
+					{Type: "sensorCalibration", Name: "Calibration", ColumnName: "calibration", RepetitionType: fields.Optional, Anonymous: "struct {\n\tPoints []struct {\n\t\tX float64 `parquet:\"name=x\"`\n\t} `parquet:\"name=points\"`\n}", Children: []fields.Field{
+						{Type: "sensorCalibrationPoints", Name: "Points", ColumnName: "points", RepetitionType: fields.Repeated, Anonymous: "struct {\n\tX float64 `parquet:\"name=x\"`\n}", Children: []fields.Field{
+							{Type: "float64", Name: "X", ColumnName: "x", RepetitionType: fields.Required},
 						}},
-						{Type: "string", Name: "URL", ColumnName: "URL", RepetitionType: fields.Optional},
 					}},
 				},
 			},
 		},
 		{
-			name: "embedded embedded embedded",
-			typ:  "A",
+			name: "float16",
+			typ:  "Embedding",
 			expected: fields.Field{
 				Children: []fields.Field{
-					{Name: "D", Type: "int32", ColumnName: "D", RepetitionType: fields.Required},
-					{Name: "C", Type: "string", ColumnName: "C", RepetitionType: fields.Required},
-					{Name: "B", Type: "bool", ColumnName: "B", RepetitionType: fields.Required},
-					{Name: "Name", Type: "string", ColumnName: "Name", RepetitionType: fields.Required},
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "uint16", Name: "Weight", ColumnName: "weight", RepetitionType: fields.Required, Logical: "float16"},
+					{Type: "uint16", Name: "Bias", ColumnName: "bias", RepetitionType: fields.Optional, Logical: "float16"},
 				},
 			},
 		},
-	}
-
-	for i, tc := range testCases {
-		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
-			out, err := parse.Fields(tc.typ, "./parse_test.go")
-			assert.Nil(t, err, tc.name)
-
-			if len(tc.errors) == 0 {
-				tc.errors = nil
-			}
-
-			if !assert.Equal(t, tc.errors, out.Errors, tc.name) {
-				return
-			}
-
-			assert.Equal(t, tc.expected.Children, out.Parent.Children, tc.name)
-		})
-	}
-}
-
-func pint32(i int32) *int32 {
-	return &i
-}
-
-func prt(rt sch.FieldRepetitionType) *sch.FieldRepetitionType {
-	return &rt
-}
-
-func pt(t sch.Type) *sch.Type {
-	return &t
+		{
+			name: "invalid float16",
+			typ:  "BadEmbedding",
+			errors: []error{
+				fmt.Errorf("unsupported logical type float16 for field Weight (float32) at parse_test.go:481"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "big.Int decimal",
+			typ:  "Ledger",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "big.Int", Name: "Balance", ColumnName: "balance", RepetitionType: fields.Optional, Logical: "decimal", Precision: 38, Scale: 2},
+				},
+			},
+		},
+		{
+			name: "invalid big.Int decimal",
+			typ:  "BadLedger",
+			errors: []error{
+				fmt.Errorf("unsupported logical type decimal for field Balance (big.Int) at parse_test.go:491"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "encodings",
+			typ:  "Metric",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int64", Name: "ID", ColumnName: "id", RepetitionType: fields.Required, Encoding: "delta"},
+					{Type: "string", Name: "Host", ColumnName: "host", RepetitionType: fields.Required, Encoding: "dictionary"},
+					{Type: "string", Name: "Path", ColumnName: "path", RepetitionType: fields.Optional, Encoding: "delta_byte_array"},
+					{Type: "[]byte", Name: "Body", ColumnName: "body", RepetitionType: fields.Required, Encoding: "delta_length_byte_array"},
+					{Type: "time.Time", Name: "Seen", ColumnName: "seen", RepetitionType: fields.Required, Encoding: "delta"},
+					{Type: "float64", Name: "Value", ColumnName: "value", RepetitionType: fields.Required, Encoding: "plain"},
+					{Type: "int32", Name: "Samples", ColumnName: "samples", RepetitionType: fields.Repeated, Encoding: "delta"},
+				},
+			},
+		},
+		{
+			name: "invalid encodings",
+			typ:  "BadMetric",
+			errors: []error{
+				fmt.Errorf("unsupported encoding dictionary for field ID (int64) at parse_test.go:505"),
+				fmt.Errorf("unsupported encoding delta for field Host (string) at parse_test.go:506"),
+				fmt.Errorf("unsupported encoding rle for field Value (float64) at parse_test.go:507"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "Count", ColumnName: "count", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "geospatial",
+			typ:  "Parcel",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "[]byte", Name: "Boundary", ColumnName: "boundary", RepetitionType: fields.Required, Logical: "geometry", CRS: "OGC:CRS84"},
+					{Type: "[]byte", Name: "Access", ColumnName: "access", RepetitionType: fields.Optional, Logical: "geography"},
+				},
+			},
+		},
+		{
+			name: "invalid geospatial",
+			typ:  "BadParcel",
+			errors: []error{
+				fmt.Errorf("unsupported logical type geometry for field Center (string) at parse_test.go:519"),
+				fmt.Errorf("unsupported crs OGC:CRS84 for field Owner ([]byte) at parse_test.go:520, logical must be geometry or geography"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "named types",
+			typ:  "Reading",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "float32", Named: "Celsius", Name: "Temp", ColumnName: "Temp", RepetitionType: fields.Required},
+					{Type: "float32", Named: "Kelvin", Name: "Abs", ColumnName: "abs", RepetitionType: fields.Optional},
+					{Type: "string", Named: "Mood", Name: "Moods", ColumnName: "moods", RepetitionType: fields.Repeated},
+					{Type: "string", Named: "Mood", Name: "Status", ColumnName: "status", RepetitionType: fields.Required, Logical: "enum"},
+					{Type: "Visit", Name: "Visit", ColumnName: "visit", RepetitionType: fields.Optional, Children: []fields.Field{
+						{Type: "string", Named: "Mood", Name: "Mood", ColumnName: "mood", RepetitionType: fields.Required},
+						{Type: "float32", Named: "Celsius", Name: "Temp", ColumnName: "temp", RepetitionType: fields.Optional},
+					}},
+				},
+			},
+		},
+		{
+			name: "marshalers",
+			typ:  "Invoice",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "int64", Named: "Money", Marshaler: true, Name: "Price", ColumnName: "price", RepetitionType: fields.Required},
+					{Type: "int64", Named: "Money", Marshaler: true, Name: "Fee", ColumnName: "fee", RepetitionType: fields.Optional},
+					{Type: "int64", Named: "Money", Marshaler: true, Name: "Refunds", ColumnName: "refunds", RepetitionType: fields.Repeated},
+					{Type: "string", Named: "Email", Marshaler: true, Name: "Contact", ColumnName: "contact", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "marshaler methods in another file",
+			typ:  "Receipt",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "string", Named: "Coupon", Marshaler: true, Name: "Coupon", ColumnName: "coupon", RepetitionType: fields.Optional},
+				},
+			},
+		},
+		{
+			name: "invalid marshalers",
+			typ:  "BadOrder",
+			errors: []error{
+				fmt.Errorf("unsupported type WriteOnly for field Total at parse_test.go:579: WriteOnly has a MarshalParquet method but no UnmarshalParquet method"),
+				fmt.Errorf("unsupported type Mismatch for field Tax at parse_test.go:580: Mismatch.UnmarshalParquet must have a pointer receiver, take one int64 and return nothing"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "repeated byte arrays",
+			typ:  "Envelope",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "[]byte", Name: "Attachments", ColumnName: "attachments", RepetitionType: fields.Repeated},
+					{Type: "[16]byte", Name: "Hashes", ColumnName: "hashes", RepetitionType: fields.Repeated},
+					{Type: "Section", Name: "Sections", ColumnName: "sections", RepetitionType: fields.Repeated, Children: []fields.Field{
+						{Type: "[]byte", Name: "Blobs", ColumnName: "blobs", RepetitionType: fields.Repeated},
+					}},
+				},
+			},
+		},
+		{
+			name: "invalid null columns",
+			typ:  "BadUpstream",
+			errors: []error{
+				fmt.Errorf("unsupported logical type null for field Deprecated (struct{}) at parse_test.go:408"),
+				fmt.Errorf("unsupported type struct{} for field Removed at parse_test.go:409"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "unsigned ints",
+			typ:  "Unsigned",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "uint8", Name: "Flags", ColumnName: "flags", RepetitionType: fields.Required},
+					{Type: "uint16", Name: "Port", ColumnName: "port", RepetitionType: fields.Optional},
+					{Type: "uint32", Name: "Count", ColumnName: "count", RepetitionType: fields.Required},
+					{Type: "uint64", Name: "Total", ColumnName: "total", RepetitionType: fields.Optional},
+					{Type: "uint8", Name: "Mask", ColumnName: "mask", RepetitionType: fields.Optional},
+					{Type: "uint16", Name: "Priority", ColumnName: "priority", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "durations",
+			typ:  "Timer",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "time.Duration", Name: "Elapsed", ColumnName: "elapsed", RepetitionType: fields.Required},
+					{Type: "time.Duration", Name: "Timeout", ColumnName: "timeout", RepetitionType: fields.Optional},
+				},
+			},
+		},
+		{
+			name: "embedded pointer struct",
+			typ:  "OptionalEmbedded",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "Being", Name: "Being", ColumnName: "Being", RepetitionType: fields.Optional, Children: []fields.Field{
+						{Type: "int32", Name: "ID", ColumnName: "ID", RepetitionType: fields.Required},
+						{Type: "int32", Name: "Age", ColumnName: "Age", RepetitionType: fields.Optional},
+					}},
+					{Type: "int64", Name: "Happiness", ColumnName: "Happiness", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "slice of structs",
+			typ:  "Order",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int64", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "LineItem", Name: "LineItems", ColumnName: "line_items", RepetitionType: fields.Repeated, Children: []fields.Field{
+						{Type: "string", Name: "SKU", ColumnName: "sku", RepetitionType: fields.Required},
+						{Type: "int32", Name: "Quantity", ColumnName: "quantity", RepetitionType: fields.Required},
+						{Type: "float64", Name: "Discount", ColumnName: "discount", RepetitionType: fields.Optional},
+						{Type: "[]byte", Name: "Note", ColumnName: "note", RepetitionType: fields.Required},
+					}},
+				},
+			},
+		},
+		{
+			name: "slices of pointers",
+			typ:  "PointerSlices",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+			errors: []error{
+				fmt.Errorf("unsupported type []*LineItem for field Items at parse_test.go:349"),
+				fmt.Errorf("unsupported type []*int32 for field Counts at parse_test.go:350"),
+			},
+		},
+		{
+			name: "maps",
+			typ:  "Inventory",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "map[string]int32", Name: "Counts", ColumnName: "counts", RepetitionType: fields.Optional, Children: []fields.Field{
+						{ColumnName: "key_value", RepetitionType: fields.Repeated, Children: []fields.Field{
+							{Type: "string", Name: "Key", ColumnName: "key", RepetitionType: fields.Required},
+							{Type: "int32", Name: "Value", ColumnName: "value", RepetitionType: fields.Required},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			name: "unsupported maps",
+			typ:  "Warehouse",
+			errors: []error{
+				fmt.Errorf("unsupported type map[string]int32 for field Counts at parse_test.go:312 (only the top level struct can have maps)"),
+				fmt.Errorf("unsupported type map[string][]string for field Labels at parse_test.go:318"),
+			},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "Bin", Name: "Bin", ColumnName: "bin", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "omit tag",
+			typ:  "IgnoreMe",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "omit nested struct",
+			typ:  "IgnoreNested",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "Address", Name: "Home", ColumnName: "Home", RepetitionType: fields.Optional, Children: []fields.Field{
+						{Type: "string", Name: "Street", ColumnName: "Street", RepetitionType: fields.Required},
+						{Type: "Geo", Name: "Geo", ColumnName: "Geo", RepetitionType: fields.Optional, Children: []fields.Field{
+							{Type: "float64", Name: "Lat", ColumnName: "Lat", RepetitionType: fields.Required},
+							{Type: "float64", Name: "Lon", ColumnName: "Lon", RepetitionType: fields.Optional},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			name: "omit embedded struct",
+			typ:  "IgnoreEmbedded",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "string", Name: "Name", ColumnName: "name", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "repeated",
+			typ:  "Slice",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated},
+				},
+			},
+		},
+		{
+			name: "repeated v2",
+			typ:  "Slice2",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated},
+				},
+			},
+		},
+		{
+			name: "repeated v2",
+			typ:  "Slice3",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated},
+					{Type: "int32", Name: "Age", ColumnName: "Age", RepetitionType: fields.Optional},
+				},
+			},
+		},
+		{
+			name: "nested and repeated",
+			typ:  "Slice4",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "Hobby", Name: "Hobbies", ColumnName: "hobbies", RepetitionType: fields.Repeated, Children: []fields.Field{
+						{Type: "string", Name: "Name", ColumnName: "Name", RepetitionType: fields.Required},
+						{Type: "int32", Name: "Difficulty", ColumnName: "Difficulty", RepetitionType: fields.Required},
+					}},
+				},
+			},
+		},
+		{
+			name: "nested and repeated v2",
+			typ:  "Slice5",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "Hobby2", Name: "Hobby", ColumnName: "hobby", RepetitionType: fields.Required, Children: []fields.Field{
+						{Type: "string", Name: "Names", ColumnName: "names", RepetitionType: fields.Repeated},
+					}},
+				},
+			},
+		},
+		{
+			name: "repeated and repeated",
+			typ:  "Slice6",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "Hobby2", Name: "Hobbies", ColumnName: "hobbies", RepetitionType: fields.Repeated, Children: []fields.Field{
+						{Type: "string", Name: "Names", ColumnName: "names", RepetitionType: fields.Repeated},
+					}},
+				},
+			},
+		},
+		{
+			name: "nested repeated and repeated",
+			typ:  "Slice7",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "Slice6", Name: "Thing", ColumnName: "thing", RepetitionType: fields.Optional, Children: []fields.Field{
+						{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+						{Type: "Hobby2", Name: "Hobbies", ColumnName: "hobbies", RepetitionType: fields.Repeated, Children: []fields.Field{
+							{Type: "string", Name: "Names", ColumnName: "names", RepetitionType: fields.Repeated},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			name: "dremel paper example",
+			typ:  "Document",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int64", Name: "DocID", ColumnName: "DocID", RepetitionType: fields.Required},
+					{Type: "Link", Name: "Links", ColumnName: "Links", RepetitionType: fields.Repeated, Children: []fields.Field{
+						{Type: "int64", Name: "Backward", ColumnName: "Backward", RepetitionType: fields.Repeated},
+						{Type: "int64", Name: "Forward", ColumnName: "Forward", RepetitionType: fields.Repeated},
+					}},
+					{Type: "Name", Name: "Names", ColumnName: "Names", RepetitionType: fields.Repeated, Children: []fields.Field{
+						{Type: "Language", Name: "Languages", ColumnName: "Languages", RepetitionType: fields.Repeated, Children: []fields.Field{
+							{Type: "string", Name: "Code", ColumnName: "Code", RepetitionType: fields.Required},
+							{Type: "string", Name: "Country", ColumnName: "Country", RepetitionType: fields.Optional},
+						}},
+						{Type: "string", Name: "URL", ColumnName: "URL", RepetitionType: fields.Optional},
+					}},
+				},
+			},
+		},
+		{
+			name: "snake case",
+			typ:  "Person",
+			opts: []func(*parse.Options){parse.SnakeCase},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "int32", Name: "Age", ColumnName: "age", RepetitionType: fields.Optional},
+					{Type: "int64", Name: "Happiness", ColumnName: "happiness", RepetitionType: fields.Required},
+					{Type: "int64", Name: "Sadness", ColumnName: "sadness", RepetitionType: fields.Optional},
+					{Type: "string", Name: "Code", ColumnName: "code", RepetitionType: fields.Required},
+					{Type: "float32", Name: "Funkiness", ColumnName: "funkiness", RepetitionType: fields.Required},
+					{Type: "float32", Name: "Lameness", ColumnName: "lameness", RepetitionType: fields.Optional},
+					{Type: "bool", Name: "Keen", ColumnName: "keen", RepetitionType: fields.Optional},
+					{Type: "uint32", Name: "Birthday", ColumnName: "birthday", RepetitionType: fields.Required},
+					{Type: "uint64", Name: "Anniversary", ColumnName: "anniversary", RepetitionType: fields.Optional},
+				},
+			},
+		},
+		{
+			name: "snake case with tags",
+			typ:  "Account",
+			opts: []func(*parse.Options){parse.SnakeCase},
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int64", Name: "UserID", ColumnName: "user_id", RepetitionType: fields.Required},
+					{Type: "time.Time", Name: "CreatedAt", ColumnName: "created_at", RepetitionType: fields.Required},
+					{Type: "int32", Name: "HTTPStatus", ColumnName: "http_status", RepetitionType: fields.Optional},
+					{Type: "[]byte", Name: "Avatar", ColumnName: "avatar", RepetitionType: fields.Optional},
+					{Type: "time.Time", Name: "DeletedAt", ColumnName: "removed", RepetitionType: fields.Optional},
+					{Type: "string", Name: "Addr2", ColumnName: "address", RepetitionType: fields.Required},
+				},
+			},
+		},
+		{
+			name: "embedded embedded embedded",
+			typ:  "A",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Name: "D", Type: "int32", ColumnName: "D", RepetitionType: fields.Required},
+					{Name: "C", Type: "string", ColumnName: "C", RepetitionType: fields.Required},
+					{Name: "B", Type: "bool", ColumnName: "B", RepetitionType: fields.Required},
+					{Name: "Name", Type: "string", ColumnName: "Name", RepetitionType: fields.Required},
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			out, err := parse.Fields(tc.typ, "./parse_test.go", tc.opts...)
+			assert.Nil(t, err, tc.name)
+
+			if len(tc.errors) == 0 {
+				tc.errors = nil
+			}
+
+			if !assert.Equal(t, tc.errors, out.Errors, tc.name) {
+				return
+			}
+
+			assert.Equal(t, tc.expected.Children, out.Parent.Children, tc.name)
+		})
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	for _, typ := range []string{
+		"Person",
+		"Nested",
+		"DoubleNested",
+		"OptionalNested",
+		"OptionalDoubleNested",
+		"Resident",
+		"Slice3",
+		"Slice4",
+		"Slice5",
+		"Slice6",
+		"Slice7",
+		"Document",
+		"A",
+		"Dated",
+		"Fixed",
+		"Priced",
+		"Ticket",
+		"Inventory",
+		"Unsigned",
+		"OptionalEmbedded",
+		"Order",
+		"Timer",
+		"Stamped",
+		"Upstream",
+		"Shipment",
+		"Webhook",
+		"Attachment",
+		"Shop",
+		"Sensor",
+		"Embedding",
+		"Ledger",
+		"Metric",
+		"Parcel",
+		"Reading",
+		"Invoice",
+		"Envelope",
+	} {
+		t.Run(typ, func(t *testing.T) {
+			out, err := parse.Fields(typ, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			schema := parse.Schema(out.Parent)
+			assert.NoError(t, parse.CheckSchema(out.Parent, schema))
+
+			// and back again
+			f, err := parse.Parquet(schema)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, schema, parse.Schema(f))
+		})
+	}
+}
+
+func TestPrintSchema(t *testing.T) {
+	testCases := []struct {
+		typ      string
+		expected string
+	}{
+		{
+			typ: "Person",
+			expected: `message root {
+  required int32 ID;
+  optional int32 Age;
+  required int64 Happiness;
+  optional int64 Sadness;
+  required binary Code;
+  required float Funkiness;
+  optional float Lameness;
+  optional boolean Keen;
+  required int32 Birthday (UINT_32);
+  optional int64 Anniversary (UINT_64);
+}
+`,
+		},
+		{
+			typ: "Sensor",
+			expected: `message root {
+  required int32 id;
+  required group meta {
+    required binary model;
+    optional int32 rev;
+  }
+  optional group calibration {
+    repeated group points {
+      required double x;
+    }
+  }
+}
+`,
+		},
+		{
+			typ: "Shop",
+			expected: `message root {
+  required int32 id;
+  required int32 opens (TIME_MILLIS);
+  optional int64 closes (TIME_MICROS);
+  optional int64 alarm (TIME(NANOS,true));
+}
+`,
+		},
+		{
+			typ: "Priced",
+			expected: `message root {
+  required int32 id;
+  required int64 amount (DECIMAL(18,2));
+  optional int32 discount (DECIMAL(4,0));
+  required fixed_len_byte_array(16) total (DECIMAL(38,4));
+}
+`,
+		},
+		{
+			typ: "Parcel",
+			expected: `message root {
+  required int32 id;
+  required binary boundary (GEOMETRY(OGC:CRS84));
+  optional binary access (GEOGRAPHY);
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.typ, func(t *testing.T) {
+			out, err := parse.Fields(tc.typ, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var buf strings.Builder
+			if !assert.NoError(t, parse.PrintSchema(&buf, parse.Schema(out.Parent))) {
+				return
+			}
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestCheckSchemaMismatch(t *testing.T) {
+	out, err := parse.Fields("OptionalDoubleNested", "./parse_test.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		name     string
+		schema   []*sch.SchemaElement
+		expected error
+	}{
+		{
+			name: "repetition type",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(1)},
+				{Name: "OptionalNested", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), NumChildren: pint32(2)},
+				{Name: "Being", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), NumChildren: pint32(2)},
+				{Name: "ID", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), Type: pt(sch.Type_INT32)},
+				{Name: "Age", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), Type: pt(sch.Type_INT32)},
+				{Name: "Anniversary", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), Type: pt(sch.Type_INT64)},
+			},
+			expected: fmt.Errorf("column OptionalNested.Being.ID has repetition types [0 1 0] in the struct and [0 0 0] in the schema"),
+		},
+		{
+			name: "nesting",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(1)},
+				{Name: "OptionalNested", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), NumChildren: pint32(3)},
+				{Name: "Being", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), NumChildren: pint32(1)},
+				{Name: "ID", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), Type: pt(sch.Type_INT32)},
+				{Name: "Age", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), Type: pt(sch.Type_INT32)},
+				{Name: "Anniversary", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), Type: pt(sch.Type_INT64)},
+			},
+			expected: fmt.Errorf("column 1 is OptionalNested.Being.Age in the struct and OptionalNested.Age in the schema"),
+		},
+		{
+			name: "missing column",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(1)},
+				{Name: "OptionalNested", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), NumChildren: pint32(1)},
+				{Name: "Being", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), NumChildren: pint32(2)},
+				{Name: "ID", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), Type: pt(sch.Type_INT32)},
+				{Name: "Age", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), Type: pt(sch.Type_INT32)},
+			},
+			expected: fmt.Errorf("the struct has 3 columns and the schema has 2"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parse.CheckSchema(out.Parent, tc.schema))
+		})
+	}
+}
+
+func TestParquetInt96(t *testing.T) {
+	f, err := parse.Parquet([]*sch.SchemaElement{
+		{Name: "root", NumChildren: pint32(2)},
+		{Name: "created", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), Type: pt(sch.Type_INT96)},
+		{Name: "deleted", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), Type: pt(sch.Type_INT96)},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	fields := f.Fields()
+	if !assert.Equal(t, 2, len(fields)) {
+		return
+	}
+
+	for _, ch := range fields {
+		assert.Equal(t, "time.Time", ch.Type, ch.Name)
+		assert.Equal(t, "timestamp", ch.Logical, ch.Name)
+		assert.Equal(t, "nanos", ch.Unit, ch.Name)
+	}
+}
+
+func pint32(i int32) *int32 {
+	return &i
+}
+
+func prt(rt sch.FieldRepetitionType) *sch.FieldRepetitionType {
+	return &rt
+}
+
+func pt(t sch.Type) *sch.Type {
+	return &t
+}
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		old      string
+		new      string
+		expected parse.SchemaDiff
+		breaking bool
+	}{
+		{
+			name: "same",
+			old:  "Person",
+			new:  "Person",
+		},
+		{
+			name: "reordered",
+			old:  "Person",
+			new:  "NewOrderPerson",
+		},
+		{
+			name: "evolved",
+			old:  "Person",
+			new:  "EvolvedPerson",
+			expected: parse.SchemaDiff{
+				Added:   []string{"Nickname"},
+				Removed: []string{"Anniversary"},
+				TypeChanges: []parse.TypeChange{
+					{Column: "Happiness", Old: "int64", New: "int32"},
+				},
+				RepetitionChanges: []parse.RepetitionChange{
+					{Column: "Sadness", Old: fields.RepetitionTypes{fields.Optional}, New: fields.RepetitionTypes{fields.Required}},
+				},
+			},
+			breaking: true,
+		},
+		{
+			name:     "added",
+			old:      "IgnoreMe",
+			new:      "Tagged",
+			expected: parse.SchemaDiff{Added: []string{"name"}},
+		},
+		{
+			name:     "removed",
+			old:      "Tagged",
+			new:      "IgnoreMe",
+			expected: parse.SchemaDiff{Removed: []string{"name"}},
+			breaking: true,
+		},
+		{
+			name: "logical type",
+			old:  "Dated",
+			new:  "Redated",
+			expected: parse.SchemaDiff{
+				TypeChanges: []parse.TypeChange{
+					{Column: "dob", Old: "time.Time (date)", New: "time.Time"},
+				},
+			},
+			breaking: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			old, err := parse.Fields(tc.old, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			nw, err := parse.Fields(tc.new, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			d := parse.Diff(old.Parent.Fields(), nw.Parent.Fields())
+			assert.Equal(t, tc.expected, d)
+			assert.Equal(t, tc.breaking, d.Breaking())
+		})
+	}
+}
+
+func TestSchemaHash(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+		same bool
+	}{
+		{name: "same", a: "Person", b: "Person", same: true},
+		{name: "reordered", a: "Person", b: "NewOrderPerson", same: true},
+		{name: "added field", a: "IgnoreMe", b: "Tagged"},
+		{name: "changed fields", a: "Person", b: "EvolvedPerson"},
+		{name: "logical type", a: "Dated", b: "Redated"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := parse.Fields(tc.a, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			b, err := parse.Fields(tc.b, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			ha, hb := parse.SchemaHash(a.Parent.Fields()), parse.SchemaHash(b.Parent.Fields())
+			assert.Len(t, ha, 64)
+			assert.Equal(t, tc.same, ha == hb)
+		})
+	}
+}
+
+func TestEmbeddedPointer(t *testing.T) {
+	out, err := parse.Fields("OptionalEmbedded", "./parse_test.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var rts []fields.RepetitionTypes
+	for _, f := range out.Parent.Fields() {
+		rts = append(rts, f.RepetitionTypes())
+	}
+
+	assert.Equal(t, []fields.RepetitionTypes{
+		{fields.Optional, fields.Required},
+		{fields.Optional, fields.Optional},
+		{fields.Required},
+	}, rts)
+}
+
+func TestOptionalMiddle(t *testing.T) {
+	out, err := parse.Fields("Shipment", "./parse_test.go")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		column string
+		rts    fields.RepetitionTypes
+		maxDef int
+		// defIndex is the index (in the field's chain, starting
+		// at the root) of the field that each def level reaches
+		defIndex []int
+	}{
+		{column: "id", rts: fields.RepetitionTypes{fields.Required}, maxDef: 0, defIndex: []int{0}},
+		{column: "carrier.contact.email", rts: fields.RepetitionTypes{fields.Optional, fields.Required, fields.Required}, maxDef: 1, defIndex: []int{0, 1}},
+		{column: "carrier.contact.phone", rts: fields.RepetitionTypes{fields.Optional, fields.Required, fields.Optional}, maxDef: 2, defIndex: []int{0, 1, 3}},
+		{column: "carrier.code", rts: fields.RepetitionTypes{fields.Optional, fields.Required}, maxDef: 1, defIndex: []int{0, 1}},
+	}
+
+	ff := out.Parent.Fields()
+	if !assert.Equal(t, len(testCases), len(ff)) {
+		return
+	}
+
+	// the schema that is written for the fields must agree with them
+	fromSchema, err := parse.Parquet(parse.Schema(out.Parent))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.column, func(t *testing.T) {
+			f := ff[i]
+			assert.Equal(t, tc.column, strings.Join(f.ColumnNames(), "."))
+			assert.Equal(t, tc.rts, f.RepetitionTypes())
+			assert.Equal(t, tc.rts, fromSchema.Fields()[i].RepetitionTypes())
+			assert.Equal(t, tc.maxDef, f.MaxDef())
+			assert.Equal(t, 0, f.MaxRep())
+
+			var defIndex []int
+			for def := 0; def <= f.MaxDef(); def++ {
+				defIndex = append(defIndex, f.DefIndex(def))
+			}
+			assert.Equal(t, tc.defIndex, defIndex)
+		})
+	}
+}
+
+func TestLevels(t *testing.T) {
+	testCases := []struct {
+		typ    string
+		column string
+		maxDef int
+		maxRep int
+	}{
+		{typ: "OptionalDoubleNested", column: "OptionalNested.Being.ID", maxDef: 1, maxRep: 0},
+		{typ: "OptionalDoubleNested", column: "OptionalNested.Being.Age", maxDef: 2, maxRep: 0},
+		{typ: "OptionalDoubleNested", column: "OptionalNested.Anniversary", maxDef: 1, maxRep: 0},
+		{typ: "Slice6", column: "id", maxDef: 0, maxRep: 0},
+		{typ: "Slice6", column: "hobbies.names", maxDef: 2, maxRep: 2},
+		{typ: "Slice7", column: "thing.id", maxDef: 1, maxRep: 0},
+		{typ: "Slice7", column: "thing.hobbies.names", maxDef: 3, maxRep: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %s", tc.typ, tc.column), func(t *testing.T) {
+			out, err := parse.Fields(tc.typ, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var f *parse.Field
+			for _, fld := range out.Parent.Fields() {
+				if strings.Join(fld.ColumnNames(), ".") == tc.column {
+					fld := fld
+					f = &fld
+				}
+			}
+			if !assert.NotNil(t, f, tc.column) {
+				return
+			}
+
+			assert.Equal(t, tc.maxDef, f.MaxDefinitionLevel())
+			assert.Equal(t, tc.maxRep, f.MaxRepetitionLevel())
+			assert.Equal(t, tc.maxDef, f.MaxDef())
+			assert.Equal(t, tc.maxRep, f.MaxRep())
+			assert.Equal(t, uint8(tc.maxDef), f.RepetitionTypes().MaxDef())
+			assert.Equal(t, uint8(tc.maxRep), f.RepetitionTypes().MaxRep())
+		})
+	}
+}
+
+func TestNamedTypes(t *testing.T) {
+	testCases := []struct {
+		typ         string
+		column      string
+		fieldType   string
+		parquetType string
+		fieldTypes  []string
+	}{
+		{typ: "Reading", column: "Temp", fieldType: "Float32Field", parquetType: "Float32Type", fieldTypes: []string{"Reading", "Celsius"}},
+		{typ: "Reading", column: "abs", fieldType: "Float32OptionalField", parquetType: "Float32Type", fieldTypes: []string{"Reading", "Kelvin"}},
+		{typ: "Reading", column: "moods", fieldType: "StringOptionalField", parquetType: "StringType", fieldTypes: []string{"Reading", "Mood"}},
+		{typ: "Reading", column: "status", fieldType: "EnumField", parquetType: "EnumType", fieldTypes: []string{"Reading", "Mood"}},
+		{typ: "Reading", column: "visit.temp", fieldType: "Float32OptionalField", parquetType: "Float32Type", fieldTypes: []string{"Reading", "Visit", "Celsius"}},
+		{typ: "Birthday", column: "dob", fieldType: "DateField", parquetType: "DateType", fieldTypes: []string{"Birthday", "Date"}},
+		{typ: "Birthday", column: "left", fieldType: "DateOptionalField", parquetType: "DateType", fieldTypes: []string{"Birthday", "Date"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.column, func(t *testing.T) {
+			out, err := parse.Fields(tc.typ, "./parse_test.go")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var f *fields.Field
+			for _, fld := range out.Parent.Fields() {
+				if strings.Join(fld.ColumnNames(), ".") == tc.column {
+					fld := fld
+					f = &fld
+				}
+			}
+			if !assert.NotNil(t, f, tc.column) {
+				return
+			}
+
+			assert.Equal(t, tc.fieldType, f.FieldType())
+			assert.Equal(t, tc.parquetType, f.ParquetType())
+			assert.Equal(t, tc.fieldTypes, f.FieldTypes())
+		})
+	}
 }
 
 func TestDefIndex(t *testing.T) {
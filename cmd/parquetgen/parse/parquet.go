@@ -0,0 +1,491 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	flds "github.com/parsyl/parquet/cmd/parquetgen/fields"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// Parquet gets the fields of a parquet schema (the first element
+// is the root of the schema).  It is the opposite of Schema, except
+// that the go field names are the titled column names.
+func Parquet(schema []*sch.SchemaElement) (flds.Field, error) {
+	if len(schema) == 0 {
+		return flds.Field{}, fmt.Errorf("empty schema")
+	}
+
+	parent := flds.Field{Type: strings.Title(schema[0].Name)}
+	n, err := getParquetChildren(&parent, schema[0], schema[1:])
+	if err != nil {
+		return flds.Field{}, err
+	}
+
+	if n != len(schema)-1 {
+		return flds.Field{}, fmt.Errorf("schema has %d elements that aren't in the root's children", len(schema)-1-n)
+	}
+	return parent, nil
+}
+
+// getParquetChildren adds the fields of the children of se to
+// parent and returns the number of schema elements they used.
+func getParquetChildren(parent *flds.Field, se *sch.SchemaElement, schema []*sch.SchemaElement) (int, error) {
+	var i int
+	for j := 0; j < int(se.GetNumChildren()); j++ {
+		if i >= len(schema) {
+			return 0, fmt.Errorf("group %s is missing children", se.Name)
+		}
+
+		ch := schema[i]
+		i++
+		f := flds.Field{
+			Name:           strings.Title(ch.Name),
+			ColumnName:     ch.Name,
+			RepetitionType: flds.RepetitionType(ch.GetRepetitionType()),
+		}
+
+		if ch.GetNumChildren() == 0 {
+			if err := parquetType(&f, ch); err != nil {
+				return 0, err
+			}
+			parent.Children = append(parent.Children, f)
+			continue
+		}
+
+		f.Type = strings.Title(ch.Name)
+		n, err := getParquetChildren(&f, ch, schema[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
+
+		if ch.GetConvertedType() == sch.ConvertedType_MAP {
+			mapType(&f)
+		}
+		parent.Children = append(parent.Children, f)
+	}
+	return i, nil
+}
+
+// mapType sets the type of a MAP group (for example:
+// map[string]int32) and the names of its key and value
+// to the ones parse.Fields uses.
+func mapType(f *flds.Field) {
+	if len(f.Children) != 1 || len(f.Children[0].Children) != 2 {
+		return
+	}
+
+	kv := &f.Children[0]
+	key, val := &kv.Children[0], &kv.Children[1]
+	kv.Name, kv.Type = "", ""
+	key.Name, val.Name = "Key", "Value"
+	f.Type = fmt.Sprintf("map[%s]%s", key.Type, val.Type)
+}
+
+// parquetType sets the go type (and logical type) of the
+// field based on the column's physical and converted types.
+func parquetType(f *flds.Field, se *sch.SchemaElement) error {
+	var ct sch.ConvertedType = -1
+	if se.ConvertedType != nil {
+		ct = *se.ConvertedType
+	}
+
+	if ct == sch.ConvertedType_DECIMAL {
+		f.Logical = "decimal"
+		f.Precision = int(se.GetPrecision())
+		f.Scale = int(se.GetScale())
+	}
+
+	switch se.GetType() {
+	case sch.Type_BOOLEAN:
+		f.Type = "bool"
+	case sch.Type_INT32:
+		f.Type = "int32"
+		switch ct {
+		case sch.ConvertedType_INT_8:
+			f.Type = "int8"
+		case sch.ConvertedType_INT_16:
+			f.Type = "int16"
+		case sch.ConvertedType_UINT_8:
+			f.Type = "uint8"
+		case sch.ConvertedType_UINT_16:
+			f.Type = "uint16"
+		case sch.ConvertedType_UINT_32:
+			f.Type = "uint32"
+		case sch.ConvertedType_DATE:
+			f.Type = "time.Time"
+			f.Logical = "date"
+		case sch.ConvertedType_TIME_MILLIS:
+			f.Type = "time.Duration"
+			f.Logical = "time"
+			f.Unit = "millis"
+		}
+		if lt := se.LogicalType; lt != nil && lt.UNKNOWN != nil {
+			f.Type = "struct{}"
+			f.Logical = "null"
+		}
+	case sch.Type_INT64:
+		f.Type = "int64"
+		switch ct {
+		case sch.ConvertedType_UINT_64:
+			f.Type = "uint64"
+		case sch.ConvertedType_TIMESTAMP_MILLIS:
+			f.Type = "time.Time"
+		case sch.ConvertedType_TIMESTAMP_MICROS:
+			f.Type = "time.Time"
+			f.Logical = "timestamp"
+			f.Unit = "micros"
+		case sch.ConvertedType_TIME_MICROS:
+			f.Type = "time.Duration"
+			f.Logical = "time"
+			f.Unit = "micros"
+		}
+		// NANOS doesn't have a converted type
+		if lt := se.LogicalType; lt != nil && lt.TIMESTAMP != nil && lt.TIMESTAMP.Unit != nil && lt.TIMESTAMP.Unit.NANOS != nil {
+			f.Type = "time.Time"
+			f.Logical = "timestamp"
+			f.Unit = "nanos"
+		}
+		if lt := se.LogicalType; lt != nil && lt.TIME != nil && lt.TIME.Unit != nil && lt.TIME.Unit.NANOS != nil {
+			f.Type = "time.Duration"
+			f.Logical = "time"
+			f.Unit = "nanos"
+		}
+	case sch.Type_INT96:
+		// the deprecated timestamp type that older versions of Spark
+		// write, which can only be read (it's written as INT64 nanos)
+		f.Type = "time.Time"
+		f.Logical = "timestamp"
+		f.Unit = "nanos"
+	case sch.Type_FLOAT:
+		f.Type = "float32"
+	case sch.Type_DOUBLE:
+		f.Type = "float64"
+	case sch.Type_BYTE_ARRAY:
+		// a []byte column looks the same as a string column
+		// unless it's annotated as BSON (or WKB)
+		f.Type = "string"
+		switch ct {
+		case sch.ConvertedType_ENUM:
+			f.Logical = "enum"
+		case sch.ConvertedType_JSON:
+			f.Logical = "json"
+		case sch.ConvertedType_BSON:
+			f.Type = "[]byte"
+			f.Logical = "bson"
+		}
+
+		switch lt := se.LogicalType; {
+		case lt == nil:
+		case lt.GEOMETRY != nil:
+			f.Type, f.Logical, f.CRS = "[]byte", "geometry", lt.GEOMETRY.GetCrs()
+		case lt.GEOGRAPHY != nil:
+			f.Type, f.Logical, f.CRS = "[]byte", "geography", lt.GEOGRAPHY.GetCrs()
+		}
+	case sch.Type_FIXED_LEN_BYTE_ARRAY:
+		f.Type = fmt.Sprintf("[%d]byte", se.GetTypeLength())
+		if ct == sch.ConvertedType_INTERVAL {
+			f.Type = "time.Duration"
+		}
+		if se.LogicalType != nil && se.LogicalType.UUID != nil {
+			f.Logical = "uuid"
+		}
+		if se.LogicalType != nil && se.LogicalType.FLOAT16 != nil && se.GetTypeLength() == 2 {
+			f.Type = "uint16"
+			f.Logical = "float16"
+		}
+	default:
+		return fmt.Errorf("unsupported parquet type %s for column %s", se.GetType(), se.Name)
+	}
+	return nil
+}
+
+// Schema returns the parquet schema of the fields of parent
+// (the first element is the root of the schema).
+func Schema(parent flds.Field) []*sch.SchemaElement {
+	n := int32(len(parent.Children))
+	out := []*sch.SchemaElement{{Name: "root", NumChildren: &n}}
+	for _, f := range parent.Children {
+		out = append(out, schemaElements(f)...)
+	}
+	return out
+}
+
+func schemaElements(f flds.Field) []*sch.SchemaElement {
+	rt := sch.FieldRepetitionType(f.RepetitionType)
+	se := &sch.SchemaElement{Name: f.ColumnName, RepetitionType: &rt}
+	if f.Primitive() {
+		schemaType(se, f)
+		return []*sch.SchemaElement{se}
+	}
+
+	n := int32(len(f.Children))
+	se.NumChildren = &n
+	if f.IsMap() {
+		ct := sch.ConvertedType_MAP
+		se.ConvertedType = &ct
+	}
+
+	out := []*sch.SchemaElement{se}
+	for _, ch := range f.Children {
+		out = append(out, schemaElements(ch)...)
+	}
+	return out
+}
+
+// schemaTypes are the physical and converted types
+// of each go type (a converted type of -1 means none).
+var schemaTypes = map[string]struct {
+	typ sch.Type
+	ct  sch.ConvertedType
+}{
+	"bool":          {sch.Type_BOOLEAN, -1},
+	"int8":          {sch.Type_INT32, sch.ConvertedType_INT_8},
+	"int16":         {sch.Type_INT32, sch.ConvertedType_INT_16},
+	"uint8":         {sch.Type_INT32, sch.ConvertedType_UINT_8},
+	"uint16":        {sch.Type_INT32, sch.ConvertedType_UINT_16},
+	"int32":         {sch.Type_INT32, -1},
+	"uint32":        {sch.Type_INT32, sch.ConvertedType_UINT_32},
+	"int64":         {sch.Type_INT64, -1},
+	"uint64":        {sch.Type_INT64, sch.ConvertedType_UINT_64},
+	"float32":       {sch.Type_FLOAT, -1},
+	"float64":       {sch.Type_DOUBLE, -1},
+	"string":        {sch.Type_BYTE_ARRAY, -1},
+	"[]byte":        {sch.Type_BYTE_ARRAY, -1},
+	"time.Time":     {sch.Type_INT64, sch.ConvertedType_TIMESTAMP_MILLIS},
+	"time.Duration": {sch.Type_FIXED_LEN_BYTE_ARRAY, sch.ConvertedType_INTERVAL},
+	// a column that is always null still needs a physical type
+	"struct{}": {sch.Type_INT32, -1},
+}
+
+func schemaType(se *sch.SchemaElement, f flds.Field) {
+	st, ok := schemaTypes[f.Type]
+	if n, fixed := f.FixedLen(); fixed {
+		st.typ, st.ct, ok = sch.Type_FIXED_LEN_BYTE_ARRAY, -1, true
+		l := int32(n)
+		se.TypeLength = &l
+	}
+
+	if f.Type == "big.Int" {
+		st.typ, st.ct, ok = sch.Type_FIXED_LEN_BYTE_ARRAY, -1, true
+		l := int32(f.BigIntLen())
+		se.TypeLength = &l
+	}
+
+	if !ok {
+		return
+	}
+
+	if st.ct == sch.ConvertedType_INTERVAL {
+		l := int32(12)
+		se.TypeLength = &l
+	}
+
+	switch f.Logical {
+	case "date":
+		st.typ, st.ct = sch.Type_INT32, sch.ConvertedType_DATE
+	case "timestamp":
+		switch f.Unit {
+		case "micros":
+			st.ct = sch.ConvertedType_TIMESTAMP_MICROS
+		case "nanos":
+			st.ct = -1
+			se.LogicalType = &sch.LogicalType{TIMESTAMP: &sch.TimestampType{
+				IsAdjustedToUTC: true,
+				Unit:            &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+			}}
+		}
+	case "time":
+		se.TypeLength = nil
+		switch f.Unit {
+		case "millis":
+			st.typ, st.ct = sch.Type_INT32, sch.ConvertedType_TIME_MILLIS
+		case "micros":
+			st.typ, st.ct = sch.Type_INT64, sch.ConvertedType_TIME_MICROS
+		case "nanos":
+			st.typ, st.ct = sch.Type_INT64, -1
+			se.LogicalType = &sch.LogicalType{TIME: &sch.TimeType{
+				IsAdjustedToUTC: true,
+				Unit:            &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+			}}
+		}
+	case "enum":
+		st.ct = sch.ConvertedType_ENUM
+	case "json":
+		st.ct = sch.ConvertedType_JSON
+	case "bson":
+		st.ct = sch.ConvertedType_BSON
+	case "uuid":
+		se.LogicalType = &sch.LogicalType{UUID: sch.NewUUIDType()}
+	case "float16":
+		st.typ, st.ct = sch.Type_FIXED_LEN_BYTE_ARRAY, -1
+		l := int32(2)
+		se.TypeLength = &l
+		se.LogicalType = &sch.LogicalType{FLOAT16: &sch.Float16Type{}}
+	case "null":
+		se.LogicalType = &sch.LogicalType{UNKNOWN: sch.NewNullType()}
+	case "geometry":
+		se.LogicalType = &sch.LogicalType{GEOMETRY: &sch.GeometryType{Crs: crs(f)}}
+	case "geography":
+		se.LogicalType = &sch.LogicalType{GEOGRAPHY: &sch.GeographyType{Crs: crs(f)}}
+	case "decimal":
+		st.ct = sch.ConvertedType_DECIMAL
+		p, s := int32(f.Precision), int32(f.Scale)
+		se.Precision, se.Scale = &p, &s
+	}
+
+	se.Type = &st.typ
+	if st.ct != -1 {
+		se.ConvertedType = &st.ct
+	}
+}
+
+// crs is the crs of a geometry or geography
+// field, which is nil if it isn't set.
+func crs(f flds.Field) *string {
+	if f.CRS == "" {
+		return nil
+	}
+	return &f.CRS
+}
+
+// PrintSchema writes schema (the first element is the root) to w in
+// the format parquet-tools uses: a line for each element with its
+// repetition type, physical type, name and annotation, indented by
+// how deep it is in the schema.
+func PrintSchema(w io.Writer, schema []*sch.SchemaElement) error {
+	if len(schema) == 0 {
+		return fmt.Errorf("empty schema")
+	}
+
+	fmt.Fprintf(w, "message %s {\n", schema[0].Name)
+	n, err := printChildren(w, schema[0], schema[1:], 1)
+	if err != nil {
+		return err
+	}
+
+	if n != len(schema)-1 {
+		return fmt.Errorf("schema has %d elements that aren't in the root's children", len(schema)-1-n)
+	}
+	_, err = fmt.Fprintln(w, "}")
+	return err
+}
+
+// printChildren prints the children of se and returns
+// the number of schema elements they used.
+func printChildren(w io.Writer, se *sch.SchemaElement, schema []*sch.SchemaElement, depth int) (int, error) {
+	indent := strings.Repeat("  ", depth)
+	var i int
+	for j := 0; j < int(se.GetNumChildren()); j++ {
+		if i >= len(schema) {
+			return 0, fmt.Errorf("group %s is missing children", se.Name)
+		}
+
+		ch := schema[i]
+		i++
+		rt := strings.ToLower(ch.GetRepetitionType().String())
+		if ch.GetNumChildren() == 0 {
+			typ := strings.ToLower(ch.GetType().String())
+			switch ch.GetType() {
+			case sch.Type_BYTE_ARRAY:
+				typ = "binary"
+			case sch.Type_FIXED_LEN_BYTE_ARRAY:
+				typ = fmt.Sprintf("%s(%d)", typ, ch.GetTypeLength())
+			}
+			fmt.Fprintf(w, "%s%s %s %s%s;\n", indent, rt, typ, ch.Name, annotation(ch))
+			continue
+		}
+
+		fmt.Fprintf(w, "%s%s group %s%s {\n", indent, rt, ch.Name, annotation(ch))
+		n, err := printChildren(w, ch, schema[i:], depth+1)
+		if err != nil {
+			return 0, err
+		}
+		i += n
+		fmt.Fprintf(w, "%s}\n", indent)
+	}
+	return i, nil
+}
+
+// annotation returns the converted (or logical) type
+// of se the way PrintSchema prints it.
+func annotation(se *sch.SchemaElement) string {
+	if se.ConvertedType != nil {
+		ct := se.GetConvertedType()
+		if ct == sch.ConvertedType_DECIMAL {
+			return fmt.Sprintf(" (DECIMAL(%d,%d))", se.GetPrecision(), se.GetScale())
+		}
+		return fmt.Sprintf(" (%s)", ct)
+	}
+
+	lt := se.LogicalType
+	switch {
+	case lt == nil:
+		return ""
+	case lt.TIMESTAMP != nil:
+		return fmt.Sprintf(" (TIMESTAMP(%s,%t))", timeUnit(lt.TIMESTAMP.Unit), lt.TIMESTAMP.IsAdjustedToUTC)
+	case lt.TIME != nil:
+		return fmt.Sprintf(" (TIME(%s,%t))", timeUnit(lt.TIME.Unit), lt.TIME.IsAdjustedToUTC)
+	case lt.UUID != nil:
+		return " (UUID)"
+	case lt.FLOAT16 != nil:
+		return " (FLOAT16)"
+	case lt.UNKNOWN != nil:
+		return " (UNKNOWN)"
+	case lt.GEOMETRY != nil:
+		return geoAnnotation("GEOMETRY", lt.GEOMETRY.Crs)
+	case lt.GEOGRAPHY != nil:
+		return geoAnnotation("GEOGRAPHY", lt.GEOGRAPHY.Crs)
+	}
+	return ""
+}
+
+func geoAnnotation(name string, crs *string) string {
+	if crs == nil {
+		return fmt.Sprintf(" (%s)", name)
+	}
+	return fmt.Sprintf(" (%s(%s))", name, *crs)
+}
+
+func timeUnit(u *sch.TimeUnit) string {
+	switch {
+	case u == nil:
+	case u.IsSetMICROS():
+		return "MICROS"
+	case u.IsSetNANOS():
+		return "NANOS"
+	}
+	return "MILLIS"
+}
+
+// CheckSchema makes sure that the fields of parent and the fields
+// of the parquet schema (see Parquet) have the same columns, in the
+// same order, with the same repetition types.  parse.Fields and the
+// schema that is written for its fields should always agree, so a
+// mismatch is a bug in one or the other.
+func CheckSchema(parent flds.Field, schema []*sch.SchemaElement) error {
+	fromSchema, err := Parquet(schema)
+	if err != nil {
+		return err
+	}
+
+	a, b := parent.Fields(), fromSchema.Fields()
+	if len(a) != len(b) {
+		return fmt.Errorf("the struct has %d columns and the schema has %d", len(a), len(b))
+	}
+
+	for i := range a {
+		col := strings.Join(a[i].ColumnNames(), ".")
+		if s := strings.Join(b[i].ColumnNames(), "."); col != s {
+			return fmt.Errorf("column %d is %s in the struct and %s in the schema", i, col, s)
+		}
+
+		if ra, rb := a[i].RepetitionTypes(), b[i].RepetitionTypes(); !reflect.DeepEqual(ra, rb) {
+			return fmt.Errorf("column %s has repetition types %v in the struct and %v in the schema", col, ra, rb)
+		}
+	}
+	return nil
+}
@@ -1,11 +1,20 @@
 package parse
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"go/parser"
+	"go/printer"
 	"go/token"
+	gotypes "go/types"
 	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"go/ast"
 
@@ -13,8 +22,6 @@ import (
 	flds "github.com/parsyl/parquet/cmd/parquetgen/fields"
 )
 
-const letters = "abcdefghijklmnopqrstuvwxyz"
-
 type field struct {
 	Field     fields.Field
 	tagNames  []string
@@ -27,6 +34,11 @@ type field struct {
 	err       error
 }
 
+// Field is a field of the struct read by Fields, whose
+// MaxDefinitionLevel and MaxRepetitionLevel are the
+// levels of its column.
+type Field = fields.Field
+
 // Result holds the fields and errors that are generated
 // by reading a go struct.
 type Result struct {
@@ -36,13 +48,52 @@ type Result struct {
 	Errors []error
 }
 
+// Err returns nil if there are no Errors, otherwise it returns
+// an error that lists each of them on its own line.
+func (r *Result) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(r.Errors))
+	for i, err := range r.Errors {
+		msgs[i] = err.Error()
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
+
+// Options change how Fields reads a struct.
+type Options struct {
+	// ColumnName returns the column name of a field whose tag doesn't
+	// set one (by default the column name is the field's name).
+	ColumnName func(name string) string
+}
+
+// SnakeCase is an option for Fields that makes the column name of
+// each field that isn't named by its tag the snake case of the
+// field's name (CreatedAt is created_at and UserID is user_id).
+func SnakeCase(o *Options) {
+	o.ColumnName = snakeCase
+}
+
+// NameStrategies are the options for Fields that can be
+// chosen by name (with the -name-strategy flag).
+var NameStrategies = map[string]func(*Options){
+	"snake": SnakeCase,
+}
+
 // Fields gets the fields of the given struct.
 // pth must be a go file that defines the typ struct.
 // Any embedded structs must also be in that same file.
-func Fields(typ, pth string) (*Result, error) {
+func Fields(typ, pth string, opts ...func(*Options)) (*Result, error) {
 	fullTyp := typ
 	typ = getType(fullTyp)
 
+	o := Options{ColumnName: func(name string) string { return name }}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, pth, nil, 0)
 	if err != nil {
@@ -56,7 +107,7 @@ func Fields(typ, pth string) (*Result, error) {
 		return nil, fmt.Errorf("could not find %s", typ)
 	}
 
-	fields, err := getFields(f.n)
+	fields, pos, err := getFields(fset, f.n, o)
 	if err != nil {
 		return nil, err
 	}
@@ -66,15 +117,42 @@ func Fields(typ, pth string) (*Result, error) {
 		return nil, fmt.Errorf("could not find %s", typ)
 	}
 
-	errs := getChildren(&parent, fields)
+	errs := getChildren(&parent, fields, pos, getMarshalers(packageFiles(fset, pth, file)))
+	out := flds.Field{Type: typ, Children: parent.Children}
+	errs = append(errs, duplicates(out)...)
 
 	return &Result{
-		Parent: flds.Field{Type: typ, Children: parent.Children},
+		Parent: out,
 		Errors: errs,
 	}, nil
 }
 
-func getChildren(parent *flds.Field, fields map[string]flds.Field) []error {
+// duplicates checks the flattened fields for column
+// names that are used by more than one field.
+func duplicates(parent flds.Field) []error {
+	var errs []error
+	seen := map[string]string{}
+	for _, f := range parent.Fields() {
+		col := strings.Join(f.ColumnNames(), ".")
+		name := strings.Join(f.FieldNames(), ".")
+		if prev, ok := seen[col]; ok {
+			errs = append(errs, fmt.Errorf("duplicate column name: %s (fields %s and %s)", col, prev, name))
+			continue
+		}
+		seen[col] = name
+	}
+	return errs
+}
+
+// positions maps each struct field (like "Person.Name") to
+// where it is defined (like "person.go:12").
+type positions map[string]string
+
+func (p positions) of(parent, name string) string {
+	return p[parent+"."+name]
+}
+
+func getChildren(parent *flds.Field, fields map[string]flds.Field, pos positions, ms map[string]marshaler) []error {
 	var children []flds.Field
 	var errs []error
 	p, ok := fields[parent.Type]
@@ -83,21 +161,61 @@ func getChildren(parent *flds.Field, fields map[string]flds.Field) []error {
 	}
 
 	for _, child := range p.Children {
-		if child.Primitive() {
+		if child.IsMap() {
+			if err := mapChildren(parent, &child, pos); err != nil {
+				errs = append(errs, err)
+				continue
+			}
 			children = append(children, child)
 			continue
 		}
 
-		f, ok := fields[child.Type]
-		if !ok {
-			f, ok = fields[child.Type]
-			if !ok {
-				errs = append(errs, fmt.Errorf("unsupported type %+v", child.Type))
+		if m, ok := ms[child.Type]; ok {
+			if m.err != nil {
+				errs = append(errs, fmt.Errorf("unsupported type %s for field %s at %s: %s", child.Type, child.Name, pos.of(parent.Type, child.Name), m.err))
+				continue
+			}
+			child.Named, child.Type, child.Marshaler = child.Type, m.typ, true
+		} else if typ, ok := underlying(child.Type, fields); ok {
+			child.Named, child.Type = child.Type, typ
+		}
+
+		if child.Primitive() {
+			if child.Logical != "" && !child.SupportsLogical() {
+				errs = append(errs, fmt.Errorf("unsupported logical type %s for field %s (%s) at %s", child.Logical, child.Name, child.Type, pos.of(parent.Type, child.Name)))
+				continue
+			}
+			if !child.SupportsUnit() {
+				errs = append(errs, fmt.Errorf("unsupported unit %s for field %s (%s) at %s, it must be millis, micros or nanos and logical must be timestamp or time", child.Unit, child.Name, child.Type, pos.of(parent.Type, child.Name)))
+				continue
+			}
+			if !child.SupportsCRS() {
+				errs = append(errs, fmt.Errorf("unsupported crs %s for field %s (%s) at %s, logical must be geometry or geography", child.CRS, child.Name, child.Type, pos.of(parent.Type, child.Name)))
 				continue
 			}
+			if !child.SupportsEncoding() {
+				errs = append(errs, fmt.Errorf("unsupported encoding %s for field %s (%s) at %s", child.Encoding, child.Name, child.Type, pos.of(parent.Type, child.Name)))
+				continue
+			}
+			if child.Logical == "decimal" {
+				if err := checkDecimal(child); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+			children = append(children, child)
+			continue
+		}
+
+		// a named type that isn't a struct and wasn't
+		// resolved above has no columns of its own
+		f, ok := fields[child.Type]
+		if !ok || f.Type != child.Type {
+			errs = append(errs, fmt.Errorf("unsupported type %s for field %s at %s", child.Type, child.Name, pos.of(parent.Type, child.Name)))
+			continue
 		}
 
-		errs = append(errs, getChildren(&child, fields)...)
+		errs = append(errs, getChildren(&child, fields, pos, ms)...)
 
 		f.Name = child.Name
 		f.Type = child.Type
@@ -105,7 +223,9 @@ func getChildren(parent *flds.Field, fields map[string]flds.Field) []error {
 		f.Children = child.Children
 		f.RepetitionType = child.RepetitionType
 
-		if child.Embedded {
+		// an embedded pointer can be nil, so its fields keep
+		// the optional level of the embedded struct
+		if child.Embedded && child.RepetitionType != flds.Optional {
 			for _, ch := range f.Children {
 				children = append(children, ch)
 			}
@@ -117,55 +237,325 @@ func getChildren(parent *flds.Field, fields map[string]flds.Field) []error {
 	return errs
 }
 
-func isPrivate(x *ast.Field) bool {
-	var s string
-	if len(x.Names) == 0 {
-		s = fmt.Sprintf("%s", x.Type)
-	} else {
-		s = fmt.Sprintf("%s", x.Names[0])
+// underlying returns the type that typ is declared as when it's a named
+// scalar type (like float32 for type Celsius float32 or time.Time for
+// type Date time.Time), following named types that are declared as
+// other named types.
+func underlying(typ string, fields map[string]flds.Field) (string, bool) {
+	named := typ
+	// each step is a different named type, so there
+	// can't be more of them than there are types
+	for range fields {
+		f, ok := fields[named]
+		if !ok || f.Type == named || len(f.Children) > 0 {
+			return "", false
+		}
+
+		named = f.Type
+		if types[named] {
+			return named, true
+		}
 	}
-	return strings.Contains(letters, string(s[0]))
+	return "", false
 }
 
-func getFields(n map[string]ast.Node) (map[string]fields.Field, error) {
-	fields := map[string]flds.Field{}
-	for k, n := range n {
-		_, ok := n.(*ast.TypeSpec)
-		if !ok {
+// marshaler is a type with a MarshalParquet method, which returns the
+// value (of type typ) that it's written as, and an UnmarshalParquet
+// method, which sets it to a value that was read:
+//
+//	func (m Money) MarshalParquet() int64
+//	func (m *Money) UnmarshalParquet(v int64)
+//
+// err is set if it only has one of them or they use different types.
+type marshaler struct {
+	typ string
+	err error
+}
+
+// packageFiles returns file (which was parsed from pth) and the
+// other files in its directory that are in the same package, which
+// is where the methods of its types can be.  A file that can't be
+// parsed is left out (the package won't build anyway).
+func packageFiles(fset *token.FileSet, pth string, file *ast.File) []*ast.File {
+	out := []*ast.File{file}
+	names, err := filepath.Glob(filepath.Join(filepath.Dir(pth), "*.go"))
+	if err != nil {
+		return out
+	}
+
+	for _, name := range names {
+		if filepath.Base(name) == filepath.Base(pth) {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil || f.Name.Name != file.Name.Name {
 			continue
 		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// getMarshalers finds the types in files that have a MarshalParquet
+// or UnmarshalParquet method.
+func getMarshalers(files []*ast.File) map[string]marshaler {
+	marshal := map[string]string{}
+	unmarshal := map[string]string{}
+	for _, file := range files {
+		for _, d := range file.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+
+			addMarshaler(fn, marshal, unmarshal)
+		}
+	}
+
+	out := map[string]marshaler{}
+	for _, m := range []map[string]string{marshal, unmarshal} {
+		for typ := range m {
+			out[typ] = checkMarshaler(typ, marshal, unmarshal)
+		}
+	}
+	return out
+}
+
+// addMarshaler adds the type that the method fn is declared on to
+// marshal or unmarshal if fn is its MarshalParquet or UnmarshalParquet
+// method.  The type is mapped to the type that the method returns or
+// takes ("" if the method's signature is wrong).
+func addMarshaler(fn *ast.FuncDecl, marshal, unmarshal map[string]string) {
+	recv := embeddedName(fn.Recv.List[0].Type)
+	params, results := fn.Type.Params.List, fn.Type.Results
+	switch fn.Name.Name {
+	case "MarshalParquet":
+		if len(params) == 0 && results != nil && len(results.List) == 1 && len(results.List[0].Names) < 2 {
+			marshal[recv] = gotypes.ExprString(results.List[0].Type)
+		} else {
+			marshal[recv] = ""
+		}
+	case "UnmarshalParquet":
+		_, ptr := fn.Recv.List[0].Type.(*ast.StarExpr)
+		if ptr && results == nil && len(params) == 1 && len(params[0].Names) < 2 {
+			unmarshal[recv] = gotypes.ExprString(params[0].Type)
+		} else {
+			unmarshal[recv] = ""
+		}
+	}
+}
+
+// checkMarshaler checks the MarshalParquet and UnmarshalParquet
+// methods of typ (see marshaler).
+func checkMarshaler(typ string, marshal, unmarshal map[string]string) marshaler {
+	m, ok := marshal[typ]
+	if !ok {
+		return marshaler{err: fmt.Errorf("%s has an UnmarshalParquet method but no MarshalParquet method", typ)}
+	}
+	if m == "" || !types[m] {
+		return marshaler{err: fmt.Errorf("%s.MarshalParquet must take no arguments and return one of %s", typ, typeNames())}
+	}
+
+	u, ok := unmarshal[typ]
+	if !ok {
+		return marshaler{err: fmt.Errorf("%s has a MarshalParquet method but no UnmarshalParquet method", typ)}
+	}
+	if u != m {
+		return marshaler{err: fmt.Errorf("%s.UnmarshalParquet must have a pointer receiver, take one %s and return nothing", typ, m)}
+	}
+	return marshaler{typ: m}
+}
+
+// typeNames returns the names of the types that
+// a MarshalParquet method can return.
+func typeNames() string {
+	var out []string
+	for typ := range types {
+		out = append(out, typ)
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
+}
 
+// checkDecimal makes sure the precision and scale of a decimal
+// field fit in its go type.
+func checkDecimal(f flds.Field) error {
+	if max := f.MaxPrecision(); f.Precision < 1 || f.Precision > max {
+		return fmt.Errorf("invalid decimal precision %d for field %s (%s), it must be between 1 and %d", f.Precision, f.Name, f.Type, max)
+	}
+	if f.Scale < 0 || f.Scale > f.Precision {
+		return fmt.Errorf("invalid decimal scale %d for field %s, it must be between 0 and the precision (%d)", f.Scale, f.Name, f.Precision)
+	}
+	return nil
+}
+
+// isPrivate is true if the embedded field x is unexported, in which
+// case the generated code can't get to it.
+func isPrivate(x *ast.Field) bool {
+	return !token.IsExported(embeddedName(x.Type))
+}
+
+// embeddedName returns the name of an embedded field
+// (Being for Being, *Being and pkg.Being).
+func embeddedName(x ast.Expr) string {
+	switch t := x.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	default:
+		return ""
+	}
+}
+
+// mapChildren sets the children of a map field to the parquet
+// MAP structure: a repeated key_value group with a key and a value.
+func mapChildren(parent, child *flds.Field, pos positions) error {
+	if parent.Name != "" {
+		return fmt.Errorf("unsupported type %s for field %s at %s (only the top level struct can have maps)", child.Type, child.Name, pos.of(parent.Type, child.Name))
+	}
+
+	k, v := child.MapTypes()
+	if !mapTypes[k] || !mapTypes[v] {
+		return fmt.Errorf("unsupported type %s for field %s at %s", child.Type, child.Name, pos.of(parent.Type, child.Name))
+	}
+
+	child.Children = []flds.Field{
+		{
+			ColumnName:     "key_value",
+			RepetitionType: flds.Repeated,
+			Children: []flds.Field{
+				{Name: "Key", Type: k, ColumnName: "key", RepetitionType: flds.Required},
+				{Name: "Value", Type: v, ColumnName: "value", RepetitionType: flds.Required},
+			},
+		},
+	}
+	return nil
+}
+
+func getFields(fset *token.FileSet, n map[string]ast.Node, o Options) (map[string]fields.Field, positions, error) {
+	fields := map[string]flds.Field{}
+	pos := positions{}
+	position := func(x *ast.Field) string {
+		p := fset.Position(x.Pos())
+		return fmt.Sprintf("%s:%d", filepath.Base(p.Filename), p.Line)
+	}
+
+	// addStruct adds the struct st as typ.  An anonymous struct field
+	// (like Meta struct{A int32}) becomes a group whose type is named
+	// after its parent and the field (rowMeta), so it gets added too.
+	var addStruct func(typ string, st *ast.StructType)
+	addStruct = func(typ string, st *ast.StructType) {
 		parent := flds.Field{
-			Type: k,
+			Type: typ,
 		}
 
-		ast.Inspect(n, func(n ast.Node) bool {
-			if n == nil {
-				return false
+		for _, x := range st.Fields.List {
+			// unexported fields are skipped
+			for _, name := range x.Names {
+				if !name.IsExported() {
+					continue
+				}
+				f, skip := getField(name.Name, x, o)
+				if skip {
+					continue
+				}
+				if anon := anonymousStruct(x.Type); anon != nil {
+					f.Type = anonymousName(typ, f.Name)
+					addStruct(f.Type, anon)
+					a := fields[f.Type]
+					a.Anonymous = source(fset, anon)
+					fields[f.Type] = a
+				}
+				parent.Children = append(parent.Children, f)
+				pos[typ+"."+f.Name] = position(x)
 			}
 
-			switch x := n.(type) {
-			case *ast.Field:
-				if len(x.Names) == 1 && !isPrivate(x) {
-					f, skip := getField(x.Names[0].Name, x, nil)
-					if !skip {
-						parent.Children = append(parent.Children, f)
-					}
-				} else if len(x.Names) == 0 && !isPrivate(x) {
-					f, skip := getField(fmt.Sprintf("%s", x.Type), x, nil)
-					f.Embedded = true
-					if !skip {
-						parent.Children = append(parent.Children, f)
-					}
+			if len(x.Names) == 0 && !isPrivate(x) {
+				f, skip := getField(embeddedName(x.Type), x, o)
+				f.Embedded = true
+				if !skip {
+					parent.Children = append(parent.Children, f)
+					pos[typ+"."+f.Name] = position(x)
 				}
 			}
-			return true
-		})
+		}
+
+		fields[typ] = parent
+	}
+
+	for k, n := range n {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+
+		// any other type (like type Celsius float32 or type Date
+		// time.Time) keeps the type it's declared as so it can be
+		// resolved later, or reported if it can't be
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			fields[k] = flds.Field{Type: gotypes.ExprString(ts.Type)}
+			continue
+		}
+
+		addStruct(k, st)
+	}
+
+	return fields, pos, nil
+}
 
-		fields[k] = parent
+// anonymousStruct returns the anonymous struct of a field of type x
+// (struct{...}, *struct{...} or []struct{...}).  It returns nil for
+// struct{} since that is the type of a column that is always null.
+func anonymousStruct(x ast.Expr) *ast.StructType {
+	switch t := x.(type) {
+	case *ast.StarExpr:
+		return anonymousStruct(t.X)
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return nil
+		}
+		return anonymousStruct(t.Elt)
+	case *ast.StructType:
+		if len(t.Fields.List) == 0 {
+			return nil
+		}
+		return t
+	default:
+		return nil
 	}
+}
 
-	return fields, nil
+// byteSlice is true if x is []byte or [N]byte (where N is a number).
+func byteSlice(x ast.Expr) bool {
+	at, ok := x.(*ast.ArrayType)
+	if !ok {
+		return false
+	}
+	if _, ok := at.Len.(*ast.BasicLit); at.Len != nil && !ok {
+		return false
+	}
+	id, ok := at.Elt.(*ast.Ident)
+	return ok && id.Name == "byte"
+}
+
+// anonymousName makes up the name of the type of the anonymous
+// struct field name of typ (the Meta field of Row is rowMeta).
+func anonymousName(typ, name string) string {
+	r, n := utf8.DecodeRuneInString(typ)
+	return string(unicode.ToLower(r)) + typ[n:] + name
+}
+
+// source returns the go source of x, formatted like gofmt would.
+func source(fset *token.FileSet, x ast.Node) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	cfg.Fprint(&buf, fset, x)
+	return buf.String()
 }
 
 func getType(typ string) string {
@@ -173,24 +563,60 @@ func getType(typ string) string {
 	return parts[len(parts)-1]
 }
 
-func getField(name string, x ast.Node, parent *flds.Field) (flds.Field, bool) {
-	var typ, tag string
+func getField(name string, x ast.Node, o Options) (flds.Field, bool) {
+	var typ string
+	var tg tag
 	var optional, repeated bool
 	ast.Inspect(x, func(n ast.Node) bool {
 		switch t := n.(type) {
 		case *ast.Field:
 			if t.Tag != nil {
-				tag = parseTag(t.Tag.Value)
+				tg = parseTag(t.Tag.Value)
 			}
 			typ = fmt.Sprintf("%s", t.Type)
 		case *ast.ArrayType:
 			at := n.(*ast.ArrayType)
 			s := fmt.Sprintf("%v", at.Elt)
-			typ = s
+			if s == "byte" && at.Len == nil {
+				typ = "[]byte"
+				return false
+			}
+			if l, ok := at.Len.(*ast.BasicLit); ok && s == "byte" {
+				typ = fmt.Sprintf("[%s]byte", l.Value)
+				return false
+			}
 			repeated = true
+			// each element of a slice of byte slices (like
+			// Attachments [][]byte) is a byte array value
+			if byteSlice(at.Elt) {
+				typ = gotypes.ExprString(at.Elt)
+				return false
+			}
+			// a nil element of a slice can't be written, so slices
+			// of pointers are left with a type that isn't supported
+			if _, ok := at.Elt.(*ast.StarExpr); ok {
+				typ = "[]" + gotypes.ExprString(at.Elt)
+				return false
+			}
+			typ = s
+		case *ast.MapType:
+			typ = gotypes.ExprString(t)
+			if optional {
+				typ = "*" + typ
+			} else if repeated {
+				typ = "[]" + typ
+			}
+			return false
 		case *ast.StarExpr:
 			optional = true
 			typ = fmt.Sprintf("%s", t.X)
+		case *ast.StructType:
+			// an anonymous struct (like struct{}, which is the
+			// type of a column that is always null)
+			typ = gotypes.ExprString(t)
+			return false
+		case *ast.SelectorExpr:
+			typ = fmt.Sprintf("%s.%s", t.X, t.Sel)
 		case ast.Expr:
 			s := fmt.Sprintf("%v", t)
 			_, ok := types[s]
@@ -201,32 +627,121 @@ func getField(name string, x ast.Node, parent *flds.Field) (flds.Field, bool) {
 		return true
 	})
 
-	if tag == "" {
-		tag = name
+	if tg.name == "" {
+		tg.name = o.ColumnName(name)
+	}
+
+	if (tg.logical == "timestamp" || tg.logical == "time") && tg.unit == "" {
+		tg.unit = "millis"
 	}
 
 	rt := fields.Required
-	if repeated {
+	if strings.HasPrefix(typ, "map[") {
+		// a nil map is written as null
+		rt = fields.Optional
+	} else if repeated {
 		rt = fields.Repeated
-	} else if optional {
+	} else if optional || (tg.optional && typ == "[]byte") {
 		rt = fields.Optional
 	}
 
 	return flds.Field{
 		Type:           typ,
 		Name:           name,
-		ColumnName:     tag,
+		ColumnName:     tg.name,
 		RepetitionType: rt,
-	}, tag == "-"
+		Logical:        tg.logical,
+		Precision:      tg.precision,
+		Scale:          tg.scale,
+		Unit:           tg.unit,
+		CRS:            tg.crs,
+		Encoding:       tg.encoding,
+	}, tg.name == "-"
 }
 
-func parseTag(t string) string {
+// snakeCase returns name in snake case.  An acronym is kept as one
+// word, so HTTPStatus is http_status and UserID is user_id.
+func snakeCase(name string) string {
+	r := []rune(name)
+	var out []rune
+	for i, c := range r {
+		if unicode.IsUpper(c) && i > 0 {
+			prev := r[i-1]
+			next := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				out = append(out, '_')
+			}
+		}
+		out = append(out, unicode.ToLower(c))
+	}
+	return string(out)
+}
+
+// tag holds the options that can be set with a parquet struct tag.
+// The column name can be set by itself (`parquet:"id"`) or along
+// with other options, which are comma separated key=value pairs
+// (`parquet:"name=dob,logical=date"`) except for optional, which is
+// just its name (`parquet:"thumbnail,optional"`).  The README has a
+// table of the options.
+type tag struct {
+	name      string
+	logical   string
+	optional  bool
+	precision int
+	scale     int
+	unit      string
+	crs       string
+	encoding  string
+}
+
+func parseTag(t string) tag {
+	var out tag
 	i := strings.Index(t, `parquet:"`)
 	if i == -1 {
-		return ""
+		return out
 	}
 	t = t[i+9:]
-	return t[:strings.Index(t, `"`)]
+	t = t[:strings.Index(t, `"`)]
+
+	for i, part := range strings.Split(t, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 1 {
+			if kv[0] == "optional" {
+				out.optional = true
+			} else if i == 0 {
+				out.name = kv[0]
+			}
+			continue
+		}
+
+		switch kv[0] {
+		case "name":
+			out.name = kv[1]
+		case "logical":
+			out.logical = kv[1]
+		case "precision":
+			out.precision = atoi(kv[1])
+		case "scale":
+			out.scale = atoi(kv[1])
+		case "unit":
+			out.unit = kv[1]
+		case "crs":
+			out.crs = kv[1]
+		case "encoding":
+			out.encoding = kv[1]
+		}
+	}
+	return out
+}
+
+// atoi returns -1 for anything that isn't a number so
+// it can't be mistaken for a valid precision or scale.
+func atoi(s string) int {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return -1
+	}
+	return i
 }
 
 type visitorFunc func(n ast.Node) ast.Visitor
@@ -260,6 +775,10 @@ func (f *finder) findTypes(n ast.Node) ast.Visitor {
 }
 
 var types = map[string]bool{
+	"int8":    true,
+	"int16":   true,
+	"uint8":   true,
+	"uint16":  true,
 	"int32":   true,
 	"uint32":  true,
 	"int64":   true,
@@ -268,4 +787,22 @@ var types = map[string]bool{
 	"float64": true,
 	"bool":    true,
 	"string":  true,
+
+	"time.Time": true,
+	"[]byte":    true,
+}
+
+// mapTypes are the types that can be the key or value of a map.
+var mapTypes = map[string]bool{
+	"int8":    true,
+	"int16":   true,
+	"uint8":   true,
+	"uint16":  true,
+	"int32":   true,
+	"uint32":  true,
+	"int64":   true,
+	"uint64":  true,
+	"float32": true,
+	"float64": true,
+	"string":  true,
 }
@@ -1,6 +1,9 @@
 package parse_test
 
-import "time"
+import (
+	"math/big"
+	"time"
+)
 
 type Being struct {
 	ID  int32
@@ -31,6 +34,20 @@ type NewOrderPerson struct {
 	Anniversary *uint64
 }
 
+type Prices struct {
+	Discount float32
+	Price    float64
+	Rebate   *float32
+	Ratio    *float64
+}
+
+type Sizes struct {
+	Level int8
+	Count *int16
+	ID    int32
+	Total *int64
+}
+
 type IgnoreMe struct {
 	ID     int32  `parquet:"id"`
 	Secret string `parquet:"-"`
@@ -41,11 +58,99 @@ type Tagged struct {
 	Name string `parquet:"name"`
 }
 
+type Dated struct {
+	ID        int32      `parquet:"name=id"`
+	DOB       time.Time  `parquet:"name=dob,logical=date"`
+	Graduated *time.Time `parquet:"graduated,logical=date"`
+}
+
+type Blob struct {
+	Name      string `parquet:"name"`
+	Payload   []byte `parquet:"payload"`
+	Thumbnail []byte `parquet:"thumbnail,optional"`
+	Caption   *string
+}
+
+type Fixed struct {
+	ID       [16]byte  `parquet:"name=id,logical=uuid"`
+	Checksum [8]byte   `parquet:"checksum"`
+	Parent   *[16]byte `parquet:"parent"`
+}
+
+type Scored struct {
+	Tags   []string `parquet:"tags"`
+	Scores []int32  `parquet:"scores"`
+}
+
+type Contest struct {
+	Name   string `parquet:"name"`
+	Scored Scored `parquet:"scored"`
+}
+
+type Collision struct {
+	ID         int32  `parquet:"name=id"`
+	Identifier *int32 `parquet:"name=id"`
+	Name       string `parquet:"name"`
+}
+
+type EmbeddedCollision struct {
+	Being
+	Other int32 `parquet:"ID"`
+}
+
+type IgnoreNested struct {
+	ID      int32   `parquet:"id"`
+	Address Address `parquet:"-"`
+	Home    *Address
+}
+
+type IgnoreEmbedded struct {
+	Being `parquet:"-"`
+	Name  string `parquet:"name"`
+}
+
+type BadLogical struct {
+	ID   int32 `parquet:"name=id"`
+	Code int32 `parquet:"name=code,logical=date"`
+}
+
+type Priced struct {
+	ID       int32    `parquet:"name=id"`
+	Amount   int64    `parquet:"name=amount,logical=decimal,precision=18,scale=2"`
+	Discount *int32   `parquet:"name=discount,logical=decimal,precision=4"`
+	Total    [16]byte `parquet:"name=total,logical=decimal,precision=38,scale=4"`
+}
+
+type Ticket struct {
+	ID       int32   `parquet:"name=id"`
+	Status   string  `parquet:"name=status,logical=enum"`
+	Priority *string `parquet:"name=priority,logical=enum"`
+}
+
+type BadDecimal struct {
+	ID     int32   `parquet:"name=id"`
+	Amount int64   `parquet:"name=amount,logical=decimal,precision=19,scale=2"`
+	Rate   int32   `parquet:"name=rate,logical=decimal,precision=5,scale=6"`
+	Total  int64   `parquet:"name=total,logical=decimal"`
+	Big    [8]byte `parquet:"name=big,logical=decimal,precision=19"`
+}
+
 type Private struct {
 	Being
 	name string
 }
 
+type hidden struct {
+	Code string
+}
+
+type Secretive struct {
+	ID     int32  `parquet:"name=id"`
+	secret string `parquet:"name=secret"`
+	hidden
+	Name, nickname string
+}
+
 type Nested2 struct {
 	Info        Being
 	Anniversary *uint64
@@ -78,23 +183,43 @@ type OptionalDoubleNested struct {
 	OptionalNested OptionalNested
 }
 
+type Geo struct {
+	Lat float64
+	Lon *float64
+}
+
+type Address struct {
+	Street string
+	Geo    *Geo
+}
+
+type Resident struct {
+	Home *Address
+}
+
 type Unsupported struct {
 	Being
 	// This field will be ignored because it's not one of the
 	// supported types.
-	Time time.Time
+	Signal complex64
 }
 
 type SupportedAndUnsupported struct {
 	Happiness int64
 	x         int
-	T1        time.Time
+	S1        complex64
 	Being
 	y           int
-	T2          time.Time
+	S2          complex64
 	Anniversary *uint64
 }
 
+type Event struct {
+	ID        int32
+	CreatedAt time.Time  `parquet:"created_at"`
+	DeletedAt *time.Time `parquet:"deleted_at"`
+}
+
 type Slice struct {
 	IDs []int32 `parquet:"ids"`
 }
@@ -177,3 +302,313 @@ type A struct {
 	B
 	Name string
 }
+
+type Inventory struct {
+	ID     int32            `parquet:"name=id"`
+	Counts map[string]int32 `parquet:"name=counts"`
+}
+
+type Bin struct {
+	Counts map[string]int32 `parquet:"name=counts"`
+}
+
+type Warehouse struct {
+	ID     int32               `parquet:"name=id"`
+	Bin    Bin                 `parquet:"name=bin"`
+	Labels map[string][]string `parquet:"name=labels"`
+}
+
+type Unsigned struct {
+	Flags    uint8   `parquet:"name=flags"`
+	Port     *uint16 `parquet:"name=port"`
+	Count    uint32  `parquet:"name=count"`
+	Total    *uint64 `parquet:"name=total"`
+	Mask     *uint8  `parquet:"name=mask"`
+	Priority uint16  `parquet:"name=priority"`
+}
+
+type OptionalEmbedded struct {
+	*Being
+	Happiness int64
+}
+
+type LineItem struct {
+	SKU      string   `parquet:"sku"`
+	Quantity int32    `parquet:"quantity"`
+	Discount *float64 `parquet:"discount"`
+	Note     []byte   `parquet:"note"`
+}
+
+type Order struct {
+	ID        int64      `parquet:"id"`
+	LineItems []LineItem `parquet:"line_items"`
+}
+
+type PointerSlices struct {
+	ID     int32       `parquet:"id"`
+	Items  []*LineItem `parquet:"items"`
+	Counts []*int32    `parquet:"counts"`
+}
+
+// EvolvedPerson is a newer version of Person.
+type EvolvedPerson struct {
+	Being
+	Happiness int32
+	Sadness   int64
+	Code      string
+	Funkiness float32
+	Lameness  *float32
+	Keen      *bool
+	Birthday  uint32
+	Nickname  *string
+}
+
+// Redated is Dated with a timestamp instead of a date of birth.
+type Redated struct {
+	ID        int32      `parquet:"name=id"`
+	DOB       time.Time  `parquet:"name=dob"`
+	Graduated *time.Time `parquet:"graduated,logical=date"`
+}
+
+type Timer struct {
+	ID      int32          `parquet:"name=id"`
+	Elapsed time.Duration  `parquet:"name=elapsed"`
+	Timeout *time.Duration `parquet:"name=timeout"`
+}
+
+type Account struct {
+	UserID     int64
+	CreatedAt  time.Time
+	HTTPStatus *int32
+	Avatar     []byte     `parquet:"optional"`
+	DeletedAt  *time.Time `parquet:"name=removed"`
+	Addr2      string     `parquet:"address"`
+}
+
+type Stamped struct {
+	ID       int32      `parquet:"name=id"`
+	Logged   time.Time  `parquet:"name=logged,logical=timestamp"`
+	Started  time.Time  `parquet:"name=started,logical=timestamp,unit=micros"`
+	Finished *time.Time `parquet:"name=finished,logical=timestamp,unit=nanos"`
+}
+
+type BadEvent struct {
+	ID      int32     `parquet:"name=id"`
+	Started time.Time `parquet:"name=started,logical=timestamp,unit=seconds"`
+	Created time.Time `parquet:"name=created,unit=micros"`
+}
+
+type Upstream struct {
+	ID         int32     `parquet:"name=id"`
+	Deprecated *struct{} `parquet:"name=deprecated,logical=null"`
+}
+
+type BadUpstream struct {
+	ID         int32     `parquet:"name=id"`
+	Deprecated struct{}  `parquet:"name=deprecated,logical=null"`
+	Removed    *struct{} `parquet:"name=removed"`
+}
+
+// Shipment's carrier is optional but the contact and the
+// fields of both are required.
+type Shipment struct {
+	ID      int32    `parquet:"name=id"`
+	Carrier *Carrier `parquet:"name=carrier"`
+}
+
+type Carrier struct {
+	Contact Contact `parquet:"name=contact"`
+	Code    string  `parquet:"name=code"`
+}
+
+type Contact struct {
+	Email string  `parquet:"name=email"`
+	Phone *string `parquet:"name=phone"`
+}
+
+type Webhook struct {
+	ID      int32   `parquet:"name=id"`
+	Payload string  `parquet:"name=payload,logical=json"`
+	Extra   *string `parquet:"name=extra,logical=json"`
+}
+
+type Attachment struct {
+	ID   int32  `parquet:"name=id"`
+	Doc  []byte `parquet:"name=doc,logical=bson"`
+	Meta []byte `parquet:"name=meta,optional,logical=bson"`
+}
+
+type BadAttachment struct {
+	ID  int32  `parquet:"name=id"`
+	Doc string `parquet:"name=doc,logical=bson"`
+	Raw *int64 `parquet:"name=raw,logical=bson"`
+}
+
+type Shop struct {
+	ID     int32          `parquet:"name=id"`
+	Opens  time.Duration  `parquet:"name=opens,logical=time"`
+	Closes *time.Duration `parquet:"name=closes,logical=time,unit=micros"`
+	Alarm  *time.Duration `parquet:"name=alarm,logical=time,unit=nanos"`
+}
+
+type BadShop struct {
+	ID     int32         `parquet:"name=id"`
+	Opens  time.Time     `parquet:"name=opens,logical=time"`
+	Closes time.Duration `parquet:"name=closes,logical=time,unit=seconds"`
+}
+
+type Sensor struct {
+	ID   int32 `parquet:"name=id"`
+	Meta struct {
+		Model string `parquet:"name=model"`
+		Rev   *int32 `parquet:"name=rev"`
+	} `parquet:"name=meta"`
+	Calibration *struct {
+		Points []struct {
+			X float64 `parquet:"name=x"`
+		} `parquet:"name=points"`
+	} `parquet:"name=calibration"`
+}
+
+type Embedding struct {
+	ID     int32   `parquet:"name=id"`
+	Weight uint16  `parquet:"name=weight,logical=float16"`
+	Bias   *uint16 `parquet:"name=bias,logical=float16"`
+}
+
+type BadEmbedding struct {
+	ID     int32   `parquet:"name=id"`
+	Weight float32 `parquet:"name=weight,logical=float16"`
+}
+
+type Ledger struct {
+	ID      int32    `parquet:"name=id"`
+	Balance *big.Int `parquet:"name=balance,logical=decimal,precision=38,scale=2"`
+}
+
+type BadLedger struct {
+	ID      int32   `parquet:"name=id"`
+	Balance big.Int `parquet:"name=balance,logical=decimal,precision=38,scale=2"`
+}
+
+type Metric struct {
+	ID      int64     `parquet:"name=id,encoding=delta"`
+	Host    string    `parquet:"name=host,encoding=dictionary"`
+	Path    *string   `parquet:"name=path,encoding=delta_byte_array"`
+	Body    []byte    `parquet:"name=body,encoding=delta_length_byte_array"`
+	Seen    time.Time `parquet:"name=seen,encoding=delta"`
+	Value   float64   `parquet:"name=value,encoding=plain"`
+	Samples []int32   `parquet:"name=samples,encoding=delta"`
+}
+
+type BadMetric struct {
+	ID    int64   `parquet:"name=id,encoding=dictionary"`
+	Host  string  `parquet:"name=host,encoding=delta"`
+	Value float64 `parquet:"name=value,encoding=rle"`
+	Count int32   `parquet:"name=count"`
+}
+
+type Parcel struct {
+	ID       int32  `parquet:"name=id"`
+	Boundary []byte `parquet:"name=boundary,logical=geometry,crs=OGC:CRS84"`
+	Access   []byte `parquet:"name=access,logical=geography,optional"`
+}
+
+type BadParcel struct {
+	ID     int32  `parquet:"name=id"`
+	Center string `parquet:"name=center,logical=geometry"`
+	Owner  []byte `parquet:"name=owner,crs=OGC:CRS84"`
+}
+
+type Celsius float32
+
+// Kelvin is declared as another named type.
+type Kelvin Celsius
+
+type Mood string
+
+type Reading struct {
+	Temp   Celsius
+	Abs    *Kelvin `parquet:"name=abs"`
+	Moods  []Mood  `parquet:"name=moods"`
+	Status Mood    `parquet:"name=status,logical=enum"`
+	Visit  *Visit  `parquet:"name=visit"`
+}
+
+type Visit struct {
+	Mood Mood     `parquet:"name=mood"`
+	Temp *Celsius `parquet:"name=temp"`
+}
+
+// Money is written as the int64 that MarshalParquet returns.
+type Money struct {
+	Cents int64
+}
+
+func (m Money) MarshalParquet() int64 { return m.Cents }
+
+func (m *Money) UnmarshalParquet(v int64) { m.Cents = v }
+
+// Email has methods, so they are used instead of a conversion.
+type Email string
+
+func (e Email) MarshalParquet() string { return string(e) }
+
+func (e *Email) UnmarshalParquet(v string) { *e = Email(v) }
+
+type Invoice struct {
+	ID      int32   `parquet:"name=id"`
+	Price   Money   `parquet:"name=price"`
+	Fee     *Money  `parquet:"name=fee"`
+	Refunds []Money `parquet:"name=refunds"`
+	Contact Email   `parquet:"name=contact"`
+}
+
+type WriteOnly struct{ n int64 }
+
+func (w WriteOnly) MarshalParquet() int64 { return w.n }
+
+type Mismatch struct{ n int64 }
+
+func (m Mismatch) MarshalParquet() int64 { return m.n }
+
+func (m *Mismatch) UnmarshalParquet(v int32) { m.n = int64(v) }
+
+type BadOrder struct {
+	ID    int32     `parquet:"name=id"`
+	Total WriteOnly `parquet:"name=total"`
+	Tax   Mismatch  `parquet:"name=tax"`
+}
+
+type Envelope struct {
+	ID          int32      `parquet:"name=id"`
+	Attachments [][]byte   `parquet:"name=attachments"`
+	Hashes      [][16]byte `parquet:"name=hashes"`
+	Sections    []Section  `parquet:"name=sections"`
+}
+
+type Section struct {
+	Blobs [][]byte `parquet:"name=blobs"`
+}
+
+// Date is stored like the time.Time it's declared as.
+type Date time.Time
+
+// Zone can't be stored since a time.Location isn't
+// a struct with parquet fields or a scalar.
+type Zone time.Location
+
+type Birthday struct {
+	ID   int32 `parquet:"name=id"`
+	DOB  Date  `parquet:"name=dob,logical=date"`
+	Left *Date `parquet:"name=left,logical=date"`
+	Zone Zone  `parquet:"name=zone"`
+}
+
+// Coupon's methods are in coupon_test.go.
+type Coupon struct{ code string }
+
+type Receipt struct {
+	ID     int32   `parquet:"name=id"`
+	Coupon *Coupon `parquet:"name=coupon"`
+}
@@ -26,6 +26,15 @@ func TestStruct(t *testing.T) {
 			},
 			expected: "type Root struct {\n	Id int32 `parquet:\"id\"`\n}",
 		},
+		{
+			name: "float and double",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(2)},
+				{Name: "discount", Type: pt(sch.Type_FLOAT), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED)},
+				{Name: "ratio", Type: pt(sch.Type_DOUBLE), RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL)},
+			},
+			expected: "type Root struct {\n	Discount float32  `parquet:\"discount\"`\n	Ratio    *float64 `parquet:\"ratio\"`\n}",
+		},
 		{
 			name: "single nested field",
 			schema: []*sch.SchemaElement{
@@ -0,0 +1,633 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// ColumnWriter adds the values (and nulls) of a column to the row group
+// that a FileWriter is writing.  Each value is at the column's max
+// definition level and rep is its repetition level (0 starts a new row).
+// A null (or an empty list) is added with WriteNull at a definition level
+// below the max.  The Write method that is used has to match the
+// column's physical type (WriteString and WriteBytes are both for a
+// BYTE_ARRAY column and WriteBytes is also for FIXED_LEN_BYTE_ARRAY).
+type ColumnWriter interface {
+	WriteBool(v bool, rep uint8) error
+	WriteInt32(v int32, rep uint8) error
+	WriteInt64(v int64, rep uint8) error
+	WriteFloat32(v float32, rep uint8) error
+	WriteFloat64(v float64, rep uint8) error
+	WriteString(v string, rep uint8) error
+	WriteBytes(v []byte, rep uint8) error
+	WriteNull(def, rep uint8) error
+}
+
+// ColumnReader reads a column chunk of a file (see FileReader.Column).
+// The Read method that is used has to match the column's physical type,
+// like it does for a ColumnWriter, and it returns the column's non-null
+// values.  Levels returns the definition and repetition level of each
+// value and null, which is how the values are put back in to their rows
+// (a required column doesn't have levels).
+type ColumnReader interface {
+	ReadBool() ([]bool, error)
+	ReadInt32() ([]int32, error)
+	ReadInt64() ([]int64, error)
+	ReadFloat32() ([]float32, error)
+	ReadFloat64() ([]float64, error)
+	ReadString() ([]string, error)
+	ReadBytes() ([][]byte, error)
+	Levels() ([]uint8, []uint8)
+}
+
+// FileWriter writes a parquet file one column at a time, which is for
+// when the schema isn't known until runtime so the code for it can't be
+// generated.  A row group is written by adding the values of each column
+// (see Column) and then calling WriteRowGroup.  The generated code
+// doesn't use FileWriter (it writes its fields itself), but the files
+// they write are the same.
+type FileWriter struct {
+	w       io.Writer
+	meta    *Metadata
+	fields  []Field
+	columns []*columnWriter
+	lookup  map[string]*columnWriter
+}
+
+// NewFileWriter writes the start of a parquet file to w and returns
+// a FileWriter for adding the columns of fields to it.
+func NewFileWriter(w io.Writer, fields ...Field) (*FileWriter, error) {
+	fw := &FileWriter{
+		w:      NewCountingWriter(w, 0),
+		fields: fields,
+		lookup: map[string]*columnWriter{},
+	}
+
+	for _, f := range fields {
+		c := newColumnWriter(f)
+		fw.columns = append(fw.columns, c)
+		fw.lookup[c.name] = c
+	}
+
+	if _, err := fw.w.Write([]byte("PAR1")); err != nil {
+		return nil, err
+	}
+
+	fw.meta = New(fields...)
+	return fw, nil
+}
+
+// Column returns the ColumnWriter of a column, which is named by the
+// names in its path joined with a '.' (like hobby.name).
+func (f *FileWriter) Column(name string) (ColumnWriter, error) {
+	c, ok := f.lookup[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown column %s", name)
+	}
+	return c, nil
+}
+
+// WriteRowGroup writes the values that have been added to the columns
+// as a row group.  Each column has to have the same number of rows.
+func (f *FileWriter) WriteRowGroup() error {
+	rows := -1
+	var first string
+	for _, c := range f.columns {
+		n := c.rows()
+		if rows == -1 {
+			rows, first = n, c.name
+			continue
+		}
+		if n != rows {
+			return fmt.Errorf("column %s has %d rows and column %s has %d", c.name, n, first, rows)
+		}
+	}
+
+	// an empty row group isn't written
+	if rows <= 0 {
+		return nil
+	}
+
+	for i := 0; i < rows; i++ {
+		f.meta.NextDoc()
+	}
+
+	for _, c := range f.columns {
+		if err := c.write(f.w, f.meta); err != nil {
+			return err
+		}
+	}
+
+	f.meta.StartRowGroup(f.fields...)
+	return nil
+}
+
+// Close writes the row group of any values that haven't been
+// written yet and then the footer of the file.
+func (f *FileWriter) Close() error {
+	if err := f.WriteRowGroup(); err != nil {
+		return err
+	}
+
+	if err := f.meta.Footer(f.w); err != nil {
+		return err
+	}
+
+	_, err := f.w.Write([]byte("PAR1"))
+	return err
+}
+
+type columnWriter struct {
+	name     string
+	se       sch.SchemaElement
+	required *RequiredField
+	optional *OptionalField
+	vals     []byte
+	bools    []bool
+	count    int
+	stats    *columnStats
+}
+
+func newColumnWriter(f Field) *columnWriter {
+	c := &columnWriter{name: strings.Join(f.Path, ".")}
+	f.Type(&c.se)
+
+	rts := getRepetitionTypes(f.Types)
+	if rts.MaxDef() == 0 && rts.MaxRep() == 0 {
+		rf := NewRequiredField(f.Path)
+		c.required = &rf
+	} else {
+		of := NewOptionalField(f.Path, f.Types)
+		c.optional = &of
+	}
+	c.stats = &columnStats{se: c.se, required: c.required != nil}
+	return c
+}
+
+func (c *columnWriter) WriteBool(v bool, rep uint8) error {
+	var b byte
+	if v {
+		b = 1
+	}
+	if err := c.add(sch.Type_BOOLEAN, "bool", []byte{b}, rep); err != nil {
+		return err
+	}
+	c.bools = append(c.bools, v)
+	return nil
+}
+
+func (c *columnWriter) WriteInt32(v int32, rep uint8) error {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(v))
+	return c.add(sch.Type_INT32, "int32", b, rep)
+}
+
+func (c *columnWriter) WriteInt64(v int64, rep uint8) error {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(v))
+	return c.add(sch.Type_INT64, "int64", b, rep)
+}
+
+func (c *columnWriter) WriteFloat32(v float32, rep uint8) error {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+	return c.add(sch.Type_FLOAT, "float32", b, rep)
+}
+
+func (c *columnWriter) WriteFloat64(v float64, rep uint8) error {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, math.Float64bits(v))
+	return c.add(sch.Type_DOUBLE, "float64", b, rep)
+}
+
+func (c *columnWriter) WriteString(v string, rep uint8) error {
+	return c.add(sch.Type_BYTE_ARRAY, "string", []byte(v), rep)
+}
+
+func (c *columnWriter) WriteBytes(v []byte, rep uint8) error {
+	if c.se.GetType() == sch.Type_FIXED_LEN_BYTE_ARRAY {
+		if len(v) != int(c.se.GetTypeLength()) {
+			return fmt.Errorf("column %s has values of %d bytes, it can't have a value of %d bytes", c.name, c.se.GetTypeLength(), len(v))
+		}
+		return c.add(sch.Type_FIXED_LEN_BYTE_ARRAY, "[]byte", v, rep)
+	}
+	return c.add(sch.Type_BYTE_ARRAY, "[]byte", v, rep)
+}
+
+func (c *columnWriter) WriteNull(def, rep uint8) error {
+	if c.required != nil {
+		return fmt.Errorf("column %s is required, it can't have a null", c.name)
+	}
+
+	if def >= c.optional.MaxLevels.Def {
+		return fmt.Errorf("column %s has a max definition level of %d, a null can't be at %d", c.name, c.optional.MaxLevels.Def, def)
+	}
+
+	if err := c.checkRep(rep); err != nil {
+		return err
+	}
+
+	c.optional.Defs = append(c.optional.Defs, def)
+	c.optional.Reps = append(c.optional.Reps, rep)
+	c.stats.nils++
+	return nil
+}
+
+// add adds a value (which isn't plain encoded yet) at the
+// max definition level.
+func (c *columnWriter) add(typ sch.Type, name string, v []byte, rep uint8) error {
+	if c.se.GetType() != typ {
+		return fmt.Errorf("column %s is %s, it can't have a %s value", c.name, c.se.GetType(), name)
+	}
+
+	if err := c.checkRep(rep); err != nil {
+		return err
+	}
+
+	if c.optional != nil {
+		c.optional.Defs = append(c.optional.Defs, c.optional.MaxLevels.Def)
+		c.optional.Reps = append(c.optional.Reps, rep)
+	}
+
+	if typ == sch.Type_BYTE_ARRAY {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(len(v)))
+		c.vals = append(c.vals, b...)
+	}
+
+	if typ != sch.Type_BOOLEAN {
+		c.vals = append(c.vals, v...)
+	}
+
+	c.count++
+	c.stats.add(v)
+	return nil
+}
+
+func (c *columnWriter) checkRep(rep uint8) error {
+	var max uint8
+	if c.optional != nil {
+		max = c.optional.MaxLevels.Rep
+	}
+
+	if rep > max {
+		return fmt.Errorf("column %s has a max repetition level of %d, a value can't be at %d", c.name, max, rep)
+	}
+	return nil
+}
+
+// rows returns the number of rows that have been
+// added since the last row group was written.
+func (c *columnWriter) rows() int {
+	if c.required != nil {
+		return c.count
+	}
+
+	if c.optional.MaxLevels.Rep == 0 {
+		return len(c.optional.Defs)
+	}
+
+	var n int
+	for _, rep := range c.optional.Reps {
+		if rep == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *columnWriter) write(w io.Writer, meta *Metadata) error {
+	var err error
+	switch {
+	case c.required != nil && c.se.GetType() == sch.Type_BOOLEAN:
+		err = c.required.DoWriteBools(w, meta, c.bools, c.count, c.stats)
+	case c.required != nil:
+		err = c.required.DoWrite(w, meta, c.vals, c.count, c.stats)
+	case c.se.GetType() == sch.Type_BOOLEAN:
+		err = c.optional.DoWriteBools(w, meta, c.bools, len(c.optional.Defs), c.stats)
+	default:
+		err = c.optional.DoWrite(w, meta, c.vals, len(c.optional.Defs), c.stats)
+	}
+
+	c.vals, c.bools, c.count = nil, nil, 0
+	c.stats = &columnStats{se: c.se, required: c.required != nil}
+	if c.optional != nil {
+		c.optional.Defs, c.optional.Reps = nil, nil
+	}
+	return err
+}
+
+// columnStats are the Stats of a column that is
+// written with a ColumnWriter.
+type columnStats struct {
+	se       sch.SchemaElement
+	required bool
+	nils     int64
+	min      []byte
+	max      []byte
+}
+
+func (s *columnStats) add(v []byte) {
+	if isNaN(s.se, v) {
+		return
+	}
+	if s.min == nil || less(s.se, v, s.min) {
+		s.min = v
+	}
+	if s.max == nil || less(s.se, s.max, v) {
+		s.max = v
+	}
+}
+
+func (s *columnStats) NullCount() *int64 {
+	if s.required {
+		return new(int64)
+	}
+	return &s.nils
+}
+
+func (s *columnStats) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *columnStats) Min() []byte {
+	return s.min
+}
+
+func (s *columnStats) Max() []byte {
+	return s.max
+}
+
+// FileReader reads the column chunks of a parquet file without
+// generated code.  The columns are found from the schema in the
+// file's footer.
+type FileReader struct {
+	r      io.ReadSeeker
+	fields []Field
+	types  map[string]*sch.SchemaElement
+	pages  map[string][]Page
+	groups int
+}
+
+// NewFileReader reads the footer of a parquet file and
+// returns a FileReader for the columns of the file.
+func NewFileReader(r io.ReadSeeker) (*FileReader, error) {
+	footer, err := ReadMetaData(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(footer.Schema) == 0 {
+		return nil, fmt.Errorf("the file doesn't have a schema")
+	}
+
+	fr := &FileReader{r: r, types: map[string]*sch.SchemaElement{}}
+	fr.leafFields(footer.Schema[0], footer.Schema[1:], nil, nil)
+
+	meta := New(fr.fields...)
+	if err := meta.ReadFooter(r); err != nil {
+		return nil, err
+	}
+
+	fr.pages, err = meta.Pages()
+	fr.groups = len(footer.RowGroups)
+	return fr, err
+}
+
+// leafFields adds the columns under parent to the reader's
+// fields and returns the number of schema elements it used.
+func (f *FileReader) leafFields(parent *sch.SchemaElement, schema []*sch.SchemaElement, pth []string, types []int) int {
+	var i int
+	for j := 0; j < int(parent.GetNumChildren()) && i < len(schema); j++ {
+		se := schema[i]
+		i++
+		p := append(append([]string{}, pth...), se.Name)
+		t := append(append([]int{}, types...), int(se.GetRepetitionType()))
+		if se.GetNumChildren() > 0 {
+			i += f.leafFields(se, schema[i:], p, t)
+			continue
+		}
+
+		f.types[strings.Join(p, ".")] = se
+		f.fields = append(f.fields, Field{
+			Name:  se.Name,
+			Path:  p,
+			Types: t,
+			Type: func(out *sch.SchemaElement) {
+				out.Type = se.Type
+				out.TypeLength = se.TypeLength
+				out.ConvertedType = se.ConvertedType
+				out.LogicalType = se.LogicalType
+				out.Scale = se.Scale
+				out.Precision = se.Precision
+			},
+			RepetitionType: fieldFuncs[t[len(t)-1]],
+		})
+	}
+	return i
+}
+
+// Columns returns the name of each column (the names
+// in its path joined with a '.') in the file.
+func (f *FileReader) Columns() []string {
+	out := make([]string, len(f.fields))
+	for i, fld := range f.fields {
+		out[i] = strings.Join(fld.Path, ".")
+	}
+	return out
+}
+
+// RowGroups returns the number of row groups in the file.
+func (f *FileReader) RowGroups() int {
+	return f.groups
+}
+
+// Column reads the chunk of a column in a row group.
+func (f *FileReader) Column(rowGroup int, name string) (ColumnReader, error) {
+	var fld *Field
+	for i := range f.fields {
+		if strings.Join(f.fields[i].Path, ".") == name {
+			fld = &f.fields[i]
+		}
+	}
+
+	if fld == nil {
+		return nil, fmt.Errorf("unknown column %s", name)
+	}
+
+	pages := f.pages[name]
+	if rowGroup < 0 || rowGroup >= len(pages) {
+		return nil, fmt.Errorf("column %s doesn't have row group %d", name, rowGroup)
+	}
+
+	pg := pages[rowGroup]
+	if _, err := f.r.Seek(pg.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	c := &columnReader{name: name, se: f.types[name]}
+	var rd io.Reader
+	var sizes []int
+	var err error
+	rts := getRepetitionTypes(fld.Types)
+	if rts.MaxDef() == 0 && rts.MaxRep() == 0 {
+		rf := NewRequiredField(fld.Path)
+		rd, sizes, err = rf.DoRead(f.r, pg)
+	} else {
+		of := NewOptionalField(fld.Path, fld.Types)
+		rd, sizes, err = of.DoRead(f.r, pg)
+		c.defs, c.reps = of.Defs, of.Reps
+		if rts.MaxRep() == 0 {
+			c.reps = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read column %s, err: %s", name, err)
+	}
+
+	for _, s := range sizes {
+		c.n += s
+	}
+
+	if c.se.GetType() == sch.Type_BOOLEAN {
+		c.bools, err = GetBools(rd, c.n, sizes)
+	} else {
+		c.data, err = ioutil.ReadAll(rd)
+	}
+	return c, err
+}
+
+type columnReader struct {
+	name  string
+	se    *sch.SchemaElement
+	n     int
+	data  []byte
+	bools []bool
+	defs  []uint8
+	reps  []uint8
+}
+
+func (c *columnReader) Levels() ([]uint8, []uint8) {
+	return c.defs, c.reps
+}
+
+func (c *columnReader) ReadBool() ([]bool, error) {
+	if err := c.check(sch.Type_BOOLEAN, "bool"); err != nil {
+		return nil, err
+	}
+	return c.bools, nil
+}
+
+func (c *columnReader) ReadInt32() ([]int32, error) {
+	vals, err := c.values(sch.Type_INT32, "int32", 4)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]int32, len(vals))
+	for i, v := range vals {
+		out[i] = int32(binary.LittleEndian.Uint32(v))
+	}
+	return out, nil
+}
+
+func (c *columnReader) ReadInt64() ([]int64, error) {
+	vals, err := c.values(sch.Type_INT64, "int64", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]int64, len(vals))
+	for i, v := range vals {
+		out[i] = int64(binary.LittleEndian.Uint64(v))
+	}
+	return out, nil
+}
+
+func (c *columnReader) ReadFloat32() ([]float32, error) {
+	vals, err := c.values(sch.Type_FLOAT, "float32", 4)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]float32, len(vals))
+	for i, v := range vals {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(v))
+	}
+	return out, nil
+}
+
+func (c *columnReader) ReadFloat64() ([]float64, error) {
+	vals, err := c.values(sch.Type_DOUBLE, "float64", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]float64, len(vals))
+	for i, v := range vals {
+		out[i] = math.Float64frombits(binary.LittleEndian.Uint64(v))
+	}
+	return out, nil
+}
+
+func (c *columnReader) ReadString() ([]string, error) {
+	vals, err := c.values(sch.Type_BYTE_ARRAY, "string", -1)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = string(v)
+	}
+	return out, nil
+}
+
+func (c *columnReader) ReadBytes() ([][]byte, error) {
+	if c.se.GetType() == sch.Type_FIXED_LEN_BYTE_ARRAY {
+		return c.values(sch.Type_FIXED_LEN_BYTE_ARRAY, "[]byte", int(c.se.GetTypeLength()))
+	}
+	return c.values(sch.Type_BYTE_ARRAY, "[]byte", -1)
+}
+
+func (c *columnReader) check(typ sch.Type, name string) error {
+	if c.se.GetType() != typ {
+		return fmt.Errorf("column %s is %s, it can't be read as %s", c.name, c.se.GetType(), name)
+	}
+	return nil
+}
+
+// values splits the plain encoded values of the column chunk.  The
+// values of a BYTE_ARRAY column (a size of -1) each start with
+// their length.
+func (c *columnReader) values(typ sch.Type, name string, size int) ([][]byte, error) {
+	if err := c.check(typ, name); err != nil {
+		return nil, err
+	}
+
+	data := c.data
+	out := make([][]byte, c.n)
+	for i := range out {
+		n := size
+		if n == -1 {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("column %s: expected %d values, got %d", c.name, c.n, i)
+			}
+			n = int(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		}
+
+		if len(data) < n {
+			return nil, fmt.Errorf("column %s: expected %d values, got %d", c.name, c.n, i)
+		}
+		out[i] = data[:n:n]
+		data = data[n:]
+	}
+	return out, nil
+}
@@ -0,0 +1,108 @@
+package parquet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CSVRecord returns the CSV fields of the columns of a row that ToMap
+// returned m for.  Nulls are empty fields, time.Time is formatted as RFC
+// 3339, []byte and [N]byte are base64 encoded, and the values of repeated
+// columns are JSON arrays.  The key and value columns of a map (like
+// "attributes.key_value.key") are JSON arrays of the map's keys and values
+// (in the order of the keys).
+func CSVRecord(m map[string]interface{}, columns []string) ([]string, error) {
+	out := make([]string, len(columns))
+	for i, col := range columns {
+		v, ok := m[col]
+		if !ok {
+			v, ok = mapEntries(m, col)
+		}
+
+		if !ok || v == nil {
+			continue
+		}
+
+		s, err := csvField(v)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %s", col, err)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// mapEntries returns the keys (or values, for the value column) of the
+// map that col is a column of.
+func mapEntries(m map[string]interface{}, col string) (interface{}, bool) {
+	v, ok := m[mapColumn(col)]
+	if !ok {
+		return nil, false
+	}
+
+	mv := reflect.ValueOf(v)
+	if mv.Kind() != reflect.Map || mv.Len() == 0 {
+		return nil, false
+	}
+
+	keys := mv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return a.Uint() < b.Uint()
+		default:
+			return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+		}
+	})
+
+	value := mapColumn(col)+".key_value.value" == col
+	out := make([]interface{}, len(keys))
+	for i, k := range keys {
+		if value {
+			out[i] = mv.MapIndex(k).Interface()
+		} else {
+			out[i] = k.Interface()
+		}
+	}
+	return out, true
+}
+
+func csvField(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x), nil
+	case time.Time:
+		return x.Format(time.RFC3339Nano), nil
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), nil
+	case []interface{}:
+		buf, err := json.Marshal(x)
+		return string(buf), err
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(v), nil
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return base64.StdEncoding.EncodeToString(b), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported value %v (%T)", v, v)
+}
@@ -0,0 +1,60 @@
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+// DecimalBytes returns the unscaled value v of a decimal as the n byte
+// big-endian two's complement that a FIXED_LEN_BYTE_ARRAY decimal column
+// stores.  It returns an error if v doesn't fit in n bytes.
+func DecimalBytes(v *big.Int, n int) ([]byte, error) {
+	if v.Sign() >= 0 {
+		if v.BitLen() > 8*n-1 {
+			return nil, fmt.Errorf("decimal %s doesn't fit in %d bytes", v, n)
+		}
+		out := make([]byte, n)
+		b := v.Bytes()
+		copy(out[n-len(b):], b)
+		return out, nil
+	}
+
+	// the smallest number that fits is -2^(8n-1)
+	smallest := new(big.Int).Lsh(big.NewInt(1), uint(8*n-1))
+	if v.Cmp(smallest.Neg(smallest)) < 0 {
+		return nil, fmt.Errorf("decimal %s doesn't fit in %d bytes", v, n)
+	}
+
+	// the two's complement of a negative number is 2^(8n) + v
+	x := new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+	x.Add(x, v)
+	return x.Bytes(), nil
+}
+
+// DecimalInt returns the unscaled value of a decimal that is stored as a
+// big-endian two's complement (see DecimalBytes).
+func DecimalInt(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return v
+}
+
+// DecimalLess compares two decimals that are stored as
+// big-endian two's complements (see DecimalBytes).
+func DecimalLess(a, b []byte) bool {
+	if len(a) != len(b) {
+		return DecimalInt(a).Cmp(DecimalInt(b)) < 0
+	}
+
+	if len(a) == 0 {
+		return false
+	}
+
+	if na, nb := a[0]&0x80 != 0, b[0]&0x80 != 0; na != nb {
+		return na
+	}
+	return bytes.Compare(a, b) < 0
+}
@@ -0,0 +1,71 @@
+package parquet_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalBytes(t *testing.T) {
+	testCases := []struct {
+		val      string
+		n        int
+		expected []byte
+		err      string
+	}{
+		{val: "0", n: 2, expected: []byte{0x00, 0x00}},
+		{val: "1", n: 2, expected: []byte{0x00, 0x01}},
+		{val: "-1", n: 2, expected: []byte{0xff, 0xff}},
+		{val: "32767", n: 2, expected: []byte{0x7f, 0xff}},
+		{val: "-32768", n: 2, expected: []byte{0x80, 0x00}},
+		{val: "-256", n: 3, expected: []byte{0xff, 0xff, 0x00}},
+		{val: "32768", n: 2, err: "decimal 32768 doesn't fit in 2 bytes"},
+		{val: "-32769", n: 2, err: "decimal -32769 doesn't fit in 2 bytes"},
+		{val: "-128", n: 1, expected: []byte{0x80}},
+		{val: "-129", n: 1, err: "decimal -129 doesn't fit in 1 bytes"},
+		{val: "-384", n: 1, err: "decimal -384 doesn't fit in 1 bytes"},
+		{val: "-99999999999999999999999999999999999999", n: 16, expected: decimalBytes16("-99999999999999999999999999999999999999")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.val, func(t *testing.T) {
+			v, _ := new(big.Int).SetString(tc.val, 10)
+			b, err := parquet.DecimalBytes(v, tc.n)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tc.expected, b)
+			assert.Equal(t, tc.val, parquet.DecimalInt(b).String())
+		})
+	}
+}
+
+func TestDecimalLess(t *testing.T) {
+	vals := []string{"-170141183460469231731687303715884105728", "-12345678901234567890", "-256", "-1", "0", "1", "255", "12345678901234567890"}
+	for i, a := range vals {
+		for j, b := range vals {
+			assert.Equal(t, i < j, parquet.DecimalLess(decimalBytes16(a), decimalBytes16(b)), "%s < %s", a, b)
+		}
+	}
+
+	// values of different lengths are compared as numbers
+	assert.True(t, parquet.DecimalLess([]byte{0xff}, []byte{0x00, 0x01}))
+	assert.False(t, parquet.DecimalLess([]byte{0x00, 0x01}, []byte{0x80}))
+}
+
+func decimalBytes16(s string) []byte {
+	v, _ := new(big.Int).SetString(s, 10)
+	b, err := parquet.DecimalBytes(v, 16)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,385 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/parsyl/parquet/internal/bitpack"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+const (
+	// deltaBlockSize is the number of values in each block of a
+	// DELTA_BINARY_PACKED page and deltaMiniBlocks is the number
+	// of miniblocks (each with its own bit width) in a block.
+	deltaBlockSize  = 128
+	deltaMiniBlocks = 4
+
+	// maxDeltaBlockSize is far larger than the block size of
+	// any writer, so a page with bigger blocks is corrupt.
+	maxDeltaBlockSize = 1 << 16
+)
+
+// deltaWidth returns the size (in bytes) of the plain encoded values of
+// a column that can be DELTA_BINARY_PACKED (0 if it can't be).
+func deltaWidth(typ sch.Type) int {
+	switch typ {
+	case sch.Type_INT32:
+		return 4
+	case sch.Type_INT64:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func (m *Metadata) columnType(pth []string) sch.Type {
+	se := m.schema.lookup[strings.Join(pth, ".")]
+	return se.GetType()
+}
+
+// encodeValues encodes the plain encoded vals of the column at pth with
+// the encoding that was set for the column if the column's type supports
+// it.  Otherwise vals are left plain encoded (or dictionary encoded).
+func encodeValues(meta *Metadata, pth []string, vals []byte, dict *Dictionary, delta bool, byteArray sch.Encoding) ([]byte, sch.Encoding) {
+	if dict != nil {
+		return vals, sch.Encoding_RLE_DICTIONARY
+	}
+
+	typ := meta.columnType(pth)
+	if width := deltaWidth(typ); delta && width > 0 {
+		return encodeDelta(vals, width), sch.Encoding_DELTA_BINARY_PACKED
+	}
+
+	if typ != sch.Type_BYTE_ARRAY {
+		return vals, sch.Encoding_PLAIN
+	}
+
+	switch byteArray {
+	case sch.Encoding_DELTA_LENGTH_BYTE_ARRAY:
+		return encodeDeltaLength(vals), byteArray
+	case sch.Encoding_DELTA_BYTE_ARRAY:
+		return encodeDeltaByteArray(vals), byteArray
+	default:
+		return vals, sch.Encoding_PLAIN
+	}
+}
+
+// encodeDelta DELTA_BINARY_PACKS the plain encoded INT32 (width 4) or
+// INT64 (width 8) values.
+func encodeDelta(plain []byte, width int) []byte {
+	vals := make([]int64, len(plain)/width)
+	for i := range vals {
+		if width == 4 {
+			vals[i] = int64(int32(binary.LittleEndian.Uint32(plain[i*width:])))
+		} else {
+			vals[i] = int64(binary.LittleEndian.Uint64(plain[i*width:]))
+		}
+	}
+	return deltaBinaryPacked(nil, vals, width)
+}
+
+// deltaBinaryPacked appends the DELTA_BINARY_PACKED vals, which are
+// INT32 (width 4) or INT64 (width 8) values.
+func deltaBinaryPacked(out []byte, vals []int64, width int) []byte {
+	var first int64
+	if len(vals) > 0 {
+		first = vals[0]
+	}
+
+	out = uvarint(out, deltaBlockSize)
+	out = uvarint(out, deltaMiniBlocks)
+	out = uvarint(out, uint64(len(vals)))
+	out = varint(out, first)
+
+	deltas := make([]int64, 0, deltaBlockSize)
+	for i := 1; i < len(vals); i += deltaBlockSize {
+		deltas = deltas[:0]
+		for j := i; j < i+deltaBlockSize && j < len(vals); j++ {
+			d := vals[j] - vals[j-1]
+			if width == 4 {
+				// INT32 columns wrap around at 32 bits
+				d = int64(int32(d))
+			}
+			deltas = append(deltas, d)
+		}
+		out = deltaBlock(out, deltas)
+	}
+	return out
+}
+
+// deltaBlock appends a block of deltas: the smallest delta followed by
+// the bit width and bit packed (delta - smallest) of each miniblock.
+func deltaBlock(out []byte, deltas []int64) []byte {
+	min := deltas[0]
+	for _, d := range deltas {
+		if d < min {
+			min = d
+		}
+	}
+	out = varint(out, min)
+
+	n := deltaBlockSize / deltaMiniBlocks
+	mini := make([][]uint64, deltaMiniBlocks)
+	widths := make([]byte, deltaMiniBlocks)
+	for i := range mini {
+		if i*n >= len(deltas) {
+			break
+		}
+
+		// the last miniblock is padded with zeros
+		mini[i] = make([]uint64, n)
+		var max uint64
+		for j := range mini[i] {
+			if i*n+j < len(deltas) {
+				mini[i][j] = uint64(deltas[i*n+j] - min)
+			}
+			if mini[i][j] > max {
+				max = mini[i][j]
+			}
+		}
+		widths[i] = byte(bits.Len64(max))
+	}
+	out = append(out, widths...)
+
+	for i, vals := range mini {
+		if widths[i] == 0 {
+			continue
+		}
+		for j := 0; j < len(vals); j += 8 {
+			out = bitpack.Pack64(out, int(widths[i]), vals[j:j+8])
+		}
+	}
+	return out
+}
+
+// decodeDelta decodes n DELTA_BINARY_PACKED values and returns them
+// plain encoded as INT32 (width 4) or INT64 (width 8) values.
+func decodeDelta(data []byte, width, n int) ([]byte, error) {
+	vals, _, err := deltaInts(data, n)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(vals)*width)
+	for i, v := range vals {
+		if width == 4 {
+			binary.LittleEndian.PutUint32(out[i*width:], uint32(v))
+		} else {
+			binary.LittleEndian.PutUint64(out[i*width:], uint64(v))
+		}
+	}
+	return out, nil
+}
+
+// deltaInts decodes n DELTA_BINARY_PACKED values and returns them along
+// with the number of bytes they took up.  The values of INT32 columns
+// are only correct in their lower 32 bits.  The header has to say that
+// there are n values, which is checked before any of them are decoded.
+func deltaInts(data []byte, n int) ([]int64, int, error) {
+	var hdr [3]uint64
+	var pos int
+	for i := range hdr {
+		v, l := binary.Uvarint(data[pos:])
+		if l <= 0 {
+			return nil, 0, errDeltaTooShort
+		}
+		hdr[i] = v
+		pos += l
+	}
+
+	if hdr[0] == 0 || hdr[0] > maxDeltaBlockSize || hdr[1] == 0 || hdr[0]%hdr[1] != 0 || (hdr[0]/hdr[1])%8 != 0 {
+		return nil, 0, fmt.Errorf("invalid delta binary packed block size %d with %d miniblocks", hdr[0], hdr[1])
+	}
+
+	if hdr[2] != uint64(n) {
+		return nil, 0, fmt.Errorf("delta binary packed page has %d values, expected %d", hdr[2], n)
+	}
+	blockSize, miniBlocks, total := int(hdr[0]), int(hdr[1]), n
+
+	v, l := binary.Varint(data[pos:])
+	if l <= 0 {
+		return nil, 0, errDeltaTooShort
+	}
+	pos += l
+
+	if total == 0 {
+		return nil, pos, nil
+	}
+	out := []int64{v}
+
+	perMini := blockSize / miniBlocks
+	for len(out) < total {
+		min, l := binary.Varint(data[pos:])
+		if l <= 0 || len(data) < pos+l+miniBlocks {
+			return nil, 0, errDeltaTooShort
+		}
+		widths := data[pos+l : pos+l+miniBlocks]
+		pos += l + miniBlocks
+
+		for _, w := range widths {
+			if len(out) == total {
+				break
+			}
+
+			if int(w) > bitpack.MaxSize64 {
+				return nil, 0, fmt.Errorf("invalid delta binary packed bit width %d", w)
+			}
+
+			size := int(w) * perMini / 8
+			if len(data) < pos+size {
+				return nil, 0, errDeltaTooShort
+			}
+
+			for j := 0; j < perMini && len(out) < total; j += 8 {
+				deltas := make([]uint64, 8)
+				if w > 0 {
+					deltas = bitpack.Unpack64(int(w), data[pos+j*int(w)/8:])
+				}
+
+				for _, d := range deltas {
+					if len(out) == total {
+						break
+					}
+					v += min + int64(d)
+					out = append(out, v)
+				}
+			}
+			pos += size
+		}
+	}
+	return out, pos, nil
+}
+
+var errDeltaTooShort = fmt.Errorf("delta binary packed page is too short")
+
+// encodeDeltaLength DELTA_LENGTH_BYTE_ARRAY encodes the plain
+// encoded byte array values: the DELTA_BINARY_PACKED lengths
+// followed by all of the values' bytes.
+func encodeDeltaLength(plain []byte) []byte {
+	return deltaLengthByteArray(nil, byteArrays(plain))
+}
+
+func deltaLengthByteArray(out []byte, vals [][]byte) []byte {
+	lens := make([]int64, len(vals))
+	for i, v := range vals {
+		lens[i] = int64(len(v))
+	}
+
+	out = deltaBinaryPacked(out, lens, 4)
+	for _, v := range vals {
+		out = append(out, v...)
+	}
+	return out
+}
+
+// encodeDeltaByteArray DELTA_BYTE_ARRAY encodes the plain encoded byte
+// array values: the DELTA_BINARY_PACKED length of the prefix each value
+// shares with the value before it followed by the rest of each value
+// (DELTA_LENGTH_BYTE_ARRAY encoded).
+func encodeDeltaByteArray(plain []byte) []byte {
+	vals := byteArrays(plain)
+	prefixes := make([]int64, len(vals))
+	suffixes := make([][]byte, len(vals))
+	var prev []byte
+	for i, v := range vals {
+		var n int
+		for n < len(v) && n < len(prev) && v[n] == prev[n] {
+			n++
+		}
+		prefixes[i] = int64(n)
+		suffixes[i] = v[n:]
+		prev = v
+	}
+
+	out := deltaBinaryPacked(nil, prefixes, 4)
+	return deltaLengthByteArray(out, suffixes)
+}
+
+// decodeDeltaLength decodes n DELTA_LENGTH_BYTE_ARRAY values and
+// returns them plain encoded.
+func decodeDeltaLength(data []byte, n int) ([]byte, error) {
+	vals, err := deltaLengthValues(data, n)
+	if err != nil {
+		return nil, err
+	}
+	return plainByteArrays(vals), nil
+}
+
+func deltaLengthValues(data []byte, n int) ([][]byte, error) {
+	lens, pos, err := deltaInts(data, n)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, len(lens))
+	for i, l := range lens {
+		if l < 0 || int64(len(data)-pos) < l {
+			return nil, fmt.Errorf("delta length byte array page is too short")
+		}
+		out[i] = data[pos : pos+int(l)]
+		pos += int(l)
+	}
+	return out, nil
+}
+
+// decodeDeltaByteArray decodes n DELTA_BYTE_ARRAY values and
+// returns them plain encoded.
+func decodeDeltaByteArray(data []byte, n int) ([]byte, error) {
+	prefixes, pos, err := deltaInts(data, n)
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes, err := deltaLengthValues(data[pos:], n)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, n)
+	var prev []byte
+	for i, p := range prefixes {
+		if p < 0 || p > int64(len(prev)) {
+			return nil, fmt.Errorf("invalid delta byte array prefix length %d", p)
+		}
+		out[i] = append(prev[:p:p], suffixes[i]...)
+		prev = out[i]
+	}
+	return plainByteArrays(out), nil
+}
+
+// byteArrays splits plain encoded byte array values.
+func byteArrays(plain []byte) [][]byte {
+	var out [][]byte
+	for len(plain) >= 4 {
+		l := int(binary.LittleEndian.Uint32(plain))
+		out = append(out, plain[4:4+l])
+		plain = plain[4+l:]
+	}
+	return out
+}
+
+func plainByteArrays(vals [][]byte) []byte {
+	var buf bytes.Buffer
+	bs := make([]byte, 4)
+	for _, v := range vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(v)))
+		buf.Write(bs)
+		buf.Write(v)
+	}
+	return buf.Bytes()
+}
+
+func uvarint(b []byte, v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(b, buf[:binary.PutUvarint(buf, v)]...)
+}
+
+// varint appends the zigzag varint of v.
+func varint(b []byte, v int64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(b, buf[:binary.PutVarint(buf, v)]...)
+}
@@ -0,0 +1,173 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeltaBinaryPacked(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input func(i int) Person
+	}{
+		{
+			name: "sorted",
+			input: func(i int) Person {
+				return Person{
+					Being:     Being{ID: int32(i)},
+					Happiness: 1600000000000 + int64(i)*1000,
+					Created:   time.Unix(1600000000+int64(i/3), 0).UTC(),
+				}
+			},
+		},
+		{
+			name: "random",
+			input: func(i int) Person {
+				p := Person{
+					Being:     Being{ID: rand.Int31() - rand.Int31()},
+					Happiness: rand.Int63() - rand.Int63(),
+				}
+				if i%3 > 0 {
+					p.Scores = []int32{rand.Int31n(10), rand.Int31n(10)}[:i%3]
+				}
+				if i%4 > 0 {
+					p.Sadness = pint64(rand.Int63n(100))
+				}
+				return p
+			},
+		},
+		{
+			name: "overflow",
+			input: func(i int) Person {
+				if i%2 == 0 {
+					return Person{Being: Being{ID: math.MinInt32}, Happiness: math.MinInt64, Sadness: pint64(math.MaxInt64)}
+				}
+				return Person{Being: Being{ID: math.MaxInt32}, Happiness: math.MaxInt64, Sadness: pint64(math.MinInt64)}
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var peeps [][]Person
+			for i := 0; i < 2; i++ {
+				rg := make([]Person, 1000)
+				for j := range rg {
+					rg[j] = tc.input(j)
+				}
+				peeps = append(peeps, rg)
+			}
+
+			sizes := map[bool]int{}
+			for _, delta := range []bool{false, true} {
+				opts := []func(*ParquetWriter) error{MaxPageSize(300), Uncompressed}
+				if delta {
+					opts = append(opts, DeltaBinaryPacked)
+				}
+
+				var buf bytes.Buffer
+				w, err := NewParquetWriter(&buf, opts...)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				for _, rg := range peeps {
+					for _, p := range rg {
+						w.Add(p)
+					}
+					assert.NoError(t, w.Write())
+				}
+				assert.NoError(t, w.Close())
+				sizes[delta] = buf.Len()
+
+				rd := bytes.NewReader(buf.Bytes())
+				footer, err := parquet.ReadMetaData(rd)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				enc := sch.Encoding_PLAIN
+				if delta {
+					enc = sch.Encoding_DELTA_BINARY_PACKED
+				}
+
+				for _, col := range []string{"id", "happiness", "sadness", "created", "scores", "funkiness"} {
+					pages, err := getPageHeaders(rd, col, footer)
+					if !assert.NoError(t, err) {
+						return
+					}
+
+					for _, ph := range pages {
+						if col == "funkiness" {
+							assert.Equal(t, sch.Encoding_PLAIN, ph.DataPageHeader.Encoding, col)
+						} else {
+							assert.Equal(t, enc, ph.DataPageHeader.Encoding, col)
+						}
+					}
+				}
+
+				r, err := NewParquetReader(rd)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				var i int
+				for r.Next() {
+					var p Person
+					r.Scan(&p)
+					assert.Equal(t, *getExpected(peeps, i), p, i)
+					i++
+				}
+
+				assert.NoError(t, r.Error())
+				assert.Equal(t, getLen(peeps), i)
+			}
+
+			if tc.name == "sorted" {
+				assert.True(t, sizes[true] < sizes[false], "delta: %d, plain: %d", sizes[true], sizes[false])
+			}
+		})
+	}
+
+	// a page whose header says it has more values than the page
+	// has is an error (instead of decoding all of them first)
+	t.Run("wrong number of values", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := NewParquetWriter(&buf, DeltaBinaryPacked, Uncompressed)
+		if !assert.NoError(t, err) {
+			return
+		}
+		for i := 0; i < 10; i++ {
+			w.Add(Person{Being: Being{ID: int32(i)}})
+		}
+		assert.NoError(t, w.Write())
+		assert.NoError(t, w.Close())
+
+		data := buf.Bytes()
+		footer, err := parquet.ReadMetaData(bytes.NewReader(data))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// the block size (128), number of miniblocks (4)
+		// and number of values (10) of the id column
+		start := footer.RowGroups[0].Columns[0].MetaData.DataPageOffset
+		i := bytes.Index(data[start:], []byte{0x80, 0x01, 0x04, 10})
+		if !assert.True(t, i >= 0) {
+			return
+		}
+		data[int(start)+i+3] = 0x7f
+
+		// the first row group is read by NewParquetReader
+		_, err = NewParquetReader(bytes.NewReader(data))
+		assert.EqualError(t, err, "unable to read field id, err: delta binary packed page has 127 values, expected 10")
+	})
+}
@@ -0,0 +1,204 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/parsyl/parquet/internal/rle"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// DefaultMaxDictionarySize is the largest a column chunk's dictionary
+// (in bytes of plain encoded values) can grow before the column chunk
+// is written with plain encoding instead.
+const DefaultMaxDictionarySize = 1024 * 1024
+
+// Dictionary holds the distinct values of a byte array column chunk.
+// The values are written once to the column chunk's dictionary page and
+// each data page refers to them by their index.
+type Dictionary struct {
+	index map[string]uint32
+	vals  []string
+	size  int
+	max   int
+}
+
+// NewDictionary creates a Dictionary that holds up to max bytes of
+// plain encoded values.
+func NewDictionary(max int) *Dictionary {
+	return &Dictionary{
+		index: map[string]uint32{},
+		max:   max,
+	}
+}
+
+// Add adds v to the dictionary.  It returns false if v would grow
+// the dictionary past its maximum size, which means the column chunk
+// should be plain encoded instead.
+func (d *Dictionary) Add(v string) bool {
+	if _, ok := d.index[v]; ok {
+		return true
+	}
+
+	if d.size+len(v)+4 > d.max {
+		return false
+	}
+
+	d.index[v] = uint32(len(d.vals))
+	d.vals = append(d.vals, v)
+	d.size += len(v) + 4
+	return true
+}
+
+// Len returns the number of distinct values in the dictionary.
+func (d *Dictionary) Len() int {
+	return len(d.vals)
+}
+
+// Index returns the index of v, which must have already been added.
+func (d *Dictionary) Index(v string) uint32 {
+	return d.index[v]
+}
+
+// Encode encodes the dictionary indices of a data page.
+func (d *Dictionary) Encode(ids []uint32) []byte {
+	w := d.bitWidth()
+	return append([]byte{byte(w)}, rle.Encode(w, ids)...)
+}
+
+func (d *Dictionary) bitWidth() int {
+	if w := bits.Len(uint(len(d.vals) - 1)); w > 0 {
+		return w
+	}
+	return 1
+}
+
+// plain returns the plain encoded values of the dictionary page.
+func (d *Dictionary) plain() []byte {
+	out := make([]byte, 0, d.size)
+	bs := make([]byte, 4)
+	for _, v := range d.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(v)))
+		out = append(out, bs...)
+		out = append(out, v...)
+	}
+	return out
+}
+
+// writeDictionary writes the dictionary page of a column chunk.
+func writeDictionary(w io.Writer, meta *Metadata, pth []string, d *Dictionary, codec sch.CompressionCodec, level int) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	l, cl, vals, err := compress(codec, level, buf, d.plain())
+	if err != nil {
+		return err
+	}
+
+	if err := meta.WriteDictionaryPageHeader(w, pth, l, cl, d.Len(), codec, vals); err != nil {
+		return err
+	}
+
+	_, err = w.Write(vals)
+	return err
+}
+
+// readDictionary reads the plain encoded values of a dictionary page.
+// Other writers dictionary encode all types of columns (not just byte
+// arrays), so the values of every type except BOOLEAN can be read.
+func readDictionary(data []byte, n int, pg Page) ([][]byte, error) {
+	if pg.Type != sch.Type_BYTE_ARRAY {
+		width := fixedWidth(pg.Type, pg.TypeLength)
+		if width == 0 {
+			return nil, fmt.Errorf("unsupported dictionary page for %s column", pg.Type)
+		}
+
+		if len(data) < n*width {
+			return nil, fmt.Errorf("dictionary page is too short for %d values", n)
+		}
+
+		out := make([][]byte, n)
+		for i := range out {
+			out[i] = data[i*width : (i+1)*width]
+		}
+		return out, nil
+	}
+
+	out := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("dictionary page is too short for %d values", n)
+		}
+
+		l := int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		if len(data) < l {
+			return nil, fmt.Errorf("dictionary page is too short for %d values", n)
+		}
+
+		out = append(out, data[:l])
+		data = data[l:]
+	}
+	return out, nil
+}
+
+// fixedWidth returns the size (in bytes) of each plain encoded value
+// of a column (0 if the values aren't all the same size).
+func fixedWidth(typ sch.Type, typeLength int) int {
+	switch typ {
+	case sch.Type_INT32, sch.Type_FLOAT:
+		return 4
+	case sch.Type_INT64, sch.Type_DOUBLE:
+		return 8
+	case sch.Type_INT96:
+		return 12
+	case sch.Type_FIXED_LEN_BYTE_ARRAY:
+		return typeLength
+	default:
+		return 0
+	}
+}
+
+// dictionaryValues replaces the n dictionary indices at the start of data
+// with the plain encoded values they refer to so the values can be read
+// the same way as a plain encoded page.
+func dictionaryValues(data []byte, dict [][]byte, typ sch.Type, n int) ([]byte, error) {
+	if n == 0 {
+		return nil, nil
+	}
+
+	if dict == nil {
+		return nil, fmt.Errorf("dictionary encoded page without a dictionary page")
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing dictionary indices")
+	}
+
+	ids, _, err := rle.Decode(data[1:], int(data[0]), n)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	bs := make([]byte, 4)
+	for _, id := range ids {
+		if int(id) >= len(dict) {
+			return nil, fmt.Errorf("dictionary index %d is out of range (%d values)", id, len(dict))
+		}
+		v := dict[id]
+		if typ == sch.Type_BYTE_ARRAY {
+			binary.LittleEndian.PutUint32(bs, uint32(len(v)))
+			buf.Write(bs)
+		}
+		buf.Write(v)
+	}
+	return buf.Bytes(), nil
+}
+
+func dictionaryEncoded(enc sch.Encoding) bool {
+	return enc == sch.Encoding_RLE_DICTIONARY || enc == sch.Encoding_PLAIN_DICTIONARY
+}
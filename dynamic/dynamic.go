@@ -0,0 +1,477 @@
+// Package dynamic reads parquet files without generated code.  The
+// columns are found from the schema in the file's footer, so it can read
+// files that don't have a matching go struct (which is handy for looking
+// at a file before generating the code for it).
+package dynamic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	flds "github.com/parsyl/parquet/cmd/parquetgen/fields"
+	"github.com/parsyl/parquet/cmd/parquetgen/parse"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// Reader reads each row of a parquet file as a map[string]interface{}.
+// A column is under the name of its field (its last schema element)
+// in the map of its group, so a nested column (like hobby.name) is
+// in a map of its own (like {"hobby": {"name": "napping"}}).  Null
+// values are nil, repeated fields are []interface{} and a map (a MAP
+// group) is a []interface{} of its key_value groups.
+type Reader struct {
+	r         io.ReadSeeker
+	fields    []flds.Field
+	elements  map[string]*sch.SchemaElement
+	pages     map[string][]parquet.Page
+	rowGroups []parquet.RowGroup
+	rows      []map[string]interface{}
+	row       map[string]interface{}
+	err       error
+}
+
+// NewReader reads the footer of a parquet file and
+// returns a Reader for the rows of the file.
+func NewReader(r io.ReadSeeker) (*Reader, error) {
+	footer, err := parquet.ReadMetaData(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parse.Parquet(footer.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := map[string]*sch.SchemaElement{}
+	leafElements(footer.Schema[0], footer.Schema[1:], nil, elements)
+
+	rd := &Reader{r: r, fields: root.Fields(), elements: elements}
+	schema := make([]parquet.Field, len(rd.fields))
+	for i, f := range rd.fields {
+		pth := f.ColumnNames()
+		se, ok := elements[strings.Join(pth, ".")]
+		if !ok {
+			return nil, fmt.Errorf("could not find schema for %v", pth)
+		}
+		schema[i] = parquet.Field{
+			Name:           se.Name,
+			Path:           pth,
+			Types:          repetitionTypes(f),
+			Type:           func(out *sch.SchemaElement) { copyType(out, se) },
+			RepetitionType: func(out *sch.SchemaElement) { out.RepetitionType = se.RepetitionType },
+		}
+	}
+
+	meta := parquet.New(schema...)
+	if err := meta.ReadFooter(r); err != nil {
+		return nil, err
+	}
+
+	rd.pages, err = meta.Pages()
+	if err != nil {
+		return nil, err
+	}
+	rd.rowGroups = meta.RowGroups()
+	return rd, nil
+}
+
+// leafElements adds the schema elements of the columns under parent
+// to out and returns the number of schema elements it used.
+func leafElements(parent *sch.SchemaElement, schema []*sch.SchemaElement, pth []string, out map[string]*sch.SchemaElement) int {
+	var i int
+	for j := 0; j < int(parent.GetNumChildren()) && i < len(schema); j++ {
+		se := schema[i]
+		i++
+		p := append(append([]string{}, pth...), se.Name)
+		if se.GetNumChildren() == 0 {
+			out[strings.Join(p, ".")] = se
+			continue
+		}
+		i += leafElements(se, schema[i:], p, out)
+	}
+	return i
+}
+
+func copyType(out, se *sch.SchemaElement) {
+	out.Type = se.Type
+	out.TypeLength = se.TypeLength
+	out.ConvertedType = se.ConvertedType
+	out.LogicalType = se.LogicalType
+	out.Scale = se.Scale
+	out.Precision = se.Precision
+}
+
+// repetitionTypes returns the repetition type of
+// each schema element in the path of a column.
+func repetitionTypes(f flds.Field) []int {
+	rts := f.RepetitionTypes()
+	out := make([]int, len(rts))
+	for i, rt := range rts {
+		out[i] = int(rt)
+	}
+	return out
+}
+
+// Next advances the reader to the next row, which can then be read
+// with Row.  It returns false when there are no more rows or there was
+// an error, which can be checked with Error.  Only one row group is kept
+// in memory at a time.
+func (r *Reader) Next() bool {
+	for len(r.rows) == 0 {
+		if r.err != nil || len(r.rowGroups) == 0 {
+			r.row = nil
+			return false
+		}
+
+		r.rows, r.err = r.readRowGroup()
+	}
+
+	r.row, r.rows = r.rows[0], r.rows[1:]
+	return true
+}
+
+// Row returns the current row.
+func (r *Reader) Row() map[string]interface{} {
+	return r.row
+}
+
+// Error returns the error (if any) that stopped Next.
+func (r *Reader) Error() error {
+	return r.err
+}
+
+func (r *Reader) readRowGroup() ([]map[string]interface{}, error) {
+	rg := r.rowGroups[0]
+	r.rowGroups = r.rowGroups[1:]
+
+	rows := make([]map[string]interface{}, rg.Rows)
+	for i := range rows {
+		rows[i] = map[string]interface{}{}
+	}
+
+	for _, f := range r.fields {
+		name := strings.Join(f.ColumnNames(), ".")
+		pages := r.pages[name]
+		if len(pages) == 0 {
+			return nil, fmt.Errorf("column %s is missing from the row group", name)
+		}
+		pg := pages[0]
+		r.pages[name] = pages[1:]
+
+		c, err := r.readColumn(f, pg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read column %s, err: %s", name, err)
+		}
+
+		if err := c.assemble(rows); err != nil {
+			return nil, fmt.Errorf("unable to read column %s, err: %s", name, err)
+		}
+	}
+	return rows, nil
+}
+
+// column holds the values and levels of a column chunk.
+type column struct {
+	field flds.Field
+	vals  []interface{}
+	defs  []uint8
+	reps  []uint8
+}
+
+func (r *Reader) readColumn(f flds.Field, pg parquet.Page) (*column, error) {
+	if _, err := r.r.Seek(pg.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	pth := f.ColumnNames()
+	c := &column{field: f}
+	var rd io.Reader
+	var sizes []int
+	var err error
+	if f.MaxDef() == 0 {
+		rf := parquet.NewRequiredField(pth)
+		rd, sizes, err = rf.DoRead(r.r, pg)
+	} else {
+		of := parquet.NewOptionalField(pth, repetitionTypes(f))
+		rd, sizes, err = of.DoRead(r.r, pg)
+		c.defs, c.reps = of.Defs, of.Reps
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+	for _, s := range sizes {
+		n += s
+	}
+
+	c.vals, err = readValues(rd, f, r.elements[strings.Join(pth, ".")], n, sizes)
+	return c, err
+}
+
+// readValues reads the n plain encoded values of a column.
+func readValues(r io.Reader, f flds.Field, se *sch.SchemaElement, n int, sizes []int) ([]interface{}, error) {
+	out := make([]interface{}, n)
+	if se.GetType() == sch.Type_BOOLEAN {
+		vals, err := parquet.GetBools(r, n, sizes)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(vals) != n {
+			return nil, fmt.Errorf("expected %d bools, got %d", n, len(vals))
+		}
+
+		for i, v := range vals {
+			out[i] = v
+		}
+		return out, nil
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range out {
+		var size int
+		switch se.GetType() {
+		case sch.Type_INT32, sch.Type_FLOAT:
+			size = 4
+		case sch.Type_INT64, sch.Type_DOUBLE:
+			size = 8
+		case sch.Type_INT96:
+			size = 12
+		case sch.Type_FIXED_LEN_BYTE_ARRAY:
+			size = int(se.GetTypeLength())
+		case sch.Type_BYTE_ARRAY:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("expected %d values, got %d", n, i)
+			}
+			size = int(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return nil, fmt.Errorf("unsupported type %s", se.GetType())
+		}
+
+		if len(data) < size {
+			return nil, fmt.Errorf("expected %d values, got %d", n, i)
+		}
+
+		out[i] = value(f, data[:size])
+		data = data[size:]
+	}
+	return out, nil
+}
+
+// value returns the go value (of the type that parse.Parquet
+// gave the field) of a plain encoded value.
+func value(f flds.Field, b []byte) interface{} {
+	switch f.Type {
+	case "int32":
+		return int32(binary.LittleEndian.Uint32(b))
+	case "int8":
+		return int8(binary.LittleEndian.Uint32(b))
+	case "int16":
+		return int16(binary.LittleEndian.Uint32(b))
+	case "uint8":
+		return uint8(binary.LittleEndian.Uint32(b))
+	case "uint16":
+		if f.Logical == "float16" {
+			// the bits of a half precision float
+			return binary.LittleEndian.Uint16(b)
+		}
+		return uint16(binary.LittleEndian.Uint32(b))
+	case "uint32":
+		return binary.LittleEndian.Uint32(b)
+	case "int64":
+		return int64(binary.LittleEndian.Uint64(b))
+	case "uint64":
+		return binary.LittleEndian.Uint64(b)
+	case "float32":
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	case "float64":
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case "string":
+		return string(b)
+	case "[]byte":
+		return append([]byte{}, b...)
+	case "time.Time":
+		if len(b) == 12 {
+			// INT96
+			var x [12]byte
+			copy(x[:], b)
+			return parquet.Int96Time(x)
+		}
+		if f.Logical == "date" {
+			return time.Unix(int64(int32(binary.LittleEndian.Uint32(b)))*86400, 0).UTC()
+		}
+		x := int64(binary.LittleEndian.Uint64(b))
+		switch f.Unit {
+		case "micros":
+			return time.Unix(x/1000000, (x%1000000)*int64(time.Microsecond)).UTC()
+		case "nanos":
+			return time.Unix(0, x).UTC()
+		}
+		return time.Unix(x/1000, (x%1000)*int64(time.Millisecond)).UTC()
+	case "time.Duration":
+		if f.Logical == "time" {
+			switch f.Unit {
+			case "micros":
+				return time.Duration(binary.LittleEndian.Uint64(b)) * time.Microsecond
+			case "nanos":
+				return time.Duration(binary.LittleEndian.Uint64(b))
+			}
+			return time.Duration(int32(binary.LittleEndian.Uint32(b))) * time.Millisecond
+		}
+		var x [12]byte
+		copy(x[:], b)
+		return parquet.IntervalDuration(x)
+	default:
+		// FIXED_LEN_BYTE_ARRAY
+		v := reflect.New(reflect.ArrayOf(len(b), reflect.TypeOf(byte(0)))).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v.Interface()
+	}
+}
+
+// assemble puts the values of the column in the rows.  Each value
+// (or null) starts a new row if its repetition level is 0 and starts
+// a new element of the repeated field at its repetition level
+// otherwise.
+func (c *column) assemble(rows []map[string]interface{}) error {
+	chain := flds.Reverse(c.field.Chain())[1:]
+	if c.defs == nil {
+		if len(c.vals) != len(rows) {
+			return fmt.Errorf("expected %d values, got %d", len(rows), len(c.vals))
+		}
+
+		for i, v := range c.vals {
+			setPath(rows[i], chain, v)
+		}
+		return nil
+	}
+
+	// defAt and repAt are the definition and repetition levels
+	// of each of the fields in the column's path
+	defAt := make([]uint8, len(chain))
+	repAt := make([]uint8, len(chain))
+	var def, rep uint8
+	for i, f := range chain {
+		if f.RepetitionType != flds.Required {
+			def++
+		}
+		if f.RepetitionType == flds.Repeated {
+			rep++
+		}
+		defAt[i], repAt[i] = def, rep
+	}
+
+	row := -1
+	var v int
+	// idx is the index of the current element of each repeated field
+	idx := make([]int, len(chain))
+	for i, d := range c.defs {
+		var r uint8
+		if c.reps != nil {
+			r = c.reps[i]
+		}
+
+		if r == 0 {
+			row++
+		}
+
+		if row >= len(rows) {
+			return fmt.Errorf("expected %d rows, got more", len(rows))
+		}
+
+		var val interface{}
+		if d == def {
+			if v >= len(c.vals) {
+				return fmt.Errorf("expected %d values, got %d", v+1, len(c.vals))
+			}
+			val = c.vals[v]
+			v++
+		}
+
+		var parent interface{} = rows[row]
+		for j, f := range chain {
+			m := parent.(map[string]interface{})
+			if d < defAt[j] {
+				// the field is null (or an empty list)
+				if _, ok := m[f.ColumnName]; !ok {
+					if f.RepetitionType == flds.Repeated {
+						m[f.ColumnName] = []interface{}{}
+					} else {
+						m[f.ColumnName] = nil
+					}
+				}
+				break
+			}
+
+			leaf := j == len(chain)-1
+			if f.RepetitionType != flds.Repeated {
+				if leaf {
+					m[f.ColumnName] = val
+					break
+				}
+
+				if _, ok := m[f.ColumnName].(map[string]interface{}); !ok {
+					m[f.ColumnName] = map[string]interface{}{}
+				}
+				parent = m[f.ColumnName]
+				continue
+			}
+
+			list, _ := m[f.ColumnName].([]interface{})
+			switch {
+			case r < repAt[j]:
+				idx[j] = 0
+			case r == repAt[j]:
+				idx[j]++
+			}
+
+			for len(list) <= idx[j] {
+				if leaf {
+					list = append(list, nil)
+				} else {
+					list = append(list, map[string]interface{}{})
+				}
+			}
+			m[f.ColumnName] = list
+
+			if leaf {
+				list[idx[j]] = val
+				break
+			}
+			parent = list[idx[j]]
+		}
+	}
+
+	if row != len(rows)-1 {
+		return fmt.Errorf("expected %d rows, got %d", len(rows), row+1)
+	}
+	return nil
+}
+
+// setPath sets the value of a required column (whose
+// path only has required fields) in a row.
+func setPath(row map[string]interface{}, chain []flds.Field, v interface{}) {
+	m := row
+	for _, f := range chain[:len(chain)-1] {
+		if _, ok := m[f.ColumnName].(map[string]interface{}); !ok {
+			m[f.ColumnName] = map[string]interface{}{}
+		}
+		m = m[f.ColumnName].(map[string]interface{})
+	}
+	m[chain[len(chain)-1].ColumnName] = v
+}
@@ -0,0 +1,158 @@
+package dynamic_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/cmd/parquetgen/dremel/testcases/doc"
+	"github.com/parsyl/parquet/dynamic"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReader(t *testing.T) {
+	testCases := []struct {
+		name string
+		doc  doc.Document
+		want map[string]interface{}
+	}{
+		{
+			name: "r1",
+			doc: doc.Document{
+				DocID: 10,
+				Links: &doc.Link{Forward: []int64{20, 40, 60}},
+				Names: []doc.Name{
+					{
+						Languages: []doc.Language{
+							{Code: "en-us", Country: pstring("us")},
+							{Code: "en"},
+						},
+						URL: pstring("http://A"),
+					},
+					{URL: pstring("http://B")},
+					{Languages: []doc.Language{{Code: "en-gb", Country: pstring("gb")}}},
+				},
+			},
+			want: map[string]interface{}{
+				"docid": int64(10),
+				"link": map[string]interface{}{
+					"backward": []interface{}{},
+					"forward":  []interface{}{int64(20), int64(40), int64(60)},
+				},
+				"names": []interface{}{
+					map[string]interface{}{
+						"languages": []interface{}{
+							map[string]interface{}{"code": "en-us", "country": "us"},
+							map[string]interface{}{"code": "en", "country": nil},
+						},
+						"url": "http://A",
+					},
+					map[string]interface{}{"languages": []interface{}{}, "url": "http://B"},
+					map[string]interface{}{
+						"languages": []interface{}{
+							map[string]interface{}{"code": "en-gb", "country": "gb"},
+						},
+						"url": nil,
+					},
+				},
+			},
+		},
+		{
+			name: "r2",
+			doc: doc.Document{
+				DocID: 20,
+				Links: &doc.Link{Backward: []int64{10, 30}, Forward: []int64{80}},
+				Names: []doc.Name{{URL: pstring("http://C")}},
+			},
+			want: map[string]interface{}{
+				"docid": int64(20),
+				"link": map[string]interface{}{
+					"backward": []interface{}{int64(10), int64(30)},
+					"forward":  []interface{}{int64(80)},
+				},
+				"names": []interface{}{
+					map[string]interface{}{"languages": []interface{}{}, "url": "http://C"},
+				},
+			},
+		},
+		{
+			name: "nulls",
+			doc:  doc.Document{DocID: 30},
+			want: map[string]interface{}{
+				"docid": int64(30),
+				"link":  nil,
+				"names": []interface{}{},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := doc.NewParquetWriter(&buf, doc.MaxPageSize(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tc := range testCases {
+		w.Add(tc.doc)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := dynamic.NewReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if assert.True(t, r.Next()) {
+				assert.Equal(t, tc.want, r.Row())
+			}
+		})
+	}
+
+	assert.False(t, r.Next())
+	assert.NoError(t, r.Error())
+}
+
+func TestReaderBSON(t *testing.T) {
+	bson := func(se *sch.SchemaElement) {
+		typ, ct := sch.Type_BYTE_ARRAY, sch.ConvertedType_BSON
+		se.Type, se.ConvertedType = &typ, &ct
+	}
+
+	var buf bytes.Buffer
+	w, err := parquet.NewFileWriter(&buf, parquet.Field{Name: "doc", Path: []string{"doc"}, Types: []int{1}, Type: bson, RepetitionType: parquet.RepetitionOptional})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	c, err := w.Column("doc")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, c.WriteBytes([]byte{0x05, 0x00, 0x00, 0x00, 0x00}, 0))
+	assert.NoError(t, c.WriteNull(0, 0))
+	assert.NoError(t, w.Close())
+
+	r, err := dynamic.NewReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, want := range []map[string]interface{}{
+		{"doc": []byte{0x05, 0x00, 0x00, 0x00, 0x00}},
+		{"doc": nil},
+	} {
+		if assert.True(t, r.Next()) {
+			assert.Equal(t, want, r.Row())
+		}
+	}
+	assert.False(t, r.Next())
+	assert.NoError(t, r.Error())
+}
+
+func pstring(s string) *string {
+	return &s
+}
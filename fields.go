@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"math/bits"
 	"strings"
+	"sync"
 
 	"github.com/valyala/bytebufferpool"
 
@@ -12,9 +13,13 @@ import (
 
 	"io"
 
+	"github.com/andybalholm/brotli"
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/parsyl/parquet/internal/bitpack"
 	"github.com/parsyl/parquet/internal/rle"
 	sch "github.com/parsyl/parquet/schema"
+	"github.com/pierrec/lz4/v4"
 )
 
 // RepetitionType is an enum of the possible
@@ -60,6 +65,11 @@ func (r RepetitionTypes) MaxRep() uint8 {
 type RequiredField struct {
 	pth         []string
 	compression sch.CompressionCodec
+	level       int
+	dict        *Dictionary
+	delta       bool
+	byteArray   sch.Encoding
+	v2          bool
 }
 
 // NewRequiredField creates a required field.
@@ -92,17 +102,55 @@ func RequiredFieldUncompressed(r *RequiredField) {
 	r.compression = sch.CompressionCodec_UNCOMPRESSED
 }
 
+// RequiredFieldZstd sets the compression for a column to zstd
+// It is an optional arg to NewRequiredField
+func RequiredFieldZstd(r *RequiredField) {
+	r.compression = sch.CompressionCodec_ZSTD
+}
+
+// RequiredFieldLz4Raw sets the compression for a column to LZ4_RAW
+// (lz4 blocks without a frame).  It is an optional arg to NewRequiredField
+func RequiredFieldLz4Raw(r *RequiredField) {
+	r.compression = sch.CompressionCodec_LZ4_RAW
+}
+
+// RequiredFieldZstdLevel sets the compression for a column to zstd
+// with the given level (1 is the fastest, 22 the smallest and 0 is
+// the default). It is an optional arg to NewRequiredField
+func RequiredFieldZstdLevel(level int) func(*RequiredField) {
+	return func(r *RequiredField) {
+		r.compression = sch.CompressionCodec_ZSTD
+		r.level = level
+	}
+}
+
 // DoWrite writes the actual raw data.
 func (f *RequiredField) DoWrite(w io.Writer, meta *Metadata, vals []byte, count int, stats Stats) error {
+	vals, enc := encodeValues(meta, f.pth, vals, f.dict, f.delta, f.byteArray)
+	return f.doWrite(w, meta, vals, count, enc, stats)
+}
+
+// DoWriteBools writes the values of a boolean column.  They are
+// RLE encoded if that is smaller than plain encoding them.
+func (f *RequiredField) DoWriteBools(w io.Writer, meta *Metadata, vals []bool, count int, stats Stats) error {
+	b, enc := encodeBools(vals)
+	return f.doWrite(w, meta, b, count, enc, stats)
+}
+
+func (f *RequiredField) doWrite(w io.Writer, meta *Metadata, vals []byte, count int, enc sch.Encoding, stats Stats) error {
+	if f.v2 {
+		return writeDataPageV2(w, meta, f.pth, nil, nil, vals, count, 0, count, f.compression, f.level, enc, stats)
+	}
+
 	buff := buffpool.Get()
 	defer buffpool.Put(buff)
 
-	l, cl, vals, err := compress(f.compression, buff, vals)
+	l, cl, vals, err := compress(f.compression, f.level, buff, vals)
 	if err != nil {
 		return err
 	}
 
-	if err := meta.WritePageHeader(w, f.pth, l, cl, count, count, 0, 0, f.compression, stats); err != nil {
+	if err := meta.WritePageHeader(w, f.pth, l, cl, count, count, 0, 0, f.compression, enc, stats, vals); err != nil {
 		return err
 	}
 
@@ -110,28 +158,70 @@ func (f *RequiredField) DoWrite(w io.Writer, meta *Metadata, vals []byte, count
 	return err
 }
 
+// SetDictionary makes DoWrite write pages whose values are
+// indices in to d (see Dictionary.Encode).
+func (f *RequiredField) SetDictionary(d *Dictionary) {
+	f.dict = d
+}
+
+// Dictionary returns the dictionary set by SetDictionary
+func (f *RequiredField) Dictionary() *Dictionary {
+	return f.dict
+}
+
+// SetDeltaBinaryPacked makes DoWrite write pages with DELTA_BINARY_PACKED
+// encoding when the column is INT32 or INT64 (other columns are still
+// plain encoded).
+func (f *RequiredField) SetDeltaBinaryPacked() {
+	f.delta = true
+}
+
+// SetByteArrayEncoding makes DoWrite write pages with enc, which is
+// DELTA_LENGTH_BYTE_ARRAY or DELTA_BYTE_ARRAY, when the column is a
+// BYTE_ARRAY that isn't dictionary encoded.
+func (f *RequiredField) SetByteArrayEncoding(enc sch.Encoding) {
+	f.byteArray = enc
+}
+
+// SetDataPageV2 makes DoWrite write DATA_PAGE_V2 pages instead of
+// DATA_PAGE pages.
+func (f *RequiredField) SetDataPageV2() {
+	f.v2 = true
+}
+
+// WriteDictionary writes the dictionary page of the column chunk.
+// It must be called before the column chunk's first DoWrite.
+func (f *RequiredField) WriteDictionary(w io.Writer, meta *Metadata) error {
+	return writeDictionary(w, meta, f.pth, f.dict, f.compression, f.level)
+}
+
 // DoRead reads the actual raw data.
 func (f *RequiredField) DoRead(r io.ReadSeeker, pg Page) (io.Reader, []int, error) {
-	var nRead int
 	var out []byte
 	var sizes []int
-	for nRead < pg.N {
-		ph, err := PageHeader(r)
-		if err != nil {
-			return nil, nil, err
+	var dict [][]byte
+	err := readPages(r, pg, func(ph *sch.PageHeader, data []byte) error {
+		var err error
+		if ph.Type == sch.PageType_DICTIONARY_PAGE {
+			dict, err = readDictionary(data, int(ph.DictionaryPageHeader.NumValues), pg)
+			return err
 		}
 
-		sizes = append(sizes, int(ph.DataPageHeader.NumValues))
+		if !dataPage(ph) {
+			return nil
+		}
 
-		data, err := pageData(r, ph, pg)
+		n, enc := dataPageHeader(ph)
+		data, err = decodeValues(data, enc, dict, pg.Type, n)
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
 
+		sizes = append(sizes, n)
 		out = append(out, data...)
-		nRead += int(ph.DataPageHeader.NumValues)
-	}
-	return bytes.NewBuffer(out), sizes, nil
+		return nil
+	})
+	return bytes.NewBuffer(out), sizes, err
 }
 
 // Name returns the column name of this field
@@ -159,9 +249,20 @@ type OptionalField struct {
 	pth            []string
 	MaxLevels      MaxLevel
 	compression    sch.CompressionCodec
+	level          int
+	dict           *Dictionary
+	delta          bool
+	byteArray      sch.Encoding
+	v2             bool
 	RepetitionType FieldFunc
 	Types          []int
-	repeated       bool
+	// Groups sets the types of the groups in the field's
+	// path (see OptionalFieldGroup).
+	Groups   []FieldFunc
+	repeated bool
+	// bitPacked makes v1 data pages use the deprecated BIT_PACKED
+	// encoding for their levels (see OptionalFieldBitPackedLevels).
+	bitPacked bool
 }
 
 func getRepetitionTypes(in []int) RepetitionTypes {
@@ -211,6 +312,50 @@ func OptionalFieldUncompressed(o *OptionalField) {
 	o.compression = sch.CompressionCodec_UNCOMPRESSED
 }
 
+// OptionalFieldZstd sets the compression for a column to zstd
+// It is an optional arg to NewOptionalField
+func OptionalFieldZstd(o *OptionalField) {
+	o.compression = sch.CompressionCodec_ZSTD
+}
+
+// OptionalFieldLz4Raw sets the compression for a column to LZ4_RAW
+// (lz4 blocks without a frame).  It is an optional arg to NewOptionalField
+func OptionalFieldLz4Raw(o *OptionalField) {
+	o.compression = sch.CompressionCodec_LZ4_RAW
+}
+
+// OptionalFieldZstdLevel sets the compression for a column to zstd
+// with the given level (1 is the fastest, 22 the smallest and 0 is
+// the default). It is an optional arg to NewOptionalField
+func OptionalFieldZstdLevel(level int) func(*OptionalField) {
+	return func(o *OptionalField) {
+		o.compression = sch.CompressionCodec_ZSTD
+		o.level = level
+	}
+}
+
+// OptionalFieldGroup sets the type of the group at the nth element
+// of the field's path (for example, MapGroup for the outer group of
+// the key and value columns of a map).  It is an optional arg to
+// NewOptionalField
+func OptionalFieldGroup(n int, typ FieldFunc) func(*OptionalField) {
+	return func(o *OptionalField) {
+		if o.Groups == nil {
+			o.Groups = make([]FieldFunc, len(o.pth)-1)
+		}
+		o.Groups[n] = typ
+	}
+}
+
+// OptionalFieldBitPackedLevels makes the field's data pages (but not its
+// DATA_PAGE_V2 pages, which can only use the RLE/bit-packing hybrid) use
+// the deprecated BIT_PACKED encoding for their definition and repetition
+// levels.  It is only meant for checking that other readers can read them.
+// It is an optional arg to NewOptionalField
+func OptionalFieldBitPackedLevels(o *OptionalField) {
+	o.bitPacked = true
+}
+
 // Values reads the definition levels and uses them
 // to return the values from the page data.
 func (f *OptionalField) Values() int {
@@ -230,90 +375,243 @@ func (f *OptionalField) valsFromDefs(defs []uint8, max uint8) int {
 // DoWrite is called by all optional field types to write the definition levels
 // and raw data to the io.Writer
 func (f *OptionalField) DoWrite(w io.Writer, meta *Metadata, vals []byte, count int, stats Stats) error {
+	vals, enc := encodeValues(meta, f.pth, vals, f.dict, f.delta, f.byteArray)
+	return f.doWrite(w, meta, vals, count, enc, stats)
+}
+
+// DoWriteBools writes the definition levels and values of a boolean column.
+// The values are RLE encoded if that is smaller than plain encoding them.
+func (f *OptionalField) DoWriteBools(w io.Writer, meta *Metadata, vals []bool, count int, stats Stats) error {
+	b, enc := encodeBools(vals)
+	return f.doWrite(w, meta, b, count, enc, stats)
+}
+
+func (f *OptionalField) doWrite(w io.Writer, meta *Metadata, vals []byte, count int, enc sch.Encoding, stats Stats) error {
+	if f.v2 {
+		return f.doWriteV2(w, meta, vals, count, enc, stats)
+	}
+
 	buf := buffpool.Get()
 	defer buffpool.Put(buf)
 	wc := &writeCounter{w: buf}
 
 	var repLen int64
 
+	write, levelEnc := writeLevels, sch.Encoding_RLE
+	if f.bitPacked {
+		write, levelEnc = writeBitPackedLevels, sch.Encoding_BIT_PACKED
+	}
+
 	if f.repeated {
-		err := writeLevels(wc, f.Reps, int32(bits.Len(uint(f.MaxLevels.Rep))))
+		err := write(wc, f.Reps, int32(bits.Len(uint(f.MaxLevels.Rep))))
 		if err != nil {
 			return err
 		}
 		repLen = wc.n
 	}
 
-	err := writeLevels(wc, f.Defs, int32(bits.Len(uint(f.MaxLevels.Def))))
-	if err != nil {
-		return err
+	// a column whose types are all required has no definition levels
+	if f.MaxLevels.Def > 0 {
+		err := write(wc, f.Defs, int32(bits.Len(uint(f.MaxLevels.Def))))
+		if err != nil {
+			return err
+		}
 	}
 
 	defLen := wc.n - repLen
 
-	if _, err = wc.Write(vals); err != nil {
+	if _, err := wc.Write(vals); err != nil {
 		return err
 	}
 
 	compressed := buffpool.Get()
 	defer buffpool.Put(compressed)
 
-	l, cl, vals, err := compress(f.compression, compressed, buf.Bytes())
+	l, cl, vals, err := compress(f.compression, f.level, compressed, buf.Bytes())
 	if err != nil {
 		return err
 	}
 
-	if err := meta.WritePageHeader(w, f.pth, l, cl, len(f.Defs), count, defLen, repLen, f.compression, stats); err != nil {
+	_, rows := f.nullsAndRows()
+	if err := meta.writeDataPageHeader(w, f.pth, l, cl, rows, count, defLen, repLen, f.compression, enc, levelEnc, stats, vals); err != nil {
 		return err
 	}
 	_, err = w.Write(vals)
 	return err
 }
 
+func (f *OptionalField) doWriteV2(w io.Writer, meta *Metadata, vals []byte, count int, enc sch.Encoding, stats Stats) error {
+	var reps []byte
+	if f.repeated {
+		reps = encodeLevels(f.Reps, f.MaxLevels.Rep)
+	}
+	var defs []byte
+	if f.MaxLevels.Def > 0 {
+		defs = encodeLevels(f.Defs, f.MaxLevels.Def)
+	}
+
+	nulls, rows := f.nullsAndRows()
+	return writeDataPageV2(w, meta, f.pth, reps, defs, vals, count, nulls, rows, f.compression, f.level, enc, stats)
+}
+
+// nullsAndRows returns the number of null values and
+// the number of rows of the page that is being written.
+func (f *OptionalField) nullsAndRows() (int, int) {
+	var nulls, rows int
+	for i, d := range f.Defs {
+		if d < f.MaxLevels.Def {
+			nulls++
+		}
+		if !f.repeated || f.Reps[i] == 0 {
+			rows++
+		}
+	}
+	return nulls, rows
+}
+
+// SetDictionary makes DoWrite write pages whose values are
+// indices in to d (see Dictionary.Encode).
+func (f *OptionalField) SetDictionary(d *Dictionary) {
+	f.dict = d
+}
+
+// Dictionary returns the dictionary set by SetDictionary
+func (f *OptionalField) Dictionary() *Dictionary {
+	return f.dict
+}
+
+// SetDeltaBinaryPacked makes DoWrite write pages with DELTA_BINARY_PACKED
+// encoding when the column is INT32 or INT64 (other columns are still
+// plain encoded).
+func (f *OptionalField) SetDeltaBinaryPacked() {
+	f.delta = true
+}
+
+// SetByteArrayEncoding makes DoWrite write pages with enc, which is
+// DELTA_LENGTH_BYTE_ARRAY or DELTA_BYTE_ARRAY, when the column is a
+// BYTE_ARRAY that isn't dictionary encoded.
+func (f *OptionalField) SetByteArrayEncoding(enc sch.Encoding) {
+	f.byteArray = enc
+}
+
+// SetDataPageV2 makes DoWrite write DATA_PAGE_V2 pages instead of
+// DATA_PAGE pages.
+func (f *OptionalField) SetDataPageV2() {
+	f.v2 = true
+}
+
+// WriteDictionary writes the dictionary page of the column chunk.
+// It must be called before the column chunk's first DoWrite.
+func (f *OptionalField) WriteDictionary(w io.Writer, meta *Metadata) error {
+	return writeDictionary(w, meta, f.pth, f.dict, f.compression, f.level)
+}
+
 // DoRead is called by all optional fields.  It reads the definition levels and uses
 // them to interpret the raw data.
 func (f *OptionalField) DoRead(r io.ReadSeeker, pg Page) (io.Reader, []int, error) {
-	var nRead int
 	var out []byte
 	var sizes []int
-	var rc *readCounter
+	var dict [][]byte
+	err := readPages(r, pg, func(ph *sch.PageHeader, data []byte) error {
+		var err error
+		if ph.Type == sch.PageType_DICTIONARY_PAGE {
+			dict, err = readDictionary(data, int(ph.DictionaryPageHeader.NumValues), pg)
+			return err
+		}
+
+		if !dataPage(ph) {
+			return nil
+		}
 
-	for nRead < pg.Size {
-		rc = &readCounter{r: r}
-		ph, err := PageHeader(rc)
+		count, enc := dataPageHeader(ph)
+		reps, defs, l, err := f.readLevels(ph, data, count)
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
+		f.Reps = append(f.Reps, reps...)
+		f.Defs = append(f.Defs, defs...)
 
-		data, err := pageData(rc, ph, pg)
+		n := f.valsFromDefs(defs, uint8(f.MaxLevels.Def))
+		vals, err := decodeValues(data[l:], enc, dict, pg.Type, n)
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
 
-		var l int
+		sizes = append(sizes, n)
+		out = append(out, vals...)
+		return nil
+	})
+	return bytes.NewBuffer(out), sizes, err
+}
 
+// readLevels reads the count repetition levels (if the field is repeated)
+// and definition levels at the start of a data page and returns them with
+// the number of bytes they took up.  A page of a column whose types are
+// all required doesn't have definition levels, so they are all 0.
+func (f *OptionalField) readLevels(ph *sch.PageHeader, data []byte, count int) ([]uint8, []uint8, int, error) {
+	repWidth := int32(bits.Len(uint(f.MaxLevels.Rep)))
+	defWidth := int32(bits.Len(uint(f.MaxLevels.Def)))
+
+	if h := ph.DataPageHeaderV2; h != nil {
+		rl, dl := int(h.RepetitionLevelsByteLength), int(h.DefinitionLevelsByteLength)
+		var reps []uint8
+		var err error
 		if f.repeated {
-			reps, l2, err := readLevels(bytes.NewBuffer(data[l:]), int32(bits.Len(uint(f.MaxLevels.Rep))))
+			reps, err = decodeLevels(data[:rl], repWidth, count)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, 0, err
 			}
-			f.Reps = append(f.Reps, reps[:int(ph.DataPageHeader.NumValues)]...)
-			l += l2
 		}
 
-		defs, l2, err := readLevels(bytes.NewBuffer(data[l:]), int32(bits.Len(uint(f.MaxLevels.Def))))
+		if defWidth == 0 {
+			return reps, make([]uint8, count), rl + dl, nil
+		}
+
+		defs, err := decodeLevels(data[rl:rl+dl], defWidth, count)
+		return reps, defs, rl + dl, err
+	}
+
+	h := ph.DataPageHeader
+	var l int
+	var reps []uint8
+	if f.repeated {
+		r, l2, err := readV1Levels(h.RepetitionLevelEncoding, data, repWidth, count)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, 0, fmt.Errorf("repetition levels: %s", err)
 		}
-		f.Defs = append(f.Defs, defs[:int(ph.DataPageHeader.NumValues)]...)
+		reps = r
 		l += l2
+	}
 
-		n := f.valsFromDefs(defs, uint8(f.MaxLevels.Def))
-		sizes = append(sizes, n)
-		out = append(out, data[l:]...)
-		nRead += int(rc.n)
+	if defWidth == 0 {
+		return reps, make([]uint8, count), l, nil
+	}
+
+	defs, l2, err := readV1Levels(h.DefinitionLevelEncoding, data[l:], defWidth, count)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("definition levels: %s", err)
+	}
+	return reps, defs, l + l2, nil
+}
+
+// readV1Levels reads the count levels at the start of data, which is
+// part of a v1 data page, and returns them with the number of bytes
+// they took up.
+func readV1Levels(enc sch.Encoding, data []byte, width int32, count int) ([]uint8, int, error) {
+	switch enc {
+	case sch.Encoding_RLE:
+		levels, n, err := readLevels(bytes.NewBuffer(data), width)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(levels) < count {
+			return nil, 0, fmt.Errorf("found %d levels, expected %d", len(levels), count)
+		}
+		return levels[:count], n, nil
+	case sch.Encoding_BIT_PACKED:
+		return decodeBitPackedLevels(data, width, count)
 	}
-	return bytes.NewBuffer(out), sizes, nil
+	return nil, 0, fmt.Errorf("unsupported %s encoding", enc)
 }
 
 // Name returns the column name of this field
@@ -355,53 +653,242 @@ func (r *readCounter) Read(p []byte) (int, error) {
 	return n, err
 }
 
-func pageData(r io.Reader, ph *sch.PageHeader, pg Page) ([]byte, error) {
-	var data []byte
-	switch pg.Codec {
-	case sch.CompressionCodec_SNAPPY:
-		compressed := make([]byte, ph.CompressedPageSize)
-		if _, err := r.Read(compressed); err != nil {
-			return nil, err
+// readPages calls fn with the header and (decompressed) data of each of
+// the column chunk's pages, starting at the reader's position.  When pg has
+// Locations only the dictionary page (if the column chunk starts with one)
+// and the data pages at the Locations are read.  The column chunk (or the
+// dictionary page and each of the pages) is read all at once, which is one
+// range request when r reads from an object store.
+func readPages(r io.ReadSeeker, pg Page, fn func(ph *sch.PageHeader, data []byte) error) error {
+	var bufs readBuffers
+	defer bufs.release()
+
+	readPage := func(br *bytes.Reader) error {
+		ph, err := PageHeader(br)
+		if err != nil {
+			return err
 		}
 
-		var err error
-		data, err = snappy.Decode(nil, compressed)
+		data, err := pageData(br, ph, pg, &bufs)
 		if err != nil {
-			return nil, err
+			return err
 		}
-	case sch.CompressionCodec_GZIP:
-		var buf bytes.Buffer
-		_, err := io.CopyN(&buf, r, int64(ph.CompressedPageSize))
+		return fn(ph, data)
+	}
+
+	if len(pg.Locations) == 0 {
+		br, err := readRange(r, int64(pg.Size), &bufs)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		for br.Len() > 0 {
+			if err := readPage(br); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// the dictionary page is between the start of
+	// the column chunk and the first data page
+	if pg.Offset < pg.DataOffset {
+		if _, err := r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return err
 		}
 
-		zr, err := gzip.NewReader(&buf)
+		br, err := readRange(r, pg.DataOffset-pg.Offset, &bufs)
 		if err != nil {
+			return err
+		}
+
+		if err := readPage(br); err != nil {
+			return err
+		}
+	}
+
+	for _, loc := range pg.Locations {
+		if _, err := r.Seek(loc.Offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		br, err := readRange(r, int64(loc.CompressedPageSize), &bufs)
+		if err != nil {
+			return err
+		}
+
+		if err := readPage(br); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBufferPool holds the buffers that column chunks are read in to
+// (and their pages are decompressed in to) so each read doesn't need
+// new ones.
+var readBufferPool = sync.Pool{
+	New: func() interface{} { return new([]byte) },
+}
+
+// readBuffers are the buffers that readPages has taken from the pool.
+// The dictionary values of a column chunk point in to its dictionary
+// page, so none of them go back until readPages is done.  Everything
+// that DoRead returns is copied out of them.
+type readBuffers []*[]byte
+
+// get returns a buffer of n bytes.
+func (b *readBuffers) get(n int) []byte {
+	buf := readBufferPool.Get().(*[]byte)
+	*b = append(*b, buf)
+	if cap(*buf) < n {
+		*buf = make([]byte, n)
+	}
+	*buf = (*buf)[:n]
+	return *buf
+}
+
+// release puts the buffers back in the pool.
+func (b *readBuffers) release() {
+	for _, buf := range *b {
+		readBufferPool.Put(buf)
+	}
+}
+
+// readRange reads the next n bytes of r.
+func readRange(r io.Reader, n int64, bufs *readBuffers) (*bytes.Reader, error) {
+	buf := bufs.get(int(n))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(buf), nil
+}
+
+func pageData(r io.Reader, ph *sch.PageHeader, pg Page, bufs *readBuffers) ([]byte, error) {
+	compressed := bufs.get(int(ph.CompressedPageSize))
+	if _, err := io.ReadFull(r, compressed); err != nil {
+		return nil, err
+	}
+
+	if pg.Checksums {
+		if err := verifyChecksum(ph, compressed); err != nil {
 			return nil, err
 		}
+	}
+
+	h := ph.DataPageHeaderV2
+	if h == nil {
+		return decompress(pg.Codec, compressed, bufs.get(int(ph.UncompressedPageSize)))
+	}
+
+	// the levels of a v2 data page aren't compressed
+	l := int(h.RepetitionLevelsByteLength + h.DefinitionLevelsByteLength)
+	if l > len(compressed) {
+		return nil, fmt.Errorf("data page v2 levels (%d bytes) are bigger than the page (%d bytes)", l, len(compressed))
+	}
+
+	if !h.IsCompressed {
+		return compressed, nil
+	}
 
-		data, err = io.ReadAll(zr)
+	out := bufs.get(int(ph.UncompressedPageSize))
+	if l > len(out) {
+		return nil, fmt.Errorf("data page v2 levels (%d bytes) are bigger than the uncompressed page (%d bytes)", l, len(out))
+	}
+	copy(out, compressed[:l])
+
+	vals, err := decompress(pg.Codec, compressed[l:], out[l:])
+	if err != nil {
+		return nil, err
+	}
+	// vals is usually already in out (after the levels)
+	return append(out[:l], vals...), nil
+}
+
+// decompress decompresses a page in to dst (which is the size of the
+// uncompressed page) unless it turns out to be too small.
+func decompress(codec sch.CompressionCodec, compressed, dst []byte) ([]byte, error) {
+	switch codec {
+	case sch.CompressionCodec_SNAPPY:
+		return snappy.Decode(dst, compressed)
+	case sch.CompressionCodec_GZIP:
+		zr, err := gzip.NewReader(bytes.NewReader(compressed))
 		if err != nil {
 			return nil, err
 		}
 
-		if err := zr.Close(); err != nil {
+		data, err := io.ReadAll(zr)
+		if err != nil {
 			return nil, err
 		}
-	case sch.CompressionCodec_UNCOMPRESSED:
-		data = make([]byte, ph.UncompressedPageSize)
-		if _, err := r.Read(data); err != nil {
+		return data, zr.Close()
+	case sch.CompressionCodec_ZSTD:
+		return zstdDecoder.DecodeAll(compressed, dst[:0])
+	case sch.CompressionCodec_BROTLI:
+		// brotli pages can be read but not written
+		buf := bytes.NewBuffer(dst[:0])
+		if _, err := buf.ReadFrom(brotli.NewReader(bytes.NewReader(compressed))); err != nil {
 			return nil, err
 		}
+		return buf.Bytes(), nil
+	case sch.CompressionCodec_LZ4_RAW:
+		n, err := lz4.UncompressBlock(compressed, dst)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress LZ4_RAW page: %s", err)
+		}
+		return dst[:n], nil
+	case sch.CompressionCodec_LZ4:
+		// LZ4 is the deprecated codec whose blocks are framed (by
+		// hadoop or the lz4 frame format, depending on the writer)
+		return nil, fmt.Errorf("unsupported column chunk codec: %s (only LZ4_RAW is supported)", codec)
+	case sch.CompressionCodec_UNCOMPRESSED:
+		return compressed, nil
+	default:
+		return nil, fmt.Errorf("unsupported column chunk codec: %s", codec)
+	}
+}
+
+// dataPage returns false for the pages (like index pages) that
+// don't hold any of a column's values.
+func dataPage(ph *sch.PageHeader) bool {
+	return ph.DataPageHeader != nil || ph.DataPageHeaderV2 != nil
+}
+
+// dataPageHeader returns the number of values (including nulls)
+// and the encoding of a v1 or v2 data page.
+func dataPageHeader(ph *sch.PageHeader) (int, sch.Encoding) {
+	switch {
+	case ph.DataPageHeaderV2 != nil:
+		return int(ph.DataPageHeaderV2.NumValues), ph.DataPageHeaderV2.Encoding
+	case ph.DataPageHeader != nil:
+		return int(ph.DataPageHeader.NumValues), ph.DataPageHeader.Encoding
 	default:
-		return nil, fmt.Errorf("unsupported column chunk codec: %s", pg.Codec)
+		return 0, sch.Encoding_PLAIN
 	}
+}
 
-	return data, nil
+// decodeValues returns the n values of a data page plain
+// encoded so they can all be read the same way.
+func decodeValues(vals []byte, enc sch.Encoding, dict [][]byte, typ sch.Type, n int) ([]byte, error) {
+	switch {
+	case enc == sch.Encoding_PLAIN:
+		return vals, nil
+	case dictionaryEncoded(enc):
+		return dictionaryValues(vals, dict, typ, n)
+	case enc == sch.Encoding_RLE && typ == sch.Type_BOOLEAN:
+		return decodeBools(vals, n)
+	case enc == sch.Encoding_DELTA_BINARY_PACKED && deltaWidth(typ) > 0:
+		return decodeDelta(vals, deltaWidth(typ), n)
+	case enc == sch.Encoding_DELTA_LENGTH_BYTE_ARRAY:
+		return decodeDeltaLength(vals, n)
+	case enc == sch.Encoding_DELTA_BYTE_ARRAY:
+		return decodeDeltaByteArray(vals, n)
+	default:
+		return nil, fmt.Errorf("unsupported %s encoding for %s column", enc, typ)
+	}
 }
 
-func compress(codec sch.CompressionCodec, buf *bytebufferpool.ByteBuffer, vals []byte) (int, int, []byte, error) {
+func compress(codec sch.CompressionCodec, level int, buf *bytebufferpool.ByteBuffer, vals []byte) (int, int, []byte, error) {
 	var err error
 	l := len(vals)
 	switch codec {
@@ -430,10 +917,65 @@ func compress(codec sch.CompressionCodec, buf *bytebufferpool.ByteBuffer, vals [
 		}
 
 		vals = buf.Bytes()
+	case sch.CompressionCodec_ZSTD:
+		enc, err := zstdEncoder(level)
+		if err != nil {
+			return l, 0, vals, err
+		}
+		buf.B = enc.EncodeAll(vals, buf.B[:0])
+		vals = buf.B
+	case sch.CompressionCodec_LZ4_RAW:
+		if v := lz4.CompressBlockBound(len(vals)); v > cap(buf.B) {
+			buf.B = make([]byte, v)
+		} else {
+			buf.B = buf.B[:v]
+		}
+
+		// the compressed block always fits in buf
+		// because it's CompressBlockBound bytes
+		c := lz4Compressors.Get().(*lz4.Compressor)
+		n, err := c.CompressBlock(vals, buf.B)
+		lz4Compressors.Put(c)
+		if err != nil {
+			return l, 0, vals, err
+		}
+		vals = buf.B[:n]
 	}
 	return l, len(vals), vals, err
 }
 
+var (
+	// zstd encoders and decoders are safe for concurrent
+	// use with EncodeAll/DecodeAll, so they are shared.
+	zstdDecoder, _ = zstd.NewReader(nil)
+	zstdEncoders   = map[zstd.EncoderLevel]*zstd.Encoder{}
+	zstdLock       sync.Mutex
+
+	// lz4 compressors aren't safe for concurrent use
+	lz4Compressors = sync.Pool{New: func() interface{} { return new(lz4.Compressor) }}
+)
+
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	l := zstd.SpeedDefault
+	if level > 0 {
+		l = zstd.EncoderLevelFromZstd(level)
+	}
+
+	zstdLock.Lock()
+	defer zstdLock.Unlock()
+
+	if enc, ok := zstdEncoders[l]; ok {
+		return enc, nil
+	}
+
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(l))
+	if err != nil {
+		return nil, err
+	}
+	zstdEncoders[l] = enc
+	return enc, nil
+}
+
 // writeLevels writes vals to w as RLE/bitpack encoded data
 func writeLevels(w io.Writer, levels []uint8, width int32) error {
 	enc, _ := rle.New(width, len(levels)) //TODO: len(levels) is probably too big.  Chop it down a bit?
@@ -455,3 +997,107 @@ func readLevels(in io.Reader, width int32) ([]uint8, int, error) {
 
 	return out, n, nil
 }
+
+// writeBitPackedLevels writes levels to w with the deprecated BIT_PACKED
+// encoding, which packs them from the most significant bit of each byte
+// to the least (the opposite of the RLE/bit-packing hybrid) and, like a
+// v2 data page, doesn't start with their length.  bitpack.Pack packs from
+// the least significant bit so the bits of each level are reversed before
+// they are packed and the bits of each byte are reversed after.
+func writeBitPackedLevels(w io.Writer, levels []uint8, width int32) error {
+	out := make([]byte, 0, (len(levels)+7)/8*int(width))
+	group := make([]uint32, 8)
+	for i := 0; i < len(levels); i += 8 {
+		for j := range group {
+			group[j] = 0
+			if i+j < len(levels) {
+				group[j] = uint32(bits.Reverse8(levels[i+j]) >> (8 - width))
+			}
+		}
+		out = bitpack.Pack(out, int(width), group)
+	}
+
+	for i, b := range out {
+		out[i] = bits.Reverse8(b)
+	}
+	_, err := w.Write(out[:bitPackedLen(len(levels), width)])
+	return err
+}
+
+// decodeBitPackedLevels decodes the first n BIT_PACKED encoded levels of
+// data (see writeBitPackedLevels) and returns them with the number of
+// bytes they took up.
+func decodeBitPackedLevels(data []byte, width int32, n int) ([]uint8, int, error) {
+	l := bitPackedLen(n, width)
+	if len(data) < l {
+		return nil, 0, fmt.Errorf("%d levels need %d bytes, found %d", n, l, len(data))
+	}
+
+	// bitpack.Unpack needs all of the bytes of the last group of 8
+	groups := make([]byte, (n+7)/8*int(width))
+	for i, b := range data[:l] {
+		groups[i] = bits.Reverse8(b)
+	}
+
+	out := make([]uint8, 0, n+7)
+	for i := 0; i < len(groups); i += int(width) {
+		for _, v := range bitpack.Unpack(int(width), groups[i:]) {
+			out = append(out, bits.Reverse8(uint8(v))>>(8-width))
+		}
+	}
+	return out[:n], l, nil
+}
+
+// bitPackedLen returns the number of bytes that n BIT_PACKED
+// encoded levels of the given width take up.
+func bitPackedLen(n int, width int32) int {
+	return (n*int(width) + 7) / 8
+}
+
+// encodeLevels RLE/bitpack encodes the levels of a v2 data page (which,
+// unlike a v1 data page, doesn't start with their length).
+func encodeLevels(levels []uint8, max uint8) []byte {
+	vals := make([]uint32, len(levels))
+	for i, l := range levels {
+		vals[i] = uint32(l)
+	}
+	return rle.Encode(bits.Len(uint(max)), vals)
+}
+
+// decodeLevels decodes the n RLE/bitpack encoded levels of a v2 data page.
+func decodeLevels(data []byte, width int32, n int) ([]uint8, error) {
+	vals, _, err := rle.Decode(data, int(width), n)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]uint8, len(vals))
+	for i, v := range vals {
+		out[i] = uint8(v)
+	}
+	return out, nil
+}
+
+// writeDataPageV2 writes a DATA_PAGE_V2 page.  Only its values are
+// compressed so the levels can be read without decompressing them.
+func writeDataPageV2(w io.Writer, meta *Metadata, pth []string, reps, defs, vals []byte, count, nulls, rows int, comp sch.CompressionCodec, level int, enc sch.Encoding, stats Stats) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	l, cl, vals, err := compress(comp, level, buf, vals)
+	if err != nil {
+		return err
+	}
+
+	n := len(reps) + len(defs)
+	if err := meta.WritePageHeaderV2(w, pth, l+n, cl+n, count, nulls, rows, int64(len(defs)), int64(len(reps)), comp, enc, stats, reps, defs, vals); err != nil {
+		return err
+	}
+
+	for _, b := range [][]byte{reps, defs, vals} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
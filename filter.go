@@ -0,0 +1,299 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// Operator is how a Filter compares a column's values to its Value.
+type Operator int
+
+const (
+	Equal Operator = iota
+	NotEqual
+	Less
+	LessOrEqual
+	Greater
+	GreaterOrEqual
+)
+
+func (o Operator) String() string {
+	switch o {
+	case Equal:
+		return "="
+	case NotEqual:
+		return "!="
+	case Less:
+		return "<"
+	case LessOrEqual:
+		return "<="
+	case Greater:
+		return ">"
+	case GreaterOrEqual:
+		return ">="
+	default:
+		return fmt.Sprintf("Operator(%d)", int(o))
+	}
+}
+
+// Filter is used to skip the row groups that can't have any rows whose
+// Column matches Value.  Only the min and max statistics of each column
+// chunk are used, so a row group that isn't skipped can still have rows
+// that don't match.
+type Filter struct {
+	Column string
+	Op     Operator
+	Value  interface{}
+}
+
+// Skip returns true if the statistics of rg show that none of its rows
+// can match one of the filters.  A column chunk without min and max
+// statistics, or whose column's order isn't declared by the file's
+// column orders, is never skipped.
+func (m *Metadata) Skip(rg RowGroup, filters ...Filter) (bool, error) {
+	for _, f := range filters {
+		se, ok := m.schema.lookup[f.Column]
+		if !ok {
+			return false, fmt.Errorf("unknown filter column: %s", f.Column)
+		}
+
+		v, err := plainValue(se, f.Value)
+		if err != nil {
+			return false, fmt.Errorf("filter on column %s: %s", f.Column, err)
+		}
+
+		if !m.ordered(f.Column) {
+			continue
+		}
+
+		for _, ch := range rg.Columns() {
+			if strings.Join(ch.MetaData.PathInSchema, ".") != f.Column {
+				continue
+			}
+
+			st := ch.MetaData.Statistics
+			if st == nil || st.MinValue == nil || st.MaxValue == nil {
+				continue
+			}
+
+			if skip(se, f.Op, st.MinValue, st.MaxValue, v) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// ordered returns true if the column orders of the file read by
+// ReadFooter say that the min and max statistics of column are in the
+// order of its type (and its type has one), which are the only ones
+// that can be trusted.
+func (m *Metadata) ordered(column string) bool {
+	if m.metadata == nil || undefinedOrder(m.schema.lookup[column]) {
+		return false
+	}
+
+	i, ok := fileColumns(m.metadata.Schema)[column]
+	if !ok || i >= len(m.metadata.ColumnOrders) {
+		return false
+	}
+	return m.metadata.ColumnOrders[i].IsSetTYPE_ORDER()
+}
+
+// skip returns true if no value between min and max can satisfy
+// the comparison with v.  A NaN can't be ordered, so nothing is
+// skipped when min, max or v is one.
+func skip(se sch.SchemaElement, op Operator, min, max, v []byte) bool {
+	if isNaN(se, min) || isNaN(se, max) || isNaN(se, v) {
+		return false
+	}
+
+	switch op {
+	case Equal:
+		return less(se, v, min) || less(se, max, v)
+	case NotEqual:
+		return !less(se, min, v) && !less(se, v, max)
+	case Less:
+		return !less(se, min, v)
+	case LessOrEqual:
+		return less(se, v, min)
+	case Greater:
+		return !less(se, v, max)
+	case GreaterOrEqual:
+		return less(se, max, v)
+	default:
+		return false
+	}
+}
+
+// isNaN returns true if b is a plain encoded NaN
+// of the floating point column described by se.
+func isNaN(se sch.SchemaElement, b []byte) bool {
+	switch {
+	case se.GetType() == sch.Type_FLOAT && len(b) == 4:
+		return math.IsNaN(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+	case se.GetType() == sch.Type_DOUBLE && len(b) == 8:
+		return math.IsNaN(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	case se.LogicalType != nil && se.LogicalType.FLOAT16 != nil && len(b) == 2:
+		return Float16IsNaN(binary.LittleEndian.Uint16(b))
+	default:
+		return false
+	}
+}
+
+// plainValue plain encodes v the same way as the
+// statistics of the column described by se.
+func plainValue(se sch.SchemaElement, v interface{}) ([]byte, error) {
+	if t, ok := v.(time.Time); ok {
+		return timeValue(se, t)
+	}
+
+	if d, ok := v.(time.Duration); ok && se.LogicalType != nil && se.LogicalType.TIME != nil {
+		return timeOfDayValue(se, d)
+	}
+
+	// the value of a FLOAT16 column is the bits of the half precision float
+	if u, ok := v.(uint16); ok && se.LogicalType != nil && se.LogicalType.FLOAT16 != nil {
+		b := make([]byte, 2)
+		binary.LittleEndian.PutUint16(b, u)
+		return b, nil
+	}
+
+	if d, ok := v.(*big.Int); ok && se.GetConvertedType() == sch.ConvertedType_DECIMAL && se.GetType() == sch.Type_FIXED_LEN_BYTE_ARRAY {
+		return DecimalBytes(d, int(se.GetTypeLength()))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch se.GetType() {
+	case sch.Type_INT32:
+		i, ok := intValue(rv, 32, isUnsigned(se))
+		if !ok {
+			break
+		}
+		if i == nil {
+			return nil, fmt.Errorf("%v is out of range for a %s column", v, se.GetType())
+		}
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(*i))
+		return b, nil
+	case sch.Type_INT64:
+		i, ok := intValue(rv, 64, isUnsigned(se))
+		if !ok {
+			break
+		}
+		if i == nil {
+			return nil, fmt.Errorf("%v is out of range for a %s column", v, se.GetType())
+		}
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, *i)
+		return b, nil
+	case sch.Type_FLOAT:
+		if rv.Kind() != reflect.Float32 && rv.Kind() != reflect.Float64 {
+			break
+		}
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, math.Float32bits(float32(rv.Float())))
+		return b, nil
+	case sch.Type_DOUBLE:
+		if rv.Kind() != reflect.Float32 && rv.Kind() != reflect.Float64 {
+			break
+		}
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, math.Float64bits(rv.Float()))
+		return b, nil
+	case sch.Type_BYTE_ARRAY, sch.Type_FIXED_LEN_BYTE_ARRAY:
+		switch x := v.(type) {
+		case string:
+			return []byte(x), nil
+		case []byte:
+			return x, nil
+		}
+		if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return b, nil
+		}
+	}
+
+	return nil, fmt.Errorf("can't compare a %T to a %s column", v, se.GetType())
+}
+
+// timeOfDayValue plain encodes d (the time since
+// midnight) in the unit of a TIME column.
+func timeOfDayValue(se sch.SchemaElement, d time.Duration) ([]byte, error) {
+	unit := se.LogicalType.TIME.Unit
+	switch {
+	case unit == nil:
+	case unit.MILLIS != nil:
+		return plainValue(se, int32(d/time.Millisecond))
+	case unit.MICROS != nil:
+		return plainValue(se, int64(d/time.Microsecond))
+	case unit.NANOS != nil:
+		return plainValue(se, int64(d))
+	}
+	return nil, fmt.Errorf("can't compare a time.Duration to a TIME column without a unit")
+}
+
+// intValue returns the bits of the integer rv as the value of a column
+// whose type is that many bits and is unsigned or not, or nil if rv is
+// out of its range.  It returns false if rv isn't an integer.
+func intValue(rv reflect.Value, bits uint, unsigned bool) (*uint64, bool) {
+	var min int64
+	max := uint64(1)<<(bits-1) - 1
+	if unsigned {
+		max = max<<1 | 1
+	} else {
+		min = -1 << (bits - 1)
+	}
+
+	var out uint64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		if i < min || (i > 0 && uint64(i) > max) {
+			return nil, true
+		}
+		out = uint64(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		out = rv.Uint()
+		if out > max {
+			return nil, true
+		}
+	default:
+		return nil, false
+	}
+	return &out, true
+}
+
+func timeValue(se sch.SchemaElement, t time.Time) ([]byte, error) {
+	var ct sch.ConvertedType = -1
+	if se.ConvertedType != nil {
+		ct = *se.ConvertedType
+	}
+
+	if lt := se.LogicalType; lt != nil && lt.TIMESTAMP != nil && lt.TIMESTAMP.Unit != nil && lt.TIMESTAMP.Unit.NANOS != nil {
+		n, err := TimestampNanos(t)
+		if err != nil {
+			return nil, err
+		}
+		return plainValue(se, n)
+	}
+
+	switch ct {
+	case sch.ConvertedType_TIMESTAMP_MILLIS:
+		return plainValue(se, t.Unix()*1000+int64(t.Nanosecond())/int64(time.Millisecond))
+	case sch.ConvertedType_TIMESTAMP_MICROS:
+		return plainValue(se, t.Unix()*1000000+int64(t.Nanosecond())/int64(time.Microsecond))
+	case sch.ConvertedType_DATE:
+		return plainValue(se, int32(t.Truncate(24*time.Hour).Unix()/86400))
+	default:
+		return nil, fmt.Errorf("can't compare a time.Time to a %s column", se.GetType())
+	}
+}
@@ -0,0 +1,25 @@
+package parquet
+
+// Float16Less reports whether the half precision float whose bits are a
+// comes before the one whose bits are b (a FLOAT16 column stores the bits
+// of each value).  The floats are in IEEE 754's total order, so -0 comes
+// before +0 and a NaN comes after +Inf (or before -Inf if its sign bit is
+// set).
+func Float16Less(a, b uint16) bool {
+	return float16Key(a) < float16Key(b)
+}
+
+// float16Key maps the bits of a half precision float to
+// a uint16 that is in the same order as the floats.
+func float16Key(x uint16) uint16 {
+	if x&0x8000 != 0 {
+		return ^x
+	}
+	return x | 0x8000
+}
+
+// Float16IsNaN reports whether the half precision float
+// whose bits are x is a NaN.
+func Float16IsNaN(x uint16) bool {
+	return x&0x7c00 == 0x7c00 && x&0x03ff != 0
+}
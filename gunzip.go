@@ -0,0 +1,50 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Gunzip reads all of r, a whole parquet file that was gzipped (like a
+// .parquet.gz file), and returns the parquet file as a bytes.Reader.  A
+// gzip stream can't be seeked, so the whole file is decompressed into
+// memory first.  The bytes.Reader is an io.ReadSeeker and an io.ReaderAt,
+// so it can be read like any other file:
+//
+//	br, err := parquet.Gunzip(f)
+//	r, err := NewParquetReader(br)
+//
+// It returns an error if the decompressed file doesn't start and end
+// with PAR1 or its footer length doesn't fit in the file.
+func Gunzip(r io.Reader) (*bytes.Reader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read gzip header: %s", err)
+	}
+	defer zr.Close()
+
+	buf, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to gunzip: %s", err)
+	}
+
+	if len(buf) < 12 || !bytes.HasPrefix(buf, []byte("PAR1")) || !bytes.HasSuffix(buf, []byte("PAR1")) {
+		return nil, fmt.Errorf("the gzipped file isn't a parquet file")
+	}
+
+	br := bytes.NewReader(buf)
+	size, err := getMetaDataSize(br)
+	if err != nil {
+		return nil, err
+	}
+
+	if size+12 > len(buf) {
+		return nil, fmt.Errorf("the footer of the gzipped file is %d bytes but the file is only %d bytes", size, len(buf))
+	}
+
+	_, err = br.Seek(0, io.SeekStart)
+	return br, err
+}
@@ -0,0 +1,34 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// julianUnixEpoch is the Julian day of 1970-01-01.
+const julianUnixEpoch = 2440588
+
+// Int96Time returns the time.Time of a (deprecated) INT96 timestamp, which
+// older versions of Spark and Hive write: the nanoseconds since midnight
+// as a little-endian 64 bit integer followed by the Julian day as a
+// little-endian 32 bit integer.  The time is in UTC.
+func Int96Time(b [12]byte) time.Time {
+	nanos := int64(binary.LittleEndian.Uint64(b[:8]))
+	days := int64(binary.LittleEndian.Uint32(b[8:])) - julianUnixEpoch
+	return time.Unix(days*86400, nanos).UTC()
+}
+
+// GetInt96Times reads n plain encoded INT96 timestamps (see Int96Time).
+func GetInt96Times(r io.Reader, n int) ([]time.Time, error) {
+	out := make([]time.Time, n)
+	var b [12]byte
+	for i := range out {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, fmt.Errorf("expected %d INT96 values, got %d, err: %s", n, i, err)
+		}
+		out[i] = Int96Time(b)
+	}
+	return out, nil
+}
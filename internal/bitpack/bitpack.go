@@ -2,9 +2,9 @@ package bitpack
 
 // Code generated by github.com/parsyl/parquet.  DO NOT EDIT.
 
-const MaxSize = 4
+const MaxSize = 32
 
-func Pack(b []byte, width int, vals []uint8) []byte {
+func Pack(b []byte, width int, vals []uint32) []byte {
 	switch width {
 	case 1:
 		return pack1(b, vals)
@@ -14,66 +14,932 @@ func Pack(b []byte, width int, vals []uint8) []byte {
 		return pack3(b, vals)
 	case 4:
 		return pack4(b, vals)
+	case 5:
+		return pack5(b, vals)
+	case 6:
+		return pack6(b, vals)
+	case 7:
+		return pack7(b, vals)
+	case 8:
+		return pack8(b, vals)
+	case 9:
+		return pack9(b, vals)
+	case 10:
+		return pack10(b, vals)
+	case 11:
+		return pack11(b, vals)
+	case 12:
+		return pack12(b, vals)
+	case 13:
+		return pack13(b, vals)
+	case 14:
+		return pack14(b, vals)
+	case 15:
+		return pack15(b, vals)
+	case 16:
+		return pack16(b, vals)
+	case 17:
+		return pack17(b, vals)
+	case 18:
+		return pack18(b, vals)
+	case 19:
+		return pack19(b, vals)
+	case 20:
+		return pack20(b, vals)
+	case 21:
+		return pack21(b, vals)
+	case 22:
+		return pack22(b, vals)
+	case 23:
+		return pack23(b, vals)
+	case 24:
+		return pack24(b, vals)
+	case 25:
+		return pack25(b, vals)
+	case 26:
+		return pack26(b, vals)
+	case 27:
+		return pack27(b, vals)
+	case 28:
+		return pack28(b, vals)
+	case 29:
+		return pack29(b, vals)
+	case 30:
+		return pack30(b, vals)
+	case 31:
+		return pack31(b, vals)
+	case 32:
+		return pack32(b, vals)
 	default:
 		return b
 	}
 }
 
-func pack1(b []byte, vals []uint8) []byte {
+func pack1(b []byte, vals []uint32) []byte {
 	return append(b,
-		(byte((vals[0]&1)<<0) |
-			byte((vals[1]&1)<<1) |
-			byte((vals[2]&1)<<2) |
-			byte((vals[3]&1)<<3) |
-			byte((vals[4]&1)<<4) |
-			byte((vals[5]&1)<<5) |
-			byte((vals[6]&1)<<6) |
-			byte((vals[7]&1)<<7)),
+		(byte((vals[0]>>0)&1)<<0 |
+			byte((vals[1]>>0)&1)<<1 |
+			byte((vals[2]>>0)&1)<<2 |
+			byte((vals[3]>>0)&1)<<3 |
+			byte((vals[4]>>0)&1)<<4 |
+			byte((vals[5]>>0)&1)<<5 |
+			byte((vals[6]>>0)&1)<<6 |
+			byte((vals[7]>>0)&1)<<7),
 	)
 }
 
-func pack2(b []byte, vals []uint8) []byte {
+func pack2(b []byte, vals []uint32) []byte {
 	return append(b,
-		(byte((vals[0]&3)<<0) |
-			byte((vals[1]&3)<<2) |
-			byte((vals[2]&3)<<4) |
-			byte((vals[3]&3)<<6)),
-		(byte((vals[4]&3)<<0) |
-			byte((vals[5]&3)<<2) |
-			byte((vals[6]&3)<<4) |
-			byte((vals[7]&3)<<6)),
+		(byte((vals[0]>>0)&3)<<0 |
+			byte((vals[1]>>0)&3)<<2 |
+			byte((vals[2]>>0)&3)<<4 |
+			byte((vals[3]>>0)&3)<<6),
+		(byte((vals[4]>>0)&3)<<0 |
+			byte((vals[5]>>0)&3)<<2 |
+			byte((vals[6]>>0)&3)<<4 |
+			byte((vals[7]>>0)&3)<<6),
 	)
 }
 
-func pack3(b []byte, vals []uint8) []byte {
+func pack3(b []byte, vals []uint32) []byte {
 	return append(b,
-		(byte((vals[0]&7)<<0) |
-			byte((vals[1]&7)<<3) |
-			byte((vals[2]&3)<<6)),
-		(byte((vals[2]&4)>>2) |
-			byte((vals[3]&7)<<1) |
-			byte((vals[4]&7)<<4) |
-			byte((vals[5]&1)<<7)),
-		(byte((vals[5]&6)>>1) |
-			byte((vals[6]&7)<<2) |
-			byte((vals[7]&7)<<5)),
+		(byte((vals[0]>>0)&7)<<0 |
+			byte((vals[1]>>0)&7)<<3 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&1)<<0 |
+			byte((vals[3]>>0)&7)<<1 |
+			byte((vals[4]>>0)&7)<<4 |
+			byte((vals[5]>>0)&1)<<7),
+		(byte((vals[5]>>1)&3)<<0 |
+			byte((vals[6]>>0)&7)<<2 |
+			byte((vals[7]>>0)&7)<<5),
 	)
 }
 
-func pack4(b []byte, vals []uint8) []byte {
+func pack4(b []byte, vals []uint32) []byte {
 	return append(b,
-		(byte((vals[0]&15)<<0) |
-			byte((vals[1]&15)<<4)),
-		(byte((vals[2]&15)<<0) |
-			byte((vals[3]&15)<<4)),
-		(byte((vals[4]&15)<<0) |
-			byte((vals[5]&15)<<4)),
-		(byte((vals[6]&15)<<0) |
-			byte((vals[7]&15)<<4)),
+		(byte((vals[0]>>0)&15)<<0 |
+			byte((vals[1]>>0)&15)<<4),
+		(byte((vals[2]>>0)&15)<<0 |
+			byte((vals[3]>>0)&15)<<4),
+		(byte((vals[4]>>0)&15)<<0 |
+			byte((vals[5]>>0)&15)<<4),
+		(byte((vals[6]>>0)&15)<<0 |
+			byte((vals[7]>>0)&15)<<4),
 	)
 }
 
-func Unpack(width int, vals []byte) []uint8 {
+func pack5(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&31)<<0 |
+			byte((vals[1]>>0)&7)<<5),
+		(byte((vals[1]>>3)&3)<<0 |
+			byte((vals[2]>>0)&31)<<2 |
+			byte((vals[3]>>0)&1)<<7),
+		(byte((vals[3]>>1)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&1)<<0 |
+			byte((vals[5]>>0)&31)<<1 |
+			byte((vals[6]>>0)&3)<<6),
+		(byte((vals[6]>>2)&7)<<0 |
+			byte((vals[7]>>0)&31)<<3),
+	)
+}
+
+func pack6(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&63)<<0 |
+			byte((vals[1]>>0)&3)<<6),
+		(byte((vals[1]>>2)&15)<<0 |
+			byte((vals[2]>>0)&15)<<4),
+		(byte((vals[2]>>4)&3)<<0 |
+			byte((vals[3]>>0)&63)<<2),
+		(byte((vals[4]>>0)&63)<<0 |
+			byte((vals[5]>>0)&3)<<6),
+		(byte((vals[5]>>2)&15)<<0 |
+			byte((vals[6]>>0)&15)<<4),
+		(byte((vals[6]>>4)&3)<<0 |
+			byte((vals[7]>>0)&63)<<2),
+	)
+}
+
+func pack7(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&127)<<0 |
+			byte((vals[1]>>0)&1)<<7),
+		(byte((vals[1]>>1)&63)<<0 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&31)<<0 |
+			byte((vals[3]>>0)&7)<<5),
+		(byte((vals[3]>>3)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&7)<<0 |
+			byte((vals[5]>>0)&31)<<3),
+		(byte((vals[5]>>5)&3)<<0 |
+			byte((vals[6]>>0)&63)<<2),
+		(byte((vals[6]>>6)&1)<<0 |
+			byte((vals[7]>>0)&127)<<1),
+	)
+}
+
+func pack8(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[1]>>0)&255) << 0),
+		(byte((vals[2]>>0)&255) << 0),
+		(byte((vals[3]>>0)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[5]>>0)&255) << 0),
+		(byte((vals[6]>>0)&255) << 0),
+		(byte((vals[7]>>0)&255) << 0),
+	)
+}
+
+func pack9(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&1)<<0 |
+			byte((vals[1]>>0)&127)<<1),
+		(byte((vals[1]>>7)&3)<<0 |
+			byte((vals[2]>>0)&63)<<2),
+		(byte((vals[2]>>6)&7)<<0 |
+			byte((vals[3]>>0)&31)<<3),
+		(byte((vals[3]>>5)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&31)<<0 |
+			byte((vals[5]>>0)&7)<<5),
+		(byte((vals[5]>>3)&63)<<0 |
+			byte((vals[6]>>0)&3)<<6),
+		(byte((vals[6]>>2)&127)<<0 |
+			byte((vals[7]>>0)&1)<<7),
+		(byte((vals[7]>>1)&255) << 0),
+	)
+}
+
+func pack10(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&3)<<0 |
+			byte((vals[1]>>0)&63)<<2),
+		(byte((vals[1]>>6)&15)<<0 |
+			byte((vals[2]>>0)&15)<<4),
+		(byte((vals[2]>>4)&63)<<0 |
+			byte((vals[3]>>0)&3)<<6),
+		(byte((vals[3]>>2)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&3)<<0 |
+			byte((vals[5]>>0)&63)<<2),
+		(byte((vals[5]>>6)&15)<<0 |
+			byte((vals[6]>>0)&15)<<4),
+		(byte((vals[6]>>4)&63)<<0 |
+			byte((vals[7]>>0)&3)<<6),
+		(byte((vals[7]>>2)&255) << 0),
+	)
+}
+
+func pack11(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&7)<<0 |
+			byte((vals[1]>>0)&31)<<3),
+		(byte((vals[1]>>5)&63)<<0 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&255) << 0),
+		(byte((vals[2]>>10)&1)<<0 |
+			byte((vals[3]>>0)&127)<<1),
+		(byte((vals[3]>>7)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&127)<<0 |
+			byte((vals[5]>>0)&1)<<7),
+		(byte((vals[5]>>1)&255) << 0),
+		(byte((vals[5]>>9)&3)<<0 |
+			byte((vals[6]>>0)&63)<<2),
+		(byte((vals[6]>>6)&31)<<0 |
+			byte((vals[7]>>0)&7)<<5),
+		(byte((vals[7]>>3)&255) << 0),
+	)
+}
+
+func pack12(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&15)<<0 |
+			byte((vals[1]>>0)&15)<<4),
+		(byte((vals[1]>>4)&255) << 0),
+		(byte((vals[2]>>0)&255) << 0),
+		(byte((vals[2]>>8)&15)<<0 |
+			byte((vals[3]>>0)&15)<<4),
+		(byte((vals[3]>>4)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&15)<<0 |
+			byte((vals[5]>>0)&15)<<4),
+		(byte((vals[5]>>4)&255) << 0),
+		(byte((vals[6]>>0)&255) << 0),
+		(byte((vals[6]>>8)&15)<<0 |
+			byte((vals[7]>>0)&15)<<4),
+		(byte((vals[7]>>4)&255) << 0),
+	)
+}
+
+func pack13(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&31)<<0 |
+			byte((vals[1]>>0)&7)<<5),
+		(byte((vals[1]>>3)&255) << 0),
+		(byte((vals[1]>>11)&3)<<0 |
+			byte((vals[2]>>0)&63)<<2),
+		(byte((vals[2]>>6)&127)<<0 |
+			byte((vals[3]>>0)&1)<<7),
+		(byte((vals[3]>>1)&255) << 0),
+		(byte((vals[3]>>9)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&1)<<0 |
+			byte((vals[5]>>0)&127)<<1),
+		(byte((vals[5]>>7)&63)<<0 |
+			byte((vals[6]>>0)&3)<<6),
+		(byte((vals[6]>>2)&255) << 0),
+		(byte((vals[6]>>10)&7)<<0 |
+			byte((vals[7]>>0)&31)<<3),
+		(byte((vals[7]>>5)&255) << 0),
+	)
+}
+
+func pack14(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&63)<<0 |
+			byte((vals[1]>>0)&3)<<6),
+		(byte((vals[1]>>2)&255) << 0),
+		(byte((vals[1]>>10)&15)<<0 |
+			byte((vals[2]>>0)&15)<<4),
+		(byte((vals[2]>>4)&255) << 0),
+		(byte((vals[2]>>12)&3)<<0 |
+			byte((vals[3]>>0)&63)<<2),
+		(byte((vals[3]>>6)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&63)<<0 |
+			byte((vals[5]>>0)&3)<<6),
+		(byte((vals[5]>>2)&255) << 0),
+		(byte((vals[5]>>10)&15)<<0 |
+			byte((vals[6]>>0)&15)<<4),
+		(byte((vals[6]>>4)&255) << 0),
+		(byte((vals[6]>>12)&3)<<0 |
+			byte((vals[7]>>0)&63)<<2),
+		(byte((vals[7]>>6)&255) << 0),
+	)
+}
+
+func pack15(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&127)<<0 |
+			byte((vals[1]>>0)&1)<<7),
+		(byte((vals[1]>>1)&255) << 0),
+		(byte((vals[1]>>9)&63)<<0 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&255) << 0),
+		(byte((vals[2]>>10)&31)<<0 |
+			byte((vals[3]>>0)&7)<<5),
+		(byte((vals[3]>>3)&255) << 0),
+		(byte((vals[3]>>11)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&7)<<0 |
+			byte((vals[5]>>0)&31)<<3),
+		(byte((vals[5]>>5)&255) << 0),
+		(byte((vals[5]>>13)&3)<<0 |
+			byte((vals[6]>>0)&63)<<2),
+		(byte((vals[6]>>6)&255) << 0),
+		(byte((vals[6]>>14)&1)<<0 |
+			byte((vals[7]>>0)&127)<<1),
+		(byte((vals[7]>>7)&255) << 0),
+	)
+}
+
+func pack16(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[1]>>0)&255) << 0),
+		(byte((vals[1]>>8)&255) << 0),
+		(byte((vals[2]>>0)&255) << 0),
+		(byte((vals[2]>>8)&255) << 0),
+		(byte((vals[3]>>0)&255) << 0),
+		(byte((vals[3]>>8)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[5]>>0)&255) << 0),
+		(byte((vals[5]>>8)&255) << 0),
+		(byte((vals[6]>>0)&255) << 0),
+		(byte((vals[6]>>8)&255) << 0),
+		(byte((vals[7]>>0)&255) << 0),
+		(byte((vals[7]>>8)&255) << 0),
+	)
+}
+
+func pack17(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&1)<<0 |
+			byte((vals[1]>>0)&127)<<1),
+		(byte((vals[1]>>7)&255) << 0),
+		(byte((vals[1]>>15)&3)<<0 |
+			byte((vals[2]>>0)&63)<<2),
+		(byte((vals[2]>>6)&255) << 0),
+		(byte((vals[2]>>14)&7)<<0 |
+			byte((vals[3]>>0)&31)<<3),
+		(byte((vals[3]>>5)&255) << 0),
+		(byte((vals[3]>>13)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&31)<<0 |
+			byte((vals[5]>>0)&7)<<5),
+		(byte((vals[5]>>3)&255) << 0),
+		(byte((vals[5]>>11)&63)<<0 |
+			byte((vals[6]>>0)&3)<<6),
+		(byte((vals[6]>>2)&255) << 0),
+		(byte((vals[6]>>10)&127)<<0 |
+			byte((vals[7]>>0)&1)<<7),
+		(byte((vals[7]>>1)&255) << 0),
+		(byte((vals[7]>>9)&255) << 0),
+	)
+}
+
+func pack18(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&3)<<0 |
+			byte((vals[1]>>0)&63)<<2),
+		(byte((vals[1]>>6)&255) << 0),
+		(byte((vals[1]>>14)&15)<<0 |
+			byte((vals[2]>>0)&15)<<4),
+		(byte((vals[2]>>4)&255) << 0),
+		(byte((vals[2]>>12)&63)<<0 |
+			byte((vals[3]>>0)&3)<<6),
+		(byte((vals[3]>>2)&255) << 0),
+		(byte((vals[3]>>10)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&3)<<0 |
+			byte((vals[5]>>0)&63)<<2),
+		(byte((vals[5]>>6)&255) << 0),
+		(byte((vals[5]>>14)&15)<<0 |
+			byte((vals[6]>>0)&15)<<4),
+		(byte((vals[6]>>4)&255) << 0),
+		(byte((vals[6]>>12)&63)<<0 |
+			byte((vals[7]>>0)&3)<<6),
+		(byte((vals[7]>>2)&255) << 0),
+		(byte((vals[7]>>10)&255) << 0),
+	)
+}
+
+func pack19(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&7)<<0 |
+			byte((vals[1]>>0)&31)<<3),
+		(byte((vals[1]>>5)&255) << 0),
+		(byte((vals[1]>>13)&63)<<0 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&255) << 0),
+		(byte((vals[2]>>10)&255) << 0),
+		(byte((vals[2]>>18)&1)<<0 |
+			byte((vals[3]>>0)&127)<<1),
+		(byte((vals[3]>>7)&255) << 0),
+		(byte((vals[3]>>15)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&127)<<0 |
+			byte((vals[5]>>0)&1)<<7),
+		(byte((vals[5]>>1)&255) << 0),
+		(byte((vals[5]>>9)&255) << 0),
+		(byte((vals[5]>>17)&3)<<0 |
+			byte((vals[6]>>0)&63)<<2),
+		(byte((vals[6]>>6)&255) << 0),
+		(byte((vals[6]>>14)&31)<<0 |
+			byte((vals[7]>>0)&7)<<5),
+		(byte((vals[7]>>3)&255) << 0),
+		(byte((vals[7]>>11)&255) << 0),
+	)
+}
+
+func pack20(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&15)<<0 |
+			byte((vals[1]>>0)&15)<<4),
+		(byte((vals[1]>>4)&255) << 0),
+		(byte((vals[1]>>12)&255) << 0),
+		(byte((vals[2]>>0)&255) << 0),
+		(byte((vals[2]>>8)&255) << 0),
+		(byte((vals[2]>>16)&15)<<0 |
+			byte((vals[3]>>0)&15)<<4),
+		(byte((vals[3]>>4)&255) << 0),
+		(byte((vals[3]>>12)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&15)<<0 |
+			byte((vals[5]>>0)&15)<<4),
+		(byte((vals[5]>>4)&255) << 0),
+		(byte((vals[5]>>12)&255) << 0),
+		(byte((vals[6]>>0)&255) << 0),
+		(byte((vals[6]>>8)&255) << 0),
+		(byte((vals[6]>>16)&15)<<0 |
+			byte((vals[7]>>0)&15)<<4),
+		(byte((vals[7]>>4)&255) << 0),
+		(byte((vals[7]>>12)&255) << 0),
+	)
+}
+
+func pack21(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&31)<<0 |
+			byte((vals[1]>>0)&7)<<5),
+		(byte((vals[1]>>3)&255) << 0),
+		(byte((vals[1]>>11)&255) << 0),
+		(byte((vals[1]>>19)&3)<<0 |
+			byte((vals[2]>>0)&63)<<2),
+		(byte((vals[2]>>6)&255) << 0),
+		(byte((vals[2]>>14)&127)<<0 |
+			byte((vals[3]>>0)&1)<<7),
+		(byte((vals[3]>>1)&255) << 0),
+		(byte((vals[3]>>9)&255) << 0),
+		(byte((vals[3]>>17)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&255) << 0),
+		(byte((vals[4]>>20)&1)<<0 |
+			byte((vals[5]>>0)&127)<<1),
+		(byte((vals[5]>>7)&255) << 0),
+		(byte((vals[5]>>15)&63)<<0 |
+			byte((vals[6]>>0)&3)<<6),
+		(byte((vals[6]>>2)&255) << 0),
+		(byte((vals[6]>>10)&255) << 0),
+		(byte((vals[6]>>18)&7)<<0 |
+			byte((vals[7]>>0)&31)<<3),
+		(byte((vals[7]>>5)&255) << 0),
+		(byte((vals[7]>>13)&255) << 0),
+	)
+}
+
+func pack22(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&63)<<0 |
+			byte((vals[1]>>0)&3)<<6),
+		(byte((vals[1]>>2)&255) << 0),
+		(byte((vals[1]>>10)&255) << 0),
+		(byte((vals[1]>>18)&15)<<0 |
+			byte((vals[2]>>0)&15)<<4),
+		(byte((vals[2]>>4)&255) << 0),
+		(byte((vals[2]>>12)&255) << 0),
+		(byte((vals[2]>>20)&3)<<0 |
+			byte((vals[3]>>0)&63)<<2),
+		(byte((vals[3]>>6)&255) << 0),
+		(byte((vals[3]>>14)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&63)<<0 |
+			byte((vals[5]>>0)&3)<<6),
+		(byte((vals[5]>>2)&255) << 0),
+		(byte((vals[5]>>10)&255) << 0),
+		(byte((vals[5]>>18)&15)<<0 |
+			byte((vals[6]>>0)&15)<<4),
+		(byte((vals[6]>>4)&255) << 0),
+		(byte((vals[6]>>12)&255) << 0),
+		(byte((vals[6]>>20)&3)<<0 |
+			byte((vals[7]>>0)&63)<<2),
+		(byte((vals[7]>>6)&255) << 0),
+		(byte((vals[7]>>14)&255) << 0),
+	)
+}
+
+func pack23(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&127)<<0 |
+			byte((vals[1]>>0)&1)<<7),
+		(byte((vals[1]>>1)&255) << 0),
+		(byte((vals[1]>>9)&255) << 0),
+		(byte((vals[1]>>17)&63)<<0 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&255) << 0),
+		(byte((vals[2]>>10)&255) << 0),
+		(byte((vals[2]>>18)&31)<<0 |
+			byte((vals[3]>>0)&7)<<5),
+		(byte((vals[3]>>3)&255) << 0),
+		(byte((vals[3]>>11)&255) << 0),
+		(byte((vals[3]>>19)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&255) << 0),
+		(byte((vals[4]>>20)&7)<<0 |
+			byte((vals[5]>>0)&31)<<3),
+		(byte((vals[5]>>5)&255) << 0),
+		(byte((vals[5]>>13)&255) << 0),
+		(byte((vals[5]>>21)&3)<<0 |
+			byte((vals[6]>>0)&63)<<2),
+		(byte((vals[6]>>6)&255) << 0),
+		(byte((vals[6]>>14)&255) << 0),
+		(byte((vals[6]>>22)&1)<<0 |
+			byte((vals[7]>>0)&127)<<1),
+		(byte((vals[7]>>7)&255) << 0),
+		(byte((vals[7]>>15)&255) << 0),
+	)
+}
+
+func pack24(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[1]>>0)&255) << 0),
+		(byte((vals[1]>>8)&255) << 0),
+		(byte((vals[1]>>16)&255) << 0),
+		(byte((vals[2]>>0)&255) << 0),
+		(byte((vals[2]>>8)&255) << 0),
+		(byte((vals[2]>>16)&255) << 0),
+		(byte((vals[3]>>0)&255) << 0),
+		(byte((vals[3]>>8)&255) << 0),
+		(byte((vals[3]>>16)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&255) << 0),
+		(byte((vals[5]>>0)&255) << 0),
+		(byte((vals[5]>>8)&255) << 0),
+		(byte((vals[5]>>16)&255) << 0),
+		(byte((vals[6]>>0)&255) << 0),
+		(byte((vals[6]>>8)&255) << 0),
+		(byte((vals[6]>>16)&255) << 0),
+		(byte((vals[7]>>0)&255) << 0),
+		(byte((vals[7]>>8)&255) << 0),
+		(byte((vals[7]>>16)&255) << 0),
+	)
+}
+
+func pack25(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&1)<<0 |
+			byte((vals[1]>>0)&127)<<1),
+		(byte((vals[1]>>7)&255) << 0),
+		(byte((vals[1]>>15)&255) << 0),
+		(byte((vals[1]>>23)&3)<<0 |
+			byte((vals[2]>>0)&63)<<2),
+		(byte((vals[2]>>6)&255) << 0),
+		(byte((vals[2]>>14)&255) << 0),
+		(byte((vals[2]>>22)&7)<<0 |
+			byte((vals[3]>>0)&31)<<3),
+		(byte((vals[3]>>5)&255) << 0),
+		(byte((vals[3]>>13)&255) << 0),
+		(byte((vals[3]>>21)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&255) << 0),
+		(byte((vals[4]>>20)&31)<<0 |
+			byte((vals[5]>>0)&7)<<5),
+		(byte((vals[5]>>3)&255) << 0),
+		(byte((vals[5]>>11)&255) << 0),
+		(byte((vals[5]>>19)&63)<<0 |
+			byte((vals[6]>>0)&3)<<6),
+		(byte((vals[6]>>2)&255) << 0),
+		(byte((vals[6]>>10)&255) << 0),
+		(byte((vals[6]>>18)&127)<<0 |
+			byte((vals[7]>>0)&1)<<7),
+		(byte((vals[7]>>1)&255) << 0),
+		(byte((vals[7]>>9)&255) << 0),
+		(byte((vals[7]>>17)&255) << 0),
+	)
+}
+
+func pack26(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&3)<<0 |
+			byte((vals[1]>>0)&63)<<2),
+		(byte((vals[1]>>6)&255) << 0),
+		(byte((vals[1]>>14)&255) << 0),
+		(byte((vals[1]>>22)&15)<<0 |
+			byte((vals[2]>>0)&15)<<4),
+		(byte((vals[2]>>4)&255) << 0),
+		(byte((vals[2]>>12)&255) << 0),
+		(byte((vals[2]>>20)&63)<<0 |
+			byte((vals[3]>>0)&3)<<6),
+		(byte((vals[3]>>2)&255) << 0),
+		(byte((vals[3]>>10)&255) << 0),
+		(byte((vals[3]>>18)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&255) << 0),
+		(byte((vals[4]>>24)&3)<<0 |
+			byte((vals[5]>>0)&63)<<2),
+		(byte((vals[5]>>6)&255) << 0),
+		(byte((vals[5]>>14)&255) << 0),
+		(byte((vals[5]>>22)&15)<<0 |
+			byte((vals[6]>>0)&15)<<4),
+		(byte((vals[6]>>4)&255) << 0),
+		(byte((vals[6]>>12)&255) << 0),
+		(byte((vals[6]>>20)&63)<<0 |
+			byte((vals[7]>>0)&3)<<6),
+		(byte((vals[7]>>2)&255) << 0),
+		(byte((vals[7]>>10)&255) << 0),
+		(byte((vals[7]>>18)&255) << 0),
+	)
+}
+
+func pack27(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&7)<<0 |
+			byte((vals[1]>>0)&31)<<3),
+		(byte((vals[1]>>5)&255) << 0),
+		(byte((vals[1]>>13)&255) << 0),
+		(byte((vals[1]>>21)&63)<<0 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&255) << 0),
+		(byte((vals[2]>>10)&255) << 0),
+		(byte((vals[2]>>18)&255) << 0),
+		(byte((vals[2]>>26)&1)<<0 |
+			byte((vals[3]>>0)&127)<<1),
+		(byte((vals[3]>>7)&255) << 0),
+		(byte((vals[3]>>15)&255) << 0),
+		(byte((vals[3]>>23)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&255) << 0),
+		(byte((vals[4]>>20)&127)<<0 |
+			byte((vals[5]>>0)&1)<<7),
+		(byte((vals[5]>>1)&255) << 0),
+		(byte((vals[5]>>9)&255) << 0),
+		(byte((vals[5]>>17)&255) << 0),
+		(byte((vals[5]>>25)&3)<<0 |
+			byte((vals[6]>>0)&63)<<2),
+		(byte((vals[6]>>6)&255) << 0),
+		(byte((vals[6]>>14)&255) << 0),
+		(byte((vals[6]>>22)&31)<<0 |
+			byte((vals[7]>>0)&7)<<5),
+		(byte((vals[7]>>3)&255) << 0),
+		(byte((vals[7]>>11)&255) << 0),
+		(byte((vals[7]>>19)&255) << 0),
+	)
+}
+
+func pack28(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&15)<<0 |
+			byte((vals[1]>>0)&15)<<4),
+		(byte((vals[1]>>4)&255) << 0),
+		(byte((vals[1]>>12)&255) << 0),
+		(byte((vals[1]>>20)&255) << 0),
+		(byte((vals[2]>>0)&255) << 0),
+		(byte((vals[2]>>8)&255) << 0),
+		(byte((vals[2]>>16)&255) << 0),
+		(byte((vals[2]>>24)&15)<<0 |
+			byte((vals[3]>>0)&15)<<4),
+		(byte((vals[3]>>4)&255) << 0),
+		(byte((vals[3]>>12)&255) << 0),
+		(byte((vals[3]>>20)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&255) << 0),
+		(byte((vals[4]>>24)&15)<<0 |
+			byte((vals[5]>>0)&15)<<4),
+		(byte((vals[5]>>4)&255) << 0),
+		(byte((vals[5]>>12)&255) << 0),
+		(byte((vals[5]>>20)&255) << 0),
+		(byte((vals[6]>>0)&255) << 0),
+		(byte((vals[6]>>8)&255) << 0),
+		(byte((vals[6]>>16)&255) << 0),
+		(byte((vals[6]>>24)&15)<<0 |
+			byte((vals[7]>>0)&15)<<4),
+		(byte((vals[7]>>4)&255) << 0),
+		(byte((vals[7]>>12)&255) << 0),
+		(byte((vals[7]>>20)&255) << 0),
+	)
+}
+
+func pack29(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&31)<<0 |
+			byte((vals[1]>>0)&7)<<5),
+		(byte((vals[1]>>3)&255) << 0),
+		(byte((vals[1]>>11)&255) << 0),
+		(byte((vals[1]>>19)&255) << 0),
+		(byte((vals[1]>>27)&3)<<0 |
+			byte((vals[2]>>0)&63)<<2),
+		(byte((vals[2]>>6)&255) << 0),
+		(byte((vals[2]>>14)&255) << 0),
+		(byte((vals[2]>>22)&127)<<0 |
+			byte((vals[3]>>0)&1)<<7),
+		(byte((vals[3]>>1)&255) << 0),
+		(byte((vals[3]>>9)&255) << 0),
+		(byte((vals[3]>>17)&255) << 0),
+		(byte((vals[3]>>25)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&255) << 0),
+		(byte((vals[4]>>20)&255) << 0),
+		(byte((vals[4]>>28)&1)<<0 |
+			byte((vals[5]>>0)&127)<<1),
+		(byte((vals[5]>>7)&255) << 0),
+		(byte((vals[5]>>15)&255) << 0),
+		(byte((vals[5]>>23)&63)<<0 |
+			byte((vals[6]>>0)&3)<<6),
+		(byte((vals[6]>>2)&255) << 0),
+		(byte((vals[6]>>10)&255) << 0),
+		(byte((vals[6]>>18)&255) << 0),
+		(byte((vals[6]>>26)&7)<<0 |
+			byte((vals[7]>>0)&31)<<3),
+		(byte((vals[7]>>5)&255) << 0),
+		(byte((vals[7]>>13)&255) << 0),
+		(byte((vals[7]>>21)&255) << 0),
+	)
+}
+
+func pack30(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&63)<<0 |
+			byte((vals[1]>>0)&3)<<6),
+		(byte((vals[1]>>2)&255) << 0),
+		(byte((vals[1]>>10)&255) << 0),
+		(byte((vals[1]>>18)&255) << 0),
+		(byte((vals[1]>>26)&15)<<0 |
+			byte((vals[2]>>0)&15)<<4),
+		(byte((vals[2]>>4)&255) << 0),
+		(byte((vals[2]>>12)&255) << 0),
+		(byte((vals[2]>>20)&255) << 0),
+		(byte((vals[2]>>28)&3)<<0 |
+			byte((vals[3]>>0)&63)<<2),
+		(byte((vals[3]>>6)&255) << 0),
+		(byte((vals[3]>>14)&255) << 0),
+		(byte((vals[3]>>22)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&255) << 0),
+		(byte((vals[4]>>24)&63)<<0 |
+			byte((vals[5]>>0)&3)<<6),
+		(byte((vals[5]>>2)&255) << 0),
+		(byte((vals[5]>>10)&255) << 0),
+		(byte((vals[5]>>18)&255) << 0),
+		(byte((vals[5]>>26)&15)<<0 |
+			byte((vals[6]>>0)&15)<<4),
+		(byte((vals[6]>>4)&255) << 0),
+		(byte((vals[6]>>12)&255) << 0),
+		(byte((vals[6]>>20)&255) << 0),
+		(byte((vals[6]>>28)&3)<<0 |
+			byte((vals[7]>>0)&63)<<2),
+		(byte((vals[7]>>6)&255) << 0),
+		(byte((vals[7]>>14)&255) << 0),
+		(byte((vals[7]>>22)&255) << 0),
+	)
+}
+
+func pack31(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&127)<<0 |
+			byte((vals[1]>>0)&1)<<7),
+		(byte((vals[1]>>1)&255) << 0),
+		(byte((vals[1]>>9)&255) << 0),
+		(byte((vals[1]>>17)&255) << 0),
+		(byte((vals[1]>>25)&63)<<0 |
+			byte((vals[2]>>0)&3)<<6),
+		(byte((vals[2]>>2)&255) << 0),
+		(byte((vals[2]>>10)&255) << 0),
+		(byte((vals[2]>>18)&255) << 0),
+		(byte((vals[2]>>26)&31)<<0 |
+			byte((vals[3]>>0)&7)<<5),
+		(byte((vals[3]>>3)&255) << 0),
+		(byte((vals[3]>>11)&255) << 0),
+		(byte((vals[3]>>19)&255) << 0),
+		(byte((vals[3]>>27)&15)<<0 |
+			byte((vals[4]>>0)&15)<<4),
+		(byte((vals[4]>>4)&255) << 0),
+		(byte((vals[4]>>12)&255) << 0),
+		(byte((vals[4]>>20)&255) << 0),
+		(byte((vals[4]>>28)&7)<<0 |
+			byte((vals[5]>>0)&31)<<3),
+		(byte((vals[5]>>5)&255) << 0),
+		(byte((vals[5]>>13)&255) << 0),
+		(byte((vals[5]>>21)&255) << 0),
+		(byte((vals[5]>>29)&3)<<0 |
+			byte((vals[6]>>0)&63)<<2),
+		(byte((vals[6]>>6)&255) << 0),
+		(byte((vals[6]>>14)&255) << 0),
+		(byte((vals[6]>>22)&255) << 0),
+		(byte((vals[6]>>30)&1)<<0 |
+			byte((vals[7]>>0)&127)<<1),
+		(byte((vals[7]>>7)&255) << 0),
+		(byte((vals[7]>>15)&255) << 0),
+		(byte((vals[7]>>23)&255) << 0),
+	)
+}
+
+func pack32(b []byte, vals []uint32) []byte {
+	return append(b,
+		(byte((vals[0]>>0)&255) << 0),
+		(byte((vals[0]>>8)&255) << 0),
+		(byte((vals[0]>>16)&255) << 0),
+		(byte((vals[0]>>24)&255) << 0),
+		(byte((vals[1]>>0)&255) << 0),
+		(byte((vals[1]>>8)&255) << 0),
+		(byte((vals[1]>>16)&255) << 0),
+		(byte((vals[1]>>24)&255) << 0),
+		(byte((vals[2]>>0)&255) << 0),
+		(byte((vals[2]>>8)&255) << 0),
+		(byte((vals[2]>>16)&255) << 0),
+		(byte((vals[2]>>24)&255) << 0),
+		(byte((vals[3]>>0)&255) << 0),
+		(byte((vals[3]>>8)&255) << 0),
+		(byte((vals[3]>>16)&255) << 0),
+		(byte((vals[3]>>24)&255) << 0),
+		(byte((vals[4]>>0)&255) << 0),
+		(byte((vals[4]>>8)&255) << 0),
+		(byte((vals[4]>>16)&255) << 0),
+		(byte((vals[4]>>24)&255) << 0),
+		(byte((vals[5]>>0)&255) << 0),
+		(byte((vals[5]>>8)&255) << 0),
+		(byte((vals[5]>>16)&255) << 0),
+		(byte((vals[5]>>24)&255) << 0),
+		(byte((vals[6]>>0)&255) << 0),
+		(byte((vals[6]>>8)&255) << 0),
+		(byte((vals[6]>>16)&255) << 0),
+		(byte((vals[6]>>24)&255) << 0),
+		(byte((vals[7]>>0)&255) << 0),
+		(byte((vals[7]>>8)&255) << 0),
+		(byte((vals[7]>>16)&255) << 0),
+		(byte((vals[7]>>24)&255) << 0),
+	)
+}
+
+func Unpack(width int, vals []byte) []uint32 {
 	switch width {
 	case 1:
 		return unpack1(vals)
@@ -83,59 +949,994 @@ func Unpack(width int, vals []byte) []uint8 {
 		return unpack3(vals)
 	case 4:
 		return unpack4(vals)
+	case 5:
+		return unpack5(vals)
+	case 6:
+		return unpack6(vals)
+	case 7:
+		return unpack7(vals)
+	case 8:
+		return unpack8(vals)
+	case 9:
+		return unpack9(vals)
+	case 10:
+		return unpack10(vals)
+	case 11:
+		return unpack11(vals)
+	case 12:
+		return unpack12(vals)
+	case 13:
+		return unpack13(vals)
+	case 14:
+		return unpack14(vals)
+	case 15:
+		return unpack15(vals)
+	case 16:
+		return unpack16(vals)
+	case 17:
+		return unpack17(vals)
+	case 18:
+		return unpack18(vals)
+	case 19:
+		return unpack19(vals)
+	case 20:
+		return unpack20(vals)
+	case 21:
+		return unpack21(vals)
+	case 22:
+		return unpack22(vals)
+	case 23:
+		return unpack23(vals)
+	case 24:
+		return unpack24(vals)
+	case 25:
+		return unpack25(vals)
+	case 26:
+		return unpack26(vals)
+	case 27:
+		return unpack27(vals)
+	case 28:
+		return unpack28(vals)
+	case 29:
+		return unpack29(vals)
+	case 30:
+		return unpack30(vals)
+	case 31:
+		return unpack31(vals)
+	case 32:
+		return unpack32(vals)
 	default:
-		return []uint8{}
+		return []uint32{}
+	}
+}
+
+func unpack1(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&1) << 0,
+		uint32((vals[0]>>1)&1) << 0,
+		uint32((vals[0]>>2)&1) << 0,
+		uint32((vals[0]>>3)&1) << 0,
+		uint32((vals[0]>>4)&1) << 0,
+		uint32((vals[0]>>5)&1) << 0,
+		uint32((vals[0]>>6)&1) << 0,
+		uint32((vals[0]>>7)&1) << 0,
+	}
+}
+
+func unpack2(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&3) << 0,
+		uint32((vals[0]>>2)&3) << 0,
+		uint32((vals[0]>>4)&3) << 0,
+		uint32((vals[0]>>6)&3) << 0,
+		uint32((vals[1]>>0)&3) << 0,
+		uint32((vals[1]>>2)&3) << 0,
+		uint32((vals[1]>>4)&3) << 0,
+		uint32((vals[1]>>6)&3) << 0,
+	}
+}
+
+func unpack3(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&7) << 0,
+		uint32((vals[0]>>3)&7) << 0,
+		uint32((vals[0]>>6)&3)<<0 |
+			uint32((vals[1]>>0)&1)<<2,
+		uint32((vals[1]>>1)&7) << 0,
+		uint32((vals[1]>>4)&7) << 0,
+		uint32((vals[1]>>7)&1)<<0 |
+			uint32((vals[2]>>0)&3)<<1,
+		uint32((vals[2]>>2)&7) << 0,
+		uint32((vals[2]>>5)&7) << 0,
+	}
+}
+
+func unpack4(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&15) << 0,
+		uint32((vals[0]>>4)&15) << 0,
+		uint32((vals[1]>>0)&15) << 0,
+		uint32((vals[1]>>4)&15) << 0,
+		uint32((vals[2]>>0)&15) << 0,
+		uint32((vals[2]>>4)&15) << 0,
+		uint32((vals[3]>>0)&15) << 0,
+		uint32((vals[3]>>4)&15) << 0,
+	}
+}
+
+func unpack5(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&31) << 0,
+		uint32((vals[0]>>5)&7)<<0 |
+			uint32((vals[1]>>0)&3)<<3,
+		uint32((vals[1]>>2)&31) << 0,
+		uint32((vals[1]>>7)&1)<<0 |
+			uint32((vals[2]>>0)&15)<<1,
+		uint32((vals[2]>>4)&15)<<0 |
+			uint32((vals[3]>>0)&1)<<4,
+		uint32((vals[3]>>1)&31) << 0,
+		uint32((vals[3]>>6)&3)<<0 |
+			uint32((vals[4]>>0)&7)<<2,
+		uint32((vals[4]>>3)&31) << 0,
+	}
+}
+
+func unpack6(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&63) << 0,
+		uint32((vals[0]>>6)&3)<<0 |
+			uint32((vals[1]>>0)&15)<<2,
+		uint32((vals[1]>>4)&15)<<0 |
+			uint32((vals[2]>>0)&3)<<4,
+		uint32((vals[2]>>2)&63) << 0,
+		uint32((vals[3]>>0)&63) << 0,
+		uint32((vals[3]>>6)&3)<<0 |
+			uint32((vals[4]>>0)&15)<<2,
+		uint32((vals[4]>>4)&15)<<0 |
+			uint32((vals[5]>>0)&3)<<4,
+		uint32((vals[5]>>2)&63) << 0,
+	}
+}
+
+func unpack7(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&127) << 0,
+		uint32((vals[0]>>7)&1)<<0 |
+			uint32((vals[1]>>0)&63)<<1,
+		uint32((vals[1]>>6)&3)<<0 |
+			uint32((vals[2]>>0)&31)<<2,
+		uint32((vals[2]>>5)&7)<<0 |
+			uint32((vals[3]>>0)&15)<<3,
+		uint32((vals[3]>>4)&15)<<0 |
+			uint32((vals[4]>>0)&7)<<4,
+		uint32((vals[4]>>3)&31)<<0 |
+			uint32((vals[5]>>0)&3)<<5,
+		uint32((vals[5]>>2)&63)<<0 |
+			uint32((vals[6]>>0)&1)<<6,
+		uint32((vals[6]>>1)&127) << 0,
+	}
+}
+
+func unpack8(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255) << 0,
+		uint32((vals[1]>>0)&255) << 0,
+		uint32((vals[2]>>0)&255) << 0,
+		uint32((vals[3]>>0)&255) << 0,
+		uint32((vals[4]>>0)&255) << 0,
+		uint32((vals[5]>>0)&255) << 0,
+		uint32((vals[6]>>0)&255) << 0,
+		uint32((vals[7]>>0)&255) << 0,
+	}
+}
+
+func unpack9(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&1)<<8,
+		uint32((vals[1]>>1)&127)<<0 |
+			uint32((vals[2]>>0)&3)<<7,
+		uint32((vals[2]>>2)&63)<<0 |
+			uint32((vals[3]>>0)&7)<<6,
+		uint32((vals[3]>>3)&31)<<0 |
+			uint32((vals[4]>>0)&15)<<5,
+		uint32((vals[4]>>4)&15)<<0 |
+			uint32((vals[5]>>0)&31)<<4,
+		uint32((vals[5]>>5)&7)<<0 |
+			uint32((vals[6]>>0)&63)<<3,
+		uint32((vals[6]>>6)&3)<<0 |
+			uint32((vals[7]>>0)&127)<<2,
+		uint32((vals[7]>>7)&1)<<0 |
+			uint32((vals[8]>>0)&255)<<1,
+	}
+}
+
+func unpack10(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&3)<<8,
+		uint32((vals[1]>>2)&63)<<0 |
+			uint32((vals[2]>>0)&15)<<6,
+		uint32((vals[2]>>4)&15)<<0 |
+			uint32((vals[3]>>0)&63)<<4,
+		uint32((vals[3]>>6)&3)<<0 |
+			uint32((vals[4]>>0)&255)<<2,
+		uint32((vals[5]>>0)&255)<<0 |
+			uint32((vals[6]>>0)&3)<<8,
+		uint32((vals[6]>>2)&63)<<0 |
+			uint32((vals[7]>>0)&15)<<6,
+		uint32((vals[7]>>4)&15)<<0 |
+			uint32((vals[8]>>0)&63)<<4,
+		uint32((vals[8]>>6)&3)<<0 |
+			uint32((vals[9]>>0)&255)<<2,
+	}
+}
+
+func unpack11(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&7)<<8,
+		uint32((vals[1]>>3)&31)<<0 |
+			uint32((vals[2]>>0)&63)<<5,
+		uint32((vals[2]>>6)&3)<<0 |
+			uint32((vals[3]>>0)&255)<<2 |
+			uint32((vals[4]>>0)&1)<<10,
+		uint32((vals[4]>>1)&127)<<0 |
+			uint32((vals[5]>>0)&15)<<7,
+		uint32((vals[5]>>4)&15)<<0 |
+			uint32((vals[6]>>0)&127)<<4,
+		uint32((vals[6]>>7)&1)<<0 |
+			uint32((vals[7]>>0)&255)<<1 |
+			uint32((vals[8]>>0)&3)<<9,
+		uint32((vals[8]>>2)&63)<<0 |
+			uint32((vals[9]>>0)&31)<<6,
+		uint32((vals[9]>>5)&7)<<0 |
+			uint32((vals[10]>>0)&255)<<3,
+	}
+}
+
+func unpack12(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&15)<<8,
+		uint32((vals[1]>>4)&15)<<0 |
+			uint32((vals[2]>>0)&255)<<4,
+		uint32((vals[3]>>0)&255)<<0 |
+			uint32((vals[4]>>0)&15)<<8,
+		uint32((vals[4]>>4)&15)<<0 |
+			uint32((vals[5]>>0)&255)<<4,
+		uint32((vals[6]>>0)&255)<<0 |
+			uint32((vals[7]>>0)&15)<<8,
+		uint32((vals[7]>>4)&15)<<0 |
+			uint32((vals[8]>>0)&255)<<4,
+		uint32((vals[9]>>0)&255)<<0 |
+			uint32((vals[10]>>0)&15)<<8,
+		uint32((vals[10]>>4)&15)<<0 |
+			uint32((vals[11]>>0)&255)<<4,
+	}
+}
+
+func unpack13(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&31)<<8,
+		uint32((vals[1]>>5)&7)<<0 |
+			uint32((vals[2]>>0)&255)<<3 |
+			uint32((vals[3]>>0)&3)<<11,
+		uint32((vals[3]>>2)&63)<<0 |
+			uint32((vals[4]>>0)&127)<<6,
+		uint32((vals[4]>>7)&1)<<0 |
+			uint32((vals[5]>>0)&255)<<1 |
+			uint32((vals[6]>>0)&15)<<9,
+		uint32((vals[6]>>4)&15)<<0 |
+			uint32((vals[7]>>0)&255)<<4 |
+			uint32((vals[8]>>0)&1)<<12,
+		uint32((vals[8]>>1)&127)<<0 |
+			uint32((vals[9]>>0)&63)<<7,
+		uint32((vals[9]>>6)&3)<<0 |
+			uint32((vals[10]>>0)&255)<<2 |
+			uint32((vals[11]>>0)&7)<<10,
+		uint32((vals[11]>>3)&31)<<0 |
+			uint32((vals[12]>>0)&255)<<5,
+	}
+}
+
+func unpack14(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&63)<<8,
+		uint32((vals[1]>>6)&3)<<0 |
+			uint32((vals[2]>>0)&255)<<2 |
+			uint32((vals[3]>>0)&15)<<10,
+		uint32((vals[3]>>4)&15)<<0 |
+			uint32((vals[4]>>0)&255)<<4 |
+			uint32((vals[5]>>0)&3)<<12,
+		uint32((vals[5]>>2)&63)<<0 |
+			uint32((vals[6]>>0)&255)<<6,
+		uint32((vals[7]>>0)&255)<<0 |
+			uint32((vals[8]>>0)&63)<<8,
+		uint32((vals[8]>>6)&3)<<0 |
+			uint32((vals[9]>>0)&255)<<2 |
+			uint32((vals[10]>>0)&15)<<10,
+		uint32((vals[10]>>4)&15)<<0 |
+			uint32((vals[11]>>0)&255)<<4 |
+			uint32((vals[12]>>0)&3)<<12,
+		uint32((vals[12]>>2)&63)<<0 |
+			uint32((vals[13]>>0)&255)<<6,
+	}
+}
+
+func unpack15(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&127)<<8,
+		uint32((vals[1]>>7)&1)<<0 |
+			uint32((vals[2]>>0)&255)<<1 |
+			uint32((vals[3]>>0)&63)<<9,
+		uint32((vals[3]>>6)&3)<<0 |
+			uint32((vals[4]>>0)&255)<<2 |
+			uint32((vals[5]>>0)&31)<<10,
+		uint32((vals[5]>>5)&7)<<0 |
+			uint32((vals[6]>>0)&255)<<3 |
+			uint32((vals[7]>>0)&15)<<11,
+		uint32((vals[7]>>4)&15)<<0 |
+			uint32((vals[8]>>0)&255)<<4 |
+			uint32((vals[9]>>0)&7)<<12,
+		uint32((vals[9]>>3)&31)<<0 |
+			uint32((vals[10]>>0)&255)<<5 |
+			uint32((vals[11]>>0)&3)<<13,
+		uint32((vals[11]>>2)&63)<<0 |
+			uint32((vals[12]>>0)&255)<<6 |
+			uint32((vals[13]>>0)&1)<<14,
+		uint32((vals[13]>>1)&127)<<0 |
+			uint32((vals[14]>>0)&255)<<7,
+	}
+}
+
+func unpack16(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8,
+		uint32((vals[2]>>0)&255)<<0 |
+			uint32((vals[3]>>0)&255)<<8,
+		uint32((vals[4]>>0)&255)<<0 |
+			uint32((vals[5]>>0)&255)<<8,
+		uint32((vals[6]>>0)&255)<<0 |
+			uint32((vals[7]>>0)&255)<<8,
+		uint32((vals[8]>>0)&255)<<0 |
+			uint32((vals[9]>>0)&255)<<8,
+		uint32((vals[10]>>0)&255)<<0 |
+			uint32((vals[11]>>0)&255)<<8,
+		uint32((vals[12]>>0)&255)<<0 |
+			uint32((vals[13]>>0)&255)<<8,
+		uint32((vals[14]>>0)&255)<<0 |
+			uint32((vals[15]>>0)&255)<<8,
+	}
+}
+
+func unpack17(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&1)<<16,
+		uint32((vals[2]>>1)&127)<<0 |
+			uint32((vals[3]>>0)&255)<<7 |
+			uint32((vals[4]>>0)&3)<<15,
+		uint32((vals[4]>>2)&63)<<0 |
+			uint32((vals[5]>>0)&255)<<6 |
+			uint32((vals[6]>>0)&7)<<14,
+		uint32((vals[6]>>3)&31)<<0 |
+			uint32((vals[7]>>0)&255)<<5 |
+			uint32((vals[8]>>0)&15)<<13,
+		uint32((vals[8]>>4)&15)<<0 |
+			uint32((vals[9]>>0)&255)<<4 |
+			uint32((vals[10]>>0)&31)<<12,
+		uint32((vals[10]>>5)&7)<<0 |
+			uint32((vals[11]>>0)&255)<<3 |
+			uint32((vals[12]>>0)&63)<<11,
+		uint32((vals[12]>>6)&3)<<0 |
+			uint32((vals[13]>>0)&255)<<2 |
+			uint32((vals[14]>>0)&127)<<10,
+		uint32((vals[14]>>7)&1)<<0 |
+			uint32((vals[15]>>0)&255)<<1 |
+			uint32((vals[16]>>0)&255)<<9,
+	}
+}
+
+func unpack18(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&3)<<16,
+		uint32((vals[2]>>2)&63)<<0 |
+			uint32((vals[3]>>0)&255)<<6 |
+			uint32((vals[4]>>0)&15)<<14,
+		uint32((vals[4]>>4)&15)<<0 |
+			uint32((vals[5]>>0)&255)<<4 |
+			uint32((vals[6]>>0)&63)<<12,
+		uint32((vals[6]>>6)&3)<<0 |
+			uint32((vals[7]>>0)&255)<<2 |
+			uint32((vals[8]>>0)&255)<<10,
+		uint32((vals[9]>>0)&255)<<0 |
+			uint32((vals[10]>>0)&255)<<8 |
+			uint32((vals[11]>>0)&3)<<16,
+		uint32((vals[11]>>2)&63)<<0 |
+			uint32((vals[12]>>0)&255)<<6 |
+			uint32((vals[13]>>0)&15)<<14,
+		uint32((vals[13]>>4)&15)<<0 |
+			uint32((vals[14]>>0)&255)<<4 |
+			uint32((vals[15]>>0)&63)<<12,
+		uint32((vals[15]>>6)&3)<<0 |
+			uint32((vals[16]>>0)&255)<<2 |
+			uint32((vals[17]>>0)&255)<<10,
 	}
 }
 
-func unpack1(vals []byte) []uint8 {
-	return []uint8{
-		(uint8(vals[0]&1) >> 0),
-		(uint8(vals[0]&2) >> 1),
-		(uint8(vals[0]&4) >> 2),
-		(uint8(vals[0]&8) >> 3),
-		(uint8(vals[0]&16) >> 4),
-		(uint8(vals[0]&32) >> 5),
-		(uint8(vals[0]&64) >> 6),
-		(uint8(vals[0]&128) >> 7),
+func unpack19(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&7)<<16,
+		uint32((vals[2]>>3)&31)<<0 |
+			uint32((vals[3]>>0)&255)<<5 |
+			uint32((vals[4]>>0)&63)<<13,
+		uint32((vals[4]>>6)&3)<<0 |
+			uint32((vals[5]>>0)&255)<<2 |
+			uint32((vals[6]>>0)&255)<<10 |
+			uint32((vals[7]>>0)&1)<<18,
+		uint32((vals[7]>>1)&127)<<0 |
+			uint32((vals[8]>>0)&255)<<7 |
+			uint32((vals[9]>>0)&15)<<15,
+		uint32((vals[9]>>4)&15)<<0 |
+			uint32((vals[10]>>0)&255)<<4 |
+			uint32((vals[11]>>0)&127)<<12,
+		uint32((vals[11]>>7)&1)<<0 |
+			uint32((vals[12]>>0)&255)<<1 |
+			uint32((vals[13]>>0)&255)<<9 |
+			uint32((vals[14]>>0)&3)<<17,
+		uint32((vals[14]>>2)&63)<<0 |
+			uint32((vals[15]>>0)&255)<<6 |
+			uint32((vals[16]>>0)&31)<<14,
+		uint32((vals[16]>>5)&7)<<0 |
+			uint32((vals[17]>>0)&255)<<3 |
+			uint32((vals[18]>>0)&255)<<11,
 	}
 }
 
-func unpack2(vals []byte) []uint8 {
-	return []uint8{
-		(uint8(vals[0]&3) >> 0),
-		(uint8(vals[0]&12) >> 2),
-		(uint8(vals[0]&48) >> 4),
-		(uint8(vals[0]&192) >> 6),
-		(uint8(vals[1]&3) >> 0),
-		(uint8(vals[1]&12) >> 2),
-		(uint8(vals[1]&48) >> 4),
-		(uint8(vals[1]&192) >> 6),
+func unpack20(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&15)<<16,
+		uint32((vals[2]>>4)&15)<<0 |
+			uint32((vals[3]>>0)&255)<<4 |
+			uint32((vals[4]>>0)&255)<<12,
+		uint32((vals[5]>>0)&255)<<0 |
+			uint32((vals[6]>>0)&255)<<8 |
+			uint32((vals[7]>>0)&15)<<16,
+		uint32((vals[7]>>4)&15)<<0 |
+			uint32((vals[8]>>0)&255)<<4 |
+			uint32((vals[9]>>0)&255)<<12,
+		uint32((vals[10]>>0)&255)<<0 |
+			uint32((vals[11]>>0)&255)<<8 |
+			uint32((vals[12]>>0)&15)<<16,
+		uint32((vals[12]>>4)&15)<<0 |
+			uint32((vals[13]>>0)&255)<<4 |
+			uint32((vals[14]>>0)&255)<<12,
+		uint32((vals[15]>>0)&255)<<0 |
+			uint32((vals[16]>>0)&255)<<8 |
+			uint32((vals[17]>>0)&15)<<16,
+		uint32((vals[17]>>4)&15)<<0 |
+			uint32((vals[18]>>0)&255)<<4 |
+			uint32((vals[19]>>0)&255)<<12,
 	}
 }
 
-func unpack3(vals []byte) []uint8 {
-	return []uint8{
-		(uint8(vals[0]&7) >> 0),
-		(uint8(vals[0]&56) >> 3),
-		(uint8(vals[0]&192) >> 6) | (uint8(vals[1]&1) << 2),
-		(uint8(vals[1]&14) >> 1),
-		(uint8(vals[1]&112) >> 4),
-		(uint8(vals[1]&128) >> 7) | (uint8(vals[2]&3) << 1),
-		(uint8(vals[2]&28) >> 2),
-		(uint8(vals[2]&224) >> 5),
+func unpack21(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&31)<<16,
+		uint32((vals[2]>>5)&7)<<0 |
+			uint32((vals[3]>>0)&255)<<3 |
+			uint32((vals[4]>>0)&255)<<11 |
+			uint32((vals[5]>>0)&3)<<19,
+		uint32((vals[5]>>2)&63)<<0 |
+			uint32((vals[6]>>0)&255)<<6 |
+			uint32((vals[7]>>0)&127)<<14,
+		uint32((vals[7]>>7)&1)<<0 |
+			uint32((vals[8]>>0)&255)<<1 |
+			uint32((vals[9]>>0)&255)<<9 |
+			uint32((vals[10]>>0)&15)<<17,
+		uint32((vals[10]>>4)&15)<<0 |
+			uint32((vals[11]>>0)&255)<<4 |
+			uint32((vals[12]>>0)&255)<<12 |
+			uint32((vals[13]>>0)&1)<<20,
+		uint32((vals[13]>>1)&127)<<0 |
+			uint32((vals[14]>>0)&255)<<7 |
+			uint32((vals[15]>>0)&63)<<15,
+		uint32((vals[15]>>6)&3)<<0 |
+			uint32((vals[16]>>0)&255)<<2 |
+			uint32((vals[17]>>0)&255)<<10 |
+			uint32((vals[18]>>0)&7)<<18,
+		uint32((vals[18]>>3)&31)<<0 |
+			uint32((vals[19]>>0)&255)<<5 |
+			uint32((vals[20]>>0)&255)<<13,
 	}
 }
 
-func unpack4(vals []byte) []uint8 {
-	return []uint8{
-		(uint8(vals[0]&15) >> 0),
-		(uint8(vals[0]&240) >> 4),
-		(uint8(vals[1]&15) >> 0),
-		(uint8(vals[1]&240) >> 4),
-		(uint8(vals[2]&15) >> 0),
-		(uint8(vals[2]&240) >> 4),
-		(uint8(vals[3]&15) >> 0),
-		(uint8(vals[3]&240) >> 4),
+func unpack22(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&63)<<16,
+		uint32((vals[2]>>6)&3)<<0 |
+			uint32((vals[3]>>0)&255)<<2 |
+			uint32((vals[4]>>0)&255)<<10 |
+			uint32((vals[5]>>0)&15)<<18,
+		uint32((vals[5]>>4)&15)<<0 |
+			uint32((vals[6]>>0)&255)<<4 |
+			uint32((vals[7]>>0)&255)<<12 |
+			uint32((vals[8]>>0)&3)<<20,
+		uint32((vals[8]>>2)&63)<<0 |
+			uint32((vals[9]>>0)&255)<<6 |
+			uint32((vals[10]>>0)&255)<<14,
+		uint32((vals[11]>>0)&255)<<0 |
+			uint32((vals[12]>>0)&255)<<8 |
+			uint32((vals[13]>>0)&63)<<16,
+		uint32((vals[13]>>6)&3)<<0 |
+			uint32((vals[14]>>0)&255)<<2 |
+			uint32((vals[15]>>0)&255)<<10 |
+			uint32((vals[16]>>0)&15)<<18,
+		uint32((vals[16]>>4)&15)<<0 |
+			uint32((vals[17]>>0)&255)<<4 |
+			uint32((vals[18]>>0)&255)<<12 |
+			uint32((vals[19]>>0)&3)<<20,
+		uint32((vals[19]>>2)&63)<<0 |
+			uint32((vals[20]>>0)&255)<<6 |
+			uint32((vals[21]>>0)&255)<<14,
+	}
+}
+
+func unpack23(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&127)<<16,
+		uint32((vals[2]>>7)&1)<<0 |
+			uint32((vals[3]>>0)&255)<<1 |
+			uint32((vals[4]>>0)&255)<<9 |
+			uint32((vals[5]>>0)&63)<<17,
+		uint32((vals[5]>>6)&3)<<0 |
+			uint32((vals[6]>>0)&255)<<2 |
+			uint32((vals[7]>>0)&255)<<10 |
+			uint32((vals[8]>>0)&31)<<18,
+		uint32((vals[8]>>5)&7)<<0 |
+			uint32((vals[9]>>0)&255)<<3 |
+			uint32((vals[10]>>0)&255)<<11 |
+			uint32((vals[11]>>0)&15)<<19,
+		uint32((vals[11]>>4)&15)<<0 |
+			uint32((vals[12]>>0)&255)<<4 |
+			uint32((vals[13]>>0)&255)<<12 |
+			uint32((vals[14]>>0)&7)<<20,
+		uint32((vals[14]>>3)&31)<<0 |
+			uint32((vals[15]>>0)&255)<<5 |
+			uint32((vals[16]>>0)&255)<<13 |
+			uint32((vals[17]>>0)&3)<<21,
+		uint32((vals[17]>>2)&63)<<0 |
+			uint32((vals[18]>>0)&255)<<6 |
+			uint32((vals[19]>>0)&255)<<14 |
+			uint32((vals[20]>>0)&1)<<22,
+		uint32((vals[20]>>1)&127)<<0 |
+			uint32((vals[21]>>0)&255)<<7 |
+			uint32((vals[22]>>0)&255)<<15,
+	}
+}
+
+func unpack24(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16,
+		uint32((vals[3]>>0)&255)<<0 |
+			uint32((vals[4]>>0)&255)<<8 |
+			uint32((vals[5]>>0)&255)<<16,
+		uint32((vals[6]>>0)&255)<<0 |
+			uint32((vals[7]>>0)&255)<<8 |
+			uint32((vals[8]>>0)&255)<<16,
+		uint32((vals[9]>>0)&255)<<0 |
+			uint32((vals[10]>>0)&255)<<8 |
+			uint32((vals[11]>>0)&255)<<16,
+		uint32((vals[12]>>0)&255)<<0 |
+			uint32((vals[13]>>0)&255)<<8 |
+			uint32((vals[14]>>0)&255)<<16,
+		uint32((vals[15]>>0)&255)<<0 |
+			uint32((vals[16]>>0)&255)<<8 |
+			uint32((vals[17]>>0)&255)<<16,
+		uint32((vals[18]>>0)&255)<<0 |
+			uint32((vals[19]>>0)&255)<<8 |
+			uint32((vals[20]>>0)&255)<<16,
+		uint32((vals[21]>>0)&255)<<0 |
+			uint32((vals[22]>>0)&255)<<8 |
+			uint32((vals[23]>>0)&255)<<16,
+	}
+}
+
+func unpack25(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&1)<<24,
+		uint32((vals[3]>>1)&127)<<0 |
+			uint32((vals[4]>>0)&255)<<7 |
+			uint32((vals[5]>>0)&255)<<15 |
+			uint32((vals[6]>>0)&3)<<23,
+		uint32((vals[6]>>2)&63)<<0 |
+			uint32((vals[7]>>0)&255)<<6 |
+			uint32((vals[8]>>0)&255)<<14 |
+			uint32((vals[9]>>0)&7)<<22,
+		uint32((vals[9]>>3)&31)<<0 |
+			uint32((vals[10]>>0)&255)<<5 |
+			uint32((vals[11]>>0)&255)<<13 |
+			uint32((vals[12]>>0)&15)<<21,
+		uint32((vals[12]>>4)&15)<<0 |
+			uint32((vals[13]>>0)&255)<<4 |
+			uint32((vals[14]>>0)&255)<<12 |
+			uint32((vals[15]>>0)&31)<<20,
+		uint32((vals[15]>>5)&7)<<0 |
+			uint32((vals[16]>>0)&255)<<3 |
+			uint32((vals[17]>>0)&255)<<11 |
+			uint32((vals[18]>>0)&63)<<19,
+		uint32((vals[18]>>6)&3)<<0 |
+			uint32((vals[19]>>0)&255)<<2 |
+			uint32((vals[20]>>0)&255)<<10 |
+			uint32((vals[21]>>0)&127)<<18,
+		uint32((vals[21]>>7)&1)<<0 |
+			uint32((vals[22]>>0)&255)<<1 |
+			uint32((vals[23]>>0)&255)<<9 |
+			uint32((vals[24]>>0)&255)<<17,
+	}
+}
+
+func unpack26(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&3)<<24,
+		uint32((vals[3]>>2)&63)<<0 |
+			uint32((vals[4]>>0)&255)<<6 |
+			uint32((vals[5]>>0)&255)<<14 |
+			uint32((vals[6]>>0)&15)<<22,
+		uint32((vals[6]>>4)&15)<<0 |
+			uint32((vals[7]>>0)&255)<<4 |
+			uint32((vals[8]>>0)&255)<<12 |
+			uint32((vals[9]>>0)&63)<<20,
+		uint32((vals[9]>>6)&3)<<0 |
+			uint32((vals[10]>>0)&255)<<2 |
+			uint32((vals[11]>>0)&255)<<10 |
+			uint32((vals[12]>>0)&255)<<18,
+		uint32((vals[13]>>0)&255)<<0 |
+			uint32((vals[14]>>0)&255)<<8 |
+			uint32((vals[15]>>0)&255)<<16 |
+			uint32((vals[16]>>0)&3)<<24,
+		uint32((vals[16]>>2)&63)<<0 |
+			uint32((vals[17]>>0)&255)<<6 |
+			uint32((vals[18]>>0)&255)<<14 |
+			uint32((vals[19]>>0)&15)<<22,
+		uint32((vals[19]>>4)&15)<<0 |
+			uint32((vals[20]>>0)&255)<<4 |
+			uint32((vals[21]>>0)&255)<<12 |
+			uint32((vals[22]>>0)&63)<<20,
+		uint32((vals[22]>>6)&3)<<0 |
+			uint32((vals[23]>>0)&255)<<2 |
+			uint32((vals[24]>>0)&255)<<10 |
+			uint32((vals[25]>>0)&255)<<18,
+	}
+}
+
+func unpack27(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&7)<<24,
+		uint32((vals[3]>>3)&31)<<0 |
+			uint32((vals[4]>>0)&255)<<5 |
+			uint32((vals[5]>>0)&255)<<13 |
+			uint32((vals[6]>>0)&63)<<21,
+		uint32((vals[6]>>6)&3)<<0 |
+			uint32((vals[7]>>0)&255)<<2 |
+			uint32((vals[8]>>0)&255)<<10 |
+			uint32((vals[9]>>0)&255)<<18 |
+			uint32((vals[10]>>0)&1)<<26,
+		uint32((vals[10]>>1)&127)<<0 |
+			uint32((vals[11]>>0)&255)<<7 |
+			uint32((vals[12]>>0)&255)<<15 |
+			uint32((vals[13]>>0)&15)<<23,
+		uint32((vals[13]>>4)&15)<<0 |
+			uint32((vals[14]>>0)&255)<<4 |
+			uint32((vals[15]>>0)&255)<<12 |
+			uint32((vals[16]>>0)&127)<<20,
+		uint32((vals[16]>>7)&1)<<0 |
+			uint32((vals[17]>>0)&255)<<1 |
+			uint32((vals[18]>>0)&255)<<9 |
+			uint32((vals[19]>>0)&255)<<17 |
+			uint32((vals[20]>>0)&3)<<25,
+		uint32((vals[20]>>2)&63)<<0 |
+			uint32((vals[21]>>0)&255)<<6 |
+			uint32((vals[22]>>0)&255)<<14 |
+			uint32((vals[23]>>0)&31)<<22,
+		uint32((vals[23]>>5)&7)<<0 |
+			uint32((vals[24]>>0)&255)<<3 |
+			uint32((vals[25]>>0)&255)<<11 |
+			uint32((vals[26]>>0)&255)<<19,
+	}
+}
+
+func unpack28(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&15)<<24,
+		uint32((vals[3]>>4)&15)<<0 |
+			uint32((vals[4]>>0)&255)<<4 |
+			uint32((vals[5]>>0)&255)<<12 |
+			uint32((vals[6]>>0)&255)<<20,
+		uint32((vals[7]>>0)&255)<<0 |
+			uint32((vals[8]>>0)&255)<<8 |
+			uint32((vals[9]>>0)&255)<<16 |
+			uint32((vals[10]>>0)&15)<<24,
+		uint32((vals[10]>>4)&15)<<0 |
+			uint32((vals[11]>>0)&255)<<4 |
+			uint32((vals[12]>>0)&255)<<12 |
+			uint32((vals[13]>>0)&255)<<20,
+		uint32((vals[14]>>0)&255)<<0 |
+			uint32((vals[15]>>0)&255)<<8 |
+			uint32((vals[16]>>0)&255)<<16 |
+			uint32((vals[17]>>0)&15)<<24,
+		uint32((vals[17]>>4)&15)<<0 |
+			uint32((vals[18]>>0)&255)<<4 |
+			uint32((vals[19]>>0)&255)<<12 |
+			uint32((vals[20]>>0)&255)<<20,
+		uint32((vals[21]>>0)&255)<<0 |
+			uint32((vals[22]>>0)&255)<<8 |
+			uint32((vals[23]>>0)&255)<<16 |
+			uint32((vals[24]>>0)&15)<<24,
+		uint32((vals[24]>>4)&15)<<0 |
+			uint32((vals[25]>>0)&255)<<4 |
+			uint32((vals[26]>>0)&255)<<12 |
+			uint32((vals[27]>>0)&255)<<20,
+	}
+}
+
+func unpack29(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&31)<<24,
+		uint32((vals[3]>>5)&7)<<0 |
+			uint32((vals[4]>>0)&255)<<3 |
+			uint32((vals[5]>>0)&255)<<11 |
+			uint32((vals[6]>>0)&255)<<19 |
+			uint32((vals[7]>>0)&3)<<27,
+		uint32((vals[7]>>2)&63)<<0 |
+			uint32((vals[8]>>0)&255)<<6 |
+			uint32((vals[9]>>0)&255)<<14 |
+			uint32((vals[10]>>0)&127)<<22,
+		uint32((vals[10]>>7)&1)<<0 |
+			uint32((vals[11]>>0)&255)<<1 |
+			uint32((vals[12]>>0)&255)<<9 |
+			uint32((vals[13]>>0)&255)<<17 |
+			uint32((vals[14]>>0)&15)<<25,
+		uint32((vals[14]>>4)&15)<<0 |
+			uint32((vals[15]>>0)&255)<<4 |
+			uint32((vals[16]>>0)&255)<<12 |
+			uint32((vals[17]>>0)&255)<<20 |
+			uint32((vals[18]>>0)&1)<<28,
+		uint32((vals[18]>>1)&127)<<0 |
+			uint32((vals[19]>>0)&255)<<7 |
+			uint32((vals[20]>>0)&255)<<15 |
+			uint32((vals[21]>>0)&63)<<23,
+		uint32((vals[21]>>6)&3)<<0 |
+			uint32((vals[22]>>0)&255)<<2 |
+			uint32((vals[23]>>0)&255)<<10 |
+			uint32((vals[24]>>0)&255)<<18 |
+			uint32((vals[25]>>0)&7)<<26,
+		uint32((vals[25]>>3)&31)<<0 |
+			uint32((vals[26]>>0)&255)<<5 |
+			uint32((vals[27]>>0)&255)<<13 |
+			uint32((vals[28]>>0)&255)<<21,
+	}
+}
+
+func unpack30(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&63)<<24,
+		uint32((vals[3]>>6)&3)<<0 |
+			uint32((vals[4]>>0)&255)<<2 |
+			uint32((vals[5]>>0)&255)<<10 |
+			uint32((vals[6]>>0)&255)<<18 |
+			uint32((vals[7]>>0)&15)<<26,
+		uint32((vals[7]>>4)&15)<<0 |
+			uint32((vals[8]>>0)&255)<<4 |
+			uint32((vals[9]>>0)&255)<<12 |
+			uint32((vals[10]>>0)&255)<<20 |
+			uint32((vals[11]>>0)&3)<<28,
+		uint32((vals[11]>>2)&63)<<0 |
+			uint32((vals[12]>>0)&255)<<6 |
+			uint32((vals[13]>>0)&255)<<14 |
+			uint32((vals[14]>>0)&255)<<22,
+		uint32((vals[15]>>0)&255)<<0 |
+			uint32((vals[16]>>0)&255)<<8 |
+			uint32((vals[17]>>0)&255)<<16 |
+			uint32((vals[18]>>0)&63)<<24,
+		uint32((vals[18]>>6)&3)<<0 |
+			uint32((vals[19]>>0)&255)<<2 |
+			uint32((vals[20]>>0)&255)<<10 |
+			uint32((vals[21]>>0)&255)<<18 |
+			uint32((vals[22]>>0)&15)<<26,
+		uint32((vals[22]>>4)&15)<<0 |
+			uint32((vals[23]>>0)&255)<<4 |
+			uint32((vals[24]>>0)&255)<<12 |
+			uint32((vals[25]>>0)&255)<<20 |
+			uint32((vals[26]>>0)&3)<<28,
+		uint32((vals[26]>>2)&63)<<0 |
+			uint32((vals[27]>>0)&255)<<6 |
+			uint32((vals[28]>>0)&255)<<14 |
+			uint32((vals[29]>>0)&255)<<22,
+	}
+}
+
+func unpack31(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&127)<<24,
+		uint32((vals[3]>>7)&1)<<0 |
+			uint32((vals[4]>>0)&255)<<1 |
+			uint32((vals[5]>>0)&255)<<9 |
+			uint32((vals[6]>>0)&255)<<17 |
+			uint32((vals[7]>>0)&63)<<25,
+		uint32((vals[7]>>6)&3)<<0 |
+			uint32((vals[8]>>0)&255)<<2 |
+			uint32((vals[9]>>0)&255)<<10 |
+			uint32((vals[10]>>0)&255)<<18 |
+			uint32((vals[11]>>0)&31)<<26,
+		uint32((vals[11]>>5)&7)<<0 |
+			uint32((vals[12]>>0)&255)<<3 |
+			uint32((vals[13]>>0)&255)<<11 |
+			uint32((vals[14]>>0)&255)<<19 |
+			uint32((vals[15]>>0)&15)<<27,
+		uint32((vals[15]>>4)&15)<<0 |
+			uint32((vals[16]>>0)&255)<<4 |
+			uint32((vals[17]>>0)&255)<<12 |
+			uint32((vals[18]>>0)&255)<<20 |
+			uint32((vals[19]>>0)&7)<<28,
+		uint32((vals[19]>>3)&31)<<0 |
+			uint32((vals[20]>>0)&255)<<5 |
+			uint32((vals[21]>>0)&255)<<13 |
+			uint32((vals[22]>>0)&255)<<21 |
+			uint32((vals[23]>>0)&3)<<29,
+		uint32((vals[23]>>2)&63)<<0 |
+			uint32((vals[24]>>0)&255)<<6 |
+			uint32((vals[25]>>0)&255)<<14 |
+			uint32((vals[26]>>0)&255)<<22 |
+			uint32((vals[27]>>0)&1)<<30,
+		uint32((vals[27]>>1)&127)<<0 |
+			uint32((vals[28]>>0)&255)<<7 |
+			uint32((vals[29]>>0)&255)<<15 |
+			uint32((vals[30]>>0)&255)<<23,
+	}
+}
+
+func unpack32(vals []byte) []uint32 {
+	return []uint32{
+		uint32((vals[0]>>0)&255)<<0 |
+			uint32((vals[1]>>0)&255)<<8 |
+			uint32((vals[2]>>0)&255)<<16 |
+			uint32((vals[3]>>0)&255)<<24,
+		uint32((vals[4]>>0)&255)<<0 |
+			uint32((vals[5]>>0)&255)<<8 |
+			uint32((vals[6]>>0)&255)<<16 |
+			uint32((vals[7]>>0)&255)<<24,
+		uint32((vals[8]>>0)&255)<<0 |
+			uint32((vals[9]>>0)&255)<<8 |
+			uint32((vals[10]>>0)&255)<<16 |
+			uint32((vals[11]>>0)&255)<<24,
+		uint32((vals[12]>>0)&255)<<0 |
+			uint32((vals[13]>>0)&255)<<8 |
+			uint32((vals[14]>>0)&255)<<16 |
+			uint32((vals[15]>>0)&255)<<24,
+		uint32((vals[16]>>0)&255)<<0 |
+			uint32((vals[17]>>0)&255)<<8 |
+			uint32((vals[18]>>0)&255)<<16 |
+			uint32((vals[19]>>0)&255)<<24,
+		uint32((vals[20]>>0)&255)<<0 |
+			uint32((vals[21]>>0)&255)<<8 |
+			uint32((vals[22]>>0)&255)<<16 |
+			uint32((vals[23]>>0)&255)<<24,
+		uint32((vals[24]>>0)&255)<<0 |
+			uint32((vals[25]>>0)&255)<<8 |
+			uint32((vals[26]>>0)&255)<<16 |
+			uint32((vals[27]>>0)&255)<<24,
+		uint32((vals[28]>>0)&255)<<0 |
+			uint32((vals[29]>>0)&255)<<8 |
+			uint32((vals[30]>>0)&255)<<16 |
+			uint32((vals[31]>>0)&255)<<24,
+	}
+}
+
+const MaxSize64 = 64
+
+// Pack64 packs a group of 8 values with widths up to 64 bits.
+// Unlike Pack it isn't unrolled, the bits are accumulated in
+// a uint64 and written a byte at a time.
+func Pack64(b []byte, width int, vals []uint64) []byte {
+	if width < 1 || width > MaxSize64 {
+		return b
+	}
+
+	var acc uint64
+	var n uint
+	push := func(v uint64, w uint) {
+		acc |= (v & (1<<w - 1)) << n
+		n += w
+		for n >= 8 {
+			b = append(b, byte(acc))
+			acc >>= 8
+			n -= 8
+		}
+	}
+
+	for _, v := range vals[:8] {
+		// acc can hold up to 7 bits that haven't been written,
+		// so wide values are pushed in two halves.
+		if width > 32 {
+			push(v, 32)
+			push(v>>32, uint(width-32))
+		} else {
+			push(v, uint(width))
+		}
+	}
+	return b
+}
+
+// Unpack64 unpacks a group of 8 values with widths up to 64 bits.
+func Unpack64(width int, vals []byte) []uint64 {
+	if width < 1 || width > MaxSize64 {
+		return []uint64{}
+	}
+
+	var acc uint64
+	var n uint
+	var i int
+	pull := func(w uint) uint64 {
+		for n < w {
+			acc |= uint64(vals[i]) << n
+			i++
+			n += 8
+		}
+		v := acc & (1<<w - 1)
+		acc >>= w
+		n -= w
+		return v
+	}
+
+	out := make([]uint64, 8)
+	for j := range out {
+		if width > 32 {
+			out[j] = pull(32) | pull(uint(width-32))<<32
+		} else {
+			out[j] = pull(uint(width))
+		}
 	}
+	return out
 }
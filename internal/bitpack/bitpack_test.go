@@ -12,7 +12,7 @@ import (
 type testCase struct {
 	name  string
 	width int
-	ints  []uint8
+	ints  []uint32
 	bytes []byte
 }
 
@@ -21,25 +21,31 @@ func TestPackAndUnpack(t *testing.T) {
 		{
 			name:  "width 1",
 			width: 1,
-			ints:  []uint8{0, 1, 1, 0, 0, 1, 1, 1},
+			ints:  []uint32{0, 1, 1, 0, 0, 1, 1, 1},
 			bytes: getBytes("11100110"),
 		},
 		{
 			name:  "width 2",
 			width: 2,
-			ints:  []uint8{0, 1, 2, 0, 0, 1, 2, 2},
+			ints:  []uint32{0, 1, 2, 0, 0, 1, 2, 2},
 			bytes: getBytes("00100100", "10100100"),
 		},
 		{
 			name:  "width 3 from apache documentation",
 			width: 3,
-			ints:  []uint8{0, 1, 2, 3, 4, 5, 6, 7},
+			ints:  []uint32{0, 1, 2, 3, 4, 5, 6, 7},
 			bytes: getBytes("10001000", "11000110", "11111010"),
 		},
 		{
 			name:  "width 4",
 			width: 4,
-			ints:  []uint8{0, 2, 4, 7, 14, 15, 1, 0},
+			ints:  []uint32{0, 2, 4, 7, 14, 15, 1, 0},
+		},
+		{
+			name:  "width 12",
+			width: 12,
+			ints:  []uint32{1, 2, 3, 4, 5, 6, 7, 4095},
+			bytes: []byte{0x01, 0x20, 0x00, 0x03, 0x40, 0x00, 0x05, 0x60, 0x00, 0x07, 0xf0, 0xff},
 		},
 	}
 
@@ -56,6 +62,74 @@ func TestPackAndUnpack(t *testing.T) {
 	}
 }
 
+func TestPackAndUnpackAllWidths(t *testing.T) {
+	for width := 1; width <= bitpack.MaxSize; width++ {
+		t.Run(fmt.Sprintf("width %d", width), func(t *testing.T) {
+			max := uint32(1<<uint(width) - 1)
+			ints := []uint32{0, max, 1, max - 1, max / 2, max / 3, 0, max}
+
+			b := bitpack.Pack(nil, width, ints)
+			assert.Len(t, b, width)
+			assert.Equal(t, ints, bitpack.Unpack(width, b))
+		})
+	}
+}
+
+func TestPack64AndUnpack64(t *testing.T) {
+	for width := 1; width <= bitpack.MaxSize64; width++ {
+		t.Run(fmt.Sprintf("width %d", width), func(t *testing.T) {
+			max := uint64(1<<uint(width) - 1)
+			ints := []uint64{0, max, 1, max - 1, max / 2, max / 3, 0, max}
+
+			b := bitpack.Pack64(nil, width, ints)
+			assert.Len(t, b, width)
+			assert.Equal(t, ints, bitpack.Unpack64(width, b))
+
+			if width <= bitpack.MaxSize {
+				ints32 := make([]uint32, len(ints))
+				for i, v := range ints {
+					ints32[i] = uint32(v)
+				}
+				assert.Equal(t, bitpack.Pack(nil, width, ints32), b)
+			}
+		})
+	}
+}
+
+func BenchmarkPack(b *testing.B) {
+	ints := []uint32{1, 2, 3, 4, 5, 6, 7, 8}
+	ints64 := []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+	buf := make([]byte, 0, bitpack.MaxSize64)
+	for _, width := range []int{1, 4, 12, 32} {
+		b.Run(fmt.Sprintf("unrolled width %d", width), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bitpack.Pack(buf[:0], width, ints)
+			}
+		})
+		b.Run(fmt.Sprintf("loop width %d", width), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bitpack.Pack64(buf[:0], width, ints64)
+			}
+		})
+	}
+}
+
+func BenchmarkUnpack(b *testing.B) {
+	buf := bitpack.Pack(nil, 32, []uint32{1, 2, 3, 4, 5, 6, 7, 8})
+	for _, width := range []int{1, 4, 12, 32} {
+		b.Run(fmt.Sprintf("unrolled width %d", width), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bitpack.Unpack(width, buf)
+			}
+		})
+		b.Run(fmt.Sprintf("loop width %d", width), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bitpack.Unpack64(width, buf)
+			}
+		})
+	}
+}
+
 func getBytes(vals ...string) []byte {
 	out := make([]byte, len(vals))
 	for i, s := range vals {
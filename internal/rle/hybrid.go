@@ -0,0 +1,138 @@
+package rle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/parsyl/parquet/internal/bitpack"
+)
+
+// Encode encodes vals with the RLE/bit-packing hybrid encoding.  Unlike
+// RLE.Bytes it supports widths up to 32 and the output isn't prefixed with
+// its length, which is how dictionary indices are stored in a data page.
+func Encode(width int, vals []uint32) []byte {
+	var out []byte
+	var group []uint32
+	var groups []byte
+	var n int
+
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		out = append(out, uvarint(uint64(n<<1|1))...)
+		out = append(out, groups...)
+		groups = groups[:0]
+		n = 0
+	}
+
+	for i := 0; i < len(vals); {
+		// only start a run on a group boundary so the values
+		// already in the bit-packed group stay in order.
+		if len(group) == 0 {
+			j := i + 1
+			for j < len(vals) && vals[j] == vals[i] {
+				j++
+			}
+
+			if j-i >= 8 {
+				flush()
+				out = append(out, uvarint(uint64((j-i)<<1))...)
+				out = append(out, littleEndian(vals[i], width)...)
+				i = j
+				continue
+			}
+		}
+
+		group = append(group, vals[i])
+		i++
+		if len(group) == 8 {
+			groups = bitpack.Pack(groups, width, group)
+			group = group[:0]
+			n++
+			if n == 63 {
+				flush()
+			}
+		}
+	}
+
+	if len(group) > 0 {
+		group = append(group, make([]uint32, 8-len(group))...)
+		groups = bitpack.Pack(groups, width, group)
+		n++
+	}
+	flush()
+	return out
+}
+
+// Decode decodes n values that were encoded with the RLE/bit-packing
+// hybrid encoding.  It returns the values and the number of bytes of
+// data that were read.  A run that has more values than are left to
+// decode is an error (except for the padding of the last bit-packed
+// group), so a corrupt run length can't make it decode too many.
+func Decode(data []byte, width, n int) ([]uint32, int, error) {
+	out := make([]uint32, 0, n)
+	r := bytes.NewReader(data)
+	for len(out) < n {
+		header, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		left := uint64(n - len(out))
+		if header&1 == 0 {
+			if header>>1 > left {
+				return nil, 0, fmt.Errorf("rle run of %d values is longer than the %d values that are left", header>>1, left)
+			}
+
+			b := make([]byte, (width+7)/8)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return nil, 0, fmt.Errorf("rle run is too short: %s", err)
+			}
+
+			var v uint32
+			for i, x := range b {
+				v |= uint32(x) << (8 * uint(i))
+			}
+
+			for i := 0; i < int(header>>1); i++ {
+				out = append(out, v)
+			}
+			continue
+		}
+
+		if header>>1 > (left+7)/8 {
+			return nil, 0, fmt.Errorf("bit-packed run of %d groups is longer than the %d values that are left", header>>1, left)
+		}
+
+		for i := 0; i < int(header>>1); i++ {
+			if width == 0 {
+				out = append(out, make([]uint32, 8)...)
+				continue
+			}
+
+			b := make([]byte, width)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return nil, 0, fmt.Errorf("bit-packed run is too short: %s", err)
+			}
+			out = append(out, bitpack.Unpack(width, b)...)
+		}
+	}
+
+	return out[:n], len(data) - r.Len(), nil
+}
+
+func uvarint(v uint64) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutUvarint(b, v)]
+}
+
+func littleEndian(v uint32, width int) []byte {
+	b := make([]byte, (width+7)/8)
+	for i := range b {
+		b[i] = byte(v >> (8 * uint(i)))
+	}
+	return b
+}
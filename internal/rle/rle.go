@@ -22,7 +22,7 @@ type RLE struct {
 	bitWidth      int32
 	packBuf       []byte
 	prev          uint8
-	valBuf        []uint8
+	valBuf        []uint32
 	bufCount      int
 	repeatCount   int
 	groupCount    int
@@ -39,7 +39,7 @@ func New(width int32, size int) (*RLE, error) {
 		out:           newWriteBuffer(size),
 		bitWidth:      width,
 		packBuf:       make([]byte, int(width)),
-		valBuf:        make([]uint8, 8),
+		valBuf:        make([]uint32, 8),
 		headerPointer: -1,
 	}, nil
 }
@@ -58,7 +58,7 @@ func (r *RLE) Write(value uint8) {
 		r.repeatCount = 1
 		r.prev = value
 	}
-	r.valBuf[r.bufCount] = value
+	r.valBuf[r.bufCount] = uint32(value)
 	r.bufCount++
 
 	if r.bufCount == 8 {
@@ -120,7 +120,7 @@ func (r *RLE) writeIntLittleEndianPaddedOnBitWidth(v uint8, bitWidth int32) ([]b
 	case 2:
 		return []byte{
 			byte(uint(v>>0) & 0xFF),
-			byte(uint(v>>8) & 0xFF),
+			byte(uint(v) >> 8 & 0xFF),
 		}, nil
 	default:
 		return nil, fmt.Errorf("Encountered value (%d) that requires more than 2 bytes", v)
@@ -208,7 +208,9 @@ func readRLEBitPacked(r io.Reader, header uint64, width uint8) ([]uint8, error)
 
 	var out []uint8
 	for len(rawBytes) > 0 {
-		out = append(out, bitpack.Unpack(int(width), rawBytes[:width])...)
+		for _, v := range bitpack.Unpack(int(width), rawBytes[:width]) {
+			out = append(out, uint8(v))
+		}
 		rawBytes = rawBytes[int(width):]
 	}
 
@@ -268,7 +270,7 @@ func readIntLittleEndianOnTwoBytes(in io.Reader) (uint8, error) {
 	if (b[0] | b[1]) < 0 {
 		return 0, io.EOF
 	}
-	return (uint8(b[1]) << 8) + (uint8(b[0]) << 0), nil
+	return uint8(uint16(b[1])<<8 | uint16(b[0])), nil
 }
 
 func readLEB128(r io.Reader) (uint64, error) {
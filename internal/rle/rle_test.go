@@ -2,6 +2,7 @@ package rle_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"testing"
 
@@ -123,3 +124,116 @@ func repeat(v uint8, c int) []uint8 {
 	}
 	return out
 }
+
+func TestEncodeDecode(t *testing.T) {
+	testCases := []struct {
+		name  string
+		width int
+		in    []uint32
+	}{
+		{
+			name:  "single value",
+			width: 1,
+			in:    []uint32{1},
+		},
+		{
+			name:  "rle only",
+			width: 12,
+			in:    append(repeat32(4000, 100), repeat32(5, 100)...),
+		},
+		{
+			name:  "bitpacking only",
+			width: 7,
+			in:    mod32(100, 1000),
+		},
+		{
+			name:  "more than 63 bit-packed groups",
+			width: 10,
+			in:    mod32(1000, 1003),
+		},
+		{
+			name:  "runs between groups",
+			width: 3,
+			in:    append(append(mod32(5, 13), repeat32(7, 20)...), mod32(6, 9)...),
+		},
+		{
+			name:  "full width",
+			width: 32,
+			in:    []uint32{0, 1, 1 << 31, 1<<32 - 1, 1<<32 - 1, 1<<32 - 1, 1<<32 - 1, 1<<32 - 1, 1<<32 - 1, 1<<32 - 1, 1<<32 - 1, 7},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			b := rle.Encode(tc.width, tc.in)
+			out, n, err := rle.Decode(append(b, 0xff), tc.width, len(tc.in))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.in, out)
+				assert.Equal(t, len(b), n)
+			}
+		})
+	}
+}
+
+func TestDecodeTooShort(t *testing.T) {
+	b := rle.Encode(5, mod32(20, 100))
+	_, _, err := rle.Decode(b[:len(b)-1], 5, 100)
+	assert.Error(t, err)
+}
+
+func TestDecodeTooLong(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{
+			name: "rle run",
+			data: append(uvarint(1<<40), 0x01),
+			err:  "rle run of 549755813888 values is longer than the 100 values that are left",
+		},
+		{
+			name: "bit-packed run",
+			data: uvarint(1<<41 | 1),
+			err:  "bit-packed run of 1099511627776 groups is longer than the 100 values that are left",
+		},
+		{
+			name: "after some values",
+			data: append([]byte{3 << 1, 0x01}, append(uvarint(98<<1), 0x02)...),
+			err:  "rle run of 98 values is longer than the 97 values that are left",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := rle.Decode(tc.data, 5, 100)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+
+	// the last bit-packed group is padded to 8 values
+	out, _, err := rle.Decode(rle.Encode(5, mod32(20, 100)), 5, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, mod32(20, 100), out)
+}
+
+func uvarint(v uint64) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutUvarint(b, v)]
+}
+
+func mod32(m, c int) []uint32 {
+	out := make([]uint32, c)
+	for i := range out {
+		out[i] = uint32(i % m)
+	}
+	return out
+}
+
+func repeat32(v uint32, c int) []uint32 {
+	out := make([]uint32, c)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,30 @@
+package xxhash_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/parsyl/parquet/internal/xxhash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSum64(t *testing.T) {
+	testCases := []struct {
+		in   string
+		hash uint64
+	}{
+		{in: "", hash: 0xef46db3751d8e999},
+		{in: "a", hash: 0xd24ec4f1a98c6e5b},
+		{in: "abc", hash: 0x44bc2cf5ad770999},
+		{in: "message digest", hash: 0x066ed728fceeb3be},
+		{in: "abcdefghijklmnopqrstuvwxyz", hash: 0xcfe1f278fa89835c},
+		{in: strings.Repeat("1234567890", 8), hash: 0xe04a477f19ee145d},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %d bytes", i, len(tc.in)), func(t *testing.T) {
+			assert.Equal(t, tc.hash, xxhash.Sum64([]byte(tc.in)))
+		})
+	}
+}
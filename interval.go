@@ -0,0 +1,36 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// IntervalBytes returns d as the 12 bytes that an INTERVAL column stores:
+// the number of months, days and milliseconds (each an unsigned
+// little-endian 32 bit integer).  The months are always 0, the days are
+// the whole days of d and the milliseconds are the rest (anything smaller
+// than a millisecond is dropped).  The spec's integers are unsigned, so
+// it returns an error if d is negative.
+func IntervalBytes(d time.Duration) ([12]byte, error) {
+	var out [12]byte
+	if d < 0 {
+		return out, fmt.Errorf("negative duration %s can't be stored as an INTERVAL", d)
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(d/day))
+	binary.LittleEndian.PutUint32(out[8:], uint32((d%day)/time.Millisecond))
+	return out, nil
+}
+
+// IntervalDuration returns the time.Duration of an INTERVAL (see
+// IntervalBytes).  A month doesn't have a fixed length, so each
+// month (which IntervalBytes never writes) is counted as 30 days.
+func IntervalDuration(b [12]byte) time.Duration {
+	months := binary.LittleEndian.Uint32(b[:])
+	days := binary.LittleEndian.Uint32(b[4:])
+	millis := binary.LittleEndian.Uint32(b[8:])
+	return time.Duration(int64(months)*30+int64(days))*day + time.Duration(millis)*time.Millisecond
+}
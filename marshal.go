@@ -0,0 +1,158 @@
+package parquet
+
+import "time"
+
+// A type that isn't one of the types parquetgen can write can still be
+// a field if it has a MarshalParquet method, which returns the value
+// that the field is written as, and an UnmarshalParquet method (with a
+// pointer receiver) that sets it to a value that was read.  There is a
+// pair of interfaces for each type that MarshalParquet can return, and
+// the generated code checks that a type has both of them:
+//
+//	func (m Money) MarshalParquet() int64
+//	func (m *Money) UnmarshalParquet(v int64)
+//
+//	var (
+//		_ parquet.Int64Marshaler   = (*Money)(nil)
+//		_ parquet.Int64Unmarshaler = (*Money)(nil)
+//	)
+
+// Int8Marshaler is a type that is written as an int8.
+type Int8Marshaler interface {
+	MarshalParquet() int8
+}
+
+// Int8Unmarshaler is a type that is read from an int8.
+type Int8Unmarshaler interface {
+	UnmarshalParquet(v int8)
+}
+
+// Int16Marshaler is a type that is written as an int16.
+type Int16Marshaler interface {
+	MarshalParquet() int16
+}
+
+// Int16Unmarshaler is a type that is read from an int16.
+type Int16Unmarshaler interface {
+	UnmarshalParquet(v int16)
+}
+
+// Uint8Marshaler is a type that is written as a uint8.
+type Uint8Marshaler interface {
+	MarshalParquet() uint8
+}
+
+// Uint8Unmarshaler is a type that is read from a uint8.
+type Uint8Unmarshaler interface {
+	UnmarshalParquet(v uint8)
+}
+
+// Uint16Marshaler is a type that is written as a uint16.
+type Uint16Marshaler interface {
+	MarshalParquet() uint16
+}
+
+// Uint16Unmarshaler is a type that is read from a uint16.
+type Uint16Unmarshaler interface {
+	UnmarshalParquet(v uint16)
+}
+
+// Int32Marshaler is a type that is written as an int32.
+type Int32Marshaler interface {
+	MarshalParquet() int32
+}
+
+// Int32Unmarshaler is a type that is read from an int32.
+type Int32Unmarshaler interface {
+	UnmarshalParquet(v int32)
+}
+
+// Uint32Marshaler is a type that is written as a uint32.
+type Uint32Marshaler interface {
+	MarshalParquet() uint32
+}
+
+// Uint32Unmarshaler is a type that is read from a uint32.
+type Uint32Unmarshaler interface {
+	UnmarshalParquet(v uint32)
+}
+
+// Int64Marshaler is a type that is written as an int64.
+type Int64Marshaler interface {
+	MarshalParquet() int64
+}
+
+// Int64Unmarshaler is a type that is read from an int64.
+type Int64Unmarshaler interface {
+	UnmarshalParquet(v int64)
+}
+
+// Uint64Marshaler is a type that is written as a uint64.
+type Uint64Marshaler interface {
+	MarshalParquet() uint64
+}
+
+// Uint64Unmarshaler is a type that is read from a uint64.
+type Uint64Unmarshaler interface {
+	UnmarshalParquet(v uint64)
+}
+
+// Float32Marshaler is a type that is written as a float32.
+type Float32Marshaler interface {
+	MarshalParquet() float32
+}
+
+// Float32Unmarshaler is a type that is read from a float32.
+type Float32Unmarshaler interface {
+	UnmarshalParquet(v float32)
+}
+
+// Float64Marshaler is a type that is written as a float64.
+type Float64Marshaler interface {
+	MarshalParquet() float64
+}
+
+// Float64Unmarshaler is a type that is read from a float64.
+type Float64Unmarshaler interface {
+	UnmarshalParquet(v float64)
+}
+
+// BoolMarshaler is a type that is written as a bool.
+type BoolMarshaler interface {
+	MarshalParquet() bool
+}
+
+// BoolUnmarshaler is a type that is read from a bool.
+type BoolUnmarshaler interface {
+	UnmarshalParquet(v bool)
+}
+
+// StringMarshaler is a type that is written as a string.
+type StringMarshaler interface {
+	MarshalParquet() string
+}
+
+// StringUnmarshaler is a type that is read from a string.
+type StringUnmarshaler interface {
+	UnmarshalParquet(v string)
+}
+
+// TimeMarshaler is a type that is written as a time.Time.
+type TimeMarshaler interface {
+	MarshalParquet() time.Time
+}
+
+// TimeUnmarshaler is a type that is read from a time.Time.
+type TimeUnmarshaler interface {
+	UnmarshalParquet(v time.Time)
+}
+
+// BytesMarshaler is a type that is written as a []byte.
+type BytesMarshaler interface {
+	MarshalParquet() []byte
+}
+
+// BytesUnmarshaler is a type that is read from a []byte.
+type BytesUnmarshaler interface {
+	UnmarshalParquet(v []byte)
+}
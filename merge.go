@@ -0,0 +1,265 @@
+package parquet
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// Merge writes the row groups of each of the inputs (which must all have
+// the same schema) to out as one parquet file.  The column chunks are
+// copied as they are, without being decoded and encoded again, and the
+// footer is made from the footers of the inputs with their offsets moved
+// to where everything was copied to.  Bloom filters and page indexes are
+// copied too.  The key/value metadata of the first input is kept, and so
+// are its column orders if every input has the same ones.
+//
+// The size of each input has to be known, so each one needs a Size method
+// (like a bytes.Reader or an io.SectionReader) or a Stat method (like an
+// os.File).
+func Merge(out io.Writer, inputs ...io.ReaderAt) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("no files to merge")
+	}
+
+	w := NewCountingWriter(out, 0)
+	if _, err := w.Write([]byte("PAR1")); err != nil {
+		return err
+	}
+
+	var fmd *sch.FileMetaData
+	var indexes []mergedIndex
+	for i, in := range inputs {
+		size, err := readerAtSize(in)
+		if err != nil {
+			return fmt.Errorf("input %d: %s", i, err)
+		}
+
+		r := io.NewSectionReader(in, 0, size)
+		meta, err := ReadMetaData(r)
+		if err != nil {
+			return fmt.Errorf("input %d: unable to read footer: %s", i, err)
+		}
+
+		if fmd == nil {
+			fmd = &sch.FileMetaData{
+				Version:          meta.Version,
+				Schema:           meta.Schema,
+				KeyValueMetadata: meta.KeyValueMetadata,
+				ColumnOrders:     meta.ColumnOrders,
+				CreatedBy:        thrift.StringPtr(DefaultCreatedBy),
+			}
+		} else if err := sameSchema(fmd.Schema, meta.Schema); err != nil {
+			return fmt.Errorf("input %d: %s", i, err)
+		} else if !reflect.DeepEqual(fmd.ColumnOrders, meta.ColumnOrders) {
+			// the stats of an input without column orders (or with
+			// different ones) may not be in the order they claim
+			fmd.ColumnOrders = nil
+		}
+
+		for _, rg := range meta.RowGroups {
+			idx, err := copyRowGroup(w, r, rg)
+			if err != nil {
+				return fmt.Errorf("input %d: %s", i, err)
+			}
+			indexes = append(indexes, idx...)
+			fmd.NumRows += rg.NumRows
+			fmd.RowGroups = append(fmd.RowGroups, rg)
+		}
+	}
+
+	ts := thrift.NewTSerializer()
+	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
+	if err := copyPageIndexes(w, ts, indexes); err != nil {
+		return err
+	}
+
+	buf, err := ts.Write(context.TODO(), fmd)
+	if err != nil {
+		return err
+	}
+
+	n, err := w.Write(buf)
+	if err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, uint32(n)); err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte("PAR1"))
+	return err
+}
+
+// mergedIndex is the page index of a column chunk that was copied by
+// Merge.  Its page index is copied after all of the row groups.
+type mergedIndex struct {
+	r  io.ReadSeeker
+	ch *sch.ColumnChunk
+	// old has the offsets of the column chunk's column
+	// and offset indexes in r
+	old *sch.ColumnChunk
+	// delta is how far the column chunk moved
+	delta int64
+}
+
+// copyRowGroup copies the column chunks of rg (and then their bloom
+// filters) from r to w and moves their offsets to where they were copied
+// to.  It returns the column chunks that have a page index.
+func copyRowGroup(w *CountingWriter, r *io.SectionReader, rg *sch.RowGroup) ([]mergedIndex, error) {
+	var out []mergedIndex
+	for _, ch := range rg.Columns {
+		md := ch.MetaData
+		if md == nil || ch.FilePath != nil {
+			return nil, fmt.Errorf("column chunks in other files can't be merged")
+		}
+
+		start := chunkOffset(md)
+		delta := w.Count() - start
+		if _, err := io.Copy(w, io.NewSectionReader(r, start, md.TotalCompressedSize)); err != nil {
+			return nil, err
+		}
+
+		ch.FileOffset += delta
+		md.DataPageOffset += delta
+		if o := md.GetDictionaryPageOffset(); o > 0 {
+			md.DictionaryPageOffset = thrift.Int64Ptr(o + delta)
+		}
+		if md.IndexPageOffset != nil {
+			md.IndexPageOffset = thrift.Int64Ptr(*md.IndexPageOffset + delta)
+		}
+
+		if ch.ColumnIndexOffset != nil || ch.OffsetIndexOffset != nil {
+			old := *ch
+			out = append(out, mergedIndex{r: r, ch: ch, old: &old, delta: delta})
+			ch.ColumnIndexOffset, ch.ColumnIndexLength = nil, nil
+			ch.OffsetIndexOffset, ch.OffsetIndexLength = nil, nil
+		}
+	}
+
+	for _, ch := range rg.Columns {
+		o := ch.MetaData.BloomFilterOffset
+		if o == nil {
+			continue
+		}
+
+		n, err := bloomFilterSize(r, *o)
+		if err != nil {
+			return nil, err
+		}
+
+		pos := w.Count()
+		if _, err := io.Copy(w, io.NewSectionReader(r, *o, n)); err != nil {
+			return nil, err
+		}
+		ch.MetaData.BloomFilterOffset = thrift.Int64Ptr(pos)
+	}
+	return out, nil
+}
+
+// bloomFilterSize returns the size of the bloom filter
+// (including its header) that starts at offset.
+func bloomFilterSize(r *io.SectionReader, offset int64) (int64, error) {
+	rc := &readCounter{r: io.NewSectionReader(r, offset, r.Size()-offset)}
+	hdr := sch.NewBloomFilterPageHeader()
+	if err := hdr.Read(thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: rc})); err != nil {
+		return 0, fmt.Errorf("unable to read bloom filter header: %s", err)
+	}
+	return rc.n + int64(hdr.NumBytes), nil
+}
+
+// copyPageIndexes writes the column indexes of the merged column chunks
+// followed by their offset indexes, whose page locations are moved by
+// however far their column chunk moved.
+func copyPageIndexes(w *CountingWriter, ts *thrift.TSerializer, indexes []mergedIndex) error {
+	write := func(st thrift.TStruct) (int64, int32, error) {
+		buf, err := ts.Write(context.TODO(), st)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		o := w.Count()
+		n, err := w.Write(buf)
+		return o, int32(n), err
+	}
+
+	for _, idx := range indexes {
+		ci, err := ReadColumnIndex(idx.r, idx.old)
+		if err != nil {
+			return err
+		}
+		if ci == nil {
+			continue
+		}
+
+		o, n, err := write(ci)
+		if err != nil {
+			return err
+		}
+		idx.ch.ColumnIndexOffset = thrift.Int64Ptr(o)
+		idx.ch.ColumnIndexLength = thrift.Int32Ptr(n)
+	}
+
+	for _, idx := range indexes {
+		oi, err := ReadOffsetIndex(idx.r, idx.old)
+		if err != nil {
+			return err
+		}
+		if oi == nil {
+			continue
+		}
+
+		for _, loc := range oi.PageLocations {
+			loc.Offset += idx.delta
+		}
+
+		o, n, err := write(oi)
+		if err != nil {
+			return err
+		}
+		idx.ch.OffsetIndexOffset = thrift.Int64Ptr(o)
+		idx.ch.OffsetIndexLength = thrift.Int32Ptr(n)
+	}
+	return nil
+}
+
+// sameSchema returns an error if schema b isn't the same as schema a.
+// The name of the root doesn't matter.
+func sameSchema(a, b []*sch.SchemaElement) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("schema has %d elements instead of %d", len(b), len(a))
+	}
+
+	for i := range a {
+		x, y := *a[i], *b[i]
+		if i == 0 {
+			x.Name, y.Name = "", ""
+		}
+		if !reflect.DeepEqual(x, y) {
+			return fmt.Errorf("schema element %s doesn't match %s", b[i].Name, a[i].Name)
+		}
+	}
+	return nil
+}
+
+// readerAtSize returns the size of r.
+func readerAtSize(r io.ReaderAt) (int64, error) {
+	switch x := r.(type) {
+	case interface{ Size() int64 }:
+		return x.Size(), nil
+	case interface{ Stat() (os.FileInfo, error) }:
+		fi, err := x.Stat()
+		if err != nil {
+			return 0, err
+		}
+		return fi.Size(), nil
+	}
+	return 0, fmt.Errorf("unable to get the size of a %T", r)
+}
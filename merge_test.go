@@ -0,0 +1,137 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "plain"},
+		{name: "no dictionaries", opts: []func(*ParquetWriter) error{MaxDictionarySize(0)}},
+		{name: "page index", opts: []func(*ParquetWriter) error{MaxPageSize(100), PageIndex}},
+		{name: "bloom filters", opts: []func(*ParquetWriter) error{BloomFilter("BFF", "code")}},
+	}
+
+	peeps := getPeople(5, 30)
+	for _, rg := range peeps {
+		for j := range rg {
+			rg[j].BFF = fmt.Sprintf("user%d@example.com", rg[j].ID)
+		}
+	}
+
+	write := func(rgs [][]Person, opts []func(*ParquetWriter) error) []byte {
+		var buf bytes.Buffer
+		w, err := NewParquetWriter(&buf, opts...)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+
+		for _, rg := range rgs {
+			for _, p := range rg {
+				w.Add(p)
+			}
+			assert.NoError(t, w.Write())
+		}
+		assert.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := write(peeps[:3], tc.opts)
+			b := write(peeps[3:], tc.opts)
+
+			var buf bytes.Buffer
+			if !assert.NoError(t, parquet.Merge(&buf, bytes.NewReader(a), bytes.NewReader(b))) {
+				return
+			}
+
+			data := buf.Bytes()
+			footer, err := parquet.ReadMetaData(bytes.NewReader(data))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, int64(30), footer.NumRows)
+			assert.Equal(t, 6, len(footer.RowGroups))
+			assert.NotEmpty(t, footer.ColumnOrders)
+
+			r, err := NewParquetReader(bytes.NewReader(data))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, 30, i)
+
+			for _, rg := range footer.RowGroups {
+				for _, ch := range rg.Columns {
+					oi, err := parquet.ReadOffsetIndex(bytes.NewReader(data), ch)
+					if !assert.NoError(t, err) || oi == nil {
+						continue
+					}
+					assert.Equal(t, ch.MetaData.DataPageOffset, oi.PageLocations[0].Offset)
+				}
+			}
+
+			ok, err := r.MayContain("bff", "user27@example.com")
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+
+	t.Run("different schema", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := NewMeasurementParquetWriter(&buf)
+		if !assert.NoError(t, err) {
+			return
+		}
+		w.Add(Measurement{})
+		assert.NoError(t, w.Close())
+
+		err = parquet.Merge(ioutil.Discard, bytes.NewReader(write(peeps[:1], nil)), bytes.NewReader(buf.Bytes()))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "input 1: schema")
+		}
+	})
+
+	t.Run("no column orders", func(t *testing.T) {
+		// an older writer's stats may not be in the order
+		// that the other input's column orders say
+		b := changeFooter(t, write(peeps[3:], nil), func(fmd *sch.FileMetaData) { fmd.ColumnOrders = nil })
+
+		var buf bytes.Buffer
+		if !assert.NoError(t, parquet.Merge(&buf, bytes.NewReader(write(peeps[:3], nil)), bytes.NewReader(b))) {
+			return
+		}
+
+		footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+		if assert.NoError(t, err) {
+			assert.Nil(t, footer.ColumnOrders)
+		}
+	})
+
+	t.Run("unknown size", func(t *testing.T) {
+		err := parquet.Merge(ioutil.Discard, struct{ io.ReaderAt }{bytes.NewReader(write(peeps[:1], nil))})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unable to get the size")
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// SetNoCopy makes Pages return pages whose BYTE_ARRAY values are read
+// without being copied (see Page.NoCopy).  It must be called before
+// Pages is called.
+func (m *Metadata) SetNoCopy() {
+	m.noCopy = true
+}
+
+// ByteArrays reads n PLAIN encoded BYTE_ARRAY values from r, which is
+// the reader that DoRead returns.  The values are slices of r's data
+// instead of copies of it, so they mustn't be changed.
+func ByteArrays(r io.Reader, n int) ([][]byte, error) {
+	buf, ok := r.(*bytes.Buffer)
+	if !ok {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		buf = bytes.NewBuffer(b)
+	}
+
+	out := make([][]byte, n)
+	for i := range out {
+		if buf.Len() < 4 {
+			return nil, fmt.Errorf("byte array value %d: %s", i, io.ErrUnexpectedEOF)
+		}
+
+		x := int(binary.LittleEndian.Uint32(buf.Next(4)))
+		if buf.Len() < x {
+			return nil, fmt.Errorf("byte array value %d is %d bytes, only %d are left: %s", i, x, buf.Len(), io.ErrUnexpectedEOF)
+		}
+		// the capacity is cut so that appending to a value
+		// doesn't write over the values after it
+		out[i] = buf.Next(x)[:x:x]
+	}
+	return out, nil
+}
+
+// NoCopyString returns b as a string that shares b's memory, so b
+// mustn't be changed for as long as the string is used.
+func NoCopyString(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
@@ -0,0 +1,388 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// SetPageIndex makes Footer write a ColumnIndex (the min, max and null
+// count of each page) and an OffsetIndex (where each page is) for each
+// column chunk, which FilterPages uses to skip pages.  It must be called
+// before any pages are written.
+func (m *Metadata) SetPageIndex() {
+	m.pageIndex = true
+}
+
+// pageIndex is what the page index keeps track of for each data page.
+type pageIndex struct {
+	// offset is where the page starts relative
+	// to the start of its column chunk
+	offset   int64
+	size     int32
+	firstRow int64
+	rows     int64
+	min      []byte
+	max      []byte
+	nulls    int64
+	// nullPage is true if all of the page's values are null
+	nullPage bool
+}
+
+// addPage records the page index of a data page.  It must be called
+// before the page is added to its column chunk.
+func (r *RowGroup) addPage(pth []string, size, count, rows int, stats Stats) {
+	col := strings.Join(pth, ".")
+	pi := pageIndex{
+		size: int32(size),
+		rows: int64(rows),
+		min:  stats.Min(),
+		max:  stats.Max(),
+	}
+
+	if ch, ok := r.columns[col]; ok {
+		pi.offset = ch.MetaData.TotalCompressedSize
+	}
+
+	if pages := r.pages[col]; len(pages) > 0 {
+		last := pages[len(pages)-1]
+		pi.firstRow = last.firstRow + last.rows
+	}
+
+	if n := stats.NullCount(); n != nil {
+		pi.nulls = *n
+		pi.nullPage = count > 0 && int(*n) == count
+	}
+
+	r.pages[col] = append(r.pages[col], pi)
+}
+
+// chunkPages are the pages of a column chunk that was written to the footer.
+type chunkPages struct {
+	ch    *sch.ColumnChunk
+	se    sch.SchemaElement
+	pages []pageIndex
+}
+
+// writePageIndex writes the column indexes of the chunks followed by
+// their offset indexes (starting at pos) and sets their offsets.
+func (m *Metadata) writePageIndex(w io.Writer, pos int64, chunks []chunkPages) error {
+	write := func(st thrift.TStruct) (int64, int32, error) {
+		buf, err := m.ts.Write(context.TODO(), st)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		o := pos
+		n, err := w.Write(buf)
+		pos += int64(n)
+		return o, int32(n), err
+	}
+
+	for _, c := range chunks {
+		ci := columnIndex(c.se, c.pages)
+		if ci == nil {
+			continue
+		}
+		m.truncateColumnIndex(ci, c.se)
+
+		o, n, err := write(ci)
+		if err != nil {
+			return err
+		}
+		c.ch.ColumnIndexOffset = thrift.Int64Ptr(o)
+		c.ch.ColumnIndexLength = thrift.Int32Ptr(n)
+	}
+
+	for _, c := range chunks {
+		oi := &sch.OffsetIndex{PageLocations: make([]*sch.PageLocation, len(c.pages))}
+		for i, pg := range c.pages {
+			oi.PageLocations[i] = &sch.PageLocation{
+				Offset:             c.ch.FileOffset + pg.offset,
+				CompressedPageSize: pg.size,
+				FirstRowIndex:      pg.firstRow,
+			}
+		}
+
+		o, n, err := write(oi)
+		if err != nil {
+			return err
+		}
+		c.ch.OffsetIndexOffset = thrift.Int64Ptr(o)
+		c.ch.OffsetIndexLength = thrift.Int32Ptr(n)
+	}
+	return nil
+}
+
+// columnIndex returns the ColumnIndex of a column chunk's pages, or nil
+// if one of its pages (that has a value) doesn't have a min and max or
+// the column's order is undefined.
+func columnIndex(se sch.SchemaElement, pages []pageIndex) *sch.ColumnIndex {
+	if undefinedOrder(se) {
+		return nil
+	}
+
+	ci := &sch.ColumnIndex{
+		NullPages:  make([]bool, len(pages)),
+		MinValues:  make([][]byte, len(pages)),
+		MaxValues:  make([][]byte, len(pages)),
+		NullCounts: make([]int64, len(pages)),
+	}
+
+	asc, desc := true, true
+	var prev *pageIndex
+	for i, pg := range pages {
+		ci.NullCounts[i] = pg.nulls
+		if pg.nullPage {
+			ci.NullPages[i] = true
+			ci.MinValues[i] = []byte{}
+			ci.MaxValues[i] = []byte{}
+			continue
+		}
+
+		if pg.min == nil || pg.max == nil {
+			return nil
+		}
+
+		ci.MinValues[i] = pg.min
+		ci.MaxValues[i] = pg.max
+		if prev != nil {
+			asc = asc && !less(se, pg.min, prev.min) && !less(se, pg.max, prev.max)
+			desc = desc && !less(se, prev.min, pg.min) && !less(se, prev.max, pg.max)
+		}
+		prev = &pages[i]
+	}
+
+	switch {
+	case asc:
+		ci.BoundaryOrder = sch.BoundaryOrder_ASCENDING
+	case desc:
+		ci.BoundaryOrder = sch.BoundaryOrder_DESCENDING
+	default:
+		ci.BoundaryOrder = sch.BoundaryOrder_UNORDERED
+	}
+	return ci
+}
+
+// ReadColumnIndex reads the ColumnIndex of a column chunk.  It returns
+// nil if the column chunk doesn't have one.
+func ReadColumnIndex(r io.ReadSeeker, ch *sch.ColumnChunk) (*sch.ColumnIndex, error) {
+	if ch.ColumnIndexOffset == nil {
+		return nil, nil
+	}
+
+	ci := sch.NewColumnIndex()
+	if err := readIndex(r, *ch.ColumnIndexOffset, ch.GetColumnIndexLength(), ci); err != nil {
+		return nil, fmt.Errorf("unable to read column index: %s", err)
+	}
+	return ci, nil
+}
+
+// ReadOffsetIndex reads the OffsetIndex of a column chunk.  It returns
+// nil if the column chunk doesn't have one.
+func ReadOffsetIndex(r io.ReadSeeker, ch *sch.ColumnChunk) (*sch.OffsetIndex, error) {
+	if ch.OffsetIndexOffset == nil {
+		return nil, nil
+	}
+
+	oi := sch.NewOffsetIndex()
+	if err := readIndex(r, *ch.OffsetIndexOffset, ch.GetOffsetIndexLength(), oi); err != nil {
+		return nil, fmt.Errorf("unable to read offset index: %s", err)
+	}
+	return oi, nil
+}
+
+// readIndex reads an index that starts at offset.  When its
+// length is known it is read all at once.
+func readIndex(r io.ReadSeeker, offset int64, length int32, st interface{ Read(thrift.TProtocol) error }) error {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	var rd io.Reader = r
+	if length > 0 {
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		rd = bytes.NewReader(buf)
+	}
+	return st.Read(thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: rd}))
+}
+
+// FilterPages uses the page index of rg to skip the pages that can't have
+// any rows that match all of the filters.  pages are the Pages of rg's
+// column chunks (see Pages).  It returns the pages with the locations of
+// the data pages to read and the number of rows they have.  The same rows
+// are read from each column, even when the columns' pages don't start at
+// the same rows, so some of the rows can still not match.  pages are
+// returned as they are (with all of rg's rows) if rg doesn't have an offset
+// index for each of its column chunks or no pages can be skipped.
+func (m *Metadata) FilterPages(r io.ReadSeeker, rg RowGroup, pages map[string]Page, filters ...Filter) (map[string]Page, int64, error) {
+	if len(filters) == 0 {
+		return pages, rg.Rows, nil
+	}
+
+	locations := make(map[string][]*sch.PageLocation, len(rg.Columns()))
+	for _, ch := range rg.Columns() {
+		oi, err := ReadOffsetIndex(r, ch)
+		if err != nil || oi == nil || len(oi.PageLocations) == 0 {
+			return pages, rg.Rows, err
+		}
+		locations[strings.Join(ch.MetaData.PathInSchema, ".")] = oi.PageLocations
+	}
+
+	// the row group is split into the segments between the
+	// first rows of all of the pages
+	bounds := []int64{0, rg.Rows}
+	for _, locs := range locations {
+		for _, loc := range locs {
+			bounds = append(bounds, loc.FirstRowIndex)
+		}
+	}
+	bounds = uniqueBounds(bounds)
+
+	keep := make([]bool, len(bounds)-1)
+	for i := range keep {
+		keep[i] = true
+	}
+
+	for _, f := range filters {
+		if err := m.filterSegments(r, rg, locations, bounds, keep, f); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// a page is read if it has any rows that are kept, so all of its
+	// rows (which might be in other columns' pages) are kept
+	for changed := true; changed; {
+		changed = false
+		for _, locs := range locations {
+			for i := range locs {
+				start, end := pageSegments(bounds, locs, i, rg.Rows)
+				if !anyKept(keep[start:end]) {
+					continue
+				}
+				for j := start; j < end; j++ {
+					changed = changed || !keep[j]
+					keep[j] = true
+				}
+			}
+		}
+	}
+
+	var rows int64
+	for i, k := range keep {
+		if k {
+			rows += bounds[i+1] - bounds[i]
+		}
+	}
+
+	if rows == rg.Rows {
+		return pages, rg.Rows, nil
+	}
+
+	out := make(map[string]Page, len(pages))
+	for name, pg := range pages {
+		locs := locations[name]
+		pg.Locations = nil
+		for i := range locs {
+			start, end := pageSegments(bounds, locs, i, rg.Rows)
+			if anyKept(keep[start:end]) {
+				pg.Locations = append(pg.Locations, locs[i])
+			}
+		}
+
+		// required columns have a value for each row
+		// (which is all N is used for)
+		pg.N = int(rows)
+		out[name] = pg
+	}
+	return out, rows, nil
+}
+
+// filterSegments stops keeping the segments that are in the pages of
+// the filter's column that can't match the filter.
+func (m *Metadata) filterSegments(r io.ReadSeeker, rg RowGroup, locations map[string][]*sch.PageLocation, bounds []int64, keep []bool, f Filter) error {
+	se, ok := m.schema.lookup[f.Column]
+	if !ok {
+		return fmt.Errorf("unknown filter column: %s", f.Column)
+	}
+
+	v, err := plainValue(se, f.Value)
+	if err != nil {
+		return fmt.Errorf("filter on column %s: %s", f.Column, err)
+	}
+
+	if !m.ordered(f.Column) {
+		return nil
+	}
+
+	for _, ch := range rg.Columns() {
+		if strings.Join(ch.MetaData.PathInSchema, ".") != f.Column {
+			continue
+		}
+
+		ci, err := ReadColumnIndex(r, ch)
+		if err != nil {
+			return err
+		}
+
+		locs := locations[f.Column]
+		if ci == nil || len(ci.NullPages) != len(locs) || len(ci.MinValues) != len(locs) || len(ci.MaxValues) != len(locs) {
+			continue
+		}
+
+		for i := range locs {
+			// nulls never match a filter
+			if !ci.NullPages[i] && !skip(se, f.Op, ci.MinValues[i], ci.MaxValues[i], v) {
+				continue
+			}
+
+			start, end := pageSegments(bounds, locs, i, rg.Rows)
+			for j := start; j < end; j++ {
+				keep[j] = false
+			}
+		}
+	}
+	return nil
+}
+
+// pageSegments returns the range of segments that
+// the rows of the ith page are in.
+func pageSegments(bounds []int64, locs []*sch.PageLocation, i int, rows int64) (int, int) {
+	first, last := locs[i].FirstRowIndex, rows
+	if i+1 < len(locs) {
+		last = locs[i+1].FirstRowIndex
+	}
+	start := sort.Search(len(bounds), func(j int) bool { return bounds[j] >= first })
+	end := sort.Search(len(bounds), func(j int) bool { return bounds[j] >= last })
+	return start, end
+}
+
+func anyKept(keep []bool) bool {
+	for _, k := range keep {
+		if k {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueBounds sorts the bounds and removes the duplicates.
+func uniqueBounds(bounds []int64) []int64 {
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+	out := bounds[:1]
+	for _, b := range bounds[1:] {
+		if b != out[len(out)-1] {
+			out = append(out, b)
+		}
+	}
+	return out
+}
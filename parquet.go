@@ -1,17 +1,24 @@
 // Package parquet is not intended to be used as a general library.  The
 // code generated by the 'parquetgen' command is what actually uses it for
-// reading and writing parquet files.
+// reading and writing parquet files.  The exception is FileWriter and
+// FileReader, which write and read the columns of a file directly for
+// when the schema isn't known until runtime.
 package parquet
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
+	"runtime/debug"
+	"sort"
 	"strings"
 
 	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/parsyl/parquet/internal/rle"
 	sch "github.com/parsyl/parquet/schema"
 )
 
@@ -22,6 +29,9 @@ type Field struct {
 	Types          []int
 	Type           FieldFunc
 	RepetitionType FieldFunc
+	// Groups sets the types of the groups in Path.  Groups[i]
+	// is used for Path[i] and can be nil.
+	Groups []FieldFunc
 }
 
 // Page keeps track of metadata for each ColumnChunk
@@ -31,6 +41,24 @@ type Page struct {
 	Size   int
 	Offset int64
 	Codec  sch.CompressionCodec
+	// Type is the column's physical type in the file (which is
+	// needed to decode DELTA_BINARY_PACKED pages and INT96 timestamps)
+	Type sch.Type
+	// TypeLength is the size of each value of a FIXED_LEN_BYTE_ARRAY
+	// column (which is needed to read its dictionary page)
+	TypeLength int
+	// Locations are the data pages that are read (along with the
+	// dictionary page) when only some of them are (see FilterPages).
+	Locations []*sch.PageLocation
+	// Checksums is true if the checksums of the pages
+	// are verified (see SetPageChecksums).
+	Checksums bool
+	// DataOffset is where the first data page starts (Offset is
+	// where the dictionary page starts if there is one).
+	DataOffset int64
+	// NoCopy is true if BYTE_ARRAY values are read as slices of the
+	// data that DoRead returns instead of copies (see SetNoCopy).
+	NoCopy bool
 }
 
 type schema struct {
@@ -39,38 +67,41 @@ type schema struct {
 }
 
 func (s schema) schema() (int64, []*sch.SchemaElement) {
+	var z int32
 	out := make([]*sch.SchemaElement, 0, len(s.fields)+1)
 	out = append(out, &sch.SchemaElement{
-		Name: "root",
+		Name:        "root",
+		NumChildren: new(int32),
 	})
 
-	var children int32
-	var z int32
 	m := map[string]*sch.SchemaElement{}
 	for _, f := range s.fields {
-		if len(f.Path) > 1 {
-			for i, name := range f.Path[:len(f.Path)-1] {
-				par, ok := m[name]
-				if !ok {
-					children++
-					parts := strings.Split(name, ".")
-					rt := sch.FieldRepetitionType(f.Types[i])
-					par = &sch.SchemaElement{
-						Name:           parts[len(parts)-1],
-						RepetitionType: &rt,
-						NumChildren:    &z,
-					}
-					out = append(out, par)
+		// par is the group that the field (or its
+		// next group) is a direct child of
+		par := out[0]
+		for i, name := range f.Path[:len(f.Path)-1] {
+			// groups with the same name can have different parents
+			// (like the key_value groups of two maps)
+			pth := strings.Join(f.Path[:i+1], ".")
+			grp, ok := m[pth]
+			if !ok {
+				*par.NumChildren++
+				rt := sch.FieldRepetitionType(f.Types[i])
+				grp = &sch.SchemaElement{
+					Name:           name,
+					RepetitionType: &rt,
+					NumChildren:    new(int32),
+				}
+				if i < len(f.Groups) && f.Groups[i] != nil {
+					f.Groups[i](grp)
 				}
-				n := *par.NumChildren
-				n++
-				par.NumChildren = &n
-				m[name] = par
+				out = append(out, grp)
+				m[pth] = grp
 			}
-		} else if len(f.Path) == 1 {
-			children++
+			par = grp
 		}
 
+		*par.NumChildren++
 		se := &sch.SchemaElement{
 			Name:       f.Path[len(f.Path)-1],
 			TypeLength: &z,
@@ -84,7 +115,6 @@ func (s schema) schema() (int64, []*sch.SchemaElement) {
 		out = append(out, se)
 	}
 
-	out[0].NumChildren = &children
 	return int64(len(s.fields)), out
 }
 
@@ -99,6 +129,32 @@ type Metadata struct {
 	rowGroupDocs int64
 	rowGroups    []RowGroup
 
+	// pageIndex is true if Footer writes the page index
+	pageIndex bool
+
+	// pageChecksums is true if page headers have
+	// checksums (see SetPageChecksums)
+	pageChecksums bool
+
+	// noCopy is true if BYTE_ARRAY values are
+	// read without copying them (see SetNoCopy)
+	noCopy bool
+
+	// sortingColumns are the columns that the
+	// rows of each row group are sorted by
+	sortingColumns []*sch.SortingColumn
+
+	// keyValues is the application's key/value
+	// metadata (see SetKeyValueMetadata)
+	keyValues map[string]string
+
+	// createdBy is what wrote the file (see SetCreatedBy)
+	createdBy string
+
+	// statsTruncateLength is the longest min or max of a byte
+	// array column (see SetStatsTruncateLength), 0 is no limit
+	statsTruncateLength int
+
 	metadata *sch.FileMetaData
 }
 
@@ -128,8 +184,11 @@ func New(fields ...Field) *Metadata {
 func (m *Metadata) StartRowGroup(fields ...Field) {
 	m.rowGroupDocs = 0
 	m.rowGroups = append(m.rowGroups, RowGroup{
-		fields:  schemaElements(fields),
-		columns: make(map[string]sch.ColumnChunk),
+		fields:       schemaElements(fields),
+		columns:      make(map[string]sch.ColumnChunk),
+		dictionaries: make(map[string]int64),
+		offsets:      make(map[string]int64),
+		pages:        make(map[string][]pageIndex),
 	})
 }
 
@@ -142,7 +201,9 @@ func (m *Metadata) NextDoc() {
 	m.pageDocs++
 }
 
-// RowGroups returns a summary of each schema.RowGroup
+// RowGroups returns a summary of each schema.RowGroup.  The column
+// chunks of the columns that aren't in the schema are left out (see
+// Pages).
 func (m *Metadata) RowGroups() []RowGroup {
 	rgs := make([]RowGroup, len(m.metadata.RowGroups))
 	for i, rg := range m.metadata.RowGroups {
@@ -150,30 +211,89 @@ func (m *Metadata) RowGroups() []RowGroup {
 			rowGroup: *rg,
 			Rows:     rg.NumRows,
 		}
+		rgs[i].rowGroup.Columns = m.schemaColumns(rg.Columns)
 	}
 	return rgs
 }
 
+// schemaColumns returns the column chunks of cols whose
+// columns are in the schema.
+func (m *Metadata) schemaColumns(cols []*sch.ColumnChunk) []*sch.ColumnChunk {
+	out := make([]*sch.ColumnChunk, 0, len(cols))
+	for _, ch := range cols {
+		if _, ok := m.schema.lookup[strings.Join(ch.MetaData.PathInSchema, ".")]; ok {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
 // WritePageHeader is called in order to finish writing to a column chunk.
-func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compressedLen, defCount, count int, defLen, repLen int64, comp sch.CompressionCodec, stats Stats) error {
+// page is the (compressed) data of the page that is written after the
+// header, which is only needed for its checksum (see SetPageChecksums).
+func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compressedLen, rows, count int, defLen, repLen int64, comp sch.CompressionCodec, enc sch.Encoding, stats Stats, page ...[]byte) error {
+	return m.writeDataPageHeader(w, pth, dataLen, compressedLen, rows, count, defLen, repLen, comp, enc, sch.Encoding_RLE, stats, page...)
+}
+
+// writeDataPageHeader is WritePageHeader for a page whose definition and
+// repetition levels are encoded with levelEnc.
+func (m *Metadata) writeDataPageHeader(w io.Writer, pth []string, dataLen, compressedLen, rows, count int, defLen, repLen int64, comp sch.CompressionCodec, enc, levelEnc sch.Encoding, stats Stats, page ...[]byte) error {
 	ph := &sch.PageHeader{
 		Type:                 sch.PageType_DATA_PAGE,
 		UncompressedPageSize: int32(dataLen),
 		CompressedPageSize:   int32(compressedLen),
 		DataPageHeader: &sch.DataPageHeader{
 			NumValues:               int32(count),
-			Encoding:                sch.Encoding_PLAIN,
-			DefinitionLevelEncoding: sch.Encoding_RLE,
-			RepetitionLevelEncoding: sch.Encoding_RLE,
-			Statistics: &sch.Statistics{
-				NullCount:     stats.NullCount(),
-				DistinctCount: stats.DistinctCount(),
-				MinValue:      stats.Min(),
-				MaxValue:      stats.Max(),
-			},
+			Encoding:                enc,
+			DefinitionLevelEncoding: levelEnc,
+			RepetitionLevelEncoding: levelEnc,
+			Statistics:              m.pageStatistics(pth, stats),
 		},
 	}
 
+	m.setChecksum(ph, page)
+	return m.writePageHeader(w, ph, pth, dataLen, compressedLen, count, rows, comp, enc, stats)
+}
+
+// WritePageHeaderV2 is called in order to finish writing to a column chunk
+// with a DATA_PAGE_V2 page.  Only the page's values are compressed (its
+// levels, which take up the first defLen+repLen bytes, are not).  page
+// is the same as it is for WritePageHeader.
+func (m *Metadata) WritePageHeaderV2(w io.Writer, pth []string, dataLen, compressedLen, count, nulls, rows int, defLen, repLen int64, comp sch.CompressionCodec, enc sch.Encoding, stats Stats, page ...[]byte) error {
+	ph := &sch.PageHeader{
+		Type:                 sch.PageType_DATA_PAGE_V2,
+		UncompressedPageSize: int32(dataLen),
+		CompressedPageSize:   int32(compressedLen),
+		DataPageHeaderV2: &sch.DataPageHeaderV2{
+			NumValues:                  int32(count),
+			NumNulls:                   int32(nulls),
+			NumRows:                    int32(rows),
+			Encoding:                   enc,
+			DefinitionLevelsByteLength: int32(defLen),
+			RepetitionLevelsByteLength: int32(repLen),
+			IsCompressed:               comp != sch.CompressionCodec_UNCOMPRESSED,
+			Statistics:                 m.pageStatistics(pth, stats),
+		},
+	}
+
+	m.setChecksum(ph, page)
+	return m.writePageHeader(w, ph, pth, dataLen, compressedLen, count, rows, comp, enc, stats)
+}
+
+func (m *Metadata) pageStatistics(pth []string, stats Stats) *sch.Statistics {
+	se := m.schema.lookup[strings.Join(pth, ".")]
+	st := &sch.Statistics{
+		NullCount:     stats.NullCount(),
+		DistinctCount: stats.DistinctCount(),
+	}
+	if !undefinedOrder(se) {
+		st.MinValue, st.MaxValue = stats.Min(), stats.Max()
+	}
+	m.truncateStats(st, se)
+	return st
+}
+
+func (m *Metadata) writePageHeader(w io.Writer, ph *sch.PageHeader, pth []string, dataLen, compressedLen, count, rows int, comp sch.CompressionCodec, enc sch.Encoding, stats Stats) error {
 	m.pageDocs = 0
 
 	buf, err := m.ts.Write(context.TODO(), ph)
@@ -181,7 +301,56 @@ func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compresse
 		return err
 	}
 
-	if err := m.updateRowGroup(pth, dataLen, compressedLen, len(buf), count, comp); err != nil {
+	if m.pageIndex {
+		rg, err := m.currentRowGroup()
+		if err != nil {
+			return err
+		}
+		rg.addPage(pth, compressedLen+len(buf), count, rows, stats)
+	}
+
+	if err := m.recordOffset(w, pth); err != nil {
+		return err
+	}
+
+	if err := m.updateRowGroup(pth, dataLen, compressedLen, len(buf), count, comp, enc, stats); err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf)
+	return err
+}
+
+// WriteDictionaryPageHeader is called to write the header of the dictionary
+// page that starts a column chunk.  page is the same as it is for
+// WritePageHeader.
+func (m *Metadata) WriteDictionaryPageHeader(w io.Writer, pth []string, dataLen, compressedLen, count int, comp sch.CompressionCodec, page ...[]byte) error {
+	ph := &sch.PageHeader{
+		Type:                 sch.PageType_DICTIONARY_PAGE,
+		UncompressedPageSize: int32(dataLen),
+		CompressedPageSize:   int32(compressedLen),
+		DictionaryPageHeader: &sch.DictionaryPageHeader{
+			NumValues: int32(count),
+			Encoding:  sch.Encoding_PLAIN,
+		},
+	}
+	m.setChecksum(ph, page)
+
+	buf, err := m.ts.Write(context.TODO(), ph)
+	if err != nil {
+		return err
+	}
+
+	if err := m.recordOffset(w, pth); err != nil {
+		return err
+	}
+
+	rg, err := m.currentRowGroup()
+	if err != nil {
+		return err
+	}
+
+	if err := rg.updateDictionary(pth, dataLen+len(buf), compressedLen+len(buf), count, m.schema, comp); err != nil {
 		return err
 	}
 
@@ -189,18 +358,42 @@ func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compresse
 	return err
 }
 
-func (m *Metadata) updateRowGroup(pth []string, dataLen, compressedLen, headerLen, count int, comp sch.CompressionCodec) error {
+// recordOffset records where the column chunk of pth starts if this is its
+// first page and w is a CountingWriter (see Footer).
+func (m *Metadata) recordOffset(w io.Writer, pth []string) error {
+	rg, err := m.currentRowGroup()
+	if err != nil {
+		return err
+	}
+
+	col := strings.Join(pth, ".")
+	if _, ok := rg.columns[col]; ok {
+		return nil
+	}
+
+	rg.order = append(rg.order, col)
+	if o := position(w); o >= 0 {
+		rg.offsets[col] = o
+	}
+	return nil
+}
+
+func (m *Metadata) currentRowGroup() (*RowGroup, error) {
 	i := len(m.rowGroups)
 	if i == 0 {
-		return fmt.Errorf("no row groups, you must call StartRowGroup at least once")
+		return nil, fmt.Errorf("no row groups, you must call StartRowGroup at least once")
 	}
+	return &m.rowGroups[i-1], nil
+}
 
-	rg := m.rowGroups[i-1]
+func (m *Metadata) updateRowGroup(pth []string, dataLen, compressedLen, headerLen, count int, comp sch.CompressionCodec, enc sch.Encoding, stats Stats) error {
+	rg, err := m.currentRowGroup()
+	if err != nil {
+		return err
+	}
 
 	rg.rowGroup.NumRows = m.rowGroupDocs
-	err := rg.updateColumnChunk(pth, dataLen+headerLen, compressedLen+headerLen, count, m.schema, comp)
-	m.rowGroups[i-1] = rg
-	return err
+	return rg.updateColumnChunk(pth, dataLen+headerLen, compressedLen+headerLen, count, m.schema, comp, enc, stats)
 }
 
 func columnType(col string, fields schema) (sch.Type, error) {
@@ -211,13 +404,100 @@ func columnType(col string, fields schema) (sch.Type, error) {
 	return *f.Type, nil
 }
 
+// Schema returns the schema elements that are written to the
+// footer.  The first element is the root of the schema.
+func (m *Metadata) Schema() []*sch.SchemaElement {
+	_, out := m.schema.schema()
+	return out
+}
+
 // Rows return the total number of rows that are being written
 // in to a parquet file.
 func (m *Metadata) Rows() int64 {
 	return m.metadata.NumRows
 }
 
-// Footer writes the FileMetaData at the end of the file.
+// SortedBy records that the rows of each row group are sorted by the column
+// col.  Nulls come first, or last when descending (see SortingColumn).  It
+// can be called more than once for rows that are sorted by more than one
+// column.
+func (m *Metadata) SortedBy(col string, descending bool) error {
+	for i, f := range m.schema.fields {
+		if strings.Join(f.Path, ".") != col {
+			continue
+		}
+
+		m.sortingColumns = append(m.sortingColumns, &sch.SortingColumn{
+			ColumnIdx:  int32(i),
+			Descending: descending,
+			NullsFirst: !descending,
+		})
+		return nil
+	}
+	return fmt.Errorf("unknown sorting column: %s", col)
+}
+
+// SetKeyValueMetadata makes Footer write kv as the file's key/value
+// metadata (which is where other tools keep things like the version of the
+// writer or a pandas schema).  The keys are written in order.
+func (m *Metadata) SetKeyValueMetadata(kv map[string]string) {
+	m.keyValues = kv
+}
+
+// KeyValueMetadata returns the key/value metadata of the file whose footer
+// was read.  A key without a value has an empty value.
+func (m *Metadata) KeyValueMetadata() map[string]string {
+	out := make(map[string]string, len(m.metadata.KeyValueMetadata))
+	for _, kv := range m.metadata.KeyValueMetadata {
+		out[kv.Key] = kv.GetValue()
+	}
+	return out
+}
+
+// SetCreatedBy makes Footer write s as the application that wrote the file
+// (like "myapp version 1.2.3") instead of DefaultCreatedBy.
+func (m *Metadata) SetCreatedBy(s string) {
+	m.createdBy = s
+}
+
+// CreatedBy returns the application that wrote the file whose footer was
+// read, which is empty if the footer doesn't say.
+func (m *Metadata) CreatedBy() string {
+	return m.metadata.GetCreatedBy()
+}
+
+// DefaultCreatedBy is the created_by of the files that are written when
+// SetCreatedBy isn't used.  The version is the version of this module
+// that the program was built with.
+var DefaultCreatedBy = "parsyl/parquet version " + moduleVersion()
+
+func moduleVersion() string {
+	const path = "github.com/parsyl/parquet"
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if bi.Main.Path == path {
+		return bi.Main.Version
+	}
+
+	for _, d := range bi.Deps {
+		if d.Path != path {
+			continue
+		}
+		if d.Replace != nil && d.Replace.Version != "" {
+			return d.Replace.Version
+		}
+		return d.Version
+	}
+	return "unknown"
+}
+
+// Footer writes the FileMetaData at the end of the file.  The offset of
+// each column chunk is worked out from the sizes of everything that was
+// written before it unless the file was written to a CountingWriter, which
+// knows where each one actually starts.
 func (m *Metadata) Footer(w io.Writer) error {
 	_, s := m.schema.schema()
 	fmd := &sch.FileMetaData{
@@ -225,31 +505,97 @@ func (m *Metadata) Footer(w io.Writer) error {
 		Schema:    s,
 		NumRows:   m.docs,
 		RowGroups: make([]*sch.RowGroup, 0, len(m.rowGroups)),
+		CreatedBy: thrift.StringPtr(DefaultCreatedBy),
 	}
 
+	if m.createdBy != "" {
+		fmd.CreatedBy = thrift.StringPtr(m.createdBy)
+	}
+
+	// the min and max of each column's stats are in the order of its
+	// type (like unsigned for a UINT_64, and without NaNs for a float),
+	// and readers only trust them when the column orders say so.  The
+	// column orders can't leave out a column, so the ones whose order
+	// is undefined (INTERVAL) get one too but never have a min or max.
+	for range m.schema.fields {
+		fmd.ColumnOrders = append(fmd.ColumnOrders, &sch.ColumnOrder{TYPE_ORDER: sch.NewTypeDefinedOrder()})
+	}
+
+	for k, v := range m.keyValues {
+		fmd.KeyValueMetadata = append(fmd.KeyValueMetadata, &sch.KeyValue{Key: k, Value: thrift.StringPtr(v)})
+	}
+	sort.Slice(fmd.KeyValueMetadata, func(i, j int) bool {
+		return fmd.KeyValueMetadata[i].Key < fmd.KeyValueMetadata[j].Key
+	})
+
 	pos := int64(4)
+	var pages []chunkPages
 	for _, mrg := range m.rowGroups {
 		rg := mrg.rowGroup
 		if rg.NumRows == 0 {
 			continue
 		}
-
-		for _, col := range mrg.fields.fields {
-			ch, ok := mrg.columns[strings.Join(col.Path, ".")]
+		rg.SortingColumns = m.sortingColumns
+
+		// the offsets are worked out in the order that the
+		// column chunks were written but the footer lists
+		// them in the order of the schema
+		chunks := make(map[string]*sch.ColumnChunk, len(mrg.columns))
+		for _, k := range mrg.order {
+			ch, ok := mrg.columns[k]
 			if !ok {
 				continue
 			}
 
+			if o, ok := mrg.offsets[k]; ok {
+				pos = o
+			}
 			ch.FileOffset = pos
 			ch.MetaData.DataPageOffset = pos
-			rg.TotalByteSize += ch.MetaData.TotalCompressedSize
-			rg.Columns = append(rg.Columns, &ch)
+			if n, ok := mrg.dictionaries[k]; ok {
+				ch.MetaData.DictionaryPageOffset = thrift.Int64Ptr(pos)
+				ch.MetaData.DataPageOffset = pos + n
+			}
+			chunks[k] = &ch
 			pos += ch.MetaData.TotalCompressedSize
 		}
 
+		for _, col := range mrg.fields.fields {
+			k := strings.Join(col.Path, ".")
+			ch, ok := chunks[k]
+			if !ok {
+				continue
+			}
+
+			m.truncateStats(ch.MetaData.Statistics, m.schema.lookup[k])
+			rg.TotalByteSize += ch.MetaData.TotalCompressedSize
+			rg.Columns = append(rg.Columns, ch)
+			if m.pageIndex {
+				pages = append(pages, chunkPages{ch: ch, se: m.schema.lookup[k], pages: mrg.pages[k]})
+			}
+		}
+
+		for _, bf := range mrg.bloomFilters {
+			if bf.offset >= 0 {
+				pos = bf.offset
+			}
+			if ch, ok := chunks[bf.col]; ok {
+				ch.MetaData.BloomFilterOffset = thrift.Int64Ptr(pos)
+			}
+			pos += bf.n
+		}
+
 		fmd.RowGroups = append(fmd.RowGroups, &rg)
 	}
 
+	if o := position(w); o >= 0 {
+		pos = o
+	}
+
+	if err := m.writePageIndex(w, pos, pages); err != nil {
+		return err
+	}
+
 	buf, err := m.ts.Write(context.TODO(), fmd)
 	if err != nil {
 		return err
@@ -272,6 +618,26 @@ type RowGroup struct {
 	columns  map[string]sch.ColumnChunk
 	child    *RowGroup
 
+	// dictionaries holds the size of each column
+	// chunk's dictionary page (if it has one)
+	dictionaries map[string]int64
+
+	// offsets holds where each column chunk starts
+	// when that's known (see CountingWriter)
+	offsets map[string]int64
+
+	// order holds the columns in the order that their column
+	// chunks were written, which doesn't have to be the
+	// order of the schema that the footer lists them in
+	order []string
+
+	// bloomFilters are written after the column chunks
+	bloomFilters []bloomFilterLen
+
+	// pages holds the page index of each column
+	// chunk's data pages (see Metadata.SetPageIndex)
+	pages map[string][]pageIndex
+
 	Rows int64
 }
 
@@ -280,33 +646,150 @@ func (r *RowGroup) Columns() []*sch.ColumnChunk {
 	return r.rowGroup.Columns
 }
 
-func (r *RowGroup) updateColumnChunk(pth []string, dataLen, compressedLen, count int, fields schema, comp sch.CompressionCodec) error {
+func (r *RowGroup) updateColumnChunk(pth []string, dataLen, compressedLen, count int, fields schema, comp sch.CompressionCodec, enc sch.Encoding, stats Stats) error {
 	col := strings.Join(pth, ".")
+	ch, err := r.columnChunk(pth, fields, comp)
+	if err != nil {
+		return err
+	}
 
-	ch, ok := r.columns[col]
-	if !ok {
-		t, err := columnType(col, fields)
-		if err != nil {
-			return err
-		}
+	// the null count is only known if the first data page knows it
+	if ch.MetaData.NumValues == 0 && stats.NullCount() != nil {
+		ch.MetaData.Statistics.NullCount = new(int64)
+	}
 
-		ch = sch.ColumnChunk{
-			MetaData: &sch.ColumnMetaData{
-				Type:         t,
-				Encodings:    []sch.Encoding{sch.Encoding_PLAIN},
-				PathInSchema: pth,
-				Codec:        comp,
-			},
-		}
+	if !hasEncoding(ch.MetaData.Encodings, enc) {
+		ch.MetaData.Encodings = append(ch.MetaData.Encodings, enc)
 	}
 
 	ch.MetaData.NumValues += int64(count)
 	ch.MetaData.TotalUncompressedSize += int64(dataLen)
 	ch.MetaData.TotalCompressedSize += int64(compressedLen)
+	mergeStats(ch.MetaData.Statistics, fields.lookup[col], stats)
+	r.columns[col] = ch
+	return nil
+}
+
+func (r *RowGroup) updateDictionary(pth []string, dataLen, compressedLen, count int, fields schema, comp sch.CompressionCodec) error {
+	col := strings.Join(pth, ".")
+	ch, err := r.columnChunk(pth, fields, comp)
+	if err != nil {
+		return err
+	}
+
+	// the dictionary holds each of the column chunk's distinct values
+	ch.MetaData.Statistics.DistinctCount = thrift.Int64Ptr(int64(count))
+	ch.MetaData.TotalUncompressedSize += int64(dataLen)
+	ch.MetaData.TotalCompressedSize += int64(compressedLen)
+	r.dictionaries[col] = int64(compressedLen)
 	r.columns[col] = ch
 	return nil
 }
 
+// columnChunk returns the column chunk for pth, creating it if this
+// is the first page written to it.
+func (r *RowGroup) columnChunk(pth []string, fields schema, comp sch.CompressionCodec) (sch.ColumnChunk, error) {
+	col := strings.Join(pth, ".")
+	if ch, ok := r.columns[col]; ok {
+		return ch, nil
+	}
+
+	t, err := columnType(col, fields)
+	if err != nil {
+		return sch.ColumnChunk{}, err
+	}
+
+	return sch.ColumnChunk{
+		MetaData: &sch.ColumnMetaData{
+			Type:         t,
+			Encodings:    []sch.Encoding{sch.Encoding_PLAIN},
+			PathInSchema: pth,
+			Codec:        comp,
+			Statistics:   &sch.Statistics{},
+		},
+	}, nil
+}
+
+func hasEncoding(encs []sch.Encoding, enc sch.Encoding) bool {
+	for _, e := range encs {
+		if e == enc {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeStats folds the stats of a single page into the
+// stats of the column chunk the page belongs to.
+func mergeStats(st *sch.Statistics, se sch.SchemaElement, stats Stats) {
+	if n := stats.NullCount(); n != nil && st.NullCount != nil {
+		*st.NullCount += *n
+	}
+
+	if undefinedOrder(se) {
+		return
+	}
+
+	if min := stats.Min(); min != nil && (st.MinValue == nil || less(se, min, st.MinValue)) {
+		st.MinValue = min
+	}
+
+	if max := stats.Max(); max != nil && (st.MaxValue == nil || less(se, st.MaxValue, max)) {
+		st.MaxValue = max
+	}
+}
+
+// less compares two plain encoded values using the sort
+// order of the column's type.
+func less(se sch.SchemaElement, a, b []byte) bool {
+	unsigned := isUnsigned(se)
+	switch se.GetType() {
+	case sch.Type_INT32:
+		x, y := binary.LittleEndian.Uint32(a), binary.LittleEndian.Uint32(b)
+		if unsigned {
+			return x < y
+		}
+		return int32(x) < int32(y)
+	case sch.Type_INT64:
+		x, y := binary.LittleEndian.Uint64(a), binary.LittleEndian.Uint64(b)
+		if unsigned {
+			return x < y
+		}
+		return int64(x) < int64(y)
+	case sch.Type_FLOAT:
+		return math.Float32frombits(binary.LittleEndian.Uint32(a)) < math.Float32frombits(binary.LittleEndian.Uint32(b))
+	case sch.Type_DOUBLE:
+		return math.Float64frombits(binary.LittleEndian.Uint64(a)) < math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case sch.Type_FIXED_LEN_BYTE_ARRAY, sch.Type_BYTE_ARRAY:
+		if se.GetConvertedType() == sch.ConvertedType_DECIMAL {
+			return DecimalLess(a, b)
+		}
+		if lt := se.LogicalType; lt != nil && lt.FLOAT16 != nil && len(a) == 2 && len(b) == 2 {
+			return Float16Less(binary.LittleEndian.Uint16(a), binary.LittleEndian.Uint16(b))
+		}
+		return bytes.Compare(a, b) < 0
+	default:
+		return bytes.Compare(a, b) < 0
+	}
+}
+
+// undefinedOrder returns true if the spec doesn't define the sort
+// order of the column described by se, so its min and max can't be
+// written or trusted.
+func undefinedOrder(se sch.SchemaElement) bool {
+	return se.GetType() == sch.Type_INT96 || (se.ConvertedType != nil && *se.ConvertedType == sch.ConvertedType_INTERVAL)
+}
+
+// isUnsigned returns true if the column described
+// by se is an unsigned INT32 or INT64.
+func isUnsigned(se sch.SchemaElement) bool {
+	switch se.GetConvertedType() {
+	case sch.ConvertedType_UINT_8, sch.ConvertedType_UINT_16, sch.ConvertedType_UINT_32, sch.ConvertedType_UINT_64:
+		return true
+	}
+	return false
+}
+
 func schemaElements(fields []Field) schema {
 	m := make(map[string]sch.SchemaElement)
 	for _, f := range fields {
@@ -327,25 +810,85 @@ func schemaElements(fields []Field) schema {
 	return schema{lookup: m, fields: fields}
 }
 
-// Pages maps each column name to its Pages
+// CheckColumns returns an error if the file read by ReadFooter doesn't
+// have a column for one of the schema's required fields (the ones that
+// aren't in an optional or repeated group either).  The other fields that
+// it doesn't have a column for (like the fields that were added to a
+// struct after the file was written) are read as nulls, so a schema can
+// be read from the files written with an older version of it.
+func (m *Metadata) CheckColumns() error {
+	cols := fileColumns(m.metadata.Schema)
+	for _, f := range m.schema.fields {
+		k := strings.Join(f.Path, ".")
+		if _, ok := cols[k]; ok {
+			continue
+		}
+
+		required := true
+		for _, t := range f.Types {
+			if RepetitionType(t) != Required {
+				required = false
+			}
+		}
+
+		if required {
+			return fmt.Errorf("the file doesn't have a column for required field %s", k)
+		}
+	}
+	return nil
+}
+
+// fileColumns maps the names of the columns (the leaves) of a file's
+// schema to their index, which is where they are in its column orders.
+func fileColumns(elements []*sch.SchemaElement) map[string]int {
+	out := map[string]int{}
+	if len(elements) == 0 {
+		return out
+	}
+
+	i := 1
+	var walk func(pth []string, n int32)
+	walk = func(pth []string, n int32) {
+		for j := int32(0); j < n && i < len(elements); j++ {
+			se := elements[i]
+			i++
+			p := append(pth[:len(pth):len(pth)], se.Name)
+			if se.GetNumChildren() == 0 {
+				out[strings.Join(p, ".")] = len(out)
+				continue
+			}
+			walk(p, se.GetNumChildren())
+		}
+	}
+
+	walk(nil, elements[0].GetNumChildren())
+	return out
+}
+
+// Pages maps each column name to its Pages.  The columns that the file
+// has but the schema doesn't (like the fields that were removed from a
+// struct after the file was written) are left out, so their column
+// chunks are never read.
 func (m *Metadata) Pages() (map[string][]Page, error) {
 	if len(m.metadata.RowGroups) == 0 {
 		return nil, nil
 	}
 	out := map[string][]Page{}
 	for _, rg := range m.metadata.RowGroups {
-		for _, ch := range rg.Columns {
+		for _, ch := range m.schemaColumns(rg.Columns) {
 			pth := ch.MetaData.PathInSchema
-			_, ok := m.schema.lookup[strings.Join(pth, ".")]
-			if !ok {
-				return nil, fmt.Errorf("could not find schema for %v", pth)
-			}
+			se := m.schema.lookup[strings.Join(pth, ".")]
 
 			pg := Page{
-				N:      int(ch.MetaData.NumValues),
-				Offset: ch.FileOffset,
-				Size:   int(ch.MetaData.TotalCompressedSize),
-				Codec:  ch.MetaData.Codec,
+				N:          int(ch.MetaData.NumValues),
+				Offset:     chunkOffset(ch.MetaData),
+				DataOffset: ch.MetaData.DataPageOffset,
+				Checksums:  m.pageChecksums,
+				NoCopy:     m.noCopy,
+				Size:       int(ch.MetaData.TotalCompressedSize),
+				Codec:      ch.MetaData.Codec,
+				Type:       ch.MetaData.Type,
+				TypeLength: int(se.GetTypeLength()),
 			}
 			k := strings.Join(pth, ".")
 			out[k] = append(out[k], pg)
@@ -354,9 +897,21 @@ func (m *Metadata) Pages() (map[string][]Page, error) {
 	return out, nil
 }
 
-// ReadMetaData reads the FileMetaData from the end of a parquet file
+// chunkOffset returns the offset of the first page of a column chunk.
+// ColumnChunk.FileOffset can't be used for that because some writers
+// (like parquet-cpp) set it to the end of the column chunk.
+func chunkOffset(md *sch.ColumnMetaData) int64 {
+	if o := md.GetDictionaryPageOffset(); o > 0 && o < md.DataPageOffset {
+		return o
+	}
+	return md.DataPageOffset
+}
+
+// ReadMetaData reads the FileMetaData from the end of a parquet file.  Its
+// length (just before the PAR1 at the end) is read first and then the
+// FileMetaData is read all at once, which is one range request when r
+// reads from an object store.
 func ReadMetaData(r io.ReadSeeker) (*sch.FileMetaData, error) {
-	p := thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: r})
 	size, err := getMetaDataSize(r)
 	if err != nil {
 		return nil, err
@@ -367,6 +922,12 @@ func ReadMetaData(r io.ReadSeeker) (*sch.FileMetaData, error) {
 		return nil, err
 	}
 
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	p := thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: bytes.NewReader(buf)})
 	m := sch.NewFileMetaData()
 	return m, m.Read(p)
 }
@@ -432,7 +993,8 @@ func PageHeadersAtOffset(r io.ReadSeeker, o, n int64) ([]sch.PageHeader, error)
 			return nil, fmt.Errorf("unable to seek to next page: %s", err)
 		}
 
-		nRead += int64(ph.DataPageHeader.NumValues)
+		n, _ := dataPageHeader(ph)
+		nRead += int64(n)
 	}
 	return out, nil
 }
@@ -458,8 +1020,61 @@ func RepetitionRepeated(se *sch.SchemaElement) {
 	se.RepetitionType = &t
 }
 
+// MapGroup annotates a group as a MAP.  The group must have one
+// repeated group (named key_value) with a required key field and
+// a value field.
+func MapGroup(se *sch.SchemaElement) {
+	ct := sch.ConvertedType_MAP
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		MAP: sch.NewMapType(),
+	}
+}
+
 var fieldFuncs = []FieldFunc{RepetitionRequired, RepetitionOptional, RepetitionRepeated}
 
+// encodeBools encodes vals with whichever of PLAIN (bit-packed)
+// or RLE encoding is smaller.
+func encodeBools(vals []bool) ([]byte, sch.Encoding) {
+	plain := make([]byte, (len(vals)+7)/8)
+	enc, _ := rle.New(1, len(vals))
+	for i, v := range vals {
+		if v {
+			plain[i/8] |= 1 << uint(i%8)
+			enc.Write(1)
+		} else {
+			enc.Write(0)
+		}
+	}
+
+	if b := enc.Bytes(); len(b) < len(plain) {
+		return b, sch.Encoding_RLE
+	}
+	return plain, sch.Encoding_PLAIN
+}
+
+// decodeBools turns n RLE encoded bools back into plain
+// encoded bools so they can be read by GetBools.
+func decodeBools(data []byte, n int) ([]byte, error) {
+	dec, _ := rle.New(1, 0)
+	vals, _, err := dec.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vals) < n {
+		return nil, fmt.Errorf("expected %d rle encoded bools, got %d", n, len(vals))
+	}
+
+	out := make([]byte, (n+7)/8)
+	for i, v := range vals[:n] {
+		if v == 1 {
+			out[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return out, nil
+}
+
 // GetBools reads a byte array and turns each bit into a bool
 func GetBools(r io.Reader, n int, pageSizes []int) ([]bool, error) {
 	var vals [8]bool
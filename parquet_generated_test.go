@@ -3,11 +3,15 @@ package parquet_test
 // Code generated by github.com/parsyl/parquet.  DO NOT EDIT.
 
 import (
+	"context"
 	"encoding/binary"
+	"encoding/csv"
 	"fmt"
 	"io"
 	"math"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/parsyl/parquet"
 	sch "github.com/parsyl/parquet/schema"
@@ -22,6 +26,8 @@ const (
 	compressionUncompressed compression = 0
 	compressionSnappy       compression = 1
 	compressionGzip         compression = 2
+	compressionZstd         compression = 3
+	compressionLz4Raw       compression = 4
 	compressionUnknown      compression = -1
 )
 
@@ -43,32 +49,113 @@ type ParquetWriter struct {
 	meta        *parquet.Metadata
 	w           io.Writer
 	compression compression
+	// level is the compression level (only used by zstd)
+	level int
+
+	// maxDictionarySize is the largest dictionary a column chunk
+	// can have before it is plain encoded (0 turns dictionaries off)
+	maxDictionarySize int
+
+	// deltaBinaryPacked is true if integer columns are
+	// written with DELTA_BINARY_PACKED encoding
+	deltaBinaryPacked bool
+
+	// byteArrayEncoding is the encoding of string and []byte columns
+	// (DELTA_LENGTH_BYTE_ARRAY, DELTA_BYTE_ARRAY or PLAIN, which means
+	// they are dictionary encoded when they can be)
+	byteArrayEncoding sch.Encoding
+
+	// dataPageV2 is true if pages are written as
+	// DATA_PAGE_V2 instead of DATA_PAGE
+	dataPageV2 bool
+
+	// bloomFilters holds the columns that get a bloom
+	// filter in each row group
+	bloomFilters map[string]bool
+
+	// columnOrder holds the columns (see ColumnOrder) whose column
+	// chunks are written first in each row group
+	columnOrder []string
+
+	// failFast is true if WriteAll stops at the
+	// first row that can't be written (see FailFast)
+	failFast bool
+
+	// pageIndex is true if the footer has a
+	// column index and offset index for each
+	// column chunk
+	pageIndex bool
+
+	// pageChecksums is true if each page header
+	// has the CRC32 of its page's data
+	pageChecksums bool
+
+	// statsTruncateLength is the longest that the min and
+	// max of a string or []byte column can be (0 is no limit)
+	statsTruncateLength int
+
+	// sortingColumns are the columns (see SortedBy)
+	// that the rows of each row group are sorted by
+	sortingColumns []sortingColumn
+
+	// keyValueMetadata is written in the
+	// footer (see SetMetadata)
+	keyValueMetadata map[string]string
+
+	// createdBy is written in the footer (see SetCreatedBy)
+	createdBy string
+
+	// maxRowGroupRows is the number of rows Add adds to a row
+	// group before writing it (0 means only Write writes it)
+	maxRowGroupRows int
+	rowGroupRows    int
+
+	// ctx (see WriteContext) is checked before
+	// each row group and the footer are written
+	ctx context.Context
+
+	// err is an error from a row group written by Add
+	err error
 }
 
-func Fields(compression compression) []Field {
+func Fields(compression compression, level int) []Field {
 	return []Field{
-		NewInt32Field(readID, writeID, []string{"id"}, fieldCompression(compression)),
-		NewStringField(readName, writeName, []string{"name"}, fieldCompression(compression)),
-		NewInt32OptionalField(readAge, writeAge, []string{"age"}, []int{1}, optionalFieldCompression(compression)),
-		NewInt64Field(readHappiness, writeHappiness, []string{"happiness"}, fieldCompression(compression)),
-		NewInt64OptionalField(readSadness, writeSadness, []string{"sadness"}, []int{1}, optionalFieldCompression(compression)),
-		NewStringOptionalField(readCode, writeCode, []string{"code"}, []int{1}, optionalFieldCompression(compression)),
-		NewFloat32Field(readFunkiness, writeFunkiness, []string{"funkiness"}, fieldCompression(compression)),
-		NewFloat64Field(readBoldness, writeBoldness, []string{"boldness"}, fieldCompression(compression)),
-		NewFloat32OptionalField(readLameness, writeLameness, []string{"lameness"}, []int{1}, optionalFieldCompression(compression)),
-		NewBoolOptionalField(readKeen, writeKeen, []string{"keen"}, []int{1}, optionalFieldCompression(compression)),
-		NewUint32Field(readBirthday, writeBirthday, []string{"birthday"}, fieldCompression(compression)),
-		NewUint64OptionalField(readAnniversary, writeAnniversary, []string{"anniversary"}, []int{1}, optionalFieldCompression(compression)),
-		NewStringField(readBFF, writeBFF, []string{"bff"}, fieldCompression(compression)),
-		NewBoolField(readHungry, writeHungry, []string{"hungry"}, fieldCompression(compression)),
-		NewStringOptionalField(readHobbyName, writeHobbyName, []string{"hobby", "name"}, []int{1, 0}, optionalFieldCompression(compression)),
-		NewInt32OptionalField(readHobbyDifficulty, writeHobbyDifficulty, []string{"hobby", "difficulty"}, []int{1, 1}, optionalFieldCompression(compression)),
-		NewStringOptionalField(readHobbySkillsName, writeHobbySkillsName, []string{"hobby", "skills", "name"}, []int{1, 2, 0}, optionalFieldCompression(compression)),
-		NewStringOptionalField(readHobbySkillsDifficulty, writeHobbySkillsDifficulty, []string{"hobby", "skills", "difficulty"}, []int{1, 2, 0}, optionalFieldCompression(compression)),
-		NewInt32OptionalField(readFriendsID, writeFriendsID, []string{"friends", "id"}, []int{2, 0}, optionalFieldCompression(compression)),
-		NewStringOptionalField(readFriendsName, writeFriendsName, []string{"friends", "name"}, []int{2, 0}, optionalFieldCompression(compression)),
-		NewInt32OptionalField(readFriendsAge, writeFriendsAge, []string{"friends", "age"}, []int{2, 1}, optionalFieldCompression(compression)),
-		NewBoolField(readSleepy, writeSleepy, []string{"Sleepy"}, fieldCompression(compression)),
+		NewInt32Field(readID, writeID, []string{"id"}, fieldCompression(compression, level)),
+		NewStringField(readName, writeName, []string{"name"}, fieldCompression(compression, level)),
+		NewInt32OptionalField(readAge, writeAge, []string{"age"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewInt64Field(readHappiness, writeHappiness, []string{"happiness"}, fieldCompression(compression, level)),
+		NewInt64OptionalField(readSadness, writeSadness, []string{"sadness"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewStringOptionalField(readCode, writeCode, []string{"code"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewFloat32Field(readFunkiness, writeFunkiness, []string{"funkiness"}, fieldCompression(compression, level)),
+		NewFloat64Field(readBoldness, writeBoldness, []string{"boldness"}, fieldCompression(compression, level)),
+		NewFloat32OptionalField(readLameness, writeLameness, []string{"lameness"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewBoolOptionalField(readKeen, writeKeen, []string{"keen"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewUint32Field(readBirthday, writeBirthday, []string{"birthday"}, fieldCompression(compression, level)),
+		NewUint64OptionalField(readAnniversary, writeAnniversary, []string{"anniversary"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewStringField(readBFF, writeBFF, []string{"bff"}, fieldCompression(compression, level)),
+		NewBoolField(readHungry, writeHungry, []string{"hungry"}, fieldCompression(compression, level)),
+		NewStringOptionalField(readHobbyName, writeHobbyName, []string{"hobby", "name"}, []int{1, 0}, optionalFieldCompression(compression, level)),
+		NewInt32OptionalField(readHobbyDifficulty, writeHobbyDifficulty, []string{"hobby", "difficulty"}, []int{1, 1}, optionalFieldCompression(compression, level)),
+		NewStringOptionalField(readHobbySkillsName, writeHobbySkillsName, []string{"hobby", "skills", "name"}, []int{1, 2, 0}, optionalFieldCompression(compression, level)),
+		NewStringOptionalField(readHobbySkillsDifficulty, writeHobbySkillsDifficulty, []string{"hobby", "skills", "difficulty"}, []int{1, 2, 0}, optionalFieldCompression(compression, level)),
+		NewInt32OptionalField(readFriendsID, writeFriendsID, []string{"friends", "id"}, []int{2, 0}, optionalFieldCompression(compression, level)),
+		NewStringOptionalField(readFriendsName, writeFriendsName, []string{"friends", "name"}, []int{2, 0}, optionalFieldCompression(compression, level)),
+		NewInt32OptionalField(readFriendsAge, writeFriendsAge, []string{"friends", "age"}, []int{2, 1}, optionalFieldCompression(compression, level)),
+		NewBoolField(readSleepy, writeSleepy, []string{"Sleepy"}, fieldCompression(compression, level)),
+		NewInt8Field(readLevel, writeLevel, []string{"level"}, fieldCompression(compression, level)),
+		NewInt16OptionalField(readCount, writeCount, []string{"count"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewTimestampField(readCreated, writeCreated, []string{"created"}, fieldCompression(compression, level)),
+		NewTimestampOptionalField(readDeleted, writeDeleted, []string{"deleted"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewDateOptionalField(readGraduated, writeGraduated, []string{"graduated"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewBytesField(readPayload, writePayload, []string{"payload"}, fieldCompression(compression, level)),
+		NewBytesOptionalField(readThumbnail, writeThumbnail, []string{"thumbnail"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewUUIDField(readUUID, writeUUID, []string{"uuid"}, fieldCompression(compression, level)),
+		NewFixedLenByteArray8OptionalField(readChecksum, writeChecksum, []string{"checksum"}, []int{1}, optionalFieldCompression(compression, level)),
+		NewStringOptionalField(readTags, writeTags, []string{"tags"}, []int{2}, optionalFieldCompression(compression, level)),
+		NewInt32OptionalField(readScores, writeScores, []string{"scores"}, []int{2}, optionalFieldCompression(compression, level)),
+		NewStringOptionalField(readHomeStreet, writeHomeStreet, []string{"home", "street"}, []int{1, 0}, optionalFieldCompression(compression, level)),
+		NewFloat64OptionalField(readHomeGeoLat, writeHomeGeoLat, []string{"home", "geo", "lat"}, []int{1, 1, 0}, optionalFieldCompression(compression, level)),
+		NewFloat64OptionalField(readHomeGeoLon, writeHomeGeoLon, []string{"home", "geo", "lon"}, []int{1, 1, 1}, optionalFieldCompression(compression, level)),
 	}
 }
 
@@ -566,1348 +653,4839 @@ func writeSleepy(x *Person, vals []bool) {
 	x.Sleepy = vals[0]
 }
 
-func fieldCompression(c compression) func(*parquet.RequiredField) {
-	switch c {
-	case compressionUncompressed:
-		return parquet.RequiredFieldUncompressed
-	case compressionSnappy:
-		return parquet.RequiredFieldSnappy
-	case compressionGzip:
-		return parquet.RequiredFieldGzip
+func readLevel(x Person) int8 {
+	return x.Level
+}
+
+func writeLevel(x *Person, vals []int8) {
+	x.Level = vals[0]
+}
+
+func readCount(x Person, vals []int16, defs, reps []uint8) ([]int16, []uint8, []uint8) {
+	switch {
+	case x.Count == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
 	default:
-		return parquet.RequiredFieldUncompressed
+		vals = append(vals, *x.Count)
+		defs = append(defs, 1)
+		return vals, defs, reps
 	}
 }
 
-func optionalFieldCompression(c compression) func(*parquet.OptionalField) {
-	switch c {
-	case compressionUncompressed:
-		return parquet.OptionalFieldUncompressed
-	case compressionSnappy:
-		return parquet.OptionalFieldSnappy
-	case compressionGzip:
-		return parquet.OptionalFieldGzip
-	default:
-		return parquet.OptionalFieldUncompressed
+func writeCount(x *Person, vals []int16, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Count = pint16(vals[0])
+		return 1, 1
 	}
+
+	return 0, 1
 }
 
-func NewParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
-	return newParquetWriter(w, append(opts, begin)...)
+func readCreated(x Person) time.Time {
+	return x.Created
 }
 
-func newParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
-	p := &ParquetWriter{
-		max:         1000,
-		w:           w,
-		compression: compressionSnappy,
-	}
+func writeCreated(x *Person, vals []time.Time) {
+	x.Created = vals[0]
+}
 
-	for _, opt := range opts {
-		if err := opt(p); err != nil {
-			return nil, err
-		}
+func readDeleted(x Person, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8) {
+	switch {
+	case x.Deleted == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	default:
+		vals = append(vals, *x.Deleted)
+		defs = append(defs, 1)
+		return vals, defs, reps
 	}
+}
 
-	p.fields = Fields(p.compression)
-	if p.meta == nil {
-		ff := Fields(p.compression)
-		schema := make([]parquet.Field, len(ff))
-		for i, f := range ff {
-			schema[i] = f.Schema()
-		}
-		p.meta = parquet.New(schema...)
+func writeDeleted(x *Person, vals []time.Time, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Deleted = ptime(vals[0])
+		return 1, 1
 	}
 
-	return p, nil
+	return 0, 1
 }
 
-// MaxPageSize is the maximum number of rows in each row groups' page.
-func MaxPageSize(m int) func(*ParquetWriter) error {
-	return func(p *ParquetWriter) error {
-		p.max = m
-		return nil
+func readGraduated(x Person, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8) {
+	switch {
+	case x.Graduated == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	default:
+		vals = append(vals, *x.Graduated)
+		defs = append(defs, 1)
+		return vals, defs, reps
 	}
 }
 
-var par1 = []byte("PAR1")
+func writeGraduated(x *Person, vals []time.Time, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Graduated = ptime(vals[0])
+		return 1, 1
+	}
 
-func begin(p *ParquetWriter) error {
-	_, err := p.w.Write(par1)
-	return err
+	return 0, 1
 }
 
-func withMeta(m *parquet.Metadata) func(*ParquetWriter) error {
-	return func(p *ParquetWriter) error {
-		p.meta = m
-		return nil
+func readPayload(x Person) []byte {
+	return x.Payload
+}
+
+func writePayload(x *Person, vals [][]byte) {
+	x.Payload = vals[0]
+}
+
+func readThumbnail(x Person, vals [][]byte, defs, reps []uint8) ([][]byte, []uint8, []uint8) {
+	switch {
+	case x.Thumbnail == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	default:
+		vals = append(vals, x.Thumbnail)
+		defs = append(defs, 1)
+		return vals, defs, reps
 	}
 }
 
-func Uncompressed(p *ParquetWriter) error {
-	p.compression = compressionUncompressed
-	return nil
+func writeThumbnail(x *Person, vals [][]byte, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Thumbnail = vals[0]
+		return 1, 1
+	}
+
+	return 0, 1
 }
 
-func Snappy(p *ParquetWriter) error {
-	p.compression = compressionSnappy
-	return nil
+func readUUID(x Person) [16]byte {
+	return x.UUID
 }
 
-func Gzip(p *ParquetWriter) error {
-	p.compression = compressionGzip
-	return nil
+func writeUUID(x *Person, vals [][16]byte) {
+	x.UUID = vals[0]
 }
 
-func withCompression(c compression) func(*ParquetWriter) error {
-	return func(p *ParquetWriter) error {
-		p.compression = c
-		return nil
+func readChecksum(x Person, vals [][8]byte, defs, reps []uint8) ([][8]byte, []uint8, []uint8) {
+	switch {
+	case x.Checksum == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	default:
+		vals = append(vals, *x.Checksum)
+		defs = append(defs, 1)
+		return vals, defs, reps
 	}
 }
 
-func (p *ParquetWriter) Write() error {
-	for i, f := range p.fields {
-		if err := f.Write(p.w, p.meta); err != nil {
-			return err
-		}
+func writeChecksum(x *Person, vals [][8]byte, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Checksum = pfixedlenbytearray8(vals[0])
+		return 1, 1
+	}
 
-		for child := p.child; child != nil; child = child.child {
-			if err := child.fields[i].Write(p.w, p.meta); err != nil {
-				return err
+	return 0, 1
+}
+
+func readTags(x Person, vals []string, defs, reps []uint8) ([]string, []uint8, []uint8) {
+	var lastRep uint8
+
+	if len(x.Tags) == 0 {
+		defs = append(defs, 0)
+		reps = append(reps, lastRep)
+	} else {
+		for i0, x0 := range x.Tags {
+			if i0 >= 1 {
+				lastRep = 1
 			}
+			defs = append(defs, 1)
+			reps = append(reps, lastRep)
+			vals = append(vals, x0)
 		}
 	}
 
-	p.fields = Fields(p.compression)
-	p.child = nil
-	p.len = 0
+	return vals, defs, reps
+}
 
-	schema := make([]parquet.Field, len(p.fields))
-	for i, f := range p.fields {
-		schema[i] = f.Schema()
+func writeTags(x *Person, vals []string, defs, reps []uint8) (int, int) {
+	var nVals, nLevels int
+	ind := make(indices, 1)
+
+	for i := range defs {
+		def := defs[i]
+		rep := reps[i]
+		if i > 0 && rep == 0 {
+			break
+		}
+
+		nLevels++
+		ind.rep(rep)
+
+		switch def {
+		case 1:
+			x.Tags = append(x.Tags, vals[nVals])
+			nVals++
+		}
 	}
-	p.meta.StartRowGroup(schema...)
-	return nil
+
+	return nVals, nLevels
 }
 
-func (p *ParquetWriter) Close() error {
-	if err := p.meta.Footer(p.w); err != nil {
-		return err
+func readScores(x Person, vals []int32, defs, reps []uint8) ([]int32, []uint8, []uint8) {
+	var lastRep uint8
+
+	if len(x.Scores) == 0 {
+		defs = append(defs, 0)
+		reps = append(reps, lastRep)
+	} else {
+		for i0, x0 := range x.Scores {
+			if i0 >= 1 {
+				lastRep = 1
+			}
+			defs = append(defs, 1)
+			reps = append(reps, lastRep)
+			vals = append(vals, x0)
+		}
 	}
 
-	_, err := p.w.Write(par1)
-	return err
+	return vals, defs, reps
 }
 
-func (p *ParquetWriter) Add(rec Person) {
-	if p.len == p.max {
-		if p.child == nil {
-			// an error can't happen here
-			p.child, _ = newParquetWriter(p.w, MaxPageSize(p.max), withMeta(p.meta), withCompression(p.compression))
+func writeScores(x *Person, vals []int32, defs, reps []uint8) (int, int) {
+	var nVals, nLevels int
+	ind := make(indices, 1)
+
+	for i := range defs {
+		def := defs[i]
+		rep := reps[i]
+		if i > 0 && rep == 0 {
+			break
 		}
 
-		p.child.Add(rec)
-		return
-	}
+		nLevels++
+		ind.rep(rep)
 
-	p.meta.NextDoc()
-	for _, f := range p.fields {
-		f.Add(rec)
+		switch def {
+		case 1:
+			x.Scores = append(x.Scores, vals[nVals])
+			nVals++
+		}
 	}
 
-	p.len++
-}
-
-type Field interface {
-	Add(r Person)
-	Write(w io.Writer, meta *parquet.Metadata) error
-	Schema() parquet.Field
-	Scan(r *Person)
-	Read(r io.ReadSeeker, pg parquet.Page) error
-	Name() string
-	Levels() ([]uint8, []uint8)
+	return nVals, nLevels
 }
 
-func getFields(ff []Field) map[string]Field {
-	m := make(map[string]Field, len(ff))
-	for _, f := range ff {
-		m[f.Name()] = f
+func readHomeStreet(x Person, vals []string, defs, reps []uint8) ([]string, []uint8, []uint8) {
+	switch {
+	case x.Home == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	default:
+		vals = append(vals, x.Home.Street)
+		defs = append(defs, 1)
+		return vals, defs, reps
 	}
-	return m
 }
 
-func NewParquetReader(r io.ReadSeeker, opts ...func(*ParquetReader)) (*ParquetReader, error) {
-	ff := Fields(compressionUnknown)
-	pr := &ParquetReader{
-		r: r,
+func writeHomeStreet(x *Person, vals []string, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 1:
+		x.Home = &Address{Street: vals[0]}
+		return 1, 1
 	}
 
-	for _, opt := range opts {
-		opt(pr)
-	}
-
-	schema := make([]parquet.Field, len(ff))
-	for i, f := range ff {
-		pr.fieldNames = append(pr.fieldNames, f.Name())
-		schema[i] = f.Schema()
-	}
+	return 0, 1
+}
 
-	meta := parquet.New(schema...)
-	if err := meta.ReadFooter(r); err != nil {
-		return nil, err
-	}
-	pr.rows = meta.Rows()
-	var err error
-	pr.pages, err = meta.Pages()
-	if err != nil {
-		return nil, err
+func readHomeGeoLat(x Person, vals []float64, defs, reps []uint8) ([]float64, []uint8, []uint8) {
+	switch {
+	case x.Home == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	case x.Home.Geo == nil:
+		defs = append(defs, 1)
+		return vals, defs, reps
+	default:
+		vals = append(vals, x.Home.Geo.Lat)
+		defs = append(defs, 2)
+		return vals, defs, reps
 	}
+}
 
-	pr.rowGroups = meta.RowGroups()
-	_, err = r.Seek(4, io.SeekStart)
-	if err != nil {
-		return nil, err
+func writeHomeGeoLat(x *Person, vals []float64, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 2:
+		x.Home.Geo = &Geo{Lat: vals[0]}
+		return 1, 1
 	}
-	pr.meta = meta
 
-	return pr, pr.readRowGroup()
+	return 0, 1
 }
 
-func readerIndex(i int) func(*ParquetReader) {
-	return func(p *ParquetReader) {
-		p.index = i
+func readHomeGeoLon(x Person, vals []float64, defs, reps []uint8) ([]float64, []uint8, []uint8) {
+	switch {
+	case x.Home == nil:
+		defs = append(defs, 0)
+		return vals, defs, reps
+	case x.Home.Geo == nil:
+		defs = append(defs, 1)
+		return vals, defs, reps
+	case x.Home.Geo.Lon == nil:
+		defs = append(defs, 2)
+		return vals, defs, reps
+	default:
+		vals = append(vals, *x.Home.Geo.Lon)
+		defs = append(defs, 3)
+		return vals, defs, reps
 	}
 }
 
-// ParquetReader reads one page from a row group.
-type ParquetReader struct {
-	fields         map[string]Field
-	fieldNames     []string
-	index          int
-	cursor         int64
-	rows           int64
-	rowGroupCursor int64
-	rowGroupCount  int64
-	pages          map[string][]parquet.Page
-	meta           *parquet.Metadata
-	err            error
+func writeHomeGeoLon(x *Person, vals []float64, defs, reps []uint8) (int, int) {
+	def := defs[0]
+	switch def {
+	case 3:
+		x.Home.Geo.Lon = pfloat64(vals[0])
+		return 1, 1
+	}
 
-	r         io.ReadSeeker
-	rowGroups []parquet.RowGroup
+	return 0, 1
 }
 
-type Levels struct {
-	Name string
-	Defs []uint8
-	Reps []uint8
+func fieldCompression(c compression, level int) func(*parquet.RequiredField) {
+	switch c {
+	case compressionUncompressed:
+		return parquet.RequiredFieldUncompressed
+	case compressionSnappy:
+		return parquet.RequiredFieldSnappy
+	case compressionGzip:
+		return parquet.RequiredFieldGzip
+	case compressionZstd:
+		return parquet.RequiredFieldZstdLevel(level)
+	case compressionLz4Raw:
+		return parquet.RequiredFieldLz4Raw
+	default:
+		return parquet.RequiredFieldUncompressed
+	}
 }
 
-func (p *ParquetReader) Levels() []Levels {
-	var out []Levels
-	//for {
-	for _, name := range p.fieldNames {
-		f := p.fields[name]
-		d, r := f.Levels()
-		out = append(out, Levels{Name: f.Name(), Defs: d, Reps: r})
+func optionalFieldCompression(c compression, level int) func(*parquet.OptionalField) {
+	switch c {
+	case compressionUncompressed:
+		return parquet.OptionalFieldUncompressed
+	case compressionSnappy:
+		return parquet.OptionalFieldSnappy
+	case compressionGzip:
+		return parquet.OptionalFieldGzip
+	case compressionZstd:
+		return parquet.OptionalFieldZstdLevel(level)
+	case compressionLz4Raw:
+		return parquet.OptionalFieldLz4Raw
+	default:
+		return parquet.OptionalFieldUncompressed
 	}
-	//	if err := p.readRowGroup(); err != nil {
-	//		break
-	//	}
-	//}
-	return out
 }
 
-func (p *ParquetReader) Error() error {
-	return p.err
+// NewParquetWriter returns a writer of a parquet file to w, which can be
+// any io.Writer (it's never asked to Seek because the writer counts the
+// bytes written to it to know where each column chunk starts).
+func NewParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
+	return newParquetWriter(parquet.NewCountingWriter(w, 0), append(opts, begin)...)
 }
 
-func (p *ParquetReader) readRowGroup() error {
-	p.rowGroupCursor = 0
-
-	if len(p.rowGroups) == 0 {
-		p.rowGroupCount = 0
-		return nil
+func newParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
+	p := &ParquetWriter{
+		max:               1000,
+		w:                 w,
+		compression:       compressionSnappy,
+		maxDictionarySize: parquet.DefaultMaxDictionarySize,
 	}
 
-	rg := p.rowGroups[0]
-	p.fields = getFields(Fields(compressionUnknown))
-	p.rowGroupCount = rg.Rows
-	p.rowGroupCursor = 0
-	for _, col := range rg.Columns() {
-		name := strings.Join(col.MetaData.PathInSchema, ".")
-		f, ok := p.fields[name]
-		if !ok {
-			return fmt.Errorf("unknown field: %s", name)
-		}
-		pages := p.pages[name]
-		if len(pages) <= p.index {
-			break
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, err
 		}
+	}
 
-		pg := pages[0]
-		if err := f.Read(p.r, pg); err != nil {
-			return fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+	p.fields = Fields(p.compression, p.level)
+	if p.meta == nil {
+		if err := p.newMeta(); err != nil {
+			return nil, err
 		}
-		p.pages[name] = p.pages[name][1:]
 	}
-	p.rowGroups = p.rowGroups[1:]
-	return nil
-}
 
-func (p *ParquetReader) Rows() int64 {
-	return p.rows
+	return p, nil
 }
 
-func (p *ParquetReader) Next() bool {
-	if p.err == nil && p.cursor >= p.rows {
-		return false
+// newMeta starts the metadata of a new file.
+func (p *ParquetWriter) newMeta() error {
+	ff := Fields(p.compression, p.level)
+	schema := make([]parquet.Field, len(ff))
+	for i, f := range ff {
+		schema[i] = f.Schema()
 	}
-	if p.rowGroupCursor >= p.rowGroupCount {
-		p.err = p.readRowGroup()
-		if p.err != nil {
-			return false
+	p.meta = parquet.New(schema...)
+
+	if p.pageIndex {
+		p.meta.SetPageIndex()
+	}
+
+	if p.pageChecksums {
+		p.meta.SetPageChecksums()
+	}
+
+	if p.statsTruncateLength > 0 {
+		p.meta.SetStatsTruncateLength(p.statsTruncateLength)
+	}
+
+	for _, sc := range p.sortingColumns {
+		if err := p.meta.SortedBy(sc.column, sc.descending); err != nil {
+			return err
 		}
 	}
 
-	p.cursor++
-	p.rowGroupCursor++
-	return true
+	p.meta.SetKeyValueMetadata(p.keyValueMetadata)
+	p.meta.SetCreatedBy(p.createdBy)
+	return nil
 }
 
-func (p *ParquetReader) Scan(x *Person) {
-	if p.err != nil {
-		return
+// Schema returns the schema that ParquetWriter writes (which can
+// be printed to compare it with the schema another tool expects).
+// The first element is the root of the schema.
+func Schema() []*sch.SchemaElement {
+	ff := Fields(compressionSnappy, 0)
+	schema := make([]parquet.Field, len(ff))
+	for i, f := range ff {
+		schema[i] = f.Schema()
 	}
+	return parquet.New(schema...).Schema()
+}
 
-	for _, name := range p.fieldNames {
-		f := p.fields[name]
-		f.Scan(x)
+// MaxPageSize is the maximum number of rows in each row groups' page.
+func MaxPageSize(m int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.max = m
+		return nil
 	}
 }
 
-type Int32Field struct {
-	vals []int32
-	parquet.RequiredField
-	read  func(r Person) int32
-	write func(r *Person, vals []int32)
-	stats *int32stats
+// MaxDictionarySize is the largest (in bytes) the dictionary of a string
+// or []byte column chunk can be before the column chunk is written with
+// plain encoding instead.  0 turns off dictionary encoding.
+func MaxDictionarySize(n int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if n < 0 {
+			return fmt.Errorf("invalid max dictionary size %d, it must not be negative", n)
+		}
+		p.maxDictionarySize = n
+		return nil
+	}
 }
 
-func NewInt32Field(read func(r Person) int32, write func(r *Person, vals []int32), path []string, opts ...func(*parquet.RequiredField)) *Int32Field {
-	return &Int32Field{
-		read:          read,
-		write:         write,
-		RequiredField: parquet.NewRequiredField(path, opts...),
-		stats:         newInt32stats(),
-	}
+// DeltaBinaryPacked writes the integer columns (including timestamps and
+// dates) with DELTA_BINARY_PACKED encoding instead of plain encoding, which
+// is much smaller for sorted or slowly changing values.
+func DeltaBinaryPacked(p *ParquetWriter) error {
+	p.deltaBinaryPacked = true
+	return nil
 }
 
-func (f *Int32Field) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int32Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+// DeltaLengthByteArray writes the string and []byte columns with
+// DELTA_LENGTH_BYTE_ARRAY encoding (all of the lengths followed by all
+// of the values) instead of dictionary encoding.
+func DeltaLengthByteArray(p *ParquetWriter) error {
+	p.byteArrayEncoding = sch.Encoding_DELTA_LENGTH_BYTE_ARRAY
+	return nil
 }
 
-func (f *Int32Field) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
-	}
+// DeltaByteArray writes the string and []byte columns with DELTA_BYTE_ARRAY
+// encoding instead of dictionary encoding.  Each value only stores what
+// comes after the prefix it shares with the value before it, which is much
+// smaller for sorted values or values with common prefixes (like URLs).
+func DeltaByteArray(p *ParquetWriter) error {
+	p.byteArrayEncoding = sch.Encoding_DELTA_BYTE_ARRAY
+	return nil
+}
 
-	v := make([]int32, int(pg.N))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+// DataPageV2 writes DATA_PAGE_V2 pages instead of DATA_PAGE pages.  The
+// definition and repetition levels of a v2 page aren't compressed, only
+// its values are.
+func DataPageV2(p *ParquetWriter) error {
+	p.dataPageV2 = true
+	return nil
 }
 
-func (f *Int32Field) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+// BloomFilter writes a split block bloom filter for each of the columns
+// (given by their names or the names of their fields) in each row group,
+// which ParquetReader.MayContain uses.  Only string and []byte columns
+// can have bloom filters.
+func BloomFilter(cols ...string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		ff := getFields(Fields(compressionUnknown, 0))
+		for _, col := range cols {
+			if c, ok := columnNames[col]; ok {
+				col = c
+			}
 
-	bs := make([]byte, 4)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint32(bs, uint32(v))
-		if _, err := buf.Write(bs); err != nil {
-			return err
+			f, ok := ff[col]
+			if !ok {
+				return fmt.Errorf("unknown column: %s", col)
+			}
+
+			if _, ok := f.(bloomField); !ok {
+				return fmt.Errorf("column %s can't have a bloom filter, only string and []byte columns can", col)
+			}
+
+			if p.bloomFilters == nil {
+				p.bloomFilters = map[string]bool{}
+			}
+			p.bloomFilters[col] = true
 		}
+		return nil
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
 }
 
-func (f *Int32Field) Scan(r *Person) {
-	if len(f.vals) == 0 {
-		return
-	}
+// ColumnOrder writes the column chunks of the columns (given by their
+// names or the names of their fields) first in each row group, in the
+// order they are given, so a reader that only reads those columns has
+// less to seek past.  The other column chunks are written after them in
+// the order of the schema.  The footer lists the column chunks in the
+// order of the schema no matter where they are in the file.
+func ColumnOrder(cols ...string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		ff := getFields(Fields(compressionUnknown, 0))
+		for _, col := range cols {
+			if c, ok := columnNames[col]; ok {
+				col = c
+			}
 
-	f.write(r, f.vals)
-	f.vals = f.vals[1:]
+			if _, ok := ff[col]; !ok {
+				return fmt.Errorf("unknown column: %s", col)
+			}
+			p.columnOrder = append(p.columnOrder, col)
+		}
+		return nil
+	}
 }
 
-func (f *Int32Field) Add(r Person) {
-	v := f.read(r)
-	f.stats.add(v)
-	f.vals = append(f.vals, v)
+// FailFast makes WriteAll return the error of the first row that can't be
+// written without adding any of the rows, instead of writing the rest.
+func FailFast(p *ParquetWriter) error {
+	p.failFast = true
+	return nil
 }
 
-func (f *Int32Field) Levels() ([]uint8, []uint8) {
-	return nil, nil
+// PageIndex writes a column index (the min, max and null count of each
+// page) and an offset index (where each page starts) for each column chunk
+// before the footer.  ParquetReader uses them to skip the pages that can't
+// match its filters (see Filter).
+func PageIndex(p *ParquetWriter) error {
+	p.pageIndex = true
+	return nil
 }
 
-type StringField struct {
-	parquet.RequiredField
-	vals  []string
-	read  func(r Person) string
-	write func(r *Person, vals []string)
-	stats *stringStats
+// StatsTruncateLength truncates the min and max statistics of string and
+// []byte columns that are longer than n bytes so that very long values
+// don't bloat the footer (see parquet.Metadata.SetStatsTruncateLength).
+// The statistics say that a truncated min or max isn't exact.
+func StatsTruncateLength(n int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if n < 0 {
+			return fmt.Errorf("invalid stats truncate length %d, it must not be negative", n)
+		}
+		p.statsTruncateLength = n
+		return nil
+	}
 }
 
-func NewStringField(read func(r Person) string, write func(r *Person, vals []string), path []string, opts ...func(*parquet.RequiredField)) *StringField {
-	return &StringField{
-		read:          read,
-		write:         write,
-		RequiredField: parquet.NewRequiredField(path, opts...),
-		stats:         newStringStats(),
-	}
+// PageChecksums writes the CRC32 of each page's (compressed) data in its
+// header so that readers can tell when a page is corrupt (see
+// VerifyChecksums).
+func PageChecksums(p *ParquetWriter) error {
+	p.pageChecksums = true
+	return nil
 }
 
-func (f *StringField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+type sortingColumn struct {
+	column     string
+	descending bool
 }
 
-func (f *StringField) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+// SortedBy records in the metadata of each row group that its rows are
+// sorted by a column (given by its name or by its field's name), which can
+// be used more than once for rows that are sorted by more than one column.
+// The writer doesn't sort the rows, they must be added in order (see Less).
+func SortedBy(column string, descending bool) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if col, ok := columnNames[column]; ok {
+			column = col
+		}
 
-	bs := make([]byte, 4)
-	for _, s := range f.vals {
-		binary.LittleEndian.PutUint32(bs, uint32(len(s)))
-		if _, err := buf.Write(bs); err != nil {
+		if _, err := Less(column, descending); err != nil {
 			return err
 		}
-		buf.WriteString(s)
-	}
 
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+		p.sortingColumns = append(p.sortingColumns, sortingColumn{column: column, descending: descending})
+		return nil
+	}
 }
 
-func (f *StringField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+// SetMetadata writes kv as the key/value metadata in the file's footer,
+// for things about the file (like what wrote it) that other programs can
+// read (see ParquetReader.Metadata).  It isn't part of the schema.
+func SetMetadata(kv map[string]string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.keyValueMetadata = make(map[string]string, len(kv))
+		for k, v := range kv {
+			p.keyValueMetadata[k] = v
+		}
+		return nil
 	}
+}
 
-	for j := 0; j < pg.N; j++ {
-		var x int32
-		if err := binary.Read(rr, binary.LittleEndian, &x); err != nil {
-			return err
-		}
-		s := make([]byte, x)
-		if _, err := rr.Read(s); err != nil {
-			return err
-		}
+// SetCreatedBy writes s (like "myapp version 1.2.3") as the application
+// that wrote the file in its footer instead of parquet.DefaultCreatedBy
+// (see ParquetReader.CreatedBy).
+func SetCreatedBy(s string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.createdBy = s
+		return nil
+	}
+}
 
-		f.vals = append(f.vals, string(s))
+// MaxRowGroupRows is the maximum number of rows in each row group.  Add
+// writes the row group (just like calling Write) once it has m rows.  0
+// means there is no maximum, so row groups are only written by Write.
+func MaxRowGroupRows(m int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if m < 0 {
+			return fmt.Errorf("invalid max row group rows %d, it must not be negative", m)
+		}
+		p.maxRowGroupRows = m
+		return nil
 	}
-	return nil
 }
 
-func (f *StringField) Scan(r *Person) {
-	if len(f.vals) == 0 {
-		return
+// WriteContext makes Write (and Add, when it writes a row group) and Close
+// return ctx's error instead of writing anything once ctx is done.
+func WriteContext(ctx context.Context) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.ctx = ctx
+		return nil
 	}
+}
 
-	f.write(r, f.vals)
-	f.vals = f.vals[1:]
+var par1 = []byte("PAR1")
+
+func begin(p *ParquetWriter) error {
+	_, err := p.w.Write(par1)
+	return err
 }
 
-func (f *StringField) Add(r Person) {
-	v := f.read(r)
-	f.stats.add(v)
-	f.vals = append(f.vals, v)
+func withMeta(m *parquet.Metadata) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.meta = m
+		return nil
+	}
 }
 
-func (f *StringField) Levels() ([]uint8, []uint8) {
-	return nil, nil
+func Uncompressed(p *ParquetWriter) error {
+	p.compression = compressionUncompressed
+	return nil
 }
 
-type Int32OptionalField struct {
-	parquet.OptionalField
-	vals  []int32
-	read  func(r Person, vals []int32, defs, reps []uint8) ([]int32, []uint8, []uint8)
-	write func(r *Person, vals []int32, defs, reps []uint8) (int, int)
-	stats *int32optionalStats
+func Snappy(p *ParquetWriter) error {
+	p.compression = compressionSnappy
+	return nil
 }
 
-func NewInt32OptionalField(read func(r Person, vals []int32, defs, reps []uint8) ([]int32, []uint8, []uint8), write func(r *Person, vals []int32, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Int32OptionalField {
-	return &Int32OptionalField{
-		read:          read,
-		write:         write,
-		OptionalField: parquet.NewOptionalField(path, types, opts...),
-		stats:         newint32optionalStats(maxDef(types)),
-	}
+func Gzip(p *ParquetWriter) error {
+	p.compression = compressionGzip
+	return nil
 }
 
-func (f *Int32OptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int32Type, RepetitionType: f.RepetitionType, Types: f.Types}
+func Zstd(p *ParquetWriter) error {
+	p.compression = compressionZstd
+	return nil
 }
 
-func (f *Int32OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+// Lz4Raw compresses with LZ4_RAW (lz4 blocks without a frame),
+// which is what hadoop 3 and newer versions of spark use.
+func Lz4Raw(p *ParquetWriter) error {
+	p.compression = compressionLz4Raw
+	return nil
+}
 
-	bs := make([]byte, 4)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint32(bs, uint32(v))
-		if _, err := buf.Write(bs); err != nil {
-			return err
+// ZstdLevel compresses with zstd at the given level
+// (1 is the fastest and 22 the smallest).
+func ZstdLevel(level int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if level < 1 || level > 22 {
+			return fmt.Errorf("invalid zstd level %d, it must be between 1 and 22", level)
 		}
+		p.compression = compressionZstd
+		p.level = level
+		return nil
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
 }
 
-func (f *Int32OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
+func withCompression(c compression, level int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.compression = c
+		p.level = level
+		return nil
+	}
+}
+
+// Write writes the rows that have been added since the last call
+// to Write as a row group.
+func (p *ParquetWriter) Write() error {
+	if p.err != nil {
+		return p.err
+	}
+
+	if err := p.ctxErr(); err != nil {
 		return err
 	}
 
-	v := make([]int32, f.Values()-len(f.vals))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
-}
+	// an empty row group isn't written
+	if p.len == 0 {
+		return nil
+	}
 
-func (f *Int32OptionalField) Add(r Person) {
-	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
-	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
-	f.vals = vals
-	f.Defs = defs
-	f.Reps = reps
-}
+	var blooms [][]Field
+	for _, i := range p.writeOrder() {
+		f := p.fields[i]
+		fields := []Field{f}
+		for child := p.child; child != nil; child = child.child {
+			fields = append(fields, child.fields[i])
+		}
 
-func (f *Int32OptionalField) Scan(r *Person) {
-	if len(f.Defs) == 0 {
-		return
+		if p.bloomFilters[f.Name()] {
+			blooms = append(blooms, fields)
+		}
+
+		if err := p.writeDictionary(fields); err != nil {
+			return err
+		}
+
+		delta, byteArray := p.encodings(f.Name())
+		for _, f := range fields {
+			if ef, ok := f.(encodingField); ok {
+				if delta {
+					ef.SetDeltaBinaryPacked()
+				}
+				ef.SetByteArrayEncoding(byteArray)
+			}
+
+			if pf, ok := f.(dataPageV2Field); ok && p.dataPageV2 {
+				pf.SetDataPageV2()
+			}
+
+			if err := f.Write(p.w, p.meta); err != nil {
+				return err
+			}
+		}
 	}
 
-	v, l := f.write(r, f.vals, f.Defs, f.Reps)
-	f.vals = f.vals[v:]
-	f.Defs = f.Defs[l:]
-	if len(f.Reps) > 0 {
-		f.Reps = f.Reps[l:]
+	if err := p.writeBloomFilters(blooms); err != nil {
+		return err
 	}
-}
 
-func (f *Int32OptionalField) Levels() ([]uint8, []uint8) {
-	return f.Defs, f.Reps
-}
+	p.fields = Fields(p.compression, p.level)
+	p.child = nil
+	p.len = 0
+	p.rowGroupRows = 0
 
-type Int64Field struct {
-	vals []int64
-	parquet.RequiredField
-	read  func(r Person) int64
-	write func(r *Person, vals []int64)
-	stats *int64stats
+	schema := make([]parquet.Field, len(p.fields))
+	for i, f := range p.fields {
+		schema[i] = f.Schema()
+	}
+	p.meta.StartRowGroup(schema...)
+	return nil
 }
 
-func NewInt64Field(read func(r Person) int64, write func(r *Person, vals []int64), path []string, opts ...func(*parquet.RequiredField)) *Int64Field {
-	return &Int64Field{
-		read:          read,
-		write:         write,
-		RequiredField: parquet.NewRequiredField(path, opts...),
-		stats:         newInt64stats(),
+// writeOrder returns the indices of p.fields in the order
+// that their column chunks are written (see ColumnOrder).
+func (p *ParquetWriter) writeOrder() []int {
+	out := make([]int, 0, len(p.fields))
+	first := map[string]bool{}
+	for _, col := range p.columnOrder {
+		for i, f := range p.fields {
+			if f.Name() == col && !first[col] {
+				out = append(out, i)
+				first[col] = true
+			}
+		}
 	}
-}
 
-func (f *Int64Field) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int64Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+	for i, f := range p.fields {
+		if !first[f.Name()] {
+			out = append(out, i)
+		}
+	}
+	return out
 }
 
-func (f *Int64Field) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+func (p *ParquetWriter) ctxErr() error {
+	if p.ctx == nil {
+		return nil
 	}
+	return p.ctx.Err()
+}
 
-	v := make([]int64, int(pg.N))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+// bloomField is a field that can have a bloom filter.
+type bloomField interface {
+	Path() []string
+	bloomHashes(hashes []uint64) []uint64
 }
 
-func (f *Int64Field) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+// writeBloomFilters writes the bloom filter of each column chunk (the
+// fields of each chunk are its pages) after the row group's column chunks.
+func (p *ParquetWriter) writeBloomFilters(chunks [][]Field) error {
+	for _, fields := range chunks {
+		var hashes []uint64
+		for _, f := range fields {
+			hashes = f.(bloomField).bloomHashes(hashes)
+		}
 
-	bs := make([]byte, 8)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint64(bs, uint64(v))
-		if _, err := buf.Write(bs); err != nil {
+		pth := fields[0].(bloomField).Path()
+		if err := p.meta.WriteBloomFilter(p.w, pth, parquet.NewBloomFilter(hashes)); err != nil {
 			return err
 		}
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+	return nil
 }
 
-func (f *Int64Field) Scan(r *Person) {
-	if len(f.vals) == 0 {
-		return
+// flusher is an io.Writer (like a bufio.Writer) that buffers
+// what is written to it until it is flushed.
+type flusher interface {
+	Flush() error
+}
+
+// Flush writes the rows that have been added since the last call to Write
+// (or Flush) as a row group and then flushes the underlying io.Writer if it
+// has a Flush method.  Everything up to the end of the row group has been
+// written to the io.Writer when Flush returns, but the file can't be read
+// until Close writes the footer.
+func (p *ParquetWriter) Flush() error {
+	if err := p.Write(); err != nil {
+		return err
 	}
 
-	f.write(r, f.vals)
-	f.vals = f.vals[1:]
+	if f, ok := p.w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
 }
 
-func (f *Int64Field) Add(r Person) {
-	v := f.read(r)
-	f.stats.add(v)
-	f.vals = append(f.vals, v)
+type dictionaryField interface {
+	addToDictionary(d *parquet.Dictionary) bool
+	SetDictionary(d *parquet.Dictionary)
+	WriteDictionary(w io.Writer, meta *parquet.Metadata) error
 }
 
-func (f *Int64Field) Levels() ([]uint8, []uint8) {
-	return nil, nil
+// encodingField is a field that can be written with something other than
+// plain (or dictionary) encoding.  Each encoding is only used if it works
+// with the field's column type.
+type encodingField interface {
+	SetDeltaBinaryPacked()
+	SetByteArrayEncoding(enc sch.Encoding)
 }
 
-type Int64OptionalField struct {
-	parquet.OptionalField
-	vals  []int64
-	read  func(r Person, vals []int64, defs, reps []uint8) ([]int64, []uint8, []uint8)
-	write func(r *Person, vals []int64, defs, reps []uint8) (int, int)
-	stats *int64optionalStats
+type dataPageV2Field interface {
+	SetDataPageV2()
 }
 
-func NewInt64OptionalField(read func(r Person, vals []int64, defs, reps []uint8) ([]int64, []uint8, []uint8), write func(r *Person, vals []int64, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Int64OptionalField {
-	return &Int64OptionalField{
-		read:          read,
-		write:         write,
-		OptionalField: parquet.NewOptionalField(path, types, opts...),
-		stats:         newint64optionalStats(maxDef(types)),
+// encodings returns whether the column is DELTA_BINARY_PACKED and the
+// encoding of its byte arrays (see encodingField), which come from the
+// column's struct tag if it has an encoding and the options if it doesn't.
+func (p *ParquetWriter) encodings(col string) (bool, sch.Encoding) {
+	enc, ok := columnEncodings[col]
+	if !ok {
+		return p.deltaBinaryPacked, p.byteArrayEncoding
 	}
-}
 
-func (f *Int64OptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int64Type, RepetitionType: f.RepetitionType, Types: f.Types}
+	switch enc {
+	case sch.Encoding_DELTA_LENGTH_BYTE_ARRAY, sch.Encoding_DELTA_BYTE_ARRAY:
+		return false, enc
+	}
+	return enc == sch.Encoding_DELTA_BINARY_PACKED, sch.Encoding_PLAIN
 }
 
-func (f *Int64OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+// writeDictionary writes the dictionary page of a column chunk (the
+// fields are the chunk's pages) when the column supports dictionary
+// encoding and the chunk's distinct values fit in the dictionary.
+// Otherwise the column chunk is plain encoded.  A column whose struct
+// tag sets its encoding only gets a dictionary if it's dictionary.
+func (p *ParquetWriter) writeDictionary(fields []Field) error {
+	enc, ok := columnEncodings[fields[0].Name()]
+	if p.maxDictionarySize == 0 || (ok && enc != sch.Encoding_RLE_DICTIONARY) || (!ok && p.byteArrayEncoding != sch.Encoding_PLAIN) {
+		return nil
+	}
 
-	bs := make([]byte, 8)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint64(bs, uint64(v))
-		if _, err := buf.Write(bs); err != nil {
-			return err
+	d := parquet.NewDictionary(p.maxDictionarySize)
+	for _, f := range fields {
+		df, ok := f.(dictionaryField)
+		if !ok || !df.addToDictionary(d) {
+			return nil
 		}
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+
+	if d.Len() == 0 {
+		return nil
+	}
+
+	for _, f := range fields {
+		f.(dictionaryField).SetDictionary(d)
+	}
+	return fields[0].(dictionaryField).WriteDictionary(p.w, p.meta)
 }
 
-func (f *Int64OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
+func (p *ParquetWriter) Close() error {
+	if p.err != nil {
+		return p.err
+	}
+
+	if err := p.ctxErr(); err != nil {
 		return err
 	}
 
-	v := make([]int64, f.Values()-len(f.vals))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
+	if err := p.meta.Footer(p.w); err != nil {
+		return err
+	}
+
+	_, err := p.w.Write(par1)
 	return err
 }
 
-func (f *Int64OptionalField) Add(r Person) {
-	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
-	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
-	f.vals = vals
-	f.Defs = defs
-	f.Reps = reps
+// Reset starts a new file that is written to w with the same options, so
+// one writer can write many files.  The rows that have been added since
+// the last Write, the row groups of the current file and any error from
+// Add are discarded, so Close needs to be called first to finish the
+// current file.
+func (p *ParquetWriter) Reset(w io.Writer) error {
+	p.w = parquet.NewCountingWriter(w, 0)
+	p.fields = Fields(p.compression, p.level)
+	p.child = nil
+	p.len = 0
+	p.rowGroupRows = 0
+	p.err = nil
+
+	if err := p.newMeta(); err != nil {
+		return err
+	}
+	return begin(p)
 }
 
-func (f *Int64OptionalField) Scan(r *Person) {
-	if len(f.Defs) == 0 {
+func (p *ParquetWriter) Add(rec Person) {
+	p.add(rec)
+
+	if p.maxRowGroupRows == 0 {
 		return
 	}
 
-	v, l := f.write(r, f.vals, f.Defs, f.Reps)
-	f.vals = f.vals[v:]
-	f.Defs = f.Defs[l:]
-	if len(f.Reps) > 0 {
-		f.Reps = f.Reps[l:]
+	p.rowGroupRows++
+	if p.rowGroupRows == p.maxRowGroupRows && p.err == nil {
+		p.err = p.Write()
 	}
 }
 
-func (f *Int64OptionalField) Levels() ([]uint8, []uint8) {
-	return f.Defs, f.Reps
-}
+// WriteRow adds a row (like Add) for producers that have one row at a
+// time.  It writes the row group once it has MaxRowGroupRows rows and,
+// unlike Add, returns the error from writing it.  The rows that are left
+// over when there are no more still need a Write (or Flush) before Close.
+func (p *ParquetWriter) WriteRow(rec Person) error {
+	if p.err != nil {
+		return p.err
+	}
 
-type StringOptionalField struct {
-	parquet.OptionalField
-	vals  []string
-	read  func(r Person, vals []string, def, rep []uint8) ([]string, []uint8, []uint8)
-	write func(r *Person, vals []string, def, rep []uint8) (int, int)
-	stats *stringOptionalStats
+	p.Add(rec)
+	return p.err
 }
 
-func NewStringOptionalField(read func(r Person, vals []string, def, rep []uint8) ([]string, []uint8, []uint8), write func(r *Person, vals []string, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *StringOptionalField {
-	return &StringOptionalField{
-		read:          read,
-		write:         write,
-		OptionalField: parquet.NewOptionalField(path, types, opts...),
-		stats:         newStringOptionalStats(maxDef(types)),
+// WriteAll adds rows (like Add) and writes them (like Write).  A row that
+// can't be written (like a decimal with more digits than its precision)
+// would make Write fail for all of them, so each one is left out instead
+// and its error is returned in errs at its index in rows (errs is nil if
+// all of the rows were written).  The rows are written to a scratch
+// buffer first to find the ones that can't be written, which is about as
+// much work again as writing them.  With FailFast, none of the rows are
+// added if one of them can't be written and the error of the first one is
+// returned as err.
+func (p *ParquetWriter) WriteAll(rows []Person) ([]error, error) {
+	if p.err != nil {
+		return nil, p.err
 	}
-}
 
-func (f *StringOptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: f.RepetitionType, Types: f.Types}
-}
+	var errs []error
+	if err := p.checkRows(rows); err != nil {
+		errs = make([]error, len(rows))
+		p.rowErrors(rows, 0, errs)
+	}
 
-func (f *StringOptionalField) Add(r Person) {
-	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
-	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
-	f.vals = vals
-	f.Defs = defs
-	f.Reps = reps
+	if p.failFast {
+		for i, err := range errs {
+			if err != nil {
+				return errs, fmt.Errorf("row %d: %s", i, err)
+			}
+		}
+	}
+
+	for i, rec := range rows {
+		if errs == nil || errs[i] == nil {
+			p.Add(rec)
+		}
+	}
+	return errs, p.Write()
 }
 
-func (f *StringOptionalField) Scan(r *Person) {
-	if len(f.Defs) == 0 {
+// rowErrors sets errs[i+j] to the error of each row j that can't be
+// written.  rows are split in half until the rows that can't be written
+// are on their own.
+func (p *ParquetWriter) rowErrors(rows []Person, i int, errs []error) {
+	err := p.checkRows(rows)
+	if err == nil {
 		return
 	}
 
-	v, l := f.write(r, f.vals, f.Defs, f.Reps)
-	f.vals = f.vals[v:]
-	f.Defs = f.Defs[l:]
-	if len(f.Reps) > 0 {
-		f.Reps = f.Reps[l:]
+	if len(rows) == 1 {
+		errs[i] = err
+		return
+	}
+
+	n := len(rows) / 2
+	p.rowErrors(rows[:n], i, errs)
+	if p.failFast && hasError(errs[i:i+n]) {
+		return
 	}
+	p.rowErrors(rows[n:], i+n, errs)
 }
 
-func (f *StringOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+// checkRows returns the error from writing rows to a scratch buffer.
+func (p *ParquetWriter) checkRows(rows []Person) error {
 	buf := buffpool.Get()
 	defer buffpool.Put(buf)
 
-	bs := make([]byte, 4)
-	for _, s := range f.vals {
-		binary.LittleEndian.PutUint32(bs, uint32(len(s)))
-		if _, err := buf.Write(bs); err != nil {
-			return err
-		}
-		buf.WriteString(s)
-	}
-
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
-}
-
-func (f *StringOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
+	w, err := newParquetWriter(buf, MaxPageSize(p.max), withCompression(compressionUncompressed, 0))
 	if err != nil {
 		return err
 	}
 
-	for j := 0; j < f.Values(); j++ {
-		var x int32
-		if err := binary.Read(rr, binary.LittleEndian, &x); err != nil {
-			return err
-		}
-		s := make([]byte, x)
-		if _, err := rr.Read(s); err != nil {
-			return err
-		}
-
-		f.vals = append(f.vals, string(s))
+	for _, rec := range rows {
+		w.add(rec)
 	}
-	return nil
+	return w.Write()
 }
 
-func (f *StringOptionalField) Levels() ([]uint8, []uint8) {
-	return f.Defs, f.Reps
+func hasError(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
 }
 
-type Float32Field struct {
-	vals []float32
-	parquet.RequiredField
-	read  func(r Person) float32
-	write func(r *Person, vals []float32)
-	stats *float32stats
-}
+func (p *ParquetWriter) add(rec Person) {
+	if p.len == p.max {
+		if p.child == nil {
+			// an error can't happen here
+			p.child, _ = newParquetWriter(p.w, MaxPageSize(p.max), withMeta(p.meta), withCompression(p.compression, p.level))
+		}
 
-func NewFloat32Field(read func(r Person) float32, write func(r *Person, vals []float32), path []string, opts ...func(*parquet.RequiredField)) *Float32Field {
-	return &Float32Field{
-		read:          read,
-		write:         write,
-		RequiredField: parquet.NewRequiredField(path, opts...),
-		stats:         newFloat32stats(),
+		p.child.add(rec)
+		return
 	}
-}
-
-func (f *Float32Field) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Float32Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
-}
 
-func (f *Float32Field) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+	p.meta.NextDoc()
+	for _, f := range p.fields {
+		f.Add(rec)
 	}
 
-	v := make([]float32, int(pg.N))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+	p.len++
 }
 
-func (f *Float32Field) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
-
-	bs := make([]byte, 4)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
-		if _, err := buf.Write(bs); err != nil {
+// RotatingWriter writes rows to a series of parquet files that each have
+// up to a maximum number of rows.  Each file is a complete parquet file
+// (with its own footer) that is written with the writer's options.  The
+// io.Writer of each file comes from a function that is given the index
+// of the file (starting at 0), which is called when the file's first row
+// is added, so there are no empty files.  If the io.Writer is also an
+// io.Closer (like an *os.File) it is closed when its file is finished
+// (even if finishing it fails).  Once a file can't be finished every
+// call to Add and Close returns that error.
+type RotatingWriter struct {
+	pw       *ParquetWriter
+	w        io.Writer
+	next     func(index int) (io.Writer, error)
+	opts     []func(*ParquetWriter) error
+	maxRows  int
+	files    int
+	fileRows int
+	err      error
+}
+
+// NewRotatingWriter returns a RotatingWriter that starts a new file
+// after every maxRows rows.
+func NewRotatingWriter(maxRows int, next func(index int) (io.Writer, error), opts ...func(*ParquetWriter) error) (*RotatingWriter, error) {
+	if maxRows < 1 {
+		return nil, fmt.Errorf("invalid max rows %d, it must be at least 1", maxRows)
+	}
+	return &RotatingWriter{next: next, opts: opts, maxRows: maxRows}, nil
+}
+
+// Add adds a row to the current file, first finishing it and starting
+// the next one if it already has the maximum number of rows.
+func (r *RotatingWriter) Add(rec Person) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	if r.w != nil && r.fileRows == r.maxRows {
+		if err := r.finish(); err != nil {
 			return err
 		}
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
-}
 
-func (f *Float32Field) Scan(r *Person) {
-	if len(f.vals) == 0 {
-		return
+	if r.w == nil {
+		if err := r.start(); err != nil {
+			return err
+		}
 	}
 
-	f.write(r, f.vals)
-	f.vals = f.vals[1:]
-}
-
-func (f *Float32Field) Add(r Person) {
-	v := f.read(r)
-	f.stats.add(v)
-	f.vals = append(f.vals, v)
-}
-
-func (f *Float32Field) Levels() ([]uint8, []uint8) {
-	return nil, nil
+	r.fileRows++
+	return r.pw.WriteRow(rec)
 }
 
-type Float64Field struct {
-	vals []float64
-	parquet.RequiredField
-	read  func(r Person) float64
-	write func(r *Person, vals []float64)
-	stats *float64stats
+// Files returns the number of files that have been started.
+func (r *RotatingWriter) Files() int {
+	return r.files
 }
 
-func NewFloat64Field(read func(r Person) float64, write func(r *Person, vals []float64), path []string, opts ...func(*parquet.RequiredField)) *Float64Field {
-	return &Float64Field{
-		read:          read,
-		write:         write,
-		RequiredField: parquet.NewRequiredField(path, opts...),
-		stats:         newFloat64stats(),
+// Close finishes the current file.
+func (r *RotatingWriter) Close() error {
+	if r.err != nil || r.w == nil {
+		return r.err
 	}
+	return r.finish()
 }
 
-func (f *Float64Field) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Float64Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
-}
-
-func (f *Float64Field) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
+// start gets the io.Writer of the next file and starts
+// the file (reusing the ParquetWriter of the last file).
+func (r *RotatingWriter) start() error {
+	w, err := r.next(r.files)
 	if err != nil {
 		return err
 	}
 
-	v := make([]float64, int(pg.N))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
-}
-
-func (f *Float64Field) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
-
-	bs := make([]byte, 8)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint64(bs, math.Float64bits(v))
-		if _, err := buf.Write(bs); err != nil {
-			return err
-		}
+	if r.pw == nil {
+		r.pw, err = NewParquetWriter(w, r.opts...)
+	} else {
+		err = r.pw.Reset(w)
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
-}
-
-func (f *Float64Field) Scan(r *Person) {
-	if len(f.vals) == 0 {
-		return
+	if err != nil {
+		return err
 	}
 
-	f.write(r, f.vals)
-	f.vals = f.vals[1:]
+	r.w = w
+	r.files++
+	r.fileRows = 0
+	return nil
 }
 
-func (f *Float64Field) Add(r Person) {
-	v := f.read(r)
-	f.stats.add(v)
-	f.vals = append(f.vals, v)
-}
+// finish writes the rest of the current file's rows and its footer
+// and then closes it.
+func (r *RotatingWriter) finish() (err error) {
+	w := r.w
+	r.w = nil
+	defer func() {
+		if c, ok := w.(io.Closer); ok {
+			if cerr := c.Close(); err == nil {
+				err = cerr
+			} else if cerr != nil {
+				err = fmt.Errorf("%s (and then unable to close the file: %s)", err, cerr)
+			}
+		}
+		r.err = err
+	}()
 
-func (f *Float64Field) Levels() ([]uint8, []uint8) {
-	return nil, nil
+	if err := r.pw.Write(); err != nil {
+		return err
+	}
+	return r.pw.Close()
 }
 
-type Float32OptionalField struct {
-	parquet.OptionalField
-	vals  []float32
-	read  func(r Person, vals []float32, defs, reps []uint8) ([]float32, []uint8, []uint8)
-	write func(r *Person, vals []float32, defs, reps []uint8) (int, int)
-	stats *float32optionalStats
+type Field interface {
+	Add(r Person)
+	Write(w io.Writer, meta *parquet.Metadata) error
+	Schema() parquet.Field
+	Scan(r *Person)
+	Read(r io.ReadSeeker, pg parquet.Page) error
+	Name() string
+	Levels() ([]uint8, []uint8)
+	copyTo(dest interface{}) error
 }
 
-func NewFloat32OptionalField(read func(r Person, vals []float32, defs, reps []uint8) ([]float32, []uint8, []uint8), write func(r *Person, vals []float32, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Float32OptionalField {
-	return &Float32OptionalField{
-		read:          read,
-		write:         write,
-		OptionalField: parquet.NewOptionalField(path, types, opts...),
-		stats:         newfloat32optionalStats(maxDef(types)),
+func getFields(ff []Field) map[string]Field {
+	m := make(map[string]Field, len(ff))
+	for _, f := range ff {
+		m[f.Name()] = f
 	}
+	return m
 }
 
-func (f *Float32OptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Float32Type, RepetitionType: f.RepetitionType, Types: f.Types}
-}
+func NewParquetReader(r io.ReadSeeker, opts ...func(*ParquetReader)) (*ParquetReader, error) {
+	ff := Fields(compressionUnknown, 0)
+	pr := &ParquetReader{
+		r: r,
+	}
 
-func (f *Float32OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+	for _, opt := range opts {
+		opt(pr)
+	}
 
-	bs := make([]byte, 4)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
-		if _, err := buf.Write(bs); err != nil {
-			return err
+	if pr.parallel > 1 {
+		ra, ok := r.(io.ReaderAt)
+		if !ok {
+			return nil, fmt.Errorf("ReadParallel needs a reader that is also an io.ReaderAt, %T isn't", r)
 		}
+		pr.readerAt = ra
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
-}
 
-func (f *Float32OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+	schema := make([]parquet.Field, len(ff))
+	for i, f := range ff {
+		pr.fieldNames = append(pr.fieldNames, f.Name())
+		schema[i] = f.Schema()
+	}
+
+	meta := parquet.New(schema...)
+	if err := meta.ReadFooter(r); err != nil {
+		return nil, err
+	}
+
+	// the optional fields that the file doesn't
+	// have a column for are left as nil
+	if err := meta.CheckColumns(); err != nil {
+		return nil, err
+	}
+
+	if pr.verifyChecksums {
+		meta.SetPageChecksums()
+	}
+
+	if pr.noCopy {
+		meta.SetNoCopy()
+	}
+
+	pages, err := meta.Pages()
+	if err != nil {
+		return nil, err
+	}
+
+	pr.pages = map[string][]parquet.Page{}
+	for i, rg := range meta.RowGroups() {
+		// the row groups after MaxRows aren't needed
+		if pr.maxRows > 0 && pr.rows >= pr.maxRows {
+			break
+		}
+
+		skip, err := meta.Skip(rg, pr.filters...)
+		if err != nil {
+			return nil, err
+		}
+
+		if skip {
+			continue
+		}
+
+		rgPages := make(map[string]parquet.Page, len(rg.Columns()))
+		for _, col := range rg.Columns() {
+			name := strings.Join(col.MetaData.PathInSchema, ".")
+			rgPages[name] = pages[name][i]
+		}
+
+		rgPages, rg.Rows, err = meta.FilterPages(r, rg, rgPages, pr.filters...)
+		if err != nil {
+			return nil, err
+		}
+
+		// all of the row group's pages were skipped
+		if rg.Rows == 0 {
+			continue
+		}
+
+		for _, col := range rg.Columns() {
+			name := strings.Join(col.MetaData.PathInSchema, ".")
+			pr.pages[name] = append(pr.pages[name], rgPages[name])
+		}
+		pr.rows += rg.Rows
+		pr.rowGroups = append(pr.rowGroups, rg)
+	}
+
+	if pr.maxRows > 0 && pr.rows > pr.maxRows {
+		pr.rows = pr.maxRows
+	}
+
+	_, err = r.Seek(4, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	pr.meta = meta
+
+	if err := pr.ctxErr(); err != nil {
+		return nil, err
+	}
+	return pr, pr.readRowGroup()
+}
+
+// NewParquetReaderAt returns a reader of the size bytes of r (like a file
+// in an object store that is read with range requests).  Only the footer
+// and the column chunks (and page indexes) that are needed are read, each
+// with a single ReadAt.
+func NewParquetReaderAt(r io.ReaderAt, size int64, opts ...func(*ParquetReader)) (*ParquetReader, error) {
+	return NewParquetReader(io.NewSectionReader(r, 0, size), opts...)
+}
+
+func readerIndex(i int) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.index = i
+	}
+}
+
+// Filter makes the reader skip each row group whose statistics show that
+// none of its rows can have a column value that compares to value with op.
+// The column is given by its name (like "hobby.name") or by its field's
+// name (like "Hobby.Name").  The pages of a file written with a page index
+// (see PageIndex) are skipped the same way.  The rows of the row groups (or
+// pages) that aren't skipped are all returned, even the ones that don't
+// match the filter.
+func Filter(column string, op parquet.Operator, value interface{}) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		if col, ok := columnNames[column]; ok {
+			column = col
+		}
+		p.filters = append(p.filters, parquet.Filter{Column: column, Op: op, Value: value})
+	}
+}
+
+// MaxRows makes the reader stop after n rows (which can be in the middle
+// of a row group) without reading the row groups that come after them.
+// Rows returns n if the file has more rows than that.  ReadColumn still
+// reads all of a column's values.  MaxRows(0) doesn't limit the rows.
+func MaxRows(n int64) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.maxRows = n
+	}
+}
+
+// VerifyChecksums makes the reader check the checksum of each page it
+// reads (see PageChecksums) and fail with an error if the page's data
+// doesn't match it.  Pages without a checksum aren't checked.
+func VerifyChecksums(p *ParquetReader) {
+	p.verifyChecksums = true
+}
+
+// NoCopy makes the reader read the values of string and []byte fields as
+// slices of a column chunk's data instead of copying each one, which saves
+// an allocation per value.  The values are only valid until the reader
+// reads the next row group (Next, ReadColumn or ReadRowGroup is called
+// again): they must be copied to keep them any longer, and a []byte value
+// must never be changed because the strings share its memory.
+func NoCopy(p *ParquetReader) {
+	p.noCopy = true
+}
+
+// SkipErrors makes the reader skip each row group that can't be read (like
+// one with a corrupt page) instead of failing.  The errors of the row groups
+// that were skipped are returned by Errors and Rows goes down by the number
+// of rows that each of them had.  ReadColumn and ReadRowGroup still fail.
+func SkipErrors(p *ParquetReader) {
+	p.skipErrors = true
+}
+
+// Where makes Next skip the rows that f returns false for.  Each row is
+// read (and passed to f) before it is skipped, so Filter should be used as
+// well when it can skip whole row groups or pages that can't have any
+// matching rows.  Rows still returns the number of rows before they are
+// skipped.  Where doesn't apply to ReadColumn or ReadRowGroup.
+func Where(f func(Person) bool) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.where = f
+	}
+}
+
+// ReadContext makes the reader stop (Next returns false and Error returns
+// ctx's error) before it reads another row group once ctx is done.
+func ReadContext(ctx context.Context) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.ctx = ctx
+	}
+}
+
+// ReadParallel makes the reader read (and decode) the next n row groups at
+// the same time, each in its own goroutine, when it runs out of rows.  The
+// rows still come back in the order they're in the file, but up to n row
+// groups are kept in memory.  The reader has to also be an io.ReaderAt
+// (like an *os.File or the reader that NewParquetReaderAt uses) so the
+// row groups can each be read from their own offset.
+func ReadParallel(n int) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.parallel = n
+	}
+}
+
+// ParquetReader reads one page from a row group.
+type ParquetReader struct {
+	fields          map[string]Field
+	fieldNames      []string
+	index           int
+	cursor          int64
+	rows            int64
+	maxRows         int64
+	verifyChecksums bool
+	noCopy          bool
+	skipErrors      bool
+	errs            []error
+	rowGroupCursor  int64
+	rowGroupCount   int64
+	pages           map[string][]parquet.Page
+	meta            *parquet.Metadata
+	filters         []parquet.Filter
+	ctx             context.Context
+	err             error
+
+	// parallel (see ReadParallel) is the number of row groups that
+	// are read at a time and decoded holds the ones that have been
+	// read but not scanned yet
+	parallel int
+	readerAt io.ReaderAt
+	decoded  []map[string]Field
+
+	// where (see Where) is checked by Next, which
+	// keeps the matching row in row for Scan
+	where func(Person) bool
+	row   *Person
+
+	r         io.ReadSeeker
+	rowGroups []parquet.RowGroup
+}
+
+type Levels struct {
+	Name string
+	Defs []uint8
+	Reps []uint8
+}
+
+func (p *ParquetReader) Levels() []Levels {
+	var out []Levels
+	//for {
+	for _, name := range p.fieldNames {
+		f := p.fields[name]
+		d, r := f.Levels()
+		out = append(out, Levels{Name: f.Name(), Defs: d, Reps: r})
+	}
+	//	if err := p.readRowGroup(); err != nil {
+	//		break
+	//	}
+	//}
+	return out
+}
+
+func (p *ParquetReader) Error() error {
+	return p.err
+}
+
+// Errors returns the errors of the row groups that were skipped (see
+// SkipErrors).
+func (p *ParquetReader) Errors() []error {
+	return p.errs
+}
+
+// skip records err, the error of the row group rg, if SkipErrors
+// was used, in which case rg's rows are no longer counted.
+func (p *ParquetReader) skip(rg parquet.RowGroup, err error) bool {
+	if !p.skipErrors {
+		return false
+	}
+	p.errs = append(p.errs, err)
+	p.rows -= rg.Rows
+	return true
+}
+
+func (p *ParquetReader) ctxErr() error {
+	if p.ctx == nil {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
+func (p *ParquetReader) readRowGroup() error {
+	for {
+		p.rowGroupCursor = 0
+
+		if len(p.rowGroups) == 0 {
+			p.rowGroupCount = 0
+			return nil
+		}
+
+		if p.parallel > 1 {
+			return p.readRowGroups()
+		}
+
+		rg := p.rowGroups[0]
+		fields, err := p.readColumns(rg)
+		for name, pages := range p.pages {
+			if len(pages) > 0 {
+				p.pages[name] = pages[1:]
+			}
+		}
+		p.rowGroups = p.rowGroups[1:]
+
+		if err != nil {
+			if p.skip(rg, err) {
+				continue
+			}
+			return err
+		}
+
+		p.fields = fields
+		p.rowGroupCount = rg.Rows
+		return nil
+	}
+}
+
+// readColumns reads the columns of rg, which is the next row group.
+func (p *ParquetReader) readColumns(rg parquet.RowGroup) (map[string]Field, error) {
+	fields := getFields(Fields(compressionUnknown, 0))
+	for _, col := range rg.Columns() {
+		name := strings.Join(col.MetaData.PathInSchema, ".")
+		f, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+		pages := p.pages[name]
+		if len(pages) <= p.index {
+			break
+		}
+
+		pg := pages[0]
+		if _, err := p.r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		if err := f.Read(p.r, pg); err != nil {
+			return nil, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		}
+	}
+	return fields, nil
+}
+
+// readRowGroups is readRowGroup for ReadParallel.  When the row groups
+// that were read last time have all been scanned, the next ones are read
+// at the same time and kept in the order they're in the file.
+func (p *ParquetReader) readRowGroups() error {
+	for len(p.decoded) == 0 {
+		if len(p.rowGroups) == 0 {
+			p.rowGroupCount = 0
+			return nil
+		}
+
+		n := p.parallel
+		if n > len(p.rowGroups) {
+			n = len(p.rowGroups)
+		}
+
+		decoded := make([]map[string]Field, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i, rg := range p.rowGroups[:n] {
+			pages := make(map[string]parquet.Page, len(rg.Columns()))
+			for _, col := range rg.Columns() {
+				name := strings.Join(col.MetaData.PathInSchema, ".")
+				pages[name] = p.pages[name][i]
+			}
+
+			wg.Add(1)
+			go func(i int, rg parquet.RowGroup, pages map[string]parquet.Page) {
+				defer wg.Done()
+				decoded[i], errs[i] = p.readRowGroupAt(rg, pages)
+			}(i, rg, pages)
+		}
+		wg.Wait()
+
+		// the row groups that were skipped are left out of
+		// both decoded and rowGroups so that they stay in step
+		var rowGroups []parquet.RowGroup
+		for i, err := range errs {
+			if err == nil {
+				p.decoded = append(p.decoded, decoded[i])
+				rowGroups = append(rowGroups, p.rowGroups[i])
+				continue
+			}
+			if !p.skip(p.rowGroups[i], err) {
+				return err
+			}
+		}
+
+		for name := range p.pages {
+			p.pages[name] = p.pages[name][n:]
+		}
+		p.rowGroups = append(rowGroups, p.rowGroups[n:]...)
+	}
+
+	p.fields = p.decoded[0]
+	p.decoded = p.decoded[1:]
+	p.rowGroupCount = p.rowGroups[0].Rows
+	p.rowGroups = p.rowGroups[1:]
+	return nil
+}
+
+// readRowGroupAt reads the columns of a row group with a reader of its own
+// (so it doesn't share a position with the other row groups being read).
+func (p *ParquetReader) readRowGroupAt(rg parquet.RowGroup, pages map[string]parquet.Page) (map[string]Field, error) {
+	r := io.NewSectionReader(p.readerAt, 0, math.MaxInt64)
+	fields := getFields(Fields(compressionUnknown, 0))
+	for _, col := range rg.Columns() {
+		name := strings.Join(col.MetaData.PathInSchema, ".")
+		f, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+
+		pg := pages[name]
+		if _, err := r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		if err := f.Read(r, pg); err != nil {
+			return nil, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		}
+	}
+	return fields, nil
+}
+
+func (p *ParquetReader) Rows() int64 {
+	return p.rows
+}
+
+// Next advances the reader to the next row, which can then be read with
+// Scan.  It returns false when there are no more rows or there was an error,
+// which can be checked with Error.  Only one row group is kept in memory at a
+// time: the next one is read when all of the current row group's rows have
+// been scanned.  Rows that don't match Where are skipped.
+func (p *ParquetReader) Next() bool {
+	for p.next() {
+		if p.where == nil {
+			return true
+		}
+
+		// the row has to be read to check it, so
+		// Scan copies the row that was read here
+		var x Person
+		p.scan(&x)
+		if p.where(x) {
+			p.row = &x
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ParquetReader) next() bool {
+	if p.err == nil && p.cursor >= p.rows {
+		return false
+	}
+	if p.rowGroupCursor >= p.rowGroupCount {
+		if p.err = p.ctxErr(); p.err != nil {
+			return false
+		}
+
+		p.err = p.readRowGroup()
+		if p.err != nil {
+			return false
+		}
+
+		// the rest of the row groups could have been skipped
+		if p.cursor >= p.rows {
+			return false
+		}
+	}
+
+	p.cursor++
+	p.rowGroupCursor++
+	return true
+}
+
+// columnNames maps the name of each field (like "Hobby.Name")
+// to the name of its column (like "hobby.name").
+var columnNames = map[string]string{
+	"ID":                      "id",
+	"Name":                    "name",
+	"Age":                     "age",
+	"Happiness":               "happiness",
+	"Sadness":                 "sadness",
+	"Code":                    "code",
+	"Funkiness":               "funkiness",
+	"Boldness":                "boldness",
+	"Lameness":                "lameness",
+	"Keen":                    "keen",
+	"Birthday":                "birthday",
+	"Anniversary":             "anniversary",
+	"BFF":                     "bff",
+	"Hungry":                  "hungry",
+	"Hobby.Name":              "hobby.name",
+	"Hobby.Difficulty":        "hobby.difficulty",
+	"Hobby.Skills.Name":       "hobby.skills.name",
+	"Hobby.Skills.Difficulty": "hobby.skills.difficulty",
+	"Friends.ID":              "friends.id",
+	"Friends.Name":            "friends.name",
+	"Friends.Age":             "friends.age",
+	"Sleepy":                  "Sleepy",
+	"Level":                   "level",
+	"Count":                   "count",
+	"Created":                 "created",
+	"Deleted":                 "deleted",
+	"Graduated":               "graduated",
+	"Payload":                 "payload",
+	"Thumbnail":               "thumbnail",
+	"UUID":                    "uuid",
+	"Checksum":                "checksum",
+	"Tags":                    "tags",
+	"Scores":                  "scores",
+	"Home.Street":             "home.street",
+	"Home.Geo.Lat":            "home.geo.lat",
+	"Home.Geo.Lon":            "home.geo.lon",
+}
+
+// columnEncodings are the encodings that were set for
+// columns by their fields' struct tags.
+var columnEncodings = map[string]sch.Encoding{}
+
+// lessFuncs compares two rows by each column that isn't repeated.
+var lessFuncs = map[string]func(a, b Person) bool{
+	"id":   func(a, b Person) bool { return a.ID < b.ID },
+	"name": func(a, b Person) bool { return a.Name < b.Name },
+	"age": func(a, b Person) bool {
+		if a.Age == nil {
+			return !(b.Age == nil)
+		}
+		if b.Age == nil {
+			return false
+		}
+		return *a.Age < *b.Age
+	},
+	"happiness": func(a, b Person) bool { return a.Happiness < b.Happiness },
+	"sadness": func(a, b Person) bool {
+		if a.Sadness == nil {
+			return !(b.Sadness == nil)
+		}
+		if b.Sadness == nil {
+			return false
+		}
+		return *a.Sadness < *b.Sadness
+	},
+	"code": func(a, b Person) bool {
+		if a.Code == nil {
+			return !(b.Code == nil)
+		}
+		if b.Code == nil {
+			return false
+		}
+		return *a.Code < *b.Code
+	},
+	"funkiness": func(a, b Person) bool { return a.Funkiness < b.Funkiness },
+	"boldness":  func(a, b Person) bool { return a.Boldness < b.Boldness },
+	"lameness": func(a, b Person) bool {
+		if a.Lameness == nil {
+			return !(b.Lameness == nil)
+		}
+		if b.Lameness == nil {
+			return false
+		}
+		return *a.Lameness < *b.Lameness
+	},
+	"keen": func(a, b Person) bool {
+		if a.Keen == nil {
+			return !(b.Keen == nil)
+		}
+		if b.Keen == nil {
+			return false
+		}
+		return !*a.Keen && *b.Keen
+	},
+	"birthday": func(a, b Person) bool { return a.Birthday < b.Birthday },
+	"anniversary": func(a, b Person) bool {
+		if a.Anniversary == nil {
+			return !(b.Anniversary == nil)
+		}
+		if b.Anniversary == nil {
+			return false
+		}
+		return *a.Anniversary < *b.Anniversary
+	},
+	"bff":    func(a, b Person) bool { return a.BFF < b.BFF },
+	"hungry": func(a, b Person) bool { return !a.Hungry && b.Hungry },
+	"hobby.name": func(a, b Person) bool {
+		if a.Hobby == nil {
+			return !(b.Hobby == nil)
+		}
+		if b.Hobby == nil {
+			return false
+		}
+		return a.Hobby.Name < b.Hobby.Name
+	},
+	"hobby.difficulty": func(a, b Person) bool {
+		if a.Hobby == nil || a.Hobby.Difficulty == nil {
+			return !(b.Hobby == nil || b.Hobby.Difficulty == nil)
+		}
+		if b.Hobby == nil || b.Hobby.Difficulty == nil {
+			return false
+		}
+		return *a.Hobby.Difficulty < *b.Hobby.Difficulty
+	},
+	"Sleepy": func(a, b Person) bool { return !a.Sleepy && b.Sleepy },
+	"level":  func(a, b Person) bool { return a.Level < b.Level },
+	"count": func(a, b Person) bool {
+		if a.Count == nil {
+			return !(b.Count == nil)
+		}
+		if b.Count == nil {
+			return false
+		}
+		return *a.Count < *b.Count
+	},
+	"created": func(a, b Person) bool { return a.Created.Before(b.Created) },
+	"deleted": func(a, b Person) bool {
+		if a.Deleted == nil {
+			return !(b.Deleted == nil)
+		}
+		if b.Deleted == nil {
+			return false
+		}
+		return (*a.Deleted).Before(*b.Deleted)
+	},
+	"graduated": func(a, b Person) bool {
+		if a.Graduated == nil {
+			return !(b.Graduated == nil)
+		}
+		if b.Graduated == nil {
+			return false
+		}
+		return (*a.Graduated).Before(*b.Graduated)
+	},
+	"payload": func(a, b Person) bool { return string(a.Payload) < string(b.Payload) },
+	"thumbnail": func(a, b Person) bool {
+		if a.Thumbnail == nil {
+			return !(b.Thumbnail == nil)
+		}
+		if b.Thumbnail == nil {
+			return false
+		}
+		return string(a.Thumbnail) < string(b.Thumbnail)
+	},
+	"uuid": func(a, b Person) bool { return string(a.UUID[:]) < string(b.UUID[:]) },
+	"checksum": func(a, b Person) bool {
+		if a.Checksum == nil {
+			return !(b.Checksum == nil)
+		}
+		if b.Checksum == nil {
+			return false
+		}
+		return string((*a.Checksum)[:]) < string((*b.Checksum)[:])
+	},
+	"home.street": func(a, b Person) bool {
+		if a.Home == nil {
+			return !(b.Home == nil)
+		}
+		if b.Home == nil {
+			return false
+		}
+		return a.Home.Street < b.Home.Street
+	},
+	"home.geo.lat": func(a, b Person) bool {
+		if a.Home == nil || a.Home.Geo == nil {
+			return !(b.Home == nil || b.Home.Geo == nil)
+		}
+		if b.Home == nil || b.Home.Geo == nil {
+			return false
+		}
+		return a.Home.Geo.Lat < b.Home.Geo.Lat
+	},
+	"home.geo.lon": func(a, b Person) bool {
+		if a.Home == nil || a.Home.Geo == nil || a.Home.Geo.Lon == nil {
+			return !(b.Home == nil || b.Home.Geo == nil || b.Home.Geo.Lon == nil)
+		}
+		if b.Home == nil || b.Home.Geo == nil || b.Home.Geo.Lon == nil {
+			return false
+		}
+		return *a.Home.Geo.Lon < *b.Home.Geo.Lon
+	},
+}
+
+// Less returns a function that reports whether row a comes before row b
+// when the rows are sorted by a column (given by its name or by its field's
+// name), which can be used with sort.Slice to sort the rows before they are
+// added (see SortedBy).  Nulls come first, or last when descending.
+// Repeated columns can't be sorted by.
+func Less(column string, descending bool) (func(a, b Person) bool, error) {
+	if col, ok := columnNames[column]; ok {
+		column = col
+	}
+
+	less, ok := lessFuncs[column]
+	if !ok {
+		if _, ok := getFields(Fields(compressionUnknown, 0))[column]; ok {
+			return nil, fmt.Errorf("column %s is repeated, rows can't be sorted by it", column)
+		}
+		return nil, fmt.Errorf("unknown column: %s", column)
+	}
+
+	if descending {
+		return func(a, b Person) bool { return less(b, a) }, nil
+	}
+	return less, nil
+}
+
+// ToMap returns a map from the name of each of x's columns (like
+// "hobby.name") to its value, which can be marshaled to JSON.  Nil values
+// are left out, the values of repeated columns are slices and maps are
+// under the name of their group (see parquet.ToMap).
+func ToMap(x Person) map[string]interface{} {
+	return parquet.ToMap(x, columnNames)
+}
+
+// FromMap returns the row that ToMap returned m for.  m can also be
+// unmarshaled from JSON (use json.Decoder's UseNumber if the row has 64
+// bit integers that don't fit in a float64).
+func FromMap(m map[string]interface{}) (Person, error) {
+	var x Person
+	err := parquet.FromMap(m, &x, columnNames)
+	return x, err
+}
+
+// Equal reports whether x and other have the same value in each column
+// (see parquet.RowsEqual).  Unlike reflect.DeepEqual, a nil slice is equal
+// to an empty one and times in different locations are equal if they're
+// the same instant, but a nil pointer is only equal to another nil pointer.
+func (x Person) Equal(other Person) bool {
+	return parquet.RowsEqual(x, other, columnNames)
+}
+
+// ReadColumn reads all the values of a single column without reading any
+// of the other columns.  The column is given by its name (like "hobby.name")
+// or by its field's name (like "Hobby.Name").  dest must be a pointer to a
+// slice of the column's type (the non-pointer type for optional fields).
+// Optional and repeated columns only put their non-nil values in dest, so
+// their definition and repetition levels are returned too.
+func (p *ParquetReader) ReadColumn(name string, dest interface{}) (Levels, error) {
+	if col, ok := columnNames[name]; ok {
+		name = col
+	}
+
+	f, ok := getFields(Fields(compressionUnknown, 0))[name]
+	if !ok {
+		return Levels{}, fmt.Errorf("unknown column: %s", name)
+	}
+
+	pages, err := p.meta.Pages()
+	if err != nil {
+		return Levels{}, err
+	}
+
+	// put the reader back so reading rows isn't affected
+	pos, err := p.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Levels{}, err
+	}
+	defer p.r.Seek(pos, io.SeekStart)
+
+	for _, pg := range pages[name] {
+		if _, err := p.r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return Levels{}, err
+		}
+
+		if err := f.Read(p.r, pg); err != nil {
+			return Levels{}, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		}
+	}
+
+	defs, reps := f.Levels()
+	return Levels{Name: f.Name(), Defs: defs, Reps: reps}, f.copyTo(dest)
+}
+
+// NumRowGroups returns the number of row groups in the file (including
+// the ones that are skipped by Filter or MaxRows) from its footer.
+func (p *ParquetReader) NumRowGroups() int {
+	return len(p.meta.RowGroups())
+}
+
+// Metadata returns the key/value metadata from the file's footer (see
+// SetMetadata), which is empty if the file doesn't have any.
+func (p *ParquetReader) Metadata() map[string]string {
+	return p.meta.KeyValueMetadata()
+}
+
+// CreatedBy returns the application that wrote the file (see SetCreatedBy)
+// from its footer, which is empty if the footer doesn't say.
+func (p *ParquetReader) CreatedBy() string {
+	return p.meta.CreatedBy()
+}
+
+// NumRows returns the number of rows in the file (the sum of the rows of
+// its row groups) from its footer.  Unlike Rows, it includes the rows that
+// are skipped by Filter or MaxRows.
+func (p *ParquetReader) NumRows() int64 {
+	var n int64
+	for _, rg := range p.meta.RowGroups() {
+		n += rg.Rows
+	}
+	return n
+}
+
+// ReadRowGroup reads all of the rows of the row group at index i of the
+// file (see NumRowGroups), seeking straight to its column chunks, and
+// doesn't change which row Next and Scan are on.  Filter and MaxRows don't
+// apply to it (ReadContext does).  A reader can't be shared between
+// goroutines, so each worker that reads row groups in parallel needs its
+// own reader.
+func (p *ParquetReader) ReadRowGroup(i int) ([]Person, error) {
+	rowGroups := p.meta.RowGroups()
+	if i < 0 || i >= len(rowGroups) {
+		return nil, fmt.Errorf("row group %d is out of range, the file has %d row groups", i, len(rowGroups))
+	}
+
+	if err := p.ctxErr(); err != nil {
+		return nil, err
+	}
+
+	pages, err := p.meta.Pages()
+	if err != nil {
+		return nil, err
+	}
+
+	// put the reader back so reading rows isn't affected
+	pos, err := p.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	defer p.r.Seek(pos, io.SeekStart)
+
+	rg := rowGroups[i]
+	fields := getFields(Fields(compressionUnknown, 0))
+	for _, col := range rg.Columns() {
+		name := strings.Join(col.MetaData.PathInSchema, ".")
+		f, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+
+		pg := pages[name][i]
+		if _, err := p.r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		if err := f.Read(p.r, pg); err != nil {
+			return nil, fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		}
+	}
+
+	out := make([]Person, rg.Rows)
+	for j := range out {
+		for _, name := range p.fieldNames {
+			fields[name].Scan(&out[j])
+		}
+	}
+	return out, nil
+}
+
+// MayContain uses the bloom filters of a column (given by its name or by
+// its field's name) to check whether any of the row groups can have value,
+// which must be a string or a []byte.  A row group whose column chunk
+// doesn't have a bloom filter (see BloomFilter) can have any value, and
+// a value that may be in a row group might not be.
+func (p *ParquetReader) MayContain(name string, value interface{}) (bool, error) {
+	if col, ok := columnNames[name]; ok {
+		name = col
+	}
+
+	if _, ok := getFields(Fields(compressionUnknown, 0))[name]; !ok {
+		return false, fmt.Errorf("unknown column: %s", name)
+	}
+
+	var v []byte
+	switch x := value.(type) {
+	case string:
+		v = []byte(x)
+	case []byte:
+		v = x
+	default:
+		return false, fmt.Errorf("invalid value %v (%T), it must be a string or a []byte", value, value)
+	}
+
+	// put the reader back so reading rows isn't affected
+	pos, err := p.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+	defer p.r.Seek(pos, io.SeekStart)
+
+	return p.meta.MayContain(p.r, name, v)
+}
+
+// WriteCSV writes the rest of the rows to w as CSV with a header of the
+// columns' names.  columns (given by their names or by their fields'
+// names) are the columns that are written, in the order of Fields, and
+// all of the columns are written if none are given.  Nulls are empty
+// fields and repeated columns are JSON arrays (see parquet.CSVRecord).
+func (p *ParquetReader) WriteCSV(w io.Writer, columns ...string) error {
+	include := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if c, ok := columnNames[col]; ok {
+			col = c
+		}
+
+		if _, ok := getFields(Fields(compressionUnknown, 0))[col]; !ok {
+			return fmt.Errorf("unknown column: %s", col)
+		}
+		include[col] = true
+	}
+
+	var names []string
+	for _, f := range Fields(compressionUnknown, 0) {
+		if len(include) == 0 || include[f.Name()] {
+			names = append(names, f.Name())
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+
+	for p.Next() {
+		var x Person
+		p.Scan(&x)
+		rec, err := parquet.CSVRecord(ToMap(x), names)
+		if err != nil {
+			return err
+		}
+
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	if err := p.Error(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Scan copies the current row into x.
+func (p *ParquetReader) Scan(x *Person) {
+	if p.err != nil {
+		return
+	}
+
+	if p.row != nil {
+		*x = *p.row
+		p.row = nil
+		return
+	}
+
+	p.scan(x)
+}
+
+func (p *ParquetReader) scan(x *Person) {
+	for _, name := range p.fieldNames {
+		f := p.fields[name]
+		f.Scan(x)
+	}
+}
+
+type Int32Field struct {
+	vals []int32
+	parquet.RequiredField
+	read  func(r Person) int32
+	write func(r *Person, vals []int32)
+	stats *int32stats
+}
+
+func NewInt32Field(read func(r Person) int32, write func(r *Person, vals []int32), path []string, opts ...func(*parquet.RequiredField)) *Int32Field {
+	return &Int32Field{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newInt32stats(),
+	}
+}
+
+func (f *Int32Field) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int32Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *Int32Field) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]int32, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Int32Field) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *Int32Field) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *Int32Field) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *Int32Field) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]int32)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]int32", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Int32Field) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type StringField struct {
+	parquet.RequiredField
+	vals  []string
+	read  func(r Person) string
+	write func(r *Person, vals []string)
+	stats *stringStats
+}
+
+func NewStringField(read func(r Person) string, write func(r *Person, vals []string), path []string, opts ...func(*parquet.RequiredField)) *StringField {
+	return &StringField{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newStringStats(),
+	}
+}
+
+func (f *StringField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *StringField) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(v)
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.vals), f.stats)
+	}
+
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, s := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(s)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *StringField) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *StringField) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash([]byte(v)))
+	}
+	return hashes
+}
+
+func (f *StringField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, pg.N)
+		if err != nil {
+			return err
+		}
+		for _, b := range vals {
+			f.vals = append(f.vals, parquet.NoCopyString(b))
+		}
+		return nil
+	}
+
+	// s is reused for each value (string copies it)
+	var s []byte
+	bs := make([]byte, 4)
+	for j := 0; j < pg.N; j++ {
+		if _, err := io.ReadFull(rr, bs); err != nil {
+			return err
+		}
+		x := int(binary.LittleEndian.Uint32(bs))
+		if cap(s) < x {
+			s = make([]byte, x)
+		}
+		s = s[:x]
+		if _, err := io.ReadFull(rr, s); err != nil {
+			return err
+		}
+
+		f.vals = append(f.vals, string(s))
+	}
+	return nil
+}
+
+func (f *StringField) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *StringField) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *StringField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]string)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]string", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *StringField) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type Int32OptionalField struct {
+	parquet.OptionalField
+	vals  []int32
+	read  func(r Person, vals []int32, defs, reps []uint8) ([]int32, []uint8, []uint8)
+	write func(r *Person, vals []int32, defs, reps []uint8) (int, int)
+	stats *int32optionalStats
+}
+
+func NewInt32OptionalField(read func(r Person, vals []int32, defs, reps []uint8) ([]int32, []uint8, []uint8), write func(r *Person, vals []int32, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Int32OptionalField {
+	return &Int32OptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newint32optionalStats(maxDef(types)),
+	}
+}
+
+func (f *Int32OptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int32Type, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *Int32OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *Int32OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]int32, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Int32OptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *Int32OptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *Int32OptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]int32)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]int32", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Int32OptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type Int64Field struct {
+	vals []int64
+	parquet.RequiredField
+	read  func(r Person) int64
+	write func(r *Person, vals []int64)
+	stats *int64stats
+}
+
+func NewInt64Field(read func(r Person) int64, write func(r *Person, vals []int64), path []string, opts ...func(*parquet.RequiredField)) *Int64Field {
+	return &Int64Field{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newInt64stats(),
+	}
+}
+
+func (f *Int64Field) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int64Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *Int64Field) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]int64, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Int64Field) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 8)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint64(bs, uint64(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *Int64Field) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *Int64Field) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *Int64Field) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]int64)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]int64", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Int64Field) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type Int64OptionalField struct {
+	parquet.OptionalField
+	vals  []int64
+	read  func(r Person, vals []int64, defs, reps []uint8) ([]int64, []uint8, []uint8)
+	write func(r *Person, vals []int64, defs, reps []uint8) (int, int)
+	stats *int64optionalStats
+}
+
+func NewInt64OptionalField(read func(r Person, vals []int64, defs, reps []uint8) ([]int64, []uint8, []uint8), write func(r *Person, vals []int64, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Int64OptionalField {
+	return &Int64OptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newint64optionalStats(maxDef(types)),
+	}
+}
+
+func (f *Int64OptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int64Type, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *Int64OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 8)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint64(bs, uint64(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *Int64OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]int64, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Int64OptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *Int64OptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *Int64OptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]int64)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]int64", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Int64OptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type StringOptionalField struct {
+	parquet.OptionalField
+	vals  []string
+	read  func(r Person, vals []string, def, rep []uint8) ([]string, []uint8, []uint8)
+	write func(r *Person, vals []string, def, rep []uint8) (int, int)
+	stats *stringOptionalStats
+}
+
+func NewStringOptionalField(read func(r Person, vals []string, def, rep []uint8) ([]string, []uint8, []uint8), write func(r *Person, vals []string, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *StringOptionalField {
+	return &StringOptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newStringOptionalStats(maxDef(types)),
+	}
+}
+
+func (f *StringOptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *StringOptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *StringOptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *StringOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(v)
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.Defs), f.stats)
+	}
+
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, s := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(s)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *StringOptionalField) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *StringOptionalField) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash([]byte(v)))
+	}
+	return hashes
+}
+
+func (f *StringOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	n := f.Values() - len(f.vals)
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, n)
+		if err != nil {
+			return err
+		}
+		for _, b := range vals {
+			f.vals = append(f.vals, parquet.NoCopyString(b))
+		}
+		return nil
+	}
+
+	// s is reused for each value (string copies it)
+	var s []byte
+	bs := make([]byte, 4)
+	for j := 0; j < n; j++ {
+		if _, err := io.ReadFull(rr, bs); err != nil {
+			return err
+		}
+		x := int(binary.LittleEndian.Uint32(bs))
+		if cap(s) < x {
+			s = make([]byte, x)
+		}
+		s = s[:x]
+		if _, err := io.ReadFull(rr, s); err != nil {
+			return err
+		}
+
+		f.vals = append(f.vals, string(s))
+	}
+	return nil
+}
+
+func (f *StringOptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]string)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]string", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *StringOptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type Float32Field struct {
+	vals []float32
+	parquet.RequiredField
+	read  func(r Person) float32
+	write func(r *Person, vals []float32)
+	stats *float32stats
+}
+
+func NewFloat32Field(read func(r Person) float32, write func(r *Person, vals []float32), path []string, opts ...func(*parquet.RequiredField)) *Float32Field {
+	return &Float32Field{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newFloat32stats(),
+	}
+}
+
+func (f *Float32Field) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Float32Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *Float32Field) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]float32, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Float32Field) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *Float32Field) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *Float32Field) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *Float32Field) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]float32)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]float32", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Float32Field) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type Float64Field struct {
+	vals []float64
+	parquet.RequiredField
+	read  func(r Person) float64
+	write func(r *Person, vals []float64)
+	stats *float64stats
+}
+
+func NewFloat64Field(read func(r Person) float64, write func(r *Person, vals []float64), path []string, opts ...func(*parquet.RequiredField)) *Float64Field {
+	return &Float64Field{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newFloat64stats(),
+	}
+}
+
+func (f *Float64Field) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Float64Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *Float64Field) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]float64, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Float64Field) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 8)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint64(bs, math.Float64bits(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *Float64Field) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *Float64Field) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *Float64Field) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]float64)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]float64", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Float64Field) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type Float32OptionalField struct {
+	parquet.OptionalField
+	vals  []float32
+	read  func(r Person, vals []float32, defs, reps []uint8) ([]float32, []uint8, []uint8)
+	write func(r *Person, vals []float32, defs, reps []uint8) (int, int)
+	stats *float32optionalStats
+}
+
+func NewFloat32OptionalField(read func(r Person, vals []float32, defs, reps []uint8) ([]float32, []uint8, []uint8), write func(r *Person, vals []float32, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Float32OptionalField {
+	return &Float32OptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newfloat32optionalStats(maxDef(types)),
+	}
+}
+
+func (f *Float32OptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Float32Type, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *Float32OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *Float32OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]float32, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Float32OptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *Float32OptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *Float32OptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]float32)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]float32", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Float32OptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type BoolOptionalField struct {
+	parquet.OptionalField
+	vals  []bool
+	read  func(r Person, vals []bool, defs, reps []uint8) ([]bool, []uint8, []uint8)
+	write func(r *Person, vals []bool, defs, reps []uint8) (int, int)
+	stats *boolOptionalStats
+}
+
+func NewBoolOptionalField(read func(r Person, vals []bool, defs, reps []uint8) ([]bool, []uint8, []uint8), write func(r *Person, vals []bool, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *BoolOptionalField {
+	return &BoolOptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newBoolOptionalStats(maxDef(types)),
+	}
+}
+
+func (f *BoolOptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *BoolOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, sizes, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v, err := parquet.GetBools(rr, f.Values()-len(f.vals), sizes)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *BoolOptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *BoolOptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *BoolOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	return f.DoWriteBools(w, meta, f.vals, len(f.Defs), f.stats)
+}
+
+func (f *BoolOptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]bool)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]bool", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *BoolOptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type Uint32Field struct {
+	vals []uint32
+	parquet.RequiredField
+	read  func(r Person) uint32
+	write func(r *Person, vals []uint32)
+	stats *uint32stats
+}
+
+func NewUint32Field(read func(r Person) uint32, write func(r *Person, vals []uint32), path []string, opts ...func(*parquet.RequiredField)) *Uint32Field {
+	return &Uint32Field{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newUint32stats(),
+	}
+}
+
+func (f *Uint32Field) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Uint32Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *Uint32Field) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]uint32, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Uint32Field) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, v)
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *Uint32Field) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *Uint32Field) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *Uint32Field) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]uint32)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]uint32", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Uint32Field) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type Uint64OptionalField struct {
+	parquet.OptionalField
+	vals  []uint64
+	read  func(r Person, vals []uint64, defs, reps []uint8) ([]uint64, []uint8, []uint8)
+	write func(r *Person, vals []uint64, defs, reps []uint8) (int, int)
+	stats *uint64optionalStats
+}
+
+func NewUint64OptionalField(read func(r Person, vals []uint64, defs, reps []uint8) ([]uint64, []uint8, []uint8), write func(r *Person, vals []uint64, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Uint64OptionalField {
+	return &Uint64OptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newuint64optionalStats(maxDef(types)),
+	}
+}
+
+func (f *Uint64OptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Uint64Type, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *Uint64OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 8)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint64(bs, v)
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *Uint64OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]uint64, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Uint64OptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *Uint64OptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *Uint64OptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]uint64)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]uint64", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Uint64OptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type BoolField struct {
+	parquet.RequiredField
+	vals  []bool
+	read  func(r Person) bool
+	write func(r *Person, vals []bool)
+	stats *boolStats
+}
+
+func NewBoolField(read func(r Person) bool, write func(r *Person, vals []bool), path []string, opts ...func(*parquet.RequiredField)) *BoolField {
+	return &BoolField{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+	}
+}
+
+func (f *BoolField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *BoolField) Write(w io.Writer, meta *parquet.Metadata) error {
+	return f.DoWriteBools(w, meta, f.vals, len(f.vals), newBoolStats())
+}
+
+func (f *BoolField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, sizes, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v, err := parquet.GetBools(rr, int(pg.N), sizes)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *BoolField) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *BoolField) Add(r Person) {
+	v := f.read(r)
+	f.vals = append(f.vals, v)
+}
+
+func (f *BoolField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]bool)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]bool", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *BoolField) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type Int8Field struct {
+	vals []int8
+	parquet.RequiredField
+	read  func(r Person) int8
+	write func(r *Person, vals []int8)
+	stats *int8stats
+}
+
+func NewInt8Field(read func(r Person) int8, write func(r *Person, vals []int8), path []string, opts ...func(*parquet.RequiredField)) *Int8Field {
+	return &Int8Field{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newInt8stats(),
+	}
+}
+
+func (f *Int8Field) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int8Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *Int8Field) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]int32, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	if err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		if x < math.MinInt8 || x > math.MaxInt8 {
+			return fmt.Errorf("value %d is out of range for int8 field %s", x, f.Name())
+		}
+		f.vals = append(f.vals, int8(x))
+	}
+	return nil
+}
+
+func (f *Int8Field) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *Int8Field) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *Int8Field) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *Int8Field) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]int8)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]int8", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Int8Field) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type Int16OptionalField struct {
+	parquet.OptionalField
+	vals  []int16
+	read  func(r Person, vals []int16, defs, reps []uint8) ([]int16, []uint8, []uint8)
+	write func(r *Person, vals []int16, defs, reps []uint8) (int, int)
+	stats *int16optionalStats
+}
+
+func NewInt16OptionalField(read func(r Person, vals []int16, defs, reps []uint8) ([]int16, []uint8, []uint8), write func(r *Person, vals []int16, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Int16OptionalField {
+	return &Int16OptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newint16optionalStats(maxDef(types)),
+	}
+}
+
+func (f *Int16OptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int16Type, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *Int16OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *Int16OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]int32, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	if err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		if x < math.MinInt16 || x > math.MaxInt16 {
+			return fmt.Errorf("value %d is out of range for int16 field %s", x, f.Name())
+		}
+		f.vals = append(f.vals, int16(x))
+	}
+	return nil
+}
+
+func (f *Int16OptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *Int16OptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *Int16OptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]int16)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]int16", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Int16OptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type TimestampField struct {
+	parquet.RequiredField
+	vals  []time.Time
+	read  func(r Person) time.Time
+	write func(r *Person, vals []time.Time)
+	stats *timestampStats
+}
+
+func NewTimestampField(read func(r Person) time.Time, write func(r *Person, vals []time.Time), path []string, opts ...func(*parquet.RequiredField)) *TimestampField {
+	return &TimestampField{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newTimestampStats(),
+	}
+}
+
+func (f *TimestampField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: TimestampType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *TimestampField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	if pg.Type == sch.Type_INT96 {
+		v, err := parquet.GetInt96Times(rr, int(pg.N))
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, v...)
+		return nil
+	}
+
+	v := make([]int64, int(pg.N))
+	if err := binary.Read(rr, binary.LittleEndian, &v); err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		f.vals = append(f.vals, time.Unix(x/1000, (x%1000)*int64(time.Millisecond)).UTC())
+	}
+	return nil
+}
+
+func (f *TimestampField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 8)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint64(bs, uint64(v.Unix()*1000+int64(v.Nanosecond())/int64(time.Millisecond)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *TimestampField) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *TimestampField) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v.Unix()*1000 + int64(v.Nanosecond())/int64(time.Millisecond))
+	f.vals = append(f.vals, v)
+}
+
+func (f *TimestampField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]time.Time)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]time.Time", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *TimestampField) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type TimestampOptionalField struct {
+	parquet.OptionalField
+	vals  []time.Time
+	read  func(r Person, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8)
+	write func(r *Person, vals []time.Time, defs, reps []uint8) (int, int)
+	stats *timestampOptionalStats
+}
+
+func NewTimestampOptionalField(read func(r Person, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8), write func(r *Person, vals []time.Time, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *TimestampOptionalField {
+	return &TimestampOptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newTimestampOptionalStats(maxDef(types)),
+	}
+}
+
+func (f *TimestampOptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: TimestampType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *TimestampOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 8)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint64(bs, uint64(v.Unix()*1000+int64(v.Nanosecond())/int64(time.Millisecond)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *TimestampOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	if pg.Type == sch.Type_INT96 {
+		v, err := parquet.GetInt96Times(rr, f.Values()-len(f.vals))
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, v...)
+		return nil
+	}
+
+	v := make([]int64, f.Values()-len(f.vals))
+	if err := binary.Read(rr, binary.LittleEndian, &v); err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		f.vals = append(f.vals, time.Unix(x/1000, (x%1000)*int64(time.Millisecond)).UTC())
+	}
+	return nil
+}
+
+func (f *TimestampOptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *TimestampOptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *TimestampOptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]time.Time)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]time.Time", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *TimestampOptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type DateOptionalField struct {
+	parquet.OptionalField
+	vals  []time.Time
+	read  func(r Person, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8)
+	write func(r *Person, vals []time.Time, defs, reps []uint8) (int, int)
+	stats *dateOptionalStats
+}
+
+func NewDateOptionalField(read func(r Person, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8), write func(r *Person, vals []time.Time, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *DateOptionalField {
+	return &DateOptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newDateOptionalStats(maxDef(types)),
+	}
+}
+
+func (f *DateOptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: DateType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *DateOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(int32(v.Truncate(24*time.Hour).Unix()/86400)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *DateOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	if pg.Type == sch.Type_INT96 {
+		v, err := parquet.GetInt96Times(rr, f.Values()-len(f.vals))
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, v...)
+		return nil
+	}
+
+	v := make([]int32, f.Values()-len(f.vals))
+	if err := binary.Read(rr, binary.LittleEndian, &v); err != nil {
+		return err
+	}
+
+	for _, x := range v {
+		f.vals = append(f.vals, time.Unix(int64(x)*86400, 0).UTC())
+	}
+	return nil
+}
+
+func (f *DateOptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *DateOptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *DateOptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]time.Time)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]time.Time", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *DateOptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type BytesField struct {
+	parquet.RequiredField
+	vals  [][]byte
+	read  func(r Person) []byte
+	write func(r *Person, vals [][]byte)
+	stats *bytesStats
+}
+
+func NewBytesField(read func(r Person) []byte, write func(r *Person, vals [][]byte), path []string, opts ...func(*parquet.RequiredField)) *BytesField {
+	return &BytesField{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newBytesStats(),
+	}
+}
+
+func (f *BytesField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: ByteArrayType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *BytesField) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(string(v))
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.vals), f.stats)
+	}
+
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, b := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(b)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *BytesField) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(string(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BytesField) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash(v))
+	}
+	return hashes
+}
+
+func (f *BytesField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, pg.N)
+		if err != nil {
+			return err
+		}
+		for _, b := range vals {
+			// empty values are read as nil (see below)
+			if len(b) == 0 {
+				b = nil
+			}
+			f.vals = append(f.vals, b)
+		}
+		return nil
+	}
+
+	bs := make([]byte, 4)
+	for j := 0; j < pg.N; j++ {
+		if _, err := io.ReadFull(rr, bs); err != nil {
+			return err
+		}
+		x := binary.LittleEndian.Uint32(bs)
+		// a required column can't tell a nil slice from an
+		// empty one, so empty values are read back as nil.
+		var b []byte
+		if x > 0 {
+			b = make([]byte, x)
+			if _, err := io.ReadFull(rr, b); err != nil {
+				return err
+			}
+		}
+
+		f.vals = append(f.vals, b)
+	}
+	return nil
+}
+
+func (f *BytesField) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *BytesField) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *BytesField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[][]byte)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[][]byte", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *BytesField) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type BytesOptionalField struct {
+	parquet.OptionalField
+	vals  [][]byte
+	read  func(r Person, vals [][]byte, def, rep []uint8) ([][]byte, []uint8, []uint8)
+	write func(r *Person, vals [][]byte, def, rep []uint8) (int, int)
+	stats *bytesOptionalStats
+}
+
+func NewBytesOptionalField(read func(r Person, vals [][]byte, def, rep []uint8) ([][]byte, []uint8, []uint8), write func(r *Person, vals [][]byte, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *BytesOptionalField {
+	return &BytesOptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newBytesOptionalStats(maxDef(types)),
+	}
+}
+
+func (f *BytesOptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: ByteArrayType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *BytesOptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *BytesOptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *BytesOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	if d := f.Dictionary(); d != nil {
+		ids := make([]uint32, len(f.vals))
+		for i, v := range f.vals {
+			ids[i] = d.Index(string(v))
+		}
+		return f.DoWrite(w, meta, d.Encode(ids), len(f.Defs), f.stats)
+	}
+
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, b := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(b)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *BytesOptionalField) addToDictionary(d *parquet.Dictionary) bool {
+	for _, v := range f.vals {
+		if !d.Add(string(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BytesOptionalField) bloomHashes(hashes []uint64) []uint64 {
+	for _, v := range f.vals {
+		hashes = append(hashes, parquet.BloomHash(v))
+	}
+	return hashes
+}
+
+func (f *BytesOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	n := f.Values() - len(f.vals)
+	if pg.NoCopy {
+		vals, err := parquet.ByteArrays(rr, n)
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, vals...)
+		return nil
+	}
+
+	bs := make([]byte, 4)
+	for j := 0; j < n; j++ {
+		if _, err := io.ReadFull(rr, bs); err != nil {
+			return err
+		}
+		x := binary.LittleEndian.Uint32(bs)
+		b := make([]byte, x)
+		if _, err := io.ReadFull(rr, b); err != nil {
+			return err
+		}
+
+		f.vals = append(f.vals, b)
+	}
+	return nil
+}
+
+func (f *BytesOptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[][]byte)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[][]byte", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *BytesOptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type UUIDField struct {
+	parquet.RequiredField
+	vals  [][16]byte
+	read  func(r Person) [16]byte
+	write func(r *Person, vals [][16]byte)
+	stats *uuidStats
+}
+
+func NewUUIDField(read func(r Person) [16]byte, write func(r *Person, vals [][16]byte), path []string, opts ...func(*parquet.RequiredField)) *UUIDField {
+	return &UUIDField{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newUuidStats(),
+	}
+}
+
+func (f *UUIDField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: UUIDType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *UUIDField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	for _, v := range f.vals {
+		if _, err := buf.Write(v[:]); err != nil {
+			return err
+		}
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *UUIDField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	b := make([]byte, pg.N*16)
+	if _, err := io.ReadFull(rr, b); err != nil {
+		return fmt.Errorf("field %s: values are shorter than 16 bytes: %s", f.Name(), err)
+	}
+
+	if n, _ := rr.Read(make([]byte, 1)); n > 0 {
+		return fmt.Errorf("field %s: values are longer than 16 bytes", f.Name())
+	}
+
+	for j := 0; j < pg.N; j++ {
+		var v [16]byte
+		copy(v[:], b[j*16:])
+		f.vals = append(f.vals, v)
+	}
+	return nil
+}
+
+func (f *UUIDField) Scan(r *Person) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *UUIDField) Add(r Person) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *UUIDField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[][16]byte)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[][16]byte", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *UUIDField) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+type FixedLenByteArray8OptionalField struct {
+	parquet.OptionalField
+	vals  [][8]byte
+	read  func(r Person, vals [][8]byte, defs, reps []uint8) ([][8]byte, []uint8, []uint8)
+	write func(r *Person, vals [][8]byte, defs, reps []uint8) (int, int)
+	stats *fixedLenByteArray8OptionalStats
+}
+
+func NewFixedLenByteArray8OptionalField(read func(r Person, vals [][8]byte, defs, reps []uint8) ([][8]byte, []uint8, []uint8), write func(r *Person, vals [][8]byte, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *FixedLenByteArray8OptionalField {
+	return &FixedLenByteArray8OptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newFixedLenByteArray8OptionalStats(maxDef(types)),
+	}
+}
+
+func pfixedlenbytearray8(v [8]byte) *[8]byte {
+	return &v
+}
+
+func (f *FixedLenByteArray8OptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: FixedLenByteArrayType(8), RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *FixedLenByteArray8OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	for _, v := range f.vals {
+		if _, err := buf.Write(v[:]); err != nil {
+			return err
+		}
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *FixedLenByteArray8OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	n := f.Values() - len(f.vals)
+	b := make([]byte, n*8)
+	if _, err := io.ReadFull(rr, b); err != nil {
+		return fmt.Errorf("field %s: values are shorter than 8 bytes: %s", f.Name(), err)
+	}
+
+	if n, _ := rr.Read(make([]byte, 1)); n > 0 {
+		return fmt.Errorf("field %s: values are longer than 8 bytes", f.Name())
+	}
+
+	for j := 0; j < n; j++ {
+		var v [8]byte
+		copy(v[:], b[j*8:])
+		f.vals = append(f.vals, v)
+	}
+	return nil
+}
+
+func (f *FixedLenByteArray8OptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *FixedLenByteArray8OptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *FixedLenByteArray8OptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[][8]byte)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[][8]byte", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *FixedLenByteArray8OptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type Float64OptionalField struct {
+	parquet.OptionalField
+	vals  []float64
+	read  func(r Person, vals []float64, defs, reps []uint8) ([]float64, []uint8, []uint8)
+	write func(r *Person, vals []float64, defs, reps []uint8) (int, int)
+	stats *float64optionalStats
+}
+
+func NewFloat64OptionalField(read func(r Person, vals []float64, defs, reps []uint8) ([]float64, []uint8, []uint8), write func(r *Person, vals []float64, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Float64OptionalField {
+	return &Float64OptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newfloat64optionalStats(maxDef(types)),
+	}
+}
+
+func (f *Float64OptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Float64Type, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *Float64OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 8)
+	for _, v := range f.vals {
+		binary.LittleEndian.PutUint64(bs, math.Float64bits(v))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *Float64OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]float64, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *Float64OptionalField) Add(r Person) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *Float64OptionalField) Scan(r *Person) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *Float64OptionalField) copyTo(dest interface{}) error {
+	d, ok := dest.(*[]float64)
+	if !ok {
+		return fmt.Errorf("column %s can't be read in to a %T, it must be a *[]float64", f.Name(), dest)
+	}
+	*d = f.vals
+	return nil
+}
+
+func (f *Float64OptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+type int32stats struct {
+	min     int32
+	max     int32
+	nonNils int64
+}
+
+func newInt32stats() *int32stats {
+	return &int32stats{}
+}
+
+func (i *int32stats) add(val int32) {
+	if i.nonNils == 0 || val < i.min {
+		i.min = val
+	}
+	if i.nonNils == 0 || val > i.max {
+		i.max = val
+	}
+	i.nonNils++
+}
+
+func (f *int32stats) bytes(v int32) []byte {
+	bs := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bs, uint32(v))
+	return bs
+}
+
+func (f *int32stats) NullCount() *int64 {
+	return new(int64)
+}
+
+func (f *int32stats) DistinctCount() *int64 {
+	return nil
+}
+
+func (f *int32stats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
+	return f.bytes(f.min)
+}
+
+func (f *int32stats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
+	return f.bytes(f.max)
+}
+
+const nilString = "__#NIL#__"
+
+type stringStats struct {
+	min string
+	max string
+}
+
+func newStringStats() *stringStats {
+	return &stringStats{
+		min: nilString,
+		max: nilString,
+	}
+}
+
+func (s *stringStats) add(val string) {
+	if s.min == nilString {
+		s.min = val
+	} else {
+		if val < s.min {
+			s.min = val
+		}
+	}
+	if s.max == nilString {
+		s.max = val
+	} else {
+		if val > s.max {
+			s.max = val
+		}
 	}
+}
 
-	v := make([]float32, f.Values()-len(f.vals))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+func (s *stringStats) NullCount() *int64 {
+	return new(int64)
 }
 
-func (f *Float32OptionalField) Add(r Person) {
-	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
-	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
-	f.vals = vals
-	f.Defs = defs
-	f.Reps = reps
+func (s *stringStats) DistinctCount() *int64 {
+	return nil
 }
 
-func (f *Float32OptionalField) Scan(r *Person) {
-	if len(f.Defs) == 0 {
-		return
+func (s *stringStats) Min() []byte {
+	if s.min == nilString {
+		return nil
 	}
+	return []byte(s.min)
+}
 
-	v, l := f.write(r, f.vals, f.Defs, f.Reps)
-	f.vals = f.vals[v:]
-	f.Defs = f.Defs[l:]
-	if len(f.Reps) > 0 {
-		f.Reps = f.Reps[l:]
+func (s *stringStats) Max() []byte {
+	if s.max == nilString {
+		return nil
 	}
+	return []byte(s.max)
 }
 
-func (f *Float32OptionalField) Levels() ([]uint8, []uint8) {
-	return f.Defs, f.Reps
+type int32optionalStats struct {
+	min     int32
+	max     int32
+	nils    int64
+	nonNils int64
+	maxDef  uint8
 }
 
-type BoolOptionalField struct {
-	parquet.OptionalField
-	vals  []bool
-	read  func(r Person, vals []bool, defs, reps []uint8) ([]bool, []uint8, []uint8)
-	write func(r *Person, vals []bool, defs, reps []uint8) (int, int)
-	stats *boolOptionalStats
+func newint32optionalStats(d uint8) *int32optionalStats {
+	return &int32optionalStats{
+		maxDef: d,
+	}
 }
 
-func NewBoolOptionalField(read func(r Person, vals []bool, defs, reps []uint8) ([]bool, []uint8, []uint8), write func(r *Person, vals []bool, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *BoolOptionalField {
-	return &BoolOptionalField{
-		read:          read,
-		write:         write,
-		OptionalField: parquet.NewOptionalField(path, types, opts...),
-		stats:         newBoolOptionalStats(maxDef(types)),
+func (f *int32optionalStats) add(vals []int32, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < f.maxDef {
+			f.nils++
+		} else {
+			val := vals[i]
+			i++
+
+			if f.nonNils == 0 || val < f.min {
+				f.min = val
+			}
+			if f.nonNils == 0 || val > f.max {
+				f.max = val
+			}
+			f.nonNils++
+		}
 	}
 }
 
-func (f *BoolOptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: f.RepetitionType, Types: f.Types}
+func (f *int32optionalStats) bytes(v int32) []byte {
+	bs := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bs, uint32(v))
+	return bs
 }
 
-func (f *BoolOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, sizes, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+func (f *int32optionalStats) NullCount() *int64 {
+	return &f.nils
+}
+
+func (f *int32optionalStats) DistinctCount() *int64 {
+	return nil
+}
+
+func (f *int32optionalStats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
+	return f.bytes(f.min)
+}
 
-	v, err := parquet.GetBools(rr, f.Values()-len(f.vals), sizes)
-	f.vals = append(f.vals, v...)
-	return err
+func (f *int32optionalStats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
+	return f.bytes(f.max)
 }
 
-func (f *BoolOptionalField) Scan(r *Person) {
-	if len(f.Defs) == 0 {
-		return
+type int64stats struct {
+	min     int64
+	max     int64
+	nonNils int64
+}
+
+func newInt64stats() *int64stats {
+	return &int64stats{}
+}
+
+func (i *int64stats) add(val int64) {
+	if i.nonNils == 0 || val < i.min {
+		i.min = val
 	}
+	if i.nonNils == 0 || val > i.max {
+		i.max = val
+	}
+	i.nonNils++
+}
 
-	v, l := f.write(r, f.vals, f.Defs, f.Reps)
-	f.vals = f.vals[v:]
-	f.Defs = f.Defs[l:]
-	if len(f.Reps) > 0 {
-		f.Reps = f.Reps[l:]
+func (f *int64stats) bytes(v int64) []byte {
+	bs := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bs, uint64(v))
+	return bs
+}
+
+func (f *int64stats) NullCount() *int64 {
+	return new(int64)
+}
+
+func (f *int64stats) DistinctCount() *int64 {
+	return nil
+}
+
+func (f *int64stats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
+	return f.bytes(f.min)
 }
 
-func (f *BoolOptionalField) Add(r Person) {
-	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
-	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
-	f.vals = vals
-	f.Defs = defs
-	f.Reps = reps
+func (f *int64stats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
+	return f.bytes(f.max)
 }
 
-func (f *BoolOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
-	ln := len(f.vals)
-	byteNum := (ln + 7) / 8
-	rawBuf := make([]byte, byteNum)
+type int64optionalStats struct {
+	min     int64
+	max     int64
+	nils    int64
+	nonNils int64
+	maxDef  uint8
+}
+
+func newint64optionalStats(d uint8) *int64optionalStats {
+	return &int64optionalStats{
+		maxDef: d,
+	}
+}
+
+func (f *int64optionalStats) add(vals []int64, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < f.maxDef {
+			f.nils++
+		} else {
+			val := vals[i]
+			i++
 
-	for i := 0; i < ln; i++ {
-		if f.vals[i] {
-			rawBuf[i/8] = rawBuf[i/8] | (1 << uint32(i%8))
+			if f.nonNils == 0 || val < f.min {
+				f.min = val
+			}
+			if f.nonNils == 0 || val > f.max {
+				f.max = val
+			}
+			f.nonNils++
 		}
 	}
+}
 
-	return f.DoWrite(w, meta, rawBuf, len(f.Defs), f.stats)
+func (f *int64optionalStats) bytes(v int64) []byte {
+	bs := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bs, uint64(v))
+	return bs
 }
 
-func (f *BoolOptionalField) Levels() ([]uint8, []uint8) {
-	return f.Defs, f.Reps
+func (f *int64optionalStats) NullCount() *int64 {
+	return &f.nils
 }
 
-type Uint32Field struct {
-	vals []uint32
-	parquet.RequiredField
-	read  func(r Person) uint32
-	write func(r *Person, vals []uint32)
-	stats *uint32stats
+func (f *int64optionalStats) DistinctCount() *int64 {
+	return nil
 }
 
-func NewUint32Field(read func(r Person) uint32, write func(r *Person, vals []uint32), path []string, opts ...func(*parquet.RequiredField)) *Uint32Field {
-	return &Uint32Field{
-		read:          read,
-		write:         write,
-		RequiredField: parquet.NewRequiredField(path, opts...),
-		stats:         newUint32stats(),
+func (f *int64optionalStats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
+	return f.bytes(f.min)
 }
 
-func (f *Uint32Field) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Uint32Type, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+func (f *int64optionalStats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
+	return f.bytes(f.max)
 }
 
-func (f *Uint32Field) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+const nilOptString = "__#NIL#__"
+
+type stringOptionalStats struct {
+	min    string
+	max    string
+	nils   int64
+	maxDef uint8
+}
+
+func newStringOptionalStats(d uint8) *stringOptionalStats {
+	return &stringOptionalStats{
+		min:    nilOptString,
+		max:    nilOptString,
+		maxDef: d,
+	}
+}
+
+func (s *stringOptionalStats) add(vals []string, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+		} else {
+			val := vals[i]
+			if s.min == nilOptString {
+				s.min = val
+			} else {
+				if val < s.min {
+					s.min = val
+				}
+			}
+			if s.max == nilOptString {
+				s.max = val
+			} else {
+				if val > s.max {
+					s.max = val
+				}
+			}
+			i++
+		}
 	}
+}
 
-	v := make([]uint32, int(pg.N))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+func (s *stringOptionalStats) NullCount() *int64 {
+	return &s.nils
 }
 
-func (f *Uint32Field) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+func (s *stringOptionalStats) DistinctCount() *int64 {
+	return nil
+}
 
-	bs := make([]byte, 4)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint32(bs, v)
-		if _, err := buf.Write(bs); err != nil {
-			return err
-		}
+func (s *stringOptionalStats) Min() []byte {
+	if s.min == nilOptString {
+		return nil
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+	return []byte(s.min)
 }
 
-func (f *Uint32Field) Scan(r *Person) {
-	if len(f.vals) == 0 {
-		return
+func (s *stringOptionalStats) Max() []byte {
+	if s.max == nilOptString {
+		return nil
 	}
-
-	f.write(r, f.vals)
-	f.vals = f.vals[1:]
+	return []byte(s.max)
 }
 
-func (f *Uint32Field) Add(r Person) {
-	v := f.read(r)
-	f.stats.add(v)
-	f.vals = append(f.vals, v)
+type float32stats struct {
+	min     float32
+	max     float32
+	nonNils int64
 }
 
-func (f *Uint32Field) Levels() ([]uint8, []uint8) {
-	return nil, nil
+func newFloat32stats() *float32stats {
+	return &float32stats{}
 }
 
-type Uint64OptionalField struct {
-	parquet.OptionalField
-	vals  []uint64
-	read  func(r Person, vals []uint64, defs, reps []uint8) ([]uint64, []uint8, []uint8)
-	write func(r *Person, vals []uint64, defs, reps []uint8) (int, int)
-	stats *uint64optionalStats
+func (i *float32stats) add(val float32) {
+	if math.IsNaN(float64(val)) {
+		return
+	}
+	if i.nonNils == 0 || val < i.min {
+		i.min = val
+	}
+	if i.nonNils == 0 || val > i.max {
+		i.max = val
+	}
+	i.nonNils++
 }
 
-func NewUint64OptionalField(read func(r Person, vals []uint64, defs, reps []uint8) ([]uint64, []uint8, []uint8), write func(r *Person, vals []uint64, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *Uint64OptionalField {
-	return &Uint64OptionalField{
-		read:          read,
-		write:         write,
-		OptionalField: parquet.NewOptionalField(path, types, opts...),
-		stats:         newuint64optionalStats(maxDef(types)),
-	}
+func (f *float32stats) bytes(v float32) []byte {
+	bs := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
+	return bs
 }
 
-func (f *Uint64OptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Uint64Type, RepetitionType: f.RepetitionType, Types: f.Types}
+func (f *float32stats) NullCount() *int64 {
+	return new(int64)
 }
 
-func (f *Uint64OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
-	buf := buffpool.Get()
-	defer buffpool.Put(buf)
+func (f *float32stats) DistinctCount() *int64 {
+	return nil
+}
 
-	bs := make([]byte, 8)
-	for _, v := range f.vals {
-		binary.LittleEndian.PutUint64(bs, v)
-		if _, err := buf.Write(bs); err != nil {
-			return err
-		}
+func (f *float32stats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
-	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+	return f.bytes(f.min)
 }
 
-func (f *Uint64OptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, _, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+func (f *float32stats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
+	return f.bytes(f.max)
+}
 
-	v := make([]uint64, f.Values()-len(f.vals))
-	err = binary.Read(rr, binary.LittleEndian, &v)
-	f.vals = append(f.vals, v...)
-	return err
+type float64stats struct {
+	min     float64
+	max     float64
+	nonNils int64
 }
 
-func (f *Uint64OptionalField) Add(r Person) {
-	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
-	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
-	f.vals = vals
-	f.Defs = defs
-	f.Reps = reps
+func newFloat64stats() *float64stats {
+	return &float64stats{}
 }
 
-func (f *Uint64OptionalField) Scan(r *Person) {
-	if len(f.Defs) == 0 {
+func (i *float64stats) add(val float64) {
+	if math.IsNaN(val) {
 		return
 	}
-
-	v, l := f.write(r, f.vals, f.Defs, f.Reps)
-	f.vals = f.vals[v:]
-	f.Defs = f.Defs[l:]
-	if len(f.Reps) > 0 {
-		f.Reps = f.Reps[l:]
+	if i.nonNils == 0 || val < i.min {
+		i.min = val
+	}
+	if i.nonNils == 0 || val > i.max {
+		i.max = val
 	}
+	i.nonNils++
 }
 
-func (f *Uint64OptionalField) Levels() ([]uint8, []uint8) {
-	return f.Defs, f.Reps
+func (f *float64stats) bytes(v float64) []byte {
+	bs := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bs, math.Float64bits(v))
+	return bs
 }
 
-type BoolField struct {
-	parquet.RequiredField
-	vals  []bool
-	read  func(r Person) bool
-	write func(r *Person, vals []bool)
-	stats *boolStats
+func (f *float64stats) NullCount() *int64 {
+	return new(int64)
 }
 
-func NewBoolField(read func(r Person) bool, write func(r *Person, vals []bool), path []string, opts ...func(*parquet.RequiredField)) *BoolField {
-	return &BoolField{
-		read:          read,
-		write:         write,
-		RequiredField: parquet.NewRequiredField(path, opts...),
-	}
+func (f *float64stats) DistinctCount() *int64 {
+	return nil
 }
 
-func (f *BoolField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+func (f *float64stats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
+	return f.bytes(f.min)
 }
 
-func (f *BoolField) Write(w io.Writer, meta *parquet.Metadata) error {
-	ln := len(f.vals)
-	n := (ln + 7) / 8
-	rawBuf := make([]byte, n)
-
-	for i := 0; i < ln; i++ {
-		if f.vals[i] {
-			rawBuf[i/8] = rawBuf[i/8] | (1 << uint32(i%8))
-		}
+func (f *float64stats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
+	return f.bytes(f.max)
+}
 
-	return f.DoWrite(w, meta, rawBuf, len(f.vals), newBoolStats())
+type float32optionalStats struct {
+	min     float32
+	max     float32
+	nils    int64
+	nonNils int64
+	maxDef  uint8
 }
 
-func (f *BoolField) Read(r io.ReadSeeker, pg parquet.Page) error {
-	rr, sizes, err := f.DoRead(r, pg)
-	if err != nil {
-		return err
+func newfloat32optionalStats(d uint8) *float32optionalStats {
+	return &float32optionalStats{
+		maxDef: d,
 	}
-
-	f.vals, err = parquet.GetBools(rr, int(pg.N), sizes)
-	return err
 }
 
-func (f *BoolField) Scan(r *Person) {
-	if len(f.vals) == 0 {
-		return
-	}
+func (f *float32optionalStats) add(vals []float32, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < f.maxDef {
+			f.nils++
+		} else {
+			val := vals[i]
+			i++
+			if math.IsNaN(float64(val)) {
+				continue
+			}
 
-	f.write(r, f.vals)
-	f.vals = f.vals[1:]
+			if f.nonNils == 0 || val < f.min {
+				f.min = val
+			}
+			if f.nonNils == 0 || val > f.max {
+				f.max = val
+			}
+			f.nonNils++
+		}
+	}
 }
 
-func (f *BoolField) Add(r Person) {
-	v := f.read(r)
-	f.vals = append(f.vals, v)
+func (f *float32optionalStats) bytes(v float32) []byte {
+	bs := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
+	return bs
 }
 
-func (f *BoolField) Levels() ([]uint8, []uint8) {
-	return nil, nil
+func (f *float32optionalStats) NullCount() *int64 {
+	return &f.nils
 }
 
-type int32stats struct {
-	min int32
-	max int32
+func (f *float32optionalStats) DistinctCount() *int64 {
+	return nil
 }
 
-func newInt32stats() *int32stats {
-	return &int32stats{
-		min: int32(math.MaxInt32),
+func (f *float32optionalStats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
+	return f.bytes(f.min)
 }
 
-func (i *int32stats) add(val int32) {
-	if val < i.min {
-		i.min = val
-	}
-	if val > i.max {
-		i.max = val
+func (f *float32optionalStats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
 	}
+	return f.bytes(f.max)
 }
 
-func (f *int32stats) bytes(v int32) []byte {
-	bs := make([]byte, 4)
-	binary.LittleEndian.PutUint32(bs, uint32(v))
-	return bs
+type boolOptionalStats struct {
+	maxDef uint8
+	nils   int64
 }
 
-func (f *int32stats) NullCount() *int64 {
-	return nil
+func newBoolOptionalStats(d uint8) *boolOptionalStats {
+	return &boolOptionalStats{maxDef: d}
 }
 
-func (f *int32stats) DistinctCount() *int64 {
+func (b *boolOptionalStats) add(vals []bool, defs []uint8) {
+	for _, def := range defs {
+		if def < b.maxDef {
+			b.nils++
+		}
+	}
+}
+
+func (b *boolOptionalStats) NullCount() *int64 {
+	return &b.nils
+}
+
+func (b *boolOptionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *int32stats) Min() []byte {
-	return f.bytes(f.min)
+func (b *boolOptionalStats) Min() []byte {
+	return nil
 }
 
-func (f *int32stats) Max() []byte {
-	return f.bytes(f.max)
+func (b *boolOptionalStats) Max() []byte {
+	return nil
 }
 
-const nilString = "__#NIL#__"
-
-type stringStats struct {
-	min string
-	max string
+type uint32stats struct {
+	min     uint32
+	max     uint32
+	nonNils int64
 }
 
-func newStringStats() *stringStats {
-	return &stringStats{
-		min: nilString,
-		max: nilString,
-	}
+func newUint32stats() *uint32stats {
+	return &uint32stats{}
 }
 
-func (s *stringStats) add(val string) {
-	if s.min == nilString {
-		s.min = val
-	} else {
-		if val < s.min {
-			s.min = val
-		}
+func (i *uint32stats) add(val uint32) {
+	if i.nonNils == 0 || val < i.min {
+		i.min = val
 	}
-	if s.max == nilString {
-		s.max = val
-	} else {
-		if val > s.max {
-			s.max = val
-		}
+	if i.nonNils == 0 || val > i.max {
+		i.max = val
 	}
+	i.nonNils++
 }
 
-func (s *stringStats) NullCount() *int64 {
-	return nil
+func (f *uint32stats) bytes(v uint32) []byte {
+	bs := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bs, v)
+	return bs
 }
 
-func (s *stringStats) DistinctCount() *int64 {
+func (f *uint32stats) NullCount() *int64 {
+	return new(int64)
+}
+
+func (f *uint32stats) DistinctCount() *int64 {
 	return nil
 }
 
-func (s *stringStats) Min() []byte {
-	if s.min == nilString {
+func (f *uint32stats) Min() []byte {
+	if f.nonNils == 0 {
 		return nil
 	}
-	return []byte(s.min)
+	return f.bytes(f.min)
 }
 
-func (s *stringStats) Max() []byte {
-	if s.max == nilString {
+func (f *uint32stats) Max() []byte {
+	if f.nonNils == 0 {
 		return nil
 	}
-	return []byte(s.max)
+	return f.bytes(f.max)
 }
 
-type int32optionalStats struct {
-	min     int32
-	max     int32
+type uint64optionalStats struct {
+	min     uint64
+	max     uint64
 	nils    int64
 	nonNils int64
 	maxDef  uint8
 }
 
-func newint32optionalStats(d uint8) *int32optionalStats {
-	return &int32optionalStats{
-		min:    int32(math.MaxInt32),
+func newuint64optionalStats(d uint8) *uint64optionalStats {
+	return &uint64optionalStats{
 		maxDef: d,
 	}
 }
 
-func (f *int32optionalStats) add(vals []int32, defs []uint8) {
+func (f *uint64optionalStats) add(vals []uint64, defs []uint8) {
 	var i int
 	for _, def := range defs {
 		if def < f.maxDef {
@@ -1916,103 +5494,116 @@ func (f *int32optionalStats) add(vals []int32, defs []uint8) {
 			val := vals[i]
 			i++
 
-			f.nonNils++
-			if val < f.min {
+			if f.nonNils == 0 || val < f.min {
 				f.min = val
 			}
-			if val > f.max {
+			if f.nonNils == 0 || val > f.max {
 				f.max = val
 			}
+			f.nonNils++
 		}
 	}
 }
 
-func (f *int32optionalStats) bytes(v int32) []byte {
-	bs := make([]byte, 4)
-	binary.LittleEndian.PutUint32(bs, uint32(v))
+func (f *uint64optionalStats) bytes(v uint64) []byte {
+	bs := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bs, v)
 	return bs
 }
 
-func (f *int32optionalStats) NullCount() *int64 {
+func (f *uint64optionalStats) NullCount() *int64 {
 	return &f.nils
 }
 
-func (f *int32optionalStats) DistinctCount() *int64 {
+func (f *uint64optionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *int32optionalStats) Min() []byte {
+func (f *uint64optionalStats) Min() []byte {
 	if f.nonNils == 0 {
 		return nil
 	}
 	return f.bytes(f.min)
 }
 
-func (f *int32optionalStats) Max() []byte {
+func (f *uint64optionalStats) Max() []byte {
 	if f.nonNils == 0 {
 		return nil
 	}
 	return f.bytes(f.max)
 }
 
-type int64stats struct {
-	min int64
-	max int64
+type boolStats struct{}
+
+func newBoolStats() *boolStats             { return &boolStats{} }
+func (b *boolStats) NullCount() *int64     { return new(int64) }
+func (b *boolStats) DistinctCount() *int64 { return nil }
+func (b *boolStats) Min() []byte           { return nil }
+func (b *boolStats) Max() []byte           { return nil }
+
+type int8stats struct {
+	min     int8
+	max     int8
+	nonNils int64
 }
 
-func newInt64stats() *int64stats {
-	return &int64stats{
-		min: int64(math.MaxInt64),
-	}
+func newInt8stats() *int8stats {
+	return &int8stats{}
 }
 
-func (i *int64stats) add(val int64) {
-	if val < i.min {
+func (i *int8stats) add(val int8) {
+	if i.nonNils == 0 || val < i.min {
 		i.min = val
 	}
-	if val > i.max {
+	if i.nonNils == 0 || val > i.max {
 		i.max = val
 	}
+	i.nonNils++
 }
 
-func (f *int64stats) bytes(v int64) []byte {
-	bs := make([]byte, 8)
-	binary.LittleEndian.PutUint64(bs, uint64(v))
+func (f *int8stats) bytes(v int8) []byte {
+	bs := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bs, uint32(v))
 	return bs
 }
 
-func (f *int64stats) NullCount() *int64 {
-	return nil
+func (f *int8stats) NullCount() *int64 {
+	return new(int64)
 }
 
-func (f *int64stats) DistinctCount() *int64 {
+func (f *int8stats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *int64stats) Min() []byte {
+func (f *int8stats) Min() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
 	return f.bytes(f.min)
 }
 
-func (f *int64stats) Max() []byte {
+func (f *int8stats) Max() []byte {
+	if f.nonNils == 0 {
+		return nil
+	}
 	return f.bytes(f.max)
 }
 
-type int64optionalStats struct {
-	min     int64
-	max     int64
+type int16optionalStats struct {
+	min     int16
+	max     int16
 	nils    int64
 	nonNils int64
 	maxDef  uint8
 }
 
-func newint64optionalStats(d uint8) *int64optionalStats {
-	return &int64optionalStats{
-		min:    int64(math.MaxInt64),
+func newint16optionalStats(d uint8) *int16optionalStats {
+	return &int16optionalStats{
 		maxDef: d,
 	}
 }
 
-func (f *int64optionalStats) add(vals []int64, defs []uint8) {
+func (f *int16optionalStats) add(vals []int16, defs []uint8) {
 	var i int
 	for _, def := range defs {
 		if def < f.maxDef {
@@ -2021,348 +5612,422 @@ func (f *int64optionalStats) add(vals []int64, defs []uint8) {
 			val := vals[i]
 			i++
 
-			f.nonNils++
-			if val < f.min {
+			if f.nonNils == 0 || val < f.min {
 				f.min = val
 			}
-			if val > f.max {
+			if f.nonNils == 0 || val > f.max {
 				f.max = val
 			}
+			f.nonNils++
 		}
 	}
 }
 
-func (f *int64optionalStats) bytes(v int64) []byte {
-	bs := make([]byte, 8)
-	binary.LittleEndian.PutUint64(bs, uint64(v))
+func (f *int16optionalStats) bytes(v int16) []byte {
+	bs := make([]byte, 4)
+	binary.LittleEndian.PutUint32(bs, uint32(v))
 	return bs
 }
 
-func (f *int64optionalStats) NullCount() *int64 {
+func (f *int16optionalStats) NullCount() *int64 {
 	return &f.nils
 }
 
-func (f *int64optionalStats) DistinctCount() *int64 {
+func (f *int16optionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *int64optionalStats) Min() []byte {
+func (f *int16optionalStats) Min() []byte {
 	if f.nonNils == 0 {
 		return nil
 	}
 	return f.bytes(f.min)
 }
 
-func (f *int64optionalStats) Max() []byte {
+func (f *int16optionalStats) Max() []byte {
 	if f.nonNils == 0 {
 		return nil
 	}
 	return f.bytes(f.max)
 }
 
-const nilOptString = "__#NIL#__"
+type timestampStats struct {
+	min     int64
+	max     int64
+	nonNils int64
+}
 
-type stringOptionalStats struct {
-	min    string
-	max    string
-	nils   int64
-	maxDef uint8
+func newTimestampStats() *timestampStats {
+	return &timestampStats{}
 }
 
-func newStringOptionalStats(d uint8) *stringOptionalStats {
-	return &stringOptionalStats{
-		min:    nilOptString,
-		max:    nilOptString,
-		maxDef: d,
+func (s *timestampStats) add(val int64) {
+	if s.nonNils == 0 || val < s.min {
+		s.min = val
+	}
+	if s.nonNils == 0 || s.max < val {
+		s.max = val
 	}
+	s.nonNils++
 }
 
-func (s *stringOptionalStats) add(vals []string, defs []uint8) {
+func (s *timestampStats) bytes(v int64) []byte {
+	bs := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bs, uint64(v))
+	return bs
+}
+
+func (s *timestampStats) NullCount() *int64 {
+	return new(int64)
+}
+
+func (s *timestampStats) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *timestampStats) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.min)
+}
+
+func (s *timestampStats) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.max)
+}
+
+type timestampOptionalStats struct {
+	min     int64
+	max     int64
+	nils    int64
+	nonNils int64
+	maxDef  uint8
+}
+
+func newTimestampOptionalStats(d uint8) *timestampOptionalStats {
+	return &timestampOptionalStats{maxDef: d}
+}
+
+func (s *timestampOptionalStats) add(vals []time.Time, defs []uint8) {
 	var i int
 	for _, def := range defs {
 		if def < s.maxDef {
 			s.nils++
-		} else {
-			val := vals[i]
-			if s.min == nilOptString {
-				s.min = val
-			} else {
-				if val < s.min {
-					s.min = val
-				}
-			}
-			if s.max == nilOptString {
-				s.max = val
-			} else {
-				if val > s.max {
-					s.max = val
-				}
-			}
-			i++
+			continue
+		}
+
+		v := vals[i]
+		i++
+		val := v.Unix()*1000 + int64(v.Nanosecond())/int64(time.Millisecond)
+		if s.nonNils == 0 || val < s.min {
+			s.min = val
 		}
+		if s.nonNils == 0 || s.max < val {
+			s.max = val
+		}
+		s.nonNils++
 	}
 }
 
-func (s *stringOptionalStats) NullCount() *int64 {
+func (s *timestampOptionalStats) bytes(v int64) []byte {
+	bs := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bs, uint64(v))
+	return bs
+}
+
+func (s *timestampOptionalStats) NullCount() *int64 {
 	return &s.nils
 }
 
-func (s *stringOptionalStats) DistinctCount() *int64 {
+func (s *timestampOptionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (s *stringOptionalStats) Min() []byte {
-	if s.min == nilOptString {
+func (s *timestampOptionalStats) Min() []byte {
+	if s.nonNils == 0 {
 		return nil
 	}
-	return []byte(s.min)
+	return s.bytes(s.min)
 }
 
-func (s *stringOptionalStats) Max() []byte {
-	if s.max == nilOptString {
+func (s *timestampOptionalStats) Max() []byte {
+	if s.nonNils == 0 {
 		return nil
 	}
-	return []byte(s.max)
+	return s.bytes(s.max)
 }
 
-type float32stats struct {
-	min float32
-	max float32
+type dateOptionalStats struct {
+	min     int32
+	max     int32
+	nils    int64
+	nonNils int64
+	maxDef  uint8
 }
 
-func newFloat32stats() *float32stats {
-	return &float32stats{
-		min: float32(math.MaxFloat32),
-	}
+func newDateOptionalStats(d uint8) *dateOptionalStats {
+	return &dateOptionalStats{maxDef: d}
 }
 
-func (i *float32stats) add(val float32) {
-	if val < i.min {
-		i.min = val
-	}
-	if val > i.max {
-		i.max = val
+func (s *dateOptionalStats) add(vals []time.Time, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
+
+		v := vals[i]
+		i++
+		val := int32(v.Truncate(24*time.Hour).Unix() / 86400)
+		if s.nonNils == 0 || val < s.min {
+			s.min = val
+		}
+		if s.nonNils == 0 || s.max < val {
+			s.max = val
+		}
+		s.nonNils++
 	}
 }
 
-func (f *float32stats) bytes(v float32) []byte {
+func (s *dateOptionalStats) bytes(v int32) []byte {
 	bs := make([]byte, 4)
-	binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
+	binary.LittleEndian.PutUint32(bs, uint32(v))
 	return bs
 }
 
-func (f *float32stats) NullCount() *int64 {
-	return nil
+func (s *dateOptionalStats) NullCount() *int64 {
+	return &s.nils
 }
 
-func (f *float32stats) DistinctCount() *int64 {
+func (s *dateOptionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *float32stats) Min() []byte {
-	return f.bytes(f.min)
+func (s *dateOptionalStats) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.min)
 }
 
-func (f *float32stats) Max() []byte {
-	return f.bytes(f.max)
+func (s *dateOptionalStats) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.max)
 }
 
-type float64stats struct {
-	min float64
-	max float64
+type bytesStats struct {
+	min     []byte
+	max     []byte
+	nonNils int64
 }
 
-func newFloat64stats() *float64stats {
-	return &float64stats{
-		min: float64(math.MaxFloat64),
-	}
+func newBytesStats() *bytesStats {
+	return &bytesStats{}
 }
 
-func (i *float64stats) add(val float64) {
-	if val < i.min {
-		i.min = val
+func (s *bytesStats) add(val []byte) {
+	if s.nonNils == 0 || string(val) < string(s.min) {
+		s.min = val
 	}
-	if val > i.max {
-		i.max = val
+	if s.nonNils == 0 || string(val) > string(s.max) {
+		s.max = val
 	}
+	s.nonNils++
 }
 
-func (f *float64stats) bytes(v float64) []byte {
-	bs := make([]byte, 8)
-	binary.LittleEndian.PutUint64(bs, math.Float64bits(v))
-	return bs
-}
-
-func (f *float64stats) NullCount() *int64 {
-	return nil
+func (s *bytesStats) NullCount() *int64 {
+	return new(int64)
 }
 
-func (f *float64stats) DistinctCount() *int64 {
+func (s *bytesStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *float64stats) Min() []byte {
-	return f.bytes(f.min)
+func (s *bytesStats) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.min
 }
 
-func (f *float64stats) Max() []byte {
-	return f.bytes(f.max)
+func (s *bytesStats) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.max
 }
 
-type float32optionalStats struct {
-	min     float32
-	max     float32
+type bytesOptionalStats struct {
+	min     []byte
+	max     []byte
 	nils    int64
 	nonNils int64
 	maxDef  uint8
 }
 
-func newfloat32optionalStats(d uint8) *float32optionalStats {
-	return &float32optionalStats{
-		min:    float32(math.MaxFloat32),
-		maxDef: d,
-	}
+func newBytesOptionalStats(d uint8) *bytesOptionalStats {
+	return &bytesOptionalStats{maxDef: d}
 }
 
-func (f *float32optionalStats) add(vals []float32, defs []uint8) {
+func (s *bytesOptionalStats) add(vals [][]byte, defs []uint8) {
 	var i int
 	for _, def := range defs {
-		if def < f.maxDef {
-			f.nils++
-		} else {
-			val := vals[i]
-			i++
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
 
-			f.nonNils++
-			if val < f.min {
-				f.min = val
-			}
-			if val > f.max {
-				f.max = val
-			}
+		val := vals[i]
+		i++
+		if s.nonNils == 0 || string(val) < string(s.min) {
+			s.min = val
 		}
+		if s.nonNils == 0 || string(val) > string(s.max) {
+			s.max = val
+		}
+		s.nonNils++
 	}
 }
 
-func (f *float32optionalStats) bytes(v float32) []byte {
-	bs := make([]byte, 4)
-	binary.LittleEndian.PutUint32(bs, math.Float32bits(v))
-	return bs
-}
-
-func (f *float32optionalStats) NullCount() *int64 {
-	return &f.nils
+func (s *bytesOptionalStats) NullCount() *int64 {
+	return &s.nils
 }
 
-func (f *float32optionalStats) DistinctCount() *int64 {
+func (s *bytesOptionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *float32optionalStats) Min() []byte {
-	if f.nonNils == 0 {
+func (s *bytesOptionalStats) Min() []byte {
+	if s.nonNils == 0 {
 		return nil
 	}
-	return f.bytes(f.min)
+	return s.min
 }
 
-func (f *float32optionalStats) Max() []byte {
-	if f.nonNils == 0 {
+func (s *bytesOptionalStats) Max() []byte {
+	if s.nonNils == 0 {
 		return nil
 	}
-	return f.bytes(f.max)
+	return s.max
 }
 
-type boolOptionalStats struct {
-	maxDef uint8
-	nils   int64
+type uuidStats struct {
+	min     [16]byte
+	max     [16]byte
+	nonNils int64
 }
 
-func newBoolOptionalStats(d uint8) *boolOptionalStats {
-	return &boolOptionalStats{maxDef: d}
+func newUuidStats() *uuidStats {
+	return &uuidStats{}
 }
 
-func (b *boolOptionalStats) add(vals []bool, defs []uint8) {
-	for _, def := range defs {
-		if def < b.maxDef {
-			b.nils++
-		}
+func (s *uuidStats) add(val [16]byte) {
+	if s.nonNils == 0 || string(val[:]) < string(s.min[:]) {
+		s.min = val
+	}
+	if s.nonNils == 0 || string(s.max[:]) < string(val[:]) {
+		s.max = val
 	}
+	s.nonNils++
 }
 
-func (b *boolOptionalStats) NullCount() *int64 {
-	return &b.nils
+func (s *uuidStats) NullCount() *int64 {
+	return new(int64)
 }
 
-func (b *boolOptionalStats) DistinctCount() *int64 {
+func (s *uuidStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (b *boolOptionalStats) Min() []byte {
-	return nil
+func (s *uuidStats) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.min[:]
 }
 
-func (b *boolOptionalStats) Max() []byte {
-	return nil
+func (s *uuidStats) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.max[:]
 }
 
-type uint32stats struct {
-	min uint32
-	max uint32
+type fixedLenByteArray8OptionalStats struct {
+	min     [8]byte
+	max     [8]byte
+	nils    int64
+	nonNils int64
+	maxDef  uint8
 }
 
-func newUint32stats() *uint32stats {
-	return &uint32stats{
-		min: uint32(math.MaxUint32),
-	}
+func newFixedLenByteArray8OptionalStats(d uint8) *fixedLenByteArray8OptionalStats {
+	return &fixedLenByteArray8OptionalStats{maxDef: d}
 }
 
-func (i *uint32stats) add(val uint32) {
-	if val < i.min {
-		i.min = val
-	}
-	if val > i.max {
-		i.max = val
-	}
-}
+func (s *fixedLenByteArray8OptionalStats) add(vals [][8]byte, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
 
-func (f *uint32stats) bytes(v uint32) []byte {
-	bs := make([]byte, 4)
-	binary.LittleEndian.PutUint32(bs, v)
-	return bs
+		val := vals[i]
+		i++
+		if s.nonNils == 0 || string(val[:]) < string(s.min[:]) {
+			s.min = val
+		}
+		if s.nonNils == 0 || string(s.max[:]) < string(val[:]) {
+			s.max = val
+		}
+		s.nonNils++
+	}
 }
 
-func (f *uint32stats) NullCount() *int64 {
-	return nil
+func (s *fixedLenByteArray8OptionalStats) NullCount() *int64 {
+	return &s.nils
 }
 
-func (f *uint32stats) DistinctCount() *int64 {
+func (s *fixedLenByteArray8OptionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *uint32stats) Min() []byte {
-	return f.bytes(f.min)
+func (s *fixedLenByteArray8OptionalStats) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.min[:]
 }
 
-func (f *uint32stats) Max() []byte {
-	return f.bytes(f.max)
+func (s *fixedLenByteArray8OptionalStats) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.max[:]
 }
 
-type uint64optionalStats struct {
-	min     uint64
-	max     uint64
+type float64optionalStats struct {
+	min     float64
+	max     float64
 	nils    int64
 	nonNils int64
 	maxDef  uint8
 }
 
-func newuint64optionalStats(d uint8) *uint64optionalStats {
-	return &uint64optionalStats{
-		min:    uint64(math.MaxUint64),
+func newfloat64optionalStats(d uint8) *float64optionalStats {
+	return &float64optionalStats{
 		maxDef: d,
 	}
 }
 
-func (f *uint64optionalStats) add(vals []uint64, defs []uint8) {
+func (f *float64optionalStats) add(vals []float64, defs []uint8) {
 	var i int
 	for _, def := range defs {
 		if def < f.maxDef {
@@ -2370,62 +6035,63 @@ func (f *uint64optionalStats) add(vals []uint64, defs []uint8) {
 		} else {
 			val := vals[i]
 			i++
+			if math.IsNaN(val) {
+				continue
+			}
 
-			f.nonNils++
-			if val < f.min {
+			if f.nonNils == 0 || val < f.min {
 				f.min = val
 			}
-			if val > f.max {
+			if f.nonNils == 0 || val > f.max {
 				f.max = val
 			}
+			f.nonNils++
 		}
 	}
 }
 
-func (f *uint64optionalStats) bytes(v uint64) []byte {
+func (f *float64optionalStats) bytes(v float64) []byte {
 	bs := make([]byte, 8)
-	binary.LittleEndian.PutUint64(bs, v)
+	binary.LittleEndian.PutUint64(bs, math.Float64bits(v))
 	return bs
 }
 
-func (f *uint64optionalStats) NullCount() *int64 {
+func (f *float64optionalStats) NullCount() *int64 {
 	return &f.nils
 }
 
-func (f *uint64optionalStats) DistinctCount() *int64 {
+func (f *float64optionalStats) DistinctCount() *int64 {
 	return nil
 }
 
-func (f *uint64optionalStats) Min() []byte {
+func (f *float64optionalStats) Min() []byte {
 	if f.nonNils == 0 {
 		return nil
 	}
 	return f.bytes(f.min)
 }
 
-func (f *uint64optionalStats) Max() []byte {
+func (f *float64optionalStats) Max() []byte {
 	if f.nonNils == 0 {
 		return nil
 	}
 	return f.bytes(f.max)
 }
 
-type boolStats struct{}
-
-func newBoolStats() *boolStats             { return &boolStats{} }
-func (b *boolStats) NullCount() *int64     { return nil }
-func (b *boolStats) DistinctCount() *int64 { return nil }
-func (b *boolStats) Min() []byte           { return nil }
-func (b *boolStats) Max() []byte           { return nil }
-
-func pint32(i int32) *int32       { return &i }
-func puint32(i uint32) *uint32    { return &i }
-func pint64(i int64) *int64       { return &i }
-func puint64(i uint64) *uint64    { return &i }
-func pbool(b bool) *bool          { return &b }
-func pstring(s string) *string    { return &s }
-func pfloat32(f float32) *float32 { return &f }
-func pfloat64(f float64) *float64 { return &f }
+func pint8(i int8) *int8                       { return &i }
+func pint16(i int16) *int16                    { return &i }
+func puint8(i uint8) *uint8                    { return &i }
+func puint16(i uint16) *uint16                 { return &i }
+func pint32(i int32) *int32                    { return &i }
+func puint32(i uint32) *uint32                 { return &i }
+func pint64(i int64) *int64                    { return &i }
+func puint64(i uint64) *uint64                 { return &i }
+func pbool(b bool) *bool                       { return &b }
+func pstring(s string) *string                 { return &s }
+func pfloat32(f float32) *float32              { return &f }
+func pfloat64(f float64) *float64              { return &f }
+func ptime(t time.Time) *time.Time             { return &t }
+func pduration(d time.Duration) *time.Duration { return &d }
 
 // keeps track of the indices of repeated fields
 // that have already been handled by a previous field
@@ -2451,6 +6117,34 @@ func maxDef(types []int) uint8 {
 	return out
 }
 
+func Int8Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_INT_8
+	se.ConvertedType = &ct
+}
+
+func Int16Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_INT_16
+	se.ConvertedType = &ct
+}
+
+func Uint8Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_UINT_8
+	se.ConvertedType = &ct
+}
+
+func Uint16Type(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_UINT_16
+	se.ConvertedType = &ct
+}
+
 func Int32Type(se *sch.SchemaElement) {
 	t := sch.Type_INT32
 	se.Type = &t
@@ -2485,6 +6179,96 @@ func Float64Type(se *sch.SchemaElement) {
 	se.Type = &t
 }
 
+func TimestampType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	ct := sch.ConvertedType_TIMESTAMP_MILLIS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIMESTAMP: &sch.TimestampType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MILLIS: sch.NewMilliSeconds()},
+		},
+	}
+}
+
+func TimestampMicrosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	ct := sch.ConvertedType_TIMESTAMP_MICROS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIMESTAMP: &sch.TimestampType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MICROS: sch.NewMicroSeconds()},
+		},
+	}
+}
+
+// TimestampNanosType doesn't have a converted type (there
+// isn't one for nanoseconds), only a logical type.
+func TimestampNanosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	se.LogicalType = &sch.LogicalType{
+		TIMESTAMP: &sch.TimestampType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+		},
+	}
+}
+
+// TimeOfDayType is a time.Duration since midnight
+// that is stored as the milliseconds since midnight.
+func TimeOfDayType(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_TIME_MILLIS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIME: &sch.TimeType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MILLIS: sch.NewMilliSeconds()},
+		},
+	}
+}
+
+func TimeOfDayMicrosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	ct := sch.ConvertedType_TIME_MICROS
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		TIME: &sch.TimeType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{MICROS: sch.NewMicroSeconds()},
+		},
+	}
+}
+
+// TimeOfDayNanosType doesn't have a converted type (there
+// isn't one for nanoseconds), only a logical type.
+func TimeOfDayNanosType(se *sch.SchemaElement) {
+	t := sch.Type_INT64
+	se.Type = &t
+	se.LogicalType = &sch.LogicalType{
+		TIME: &sch.TimeType{
+			IsAdjustedToUTC: true,
+			Unit:            &sch.TimeUnit{NANOS: sch.NewNanoSeconds()},
+		},
+	}
+}
+
+func DateType(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	ct := sch.ConvertedType_DATE
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		DATE: sch.NewDateType(),
+	}
+}
+
 func BoolType(se *sch.SchemaElement) {
 	t := sch.Type_BOOLEAN
 	se.Type = &t
@@ -2494,3 +6278,124 @@ func StringType(se *sch.SchemaElement) {
 	t := sch.Type_BYTE_ARRAY
 	se.Type = &t
 }
+
+func EnumType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+	ct := sch.ConvertedType_ENUM
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		ENUM: sch.NewEnumType(),
+	}
+}
+
+func JSONType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+	ct := sch.ConvertedType_JSON
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		JSON: sch.NewJsonType(),
+	}
+}
+
+func ByteArrayType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+}
+
+func BSONType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
+	ct := sch.ConvertedType_BSON
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{
+		BSON: sch.NewBsonType(),
+	}
+}
+
+func GeometryType(crs string) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		ByteArrayType(se)
+		se.LogicalType = &sch.LogicalType{
+			GEOMETRY: &sch.GeometryType{Crs: crsPtr(crs)},
+		}
+	}
+}
+
+func GeographyType(crs string) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		ByteArrayType(se)
+		se.LogicalType = &sch.LogicalType{
+			GEOGRAPHY: &sch.GeographyType{Crs: crsPtr(crs)},
+		}
+	}
+}
+
+// crsPtr leaves the crs of a geometry or geography
+// column unset (OGC:CRS84) when it's empty.
+func crsPtr(crs string) *string {
+	if crs == "" {
+		return nil
+	}
+	return &crs
+}
+
+func FixedLenByteArrayType(n int32) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		t := sch.Type_FIXED_LEN_BYTE_ARRAY
+		se.Type = &t
+		se.TypeLength = &n
+	}
+}
+
+func UUIDType(se *sch.SchemaElement) {
+	FixedLenByteArrayType(16)(se)
+	se.LogicalType = &sch.LogicalType{
+		UUID: sch.NewUUIDType(),
+	}
+}
+
+// Float16Type is a uint16 that holds the bits of a half precision
+// float, which is stored as a FIXED_LEN_BYTE_ARRAY(2) (the bits in
+// little-endian order) with the FLOAT16 logical type.
+func Float16Type(se *sch.SchemaElement) {
+	FixedLenByteArrayType(2)(se)
+	se.LogicalType = &sch.LogicalType{
+		FLOAT16: &sch.Float16Type{},
+	}
+}
+
+// IntervalType is a time.Duration that is stored as an INTERVAL
+// (see parquet.IntervalBytes).
+func IntervalType(se *sch.SchemaElement) {
+	FixedLenByteArrayType(12)(se)
+	ct := sch.ConvertedType_INTERVAL
+	se.ConvertedType = &ct
+}
+
+// NullType is a column that is always null.  Parquet columns need a
+// physical type, so it's an INT32 (that never has any values) with
+// the UNKNOWN logical type.
+func NullType(se *sch.SchemaElement) {
+	t := sch.Type_INT32
+	se.Type = &t
+	se.LogicalType = &sch.LogicalType{
+		UNKNOWN: sch.NewNullType(),
+	}
+}
+
+// DecimalType annotates a column of type typ (an INT32, INT64 or
+// FIXED_LEN_BYTE_ARRAY) as a decimal with the given precision and scale.
+func DecimalType(precision, scale int32, typ parquet.FieldFunc) parquet.FieldFunc {
+	return func(se *sch.SchemaElement) {
+		typ(se)
+		ct := sch.ConvertedType_DECIMAL
+		se.ConvertedType = &ct
+		se.Precision = &precision
+		se.Scale = &scale
+		se.LogicalType = &sch.LogicalType{
+			DECIMAL: &sch.DecimalType{Precision: precision, Scale: scale},
+		}
+	}
+}
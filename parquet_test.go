@@ -1,22 +1,38 @@
 package parquet_test
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
+	"math/bits"
 	"math/rand"
 	"os"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/andybalholm/brotli"
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/golang/snappy"
 	"github.com/parsyl/parquet"
+	"github.com/parsyl/parquet/internal/rle"
 	sch "github.com/parsyl/parquet/schema"
+	"github.com/pierrec/lz4/v4"
 	"github.com/stretchr/testify/assert"
 )
 
 //go:generate parquetgen -input parquet_test.go -type Person -package parquet_test -output parquet_generated_test.go
+//go:generate parquetgen -input parquet_test.go -type Measurement -package parquet_test -prefix Measurement -output measurement_generated_test.go
+//go:generate parquetgen -input parquet_test.go -type Event -package parquet_test -prefix Event -output event_generated_test.go
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
@@ -27,7 +43,7 @@ func init() {
 
 var (
 	letterRunes      = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
-	compressionCases = []string{"uncompressed", "snappy"}
+	compressionCases = []string{"uncompressed", "snappy", "zstd"}
 )
 
 func TestParquet(t *testing.T) {
@@ -415,6 +431,89 @@ func TestParquet(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "small ints",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Level: math.MaxInt8, Count: pint16(math.MinInt16)},
+					{Level: math.MinInt8},
+					{Level: -1, Count: pint16(math.MaxInt16)},
+				},
+			},
+		},
+		{
+			name:     "timestamps",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Created: time.Date(2021, 3, 4, 5, 6, 7, 8000000, time.UTC), Deleted: ptime(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC))},
+					{Created: time.Date(1969, 12, 31, 23, 59, 59, 999000000, time.UTC)},
+					{Deleted: ptime(time.Time{})},
+				},
+			},
+		},
+		{
+			name:     "dates",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Graduated: ptime(time.Date(2001, 6, 15, 0, 0, 0, 0, time.UTC))},
+					{},
+					{Graduated: ptime(time.Date(1965, 1, 2, 0, 0, 0, 0, time.UTC))},
+				},
+			},
+		},
+		{
+			name:     "bytes",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Payload: []byte{0xff, 0x00, 0xfe}, Thumbnail: []byte{0xc3, 0x28}},
+					{Payload: []byte("hello"), Thumbnail: []byte{}},
+					{Payload: []byte{0x00}},
+				},
+			},
+		},
+		{
+			name:     "fixed length byte arrays",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{UUID: [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}, Checksum: &[8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+					{UUID: [16]byte{0xff}},
+					{Checksum: &[8]byte{}},
+				},
+			},
+		},
+
+		{
+			name:     "repeated scalars",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Tags: []string{"a", "b"}, Scores: []int32{1}},
+					{Scores: []int32{2, 3, 4}},
+					{Tags: []string{"c"}},
+				},
+				{
+					{Tags: []string{"d", "e", "f"}, Scores: []int32{5, 6}},
+				},
+			},
+		},
+
+		{
+			name:     "pointer to struct chain",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Home: &Address{Street: "main", Geo: &Geo{Lat: 1.5, Lon: pfloat64(-2.5)}}},
+					{Home: &Address{Street: "elm", Geo: &Geo{Lat: 3}}},
+					{Home: &Address{Street: "oak"}},
+					{},
+				},
+			},
+		},
 		{
 			name:     "repeated two pages",
 			pageSize: 2,
@@ -452,87 +551,4625 @@ func TestParquet(t *testing.T) {
 		},
 	}
 
-	for i, tc := range testCases {
-		for j, comp := range compressionCases {
-			t.Run(fmt.Sprintf("%02d %s %s", 2*i+j, tc.name, comp), func(t *testing.T) {
-				if tc.pageSize == 0 {
-					tc.pageSize = 100
-				}
-				var buf bytes.Buffer
-				w, err := NewParquetWriter(&buf, MaxPageSize(tc.pageSize), compressionTest[comp])
-				assert.Nil(t, err, tc.name)
-				for _, rowgroup := range tc.input {
-					for _, p := range rowgroup {
-						w.Add(p)
-					}
-					assert.Nil(t, w.Write(), tc.name)
-				}
+	for i, tc := range testCases {
+		for j, comp := range compressionCases {
+			t.Run(fmt.Sprintf("%02d %s %s", 2*i+j, tc.name, comp), func(t *testing.T) {
+				if tc.pageSize == 0 {
+					tc.pageSize = 100
+				}
+				var buf bytes.Buffer
+				w, err := NewParquetWriter(&buf, MaxPageSize(tc.pageSize), compressionTest[comp])
+				assert.Nil(t, err, tc.name)
+				for _, rowgroup := range tc.input {
+					for _, p := range rowgroup {
+						w.Add(p)
+					}
+					assert.Nil(t, w.Write(), tc.name)
+				}
+
+				err = w.Close()
+				assert.Nil(t, err, tc.name)
+
+				r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				expected := tc.expected
+				if expected == nil {
+					expected = tc.input
+				}
+
+				if !assert.Equal(t, getLen(expected), int(r.Rows()), tc.name) {
+					return
+				}
+
+				var i int
+				for r.Next() {
+					var p Person
+					r.Scan(&p)
+					exp := getExpected(expected, i)
+					assert.Equal(t, *exp, p, fmt.Sprintf("%s-%d", tc.name, i))
+					i++
+				}
+
+				assert.Nil(t, r.Error(), tc.name)
+				assert.Equal(t, getLen(expected), i, tc.name)
+			})
+		}
+	}
+}
+
+func TestPageHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	docs := [][]Person{
+		{{}, {}, {}, {}},
+		{{}, {}, {}, {}},
+	}
+	for _, rowgroup := range docs {
+		for _, p := range rowgroup {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+
+	assert.NoError(t, w.Close())
+
+	rd := bytes.NewReader(buf.Bytes())
+	footer, err := parquet.ReadMetaData(rd)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pageHeaders, err := parquet.PageHeaders(footer, rd)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 144, len(pageHeaders))
+}
+
+func TestIntervalBytes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		d        time.Duration
+		days     uint32
+		millis   uint32
+		expected time.Duration
+		err      string
+	}{
+		{name: "zero"},
+		{name: "less than a day", d: 23*time.Hour + 59*time.Minute, millis: 86340000},
+		{name: "a day", d: 24 * time.Hour, days: 1},
+		{name: "more than a day", d: 25*time.Hour + 1500*time.Millisecond, days: 1, millis: 3601500},
+		{name: "sub millisecond", d: 2*24*time.Hour + time.Millisecond + time.Microsecond, days: 2, millis: 1, expected: 2*24*time.Hour + time.Millisecond},
+		{name: "negative", d: -(36 * time.Hour), err: "negative duration -36h0m0s can't be stored as an INTERVAL"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := parquet.IntervalBytes(tc.d)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, uint32(0), binary.LittleEndian.Uint32(b[:]))
+			assert.Equal(t, tc.days, binary.LittleEndian.Uint32(b[4:]))
+			assert.Equal(t, tc.millis, binary.LittleEndian.Uint32(b[8:]))
+
+			if tc.expected == 0 {
+				tc.expected = tc.d
+			}
+			assert.Equal(t, tc.expected, parquet.IntervalDuration(b))
+		})
+	}
+
+	// a month is 30 days
+	var b [12]byte
+	binary.LittleEndian.PutUint32(b[:], 2)
+	binary.LittleEndian.PutUint32(b[4:], 3)
+	assert.Equal(t, 63*24*time.Hour, parquet.IntervalDuration(b))
+
+	// the integers are unsigned
+	b = [12]byte{}
+	binary.LittleEndian.PutUint32(b[8:], 3000000000)
+	assert.Equal(t, 3000000000*time.Millisecond, parquet.IntervalDuration(b))
+}
+
+func TestInt96Time(t *testing.T) {
+	testCases := []struct {
+		name     string
+		day      uint32
+		nanos    uint64
+		expected time.Time
+	}{
+		{name: "unix epoch", day: 2440588, expected: time.Unix(0, 0).UTC()},
+		{name: "noon", day: 2451545, nanos: uint64(12 * time.Hour), expected: time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "nanos", day: 2459278, nanos: 18367000000008, expected: time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)},
+		{name: "before the unix epoch", day: 2440587, nanos: uint64(24*time.Hour - time.Millisecond), expected: time.Date(1969, 12, 31, 23, 59, 59, 999000000, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parquet.Int96Time(int96(tc.day, tc.nanos)))
+		})
+	}
+}
+
+// int96 returns the bytes of an INT96 timestamp.
+func int96(day uint32, nanos uint64) [12]byte {
+	var b [12]byte
+	binary.LittleEndian.PutUint64(b[:], nanos)
+	binary.LittleEndian.PutUint32(b[8:], day)
+	return b
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+
+	// hobby, hobby.skills, friends, home, and home.geo
+	groups := 5
+	if !assert.Equal(t, len(Fields(compressionSnappy, 0))+groups+1, len(schema)) {
+		return
+	}
+
+	assert.Equal(t, "root", schema[0].Name)
+	types := map[string]*sch.SchemaElement{}
+	for _, se := range schema[1:] {
+		types[se.Name] = se
+	}
+
+	assert.Equal(t, sch.FieldRepetitionType_OPTIONAL, types["hobby"].GetRepetitionType())
+	assert.Equal(t, sch.FieldRepetitionType_REPEATED, types["friends"].GetRepetitionType())
+	assert.Equal(t, int32(3), types["friends"].GetNumChildren())
+}
+
+func TestCompressionCodec(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []func(*ParquetWriter) error
+		expected sch.CompressionCodec
+	}{
+		{
+			name:     "default",
+			expected: sch.CompressionCodec_SNAPPY,
+		},
+		{
+			name:     "uncompressed",
+			opts:     []func(*ParquetWriter) error{Uncompressed},
+			expected: sch.CompressionCodec_UNCOMPRESSED,
+		},
+		{
+			name:     "snappy",
+			opts:     []func(*ParquetWriter) error{Snappy},
+			expected: sch.CompressionCodec_SNAPPY,
+		},
+		{
+			name:     "gzip",
+			opts:     []func(*ParquetWriter) error{Gzip},
+			expected: sch.CompressionCodec_GZIP,
+		},
+		{
+			name:     "zstd",
+			opts:     []func(*ParquetWriter) error{Zstd},
+			expected: sch.CompressionCodec_ZSTD,
+		},
+		{
+			name:     "zstd level",
+			opts:     []func(*ParquetWriter) error{ZstdLevel(19)},
+			expected: sch.CompressionCodec_ZSTD,
+		},
+		{
+			name:     "lz4 raw",
+			opts:     []func(*ParquetWriter) error{Lz4Raw},
+			expected: sch.CompressionCodec_LZ4_RAW,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			w.Add(Person{Being: Being{ID: 1}, Code: pstring("a")})
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			rd := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, rg := range footer.RowGroups {
+				for _, col := range rg.Columns {
+					assert.Equal(t, tc.expected, col.MetaData.Codec, col.MetaData.PathInSchema)
+				}
+			}
+
+			r, err := NewParquetReader(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var p Person
+			assert.True(t, r.Next())
+			r.Scan(&p)
+			assert.NoError(t, r.Error())
+			assert.Equal(t, int32(1), p.ID)
+			assert.Equal(t, pstring("a"), p.Code)
+		})
+	}
+}
+
+func TestZstdLevel(t *testing.T) {
+	_, err := NewParquetWriter(&bytes.Buffer{}, ZstdLevel(23))
+	assert.EqualError(t, err, "invalid zstd level 23, it must be between 1 and 22")
+
+	peeps := getPeople(1000, 5000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(500), ZstdLevel(3))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var i int
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		i++
+	}
+
+	assert.NoError(t, r.Error())
+	assert.Equal(t, getLen(peeps), i)
+}
+
+func TestColumnChunkStats(t *testing.T) {
+	type stats struct {
+		min   []byte
+		max   []byte
+		nulls *int64
+	}
+
+	zero := pint64(0)
+
+	var peeps [][]Person
+	for i := 0; i < 3; i++ {
+		rg := make([]Person, 250)
+		for j := range rg {
+			rg[j] = Person{
+				Being:     Being{ID: rand.Int31n(2000) - 1000},
+				Happiness: rand.Int63n(2000) - 1000,
+				Funkiness: rand.Float32() - 0.5,
+				BFF:       fmt.Sprintf("%c%d", letterRunes[rand.Intn(len(letterRunes))], rand.Intn(100)),
+				Level:     int8(rand.Intn(256) - 128),
+			}
+			if rand.Intn(4) > 0 {
+				rg[j].Sadness = pint64(rand.Int63n(2000) - 1000)
+			}
+			rg[j].Code = randString(rand.Intn(5) + 1)
+		}
+		peeps = append(peeps, rg)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.Equal(t, len(peeps), len(footer.RowGroups)) {
+		return
+	}
+
+	for i, rg := range peeps {
+		// brute force the expected stats from the input
+		p := rg[0]
+		minID, maxID := p.ID, p.ID
+		minHappiness, maxHappiness := p.Happiness, p.Happiness
+		minFunkiness, maxFunkiness := p.Funkiness, p.Funkiness
+		minBFF, maxBFF := p.BFF, p.BFF
+		minLevel, maxLevel := p.Level, p.Level
+		var minSadness, maxSadness *int64
+		var minCode, maxCode *string
+		var nilSadness, nilCode int64
+		for _, p := range rg {
+			if p.ID < minID {
+				minID = p.ID
+			}
+			if p.ID > maxID {
+				maxID = p.ID
+			}
+			if p.Happiness < minHappiness {
+				minHappiness = p.Happiness
+			}
+			if p.Happiness > maxHappiness {
+				maxHappiness = p.Happiness
+			}
+			if p.Funkiness < minFunkiness {
+				minFunkiness = p.Funkiness
+			}
+			if p.Funkiness > maxFunkiness {
+				maxFunkiness = p.Funkiness
+			}
+			if p.BFF < minBFF {
+				minBFF = p.BFF
+			}
+			if p.BFF > maxBFF {
+				maxBFF = p.BFF
+			}
+			if p.Level < minLevel {
+				minLevel = p.Level
+			}
+			if p.Level > maxLevel {
+				maxLevel = p.Level
+			}
+			if p.Sadness == nil {
+				nilSadness++
+			} else {
+				if minSadness == nil || *p.Sadness < *minSadness {
+					minSadness = p.Sadness
+				}
+				if maxSadness == nil || *p.Sadness > *maxSadness {
+					maxSadness = p.Sadness
+				}
+			}
+			if p.Code == nil {
+				nilCode++
+			} else {
+				if minCode == nil || *p.Code < *minCode {
+					minCode = p.Code
+				}
+				if maxCode == nil || *p.Code > *maxCode {
+					maxCode = p.Code
+				}
+			}
+		}
+
+		expected := map[string]stats{
+			"id":        {min: writeInt32(minID), max: writeInt32(maxID), nulls: zero},
+			"happiness": {min: writeInt64(minHappiness), max: writeInt64(maxHappiness), nulls: zero},
+			"funkiness": {min: writeFloat32(minFunkiness), max: writeFloat32(maxFunkiness), nulls: zero},
+			"bff":       {min: []byte(minBFF), max: []byte(maxBFF), nulls: zero},
+			"level":     {min: writeInt32(int32(minLevel)), max: writeInt32(int32(maxLevel)), nulls: zero},
+			"sadness":   {min: writeInt64(*minSadness), max: writeInt64(*maxSadness), nulls: &nilSadness},
+			"code":      {min: []byte(*minCode), max: []byte(*maxCode), nulls: &nilCode},
+		}
+
+		for _, col := range footer.RowGroups[i].Columns {
+			name := strings.Join(col.MetaData.PathInSchema, ".")
+			exp, ok := expected[name]
+			if !ok {
+				continue
+			}
+
+			st := col.MetaData.Statistics
+			if !assert.NotNil(t, st, name) {
+				continue
+			}
+			assert.Equal(t, exp.min, st.MinValue, fmt.Sprintf("rowgroup %d %s min", i, name))
+			assert.Equal(t, exp.max, st.MaxValue, fmt.Sprintf("rowgroup %d %s max", i, name))
+			assert.Equal(t, exp.nulls, st.NullCount, fmt.Sprintf("rowgroup %d %s nulls", i, name))
+			delete(expected, name)
+		}
+		assert.Empty(t, expected, fmt.Sprintf("rowgroup %d", i))
+	}
+}
+
+func TestStatsNaN(t *testing.T) {
+	nan32, nan64 := float32(math.NaN()), math.NaN()
+
+	testCases := []struct {
+		name string
+		col  string
+		in   []Person
+		min  []byte
+		max  []byte
+	}{
+		{
+			name: "float32",
+			col:  "funkiness",
+			in:   []Person{{Funkiness: nan32}, {Funkiness: 5}, {Funkiness: -1.5}},
+			min:  writeFloat32(-1.5),
+			max:  writeFloat32(5),
+		},
+		{
+			name: "float64",
+			col:  "boldness",
+			in:   []Person{{Boldness: nan64}, {Boldness: 2.5}, {Boldness: nan64}, {Boldness: -7}},
+			min:  writeFloat64(-7),
+			max:  writeFloat64(2.5),
+		},
+		{
+			name: "optional float32",
+			col:  "lameness",
+			in:   []Person{{Lameness: pfloat32(nan32)}, {}, {Lameness: pfloat32(3)}, {Lameness: pfloat32(1)}},
+			min:  writeFloat32(1),
+			max:  writeFloat32(3),
+		},
+
+		{
+			name: "only NaNs",
+			col:  "funkiness",
+			in:   []Person{{Funkiness: nan32}, {Funkiness: nan32}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, p := range tc.in {
+				w.Add(p)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			r := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(r)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var found bool
+			for _, col := range footer.RowGroups[0].Columns {
+				if strings.Join(col.MetaData.PathInSchema, ".") != tc.col {
+					continue
+				}
+				found = true
+				st := col.MetaData.Statistics
+				assert.Equal(t, tc.min, st.MinValue, "min")
+				assert.Equal(t, tc.max, st.MaxValue, "max")
+			}
+			assert.True(t, found, tc.col)
+
+			pages, err := getPageHeaders(r, tc.col, footer)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for _, ph := range pages {
+				st := ph.DataPageHeader.Statistics
+				assert.Equal(t, tc.min, st.MinValue, "page min")
+				assert.Equal(t, tc.max, st.MaxValue, "page max")
+			}
+		})
+	}
+}
+
+func TestStatsTruncateLength(t *testing.T) {
+	// the 64th byte of the largest bff can't be rounded
+	// up, so the max is rounded up at the 63rd instead
+	bffs := []string{
+		strings.Repeat("m", 10*1024),
+		strings.Repeat("a", 10*1024),
+		strings.Repeat("z", 63) + strings.Repeat("\xff", 10*1024-63),
+	}
+	payload := bytes.Repeat([]byte{0xff}, 10*1024)
+
+	var in []Person
+	for i, bff := range bffs {
+		p := Person{BFF: bff, Payload: payload}
+		if i > 0 {
+			p.Code = pstring(fmt.Sprintf("code %d", i))
+		}
+		in = append(in, p)
+	}
+
+	type stats struct {
+		min, max           []byte
+		minExact, maxExact *bool
+	}
+
+	testCases := []struct {
+		name     string
+		opts     []func(*ParquetWriter) error
+		expected map[string]stats
+	}{
+		{
+			name: "not truncated",
+			expected: map[string]stats{
+				"bff":     {min: []byte(bffs[1]), max: []byte(bffs[2])},
+				"payload": {min: payload, max: payload},
+				"code":    {min: []byte("code 1"), max: []byte("code 2")},
+			},
+		},
+		{
+			name: "truncated",
+			opts: []func(*ParquetWriter) error{StatsTruncateLength(64)},
+			expected: map[string]stats{
+				"bff":     {min: []byte(strings.Repeat("a", 64)), max: []byte(strings.Repeat("z", 62) + "{"), minExact: pbool(false), maxExact: pbool(false)},
+				"payload": {min: payload[:64], max: payload, minExact: pbool(false), maxExact: pbool(true)},
+				"code":    {min: []byte("code 1"), max: []byte("code 2"), minExact: pbool(true), maxExact: pbool(true)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for _, p := range in {
+				w.Add(p)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			r := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(r)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var found int
+			for _, col := range footer.RowGroups[0].Columns {
+				name := strings.Join(col.MetaData.PathInSchema, ".")
+				exp, ok := tc.expected[name]
+				if !ok {
+					continue
+				}
+				found++
+
+				// the page header's statistics are truncated the same way
+				headers, err := parquet.PageHeadersAtOffset(r, col.MetaData.DataPageOffset, col.MetaData.NumValues)
+				if !assert.NoError(t, err, name) || !assert.Len(t, headers, 1, name) {
+					continue
+				}
+
+				for _, st := range []*sch.Statistics{col.MetaData.Statistics, headers[0].DataPageHeader.Statistics} {
+					assert.Equal(t, exp.min, st.MinValue, name)
+					assert.Equal(t, exp.max, st.MaxValue, name)
+					assert.Equal(t, exp.minExact, st.IsMinValueExact, name)
+					assert.Equal(t, exp.maxExact, st.IsMaxValueExact, name)
+				}
+			}
+			assert.Equal(t, len(tc.expected), found)
+
+			// the truncated statistics still cover every value
+			pr, err := NewParquetReader(bytes.NewReader(buf.Bytes()), Filter("bff", parquet.Equal, bffs[2]))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Person
+			for pr.Next() {
+				var p Person
+				pr.Scan(&p)
+				out = append(out, p)
+			}
+			assert.NoError(t, pr.Error())
+			assert.Equal(t, in, out)
+		})
+	}
+
+	_, err := NewParquetWriter(&bytes.Buffer{}, StatsTruncateLength(-1))
+	assert.EqualError(t, err, "invalid stats truncate length -1, it must not be negative")
+}
+
+func TestDictionary(t *testing.T) {
+	countries := []string{"CA", "GB", "MX", "US"}
+	var peeps [][]Person
+	for i := 0; i < 3; i++ {
+		rg := make([]Person, 2500)
+		for j := range rg {
+			rg[j] = Person{
+				BFF:     countries[rand.Intn(len(countries))],
+				Payload: []byte(countries[rand.Intn(len(countries))]),
+				Tags:    countries[rand.Intn(len(countries)):],
+			}
+			if j%3 > 0 {
+				rg[j].Code = pstring(countries[rand.Intn(len(countries))])
+			}
+		}
+		peeps = append(peeps, rg)
+	}
+
+	testCases := []struct {
+		name       string
+		opts       []func(*ParquetWriter) error
+		dictionary bool
+	}{
+		{
+			name:       "default",
+			dictionary: true,
+		},
+		{
+			name:       "too big",
+			opts:       []func(*ParquetWriter) error{MaxDictionarySize(10)},
+			dictionary: false,
+		},
+		{
+			name:       "off",
+			opts:       []func(*ParquetWriter) error{MaxDictionarySize(0)},
+			dictionary: false,
+		},
+	}
+
+	sizes := map[bool]int{}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, append(tc.opts, MaxPageSize(1000), Uncompressed)...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+			sizes[tc.dictionary] = buf.Len()
+
+			rd := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for i, rg := range footer.RowGroups {
+				for _, col := range rg.Columns {
+					name := strings.Join(col.MetaData.PathInSchema, ".")
+					switch name {
+					case "bff", "code", "payload", "tags":
+					case "happiness", "created":
+						assert.Nil(t, col.MetaData.DictionaryPageOffset, name)
+						continue
+					default:
+						continue
+					}
+
+					if !tc.dictionary {
+						assert.Nil(t, col.MetaData.DictionaryPageOffset, name)
+						assert.Equal(t, []sch.Encoding{sch.Encoding_PLAIN}, col.MetaData.Encodings, name)
+						continue
+					}
+
+					if assert.NotNil(t, col.MetaData.DictionaryPageOffset, name) {
+						assert.Equal(t, col.FileOffset, *col.MetaData.DictionaryPageOffset, name)
+						assert.True(t, col.MetaData.DataPageOffset > col.FileOffset, name)
+					}
+					assert.Contains(t, col.MetaData.Encodings, sch.Encoding_RLE_DICTIONARY, name)
+
+					distinct := map[string]bool{}
+					for _, p := range peeps[i] {
+						switch name {
+						case "bff":
+							distinct[p.BFF] = true
+						case "code":
+							if p.Code != nil {
+								distinct[*p.Code] = true
+							}
+						case "payload":
+							distinct[string(p.Payload)] = true
+						case "tags":
+							for _, tag := range p.Tags {
+								distinct[tag] = true
+							}
+						}
+					}
+					assert.Equal(t, pint64(int64(len(distinct))), col.MetaData.Statistics.DistinctCount, name)
+
+					pages, err := parquet.PageHeadersAtOffset(rd, col.MetaData.DataPageOffset, col.MetaData.NumValues)
+					if assert.NoError(t, err) {
+						for _, ph := range pages {
+							assert.Equal(t, sch.Encoding_RLE_DICTIONARY, ph.DataPageHeader.Encoding, name)
+						}
+					}
+				}
+			}
+
+			r, err := NewParquetReader(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, getLen(peeps), i)
+		})
+	}
+
+	assert.True(t, sizes[true] < sizes[false], "dictionary: %d, plain: %d", sizes[true], sizes[false])
+}
+
+func TestBoolEncoding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		col      string
+		input    func(i int) Person
+		encoding sch.Encoding
+	}{
+		{
+			name:     "mostly true",
+			col:      "hungry",
+			input:    func(i int) Person { return Person{Hungry: i%97 != 0} },
+			encoding: sch.Encoding_RLE,
+		},
+		{
+			name:     "alternating",
+			col:      "hungry",
+			input:    func(i int) Person { return Person{Hungry: i%2 == 0} },
+			encoding: sch.Encoding_PLAIN,
+		},
+		{
+			name:     "optional mostly false",
+			col:      "keen",
+			input:    func(i int) Person { return Person{Keen: pbool(i%89 == 0)} },
+			encoding: sch.Encoding_RLE,
+		},
+		{
+			name: "optional random",
+			col:  "keen",
+			input: func(i int) Person {
+				if i%3 == 0 {
+					return Person{}
+				}
+				return Person{Keen: pbool(rand.Intn(2) == 0)}
+			},
+			encoding: sch.Encoding_PLAIN,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var peeps [][]Person
+			for i := 0; i < 2; i++ {
+				rg := make([]Person, 1000)
+				for j := range rg {
+					rg[j] = tc.input(j)
+				}
+				peeps = append(peeps, rg)
+			}
+
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, MaxPageSize(300))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+
+			rd := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			pages, err := getPageHeaders(rd, tc.col, footer)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, 8, len(pages))
+			for _, ph := range pages {
+				assert.Equal(t, tc.encoding, ph.DataPageHeader.Encoding)
+			}
+
+			r, err := NewParquetReader(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, getLen(peeps), i)
+		})
+	}
+}
+
+func TestDeltaByteArray(t *testing.T) {
+	var peeps [][]Person
+	for i := 0; i < 2; i++ {
+		rg := make([]Person, 1000)
+		for j := range rg {
+			url := fmt.Sprintf("https://example.com/people/%05d/profile", i*1000+j)
+			rg[j] = Person{
+				BFF:     url,
+				Payload: []byte(url + "/payload"),
+			}
+			if j%3 > 0 {
+				rg[j].Code = pstring(url + "/code")
+			}
+			if j%4 > 0 {
+				rg[j].Tags = []string{url, url + "/tags", ""}[:j%4]
+			}
+		}
+		peeps = append(peeps, rg)
+	}
+
+	testCases := []struct {
+		name     string
+		opt      func(*ParquetWriter) error
+		encoding sch.Encoding
+	}{
+		{
+			name:     "plain",
+			opt:      MaxDictionarySize(0),
+			encoding: sch.Encoding_PLAIN,
+		},
+		{
+			name:     "delta length byte array",
+			opt:      DeltaLengthByteArray,
+			encoding: sch.Encoding_DELTA_LENGTH_BYTE_ARRAY,
+		},
+		{
+			name:     "delta byte array",
+			opt:      DeltaByteArray,
+			encoding: sch.Encoding_DELTA_BYTE_ARRAY,
+		},
+	}
+
+	sizes := map[sch.Encoding]int{}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, tc.opt, MaxPageSize(300), Uncompressed)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+			sizes[tc.encoding] = buf.Len()
+
+			rd := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, col := range []string{"bff", "code", "payload", "tags", "id"} {
+				pages, err := getPageHeaders(rd, col, footer)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				for _, ph := range pages {
+					if col == "id" {
+						assert.Equal(t, sch.Encoding_PLAIN, ph.DataPageHeader.Encoding, col)
+					} else {
+						assert.Equal(t, tc.encoding, ph.DataPageHeader.Encoding, col)
+					}
+				}
+			}
+
+			r, err := NewParquetReader(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, getLen(peeps), i)
+		})
+	}
+
+	plain := sizes[sch.Encoding_PLAIN]
+	assert.True(t, sizes[sch.Encoding_DELTA_BYTE_ARRAY] < plain, "delta byte array: %d, plain: %d", sizes[sch.Encoding_DELTA_BYTE_ARRAY], plain)
+}
+
+func TestEncodingTags(t *testing.T) {
+	var in []Measurement
+	for i := 0; i < 200; i++ {
+		m := Measurement{
+			ID:     int64(1000 + i),
+			Sensor: fmt.Sprintf("sensor-%03d", i%7),
+			Value:  float64(i) * 0.5,
+		}
+		if i%5 > 0 {
+			m.Count = pint32(int32(i))
+		}
+		in = append(in, m)
+	}
+
+	// the struct tags of id, sensor and count set their encodings
+	expected := map[string]sch.Encoding{
+		"id":     sch.Encoding_DELTA_BINARY_PACKED,
+		"sensor": sch.Encoding_DELTA_BYTE_ARRAY,
+		"count":  sch.Encoding_PLAIN,
+		"value":  sch.Encoding_PLAIN,
+	}
+
+	testCases := []struct {
+		name string
+		opts []func(*MeasurementParquetWriter) error
+	}{
+		{name: "default options"},
+		{name: "delta binary packed", opts: []func(*MeasurementParquetWriter) error{MeasurementDeltaBinaryPacked}},
+		{name: "delta length byte array", opts: []func(*MeasurementParquetWriter) error{MeasurementDeltaLengthByteArray}},
+		{name: "data page v2", opts: []func(*MeasurementParquetWriter) error{MeasurementDataPageV2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewMeasurementParquetWriter(&buf, append(tc.opts, MeasurementMaxPageSize(50))...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, m := range in {
+				w.Add(m)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			rd := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for col, enc := range expected {
+				pages, err := getPageHeaders(rd, col, footer)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				assert.Equal(t, 4, len(pages), col)
+				for _, ph := range pages {
+					if ph.DataPageHeaderV2 != nil {
+						assert.Equal(t, enc, ph.DataPageHeaderV2.Encoding, col)
+					} else {
+						assert.Equal(t, enc, ph.DataPageHeader.GetEncoding(), col)
+					}
+				}
+			}
+
+			r, err := NewMeasurementParquetReader(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Measurement
+			for r.Next() {
+				var m Measurement
+				r.Scan(&m)
+				out = append(out, m)
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, in, out)
+		})
+	}
+}
+
+func TestDataPageV2(t *testing.T) {
+	peeps := getPeople(500, 1000)
+	var nilAges, tags int
+	for _, rg := range peeps {
+		for j := range rg {
+			if rg[j].Age == nil {
+				nilAges++
+			}
+			if j%3 > 0 {
+				rg[j].Tags = []string{"a", "b"}[:j%3]
+				tags += j % 3
+			}
+		}
+	}
+
+	testCases := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+		typ  sch.PageType
+	}{
+		{
+			name: "v1",
+			typ:  sch.PageType_DATA_PAGE,
+		},
+		{
+			name: "v2",
+			opts: []func(*ParquetWriter) error{DataPageV2},
+			typ:  sch.PageType_DATA_PAGE_V2,
+		},
+		{
+			name: "v2 uncompressed",
+			opts: []func(*ParquetWriter) error{DataPageV2, Uncompressed},
+			typ:  sch.PageType_DATA_PAGE_V2,
+		},
+		{
+			name: "v2 gzip",
+			opts: []func(*ParquetWriter) error{DataPageV2, Gzip},
+			typ:  sch.PageType_DATA_PAGE_V2,
+		},
+		{
+			name: "v2 zstd",
+			opts: []func(*ParquetWriter) error{DataPageV2, Zstd},
+			typ:  sch.PageType_DATA_PAGE_V2,
+		},
+		{
+			name: "v2 lz4 raw",
+			opts: []func(*ParquetWriter) error{DataPageV2, Lz4Raw},
+			typ:  sch.PageType_DATA_PAGE_V2,
+		},
+		{
+			name: "v2 delta",
+			opts: []func(*ParquetWriter) error{DataPageV2, DeltaBinaryPacked, DeltaByteArray},
+			typ:  sch.PageType_DATA_PAGE_V2,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, append(tc.opts, MaxPageSize(300))...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+
+			rd := bytes.NewReader(buf.Bytes())
+			footer, err := parquet.ReadMetaData(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, col := range []string{"age", "happiness", "tags"} {
+				var rows, nulls, vals int
+				for _, rg := range footer.RowGroups {
+					for _, c := range rg.Columns {
+						// friends have an age too
+						if strings.Join(c.MetaData.PathInSchema, ".") != col {
+							continue
+						}
+
+						pages, err := parquet.PageHeadersAtOffset(rd, c.MetaData.DataPageOffset, c.MetaData.NumValues)
+						if !assert.NoError(t, err) {
+							return
+						}
+
+						for _, ph := range pages {
+							assert.Equal(t, tc.typ, ph.Type, col)
+							if h := ph.DataPageHeaderV2; h != nil {
+								rows += int(h.NumRows)
+								nulls += int(h.NumNulls)
+								vals += int(h.NumValues)
+							}
+						}
+					}
+				}
+
+				if tc.typ != sch.PageType_DATA_PAGE_V2 {
+					continue
+				}
+
+				assert.Equal(t, getLen(peeps), rows, col)
+				switch col {
+				case "age":
+					assert.Equal(t, nilAges, nulls, col)
+				case "happiness":
+					assert.Equal(t, 0, nulls, col)
+				case "tags":
+					// an empty list has a null in its place
+					assert.Equal(t, tags, vals-nulls, col)
+				}
+			}
+
+			r, err := NewParquetReader(rd)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, getLen(peeps), i)
+		})
+	}
+}
+
+func TestArrowFile(t *testing.T) {
+	var in []Measurement
+	for i := 0; i < 20; i++ {
+		m := Measurement{
+			ID:     int64(i),
+			Sensor: []string{"north", "south", "east"}[i%3],
+			Serial: [4]byte{'s', 'n', byte('0' + i%2), 0},
+			Value:  float64(i%4) * 0.5,
+		}
+		if i%3 > 0 {
+			c := Celsius(float32(i) + 0.25)
+			m.Temp = &c
+		}
+		if i%5 > 0 {
+			m.Count = pint32(7)
+		}
+		if i%4 > 0 {
+			m.OK = pbool(i%2 == 0)
+		}
+		in = append(in, m)
+	}
+
+	testCases := []struct {
+		name    string
+		dictEnc sch.Encoding
+		dataEnc sch.Encoding
+		codec   sch.CompressionCodec
+		err     string
+	}{
+		{
+			name:    "format 1.0",
+			dictEnc: sch.Encoding_PLAIN_DICTIONARY,
+			dataEnc: sch.Encoding_PLAIN_DICTIONARY,
+			codec:   sch.CompressionCodec_SNAPPY,
+		},
+		{
+			name:    "format 2.6",
+			dictEnc: sch.Encoding_PLAIN,
+			dataEnc: sch.Encoding_RLE_DICTIONARY,
+			codec:   sch.CompressionCodec_SNAPPY,
+		},
+		{
+			name:    "no dictionary",
+			dataEnc: sch.Encoding_PLAIN,
+			codec:   sch.CompressionCodec_UNCOMPRESSED,
+		},
+		{
+			name:    "brotli",
+			dictEnc: sch.Encoding_PLAIN,
+			dataEnc: sch.Encoding_RLE_DICTIONARY,
+			codec:   sch.CompressionCodec_BROTLI,
+		},
+		{
+			name:    "brotli no dictionary",
+			dataEnc: sch.Encoding_PLAIN,
+			codec:   sch.CompressionCodec_BROTLI,
+		},
+		{
+			name:    "lz4 raw",
+			dictEnc: sch.Encoding_PLAIN,
+			dataEnc: sch.Encoding_RLE_DICTIONARY,
+			codec:   sch.CompressionCodec_LZ4_RAW,
+		},
+		{
+			name:    "unsupported codec",
+			dataEnc: sch.Encoding_PLAIN,
+			codec:   sch.CompressionCodec_LZO,
+			err:     "unable to read field id, err: unsupported column chunk codec: LZO",
+		},
+		{
+			name:    "framed lz4",
+			dataEnc: sch.Encoding_PLAIN,
+			codec:   sch.CompressionCodec_LZ4,
+			err:     "unable to read field id, err: unsupported column chunk codec: LZ4 (only LZ4_RAW is supported)",
+		},
+		{
+			name:    "unsupported encoding",
+			dataEnc: sch.Encoding_BIT_PACKED,
+			codec:   sch.CompressionCodec_SNAPPY,
+			err:     "unable to read field id, err: unsupported BIT_PACKED encoding for INT64 column",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			data, err := arrowFile(measurementColumns(in), len(in), tc.dictEnc, tc.dataEnc, tc.codec)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			r, err := NewMeasurementParquetReader(bytes.NewReader(data))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Measurement
+			for r.Next() {
+				var m Measurement
+				r.Scan(&m)
+				out = append(out, m)
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, in, out)
+		})
+	}
+}
+
+func TestInt96File(t *testing.T) {
+	in := []Event{
+		{Created: time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC), Deleted: ptime(time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC))},
+		{Created: time.Date(1969, 12, 31, 23, 59, 59, 999000000, time.UTC)},
+		{Created: time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC), Deleted: ptime(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC))},
+	}
+
+	// the columns are written the way spark writes timestamps
+	spark := func(tm time.Time) []byte {
+		day := tm.Truncate(24 * time.Hour)
+		b := int96(uint32(day.Unix()/86400+2440588), uint64(tm.Sub(day)))
+		return b[:]
+	}
+
+	required := sch.FieldRepetitionType_REQUIRED
+	optional := sch.FieldRepetitionType_OPTIONAL
+	cols := []arrowColumn{
+		{se: &sch.SchemaElement{Name: "created", Type: sch.TypePtr(sch.Type_INT96), RepetitionType: &required}},
+		{se: &sch.SchemaElement{Name: "deleted", Type: sch.TypePtr(sch.Type_INT96), RepetitionType: &optional}},
+	}
+	for _, p := range in {
+		cols[0].vals = append(cols[0].vals, spark(p.Created))
+		var deleted []byte
+		if p.Deleted != nil {
+			deleted = spark(*p.Deleted)
+		}
+		cols[1].vals = append(cols[1].vals, deleted)
+	}
+
+	testCases := []struct {
+		name    string
+		dictEnc sch.Encoding
+		dataEnc sch.Encoding
+		codec   sch.CompressionCodec
+	}{
+		{name: "plain", dataEnc: sch.Encoding_PLAIN, codec: sch.CompressionCodec_SNAPPY},
+		{name: "dictionary", dictEnc: sch.Encoding_PLAIN, dataEnc: sch.Encoding_RLE_DICTIONARY, codec: sch.CompressionCodec_SNAPPY},
+		// newer versions of spark compress with LZ4_RAW by default
+		{name: "lz4 raw", dictEnc: sch.Encoding_PLAIN, dataEnc: sch.Encoding_RLE_DICTIONARY, codec: sch.CompressionCodec_LZ4_RAW},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := arrowFile(cols, len(in), tc.dictEnc, tc.dataEnc, tc.codec)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			r, err := NewEventParquetReader(bytes.NewReader(data))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Event
+			for r.Next() {
+				var e Event
+				r.Scan(&e)
+				out = append(out, e)
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, in, out)
+
+			// the file doesn't have the rest of Person's required columns
+			_, err = NewParquetReader(bytes.NewReader(data))
+			assert.EqualError(t, err, "the file doesn't have a column for required field id")
+		})
+	}
+}
+
+// arrowColumn is a column of an arrowFile.  Each value is nil
+// (null) or the value's plain encoding (without the length of
+// byte arrays).
+type arrowColumn struct {
+	se   *sch.SchemaElement
+	vals [][]byte
+}
+
+// arrowFile writes the columns (which each have a value for each of
+// the rows) the way pyarrow (parquet-cpp) does: every column except
+// booleans gets a dictionary page, the levels are RLE encoded and the
+// file_offset of each column chunk points at the end of the column
+// chunk.  Passing a data page encoding that isn't a dictionary encoding
+// plain encodes the values but keeps the encoding in the page headers.
+func arrowFile(cols []arrowColumn, rows int, dictEnc, dataEnc sch.Encoding, codec sch.CompressionCodec) ([]byte, error) {
+	ts := thrift.NewTSerializer()
+	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
+
+	buf := bytes.NewBufferString("PAR1")
+	rg := &sch.RowGroup{NumRows: int64(rows)}
+	schema := []*sch.SchemaElement{{Name: "schema"}}
+	for _, col := range cols {
+		schema = append(schema, col.se)
+		var defs []uint32
+		var vals [][]byte
+		for _, v := range col.vals {
+			if v != nil {
+				vals = append(vals, v)
+				defs = append(defs, 1)
+			} else {
+				defs = append(defs, 0)
+			}
+		}
+
+		var levels []byte
+		if col.se.GetRepetitionType() == sch.FieldRepetitionType_OPTIONAL {
+			levels = rle.Encode(1, defs)
+			levels = append(arrowUint32(uint32(len(levels))), levels...)
+		}
+
+		md := &sch.ColumnMetaData{
+			Type:         col.se.GetType(),
+			PathInSchema: []string{col.se.Name},
+			Codec:        codec,
+			NumValues:    int64(len(col.vals)),
+		}
+
+		start := int64(buf.Len())
+		enc := dataEnc
+		var body []byte
+		switch {
+		case md.Type == sch.Type_BOOLEAN:
+			enc = sch.Encoding_PLAIN
+			body = arrowPlain(md.Type, vals)
+		case enc == sch.Encoding_PLAIN_DICTIONARY || enc == sch.Encoding_RLE_DICTIONARY:
+			var dict [][]byte
+			index := map[string]uint32{}
+			ids := make([]uint32, len(vals))
+			for i, v := range vals {
+				id, ok := index[string(v)]
+				if !ok {
+					id = uint32(len(dict))
+					index[string(v)] = id
+					dict = append(dict, v)
+				}
+				ids[i] = id
+			}
+
+			ph := &sch.PageHeader{
+				Type: sch.PageType_DICTIONARY_PAGE,
+				DictionaryPageHeader: &sch.DictionaryPageHeader{
+					NumValues: int32(len(dict)),
+					Encoding:  dictEnc,
+				},
+			}
+			if err := arrowPage(buf, ts, ph, arrowPlain(md.Type, dict), codec, md); err != nil {
+				return nil, err
+			}
+			md.DictionaryPageOffset = thrift.Int64Ptr(start)
+
+			// a dictionary with one value has a bit width of 0
+			width := bits.Len(uint(len(dict) - 1))
+			body = append([]byte{byte(width)}, rle.Encode(width, ids)...)
+			md.Encodings = append(md.Encodings, dictEnc)
+		default:
+			body = arrowPlain(md.Type, vals)
+		}
+
+		md.DataPageOffset = int64(buf.Len())
+		md.Encodings = append(md.Encodings, sch.Encoding_RLE, enc)
+		ph := &sch.PageHeader{
+			Type: sch.PageType_DATA_PAGE,
+			DataPageHeader: &sch.DataPageHeader{
+				NumValues:               int32(len(col.vals)),
+				Encoding:                enc,
+				DefinitionLevelEncoding: sch.Encoding_RLE,
+				RepetitionLevelEncoding: sch.Encoding_RLE,
+			},
+		}
+		if err := arrowPage(buf, ts, ph, append(levels, body...), codec, md); err != nil {
+			return nil, err
+		}
+
+		rg.Columns = append(rg.Columns, &sch.ColumnChunk{
+			FileOffset: int64(buf.Len()),
+			MetaData:   md,
+		})
+		rg.TotalByteSize += md.TotalUncompressedSize
+	}
+
+	n := int32(len(schema) - 1)
+	schema[0].NumChildren = &n
+	createdBy := "parquet-cpp-arrow version 14.0.2"
+	footer, err := ts.Write(context.TODO(), &sch.FileMetaData{
+		Version:   1,
+		Schema:    schema,
+		NumRows:   int64(rows),
+		RowGroups: []*sch.RowGroup{rg},
+		CreatedBy: &createdBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf.Write(footer)
+	binary.Write(buf, binary.LittleEndian, uint32(len(footer)))
+	buf.WriteString("PAR1")
+	return buf.Bytes(), nil
+}
+
+func arrowPage(w io.Writer, ts *thrift.TSerializer, ph *sch.PageHeader, data []byte, codec sch.CompressionCodec, md *sch.ColumnMetaData) error {
+	ph.UncompressedPageSize = int32(len(data))
+	switch codec {
+	case sch.CompressionCodec_SNAPPY:
+		data = snappy.Encode(nil, data)
+	case sch.CompressionCodec_BROTLI:
+		var buf bytes.Buffer
+		bw := brotli.NewWriter(&buf)
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+		if err := bw.Close(); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	case sch.CompressionCodec_LZ4_RAW:
+		buf := make([]byte, lz4.CompressBlockBound(len(data)))
+		n, err := lz4.CompressBlock(data, buf, nil)
+		if err != nil {
+			return err
+		}
+		data = buf[:n]
+	}
+	ph.CompressedPageSize = int32(len(data))
+
+	hdr, err := ts.Write(context.TODO(), ph)
+	if err != nil {
+		return err
+	}
+
+	md.TotalUncompressedSize += int64(len(hdr)) + int64(ph.UncompressedPageSize)
+	md.TotalCompressedSize += int64(len(hdr)) + int64(ph.CompressedPageSize)
+	_, err = w.Write(append(hdr, data...))
+	return err
+}
+
+// arrowPlain plain encodes vals.
+func arrowPlain(typ sch.Type, vals [][]byte) []byte {
+	var out []byte
+	if typ == sch.Type_BOOLEAN {
+		out = make([]byte, (len(vals)+7)/8)
+	}
+
+	for i, v := range vals {
+		switch typ {
+		case sch.Type_BOOLEAN:
+			out[i/8] |= v[0] << uint(i%8)
+		case sch.Type_BYTE_ARRAY:
+			out = append(out, arrowUint32(uint32(len(v)))...)
+			out = append(out, v...)
+		default:
+			out = append(out, v...)
+		}
+	}
+	return out
+}
+
+func measurementColumns(ms []Measurement) []arrowColumn {
+	utf8 := sch.ConvertedType_UTF8
+	serialLen := int32(4)
+	cols := []arrowColumn{
+		{se: &sch.SchemaElement{Name: "id", Type: sch.TypePtr(sch.Type_INT64)}},
+		{se: &sch.SchemaElement{Name: "sensor", Type: sch.TypePtr(sch.Type_BYTE_ARRAY), ConvertedType: &utf8}},
+		{se: &sch.SchemaElement{Name: "serial", Type: sch.TypePtr(sch.Type_FIXED_LEN_BYTE_ARRAY), TypeLength: &serialLen}},
+		{se: &sch.SchemaElement{Name: "value", Type: sch.TypePtr(sch.Type_DOUBLE)}},
+		{se: &sch.SchemaElement{Name: "temp", Type: sch.TypePtr(sch.Type_FLOAT)}},
+		{se: &sch.SchemaElement{Name: "count", Type: sch.TypePtr(sch.Type_INT32)}},
+		{se: &sch.SchemaElement{Name: "ok", Type: sch.TypePtr(sch.Type_BOOLEAN)}},
+	}
+
+	for _, m := range ms {
+		serial := m.Serial
+		row := [][]byte{
+			arrowUint64(uint64(m.ID)),
+			[]byte(m.Sensor),
+			serial[:],
+			arrowUint64(math.Float64bits(m.Value)),
+			nil,
+			nil,
+			nil,
+		}
+		if m.Temp != nil {
+			row[4] = arrowUint32(math.Float32bits(float32(*m.Temp)))
+		}
+		if m.Count != nil {
+			row[5] = arrowUint32(uint32(*m.Count))
+		}
+		if m.OK != nil {
+			row[6] = []byte{0}
+			if *m.OK {
+				row[6][0] = 1
+			}
+		}
+
+		for i, v := range row {
+			cols[i].vals = append(cols[i].vals, v)
+		}
+	}
+
+	for i := range cols {
+		rt := sch.FieldRepetitionType_REQUIRED
+		if i >= 4 {
+			rt = sch.FieldRepetitionType_OPTIONAL
+		}
+		cols[i].se.RepetitionType = &rt
+	}
+	return cols
+}
+
+func arrowUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func arrowUint64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+func TestNoCopy(t *testing.T) {
+	peeps := getPeople(100, 1000)
+	for i, p := range peeps {
+		for j := range p {
+			n := i*100 + j
+			p[j].BFF = fmt.Sprintf("friend %d", n%7)
+			if n%3 > 0 {
+				p[j].Payload = []byte(fmt.Sprintf("payload %d", n))
+			}
+			if n%4 > 0 {
+				p[j].Thumbnail = make([]byte, n%4-1)
+			}
+		}
+	}
+
+	testCases := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "dictionary"},
+		{name: "plain", opts: []func(*ParquetWriter) error{MaxDictionarySize(0)}},
+		{name: "small pages", opts: []func(*ParquetWriter) error{MaxPageSize(30), Snappy}},
+		{name: "delta byte array", opts: []func(*ParquetWriter) error{DeltaByteArray}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), NoCopy)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// the values are only checked while they're
+			// in the row group that the reader is on
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, 1000, i)
+		})
+	}
+}
+
+func TestByteArrays(t *testing.T) {
+	data := []byte{3, 0, 0, 0, 'a', 'b', 'c', 0, 0, 0, 0, 2, 0, 0, 0, 'd', 'e'}
+
+	t.Run("values", func(t *testing.T) {
+		vals, err := parquet.ByteArrays(bytes.NewBuffer(data), 3)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, [][]byte{[]byte("abc"), {}, []byte("de")}, vals)
+		assert.Equal(t, "abc", parquet.NoCopyString(vals[0]))
+
+		// the values share the data's memory, but
+		// appending to one doesn't change the next
+		data[4] = 'x'
+		assert.Equal(t, "xbc", string(vals[0]))
+		vals[1] = append(vals[1], 'z')
+		assert.Equal(t, "de", string(vals[2]))
+		data[4] = 'a'
+	})
+
+	t.Run("not a buffer", func(t *testing.T) {
+		vals, err := parquet.ByteArrays(bytes.NewReader(data), 3)
+		if assert.NoError(t, err) {
+			assert.Equal(t, [][]byte{[]byte("abc"), {}, []byte("de")}, vals)
+		}
+	})
+
+	t.Run("too many values", func(t *testing.T) {
+		_, err := parquet.ByteArrays(bytes.NewBuffer(data), 4)
+		assert.EqualError(t, err, "byte array value 3: unexpected EOF")
+	})
+
+	t.Run("short value", func(t *testing.T) {
+		_, err := parquet.ByteArrays(bytes.NewBuffer(data[:16]), 3)
+		assert.EqualError(t, err, "byte array value 2 is 2 bytes, only 1 are left: unexpected EOF")
+	})
+}
+
+func TestReadColumn(t *testing.T) {
+	peeps := getPeople(100, 1000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(30))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	var happiness []int64
+	var sadness []int64
+	var sadnessDefs []uint8
+	var codes []string
+	var codeDefs []uint8
+	var keen []bool
+	var keenDefs []uint8
+	for _, rg := range peeps {
+		for _, p := range rg {
+			happiness = append(happiness, p.Happiness)
+			if p.Sadness == nil {
+				sadnessDefs = append(sadnessDefs, 0)
+			} else {
+				sadness = append(sadness, *p.Sadness)
+				sadnessDefs = append(sadnessDefs, 1)
+			}
+			if p.Code == nil {
+				codeDefs = append(codeDefs, 0)
+			} else {
+				codes = append(codes, *p.Code)
+				codeDefs = append(codeDefs, 1)
+			}
+			if p.Keen == nil {
+				keenDefs = append(keenDefs, 0)
+			} else {
+				keen = append(keen, *p.Keen)
+				keenDefs = append(keenDefs, 1)
+			}
+		}
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// read a few rows first to make sure reading a
+	// column doesn't get in the way of reading rows.
+	var i int
+	for i < 150 && r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		i++
+	}
+
+	t.Run("required", func(t *testing.T) {
+		var vals []int64
+		lvls, err := r.ReadColumn("Happiness", &vals)
+		if assert.NoError(t, err) {
+			assert.Equal(t, happiness, vals)
+			assert.Equal(t, "happiness", lvls.Name)
+			assert.Nil(t, lvls.Defs)
+			assert.Nil(t, lvls.Reps)
+		}
+	})
+
+	t.Run("optional", func(t *testing.T) {
+		var vals []int64
+		lvls, err := r.ReadColumn("sadness", &vals)
+		if assert.NoError(t, err) {
+			assert.Equal(t, sadness, vals)
+			assert.Equal(t, sadnessDefs, lvls.Defs)
+		}
+	})
+
+	t.Run("optional string", func(t *testing.T) {
+		var vals []string
+		lvls, err := r.ReadColumn("Code", &vals)
+		if assert.NoError(t, err) {
+			assert.Equal(t, codes, vals)
+			assert.Equal(t, codeDefs, lvls.Defs)
+		}
+	})
+
+	t.Run("optional bool", func(t *testing.T) {
+		var vals []bool
+		lvls, err := r.ReadColumn("keen", &vals)
+		if assert.NoError(t, err) {
+			assert.Equal(t, keen, vals)
+			assert.Equal(t, keenDefs, lvls.Defs)
+		}
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		var vals []int32
+		_, err := r.ReadColumn("happiness", &vals)
+		assert.EqualError(t, err, "column happiness can't be read in to a *[]int32, it must be a *[]int64")
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		var vals []int64
+		_, err := r.ReadColumn("Grumpiness", &vals)
+		assert.EqualError(t, err, "unknown column: Grumpiness")
+	})
+
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		i++
+	}
+
+	assert.NoError(t, r.Error())
+	assert.Equal(t, getLen(peeps), i)
+}
+
+func TestMaxRowGroupRows(t *testing.T) {
+	peeps := getPeople(10000, 10000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(1000), MaxRowGroupRows(2500))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, p := range peeps[0] {
+		w.Add(p)
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, int64(10000), footer.NumRows)
+	if !assert.Equal(t, 4, len(footer.RowGroups)) {
+		return
+	}
+
+	for i, rg := range footer.RowGroups {
+		assert.Equal(t, int64(2500), rg.NumRows)
+		for _, ch := range rg.Columns {
+			if strings.Join(ch.MetaData.PathInSchema, ".") != "id" {
+				continue
+			}
+			assert.Equal(t, int64(2500), ch.MetaData.NumValues)
+			assert.Equal(t, writeInt32(int32(i*2500)), ch.MetaData.Statistics.MinValue)
+			assert.Equal(t, writeInt32(int32(i*2500+2499)), ch.MetaData.Statistics.MaxValue)
+		}
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var i int
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, peeps[0][i], p, i)
+		i++
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, 10000, i)
+
+	_, err = NewParquetWriter(&buf, MaxRowGroupRows(-1))
+	assert.EqualError(t, err, "invalid max row group rows -1, it must not be negative")
+}
+
+func TestWriteRow(t *testing.T) {
+	testCases := []struct {
+		name      string
+		max       int
+		rowGroups int
+	}{
+		{name: "one row group", max: 0, rowGroups: 1},
+		{name: "even row groups", max: 250, rowGroups: 4},
+		{name: "left over rows", max: 300, rowGroups: 4},
+	}
+
+	peeps := getPeople(1000, 1000)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the rows are written one at a time
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, MaxRowGroupRows(tc.max))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, p := range peeps[0] {
+				if !assert.NoError(t, w.WriteRow(p)) {
+					return
+				}
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			// and all at once (as row groups of max rows)
+			var expected bytes.Buffer
+			w, err = NewParquetWriter(&expected)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for i, p := range peeps[0] {
+				w.Add(p)
+				if tc.max > 0 && (i+1)%tc.max == 0 {
+					assert.NoError(t, w.Write())
+				}
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			assert.Equal(t, expected.Bytes(), buf.Bytes())
+
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.rowGroups, r.NumRowGroups())
+			assert.Equal(t, int64(1000), r.NumRows())
+		})
+	}
+}
+
+func TestWriteRowError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxRowGroupRows(2), WriteContext(ctx))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	peeps := getPeople(10, 10)
+	assert.NoError(t, w.WriteRow(peeps[0][0]))
+	cancel()
+	// the second row fills the row group, which can't be written
+	assert.Equal(t, context.Canceled, w.WriteRow(peeps[0][1]))
+	assert.Equal(t, context.Canceled, w.WriteRow(peeps[0][2]))
+}
+
+func TestFlush(t *testing.T) {
+	peeps := getPeople(300, 900)
+	var buf bytes.Buffer
+	bw := bufio.NewWriterSize(&buf, 1<<20)
+	w, err := NewParquetWriter(bw, MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// flushed holds the length of buf after each row group is flushed
+	flushed := []int64{int64(buf.Len())}
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Flush())
+		flushed = append(flushed, int64(buf.Len()))
+
+		// there aren't any new rows, so nothing is written
+		assert.NoError(t, w.Flush())
+		assert.Equal(t, flushed[len(flushed)-1], int64(buf.Len()))
+	}
+
+	assert.NoError(t, w.Close())
+	assert.Equal(t, flushed[len(flushed)-1], int64(buf.Len()), "Close doesn't flush the bufio.Writer")
+	assert.NoError(t, bw.Flush())
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.Equal(t, 3, len(footer.RowGroups)) {
+		return
+	}
+
+	// each row group's column chunks were written by the Flush for the row group
+	for i, rg := range footer.RowGroups {
+		assert.Equal(t, int64(300), rg.NumRows)
+		for _, ch := range rg.Columns {
+			start := ch.FileOffset
+			end := start + ch.MetaData.TotalCompressedSize
+			assert.True(t, start >= flushed[i], ch.MetaData.PathInSchema)
+			assert.True(t, end <= flushed[i+1], ch.MetaData.PathInSchema)
+		}
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var i int
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		i++
+	}
+
+	assert.NoError(t, r.Error())
+	assert.Equal(t, 900, i)
+}
+
+func TestReset(t *testing.T) {
+	peeps := getPeople(30, 100)
+	other := getPeople(7, 25)
+
+	write := func(w *ParquetWriter, peeps [][]Person) error {
+		for _, rg := range peeps {
+			for _, p := range rg {
+				w.Add(p)
+			}
+			if err := w.Write(); err != nil {
+				return err
+			}
+		}
+		return w.Close()
+	}
+
+	testCases := []struct {
+		name  string
+		opts  []func(*ParquetWriter) error
+		start io.Writer
+		dirty func(t *testing.T, w *ParquetWriter)
+	}{
+		{
+			name:  "after a file",
+			start: &bytes.Buffer{},
+			dirty: func(t *testing.T, w *ParquetWriter) { assert.NoError(t, write(w, other)) },
+		},
+		{
+			name:  "after rows that weren't written",
+			start: &bytes.Buffer{},
+			dirty: func(t *testing.T, w *ParquetWriter) {
+				assert.NoError(t, write(w, other[:1]))
+				for _, p := range other[1] {
+					w.Add(p)
+				}
+			},
+		},
+		{
+			name:  "after an error",
+			opts:  []func(*ParquetWriter) error{MaxRowGroupRows(5)},
+			start: &shortWriter{n: 100},
+			dirty: func(t *testing.T, w *ParquetWriter) {
+				for _, p := range other[0] {
+					w.Add(p)
+				}
+				assert.Error(t, w.Close())
+			},
+		},
+		{
+			name:  "page index and bloom filters",
+			opts:  []func(*ParquetWriter) error{MaxPageSize(10), PageIndex, PageChecksums, BloomFilter("BFF", "code")},
+			start: &bytes.Buffer{},
+			dirty: func(t *testing.T, w *ParquetWriter) { assert.NoError(t, write(w, other)) },
+		},
+		{
+			name:  "sorted",
+			opts:  []func(*ParquetWriter) error{SortedBy("id", false)},
+			start: &bytes.Buffer{},
+			dirty: func(t *testing.T, w *ParquetWriter) { assert.NoError(t, write(w, other)) },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var expected bytes.Buffer
+			w, err := NewParquetWriter(&expected, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if !assert.NoError(t, write(w, peeps)) {
+				return
+			}
+
+			w, err = NewParquetWriter(tc.start, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			tc.dirty(t, w)
+
+			var buf bytes.Buffer
+			if !assert.NoError(t, w.Reset(&buf)) {
+				return
+			}
+			if !assert.NoError(t, write(w, peeps)) {
+				return
+			}
+			assert.Equal(t, expected.Bytes(), buf.Bytes())
+		})
+	}
+}
+
+func TestRotatingWriter(t *testing.T) {
+	peeps := getPeople(25, 25)[0]
+
+	testCases := []struct {
+		name      string
+		rows      int
+		maxRows   int
+		opts      []func(*ParquetWriter) error
+		expected  []int
+		rowGroups int
+	}{
+		{name: "last file is short", rows: 25, maxRows: 10, expected: []int{10, 10, 5}, rowGroups: 1},
+		{name: "last file is full", rows: 20, maxRows: 10, expected: []int{10, 10}, rowGroups: 1},
+		{name: "one file", rows: 5, maxRows: 10, expected: []int{5}, rowGroups: 1},
+		{name: "no rows", rows: 0, maxRows: 10},
+		{name: "max row group rows", rows: 25, maxRows: 10, opts: []func(*ParquetWriter) error{MaxRowGroupRows(4)}, expected: []int{10, 10, 5}, rowGroups: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var files []*closingBuffer
+			next := func(i int) (io.Writer, error) {
+				assert.Equal(t, len(files), i)
+				files = append(files, &closingBuffer{})
+				return files[i], nil
+			}
+
+			w, err := NewRotatingWriter(tc.maxRows, next, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, p := range peeps[:tc.rows] {
+				assert.NoError(t, w.Add(p))
+			}
+			assert.NoError(t, w.Close())
+			assert.Equal(t, len(tc.expected), w.Files())
+			if !assert.Equal(t, len(tc.expected), len(files)) {
+				return
+			}
+
+			var i int
+			for j, f := range files {
+				assert.True(t, f.closed, j)
+				r, err := NewParquetReader(bytes.NewReader(f.Bytes()))
+				if !assert.NoError(t, err, j) {
+					return
+				}
+
+				assert.Equal(t, int64(tc.expected[j]), r.NumRows(), j)
+				if tc.expected[j] == tc.maxRows {
+					assert.Equal(t, tc.rowGroups, r.NumRowGroups(), j)
+				}
+
+				for r.Next() {
+					var p Person
+					r.Scan(&p)
+					assert.Equal(t, peeps[i], p, i)
+					i++
+				}
+				assert.NoError(t, r.Error())
+			}
+			assert.Equal(t, tc.rows, i)
+		})
+	}
+}
+
+func TestRotatingWriterErrors(t *testing.T) {
+	_, err := NewRotatingWriter(0, nil)
+	assert.EqualError(t, err, "invalid max rows 0, it must be at least 1")
+
+	w, err := NewRotatingWriter(2, func(i int) (io.Writer, error) {
+		if i == 1 {
+			return nil, fmt.Errorf("no more files")
+		}
+		return &bytes.Buffer{}, nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p := getPeople(3, 3)[0]
+	assert.NoError(t, w.Add(p[0]))
+	assert.NoError(t, w.Add(p[1]))
+	assert.EqualError(t, w.Add(p[2]), "no more files")
+
+	// a file that can't be finished is still closed, and
+	// the writer doesn't go on to the next file
+	var files []*shortFile
+	w, err = NewRotatingWriter(1, func(i int) (io.Writer, error) {
+		f := &shortFile{shortWriter: shortWriter{n: 4}}
+		files = append(files, f)
+		return f, nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, w.Add(p[0]))
+	assert.Equal(t, io.ErrShortWrite, w.Add(p[1]))
+	assert.Equal(t, io.ErrShortWrite, w.Add(p[2]))
+	assert.Equal(t, io.ErrShortWrite, w.Close())
+	if assert.Len(t, files, 1) {
+		assert.True(t, files[0].closed)
+	}
+}
+
+// closingBuffer is a bytes.Buffer that remembers if it was closed.
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *closingBuffer) Close() error {
+	c.closed = true
+	return nil
+}
+
+// shortWriter fails to write once more than n bytes have been written to it.
+type shortWriter struct {
+	n int
+}
+
+func (s *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > s.n {
+		return 0, io.ErrShortWrite
+	}
+	s.n -= len(p)
+	return len(p), nil
+}
+
+// shortFile is a shortWriter that remembers if it was closed.
+type shortFile struct {
+	shortWriter
+	closed bool
+}
+
+func (s *shortFile) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestReadOneRowGroupAtATime(t *testing.T) {
+	peeps := getPeople(250, 1000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var i int
+	for r.Next() {
+		// only the rows of the current row group that
+		// haven't been scanned yet should be buffered.
+		ids := r.fields["id"].(*Int32Field)
+		assert.Equal(t, 250-i%250, len(ids.vals), i)
+
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		i++
+	}
+
+	assert.NoError(t, r.Error())
+	assert.Equal(t, 1000, i)
+}
+
+func TestMaxRows(t *testing.T) {
+	testCases := []struct {
+		name      string
+		max       int64
+		expected  int
+		rowGroups int
+	}{
+		{name: "in the middle of a row group", max: 7, expected: 7, rowGroups: 2},
+		{name: "end of a row group", max: 5, expected: 5, rowGroups: 1},
+		{name: "first row", max: 1, expected: 1, rowGroups: 1},
+		{name: "more than the file", max: 20, expected: 15, rowGroups: 3},
+		{name: "no limit", max: 0, expected: 15, rowGroups: 3},
+	}
+
+	peeps := getPeople(5, 15)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), MaxRows(tc.max))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, int64(tc.expected), r.Rows())
+			// the first row group has already been read
+			assert.Equal(t, tc.rowGroups-1, len(r.rowGroups))
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, tc.expected, i)
+		})
+	}
+}
+
+func TestWhere(t *testing.T) {
+	peeps := getPeople(5, 15)
+	for _, rg := range peeps {
+		for i := range rg {
+			rg[i].Hungry = rg[i].ID%3 == 0
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	testCases := []struct {
+		name     string
+		where    func(Person) bool
+		expected []int32
+	}{
+		{name: "hungry", where: func(p Person) bool { return p.Hungry }, expected: []int32{0, 3, 6, 9, 12}},
+		{name: "not hungry", where: func(p Person) bool { return !p.Hungry }, expected: []int32{1, 2, 4, 5, 7, 8, 10, 11, 13, 14}},
+		{name: "none", where: func(p Person) bool { return false }},
+		{name: "last row", where: func(p Person) bool { return p.ID == 14 }, expected: []int32{14}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), Where(tc.where))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var ids []int32
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, int(p.ID)), p)
+				ids = append(ids, p.ID)
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestNumRows(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []func(*ParquetReader)
+		rows int64
+	}{
+		{name: "all rows", rows: 15},
+		{name: "max rows", opts: []func(*ParquetReader){MaxRows(7)}, rows: 7},
+		{name: "filtered", opts: []func(*ParquetReader){Filter("id", parquet.Greater, int32(100))}, rows: 0},
+	}
+
+	peeps := getPeople(5, 15)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// the footer's counts don't depend on the options
+			assert.Equal(t, 3, r.NumRowGroups())
+			assert.Equal(t, int64(15), r.NumRows())
+			assert.Equal(t, tc.rows, r.Rows())
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	testCases := []struct {
+		name string
+		kv   map[string]string
+		keys []string
+	}{
+		{name: "none", kv: nil},
+		{
+			name: "two keys",
+			kv: map[string]string{
+				"writer.version": "1.2.3",
+				"pandas":         `{"index_columns": []}`,
+			},
+			keys: []string{"pandas", "writer.version"},
+		},
+		{name: "empty value", kv: map[string]string{"flag": ""}, keys: []string{"flag"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []func(*ParquetWriter) error
+			if tc.kv != nil {
+				opts = append(opts, SetMetadata(tc.kv))
+			}
+
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, p := range getPeople(5, 5)[0] {
+				w.Add(p)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			expected := tc.kv
+			if expected == nil {
+				expected = map[string]string{}
+			}
+			assert.Equal(t, expected, r.Metadata())
+
+			// the keys are in order in the footer
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+			var keys []string
+			for _, kv := range footer.KeyValueMetadata {
+				keys = append(keys, kv.Key)
+			}
+			assert.Equal(t, tc.keys, keys)
+		})
+	}
+}
+
+func TestCreatedBy(t *testing.T) {
+	testCases := []struct {
+		name      string
+		opts      []func(*ParquetWriter) error
+		createdBy string
+	}{
+		{name: "default", createdBy: parquet.DefaultCreatedBy},
+		{name: "set", opts: []func(*ParquetWriter) error{SetCreatedBy("myapp version 1.2.3")}, createdBy: "myapp version 1.2.3"},
+		{name: "empty", opts: []func(*ParquetWriter) error{SetCreatedBy("")}, createdBy: parquet.DefaultCreatedBy},
+	}
+
+	assert.True(t, strings.HasPrefix(parquet.DefaultCreatedBy, "parsyl/parquet version "), parquet.DefaultCreatedBy)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, p := range getPeople(5, 5)[0] {
+				w.Add(p)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.createdBy, r.CreatedBy())
+		})
+	}
+}
+
+func TestReadRowGroup(t *testing.T) {
+	peeps := getPeople(250, 1000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.Equal(t, 4, r.NumRowGroups()) {
+		return
+	}
+
+	// read the row groups backwards to make sure they don't depend on each other
+	groups := make([][]Person, r.NumRowGroups())
+	for i := len(groups) - 1; i >= 0; i-- {
+		groups[i], err = r.ReadRowGroup(i)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	var fromGroups []Person
+	for _, rg := range groups {
+		fromGroups = append(fromGroups, rg...)
+	}
+
+	// reading the row groups doesn't affect the rows read with Next
+	var all []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		all = append(all, p)
+	}
+	assert.NoError(t, r.Error())
+
+	assert.Equal(t, 1000, len(all))
+	assert.Equal(t, all, fromGroups)
+
+	for _, i := range []int{-1, 4} {
+		_, err := r.ReadRowGroup(i)
+		assert.EqualError(t, err, fmt.Sprintf("row group %d is out of range, the file has 4 row groups", i))
+	}
+}
+
+func TestReadParallel(t *testing.T) {
+	peeps := getPeople(100, 1050)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(30))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	read := func(r *ParquetReader) []Person {
+		var out []Person
+		for r.Next() {
+			var p Person
+			r.Scan(&p)
+			out = append(out, p)
+		}
+		assert.NoError(t, r.Error())
+		return out
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	sequential := read(r)
+	if !assert.Equal(t, 1050, len(sequential)) {
+		return
+	}
+
+	testCases := []struct {
+		name string
+		opts []func(*ParquetReader)
+		at   bool
+	}{
+		{name: "1 at a time", opts: []func(*ParquetReader){ReadParallel(1)}},
+		{name: "2 at a time", opts: []func(*ParquetReader){ReadParallel(2)}},
+		{name: "4 at a time", opts: []func(*ParquetReader){ReadParallel(4)}},
+		{name: "more than the row groups", opts: []func(*ParquetReader){ReadParallel(20)}},
+		{name: "reader at", opts: []func(*ParquetReader){ReadParallel(3)}, at: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var r *ParquetReader
+			var err error
+			if tc.at {
+				r, err = NewParquetReaderAt(bytes.NewReader(buf.Bytes()), int64(buf.Len()), tc.opts...)
+			} else {
+				r, err = NewParquetReader(bytes.NewReader(buf.Bytes()), tc.opts...)
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			rows := read(r)
+			assert.Equal(t, sequential, rows)
+			for i, p := range rows {
+				if p.ID != int32(i) {
+					t.Fatalf("row %d has the id %d", i, p.ID)
+				}
+			}
+		})
+	}
+
+	_, err = NewParquetReader(struct{ io.ReadSeeker }{bytes.NewReader(buf.Bytes())}, ReadParallel(2))
+	assert.EqualError(t, err, "ReadParallel needs a reader that is also an io.ReaderAt, struct { io.ReadSeeker } isn't")
+}
+
+func TestReadContext(t *testing.T) {
+	peeps := getPeople(250, 1000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), ReadContext(ctx))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var i int
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		i++
+
+		// cancel in the middle of the second row group
+		if i == 300 {
+			cancel()
+		}
+	}
+
+	// the rest of the second row group was already read, but
+	// the last two row groups weren't
+	assert.Equal(t, 500, i)
+	assert.Equal(t, 2, len(r.rowGroups))
+	assert.Equal(t, context.Canceled, r.Error())
+
+	_, err = r.ReadRowGroup(0)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = NewParquetReader(bytes.NewReader(buf.Bytes()), ReadContext(ctx))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestWriteContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, WriteContext(ctx), MaxRowGroupRows(2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w.Add(Person{Being: Being{ID: 1}})
+	w.Add(Person{Being: Being{ID: 2}})
+	n := buf.Len()
+
+	cancel()
+
+	w.Add(Person{Being: Being{ID: 3}})
+	w.Add(Person{Being: Being{ID: 4}})
+	assert.Equal(t, context.Canceled, w.Write())
+	assert.Equal(t, context.Canceled, w.Close())
+
+	// nothing was written after the first row group
+	assert.Equal(t, n, buf.Len())
+}
+
+// rangeRecorder is an io.ReaderAt that records the
+// byte ranges that are read from it.
+type rangeRecorder struct {
+	r      *bytes.Reader
+	ranges [][2]int64
+}
+
+func (r *rangeRecorder) ReadAt(p []byte, off int64) (int, error) {
+	r.ranges = append(r.ranges, [2]int64{off, off + int64(len(p))})
+	return r.r.ReadAt(p, off)
+}
+
+func TestWriteNonSeekable(t *testing.T) {
+	peeps := getPeople(50, 300)
+	opts := func() []func(*ParquetWriter) error {
+		return []func(*ParquetWriter) error{MaxPageSize(30), PageIndex, BloomFilter("BFF", "code")}
+	}
+
+	write := func(w io.Writer) error {
+		pw, err := NewParquetWriter(w, opts()...)
+		if err != nil {
+			return err
+		}
+		for _, rg := range peeps {
+			for _, p := range rg {
+				pw.Add(p)
+			}
+			if err := pw.Write(); err != nil {
+				return err
+			}
+		}
+		return pw.Close()
+	}
+
+	var expected bytes.Buffer
+	if !assert.NoError(t, write(&expected)) {
+		return
+	}
+
+	testCases := []struct {
+		name  string
+		write func() ([]byte, error)
+	}{
+		{
+			name: "pipe",
+			write: func() ([]byte, error) {
+				pr, pw := io.Pipe()
+				var buf bytes.Buffer
+				done := make(chan error)
+				go func() {
+					_, err := io.Copy(&buf, pr)
+					done <- err
+				}()
+				err := write(pw)
+				pw.CloseWithError(err)
+				if err := <-done; err != nil {
+					return nil, err
+				}
+				return buf.Bytes(), err
+			},
+		},
+		{
+			name: "buffered",
+			write: func() ([]byte, error) {
+				var buf bytes.Buffer
+				bw := bufio.NewWriterSize(&buf, 16)
+				if err := write(bw); err != nil {
+					return nil, err
+				}
+				err := bw.Flush()
+				return buf.Bytes(), err
+			},
+		},
+		{
+			name: "gzip",
+			write: func() ([]byte, error) {
+				var buf bytes.Buffer
+				zw := gzip.NewWriter(&buf)
+				if err := write(zw); err != nil {
+					return nil, err
+				}
+				if err := zw.Close(); err != nil {
+					return nil, err
+				}
+				zr, err := gzip.NewReader(&buf)
+				if err != nil {
+					return nil, err
+				}
+				return ioutil.ReadAll(zr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := tc.write()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, expected.Bytes(), b)
+
+			r, err := NewParquetReader(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Person
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				out = append(out, p)
+			}
+			assert.NoError(t, r.Error())
+
+			var in []Person
+			for _, rg := range peeps {
+				in = append(in, rg...)
+			}
+			assert.Equal(t, in, out)
+		})
+	}
+}
+
+func TestParquetReaderAt(t *testing.T) {
+	testCases := []struct {
+		name      string
+		opts      []func(*ParquetReader)
+		rowGroups int
+	}{
+		{name: "all row groups", rowGroups: 3},
+		{name: "max rows", opts: []func(*ParquetReader){MaxRows(5)}, rowGroups: 1},
+	}
+
+	peeps := getPeople(5, 15)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	data := buf.Bytes()
+	size := int64(len(data))
+	footer, err := parquet.ReadMetaData(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+	metaLen := int64(binary.LittleEndian.Uint32(data[size-8:]))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &rangeRecorder{r: bytes.NewReader(data)}
+			r, err := NewParquetReaderAt(rec, size, tc.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, 5*tc.rowGroups, i)
+
+			// the length of the footer, the footer and then
+			// each column chunk of the row groups that are read
+			expected := [][2]int64{{size - 8, size - 4}, {size - 8 - metaLen, size - 8}}
+			for _, rg := range footer.RowGroups[:tc.rowGroups] {
+				for _, col := range rg.Columns {
+					o := col.MetaData.DataPageOffset
+					if d := col.MetaData.DictionaryPageOffset; d != nil && *d > 0 && *d < o {
+						o = *d
+					}
+					expected = append(expected, [2]int64{o, o + col.MetaData.TotalCompressedSize})
+				}
+			}
+			assert.ElementsMatch(t, expected, rec.ranges)
+		})
+	}
+}
+
+func TestGunzip(t *testing.T) {
+	peeps := getPeople(5, 15)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	gz := func(b []byte) []byte {
+		var out bytes.Buffer
+		zw := gzip.NewWriter(&out)
+		zw.Write(b)
+		zw.Close()
+		return out.Bytes()
+	}
+
+	data := buf.Bytes()
+	bigFooter := append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(bigFooter[len(bigFooter)-8:], uint32(len(data)))
+
+	testCases := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{name: "gzipped parquet", input: gz(data)},
+		{name: "not gzipped", input: data, err: fmt.Errorf("unable to read gzip header: gzip: invalid header")},
+		{name: "not parquet", input: gz([]byte("this isn't a parquet file")), err: fmt.Errorf("the gzipped file isn't a parquet file")},
+		{name: "truncated", input: gz(data[:len(data)-1]), err: fmt.Errorf("the gzipped file isn't a parquet file")},
+		{name: "footer too big", input: gz(bigFooter), err: fmt.Errorf("the footer of the gzipped file is %d bytes but the file is only %d bytes", len(data), len(data))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			br, err := parquet.Gunzip(bytes.NewReader(tc.input))
+			if tc.err != nil {
+				assert.Equal(t, tc.err, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			r, err := NewParquetReader(br)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, i), p, i)
+				i++
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, 15, i)
+
+			ra, err := NewParquetReaderAt(br, br.Size())
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, int64(15), ra.NumRows())
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	peeps := getPeople(250, 1000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	type testCase struct {
+		name      string
+		filters   []func(*ParquetReader)
+		rowGroups []int
+		err       string
+	}
+
+	testCases := []testCase{
+		{
+			name:      "greater",
+			filters:   []func(*ParquetReader){Filter("ID", parquet.Greater, 800)},
+			rowGroups: []int{3},
+		},
+		{
+			name:      "equal",
+			filters:   []func(*ParquetReader){Filter("id", parquet.Equal, int32(300))},
+			rowGroups: []int{1},
+		},
+		{
+			name:      "less",
+			filters:   []func(*ParquetReader){Filter("ID", parquet.Less, 250)},
+			rowGroups: []int{0},
+		},
+		{
+			name:      "less or equal",
+			filters:   []func(*ParquetReader){Filter("ID", parquet.LessOrEqual, 250)},
+			rowGroups: []int{0, 1},
+		},
+		{
+			name:      "greater or equal",
+			filters:   []func(*ParquetReader){Filter("ID", parquet.GreaterOrEqual, 1000)},
+			rowGroups: nil,
+		},
+		{
+			name:      "not equal",
+			filters:   []func(*ParquetReader){Filter("ID", parquet.NotEqual, 300)},
+			rowGroups: []int{0, 1, 2, 3},
+		},
+		{
+			name:      "unsigned",
+			filters:   []func(*ParquetReader){Filter("Birthday", parquet.Greater, 600000)},
+			rowGroups: []int{2, 3},
+		},
+
+		{
+			name: "multiple filters",
+			filters: []func(*ParquetReader){
+				Filter("ID", parquet.GreaterOrEqual, 250),
+				Filter("Happiness", parquet.Less, 1000),
+			},
+			rowGroups: []int{1},
+		},
+
+		{
+			name:    "unknown column",
+			filters: []func(*ParquetReader){Filter("Grumpiness", parquet.Equal, 1)},
+			err:     "unknown filter column: Grumpiness",
+		},
+		{
+			name:    "wrong type",
+			filters: []func(*ParquetReader){Filter("ID", parquet.Equal, "300")},
+			err:     "filter on column id: can't compare a string to a INT32 column",
+		},
+		{
+			name:    "out of range",
+			filters: []func(*ParquetReader){Filter("ID", parquet.Equal, int64(1<<32+5))},
+			err:     "filter on column id: 4294967301 is out of range for a INT32 column",
+		},
+		{
+			name:    "negative unsigned",
+			filters: []func(*ParquetReader){Filter("Birthday", parquet.Greater, -1)},
+			err:     "filter on column birthday: -1 is out of range for a INT32 column",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), tc.filters...)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var expected []Person
+			for _, i := range tc.rowGroups {
+				expected = append(expected, peeps[i]...)
+			}
+
+			var actual []Person
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				actual = append(actual, p)
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, int64(len(expected)), r.Rows())
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestFilterUnordered(t *testing.T) {
+	peeps := getPeople(250, 1000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	data := buf.Bytes()
+	changeFooter := func(f func(*sch.FileMetaData)) []byte {
+		return changeFooter(t, data, f)
+	}
+
+	testCases := []struct {
+		name   string
+		input  []byte
+		filter func(*ParquetReader)
+	}{
+		{
+			// a NaN max is never less than anything, so it
+			// looks like none of the values can be more than 0
+			name: "nan max",
+			input: changeFooter(func(fmd *sch.FileMetaData) {
+				for _, rg := range fmd.RowGroups {
+					for _, ch := range rg.Columns {
+						if ch.MetaData.PathInSchema[0] == "funkiness" {
+							ch.MetaData.Statistics.MaxValue = writeFloat32(float32(math.NaN()))
+						}
+					}
+				}
+			}),
+			filter: Filter("Funkiness", parquet.Greater, 0.0),
+		},
+		{
+			name: "nan min",
+			input: changeFooter(func(fmd *sch.FileMetaData) {
+				for _, rg := range fmd.RowGroups {
+					for _, ch := range rg.Columns {
+						if ch.MetaData.PathInSchema[0] == "funkiness" {
+							ch.MetaData.Statistics.MinValue = writeFloat32(float32(math.NaN()))
+						}
+					}
+				}
+			}),
+			filter: Filter("Funkiness", parquet.Less, 1.0),
+		},
+		{
+			name:   "nan value",
+			input:  data,
+			filter: Filter("Funkiness", parquet.NotEqual, math.NaN()),
+		},
+		{
+			name: "no column orders",
+			input: changeFooter(func(fmd *sch.FileMetaData) {
+				fmd.ColumnOrders = nil
+			}),
+			filter: Filter("ID", parquet.Greater, 800),
+		},
+	}
+
+	var expected []Person
+	for _, rg := range peeps {
+		expected = append(expected, rg...)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(tc.input), tc.filter)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var actual []Person
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				actual = append(actual, p)
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	peeps := getPeople(250, 1000)
+	for _, rg := range peeps {
+		for j := range rg {
+			rg[j].BFF = fmt.Sprintf("user%d@example.com", rg[j].ID)
+			rg[j].Payload = []byte(fmt.Sprintf("payload-%d", rand.Int63()))
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100), BloomFilter("BFF", "code", "payload"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the offset of the previous row group's last bloom filter
+	var lastBloom int64
+	for _, rg := range footer.RowGroups {
+		assert.True(t, rg.Columns[0].FileOffset > lastBloom)
+
+		// the bloom filters come after all of the row group's column chunks
+		var end int64
+		for _, ch := range rg.Columns {
+			if e := ch.FileOffset + ch.MetaData.TotalCompressedSize; e > end {
+				end = e
+			}
+		}
+
+		for _, ch := range rg.Columns {
+			col := strings.Join(ch.MetaData.PathInSchema, ".")
+			switch col {
+			case "bff", "code", "payload":
+				if assert.NotNil(t, ch.MetaData.BloomFilterOffset, col) {
+					assert.True(t, *ch.MetaData.BloomFilterOffset >= end, col)
+					if o := *ch.MetaData.BloomFilterOffset; o > lastBloom {
+						lastBloom = o
+					}
+				}
+			default:
+				assert.Nil(t, ch.MetaData.BloomFilterOffset, col)
+			}
+		}
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// bloom filters don't have false negatives
+	for _, rg := range peeps {
+		for _, p := range rg {
+			ok, err := r.MayContain("bff", p.BFF)
+			assert.NoError(t, err)
+			assert.True(t, ok, p.BFF)
+
+			ok, err = r.MayContain("Payload", p.Payload)
+			assert.NoError(t, err)
+			assert.True(t, ok, p.Payload)
+
+			if p.Code != nil {
+				ok, err = r.MayContain("code", *p.Code)
+				assert.NoError(t, err)
+				assert.True(t, ok, *p.Code)
+			}
+		}
+	}
+
+	// each of the 4 row groups has about a 1% chance of a false positive
+	var falsePositives int
+	for i := 0; i < 1000; i++ {
+		ok, err := r.MayContain("bff", fmt.Sprintf("nobody%d@example.com", i))
+		assert.NoError(t, err)
+		if ok {
+			falsePositives++
+		}
+	}
+	assert.True(t, falsePositives < 100, falsePositives)
+
+	// a column without bloom filters may contain anything
+	ok, err := r.MayContain("tags", "nope")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = r.MayContain("nope", "nope")
+	assert.EqualError(t, err, "unknown column: nope")
+
+	_, err = r.MayContain("bff", 3)
+	assert.EqualError(t, err, "invalid value 3 (int), it must be a string or a []byte")
+
+	// checking the bloom filters doesn't affect reading rows
+	var i int
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		i++
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, 1000, i)
+
+	_, err = NewParquetWriter(&buf, BloomFilter("happiness"))
+	assert.EqualError(t, err, "column happiness can't have a bloom filter, only string and []byte columns can")
+
+	_, err = NewParquetWriter(&buf, BloomFilter("nope"))
+	assert.EqualError(t, err, "unknown column: nope")
+}
+
+func TestPageIndex(t *testing.T) {
+	peeps := getPeople(500, 1000)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100), PageIndex)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	r := bytes.NewReader(buf.Bytes())
+	footer, err := parquet.ReadMetaData(r)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for i, rg := range footer.RowGroups {
+		// the page indexes come after all of the column chunks
+		var end int64
+		for _, ch := range rg.Columns {
+			if e := ch.FileOffset + ch.MetaData.TotalCompressedSize; e > end {
+				end = e
+			}
+		}
+
+		for _, ch := range rg.Columns {
+			col := strings.Join(ch.MetaData.PathInSchema, ".")
+			oi, err := parquet.ReadOffsetIndex(r, ch)
+			if !assert.NoError(t, err, col) || !assert.NotNil(t, oi, col) {
+				return
+			}
+
+			assert.True(t, *ch.OffsetIndexOffset > end, col)
+			if !assert.Len(t, oi.PageLocations, 5, col) {
+				continue
+			}
+
+			var size int64
+			for j, loc := range oi.PageLocations {
+				assert.Equal(t, int64(j*100), loc.FirstRowIndex, col)
+				ph, err := parquet.PageHeadersAtOffset(r, loc.Offset, 1)
+				if assert.NoError(t, err, col) {
+					assert.Equal(t, int32(100), ph[0].DataPageHeader.NumValues, col)
+				}
+				size += int64(loc.CompressedPageSize)
+			}
+			assert.Equal(t, ch.MetaData.DataPageOffset, oi.PageLocations[0].Offset, col)
+			assert.Equal(t, ch.FileOffset+ch.MetaData.TotalCompressedSize, oi.PageLocations[4].Offset+int64(oi.PageLocations[4].CompressedPageSize), col)
+			assert.True(t, size <= ch.MetaData.TotalCompressedSize, col)
+
+			ci, err := parquet.ReadColumnIndex(r, ch)
+			if !assert.NoError(t, err, col) {
+				return
+			}
+
+			switch col {
+			case "id":
+				if assert.NotNil(t, ci) {
+					assert.Equal(t, sch.BoundaryOrder_ASCENDING, ci.BoundaryOrder)
+					assert.Equal(t, []bool{false, false, false, false, false}, ci.NullPages)
+					assert.Equal(t, []int64{0, 0, 0, 0, 0}, ci.NullCounts)
+					for j := range oi.PageLocations {
+						id := int32(i*500 + j*100)
+						assert.Equal(t, int32Bytes(id), ci.MinValues[j])
+						assert.Equal(t, int32Bytes(id+99), ci.MaxValues[j])
+					}
+				}
+				assert.True(t, *ch.ColumnIndexOffset > end)
+				assert.True(t, *ch.ColumnIndexOffset < *ch.OffsetIndexOffset)
+			case "sadness":
+				// every 3rd person is sad
+				if assert.NotNil(t, ci) {
+					assert.Equal(t, sch.BoundaryOrder_ASCENDING, ci.BoundaryOrder)
+					for j := range oi.PageLocations {
+						var nulls int64
+						for k := i*500 + j*100; k < i*500+(j+1)*100; k++ {
+							if k%3 != 0 {
+								nulls++
+							}
+						}
+						assert.Equal(t, nulls, ci.NullCounts[j])
+					}
+				}
+			case "keen":
+				// bool pages don't have a min and max
+				assert.Nil(t, ci)
+				assert.Nil(t, ch.ColumnIndexOffset)
+			}
+		}
+	}
+
+	var all []Person
+	for _, rg := range peeps {
+		all = append(all, rg...)
+	}
+
+	testCases := []struct {
+		name     string
+		filters  []func(*ParquetReader)
+		from, to int
+	}{
+		{
+			name: "no filters",
+			to:   1000,
+		},
+		{
+			name:    "equal",
+			filters: []func(*ParquetReader){Filter("ID", parquet.Equal, 123)},
+			from:    100,
+			to:      200,
+		},
+		{
+			name:    "greater or equal",
+			filters: []func(*ParquetReader){Filter("ID", parquet.GreaterOrEqual, 750)},
+			from:    700,
+			to:      1000,
+		},
+		{
+			name: "multiple filters",
+			filters: []func(*ParquetReader){
+				Filter("ID", parquet.GreaterOrEqual, 250),
+				Filter("Happiness", parquet.Less, 700),
+			},
+			from: 200,
+			to:   400,
+		},
+		{
+			name:    "optional column",
+			filters: []func(*ParquetReader){Filter("Sadness", parquet.Greater, 905)},
+			from:    900,
+			to:      1000,
+		},
+		{
+			name:    "no pages",
+			filters: []func(*ParquetReader){Filter("ID", parquet.Equal, 450), Filter("Happiness", parquet.Greater, 1000)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), tc.filters...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			expected := all[tc.from:tc.to]
+			var actual []Person
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				actual = append(actual, p)
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, int64(len(expected)), r.Rows())
+			if len(expected) == 0 {
+				assert.Empty(t, actual)
+				return
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func int32Bytes(i int32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(i))
+	return b
+}
+
+func TestColumnChunkOrder(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cols  []string
+		first []string
+		err   string
+	}{
+		{name: "schema order", first: []string{"id", "name"}},
+		{name: "one column", cols: []string{"hobby.skills.name"}, first: []string{"hobby.skills.name", "id"}},
+		{name: "field names", cols: []string{"Home.Geo.Lat", "Age"}, first: []string{"home.geo.lat", "age", "id"}},
+		{name: "given twice", cols: []string{"age", "Age"}, first: []string{"age", "id"}},
+		{name: "unknown column", cols: []string{"nope"}, err: "unknown column: nope"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, ColumnOrder(tc.cols...))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			peeps := getPeople(10, 20)
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) || !assert.Len(t, footer.RowGroups, 2) {
+				return
+			}
+
+			var schema []string
+			for _, f := range Fields(compressionUnknown, 0) {
+				schema = append(schema, f.Name())
+			}
+
+			// the first row group starts after PAR1 and the second
+			// starts where the last column chunk of the first ends
+			start := int64(4)
+			for _, rg := range footer.RowGroups {
+				// the footer lists the columns in the schema's order...
+				var cols []string
+				for _, ch := range rg.Columns {
+					cols = append(cols, strings.Join(ch.MetaData.PathInSchema, "."))
+				}
+				assert.Equal(t, schema, cols)
+
+				// ...but they're written in the requested order
+				chunks := append([]*sch.ColumnChunk{}, rg.Columns...)
+				sort.Slice(chunks, func(i, j int) bool { return chunks[i].FileOffset < chunks[j].FileOffset })
+				for i, col := range tc.first {
+					assert.Equal(t, col, strings.Join(chunks[i].MetaData.PathInSchema, "."))
+				}
+
+				assert.Equal(t, start, chunks[0].FileOffset)
+				for i, ch := range chunks {
+					assert.Equal(t, start, ch.FileOffset, i)
+					start += ch.MetaData.TotalCompressedSize
+				}
+			}
+
+			assert.NoError(t, parquet.ValidateFileChecksums(bytes.NewReader(buf.Bytes()), int64(buf.Len())))
+
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, peeps[i/10][i%10], p)
+				i++
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, 20, i)
+		})
+	}
+}
+
+// The footer works out where the column chunks start from their sizes
+// when it can't count what was written, which has to be in the order
+// that they were written.
+func TestColumnChunkOrderFooter(t *testing.T) {
+	meta := parquet.New(
+		parquet.Field{Name: "a", Path: []string{"a"}, Types: []int{0}, Type: Int32Type, RepetitionType: parquet.RepetitionRequired},
+		parquet.Field{Name: "b", Path: []string{"b"}, Types: []int{0}, Type: Int32Type, RepetitionType: parquet.RepetitionRequired},
+	)
+	for i := 0; i < 3; i++ {
+		meta.NextDoc()
+	}
+
+	buf := bytes.NewBufferString("PAR1")
+	b := parquet.NewRequiredField([]string{"b"}, parquet.RequiredFieldUncompressed)
+	if !assert.NoError(t, b.DoWrite(buf, meta, []byte{1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0}, 3, newInt32stats())) {
+		return
+	}
+	bLen := int64(buf.Len() - 4)
+
+	a := parquet.NewRequiredField([]string{"a"}, parquet.RequiredFieldUncompressed)
+	if !assert.NoError(t, a.DoWrite(buf, meta, []byte{4, 0, 0, 0, 5, 0, 0, 0, 6, 0, 0, 0}, 3, newInt32stats())) {
+		return
+	}
+
+	if !assert.NoError(t, meta.Footer(buf)) {
+		return
+	}
+	buf.WriteString("PAR1")
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) || !assert.Len(t, footer.RowGroups, 1) {
+		return
+	}
+
+	cols := footer.RowGroups[0].Columns
+	if !assert.Len(t, cols, 2) {
+		return
+	}
+	assert.Equal(t, []string{"a"}, cols[0].MetaData.PathInSchema)
+	assert.Equal(t, 4+bLen, cols[0].FileOffset)
+	assert.Equal(t, []string{"b"}, cols[1].MetaData.PathInSchema)
+	assert.Equal(t, int64(4), cols[1].FileOffset)
+}
+
+func TestPageChecksums(t *testing.T) {
+	testCases := []struct {
+		name     string
+		writer   []func(*ParquetWriter) error
+		reader   []func(*ParquetReader)
+		corrupt  bool
+		checksum bool
+		err      string
+	}{
+		{name: "verified", writer: []func(*ParquetWriter) error{PageChecksums}, reader: []func(*ParquetReader){VerifyChecksums}, checksum: true},
+		{name: "not verified", writer: []func(*ParquetWriter) error{PageChecksums}, checksum: true},
+		{name: "no checksums", reader: []func(*ParquetReader){VerifyChecksums}},
+		{name: "corrupt and not verified", writer: []func(*ParquetWriter) error{PageChecksums}, corrupt: true, checksum: true},
+		{name: "corrupt and no checksums", reader: []func(*ParquetReader){VerifyChecksums}, corrupt: true},
+		{
+			name:     "corrupt and verified",
+			writer:   []func(*ParquetWriter) error{PageChecksums},
+			reader:   []func(*ParquetReader){VerifyChecksums},
+			corrupt:  true,
+			checksum: true,
+			err:      "unable to read field id, err: page checksum mismatch",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			// the pages aren't compressed so that a corrupt
+			// page can still be read when it isn't verified
+			w, err := NewParquetWriter(&buf, append(tc.writer, Uncompressed)...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			peeps := getPeople(5, 10)
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+
+			b := buf.Bytes()
+			footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			pageHeaders, err := parquet.PageHeaders(footer, bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, ph := range pageHeaders {
+				assert.Equal(t, tc.checksum, ph.Crc != nil)
+			}
+
+			if tc.corrupt {
+				// the last byte of the first column chunk (the id column)
+				// is the last byte of the values of its last page
+				md := footer.RowGroups[0].Columns[0].MetaData
+				b[md.DataPageOffset+md.TotalCompressedSize-1] ^= 0xff
+			}
+
+			r, err := NewParquetReader(bytes.NewReader(b), tc.reader...)
+			if tc.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				expected := *getExpected(peeps, i)
+				// the corrupt byte is in the id of the
+				// last row of the first row group
+				if tc.corrupt && i == 4 {
+					assert.NotEqual(t, expected.ID, p.ID)
+					p.ID = expected.ID
+				}
+				assert.Equal(t, expected, p, i)
+				i++
+			}
+
+			assert.NoError(t, r.Error())
+			assert.Equal(t, 10, i)
+		})
+	}
+}
+
+func TestSkipErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		// truncated is the row group whose id column is cut short
+		truncated int
+		writer    []func(*ParquetWriter) error
+		reader    []func(*ParquetReader)
+		rows      []int
+		errors    []string
+		err       string
+	}{
+		{
+			name:      "not skipped",
+			truncated: 1,
+			rows:      []int{0, 1, 2, 3, 4},
+			err:       "unable to read field id",
+		},
+		{
+			name:      "first row group not skipped",
+			truncated: 0,
+			err:       "unable to read field id",
+		},
+		{
+			name:      "skipped",
+			truncated: 1,
+			reader:    []func(*ParquetReader){SkipErrors},
+			rows:      []int{0, 1, 2, 3, 4, 10, 11, 12, 13, 14},
+			errors:    []string{"unable to read field id"},
+		},
+		{
+			name:      "first row group skipped",
+			truncated: 0,
+			reader:    []func(*ParquetReader){SkipErrors},
+			rows:      []int{5, 6, 7, 8, 9, 10, 11, 12, 13, 14},
+			errors:    []string{"unable to read field id"},
+		},
+		{
+			name:      "last row group skipped",
+			truncated: 2,
+			reader:    []func(*ParquetReader){SkipErrors},
+			rows:      []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			errors:    []string{"unable to read field id"},
+		},
+		{
+			name:      "checksum skipped",
+			truncated: 1,
+			writer:    []func(*ParquetWriter) error{PageChecksums},
+			reader:    []func(*ParquetReader){SkipErrors, VerifyChecksums},
+			rows:      []int{0, 1, 2, 3, 4, 10, 11, 12, 13, 14},
+			errors:    []string{"page checksum mismatch"},
+		},
+		{
+			name:      "skipped in parallel",
+			truncated: 1,
+			reader:    []func(*ParquetReader){SkipErrors, ReadParallel(2)},
+			rows:      []int{0, 1, 2, 3, 4, 10, 11, 12, 13, 14},
+			errors:    []string{"unable to read field id"},
+		},
+		{
+			name:      "not skipped in parallel",
+			truncated: 1,
+			reader:    []func(*ParquetReader){ReadParallel(2)},
+			err:       "unable to read field id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, append(tc.writer, Snappy)...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			peeps := getPeople(5, 15)
+			for _, rg := range peeps {
+				for _, p := range rg {
+					w.Add(p)
+				}
+				assert.NoError(t, w.Write())
+			}
+			assert.NoError(t, w.Close())
+
+			b := buf.Bytes()
+			footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// the second half of the id column chunk is zeroed
+			// out, as if the row group's bytes had been cut off
+			md := footer.RowGroups[tc.truncated].Columns[0].MetaData
+			end := md.DataPageOffset + md.TotalCompressedSize
+			for i := end - md.TotalCompressedSize/2; i < end; i++ {
+				b[i] = 0
+			}
+
+			r, err := NewParquetReader(bytes.NewReader(b), tc.reader...)
+			if err != nil {
+				if assert.NotEqual(t, "", tc.err, err) {
+					assert.Contains(t, err.Error(), tc.err)
+				}
+				return
+			}
+
+			var rows []int
+			var i int
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, *getExpected(peeps, tc.rows[i]), p, i)
+				rows = append(rows, tc.rows[i])
+				i++
+			}
+
+			if tc.err != "" {
+				if assert.Error(t, r.Error()) {
+					assert.Contains(t, r.Error().Error(), tc.err)
+				}
+			} else {
+				assert.NoError(t, r.Error())
+				assert.Equal(t, int64(len(tc.rows)), r.Rows())
+			}
+
+			assert.Equal(t, tc.rows, rows)
+			if assert.Len(t, r.Errors(), len(tc.errors)) {
+				for i, e := range tc.errors {
+					assert.Contains(t, r.Errors()[i].Error(), e)
+				}
+			}
+		})
+	}
+}
+
+func TestSortedBy(t *testing.T) {
+	peeps := getPeople(250, 1000)
+	for _, rg := range peeps {
+		less, err := Less("Sadness", true)
+		if !assert.NoError(t, err) {
+			return
+		}
+		sort.SliceStable(rg, func(i, j int) bool { return less(rg[i], rg[j]) })
+	}
+
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxPageSize(100), SortedBy("Sadness", true), SortedBy("id", false))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range peeps {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.Len(t, footer.RowGroups, 4) {
+		return
+	}
+
+	for _, rg := range footer.RowGroups {
+		if assert.Len(t, rg.SortingColumns, 2) {
+			assert.Equal(t, []string{"sadness"}, rg.Columns[rg.SortingColumns[0].ColumnIdx].MetaData.PathInSchema)
+			assert.Equal(t, &sch.SortingColumn{ColumnIdx: 4, Descending: true, NullsFirst: false}, rg.SortingColumns[0])
+			assert.Equal(t, &sch.SortingColumn{ColumnIdx: 0, Descending: false, NullsFirst: true}, rg.SortingColumns[1])
+		}
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the sad people come first (the saddest first) and then everyone else
+	var i int
+	var prev *Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		assert.Equal(t, *getExpected(peeps, i), p, i)
+		if i%250 > 0 && p.Sadness != nil {
+			assert.True(t, prev.Sadness != nil && *prev.Sadness > *p.Sadness, i)
+		}
+		prev = &p
+		i++
+	}
+	assert.NoError(t, r.Error())
+	assert.Equal(t, 1000, i)
+
+	testCases := []struct {
+		name       string
+		column     string
+		descending bool
+		a, b       Person
+		less       bool
+		err        string
+	}{
+		{name: "required", column: "ID", a: Person{Being: Being{ID: 1}}, b: Person{Being: Being{ID: 2}}, less: true},
+		{name: "descending", column: "id", descending: true, a: Person{Being: Being{ID: 1}}, b: Person{Being: Being{ID: 2}}},
+		{name: "nulls first", column: "Age", a: Person{}, b: Person{Being: Being{Age: pint32(1)}}, less: true},
+		{name: "nulls last", column: "Age", descending: true, a: Person{}, b: Person{Being: Being{Age: pint32(1)}}},
+		{name: "nested", column: "Hobby.Name", a: Person{Hobby: &Hobby{Name: "a"}}, b: Person{Hobby: &Hobby{Name: "b"}}, less: true},
+		{name: "nil struct", column: "hobby.name", a: Person{Hobby: &Hobby{Name: "a"}}, b: Person{}},
+		{name: "bool", column: "Hungry", a: Person{Hungry: false}, b: Person{Hungry: true}, less: true},
+		{name: "time", column: "Created", a: Person{Created: time.Unix(1, 0)}, b: Person{Created: time.Unix(2, 0)}, less: true},
+		{name: "repeated", column: "Tags", err: "column tags is repeated, rows can't be sorted by it"},
+		{name: "unknown", column: "nope", err: "unknown column: nope"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			less, err := Less(tc.column, tc.descending)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				_, err = NewParquetWriter(&buf, SortedBy(tc.column, tc.descending))
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.less, less(tc.a, tc.b))
+				assert.False(t, less(tc.a, tc.a))
+			}
+		})
+	}
+}
+
+func TestToMap(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	testCases := []struct {
+		name    string
+		p       Person
+		want    map[string]interface{}
+		missing []string
+	}{
+		{
+			name:    "embedded",
+			p:       Person{Being: Being{ID: 1, Name: "a", Age: pint32(20)}},
+			want:    map[string]interface{}{"id": int32(1), "name": "a", "age": int32(20)},
+			missing: []string{"ID", "Being.ID", "hobby.name", "friends.id", "sadness"},
+		},
+		{
+			name: "nested struct",
+			p: Person{Hobby: &Hobby{
+				Name:   "napping",
+				Skills: []Skill{{Name: "yawning", Difficulty: "easy"}, {Name: "snoring", Difficulty: "hard"}},
+			}},
+			want: map[string]interface{}{
+				"hobby.name":              "napping",
+				"hobby.skills.name":       []interface{}{"yawning", "snoring"},
+				"hobby.skills.difficulty": []interface{}{"easy", "hard"},
+			},
+			missing: []string{"hobby.difficulty", "home.street"},
+		},
+		{
+			name:    "nil pointer in nested struct",
+			p:       Person{Home: &Address{Street: "Main"}},
+			want:    map[string]interface{}{"home.street": "Main"},
+			missing: []string{"home.geo.lat", "home.geo.lon"},
+		},
+		{
+			name: "repeated",
+			p:    Person{Friends: []Being{{ID: 2, Age: pint32(30)}, {ID: 3}}, Tags: []string{"x", "y"}},
+			want: map[string]interface{}{
+				"friends.id":  []interface{}{int32(2), int32(3)},
+				"friends.age": []interface{}{int32(30), nil},
+				"tags":        []interface{}{"x", "y"},
+			},
+			missing: []string{"scores"},
+		},
+
+		{
+			name:    "time",
+			p:       Person{Created: created},
+			want:    map[string]interface{}{"created": created},
+			missing: []string{"deleted", "graduated"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := ToMap(tc.p)
+			for k, v := range tc.want {
+				assert.Equal(t, v, m[k], k)
+			}
+			for _, k := range tc.missing {
+				_, ok := m[k]
+				assert.False(t, ok, k)
+			}
+
+			// a row goes to JSON and back
+			buf, err := json.Marshal(m)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			d := json.NewDecoder(bytes.NewReader(buf))
+			d.UseNumber()
+			var fromJSON map[string]interface{}
+			if !assert.NoError(t, d.Decode(&fromJSON)) {
+				return
+			}
+
+			p, err := FromMap(fromJSON)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.p, p)
+			}
+
+			p, err = FromMap(m)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.p, p)
+			}
+		})
+	}
+
+	for i := 0; i < 100; i++ {
+		p := newPerson(i)
+		p.Checksum = &[8]byte{1, 2, byte(i)}
+		p.Payload = []byte{byte(i)}
+
+		buf, err := json.Marshal(ToMap(p))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		d := json.NewDecoder(bytes.NewReader(buf))
+		d.UseNumber()
+		var m map[string]interface{}
+		if !assert.NoError(t, d.Decode(&m)) {
+			return
+		}
+
+		out, err := FromMap(m)
+		if assert.NoError(t, err) {
+			assert.Equal(t, p, out, i)
+		}
+	}
+
+	_, err := FromMap(map[string]interface{}{"level": 300})
+	assert.EqualError(t, err, "column level: can't set a int8 to 300 (int)")
+}
+
+func TestEqual(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	testCases := []struct {
+		name     string
+		a        Person
+		b        Person
+		expected bool
+	}{
+		{name: "zero values", expected: true},
+		{name: "same values", a: Person{Being: Being{ID: 1, Age: pint32(20)}}, b: Person{Being: Being{ID: 1, Age: pint32(20)}}, expected: true},
+		{name: "different values", a: Person{Being: Being{ID: 1}}, b: Person{Being: Being{ID: 2}}},
+		{name: "nil and pointer to zero", a: Person{}, b: Person{Being: Being{Age: pint32(0)}}},
+		{name: "pointer to zero and nil", a: Person{Being: Being{Age: pint32(0)}}, b: Person{}},
+		{name: "nil and empty slice", a: Person{Tags: []string{}, Friends: []Being{}}, b: Person{}, expected: true},
+		{name: "different slices", a: Person{Tags: []string{"x"}}, b: Person{Tags: []string{"x", "y"}}},
+		{name: "nil in repeated struct", a: Person{Friends: []Being{{ID: 2}}}, b: Person{Friends: []Being{{ID: 2, Age: pint32(0)}}}},
+		{name: "nil nested struct", a: Person{Home: &Address{}}, b: Person{}},
+		{name: "nil struct under a nested struct", a: Person{Home: &Address{Street: "Main"}}, b: Person{Home: &Address{Street: "Main", Geo: &Geo{}}}},
+		{name: "same instant", a: Person{Created: created}, b: Person{Created: created.In(time.FixedZone("x", 3600))}, expected: true},
+		{name: "different instant", a: Person{Created: created}, b: Person{Created: created.Add(time.Nanosecond)}},
+		{name: "same bytes", a: Person{Payload: []byte("x")}, b: Person{Payload: []byte("x")}, expected: true},
+		{name: "ignored field", a: Person{Secret: "x"}, b: Person{Secret: "y"}, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.a.Equal(tc.b))
+		})
+	}
+}
+
+func TestFileWriter(t *testing.T) {
+	fields := []parquet.Field{
+		{Name: "id", Path: []string{"id"}, Types: []int{0}, Type: Int64Type, RepetitionType: parquet.RepetitionRequired},
+		{Name: "name", Path: []string{"name"}, Types: []int{1}, Type: StringType, RepetitionType: parquet.RepetitionOptional},
+		{Name: "score", Path: []string{"score"}, Types: []int{2}, Type: Float64Type, RepetitionType: parquet.RepetitionRequired},
+	}
+
+	var buf bytes.Buffer
+	w, err := parquet.NewFileWriter(&buf, fields...)
+	assert.NoError(t, err)
+
+	id, err := w.Column("id")
+	assert.NoError(t, err)
+	name, err := w.Column("name")
+	assert.NoError(t, err)
+	score, err := w.Column("score")
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, id.WriteInt64(int64(i), 0))
+		if i == 1 {
+			assert.NoError(t, score.WriteFloat64(math.NaN(), 0))
+		} else {
+			assert.NoError(t, score.WriteFloat64(float64(i), 0))
+		}
+		if i%2 == 0 {
+			assert.NoError(t, name.WriteString(fmt.Sprintf("name-%d", i), 0))
+		} else {
+			assert.NoError(t, name.WriteNull(0, 0))
+		}
+
+		if i == 2 {
+			assert.NoError(t, w.WriteRowGroup())
+		}
+	}
+	assert.NoError(t, w.Close())
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), footer.NumRows)
+	assert.Equal(t, 2, len(footer.RowGroups))
+	assert.True(t, footer.RowGroups[0].Columns[0].MetaData.Statistics.IsSetNullCount())
+	assert.Equal(t, int64(0), footer.RowGroups[0].Columns[0].MetaData.Statistics.GetNullCount())
+	assert.Equal(t, int64(1), footer.RowGroups[0].Columns[1].MetaData.Statistics.GetNullCount())
+	assert.Equal(t, writeFloat64(0), footer.RowGroups[0].Columns[2].MetaData.Statistics.MinValue)
+	assert.Equal(t, writeFloat64(2), footer.RowGroups[0].Columns[2].MetaData.Statistics.MaxValue)
+
+	r, err := parquet.NewFileReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "name", "score"}, r.Columns())
+	assert.Equal(t, 2, r.RowGroups())
+
+	testCases := []struct {
+		rowGroup int
+		ids      []int64
+		names    []string
+		defs     []uint8
+	}{
+		{rowGroup: 0, ids: []int64{0, 1, 2}, names: []string{"name-0", "name-2"}, defs: []uint8{1, 0, 1}},
+		{rowGroup: 1, ids: []int64{3, 4}, names: []string{"name-4"}, defs: []uint8{0, 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("row group %d", tc.rowGroup), func(t *testing.T) {
+			c, err := r.Column(tc.rowGroup, "id")
+			assert.NoError(t, err)
+			ids, err := c.ReadInt64()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ids, ids)
+
+			c, err = r.Column(tc.rowGroup, "name")
+			assert.NoError(t, err)
+			names, err := c.ReadString()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.names, names)
+			defs, reps := c.Levels()
+			assert.Equal(t, tc.defs, defs)
+			assert.Nil(t, reps)
+		})
+	}
+}
+
+func TestFileWriterErrors(t *testing.T) {
+	fields := []parquet.Field{
+		{Name: "id", Path: []string{"id"}, Types: []int{0}, Type: Int64Type, RepetitionType: parquet.RepetitionRequired},
+		{Name: "tags", Path: []string{"tags"}, Types: []int{2}, Type: StringType, RepetitionType: parquet.RepetitionRepeated},
+	}
+
+	testCases := []struct {
+		name     string
+		write    func(id, tags parquet.ColumnWriter) error
+		expected string
+	}{
+		{
+			name:     "wrong type",
+			write:    func(id, tags parquet.ColumnWriter) error { return id.WriteInt32(1, 0) },
+			expected: "column id is INT64, it can't have a int32 value",
+		},
+		{
+			name:     "null in a required column",
+			write:    func(id, tags parquet.ColumnWriter) error { return id.WriteNull(0, 0) },
+			expected: "column id is required, it can't have a null",
+		},
+		{
+			name:     "null at the max definition level",
+			write:    func(id, tags parquet.ColumnWriter) error { return tags.WriteNull(1, 0) },
+			expected: "column tags has a max definition level of 1, a null can't be at 1",
+		},
+		{
+			name:     "repetition level is too high",
+			write:    func(id, tags parquet.ColumnWriter) error { return tags.WriteString("a", 2) },
+			expected: "column tags has a max repetition level of 1, a value can't be at 2",
+		},
+		{
+			name: "different number of rows",
+			write: func(id, tags parquet.ColumnWriter) error {
+				id.WriteInt64(1, 0)
+				tags.WriteString("a", 0)
+				tags.WriteString("b", 1)
+				tags.WriteNull(0, 0)
+				return nil
+			},
+			expected: "column tags has 2 rows and column id has 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := parquet.NewFileWriter(&bytes.Buffer{}, fields...)
+			assert.NoError(t, err)
+			id, err := w.Column("id")
+			assert.NoError(t, err)
+			tags, err := w.Column("tags")
+			assert.NoError(t, err)
 
-				err = w.Close()
-				assert.Nil(t, err, tc.name)
+			err = tc.write(id, tags)
+			if err == nil {
+				err = w.WriteRowGroup()
+			}
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
 
-				r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+func TestRequiredLevels(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "data page"},
+		{name: "data page v2", opts: []func(*ParquetWriter) error{DataPageV2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, append(tc.opts, Uncompressed, MaxDictionarySize(0), MaxPageSize(20))...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, p := range getPeople(50, 50)[0] {
+				w.Add(p)
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// id is an INT32 so, without any levels, each
+			// page is 4 bytes for each of its values
+			var pages int
+			for _, ch := range footer.RowGroups[0].Columns {
+				if strings.Join(ch.MetaData.PathInSchema, ".") != "id" {
+					continue
+				}
+
+				phs, err := parquet.PageHeadersAtOffset(bytes.NewReader(buf.Bytes()), ch.MetaData.DataPageOffset, ch.MetaData.NumValues)
 				if !assert.NoError(t, err) {
 					return
 				}
 
-				expected := tc.expected
-				if expected == nil {
-					expected = tc.input
+				for _, ph := range phs {
+					var n int32
+					if h := ph.DataPageHeader; h != nil {
+						n = h.NumValues
+					}
+					if h := ph.DataPageHeaderV2; h != nil {
+						n = h.NumValues
+						assert.Equal(t, int32(0), h.DefinitionLevelsByteLength)
+						assert.Equal(t, int32(0), h.RepetitionLevelsByteLength)
+					}
+					assert.Equal(t, 4*n, ph.UncompressedPageSize)
+					pages++
 				}
+			}
+			assert.True(t, pages > 1, pages)
 
-				if !assert.Equal(t, getLen(expected), int(r.Rows()), tc.name) {
-					return
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var i int32
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				assert.Equal(t, i, p.ID)
+				i++
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, int32(50), i)
+		})
+	}
+
+	// an OptionalField whose types are all required doesn't write
+	// or read definition levels either
+	t.Run("optional field", func(t *testing.T) {
+		meta := parquet.New(parquet.Field{Name: "id", Path: []string{"id"}, Types: []int{0}, Type: Int32Type, RepetitionType: parquet.RepetitionRequired})
+		f := parquet.NewOptionalField([]string{"id"}, []int{0}, parquet.OptionalFieldUncompressed)
+		f.Defs = []uint8{0, 0, 0}
+		for i := 0; i < 3; i++ {
+			meta.NextDoc()
+		}
+
+		buf := bytes.NewBufferString("PAR1")
+		vals := []byte{1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0}
+		if !assert.NoError(t, f.DoWrite(buf, meta, vals, 3, newInt32stats())) {
+			return
+		}
+
+		ph, err := parquet.PageHeader(bytes.NewReader(buf.Bytes()[4:]))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, int32(len(vals)), ph.UncompressedPageSize)
+
+		r := bytes.NewReader(buf.Bytes())
+		r.Seek(4, io.SeekStart)
+		rf := parquet.NewOptionalField([]string{"id"}, []int{0})
+		rd, sizes, err := rf.DoRead(r, parquet.Page{N: 3, Offset: 4, DataOffset: 4, Size: buf.Len() - 4, Type: sch.Type_INT32})
+		if !assert.NoError(t, err) {
+			return
+		}
+		out, err := ioutil.ReadAll(rd)
+		assert.NoError(t, err)
+		assert.Equal(t, vals, out)
+		assert.Equal(t, []int{3}, sizes)
+		assert.Equal(t, []uint8{0, 0, 0}, rf.Defs)
+	})
+}
+
+func TestLevelEncodings(t *testing.T) {
+	testCases := []struct {
+		name   string
+		opts   []func(*parquet.OptionalField)
+		enc    sch.Encoding
+		pth    []string
+		types  []int
+		defs   []uint8
+		reps   []uint8
+		levels []byte
+	}{
+		{
+			name:  "rle",
+			enc:   sch.Encoding_RLE,
+			pth:   []string{"a", "b"},
+			types: []int{1, 2},
+			defs:  []uint8{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 0, 1, 2, 1, 2, 2, 0, 2, 2, 2},
+			reps:  []uint8{0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 1, 0, 1, 0, 0, 1, 0},
+		},
+		{
+			name:  "bit packed",
+			opts:  []func(*parquet.OptionalField){parquet.OptionalFieldBitPackedLevels},
+			enc:   sch.Encoding_BIT_PACKED,
+			pth:   []string{"a", "b"},
+			types: []int{1, 2},
+			defs:  []uint8{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 0, 1, 2, 1, 2, 2, 0, 2, 2, 2},
+			reps:  []uint8{0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 1, 0, 1, 0, 0, 1, 0},
+		},
+		{
+			// the example from the parquet docs for the BIT_PACKED encoding
+			name:   "bit packed spec example",
+			opts:   []func(*parquet.OptionalField){parquet.OptionalFieldBitPackedLevels},
+			enc:    sch.Encoding_BIT_PACKED,
+			pth:    []string{"a", "b", "c", "d", "e", "f", "g"},
+			types:  []int{1, 1, 1, 1, 1, 1, 1},
+			defs:   []uint8{0, 1, 2, 3, 4, 5, 6, 7},
+			levels: []byte{0x05, 0x39, 0x77},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := parquet.NewOptionalField(tc.pth, tc.types, append(tc.opts, parquet.OptionalFieldUncompressed)...)
+			f.Defs = tc.defs
+			f.Reps = tc.reps
+
+			meta := parquet.New(parquet.Field{Name: strings.Join(tc.pth, "."), Path: tc.pth, Types: tc.types, Type: Int32Type, RepetitionType: parquet.RepetitionOptional})
+			var vals []byte
+			for i, d := range tc.defs {
+				meta.NextDoc()
+				if d == f.MaxLevels.Def {
+					vals = append(vals, byte(i), 0, 0, 0)
 				}
+			}
 
-				var i int
-				for r.Next() {
-					var p Person
-					r.Scan(&p)
-					exp := getExpected(expected, i)
-					assert.Equal(t, *exp, p, fmt.Sprintf("%s-%d", tc.name, i))
-					i++
+			buf := bytes.NewBufferString("PAR1")
+			if !assert.NoError(t, f.DoWrite(buf, meta, vals, len(tc.defs), newInt32stats())) {
+				return
+			}
+
+			ph, err := parquet.PageHeader(bytes.NewReader(buf.Bytes()[4:]))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.enc, ph.DataPageHeader.DefinitionLevelEncoding)
+			assert.Equal(t, tc.enc, ph.DataPageHeader.RepetitionLevelEncoding)
+
+			// the page's data is at the end of the buffer
+			data := buf.Bytes()[buf.Len()-int(ph.CompressedPageSize):]
+			assert.Equal(t, vals, data[len(data)-len(vals):])
+			if tc.levels != nil {
+				assert.Equal(t, tc.levels, data[:len(data)-len(vals)])
+			}
+
+			// each of the levels of a v1 data page that uses the
+			// RLE/bit-packing hybrid starts with its length
+			if tc.enc == sch.Encoding_RLE {
+				var pos int
+				for _, x := range []struct {
+					levels []uint8
+					max    uint8
+				}{{tc.reps, f.MaxLevels.Rep}, {tc.defs, f.MaxLevels.Def}} {
+					n := int(binary.LittleEndian.Uint32(data[pos:]))
+					levels, _, err := rle.Decode(data[pos+4:pos+4+n], bits.Len(uint(x.max)), len(x.levels))
+					if !assert.NoError(t, err) {
+						return
+					}
+					for i, l := range levels {
+						assert.Equal(t, uint32(x.levels[i]), l, i)
+					}
+					pos += 4 + n
 				}
+				assert.Equal(t, len(data)-len(vals), pos)
+			}
 
-				assert.Nil(t, r.Error(), tc.name)
-				assert.Equal(t, getLen(expected), i, tc.name)
-			})
-		}
+			r := bytes.NewReader(buf.Bytes())
+			r.Seek(4, io.SeekStart)
+			rf := parquet.NewOptionalField(tc.pth, tc.types)
+			rd, _, err := rf.DoRead(r, parquet.Page{N: len(tc.defs), Offset: 4, DataOffset: 4, Size: buf.Len() - 4, Type: sch.Type_INT32})
+			if !assert.NoError(t, err) {
+				return
+			}
+			out, err := ioutil.ReadAll(rd)
+			assert.NoError(t, err)
+			assert.Equal(t, vals, out)
+			assert.Equal(t, tc.defs, rf.Defs)
+			assert.Equal(t, tc.reps, rf.Reps)
+		})
 	}
 }
 
-func TestPageHeaders(t *testing.T) {
-	var buf bytes.Buffer
-	w, err := NewParquetWriter(&buf, MaxPageSize(2))
-	if !assert.NoError(t, err) {
-		return
+func TestWriteCSV(t *testing.T) {
+	var all []string
+	for _, f := range Fields(compressionUnknown, 0) {
+		all = append(all, f.Name())
 	}
 
-	docs := [][]Person{
-		{{}, {}, {}, {}},
-		{{}, {}, {}, {}},
-	}
-	for _, rowgroup := range docs {
-		for _, p := range rowgroup {
-			w.Add(p)
-		}
-		assert.NoError(t, w.Write())
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	people := []Person{
+		{
+			Being:   Being{ID: 1, Name: "a", Age: pint32(20)},
+			Hobby:   &Hobby{Name: "napping", Skills: []Skill{{Name: "yawning"}, {Name: "snoring"}}},
+			Created: created,
+			Payload: []byte("hi"),
+		},
+		{Being: Being{ID: 2, Name: "b,c"}},
 	}
 
-	assert.NoError(t, w.Close())
+	testCases := []struct {
+		name    string
+		columns []string
+		header  []string
+		rows    [][]string
+		err     string
+	}{
+		{name: "all columns", header: all},
+		{
+			name:    "some columns",
+			columns: []string{"Hobby.Name", "id", "age", "hobby.skills.name"},
+			header:  []string{"id", "age", "hobby.name", "hobby.skills.name"},
+			rows: [][]string{
+				{"1", "20", "napping", `["yawning","snoring"]`},
+				{"2", "", "", ""},
+			},
+		},
+		{
+			name:    "values",
+			columns: []string{"name", "created", "payload"},
+			header:  []string{"name", "created", "payload"},
+			rows: [][]string{
+				{"a", "2020-01-02T03:04:05Z", "aGk="},
+				{"b,c", "0001-01-01T00:00:00Z", ""},
+			},
+		},
+		{name: "unknown column", columns: []string{"nope"}, err: "unknown column: nope"},
+	}
 
-	rd := bytes.NewReader(buf.Bytes())
-	footer, err := parquet.ReadMetaData(rd)
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
 	if !assert.NoError(t, err) {
 		return
 	}
 
-	pageHeaders, err := parquet.PageHeaders(footer, rd)
-	if !assert.NoError(t, err) {
-		return
+	for _, p := range people {
+		w.Add(p)
 	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out bytes.Buffer
+			err = r.WriteCSV(&out, tc.columns...)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			recs, err := csv.NewReader(&out).ReadAll()
+			if !assert.NoError(t, err) || !assert.Len(t, recs, len(people)+1) {
+				return
+			}
 
-	assert.Equal(t, 88, len(pageHeaders))
+			assert.Equal(t, tc.header, recs[0])
+			if tc.rows != nil {
+				assert.Equal(t, tc.rows, recs[1:])
+			}
+		})
+	}
 }
 
 func TestStats(t *testing.T) {
@@ -562,7 +5199,7 @@ func TestStats(t *testing.T) {
 				},
 			},
 			stats: []stats{
-				{min: writeInt64(1), max: writeInt64(22)},
+				{min: writeInt64(1), max: writeInt64(22), nilCount: pint64(0)},
 			},
 		},
 		{
@@ -577,8 +5214,8 @@ func TestStats(t *testing.T) {
 				},
 			},
 			stats: []stats{
-				{min: writeInt64(1), max: writeInt64(2)},
-				{min: writeInt64(22), max: writeInt64(22)},
+				{min: writeInt64(1), max: writeInt64(2), nilCount: pint64(0)},
+				{min: writeInt64(22), max: writeInt64(22), nilCount: pint64(0)},
 			},
 		},
 		{
@@ -592,7 +5229,7 @@ func TestStats(t *testing.T) {
 				},
 			},
 			stats: []stats{
-				{min: writeInt64(0), max: writeInt64(0)},
+				{min: writeInt64(0), max: writeInt64(0), nilCount: pint64(0)},
 			},
 		},
 		{
@@ -620,7 +5257,7 @@ func TestStats(t *testing.T) {
 				},
 			},
 			stats: []stats{
-				{min: writeInt32(10), max: writeInt32(30)},
+				{min: writeInt32(10), max: writeInt32(30), nilCount: pint64(0)},
 			},
 		},
 		{
@@ -634,7 +5271,7 @@ func TestStats(t *testing.T) {
 				},
 			},
 			stats: []stats{
-				{min: writeFloat64(-50.5), max: writeFloat64(500.0)},
+				{min: writeFloat64(-50.5), max: writeFloat64(500.0), nilCount: pint64(0)},
 			},
 		},
 		{
@@ -652,6 +5289,20 @@ func TestStats(t *testing.T) {
 				{min: writeFloat32(0.5), max: writeFloat32(500.0), nilCount: pint64(1)},
 			},
 		},
+		{
+			name: "timestamp optional stats",
+			col:  "deleted",
+			input: [][]Person{
+				{
+					{Deleted: ptime(time.Unix(20, 0))},
+					{Deleted: nil},
+					{Deleted: ptime(time.Unix(10, 5000000))},
+				},
+			},
+			stats: []stats{
+				{min: writeInt64(10005), max: writeInt64(20000), nilCount: pint64(1)},
+			},
+		},
 		{
 			name: "bool stats",
 			col:  "hungry",
@@ -663,7 +5314,7 @@ func TestStats(t *testing.T) {
 				},
 			},
 			stats: []stats{
-				{},
+				{nilCount: pint64(0)},
 			},
 		},
 		{
@@ -691,7 +5342,36 @@ func TestStats(t *testing.T) {
 				},
 			},
 			stats: []stats{
-				{min: []byte("Fred"), max: []byte("Val")},
+				{min: []byte("Fred"), max: []byte("Val"), nilCount: pint64(0)},
+			},
+		},
+		{
+			name: "negative int8 stats",
+			col:  "level",
+			input: [][]Person{
+				{
+					{Level: -5},
+					{Level: -2},
+					{Level: -9},
+				},
+			},
+			stats: []stats{
+				{min: writeInt32(-9), max: writeInt32(-2), nilCount: pint64(0)},
+			},
+		},
+
+		{
+			name: "negative optional int64 stats",
+			col:  "sadness",
+			input: [][]Person{
+				{
+					{Sadness: pint64(-5)},
+					{Sadness: nil},
+					{Sadness: pint64(-9)},
+				},
+			},
+			stats: []stats{
+				{min: writeInt64(-9), max: writeInt64(-5), nilCount: pint64(1)},
 			},
 		},
 		{
@@ -708,6 +5388,35 @@ func TestStats(t *testing.T) {
 				{min: []byte("Fred"), max: []byte("Miranda"), nilCount: pint64(1)},
 			},
 		},
+		{
+			name: "uint32 stats above 2^31",
+			col:  "birthday",
+			input: [][]Person{
+				{
+					{Birthday: 1<<31 + 5},
+					{Birthday: 7},
+					{Birthday: math.MaxUint32},
+				},
+			},
+			stats: []stats{
+				{min: writeUint32(7), max: writeUint32(math.MaxUint32), nilCount: pint64(0)},
+			},
+		},
+		{
+			name: "optional uint64 stats above 2^63",
+			col:  "anniversary",
+			input: [][]Person{
+				{
+					{Anniversary: puint64(1<<63 + 5)},
+					{Anniversary: puint64(1)},
+					{Anniversary: nil},
+					{Anniversary: puint64(math.MaxUint64 - 1)},
+				},
+			},
+			stats: []stats{
+				{min: writeUint64(1), max: writeUint64(math.MaxUint64 - 1), nilCount: pint64(1)},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -758,6 +5467,53 @@ func TestStats(t *testing.T) {
 	}
 }
 
+func TestColumnOrders(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, PageIndex)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	w.Add(Person{Anniversary: puint64(1<<63 + 5)})
+	w.Add(Person{Anniversary: puint64(1)})
+	if !assert.NoError(t, w.Write()) || !assert.NoError(t, w.Close()) {
+		return
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	footer, err := parquet.ReadMetaData(r)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var leaves int
+	for _, se := range footer.Schema {
+		if se.Type != nil {
+			leaves++
+		}
+	}
+
+	if assert.Len(t, footer.ColumnOrders, leaves) {
+		for _, o := range footer.ColumnOrders {
+			assert.True(t, o.IsSetTYPE_ORDER())
+		}
+	}
+
+	var col *sch.ColumnChunk
+	for _, ch := range footer.RowGroups[0].Columns {
+		if ch.MetaData.PathInSchema[0] == "anniversary" {
+			col = ch
+		}
+	}
+
+	if assert.NotNil(t, col) {
+		assert.Equal(t, writeUint64(1), col.MetaData.Statistics.MinValue)
+		assert.Equal(t, writeUint64(1<<63+5), col.MetaData.Statistics.MaxValue)
+		assert.NotNil(t, col.ColumnIndexOffset)
+	}
+
+}
+
 func getPageHeaders(r io.ReadSeeker, name string, footer *sch.FileMetaData) ([]sch.PageHeader, error) {
 	var out []sch.PageHeader
 	for _, rg := range footer.RowGroups {
@@ -779,6 +5535,8 @@ var compressionTest = map[string]func(*ParquetWriter) error{
 	"uncompressed": Uncompressed,
 	"snappy":       Snappy,
 	"gzip":         Gzip,
+	"zstd":         Zstd,
+	"lz4 raw":      Lz4Raw,
 }
 
 func getLen(peeps [][]Person) int {
@@ -946,6 +5704,94 @@ func BenchmarkRead(b *testing.B) {
 	}
 }
 
+// BenchmarkReadSmall reads a small file over and over (like a service
+// that reads a lot of them), which is where the allocations of each read
+// add up.
+func BenchmarkReadSmall(b *testing.B) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
+	assert.Nil(b, err, "benchmark read small")
+	for _, p := range getPeople(100, 100)[0] {
+		w.Add(p)
+	}
+	assert.Nil(b, w.Write(), "benchmark read small")
+	assert.Nil(b, w.Close(), "benchmark read small")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for r.Next() {
+			var p Person
+			r.Scan(&p)
+		}
+		if err := r.Error(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadNoCopy scans the string and []byte columns of a file with
+// and without NoCopy to show the allocations that copying the values
+// takes.
+func BenchmarkReadNoCopy(b *testing.B) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, MaxDictionarySize(0))
+	assert.Nil(b, err, "benchmark read no copy")
+	for i, p := range getPeople(1000, 1000)[0] {
+		p.BFF = fmt.Sprintf("friend %d", i)
+		p.Payload = []byte(fmt.Sprintf("payload %d", i))
+		w.Add(p)
+	}
+	assert.Nil(b, w.Write(), "benchmark read no copy")
+	assert.Nil(b, w.Close(), "benchmark read no copy")
+
+	for _, bc := range []struct {
+		name string
+		opts []func(*ParquetReader)
+	}{
+		{name: "copy"},
+		{name: "no copy", opts: []func(*ParquetReader){NoCopy}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				r, err := NewParquetReader(bytes.NewReader(buf.Bytes()), bc.opts...)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				var n int
+				for _, col := range []string{"BFF", "Code"} {
+					var vals []string
+					if _, err := r.ReadColumn(col, &vals); err != nil {
+						b.Fatal(err)
+					}
+					for _, v := range vals {
+						n += len(v)
+					}
+				}
+
+				var payloads [][]byte
+				if _, err := r.ReadColumn("Payload", &payloads); err != nil {
+					b.Fatal(err)
+				}
+				for _, v := range payloads {
+					n += len(v)
+				}
+
+				if n == 0 {
+					b.Fatal("no values were read")
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkWrite(b *testing.B) {
 	var buf bytes.Buffer
 	w, err := NewParquetWriter(&buf, MaxPageSize(10000))
@@ -968,6 +5814,40 @@ func writeInt64(i int64) []byte {
 	return buf.Bytes()
 }
 
+// changeFooter returns data with a footer that f changes.
+func changeFooter(t *testing.T, data []byte, f func(*sch.FileMetaData)) []byte {
+	fmd, err := parquet.ReadMetaData(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f(fmd)
+
+	ts := thrift.NewTSerializer()
+	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
+	b, err := ts.Write(context.TODO(), fmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	out := append(append([]byte{}, data[:len(data)-8-footerLen]...), b...)
+	out = append(out, 0, 0, 0, 0, 'P', 'A', 'R', '1')
+	binary.LittleEndian.PutUint32(out[len(out)-8:], uint32(len(b)))
+	return out
+}
+
+func writeUint64(i uint64) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, i)
+	return buf.Bytes()
+}
+
+func writeUint32(i uint32) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, i)
+	return buf.Bytes()
+}
+
 func writeInt32(i int32) []byte {
 	var buf bytes.Buffer
 	binary.Write(&buf, binary.LittleEndian, i)
@@ -1003,6 +5883,16 @@ type Hobby struct {
 	Skills     []Skill `parquet:"skills"`
 }
 
+type Geo struct {
+	Lat float64  `parquet:"lat"`
+	Lon *float64 `parquet:"lon"`
+}
+
+type Address struct {
+	Street string `parquet:"street"`
+	Geo    *Geo   `parquet:"geo"`
+}
+
 type Person struct {
 	Being
 	Happiness   int64    `parquet:"happiness"`
@@ -1020,6 +5910,39 @@ type Person struct {
 	Hobby       *Hobby   `parquet:"hobby"`
 	Friends     []Being  `parquet:"friends"`
 	Sleepy      bool
+	Level       int8       `parquet:"level"`
+	Count       *int16     `parquet:"count"`
+	Created     time.Time  `parquet:"created"`
+	Deleted     *time.Time `parquet:"deleted"`
+	Graduated   *time.Time `parquet:"name=graduated,logical=date"`
+	Payload     []byte     `parquet:"payload"`
+	Thumbnail   []byte     `parquet:"thumbnail,optional"`
+	UUID        [16]byte   `parquet:"name=uuid,logical=uuid"`
+	Checksum    *[8]byte   `parquet:"checksum"`
+	Tags        []string   `parquet:"tags"`
+	Scores      []int32    `parquet:"scores"`
+	Home        *Address   `parquet:"home"`
+}
+
+// Measurement is read from a file that is written the way pyarrow
+// writes it (see arrowFile).
+type Measurement struct {
+	ID     int64    `parquet:"id,encoding=delta"`
+	Sensor string   `parquet:"sensor,encoding=delta_byte_array"`
+	Serial [4]byte  `parquet:"serial"`
+	Value  float64  `parquet:"value"`
+	Temp   *Celsius `parquet:"temp"`
+	Count  *int32   `parquet:"count,encoding=plain"`
+	OK     *bool    `parquet:"ok"`
+}
+
+// Celsius is written as the float32 that it's declared as.
+type Celsius float32
+
+// Event only has the columns that spark writes in TestInt96File.
+type Event struct {
+	Created time.Time  `parquet:"created"`
+	Deleted *time.Time `parquet:"deleted"`
 }
 
 /*
@@ -0,0 +1,431 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToMap returns a map from the name of each of row's columns to its value.
+// columns maps the path of each column's field (like "Hobby.Name") to the
+// column's name (like "hobby.name").  Nil values (and values under a nil
+// pointer) are left out.  The value of a column under a slice is a
+// []interface{} with the value of each element (and a column under slices
+// of slices has a []interface{} for each element of the outer slice).  A
+// map is the value of its group (like "attributes"), not of its key and
+// value columns.  The value of a field whose type has a MarshalParquet
+// method is the value that the method returns.
+func ToMap(row interface{}, columns map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(columns))
+	v := reflect.ValueOf(row)
+	for pth, col := range columns {
+		x, ok := mapValue(v, strings.Split(pth, "."))
+		if !ok {
+			continue
+		}
+
+		if reflect.ValueOf(x).Kind() == reflect.Map {
+			col = mapColumn(col)
+		}
+		out[col] = x
+	}
+	return out
+}
+
+// mapColumn returns the name of the group of a map's key or value column.
+func mapColumn(col string) string {
+	col = strings.TrimSuffix(strings.TrimSuffix(col, ".key"), ".value")
+	return strings.TrimSuffix(col, ".key_value")
+}
+
+// mapValue returns the value at pth and false if it's nil.
+func mapValue(v reflect.Value, pth []string) (interface{}, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	if len(pth) == 0 {
+		if m, ok := marshalValue(v); ok {
+			return m.Interface(), true
+		}
+	}
+
+	switch {
+	case v.Kind() == reflect.Map:
+		if v.IsNil() {
+			return nil, false
+		}
+		return v.Interface(), true
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		if v.Len() == 0 {
+			return nil, false
+		}
+
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i], _ = mapValue(v.Index(i), pth)
+		}
+		return out, true
+	case len(pth) == 0:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, false
+		}
+		return v.Interface(), true
+	case v.Kind() == reflect.Struct:
+		f, ok := fieldByName(v, pth[0], false)
+		if !ok {
+			return nil, false
+		}
+		return mapValue(f, pth[1:])
+	default:
+		return nil, false
+	}
+}
+
+// fieldByName returns the (possibly promoted) field of the struct v.  It
+// returns false if the field is under a nil embedded pointer, unless alloc
+// is true, in which case the embedded struct is allocated.
+func fieldByName(v reflect.Value, name string, alloc bool) (reflect.Value, bool) {
+	sf, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	for i, x := range sf.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// RowsEqual reports whether rows a and b have the same value in each column
+// (see ToMap for columns).  A nil value (or a value under a nil pointer) is
+// only equal to another nil value, but a nil slice is equal to an empty one
+// since they're written the same way.  time.Time values are compared with
+// their Equal method, big.Int values with their Cmp method and []byte
+// values and maps by their contents.
+func RowsEqual(a, b interface{}, columns map[string]string) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for pth := range columns {
+		x, xok := mapValue(va, strings.Split(pth, "."))
+		y, yok := mapValue(vb, strings.Split(pth, "."))
+		if xok != yok || !valuesEqual(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual reports whether two values that mapValue returned are equal.
+func valuesEqual(x, y interface{}) bool {
+	switch x := x.(type) {
+	case nil:
+		return y == nil
+	case []interface{}:
+		ys, ok := y.([]interface{})
+		if !ok || len(x) != len(ys) {
+			return false
+		}
+		for i := range x {
+			if !valuesEqual(x[i], ys[i]) {
+				return false
+			}
+		}
+		return true
+	case []byte:
+		ys, ok := y.([]byte)
+		return ok && bytes.Equal(x, ys)
+	case time.Time:
+		t, ok := y.(time.Time)
+		return ok && x.Equal(t)
+	case big.Int:
+		// zero can be stored with or without an empty slice of
+		// words, so the fields of the struct can't be compared
+		n, ok := y.(big.Int)
+		return ok && x.Cmp(&n) == 0
+	}
+
+	xv, yv := reflect.ValueOf(x), reflect.ValueOf(y)
+	if xv.Kind() != reflect.Map || xv.Type() != yv.Type() {
+		return reflect.DeepEqual(x, y)
+	}
+
+	if xv.Len() != yv.Len() {
+		return false
+	}
+
+	iter := xv.MapRange()
+	for iter.Next() {
+		v := yv.MapIndex(iter.Key())
+		if !v.IsValid() || !valuesEqual(iter.Value().Interface(), v.Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// FromMap sets the fields of row (a pointer to a struct) from a map that
+// ToMap returns (see ToMap for columns).  The values can also be what
+// encoding/json decodes them as: numbers (float64 or json.Number, which
+// is needed for 64 bit integers that don't fit in a float64), RFC 3339
+// strings for time.Time and base64 strings for []byte and [N]byte.  A
+// field whose type has an UnmarshalParquet method is set with it.
+func FromMap(m map[string]interface{}, row interface{}, columns map[string]string) error {
+	v := reflect.ValueOf(row)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("can't set the fields of %T, it must be a pointer to a struct", row)
+	}
+
+	for pth, col := range columns {
+		x, ok := m[col]
+		if !ok {
+			x, ok = m[mapColumn(col)]
+		}
+
+		if !ok || x == nil {
+			continue
+		}
+
+		if err := setValue(v, strings.Split(pth, "."), x); err != nil {
+			return fmt.Errorf("column %s: %s", col, err)
+		}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, pth []string, x interface{}) error {
+	if x == nil {
+		return nil
+	}
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if len(pth) == 0 {
+		if ok, err := unmarshalValue(v, x); ok {
+			return err
+		}
+	}
+
+	switch {
+	case v.Kind() == reflect.Map:
+		return assign(v, x)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		xs := reflect.ValueOf(x)
+		if xs.Kind() != reflect.Slice {
+			return fmt.Errorf("can't set a %s to %v (%T)", v.Type(), x, x)
+		}
+
+		// the slice is grown by each of the columns under it
+		for v.Len() < xs.Len() {
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		}
+
+		for i := 0; i < xs.Len(); i++ {
+			if err := setValue(v.Index(i), pth, xs.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case len(pth) == 0:
+		return assign(v, x)
+	case v.Kind() == reflect.Struct:
+		f, ok := fieldByName(v, pth[0], true)
+		if !ok {
+			return fmt.Errorf("%s doesn't have a field %s", v.Type(), pth[0])
+		}
+		return setValue(f, pth[1:], x)
+	default:
+		return fmt.Errorf("can't set field %s of a %s", pth[0], v.Type())
+	}
+}
+
+// marshalValue returns what the MarshalParquet method of v returns,
+// which is the value that v's column is written as, and false if v
+// doesn't have one.
+func marshalValue(v reflect.Value) (reflect.Value, bool) {
+	m := v.MethodByName("MarshalParquet")
+	if !m.IsValid() && v.CanAddr() {
+		m = v.Addr().MethodByName("MarshalParquet")
+	}
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
+
+// unmarshalValue sets v to x with v's UnmarshalParquet method, converting
+// x to the type that it takes first.  It returns false if v doesn't
+// have one.
+func unmarshalValue(v reflect.Value, x interface{}) (bool, error) {
+	if !v.CanAddr() {
+		return false, nil
+	}
+
+	m := v.Addr().MethodByName("UnmarshalParquet")
+	if !m.IsValid() || m.Type().NumIn() != 1 || m.Type().NumOut() != 0 {
+		return false, nil
+	}
+
+	arg := reflect.New(m.Type().In(0)).Elem()
+	if err := assign(arg, x); err != nil {
+		return true, err
+	}
+	m.Call([]reflect.Value{arg})
+	return true, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// assign sets v to x, converting x from the way
+// encoding/json decodes it if it needs to be.
+func assign(v reflect.Value, x interface{}) error {
+	xv := reflect.ValueOf(x)
+	if xv.Type().AssignableTo(v.Type()) {
+		v.Set(xv)
+		return nil
+	}
+
+	s, isString := x.(string)
+	switch {
+	case v.Type() == timeType && isString:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 && isString:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+		return nil
+	case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8 && isString:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return err
+		}
+		if len(b) != v.Len() {
+			return fmt.Errorf("can't set a %s to %d bytes", v.Type(), len(b))
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+		return nil
+	case v.Kind() == reflect.Array && xv.Kind() == reflect.Slice:
+		if xv.Len() != v.Len() {
+			return fmt.Errorf("can't set a %s to %d values", v.Type(), xv.Len())
+		}
+		for i := 0; i < xv.Len(); i++ {
+			if err := assign(v.Index(i), xv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case v.Kind() == reflect.Map && xv.Kind() == reflect.Map:
+		m := reflect.MakeMapWithSize(v.Type(), xv.Len())
+		for _, k := range xv.MapKeys() {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := assignKey(key, k.Interface()); err != nil {
+				return err
+			}
+
+			val := reflect.New(v.Type().Elem()).Elem()
+			if err := assign(val, xv.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, val)
+		}
+		v.Set(m)
+		return nil
+	default:
+		return assignNumber(v, x)
+	}
+}
+
+// assignKey sets the map key v to k, which is a string if the map was
+// decoded by encoding/json (which writes the keys of maps as strings).
+func assignKey(v reflect.Value, k interface{}) error {
+	s, ok := k.(string)
+	if !ok || v.Kind() == reflect.String {
+		return assign(v, k)
+	}
+	return assign(v, json.Number(s))
+}
+
+// assignNumber sets the numeric v to x (a number or
+// json.Number) if x fits in v without losing anything.
+func assignNumber(v reflect.Value, x interface{}) error {
+	invalid := fmt.Errorf("can't set a %s to %v (%T)", v.Type(), x, x)
+	xv := reflect.ValueOf(x)
+
+	var n json.Number
+	switch {
+	case xv.Type() == reflect.TypeOf(n):
+		n = x.(json.Number)
+	case xv.Kind() >= reflect.Int && xv.Kind() <= reflect.Float64:
+		n = json.Number(fmt.Sprint(x))
+	default:
+		return invalid
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil {
+			f, ferr := n.Float64()
+			if ferr != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return invalid
+			}
+			i = int64(f)
+		}
+		if v.OverflowInt(i) {
+			return invalid
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		if err != nil {
+			f, ferr := n.Float64()
+			if ferr != nil || f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return invalid
+			}
+			u = uint64(f)
+		}
+		if v.OverflowUint(u) {
+			return invalid
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := n.Float64()
+		if err != nil {
+			return invalid
+		}
+		v.SetFloat(f)
+	default:
+		return invalid
+	}
+	return nil
+}
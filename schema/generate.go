@@ -0,0 +1,8 @@
+// Package schema is the parquet-format file metadata (the footer, page
+// headers, page index and so on).  It's generated from parquet.thrift by
+// version 0.11.0 of the thrift compiler.
+package schema
+
+//go:generate thrift -out .. --gen go parquet.thrift
+//go:generate mv parquet.go schema.go
+//go:generate mv parquet-consts.go schema-consts.go
@@ -444,6 +444,7 @@ const (
 	CompressionCodec_BROTLI       CompressionCodec = 4
 	CompressionCodec_LZ4          CompressionCodec = 5
 	CompressionCodec_ZSTD         CompressionCodec = 6
+	CompressionCodec_LZ4_RAW      CompressionCodec = 7
 )
 
 func (p CompressionCodec) String() string {
@@ -462,6 +463,8 @@ func (p CompressionCodec) String() string {
 		return "LZ4"
 	case CompressionCodec_ZSTD:
 		return "ZSTD"
+	case CompressionCodec_LZ4_RAW:
+		return "LZ4_RAW"
 	}
 	return "<UNSET>"
 }
@@ -482,6 +485,8 @@ func CompressionCodecFromString(s string) (CompressionCodec, error) {
 		return CompressionCodec_LZ4, nil
 	case "ZSTD":
 		return CompressionCodec_ZSTD, nil
+	case "LZ4_RAW":
+		return CompressionCodec_LZ4_RAW, nil
 	}
 	return CompressionCodec(0), fmt.Errorf("not a valid CompressionCodec string")
 }
@@ -655,6 +660,81 @@ func (p *BoundaryOrder) Value() (driver.Value, error) {
 	return int64(*p), nil
 }
 
+//How the edges of a Geography's values are interpolated
+//between their vertices.
+type EdgeInterpolationAlgorithm int64
+
+const (
+	EdgeInterpolationAlgorithm_SPHERICAL EdgeInterpolationAlgorithm = 0
+	EdgeInterpolationAlgorithm_VINCENTY  EdgeInterpolationAlgorithm = 1
+	EdgeInterpolationAlgorithm_THOMAS    EdgeInterpolationAlgorithm = 2
+	EdgeInterpolationAlgorithm_ANDOYER   EdgeInterpolationAlgorithm = 3
+	EdgeInterpolationAlgorithm_KARNEY    EdgeInterpolationAlgorithm = 4
+)
+
+func (p EdgeInterpolationAlgorithm) String() string {
+	switch p {
+	case EdgeInterpolationAlgorithm_SPHERICAL:
+		return "SPHERICAL"
+	case EdgeInterpolationAlgorithm_VINCENTY:
+		return "VINCENTY"
+	case EdgeInterpolationAlgorithm_THOMAS:
+		return "THOMAS"
+	case EdgeInterpolationAlgorithm_ANDOYER:
+		return "ANDOYER"
+	case EdgeInterpolationAlgorithm_KARNEY:
+		return "KARNEY"
+	}
+	return "<UNSET>"
+}
+
+func EdgeInterpolationAlgorithmFromString(s string) (EdgeInterpolationAlgorithm, error) {
+	switch s {
+	case "SPHERICAL":
+		return EdgeInterpolationAlgorithm_SPHERICAL, nil
+	case "VINCENTY":
+		return EdgeInterpolationAlgorithm_VINCENTY, nil
+	case "THOMAS":
+		return EdgeInterpolationAlgorithm_THOMAS, nil
+	case "ANDOYER":
+		return EdgeInterpolationAlgorithm_ANDOYER, nil
+	case "KARNEY":
+		return EdgeInterpolationAlgorithm_KARNEY, nil
+	}
+	return EdgeInterpolationAlgorithm(0), fmt.Errorf("not a valid EdgeInterpolationAlgorithm string")
+}
+
+func EdgeInterpolationAlgorithmPtr(v EdgeInterpolationAlgorithm) *EdgeInterpolationAlgorithm { return &v }
+
+func (p EdgeInterpolationAlgorithm) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *EdgeInterpolationAlgorithm) UnmarshalText(text []byte) error {
+	q, err := EdgeInterpolationAlgorithmFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*p = q
+	return nil
+}
+
+func (p *EdgeInterpolationAlgorithm) Scan(value interface{}) error {
+	v, ok := value.(int64)
+	if !ok {
+		return errors.New("Scan value is not int64")
+	}
+	*p = EdgeInterpolationAlgorithm(v)
+	return nil
+}
+
+func (p *EdgeInterpolationAlgorithm) Value() (driver.Value, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return int64(*p), nil
+}
+
 // Statistics per row group and per page
 // All fields are optional.
 //
@@ -678,13 +758,17 @@ func (p *BoundaryOrder) Value() (driver.Value, error) {
 // Values are encoded using PLAIN encoding, except that variable-length byte
 // arrays do not include a length prefix.
 //  - MinValue
+//  - IsMaxValueExact: If true, max_value is the actual maximum value for a column
+//  - IsMinValueExact: If true, min_value is the actual minimum value for a column
 type Statistics struct {
-	Max           []byte `thrift:"max,1" db:"max" json:"max,omitempty"`
-	Min           []byte `thrift:"min,2" db:"min" json:"min,omitempty"`
-	NullCount     *int64 `thrift:"null_count,3" db:"null_count" json:"null_count,omitempty"`
-	DistinctCount *int64 `thrift:"distinct_count,4" db:"distinct_count" json:"distinct_count,omitempty"`
-	MaxValue      []byte `thrift:"max_value,5" db:"max_value" json:"max_value,omitempty"`
-	MinValue      []byte `thrift:"min_value,6" db:"min_value" json:"min_value,omitempty"`
+	Max             []byte `thrift:"max,1" db:"max" json:"max,omitempty"`
+	Min             []byte `thrift:"min,2" db:"min" json:"min,omitempty"`
+	NullCount       *int64 `thrift:"null_count,3" db:"null_count" json:"null_count,omitempty"`
+	DistinctCount   *int64 `thrift:"distinct_count,4" db:"distinct_count" json:"distinct_count,omitempty"`
+	MaxValue        []byte `thrift:"max_value,5" db:"max_value" json:"max_value,omitempty"`
+	MinValue        []byte `thrift:"min_value,6" db:"min_value" json:"min_value,omitempty"`
+	IsMaxValueExact *bool  `thrift:"is_max_value_exact,7" db:"is_max_value_exact" json:"is_max_value_exact,omitempty"`
+	IsMinValueExact *bool  `thrift:"is_min_value_exact,8" db:"is_min_value_exact" json:"is_min_value_exact,omitempty"`
 }
 
 func NewStatistics() *Statistics {
@@ -732,6 +816,23 @@ var Statistics_MinValue_DEFAULT []byte
 func (p *Statistics) GetMinValue() []byte {
 	return p.MinValue
 }
+var Statistics_IsMaxValueExact_DEFAULT bool
+
+func (p *Statistics) GetIsMaxValueExact() bool {
+	if !p.IsSetIsMaxValueExact() {
+		return Statistics_IsMaxValueExact_DEFAULT
+	}
+	return *p.IsMaxValueExact
+}
+
+var Statistics_IsMinValueExact_DEFAULT bool
+
+func (p *Statistics) GetIsMinValueExact() bool {
+	if !p.IsSetIsMinValueExact() {
+		return Statistics_IsMinValueExact_DEFAULT
+	}
+	return *p.IsMinValueExact
+}
 func (p *Statistics) IsSetMax() bool {
 	return p.Max != nil
 }
@@ -756,6 +857,14 @@ func (p *Statistics) IsSetMinValue() bool {
 	return p.MinValue != nil
 }
 
+func (p *Statistics) IsSetIsMaxValueExact() bool {
+	return p.IsMaxValueExact != nil
+}
+
+func (p *Statistics) IsSetIsMinValueExact() bool {
+	return p.IsMinValueExact != nil
+}
+
 func (p *Statistics) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -830,6 +939,26 @@ func (p *Statistics) Read(iprot thrift.TProtocol) error {
 					return err
 				}
 			}
+		case 7:
+			if fieldTypeId == thrift.BOOL {
+				if err := p.ReadField7(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 8:
+			if fieldTypeId == thrift.BOOL {
+				if err := p.ReadField8(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -899,6 +1028,24 @@ func (p *Statistics) ReadField6(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *Statistics) ReadField7(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		p.IsMaxValueExact = &v
+	}
+	return nil
+}
+
+func (p *Statistics) ReadField8(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.IsMinValueExact = &v
+	}
+	return nil
+}
+
 func (p *Statistics) Write(oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin("Statistics"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -922,6 +1069,12 @@ func (p *Statistics) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField6(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField7(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField8(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -1022,6 +1175,36 @@ func (p *Statistics) writeField6(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *Statistics) writeField7(oprot thrift.TProtocol) (err error) {
+	if p.IsSetIsMaxValueExact() {
+		if err := oprot.WriteFieldBegin("is_max_value_exact", thrift.BOOL, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:is_max_value_exact: ", p), err)
+		}
+		if err := oprot.WriteBool(bool(*p.IsMaxValueExact)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.is_max_value_exact (7) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:is_max_value_exact: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *Statistics) writeField8(oprot thrift.TProtocol) (err error) {
+	if p.IsSetIsMinValueExact() {
+		if err := oprot.WriteFieldBegin("is_min_value_exact", thrift.BOOL, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:is_min_value_exact: ", p), err)
+		}
+		if err := oprot.WriteBool(bool(*p.IsMinValueExact)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.is_min_value_exact (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:is_min_value_exact: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *Statistics) String() string {
 	if p == nil {
 		return "<nil>"
@@ -1140,6 +1323,341 @@ func (p *UUIDType) String() string {
 	return fmt.Sprintf("UUIDType(%+v)", *p)
 }
 
+type Float16Type struct {
+}
+
+func NewFloat16Type() *Float16Type {
+	return &Float16Type{}
+}
+
+func (p *Float16Type) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		if err := iprot.Skip(fieldTypeId); err != nil {
+			return err
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *Float16Type) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("Float16Type"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *Float16Type) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("Float16Type(%+v)", *p)
+}
+
+// Geospatial features in the WKB format with linear edges.
+//
+// Attributes:
+//  - Crs: The coordinate reference system of the values (OGC:CRS84 if it
+// isn't set).
+type GeometryType struct {
+	Crs *string `thrift:"crs,1" db:"crs" json:"crs,omitempty"`
+}
+
+func NewGeometryType() *GeometryType {
+	return &GeometryType{}
+}
+
+var GeometryType_Crs_DEFAULT string
+
+func (p *GeometryType) GetCrs() string {
+	if !p.IsSetCrs() {
+		return GeometryType_Crs_DEFAULT
+	}
+	return *p.Crs
+}
+func (p *GeometryType) IsSetCrs() bool {
+	return p.Crs != nil
+}
+
+func (p *GeometryType) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField1(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *GeometryType) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.Crs = &v
+	}
+	return nil
+}
+
+func (p *GeometryType) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("GeometryType"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *GeometryType) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetCrs() {
+		if err := oprot.WriteFieldBegin("crs", thrift.STRING, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:crs: ", p), err)
+		}
+		if err := oprot.WriteString(string(*p.Crs)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.crs (1) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:crs: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *GeometryType) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("GeometryType(%+v)", *p)
+}
+
+// Geospatial features in the WKB format with edges interpolated on the surface
+// of the earth.
+//
+// Attributes:
+//  - Crs: The coordinate reference system of the values (OGC:CRS84 if it
+// isn't set).
+//  - Algorithm: How the edges are interpolated (SPHERICAL if it isn't set).
+type GeographyType struct {
+	Crs       *string                     `thrift:"crs,1" db:"crs" json:"crs,omitempty"`
+	Algorithm *EdgeInterpolationAlgorithm `thrift:"algorithm,2" db:"algorithm" json:"algorithm,omitempty"`
+}
+
+func NewGeographyType() *GeographyType {
+	return &GeographyType{}
+}
+
+var GeographyType_Crs_DEFAULT string
+
+func (p *GeographyType) GetCrs() string {
+	if !p.IsSetCrs() {
+		return GeographyType_Crs_DEFAULT
+	}
+	return *p.Crs
+}
+
+var GeographyType_Algorithm_DEFAULT EdgeInterpolationAlgorithm
+
+func (p *GeographyType) GetAlgorithm() EdgeInterpolationAlgorithm {
+	if !p.IsSetAlgorithm() {
+		return GeographyType_Algorithm_DEFAULT
+	}
+	return *p.Algorithm
+}
+func (p *GeographyType) IsSetCrs() bool {
+	return p.Crs != nil
+}
+
+func (p *GeographyType) IsSetAlgorithm() bool {
+	return p.Algorithm != nil
+}
+
+func (p *GeographyType) Read(iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField1(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 2:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField2(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *GeographyType) ReadField1(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.Crs = &v
+	}
+	return nil
+}
+
+func (p *GeographyType) ReadField2(iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		temp := EdgeInterpolationAlgorithm(v)
+		p.Algorithm = &temp
+	}
+	return nil
+}
+
+func (p *GeographyType) Write(oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin("GeographyType"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *GeographyType) writeField1(oprot thrift.TProtocol) (err error) {
+	if p.IsSetCrs() {
+		if err := oprot.WriteFieldBegin("crs", thrift.STRING, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:crs: ", p), err)
+		}
+		if err := oprot.WriteString(string(*p.Crs)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.crs (1) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:crs: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *GeographyType) writeField2(oprot thrift.TProtocol) (err error) {
+	if p.IsSetAlgorithm() {
+		if err := oprot.WriteFieldBegin("algorithm", thrift.I32, 2); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:algorithm: ", p), err)
+		}
+		if err := oprot.WriteI32(int32(*p.Algorithm)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.algorithm (2) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 2:algorithm: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *GeographyType) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("GeographyType(%+v)", *p)
+}
+
 type MapType struct {
 }
 
@@ -2593,6 +3111,9 @@ func (p *BsonType) String() string {
 //  - JSON
 //  - BSON
 //  - UUID
+//  - FLOAT16
+//  - GEOMETRY
+//  - GEOGRAPHY
 type LogicalType struct {
 	STRING    *StringType    `thrift:"STRING,1" db:"STRING" json:"STRING,omitempty"`
 	MAP       *MapType       `thrift:"MAP,2" db:"MAP" json:"MAP,omitempty"`
@@ -2607,7 +3128,11 @@ type LogicalType struct {
 	UNKNOWN *NullType `thrift:"UNKNOWN,11" db:"UNKNOWN" json:"UNKNOWN,omitempty"`
 	JSON    *JsonType `thrift:"JSON,12" db:"JSON" json:"JSON,omitempty"`
 	BSON    *BsonType `thrift:"BSON,13" db:"BSON" json:"BSON,omitempty"`
-	UUID    *UUIDType `thrift:"UUID,14" db:"UUID" json:"UUID,omitempty"`
+	UUID    *UUIDType    `thrift:"UUID,14" db:"UUID" json:"UUID,omitempty"`
+	FLOAT16 *Float16Type `thrift:"FLOAT16,15" db:"FLOAT16" json:"FLOAT16,omitempty"`
+	// unused field # 16
+	GEOMETRY  *GeometryType  `thrift:"GEOMETRY,17" db:"GEOMETRY" json:"GEOMETRY,omitempty"`
+	GEOGRAPHY *GeographyType `thrift:"GEOGRAPHY,18" db:"GEOGRAPHY" json:"GEOGRAPHY,omitempty"`
 }
 
 func NewLogicalType() *LogicalType {
@@ -2730,6 +3255,33 @@ func (p *LogicalType) GetUUID() *UUIDType {
 	}
 	return p.UUID
 }
+
+var LogicalType_FLOAT16_DEFAULT *Float16Type
+
+func (p *LogicalType) GetFLOAT16() *Float16Type {
+	if !p.IsSetFLOAT16() {
+		return LogicalType_FLOAT16_DEFAULT
+	}
+	return p.FLOAT16
+}
+
+var LogicalType_GEOMETRY_DEFAULT *GeometryType
+
+func (p *LogicalType) GetGEOMETRY() *GeometryType {
+	if !p.IsSetGEOMETRY() {
+		return LogicalType_GEOMETRY_DEFAULT
+	}
+	return p.GEOMETRY
+}
+
+var LogicalType_GEOGRAPHY_DEFAULT *GeographyType
+
+func (p *LogicalType) GetGEOGRAPHY() *GeographyType {
+	if !p.IsSetGEOGRAPHY() {
+		return LogicalType_GEOGRAPHY_DEFAULT
+	}
+	return p.GEOGRAPHY
+}
 func (p *LogicalType) CountSetFieldsLogicalType() int {
 	count := 0
 	if p.IsSetSTRING() {
@@ -2771,6 +3323,15 @@ func (p *LogicalType) CountSetFieldsLogicalType() int {
 	if p.IsSetUUID() {
 		count++
 	}
+	if p.IsSetFLOAT16() {
+		count++
+	}
+	if p.IsSetGEOMETRY() {
+		count++
+	}
+	if p.IsSetGEOGRAPHY() {
+		count++
+	}
 	return count
 
 }
@@ -2827,6 +3388,18 @@ func (p *LogicalType) IsSetUUID() bool {
 	return p.UUID != nil
 }
 
+func (p *LogicalType) IsSetFLOAT16() bool {
+	return p.FLOAT16 != nil
+}
+
+func (p *LogicalType) IsSetGEOMETRY() bool {
+	return p.GEOMETRY != nil
+}
+
+func (p *LogicalType) IsSetGEOGRAPHY() bool {
+	return p.GEOGRAPHY != nil
+}
+
 func (p *LogicalType) Read(iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -2971,6 +3544,36 @@ func (p *LogicalType) Read(iprot thrift.TProtocol) error {
 					return err
 				}
 			}
+		case 15:
+			if fieldTypeId == thrift.STRUCT {
+				if err := p.ReadField15(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 17:
+			if fieldTypeId == thrift.STRUCT {
+				if err := p.ReadField17(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 18:
+			if fieldTypeId == thrift.STRUCT {
+				if err := p.ReadField18(iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(fieldTypeId); err != nil {
+					return err
+				}
+			}
 		default:
 			if err := iprot.Skip(fieldTypeId); err != nil {
 				return err
@@ -3090,6 +3693,30 @@ func (p *LogicalType) ReadField14(iprot thrift.TProtocol) error {
 	return nil
 }
 
+func (p *LogicalType) ReadField15(iprot thrift.TProtocol) error {
+	p.FLOAT16 = &Float16Type{}
+	if err := p.FLOAT16.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.FLOAT16), err)
+	}
+	return nil
+}
+
+func (p *LogicalType) ReadField17(iprot thrift.TProtocol) error {
+	p.GEOMETRY = &GeometryType{}
+	if err := p.GEOMETRY.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.GEOMETRY), err)
+	}
+	return nil
+}
+
+func (p *LogicalType) ReadField18(iprot thrift.TProtocol) error {
+	p.GEOGRAPHY = &GeographyType{}
+	if err := p.GEOGRAPHY.Read(iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.GEOGRAPHY), err)
+	}
+	return nil
+}
+
 func (p *LogicalType) Write(oprot thrift.TProtocol) error {
 	if c := p.CountSetFieldsLogicalType(); c != 1 {
 		return fmt.Errorf("%T write union: exactly one field must be set (%d set).", p, c)
@@ -3137,6 +3764,15 @@ func (p *LogicalType) Write(oprot thrift.TProtocol) error {
 		if err := p.writeField14(oprot); err != nil {
 			return err
 		}
+		if err := p.writeField15(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField17(oprot); err != nil {
+			return err
+		}
+		if err := p.writeField18(oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -3342,6 +3978,51 @@ func (p *LogicalType) writeField14(oprot thrift.TProtocol) (err error) {
 	return err
 }
 
+func (p *LogicalType) writeField15(oprot thrift.TProtocol) (err error) {
+	if p.IsSetFLOAT16() {
+		if err := oprot.WriteFieldBegin("FLOAT16", thrift.STRUCT, 15); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 15:FLOAT16: ", p), err)
+		}
+		if err := p.FLOAT16.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.FLOAT16), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 15:FLOAT16: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *LogicalType) writeField17(oprot thrift.TProtocol) (err error) {
+	if p.IsSetGEOMETRY() {
+		if err := oprot.WriteFieldBegin("GEOMETRY", thrift.STRUCT, 17); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 17:GEOMETRY: ", p), err)
+		}
+		if err := p.GEOMETRY.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.GEOMETRY), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 17:GEOMETRY: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *LogicalType) writeField18(oprot thrift.TProtocol) (err error) {
+	if p.IsSetGEOGRAPHY() {
+		if err := oprot.WriteFieldBegin("GEOGRAPHY", thrift.STRUCT, 18); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 18:GEOGRAPHY: ", p), err)
+		}
+		if err := p.GEOGRAPHY.Write(oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.GEOGRAPHY), err)
+		}
+		if err := oprot.WriteFieldEnd(); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 18:GEOGRAPHY: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *LogicalType) String() string {
 	if p == nil {
 		return "<nil>"
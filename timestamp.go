@@ -0,0 +1,24 @@
+package parquet
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// the times that a TIMESTAMP(NANOS) column can store, which
+// are the ones whose nanoseconds since the epoch fit in an int64
+var (
+	minNanos = time.Unix(0, math.MinInt64)
+	maxNanos = time.Unix(0, math.MaxInt64)
+)
+
+// TimestampNanos returns the nanoseconds since the epoch of t, which is
+// what a TIMESTAMP(NANOS) column stores, or an error if t is too far from
+// the epoch (before 1677 or after 2262) for them to fit in an int64.
+func TimestampNanos(t time.Time) (int64, error) {
+	if t.Before(minNanos) || t.After(maxNanos) {
+		return 0, fmt.Errorf("time %s is out of range for a TIMESTAMP(NANOS)", t.UTC().Format(time.RFC3339Nano))
+	}
+	return t.UnixNano(), nil
+}
@@ -0,0 +1,95 @@
+package parquet
+
+import (
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// SetStatsTruncateLength makes the min and max statistics (and page index)
+// of BYTE_ARRAY and FIXED_LEN_BYTE_ARRAY columns that are longer than n
+// bytes get cut down to n bytes, so very long values don't bloat the page
+// headers and footer.  A min is cut short, which sorts before the value
+// it came from, and a max is cut short and then rounded up at its last
+// byte (that isn't 0xff), which sorts after it.  Either way the statistics
+// say the value isn't exact.  Decimal and FLOAT16 columns (whose values
+// aren't compared byte by byte) are never truncated.  It must be called
+// before any pages are written.
+func (m *Metadata) SetStatsTruncateLength(n int) {
+	m.statsTruncateLength = n
+}
+
+// truncateStats truncates the min and max of st, which are the statistics
+// of the column described by se, if SetStatsTruncateLength was called.
+func (m *Metadata) truncateStats(st *sch.Statistics, se sch.SchemaElement) {
+	if m.statsTruncateLength <= 0 || !truncatable(se) {
+		return
+	}
+
+	if st.MinValue != nil {
+		var exact bool
+		st.MinValue, exact = truncateMin(st.MinValue, m.statsTruncateLength)
+		st.IsMinValueExact = &exact
+	}
+
+	if st.MaxValue != nil {
+		var exact bool
+		st.MaxValue, exact = truncateMax(st.MaxValue, m.statsTruncateLength)
+		st.IsMaxValueExact = &exact
+	}
+}
+
+// truncateColumnIndex truncates the min and max of each page in ci the
+// same way as truncateStats (the column index doesn't say which ones
+// aren't exact).
+func (m *Metadata) truncateColumnIndex(ci *sch.ColumnIndex, se sch.SchemaElement) {
+	if m.statsTruncateLength <= 0 || !truncatable(se) {
+		return
+	}
+
+	for i := range ci.MinValues {
+		ci.MinValues[i], _ = truncateMin(ci.MinValues[i], m.statsTruncateLength)
+		ci.MaxValues[i], _ = truncateMax(ci.MaxValues[i], m.statsTruncateLength)
+	}
+}
+
+// truncatable returns true if the column described by se
+// has values that are compared byte by byte.
+func truncatable(se sch.SchemaElement) bool {
+	switch se.GetType() {
+	case sch.Type_BYTE_ARRAY, sch.Type_FIXED_LEN_BYTE_ARRAY:
+	default:
+		return false
+	}
+
+	if se.GetConvertedType() == sch.ConvertedType_DECIMAL {
+		return false
+	}
+	return se.LogicalType == nil || se.LogicalType.FLOAT16 == nil
+}
+
+// truncateMin returns the first n bytes of min and false
+// if that's shorter than min.
+func truncateMin(min []byte, n int) ([]byte, bool) {
+	if len(min) <= n {
+		return min, true
+	}
+	return append([]byte{}, min[:n]...), false
+}
+
+// truncateMax returns the first n bytes of max with the last one that
+// isn't 0xff incremented (and the ones after it dropped), and false if
+// that's shorter than max.  A max that starts with n 0xff bytes can't be
+// rounded up so it's returned as it is.
+func truncateMax(max []byte, n int) ([]byte, bool) {
+	if len(max) <= n {
+		return max, true
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		if max[i] < 0xff {
+			out := append([]byte{}, max[:i+1]...)
+			out[i]++
+			return out, false
+		}
+	}
+	return max, true
+}
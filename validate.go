@@ -0,0 +1,199 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// ValidateFile checks that the size bytes of r are a well formed parquet
+// file without reading any of its pages.  The file has to start and end
+// with PAR1, its footer has to be readable, each row group has to have a
+// column chunk for each column of the schema and the column chunks (along
+// with their page indexes and bloom filters) have to be between the first
+// PAR1 and the footer.  It returns the first problem that it finds.
+func ValidateFile(r io.ReaderAt, size int64) error {
+	return validateFile(r, size, false)
+}
+
+// ValidateFileChecksums checks everything that ValidateFile does and also
+// reads the pages of each column chunk to check that they fit in it, that
+// their values add up to its number of values and that their data matches
+// their checksums (pages without a checksum aren't checked).
+func ValidateFileChecksums(r io.ReaderAt, size int64) error {
+	return validateFile(r, size, true)
+}
+
+func validateFile(r io.ReaderAt, size int64, pages bool) error {
+	if size < 12 {
+		return fmt.Errorf("the file is %d bytes, which is too small to be a parquet file", size)
+	}
+
+	buf := make([]byte, 8)
+	if _, err := r.ReadAt(buf[:4], 0); err != nil {
+		return fmt.Errorf("unable to read the start of the file: %s", err)
+	}
+
+	if string(buf[:4]) != "PAR1" {
+		return fmt.Errorf("bad magic number %q at the start of the file", buf[:4])
+	}
+
+	if _, err := r.ReadAt(buf, size-8); err != nil {
+		return fmt.Errorf("unable to read the end of the file: %s", err)
+	}
+
+	if string(buf[4:]) != "PAR1" {
+		return fmt.Errorf("bad magic number %q at the end of the file", buf[4:])
+	}
+
+	// footer is where the footer starts
+	n := int64(binary.LittleEndian.Uint32(buf[:4]))
+	footer := size - 8 - n
+	if footer < 4 {
+		return fmt.Errorf("the footer is %d bytes but the file is only %d bytes", n, size)
+	}
+
+	b := make([]byte, n)
+	if _, err := r.ReadAt(b, footer); err != nil {
+		return fmt.Errorf("unable to read the footer: %s", err)
+	}
+
+	fmd := sch.NewFileMetaData()
+	if err := fmd.Read(thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: bytes.NewReader(b)})); err != nil {
+		return fmt.Errorf("unable to read the footer: %s", err)
+	}
+
+	if len(fmd.Schema) == 0 {
+		return fmt.Errorf("the footer doesn't have a schema")
+	}
+
+	var leaves int
+	for _, se := range fmd.Schema[1:] {
+		if se.GetNumChildren() == 0 {
+			leaves++
+		}
+	}
+
+	sr := io.NewSectionReader(r, 0, size)
+	var rows int64
+	for i, rg := range fmd.RowGroups {
+		if len(rg.Columns) != leaves {
+			return fmt.Errorf("row group %d has %d column chunks but the schema has %d columns", i, len(rg.Columns), leaves)
+		}
+
+		for j, ch := range rg.Columns {
+			if err := validateColumnChunk(sr, ch, footer, pages); err != nil {
+				return fmt.Errorf("row group %d, column chunk %d: %s", i, j, err)
+			}
+		}
+		rows += rg.NumRows
+	}
+
+	if rows != fmd.NumRows {
+		return fmt.Errorf("the row groups have %d rows but the footer says the file has %d", rows, fmd.NumRows)
+	}
+	return nil
+}
+
+// validateColumnChunk checks that ch, and its page indexes and bloom
+// filter, are between the first PAR1 and footer (the offset of the footer)
+// and, if pages is true, that its pages are valid.
+func validateColumnChunk(r *io.SectionReader, ch *sch.ColumnChunk, footer int64, pages bool) error {
+	md := ch.MetaData
+	if md == nil {
+		return fmt.Errorf("the column chunk doesn't have any metadata")
+	}
+
+	name := strings.Join(md.PathInSchema, ".")
+	inside := func(what string, o, n int64) error {
+		if o < 4 || n < 0 || o+n > footer {
+			return fmt.Errorf("column %s: the %s at %d (%d bytes) isn't between the start of the file and the footer at %d", name, what, o, n, footer)
+		}
+		return nil
+	}
+
+	start := chunkOffset(md)
+	if err := inside("column chunk", start, md.TotalCompressedSize); err != nil {
+		return err
+	}
+
+	if ch.ColumnIndexOffset != nil {
+		if err := inside("column index", ch.GetColumnIndexOffset(), int64(ch.GetColumnIndexLength())); err != nil {
+			return err
+		}
+	}
+
+	if ch.OffsetIndexOffset != nil {
+		if err := inside("offset index", ch.GetOffsetIndexOffset(), int64(ch.GetOffsetIndexLength())); err != nil {
+			return err
+		}
+	}
+
+	if o := md.BloomFilterOffset; o != nil {
+		if err := inside("bloom filter", *o, 0); err != nil {
+			return err
+		}
+
+		n, err := bloomFilterSize(r, *o)
+		if err != nil {
+			return fmt.Errorf("column %s: %s", name, err)
+		}
+
+		if err := inside("bloom filter", *o, n); err != nil {
+			return err
+		}
+	}
+
+	if !pages {
+		return nil
+	}
+
+	if err := validatePages(io.NewSectionReader(r, start, md.TotalCompressedSize), md.NumValues); err != nil {
+		return fmt.Errorf("column %s: %s", name, err)
+	}
+	return nil
+}
+
+// validatePages checks that the pages of the column chunk r fit in it,
+// have n values between them and match their checksums.
+func validatePages(r *io.SectionReader, n int64) error {
+	var values, pos int64
+	for pos < r.Size() {
+		rc := &readCounter{r: io.NewSectionReader(r, pos, r.Size()-pos)}
+		ph, err := PageHeader(rc)
+		if err != nil {
+			return fmt.Errorf("unable to read the page header at %d: %s", pos, err)
+		}
+
+		pos += rc.n
+		l := int64(ph.CompressedPageSize)
+		if l < 0 || pos+l > r.Size() {
+			return fmt.Errorf("the page at %d (%d bytes) doesn't fit in the column chunk (%d bytes)", pos, l, r.Size())
+		}
+
+		data := make([]byte, l)
+		if _, err := r.ReadAt(data, pos); err != nil {
+			return fmt.Errorf("unable to read the page at %d: %s", pos, err)
+		}
+
+		if err := verifyChecksum(ph, data); err != nil {
+			return fmt.Errorf("the page at %d: %s", pos, err)
+		}
+
+		if dataPage(ph) {
+			v, _ := dataPageHeader(ph)
+			values += int64(v)
+		}
+		pos += l
+	}
+
+	if values != n {
+		return fmt.Errorf("the pages have %d values but the column chunk has %d", values, n)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package parquet_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFile(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, PageChecksums, PageIndex, BloomFilter("Code"), MaxPageSize(100))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, rg := range getPeople(50, 150) {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+	data := buf.Bytes()
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+	n := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	start := len(data) - 8 - n
+
+	// withFooter returns data with its footer replaced by b
+	withFooter := func(b []byte) []byte {
+		out := append(append([]byte{}, data[:start]...), b...)
+		out = append(out, 0, 0, 0, 0, 'P', 'A', 'R', '1')
+		binary.LittleEndian.PutUint32(out[len(out)-8:], uint32(len(b)))
+		return out
+	}
+
+	// changeFooter returns data with a footer that f changes
+	changeFooter := func(f func(*sch.FileMetaData)) []byte {
+		fmd, err := parquet.ReadMetaData(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f(fmd)
+
+		ts := thrift.NewTSerializer()
+		ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
+		b, err := ts.Write(context.TODO(), fmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return withFooter(b)
+	}
+
+	change := func(i int, f func([]byte)) []byte {
+		out := append([]byte{}, data...)
+		f(out[i:])
+		return out
+	}
+
+	bigFooter := change(len(data)-8, func(b []byte) { binary.LittleEndian.PutUint32(b, uint32(len(data))) })
+	md := footer.RowGroups[1].Columns[0].MetaData
+	// the last byte of the second row group's first column chunk,
+	// which is in the data of its last page
+	last := int(md.DataPageOffset + md.TotalCompressedSize - 1)
+
+	testCases := []struct {
+		name      string
+		input     []byte
+		err       string
+		checksums string
+	}{
+		{name: "valid", input: data},
+		{name: "too small", input: []byte("PAR1PAR1"), err: "the file is 8 bytes, which is too small to be a parquet file"},
+		{name: "bad magic number", input: change(0, func(b []byte) { copy(b, "PAR2") }), err: `bad magic number "PAR2" at the start of the file`},
+		{name: "truncated", input: data[:len(data)-3], err: fmt.Sprintf("bad magic number %q at the end of the file", data[len(data)-7:len(data)-3])},
+		{name: "footer too big", input: bigFooter, err: fmt.Sprintf("the footer is %d bytes but the file is only %d bytes", len(data), len(data))},
+		{name: "truncated footer", input: withFooter(data[start : start+n/2]), err: "unable to read the footer: "},
+		{name: "no schema", input: changeFooter(func(fmd *sch.FileMetaData) { fmd.Schema = nil }), err: "the footer doesn't have a schema"},
+		{
+			name:  "missing column chunk",
+			input: changeFooter(func(fmd *sch.FileMetaData) { fmd.RowGroups[2].Columns = fmd.RowGroups[2].Columns[1:] }),
+			err:   fmt.Sprintf("row group 2 has %d column chunks but the schema has %d columns", len(footer.RowGroups[2].Columns)-1, len(footer.RowGroups[2].Columns)),
+		},
+		{
+			name: "column chunk past the footer",
+			input: changeFooter(func(fmd *sch.FileMetaData) {
+				fmd.RowGroups[1].Columns[0].MetaData.TotalCompressedSize = int64(len(data))
+			}),
+			err: fmt.Sprintf("row group 1, column chunk 0: column id: the column chunk at %d (%d bytes) isn't between the start of the file and the footer at %d", md.DataPageOffset, len(data), start),
+		},
+		{
+			name:  "negative column index offset",
+			input: changeFooter(func(fmd *sch.FileMetaData) { fmd.RowGroups[0].Columns[0].ColumnIndexOffset = thrift.Int64Ptr(-1) }),
+			err:   fmt.Sprintf("row group 0, column chunk 0: column id: the column index at -1 (%d bytes) isn't between the start of the file and the footer at %d", footer.RowGroups[0].Columns[0].GetColumnIndexLength(), start),
+		},
+		{
+			name:  "wrong number of rows",
+			input: changeFooter(func(fmd *sch.FileMetaData) { fmd.NumRows++ }),
+			err:   "the row groups have 150 rows but the footer says the file has 151",
+		},
+		{
+			name:      "bad checksum",
+			input:     change(last, func(b []byte) { b[0]++ }),
+			checksums: "row group 1, column chunk 0: column id: the page at ",
+		},
+		{
+			name:      "wrong number of values",
+			input:     changeFooter(func(fmd *sch.FileMetaData) { fmd.RowGroups[1].Columns[0].MetaData.NumValues++ }),
+			checksums: "row group 1, column chunk 0: column id: the pages have 50 values but the column chunk has 51",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bytes.NewReader(tc.input)
+			err := parquet.ValidateFile(r, r.Size())
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.True(t, strings.HasPrefix(err.Error(), tc.err), err.Error())
+			}
+
+			// the checksums are only checked after everything else is
+			checksums := tc.checksums
+			if checksums == "" {
+				checksums = tc.err
+			}
+
+			err = parquet.ValidateFileChecksums(r, r.Size())
+			if checksums == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.True(t, strings.HasPrefix(err.Error(), checksums), err.Error())
+			}
+		})
+	}
+}
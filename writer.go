@@ -0,0 +1,50 @@
+package parquet
+
+import "io"
+
+// CountingWriter counts the bytes that are written to the io.Writer it
+// wraps.  When a file is written through one, Metadata gets the offset of
+// each column chunk (and bloom filter and page index) from the number of
+// bytes written before it instead of adding up the sizes of what came
+// before it, so the footer is right for any io.Writer (like a pipe, a
+// bufio.Writer or a gzip.Writer) without it needing to Seek.
+type CountingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// NewCountingWriter returns a CountingWriter of w,
+// which has already had n bytes written to it.
+func NewCountingWriter(w io.Writer, n int64) *CountingWriter {
+	return &CountingWriter{w: w, n: n}
+}
+
+// Write writes p to the underlying io.Writer.
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Count returns the number of bytes that have been written.
+func (c *CountingWriter) Count() int64 {
+	return c.n
+}
+
+// Flush flushes the underlying io.Writer if it
+// has a Flush method (like a bufio.Writer).
+func (c *CountingWriter) Flush() error {
+	if f, ok := c.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// position returns the number of bytes that have been written
+// to w, which is -1 if w isn't a CountingWriter.
+func position(w io.Writer) int64 {
+	if c, ok := w.(*CountingWriter); ok {
+		return c.Count()
+	}
+	return -1
+}